@@ -5,6 +5,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/cassette"
 	"github.com/404tk/cloudtoolkit/runner"
 	"github.com/404tk/cloudtoolkit/runner/console"
 	"github.com/404tk/cloudtoolkit/runner/headless"
@@ -14,7 +15,9 @@ import (
 
 func main() {
 	if len(os.Args) > 1 {
-		os.Exit(headless.Run(os.Args[1:]))
+		code := headless.Run(os.Args[1:])
+		cassette.FlushAll()
+		os.Exit(code)
 	}
 
 	runner.ShowBanner()
@@ -28,6 +31,7 @@ func main() {
 	go func() {
 		<-sig
 		cache.SaveFile()
+		cassette.FlushAll()
 		os.Exit(130)
 	}()
 
@@ -39,4 +43,5 @@ func main() {
 		prompt.OptionInputTextColor(prompt.White),
 	)
 	p.Run()
+	cassette.FlushAll()
 }
@@ -6,6 +6,8 @@ require (
 	github.com/404tk/go-prompt v0.0.1
 	github.com/404tk/table v0.0.4
 	github.com/mattn/go-isatty v0.0.14
+	golang.org/x/crypto v0.36.0
+	golang.org/x/term v0.30.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -16,6 +18,6 @@ require (
 	github.com/mattn/go-tty v0.0.5 // indirect
 	github.com/olekukonko/tablewriter v0.0.5 // indirect
 	github.com/pkg/term v1.2.0-beta.2 // indirect
-	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/sys v0.31.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 )
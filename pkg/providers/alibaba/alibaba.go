@@ -20,6 +20,7 @@ import (
 	_sms "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/sms"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/credverify"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -99,8 +100,8 @@ func (p *Provider) Name() string {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			p.newBSSDriver(p.region).QueryAccountBalance(ctx)
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, p.newBSSDriver(p.region).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			ecsprovider := p.newECSDriver(p.region)
@@ -146,6 +147,13 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			schema.AppendAssets(list, logs)
 			list.AddError("log", err)
 			list.AddError("log", slsprovider.PartialError())
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			ecsprovider := p.newECSDriver(p.region)
+			groups, err := ecsprovider.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
+			list.AddError("securitygroup", ecsprovider.PartialError())
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -233,6 +241,17 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	ossdrvier := p.newOSSDriver(p.region)
+	infos, err := p.bucketInfos(ctx, ossdrvier, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	return ossdrvier.WalkObjects(ctx, infos, q, visit)
+}
+
 // BucketACL implements schema.BucketACLManager for alibaba OSS. `container`
 // is a bucket name; `level` is the canned OSS ACL value (private,
 // public-read, public-read-write) or a friendly alias resolved by
@@ -272,6 +291,18 @@ func (p *Provider) BucketACL(ctx context.Context, action, container, level strin
 	return result, fmt.Errorf("alibaba: unsupported bucket-acl action %q", action)
 }
 
+// ObjectAccess implements schema.ObjectAccessManager for OSS. The requests
+// appear in the bucket's access logs (and its SLS real-time log query, when
+// enabled) under the returned request ID.
+func (p *Provider) ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	return p.newOSSDriver(p.region).AccessObject(ctx, action, bucket, key, body)
+}
+
+// IAMPosture implements schema.IAMPostureReader for RAM users.
+func (p *Provider) IAMPosture(ctx context.Context) ([]schema.UserPosture, error) {
+	return p.newIAMDriver(p.region).Posture(ctx)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for alibaba RAM
 // AccessKey lifecycle. `principal` is the RAM user name (required for create
 // and delete; optional for list — empty falls back to the calling principal).
@@ -330,16 +361,26 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents filters Security Center alerts, the list whitelist acts on.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	d := p.newSASDriver()
+	return d.QueryEvents(ctx, q)
+}
+
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
-		if p.region == "" || p.region == "all" {
-			return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+		region := p.region
+		if region == "" || region == "all" {
+			host, found := p.lookupHost(ctx, instanceID)
+			if !found || host.Region == "" {
+				return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+			}
+			region = host.Region
 		}
-		output := p.newECSDriver(p.region).RunCommand(instanceID, osType, command)
-		return schema.CommandResult{Output: output}, nil
+		return p.newECSDriver(region).Invoke(ctx, instanceID, osType, command)
 	}
 
-	host, ok := p.lookupHost(instanceID)
+	host, ok := p.lookupHost(ctx, instanceID)
 	if !ok {
 		return schema.CommandResult{}, fmt.Errorf("unable to resolve instance metadata")
 	}
@@ -348,15 +389,14 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 	if err != nil {
 		return schema.CommandResult{}, err
 	}
-	output := d.RunCommand(instanceID, host.OSType, string(command))
-	return schema.CommandResult{Output: output}, nil
+	return d.Invoke(ctx, instanceID, host.OSType, string(command))
 }
 
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
 	r := p.newRDSDriver(p.region)
 	switch action {
 	case "useradd":
-		db, ok := p.lookupDatabase(instanceID)
+		db, ok := p.lookupDatabase(ctx, instanceID)
 		if !ok {
 			return schema.DatabaseActionResult{}, fmt.Errorf("unable to resolve database metadata, retry: shell <instance-id>")
 		}
@@ -369,24 +409,20 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 	}
 }
 
-func (p *Provider) lookupHost(instanceID string) (schema.Host, bool) {
-	for _, host := range _ecs.GetCacheHostList() {
-		if host.ID == instanceID || host.HostName == instanceID {
-			return host, true
-		}
-	}
-	return schema.Host{}, false
+// lookupHost resolves an instance from the persisted inventory of the
+// credential; this session's host list only answers when no inventory store
+// is attached (see invcache.ResolveHost).
+func (p *Provider) lookupHost(ctx context.Context, instanceID string) (schema.Host, bool) {
+	return invcache.ResolveHost(ctx, instanceID, _ecs.GetCacheHostList())
 }
 
-func (p *Provider) lookupDatabase(instanceID string) (schema.Database, bool) {
-	for _, db := range _rds.GetCacheDBList() {
-		if db.InstanceId == instanceID {
-			return db, true
-		}
+func (p *Provider) lookupDatabase(ctx context.Context, instanceID string) (schema.Database, bool) {
+	if db, ok := invcache.ResolveDatabase(ctx, instanceID, _rds.GetCacheDBList()); ok {
+		return db, true
 	}
 	logger.Info("Database metadata cache miss, refreshing instances ...")
 	driver := p.newRDSDriver(p.region)
-	databases, err := driver.GetDatabases(context.Background())
+	databases, err := driver.GetDatabases(ctx)
 	if err != nil {
 		logger.Error(err)
 		return schema.Database{}, false
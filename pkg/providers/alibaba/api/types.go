@@ -42,6 +42,7 @@ type QueryAccountBalanceResponse struct {
 
 type AccountBalanceData struct {
 	AvailableCashAmount string `json:"AvailableCashAmount"`
+	Currency            string `json:"Currency"`
 }
 
 func (c *Client) QueryAccountBalance(ctx context.Context, region string) (QueryAccountBalanceResponse, error) {
@@ -52,6 +52,21 @@ type ECSInstance struct {
 	PublicIP          ECSPublicIPList      `json:"PublicIpAddress"`
 	NetworkInterfaces ECSNetworkInterfaces `json:"NetworkInterfaces"`
 	EIPAddress        ECSEIPAddress        `json:"EipAddress"`
+	SecurityGroupIDs  ECSSecurityGroupIDs  `json:"SecurityGroupIds"`
+	Tags              ECSTags              `json:"Tags"`
+}
+
+type ECSTags struct {
+	Tag []ECSTag `json:"Tag"`
+}
+
+type ECSTag struct {
+	TagKey   string `json:"TagKey"`
+	TagValue string `json:"TagValue"`
+}
+
+type ECSSecurityGroupIDs struct {
+	SecurityGroupID []string `json:"SecurityGroupId"`
 }
 
 type ECSPublicIPList struct {
@@ -93,6 +108,87 @@ func (c *Client) DescribeECSInstances(ctx context.Context, region string, pageNu
 	return resp, err
 }
 
+type DescribeSecurityGroupsResponse struct {
+	PageSize       int                  `json:"PageSize"`
+	PageNumber     int                  `json:"PageNumber"`
+	RequestID      string               `json:"RequestId"`
+	TotalCount     int                  `json:"TotalCount"`
+	SecurityGroups ECSSecurityGroupList `json:"SecurityGroups"`
+}
+
+type ECSSecurityGroupList struct {
+	SecurityGroup []ECSSecurityGroup `json:"SecurityGroup"`
+}
+
+type ECSSecurityGroup struct {
+	SecurityGroupID   string `json:"SecurityGroupId"`
+	SecurityGroupName string `json:"SecurityGroupName"`
+	Description       string `json:"Description"`
+	VpcID             string `json:"VpcId"`
+}
+
+func (c *Client) DescribeSecurityGroups(ctx context.Context, region string, pageNumber, pageSize int) (DescribeSecurityGroupsResponse, error) {
+	var resp DescribeSecurityGroupsResponse
+	err := c.Do(ctx, Request{
+		Product:    "Ecs",
+		Version:    "2014-05-26",
+		Action:     "DescribeSecurityGroups",
+		Region:     region,
+		Method:     http.MethodPost,
+		Query:      pagingQuery(pageNumber, pageSize),
+		Idempotent: true,
+	}, &resp)
+	return resp, err
+}
+
+type DescribeSecurityGroupAttributeResponse struct {
+	RequestID       string                   `json:"RequestId"`
+	SecurityGroupID string                   `json:"SecurityGroupId"`
+	VpcID           string                   `json:"VpcId"`
+	Permissions     ECSSecurityPermissionSet `json:"Permissions"`
+}
+
+type ECSSecurityPermissionSet struct {
+	Permission []ECSSecurityPermission `json:"Permission"`
+}
+
+// ECSSecurityPermission is one rule of a security group. PortRange uses the
+// "from/to" form ("22/22", "-1/-1" for every port).
+type ECSSecurityPermission struct {
+	Direction          string `json:"Direction"`
+	Policy             string `json:"Policy"`
+	IPProtocol         string `json:"IpProtocol"`
+	PortRange          string `json:"PortRange"`
+	Priority           string `json:"Priority"`
+	SourceCidrIP       string `json:"SourceCidrIp"`
+	Ipv6SourceCidrIP   string `json:"Ipv6SourceCidrIp"`
+	SourceGroupID      string `json:"SourceGroupId"`
+	SourcePrefixListID string `json:"SourcePrefixListId"`
+	DestCidrIP         string `json:"DestCidrIp"`
+	Ipv6DestCidrIP     string `json:"Ipv6DestCidrIp"`
+	DestGroupID        string `json:"DestGroupId"`
+	DestPrefixListID   string `json:"DestPrefixListId"`
+	Description        string `json:"Description"`
+}
+
+func (c *Client) DescribeSecurityGroupAttribute(ctx context.Context, region, groupID string) (DescribeSecurityGroupAttributeResponse, error) {
+	query := url.Values{}
+	query.Set("SecurityGroupId", groupID)
+	query.Set("Direction", "all")
+
+	var resp DescribeSecurityGroupAttributeResponse
+	err := c.Do(ctx, Request{
+		Product:    "Ecs",
+		Version:    "2014-05-26",
+		Action:     "DescribeSecurityGroupAttribute",
+		Region:     region,
+		Method:     http.MethodPost,
+		Query:      query,
+		Idempotent: true,
+	}, &resp)
+	return resp, err
+}
+
 type RunECSCommandResponse struct {
 	RequestID string `json:"RequestId"`
 	CommandID string `json:"CommandId"`
@@ -144,6 +240,7 @@ type ECSInvocationResult struct {
 	InvokeRecordStatus string `json:"InvokeRecordStatus"`
 	Output             string `json:"Output"`
 	ErrorInfo          string `json:"ErrorInfo"`
+	ExitCode           *int64 `json:"ExitCode"`
 }
 
 func (c *Client) DescribeECSInvocationResults(ctx context.Context, region, commandID string) (DescribeECSInvocationResultsResponse, error) {
@@ -480,3 +480,59 @@ func (c *Client) DeleteRAMAccessKey(ctx context.Context, region, userName, acces
 	}, &resp)
 	return resp, err
 }
+
+type GetRAMUserMFAInfoResponse struct {
+	RequestID string       `json:"RequestId"`
+	MFADevice RAMMFADevice `json:"MFADevice"`
+}
+
+type RAMMFADevice struct {
+	SerialNumber string `json:"SerialNumber"`
+	Type         string `json:"Type"`
+}
+
+// GetRAMUserMFAInfo returns the MFA device bound to userName. RAM answers
+// EntityNotExist.User.MFADevice when none is bound.
+func (c *Client) GetRAMUserMFAInfo(ctx context.Context, region, userName string) (GetRAMUserMFAInfoResponse, error) {
+	query := url.Values{}
+	query.Set("UserName", userName)
+
+	var resp GetRAMUserMFAInfoResponse
+	err := c.Do(ctx, Request{
+		Product:    "Ram",
+		Version:    "2015-05-01",
+		Action:     "GetUserMFAInfo",
+		Region:     region,
+		Method:     http.MethodPost,
+		Query:      query,
+		Idempotent: true,
+	}, &resp)
+	return resp, err
+}
+
+type GetRAMAccessKeyLastUsedResponse struct {
+	RequestID         string               `json:"RequestId"`
+	AccessKeyLastUsed RAMAccessKeyLastUsed `json:"AccessKeyLastUsed"`
+}
+
+type RAMAccessKeyLastUsed struct {
+	LastUsedDate string `json:"LastUsedDate"`
+}
+
+func (c *Client) GetRAMAccessKeyLastUsed(ctx context.Context, region, userName, accessKeyID string) (GetRAMAccessKeyLastUsedResponse, error) {
+	query := url.Values{}
+	query.Set("UserName", userName)
+	query.Set("UserAccessKeyId", accessKeyID)
+
+	var resp GetRAMAccessKeyLastUsedResponse
+	err := c.Do(ctx, Request{
+		Product:    "Ram",
+		Version:    "2015-05-01",
+		Action:     "GetAccessKeyLastUsed",
+		Region:     region,
+		Method:     http.MethodPost,
+		Query:      query,
+		Idempotent: true,
+	}, &resp)
+	return resp, err
+}
@@ -5,7 +5,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
 	aliauth "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/auth"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -22,16 +22,23 @@ func (d *Driver) SetClientOptions(opts ...api.Option) {
 	d.clientOptions = append([]api.Option(nil), opts...)
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 	resp, err := d.newClient().QueryAccountBalance(ctx, api.NormalizeRegion(d.Region))
-	if err == nil {
-		if resp.Data.AvailableCashAmount != "" {
-			logger.Warning("Available cash amount:", resp.Data.AvailableCashAmount)
-		}
+	if err != nil || resp.Data.AvailableCashAmount == "" {
+		return nil
 	}
+	currency := resp.Data.Currency
+	if currency == "" {
+		currency = "CNY"
+	}
+	return []schema.Balance{{
+		Kind:     schema.BalanceCredit,
+		Amount:   resp.Data.AvailableCashAmount,
+		Currency: currency,
+	}}
 }
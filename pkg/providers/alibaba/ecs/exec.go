@@ -2,6 +2,8 @@ package ecs
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -29,12 +31,21 @@ func GetCacheHostList() []schema.Host {
 }
 
 func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
-	ctx := context.Background()
+	result, err := d.Invoke(context.Background(), instanceID, osType, cmd)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	return result.Output
+}
+
+// Invoke runs cmd through Cloud Assistant and returns the invocation's
+// output and exit code. A non-finished status is returned as an error.
+func (d *Driver) Invoke(ctx context.Context, instanceID, osType, cmd string) (schema.CommandResult, error) {
 	client := d.newClient()
 	commandType, ok := resolveCommandType(osType)
 	if !ok {
-		logger.Error("Unknown ostype", osType)
-		return ""
+		return schema.CommandResult{}, fmt.Errorf("Unknown ostype %s", osType)
 	}
 
 	contentEncoding := ""
@@ -46,19 +57,17 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 
 	response, err := client.RunECSCommand(ctx, d.Region, commandType, commandContent, contentEncoding, []string{instanceID})
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	if response.CommandID == "" {
-		logger.Error("Missing command id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing command id.")
 	}
 	return d.describeInvocationResults(ctx, client, response.CommandID)
 }
 
 func (d *Driver) describeInvocationResults(ctx context.Context, client interface {
 	DescribeECSInvocationResults(context.Context, string, string) (api.DescribeECSInvocationResultsResponse, error)
-}, commandID string) string {
+}, commandID string) (schema.CommandResult, error) {
 	attempts := 0
 	for {
 		d.sleepFor(d.pollDelay())
@@ -66,31 +75,32 @@ func (d *Driver) describeInvocationResults(ctx context.Context, client interface
 
 		response, err := client.DescribeECSInvocationResults(ctx, d.Region, commandID)
 		if err != nil {
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		if len(response.Invocation.InvocationResults.InvocationResult) == 0 {
-			logger.Error("Missing invocation result.")
-			return ""
+			return schema.CommandResult{}, errors.New("Missing invocation result.")
 		}
 
 		result := response.Invocation.InvocationResults.InvocationResult[0]
-		switch status := result.InvokeRecordStatus; status {
-		case "Running":
+		status := result.InvokeRecordStatus
+		if status == "Running" {
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Timeout: Wait 5s by default.")
-			return ""
-		case "Finished":
-			return result.Output
+			return schema.CommandResult{}, errors.New("Timeout: Wait 5s by default.")
+		}
+		out := schema.CommandResult{Output: result.Output, Status: status}
+		if result.ExitCode != nil {
+			code := int(*result.ExitCode)
+			out.ExitCode = &code
+		}
+		switch {
+		case status == "Finished":
+			return out, nil
+		case result.ErrorInfo != "":
+			return out, errors.New("Exception status: " + status + " - " + result.ErrorInfo)
 		default:
-			if result.ErrorInfo != "" {
-				logger.Error("Exception status: " + status + " - " + result.ErrorInfo)
-				return ""
-			}
-			logger.Error("Exception status: " + status)
-			return ""
+			return out, errors.New("Exception status: " + status)
 		}
 	}
 }
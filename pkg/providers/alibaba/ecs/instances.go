@@ -141,11 +141,23 @@ func mapInstances(region string, instances []api.ECSInstance) []schema.Host {
 			OSType:      instance.OSType,
 			Public:      ipv4 != "",
 			Region:      region,
+			Tags:        tagMap(instance.Tags.Tag),
 		})
 	}
 	return items
 }
 
+func tagMap(tags []api.ECSTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[tag.TagKey] = tag.TagValue
+	}
+	return out
+}
+
 func resolvePublicIPv4(instance api.ECSInstance) string {
 	if len(instance.PublicIP.IPAddress) > 0 {
 		return instance.PublicIP.IPAddress[0]
@@ -5,6 +5,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -184,7 +185,7 @@ func assertHost(t *testing.T, hosts []schema.Host, want schema.Host) {
 		if host.ID != want.ID {
 			continue
 		}
-		if host != want {
+		if !reflect.DeepEqual(host, want) {
 			t.Fatalf("unexpected host for %s: got %+v want %+v", want.ID, host, want)
 		}
 		return
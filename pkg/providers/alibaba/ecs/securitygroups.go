@@ -0,0 +1,175 @@
+package ecs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// GetSecurityGroups lists ECS security groups per region with their rules.
+// Attached instances come from the SecurityGroupIds of DescribeInstances, so
+// a region costs one extra instance listing rather than one call per group.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	d.partialErr = nil
+	logger.Info("List ECS security groups ...")
+	client := d.newClient()
+	var regions []string
+	if d.Region == "all" {
+		resp, err := client.DescribeECSRegions(ctx, api.DefaultRegion)
+		if err != nil {
+			logger.Error("Describe regions failed.")
+			return list, err
+		}
+		for _, r := range resp.Regions.Region {
+			regions = append(regions, r.RegionID)
+		}
+	} else {
+		regions = append(regions, api.NormalizeRegion(d.Region))
+	}
+
+	tracker := processbar.NewRegionTracker()
+	defer tracker.Finish()
+	got, regionErrs := regionrun.ForEach(ctx, regions, 0, tracker, func(ctx context.Context, region string) ([]schema.SecurityGroup, error) {
+		return d.listSecurityGroups(ctx, client, region)
+	})
+	list = append(list, got...)
+	d.partialErr = regionrun.Wrap(regionErrs)
+	return list, nil
+}
+
+func (d *Driver) listSecurityGroups(ctx context.Context, client *api.Client, region string) ([]schema.SecurityGroup, error) {
+	groups, err := paginate.Fetch(ctx, func(ctx context.Context, page int) (paginate.Page[api.ECSSecurityGroup, int], error) {
+		if page == 0 {
+			page = 1
+		}
+		resp, err := client.DescribeSecurityGroups(ctx, region, page, 100)
+		if err != nil {
+			return paginate.Page[api.ECSSecurityGroup, int]{}, err
+		}
+		return paginate.Page[api.ECSSecurityGroup, int]{
+			Items: resp.SecurityGroups.SecurityGroup,
+			Next:  page + 1,
+			Done:  isLastPage(page, resp.PageSize, resp.TotalCount, len(resp.SecurityGroups.SecurityGroup)),
+		}, nil
+	})
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	attached, err := groupAttachments(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		attr, err := client.DescribeSecurityGroupAttribute(ctx, region, group.SecurityGroupID)
+		if err != nil {
+			return out, err
+		}
+		item := schema.SecurityGroup{
+			GroupID:   group.SecurityGroupID,
+			GroupName: group.SecurityGroupName,
+			VpcID:     group.VpcID,
+			Region:    region,
+			Attached:  attached[group.SecurityGroupID],
+		}
+		for _, perm := range attr.Permissions.Permission {
+			item.Rules = append(item.Rules, convertPermission(perm)...)
+		}
+		item.FlagExposure()
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func groupAttachments(ctx context.Context, client *api.Client, region string) (map[string][]string, error) {
+	instances, err := paginate.Fetch(ctx, func(ctx context.Context, page int) (paginate.Page[api.ECSInstance, int], error) {
+		if page == 0 {
+			page = 1
+		}
+		resp, err := client.DescribeECSInstances(ctx, region, page, 100)
+		if err != nil {
+			return paginate.Page[api.ECSInstance, int]{}, err
+		}
+		return paginate.Page[api.ECSInstance, int]{
+			Items: resp.Instances.Instance,
+			Next:  page + 1,
+			Done:  isLastPage(page, resp.PageSize, resp.TotalCount, len(resp.Instances.Instance)),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for _, instance := range instances {
+		for _, groupID := range instance.SecurityGroupIDs.SecurityGroupID {
+			out[groupID] = append(out[groupID], instance.InstanceID)
+		}
+	}
+	for groupID := range out {
+		sort.Strings(out[groupID])
+	}
+	return out, nil
+}
+
+// convertPermission returns one rule per peer set on the permission: the
+// IPv4 range, the IPv6 range, the peer group or the prefix list.
+func convertPermission(perm api.ECSSecurityPermission) []schema.FirewallRule {
+	direction := strings.ToLower(strings.TrimSpace(perm.Direction))
+	peers := []string{perm.SourceCidrIP, perm.Ipv6SourceCidrIP, perm.SourceGroupID, perm.SourcePrefixListID}
+	if direction == "egress" {
+		peers = []string{perm.DestCidrIP, perm.Ipv6DestCidrIP, perm.DestGroupID, perm.DestPrefixListID}
+	}
+	action := "allow"
+	if strings.EqualFold(perm.Policy, "drop") {
+		action = "deny"
+	}
+	protocol := strings.ToLower(strings.TrimSpace(perm.IPProtocol))
+	if protocol == "" {
+		protocol = "all"
+	}
+	base := schema.FirewallRule{
+		Direction:   direction,
+		Action:      action,
+		Protocol:    protocol,
+		PortRange:   portRange(perm.PortRange),
+		Priority:    perm.Priority,
+		Description: perm.Description,
+	}
+	var rules []schema.FirewallRule
+	for _, peer := range peers {
+		if peer = strings.TrimSpace(peer); peer == "" {
+			continue
+		}
+		rule := base
+		rule.CIDR = peer
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// portRange converts the "from/to" form used by ECS.
+func portRange(value string) string {
+	from, to, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok {
+		if value == "" {
+			return "all"
+		}
+		return value
+	}
+	a, errA := strconv.Atoi(from)
+	b, errB := strconv.Atoi(to)
+	if errA != nil || errB != nil {
+		return value
+	}
+	return schema.PortRange(a, b)
+}
@@ -0,0 +1,74 @@
+package ecs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+func TestGetSecurityGroupsMapsPermissionsAndInstances(t *testing.T) {
+	logger.SetOutput(io.Discard)
+	t.Cleanup(func() {
+		logger.SetOutput(nil)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("Action") {
+		case "DescribeSecurityGroups":
+			_, _ = io.WriteString(w, `{"TotalCount":1,"PageSize":100,"PageNumber":1,"SecurityGroups":{"SecurityGroup":[{"SecurityGroupId":"sg-1","SecurityGroupName":"web","VpcId":"vpc-1"}]}}`)
+		case "DescribeInstances":
+			_, _ = io.WriteString(w, `{"TotalCount":2,"PageSize":100,"PageNumber":1,"Instances":{"Instance":[{"InstanceId":"i-2","SecurityGroupIds":{"SecurityGroupId":["sg-1"]}},{"InstanceId":"i-1","SecurityGroupIds":{"SecurityGroupId":["sg-1","sg-2"]}}]}}`)
+		case "DescribeSecurityGroupAttribute":
+			if got := r.URL.Query().Get("SecurityGroupId"); got != "sg-1" {
+				t.Fatalf("unexpected SecurityGroupId: %s", got)
+			}
+			_, _ = io.WriteString(w, `{"SecurityGroupId":"sg-1","Permissions":{"Permission":[
+				{"Direction":"ingress","Policy":"Accept","IpProtocol":"TCP","PortRange":"22/22","Priority":"1","SourceCidrIp":"0.0.0.0/0","Ipv6SourceCidrIp":"::/0"},
+				{"Direction":"ingress","Policy":"Drop","IpProtocol":"ALL","PortRange":"-1/-1","Priority":"100","SourceGroupId":"sg-9"},
+				{"Direction":"egress","Policy":"Accept","IpProtocol":"TCP","PortRange":"443/443","Priority":"1","DestCidrIp":"0.0.0.0/0"}
+			]}}`)
+		default:
+			t.Fatalf("unexpected action: %s", r.URL.Query().Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(server.URL)
+	driver.Region = "cn-hangzhou"
+	groups, err := driver.GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if err := driver.PartialError(); err != nil {
+		t.Fatalf("PartialError() = %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("unexpected group count: %d", len(groups))
+	}
+	group := groups[0]
+	if group.GroupID != "sg-1" || group.VpcID != "vpc-1" || group.Region != "cn-hangzhou" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if len(group.Attached) != 2 || group.Attached[0] != "i-1" || group.Attached[1] != "i-2" {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	if len(group.Rules) != 4 {
+		t.Fatalf("unexpected rule count: %+v", group.Rules)
+	}
+	if r := group.Rules[0]; r.Protocol != "tcp" || r.PortRange != "22" || r.CIDR != "0.0.0.0/0" || !r.Exposed {
+		t.Fatalf("unexpected ssh rule: %+v", r)
+	}
+	if r := group.Rules[1]; r.CIDR != "::/0" || !r.Exposed {
+		t.Fatalf("unexpected ipv6 ssh rule: %+v", r)
+	}
+	if r := group.Rules[2]; r.Action != "deny" || r.Protocol != "all" || r.PortRange != "all" || r.CIDR != "sg-9" || r.Exposed {
+		t.Fatalf("unexpected drop rule: %+v", r)
+	}
+	if r := group.Rules[3]; r.Direction != "egress" || r.PortRange != "443" || r.Exposed {
+		t.Fatalf("unexpected egress rule: %+v", r)
+	}
+}
@@ -0,0 +1,55 @@
+package iam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestPostureReadsRAMCredentialState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch query.Get("Action") {
+		case "ListUsers":
+			_, _ = w.Write([]byte(`{"RequestId":"r1","IsTruncated":false,"Users":{"User":[{"UserName":"demo","UserId":"2350001","CreateDate":"2026-01-10T03:00:00Z"}]}}`))
+		case "GetUser":
+			_, _ = w.Write([]byte(`{"RequestId":"r2","User":{"UserName":"demo","LastLoginDate":"2026-04-20T01:12:00Z"}}`))
+		case "GetLoginProfile":
+			_, _ = w.Write([]byte(`{"RequestId":"r3","LoginProfile":{"UserName":"demo","CreateDate":"2026-01-10T03:05:00Z"}}`))
+		case "GetUserMFAInfo":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"RequestId":"r4","Code":"EntityNotExist.User.MFADevice","Message":"The specified user does not have a MFA device."}`))
+		case "ListAccessKeys":
+			_, _ = w.Write([]byte(`{"RequestId":"r5","AccessKeys":{"AccessKey":[{"AccessKeyId":"LTAI4t1","Status":"Active","CreateDate":"2026-01-11T00:00:00Z"}]}}`))
+		case "GetAccessKeyLastUsed":
+			if got := query.Get("UserAccessKeyId"); got != "LTAI4t1" {
+				t.Fatalf("unexpected access key: %s", got)
+			}
+			_, _ = w.Write([]byte(`{"RequestId":"r6","AccessKeyLastUsed":{"LastUsedDate":"2026-04-21T06:37:40Z"}}`))
+		default:
+			t.Fatalf("unexpected action: %s", query.Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	got, err := newAccessKeysDriver(server.URL).Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(got))
+	}
+	user := got[0]
+	if !user.Password || user.MFA || user.LastLogin.IsZero() || user.PasswordChanged.IsZero() {
+		t.Fatalf("unexpected posture: %+v", user)
+	}
+	if user.HasGap(schema.PostureGapMFA) || len(user.Gaps) != 0 {
+		t.Fatalf("a missing MFA device must not be a gap: %v", user.Gaps)
+	}
+	if len(user.AccessKeys) != 1 || !user.AccessKeys[0].Active || user.AccessKeys[0].LastUsed.IsZero() {
+		t.Fatalf("unexpected access keys: %+v", user.AccessKeys)
+	}
+}
@@ -3,11 +3,13 @@ package oss
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
@@ -81,6 +83,64 @@ func (c *Client) GetBucketACL(ctx context.Context, bucket, region string) (strin
 	return strings.TrimSpace(out.AccessControlList.Grant), nil
 }
 
+// GetBucketPolicy returns bucket's policy document, or nil when the bucket
+// has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket, region string) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := c.credential.Validate(); err != nil {
+		return nil, err
+	}
+	bucket = strings.TrimSpace(bucket)
+	if bucket == "" {
+		return nil, fmt.Errorf("alibaba oss client: empty bucket")
+	}
+	region = strings.TrimSpace(region)
+	if region == "" || region == "all" {
+		return nil, fmt.Errorf("alibaba oss client: empty region")
+	}
+
+	u, err := c.bucketURL(bucket, region)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("policy", "")
+	u.RawQuery = query.Encode()
+
+	httpResp, err := c.retryPolicy.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := Sign(req, c.credential, bucket, c.now().UTC()); err != nil {
+			return nil, err
+		}
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if httpResp == nil {
+		return nil, fmt.Errorf("alibaba oss client: empty response")
+	}
+	defer httpclient.CloseResponse(httpResp)
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read alibaba oss response: %w", err)
+	}
+	if err := decodeError(httpResp, body); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "NoSuchBucketPolicy" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
 // PutBucketACL sets the canned ACL on bucket. acl must be one of the OSSACL*
 // constants; the value is sent via the `x-oss-acl` header per OSS spec.
 func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) error {
@@ -138,8 +198,9 @@ func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) e
 }
 
 // AuditBucketACL enumerates buckets in scope and returns their canned ACL
-// state. When bucket is empty all buckets are audited; otherwise the named
-// bucket is audited if found.
+// state and effective exposure, which also weighs the bucket policy. When
+// bucket is empty all buckets are audited; otherwise the named bucket is
+// audited if found.
 func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.BucketACLEntry, error) {
 	client, err := d.NewClient()
 	if err != nil {
@@ -166,10 +227,15 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 		if grant == "" {
 			grant = OSSACLPrivate
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     grant,
-		})
+		}
+		var verdict exposure.Verdict
+		verdict.Add("acl", exposure.ACL(grant))
+		verdict.AddPolicy(client.GetBucketPolicy(ctx, s.BucketName, region))
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
@@ -124,7 +124,7 @@ func (c *Client) ListBuckets(ctx context.Context, region string) (*ListBucketsRe
 	return &out, nil
 }
 
-func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, continuationToken string, maxKeys int) (ListObjectsResponse, error) {
+func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, prefix, continuationToken string, maxKeys int) (ListObjectsResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -151,6 +151,9 @@ func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, continuation
 	query.Set("list-type", "2")
 	query.Set("encoding-type", "url")
 	query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if continuationToken = strings.TrimSpace(continuationToken); continuationToken != "" {
 		query.Set("continuation-token", continuationToken)
 	}
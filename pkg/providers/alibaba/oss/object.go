@@ -9,7 +9,16 @@ import (
 	"github.com/404tk/cloudtoolkit/utils/processbar"
 )
 
+// ListObjects returns every object of each bucket.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's continuation tokens and hands the
+// objects matching q to visit.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	client, err := d.NewClient()
 	if err != nil {
 		return nil, err
@@ -17,34 +26,45 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 
 	results := []schema.BucketResult{}
 	for b, r := range buckets {
-		resp, err := client.ListObjectsV2(ctx, b, normalizeBucketRegion(r), "", 100)
-		if err != nil {
-			return nil, fmt.Errorf("list objects in %s: %w", b, err)
-		}
-
-		objects := make([]schema.BucketObject, 0, len(resp.Objects))
-		for _, obj := range resp.Objects {
-			objects = append(objects, schema.BucketObject{
-				BucketName:   b,
-				Key:          obj.Key,
-				Size:         obj.Size,
-				LastModified: obj.LastModified,
-				StorageClass: obj.StorageClass,
-			})
-		}
-
-		result := schema.BucketResult{
-			Action:      "list",
-			BucketName:  b,
-			ObjectCount: int64(len(objects)),
-			Objects:     objects,
-			Message:     fmt.Sprintf("%d objects found", len(objects)),
+		walk := schema.NewObjectWalk(q, visit)
+		token := ""
+	pages:
+		for {
+			resp, err := client.ListObjectsV2(ctx, b, normalizeBucketRegion(r), q.Prefix, token, 1000)
+			if err != nil {
+				return results, fmt.Errorf("list objects in %s: %w", b, err)
+			}
+			for _, obj := range resp.Objects {
+				more, err := walk.Add(schema.BucketObject{
+					BucketName:   b,
+					Key:          obj.Key,
+					Size:         obj.Size,
+					LastModified: obj.LastModified,
+					StorageClass: obj.StorageClass,
+				})
+				if err != nil {
+					return results, err
+				}
+				if !more {
+					break pages
+				}
+			}
+			if !resp.IsTruncated {
+				break
+			}
+			token = strings.TrimSpace(resp.NextContinuationToken)
+			if token == "" {
+				return results, fmt.Errorf("alibaba oss: missing next continuation token for bucket %s", b)
+			}
+			if ctx.Err() != nil {
+				break
+			}
 		}
-		results = append(results, result)
+		results = append(results, walk.Result(b))
 
 		select {
 		case <-ctx.Done():
-			return results, nil
+			return results, ctx.Err()
 		default:
 		}
 	}
@@ -92,7 +112,7 @@ func (d *Driver) countBucketObjects(ctx context.Context, bucket, region string,
 	token := ""
 	count := 0
 	for {
-		resp, err := client.ListObjectsV2(ctx, bucket, region, token, 1000)
+		resp, err := client.ListObjectsV2(ctx, bucket, region, "", token, 1000)
 		if err != nil {
 			return 0, err
 		}
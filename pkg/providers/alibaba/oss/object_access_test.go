@@ -0,0 +1,86 @@
+package oss
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAccessObjectRoundTripsCanary(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]string{}
+	driver := fakeOSSDriver(t, "cn-hangzhou", func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "ctk-demo.oss-cn-hangzhou.aliyuncs.com" {
+			t.Fatalf("unexpected host: %s", req.URL.Host)
+		}
+		presigned := req.URL.Query().Get("Signature") != ""
+		if signed := strings.HasPrefix(req.Header.Get("Authorization"), "OSS ak:"); signed == presigned {
+			t.Fatalf("%s %s: authorization=%q presigned=%v", req.Method, req.URL.Path, req.Header.Get("Authorization"), presigned)
+		}
+		header := http.Header{"X-Oss-Request-Id": []string{"req-" + strings.ToLower(req.Method)}}
+		body := ""
+		switch req.Method {
+		case http.MethodPut:
+			if got := req.Header.Get("Content-Type"); got != "text/plain" {
+				t.Fatalf("unexpected content type: %s", got)
+			}
+			data, _ := io.ReadAll(req.Body)
+			stored[req.URL.Path] = string(data)
+		case http.MethodGet:
+			body = stored[req.URL.Path]
+		case http.MethodDelete:
+			delete(stored, req.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    req,
+		}, nil
+	})
+
+	ctx := context.Background()
+	const key = "ctk-canary/20260419T120000Z-0a1b2c3d.txt"
+	put, err := driver.AccessObject(ctx, "put", "ctk-demo", key, []byte("canary"))
+	if err != nil || put.RequestID != "req-put" || put.StatusCode != http.StatusOK {
+		t.Fatalf("put = %+v, %v", put, err)
+	}
+	get, err := driver.AccessObject(ctx, "get", "ctk-demo", key, nil)
+	if err != nil || string(get.Body) != "canary" {
+		t.Fatalf("get = %+v, %v", get, err)
+	}
+	presign, err := driver.AccessObject(ctx, "presign", "ctk-demo", key, nil)
+	if err != nil || string(presign.Body) != "canary" || !strings.Contains(presign.URL, "Expires=") {
+		t.Fatalf("presign = %+v, %v", presign, err)
+	}
+	if _, err := driver.AccessObject(ctx, "delete", "ctk-demo", key, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Fatalf("canary left behind: %v", stored)
+	}
+}
+
+func TestAccessObjectKeepsRequestIDOfDeniedCall(t *testing.T) {
+	t.Parallel()
+
+	driver := fakeOSSDriver(t, "cn-hangzhou", func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{"Content-Type": []string{"application/xml"}},
+			Body: io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>AccessDenied</Code><Message>denied</Message><RequestId>req-denied</RequestId></Error>`)),
+			Request: req,
+		}, nil
+	})
+	got, err := driver.AccessObject(context.Background(), "get", "ctk-demo", "ctk-canary/x.txt", nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got.RequestID != "req-denied" || got.StatusCode != http.StatusForbidden {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
@@ -64,7 +64,7 @@ func TestClientListObjectsV2UsesBucketScopedEndpoint(t *testing.T) {
 		WithClock(func() time.Time { return time.Date(2026, 4, 19, 12, 0, 0, 0, time.UTC) }),
 	)
 
-	resp, err := client.ListObjectsV2(context.Background(), "examplebucket", "cn-shanghai", "", 100)
+	resp, err := client.ListObjectsV2(context.Background(), "examplebucket", "cn-shanghai", "", "", 100)
 	if err != nil {
 		t.Fatalf("ListObjectsV2() error = %v", err)
 	}
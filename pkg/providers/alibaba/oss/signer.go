@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -135,6 +136,32 @@ func Sign(req *http.Request, cred aliauth.Credential, bucket string, now time.Ti
 	return nil
 }
 
+// Presign adds OSS query-string authentication to u, valid until expires:
+// OSSAccessKeyId, Expires and Signature, plus security-token for STS
+// credentials. The expiry takes the place of the Date header in the string
+// to sign.
+func Presign(u *url.URL, method string, cred aliauth.Credential, bucket string, expires time.Time) {
+	query := u.Query()
+	for _, key := range []string{"OSSAccessKeyId", "Expires", "Signature", "security-token"} {
+		query.Del(key)
+	}
+	if cred.SecurityToken != "" {
+		query.Set("security-token", cred.SecurityToken)
+	}
+	expiresAt := strconv.FormatInt(expires.Unix(), 10)
+	stringToSign := strings.Join([]string{
+		method,
+		"",
+		"",
+		expiresAt,
+		canonicalizedResource(bucket, u.EscapedPath(), query),
+	}, "\n")
+	query.Set("OSSAccessKeyId", cred.AccessKeyID)
+	query.Set("Expires", expiresAt)
+	query.Set("Signature", signString(stringToSign, cred.AccessKeySecret))
+	u.RawQuery = query.Encode()
+}
+
 func buildStringToSign(req *http.Request, bucket string) string {
 	canonicalHeaders := canonicalizedOSSHeaders(req.Header)
 	return strings.Join([]string{
@@ -11,7 +11,7 @@ import (
 )
 
 func (d *Driver) CreateAccount(ctx context.Context, instanceID, dbName string) (schema.DatabaseActionResult, error) {
-	accountName, accountPassword, err := parseRDSAccount()
+	accountName, accountPassword, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -34,7 +34,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID, dbName string) (
 }
 
 func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.DatabaseActionResult, error) {
-	accountName, _, err := parseRDSAccount()
+	accountName, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -56,8 +56,8 @@ func grantAccountPrivilege(ctx context.Context, client *api.Client, region, inst
 	return err
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
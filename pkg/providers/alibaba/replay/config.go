@@ -6,6 +6,8 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba"
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/oss"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const (
@@ -14,12 +16,18 @@ const (
 )
 
 func ClientConfig() alibaba.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every alibaba client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) alibaba.ClientConfig {
+	httpClient = telemetry.WrapClient("alibaba", httpClient)
 	return alibaba.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		OSSOptions:          []oss.Option{oss.WithHTTPClient(httpClient)},
-		SLSHTTPClient:       httpClient,
-		SkipCredentialCache: true,
+		APIOptions:    []api.Option{api.WithHTTPClient(httpClient)},
+		OSSOptions:    []oss.Option{oss.WithHTTPClient(httpClient)},
+		SLSHTTPClient: httpClient,
 	}
 }
@@ -21,6 +21,7 @@ type ramUserFixture struct {
 	CreateDate     string
 	LastLoginDate  string
 	HasLogin       bool
+	MFASerial      string
 	AttachedPolicy []ramPolicyFixture
 }
 
@@ -119,6 +120,7 @@ var demoRAMUsers = []ramUserFixture{
 		CreateDate:    "2026-03-10T11:00:00+08:00",
 		LastLoginDate: "2026-04-20T09:12:00+08:00",
 		HasLogin:      true,
+		MFASerial:     "acs:ram::235000000000000001:mfa/demo",
 		AttachedPolicy: []ramPolicyFixture{
 			{Name: "AdministratorAccess", Type: "System"},
 		},
@@ -344,6 +346,12 @@ func seedRAMAccessKeys() map[string][]ramAccessKeyFixture {
 	return out
 }
 
+// demoRAMKeyLastUsed is what GetAccessKeyLastUsed reports for a seeded key;
+// keys missing from it were never used.
+var demoRAMKeyLastUsed = map[string]string{
+	"LTAI4tRAM001EXAMPLE": "2026-04-20T01:15:00Z",
+}
+
 func demoCallerUserName() string {
 	return "demo"
 }
@@ -0,0 +1,100 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+type securityGroupFixture struct {
+	api.ECSSecurityGroup
+	Region      string
+	Members     []string
+	Permissions []api.ECSSecurityPermission
+}
+
+// demoSecurityGroups puts SSH open to the internet on the Beijing jump host
+// and keeps the Hangzhou app group reachable only from its VPC.
+var demoSecurityGroups = []securityGroupFixture{
+	{
+		ECSSecurityGroup: api.ECSSecurityGroup{
+			SecurityGroupID:   "sg-demo001",
+			SecurityGroupName: "ctk-demo-app",
+			Description:       "app tier",
+			VpcID:             "vpc-demo001",
+		},
+		Region:  "cn-hangzhou",
+		Members: []string{"i-demo001"},
+		Permissions: []api.ECSSecurityPermission{
+			{Direction: "ingress", Policy: "Accept", IPProtocol: "TCP", PortRange: "443/443", Priority: "1", SourceCidrIP: "0.0.0.0/0", Description: "https"},
+			{Direction: "ingress", Policy: "Accept", IPProtocol: "TCP", PortRange: "3306/3306", Priority: "1", SourceCidrIP: "172.16.0.0/12", Description: "mysql from vpc"},
+			{Direction: "egress", Policy: "Accept", IPProtocol: "ALL", PortRange: "-1/-1", Priority: "1", DestCidrIP: "0.0.0.0/0"},
+		},
+	},
+	{
+		ECSSecurityGroup: api.ECSSecurityGroup{
+			SecurityGroupID:   "sg-demo002",
+			SecurityGroupName: "ctk-demo-jump",
+			Description:       "bastion",
+			VpcID:             "vpc-demo002",
+		},
+		Region:  "cn-beijing",
+		Members: []string{"i-demo002"},
+		Permissions: []api.ECSSecurityPermission{
+			{Direction: "ingress", Policy: "Accept", IPProtocol: "TCP", PortRange: "22/22", Priority: "1", SourceCidrIP: "0.0.0.0/0", Description: "ssh"},
+			{Direction: "ingress", Policy: "Drop", IPProtocol: "TCP", PortRange: "3389/3389", Priority: "100", SourceCidrIP: "0.0.0.0/0"},
+		},
+	},
+}
+
+func securityGroupsForInstance(instanceID string) []string {
+	var out []string
+	for _, group := range demoSecurityGroups {
+		for _, member := range group.Members {
+			if member == instanceID {
+				out = append(out, group.SecurityGroupID)
+			}
+		}
+	}
+	return out
+}
+
+func handleDescribeSecurityGroups(req *http.Request) (*http.Response, error) {
+	query := req.URL.Query()
+	region := strings.TrimSpace(query.Get("RegionId"))
+	pageNumber := demoreplay.ParseInt(query.Get("PageNumber"), 1)
+	pageSize := demoreplay.ParseInt(query.Get("PageSize"), 100)
+	var groups []api.ECSSecurityGroup
+	for _, group := range demoSecurityGroups {
+		if group.Region == region {
+			groups = append(groups, group.ECSSecurityGroup)
+		}
+	}
+	window := demoreplay.PageWindow(len(groups), pageNumber, pageSize)
+	return demoreplay.JSONResponse(req, http.StatusOK, api.DescribeSecurityGroupsResponse{
+		PageSize:       pageSize,
+		PageNumber:     pageNumber,
+		RequestID:      "req-ecs-security-groups",
+		TotalCount:     len(groups),
+		SecurityGroups: api.ECSSecurityGroupList{SecurityGroup: groups[window.Start:window.End]},
+	}), nil
+}
+
+func handleDescribeSecurityGroupAttribute(req *http.Request) (*http.Response, error) {
+	groupID := strings.TrimSpace(req.URL.Query().Get("SecurityGroupId"))
+	for _, group := range demoSecurityGroups {
+		if group.SecurityGroupID != groupID {
+			continue
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, api.DescribeSecurityGroupAttributeResponse{
+			RequestID:       "req-ecs-security-group-attribute",
+			SecurityGroupID: group.SecurityGroupID,
+			VpcID:           group.VpcID,
+			Permissions:     api.ECSSecurityPermissionSet{Permission: group.Permissions},
+		}), nil
+	}
+	return rpcErrorResponse(req, http.StatusNotFound, "InvalidSecurityGroupId.NotFound", fmt.Sprintf("The specified security group %s does not exist.", groupID)), nil
+}
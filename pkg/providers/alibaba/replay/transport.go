@@ -34,6 +34,8 @@ type transport struct {
 	bucketACL    map[string]string
 	accessKeys   map[string][]ramAccessKeyFixture
 	accessKeySeq int
+	objects      map[string][]byte
+	objectSeq    int
 }
 
 func newTransport() *transport {
@@ -42,6 +44,7 @@ func newTransport() *transport {
 		userPolicy:  seedUserPolicies(),
 		bucketACL:   seedBucketACLs(),
 		accessKeys:  seedRAMAccessKeys(),
+		objects:     make(map[string][]byte),
 	}
 }
 
@@ -109,6 +112,7 @@ func (t *transport) handleRPC(req *http.Request) (*http.Response, error) {
 				Success:   true,
 				Data: api.AccountBalanceData{
 					AvailableCashAmount: demoBalanceAmount(),
+					Currency:            "CNY",
 				},
 			}), nil
 		}
@@ -194,6 +198,7 @@ func (t *transport) handleECS(req *http.Request, action string) (*http.Response,
 						},
 					},
 				},
+				SecurityGroupIDs: api.ECSSecurityGroupIDs{SecurityGroupID: securityGroupsForInstance(host.ID)},
 			}
 			items = append(items, instance)
 		}
@@ -204,6 +209,10 @@ func (t *transport) handleECS(req *http.Request, action string) (*http.Response,
 			TotalCount: len(hosts),
 			Instances:  api.ECSInstanceList{Instance: items},
 		}), nil
+	case "DescribeSecurityGroups":
+		return handleDescribeSecurityGroups(req)
+	case "DescribeSecurityGroupAttribute":
+		return handleDescribeSecurityGroupAttribute(req)
 	case "RunCommand":
 		instanceID := strings.TrimSpace(demoreplay.FirstNonEmpty(query.Get("InstanceId.1"), query.Get("InstanceId")))
 		command := strings.TrimSpace(query.Get("CommandContent"))
@@ -433,6 +442,28 @@ func (t *transport) handleRAM(req *http.Request, action string) (*http.Response,
 			RequestID:  "req-ram-list-access-keys",
 			AccessKeys: api.RAMAccessKeyList{AccessKey: out},
 		}), nil
+	case "GetUserMFAInfo":
+		user, ok := findRAMUser(query.Get("UserName"))
+		if !ok {
+			return rpcErrorResponse(req, http.StatusNotFound, "EntityNotExist.User", "The specified RAM user does not exist."), nil
+		}
+		if user.MFASerial == "" {
+			return rpcErrorResponse(req, http.StatusNotFound, "EntityNotExist.User.MFADevice", "The specified user does not have a MFA device."), nil
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, api.GetRAMUserMFAInfoResponse{
+			RequestID: "req-ram-user-mfa-info",
+			MFADevice: api.RAMMFADevice{SerialNumber: user.MFASerial, Type: "VMFA"},
+		}), nil
+	case "GetAccessKeyLastUsed":
+		userName := strings.TrimSpace(query.Get("UserName"))
+		accessKeyID := strings.TrimSpace(query.Get("UserAccessKeyId"))
+		if !t.hasAccessKey(userName, accessKeyID) {
+			return rpcErrorResponse(req, http.StatusNotFound, "EntityNotExist.User.AccessKey", "The specified access key does not exist."), nil
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, api.GetRAMAccessKeyLastUsedResponse{
+			RequestID:         "req-ram-access-key-last-used",
+			AccessKeyLastUsed: api.RAMAccessKeyLastUsed{LastUsedDate: demoRAMKeyLastUsed[accessKeyID]},
+		}), nil
 	case "CreateAccessKey":
 		userName := strings.TrimSpace(query.Get("UserName"))
 		if userName == "" {
@@ -492,6 +523,17 @@ func (t *transport) mintRAMAccessKey(userName string) ramAccessKeyFixture {
 	return key
 }
 
+func (t *transport) hasAccessKey(userName, accessKeyID string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, k := range t.accessKeys[userName] {
+		if k.AccessKeyID == accessKeyID {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *transport) deleteRAMAccessKey(userName, accessKeyID string) bool {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -702,7 +744,11 @@ func (t *transport) handleSMS(req *http.Request, action string) (*http.Response,
 }
 
 func (t *transport) handleOSS(req *http.Request) (*http.Response, error) {
-	switch verifyOSSAuth(req) {
+	verify := verifyOSSAuth
+	if req.URL.Query().Has("Signature") {
+		verify = verifyOSSPresigned
+	}
+	switch verify(req) {
 	case demoreplay.AuthInvalidAccessKey:
 		return ossErrorResponse(req, http.StatusForbidden, "InvalidAccessKeyId", "The OSS Access Key Id you provided does not exist in our records."), nil
 	case demoreplay.AuthInvalidSignature:
@@ -732,10 +778,16 @@ func (t *transport) handleOSS(req *http.Request) (*http.Response, error) {
 	if region := ossRegionFromHost(requestHost(req)); region != "" && region != bucket.Region {
 		return ossErrorResponse(req, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist."), nil
 	}
+	if key := strings.TrimPrefix(req.URL.Path, "/"); key != "" {
+		return t.handleOSSObject(req, bucket.Name, key)
+	}
 	query := req.URL.Query()
 	if _, hasACL := query["acl"]; hasACL {
 		return t.handleOSSBucketACL(req, bucket.Name)
 	}
+	if _, hasPolicy := query["policy"]; hasPolicy {
+		return ossErrorResponse(req, http.StatusNotFound, "NoSuchBucketPolicy", "The bucket policy does not exist."), nil
+	}
 	maxKeys := demoreplay.ParseInt(query.Get("max-keys"), 1000)
 	window := demoreplay.PageWindow(len(bucket.Objects), 1, maxKeys)
 	return demoreplay.XMLResponse(req, http.StatusOK, oss.ListObjectsResponse{
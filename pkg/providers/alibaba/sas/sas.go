@@ -3,6 +3,7 @@ package sas
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
 	aliauth "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/auth"
@@ -33,6 +34,13 @@ func (d *Driver) SetClientOptions(opts ...api.Option) {
 }
 
 func (d *Driver) DumpEvents(ctx context.Context) ([]schema.Event, error) {
+	return d.QueryEvents(ctx, schema.EventQuery{})
+}
+
+// QueryEvents applies q to the suspicious-event list. DescribeSuspEvents has
+// no filter the query maps onto reliably (see the source IP note below), so
+// the whole query is evaluated client-side.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
 	var events []schema.Event
 	client := d.newClient()
 	/*
@@ -62,7 +70,7 @@ func (d *Driver) DumpEvents(ctx context.Context) ([]schema.Event, error) {
 			Name:     event.AlarmEventNameDisplay,
 			Affected: event.InstanceName,
 			Status:   eventStatus[event.EventStatus],
-			Time:     event.LastTime,
+			Time:     normalizeEventTime(event.LastTime),
 		}
 		for _, detail := range event.Details {
 			switch detail.NameDisplay {
@@ -77,8 +85,26 @@ func (d *Driver) DumpEvents(ctx context.Context) ([]schema.Event, error) {
 		}
 		events = append(events, _event)
 	}
-	return events, nil
+	return q.Filter(events), nil
+}
+
+// sasLocation is the zone of the wall-clock times Security Center returns
+// (China Standard Time, without an offset in the value).
+var sasLocation = time.FixedZone("CST", 8*60*60)
 
+// normalizeEventTime rewrites a "YYYY-MM-DD HH:MM:SS" LastTime as RFC3339 so
+// since= / until= can be applied to it. Values already in RFC3339 or in an
+// unknown form are returned unchanged.
+func normalizeEventTime(value string) string {
+	value = strings.TrimSpace(value)
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return value
+	}
+	at, err := time.ParseInLocation(time.DateTime, value, sasLocation)
+	if err != nil {
+		return value
+	}
+	return at.UTC().Format(time.RFC3339)
 }
 
 func (d *Driver) HandleEvents(ctx context.Context, eid string) (schema.EventActionResult, error) {
@@ -11,6 +11,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
 	aliauth "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/auth"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -113,3 +114,30 @@ func captureStdout(t *testing.T, fn func()) string {
 	os.Stdout = originalStdout
 	return <-done
 }
+
+func TestQueryEventsAppliesWindowToSASLocalTime(t *testing.T) {
+	logger.SetOutput(io.Discard)
+	t.Cleanup(func() {
+		logger.SetOutput(nil)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"RequestId":"req-events","SuspEvents":[{"SecurityEventIds":"1","AlarmEventNameDisplay":"old","LastTime":"2026-04-17 20:00:00"},{"SecurityEventIds":"2","AlarmEventNameDisplay":"new","LastTime":"2026-04-18 20:00:00"}]}`)
+	}))
+	defer server.Close()
+
+	driver := Driver{
+		Cred:          aliauth.New("ak", "sk", ""),
+		clientOptions: []api.Option{api.WithBaseURL(server.URL)},
+	}
+	events, err := driver.QueryEvents(context.Background(), schema.EventQuery{Since: time.Date(2026, 4, 18, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("QueryEvents() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Id != "2" {
+		t.Fatalf("unexpected events: %+v", events)
+	}
+	if events[0].Time != "2026-04-18T12:00:00Z" {
+		t.Fatalf("LastTime not normalized to UTC RFC3339: %q", events[0].Time)
+	}
+}
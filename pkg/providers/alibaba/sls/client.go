@@ -3,6 +3,9 @@ package sls
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 type Client struct {
@@ -31,7 +34,7 @@ func NewClient(internal bool, region, accessKeyId, accessKeySecret, securityToke
 		region:          region,
 		version:         SLSAPIVersion,
 		endpoint:        SLSDefaultEndpoint,
-		httpClient:      &http.Client{},
+		httpClient:      ratelimit.WrapClient("alibaba", telemetry.WrapClient("alibaba", &http.Client{})),
 	}
 }
 
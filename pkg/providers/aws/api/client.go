@@ -35,6 +35,7 @@ type Option func(*Client)
 
 type Client struct {
 	credential  auth.Credential
+	credentials CredentialProvider
 	httpClient  *http.Client
 	retryPolicy RetryPolicy
 	signer      SigV4Signer
@@ -77,6 +78,17 @@ func WithClock(now func() time.Time) Option {
 	}
 }
 
+// WithCredentialProvider makes the client resolve its signing credential per
+// request instead of using the static credential passed to NewClient. Used by
+// AssumeRole chains whose temporary credentials rotate during a run.
+func WithCredentialProvider(provider CredentialProvider) Option {
+	return func(c *Client) {
+		if provider != nil {
+			c.credentials = provider
+		}
+	}
+}
+
 func WithBaseURL(rawURL string) Option {
 	return func(c *Client) {
 		if rawURL == "" {
@@ -92,7 +104,8 @@ func (c *Client) DoXML(ctx context.Context, req Request, resp any) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if err := c.credential.Validate(); err != nil {
+	credential, err := c.resolveCredential(ctx)
+	if err != nil {
 		return err
 	}
 	service := strings.TrimSpace(strings.ToLower(req.Service))
@@ -121,7 +134,7 @@ func (c *Client) DoXML(ctx context.Context, req Request, resp any) error {
 	scheme, host, path := c.resolveEndpoint(req, service, region)
 	timestamp := c.now().UTC()
 	contentType := "application/x-www-form-urlencoded; charset=utf-8"
-	signed, err := c.signer.Sign(c.credential, SignInput{
+	signed, err := c.signer.Sign(credential, SignInput{
 		Method:      method,
 		Service:     service,
 		Region:      region,
@@ -136,7 +149,7 @@ func (c *Client) DoXML(ctx context.Context, req Request, resp any) error {
 		return err
 	}
 
-	headers := c.buildHeaders(req.Headers, contentType, signed)
+	headers := c.buildHeaders(req.Headers, contentType, credential.SessionToken, signed)
 	requestURL := url.URL{
 		Scheme: scheme,
 		Host:   host,
@@ -199,15 +212,37 @@ func decodeJSONBody(body []byte, out any) error {
 }
 
 func (c *Client) doREST(ctx context.Context, req Request, resp any, decode func([]byte, any) error) error {
+	raw, err := c.DoRESTRaw(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp == nil || len(raw.Body) == 0 {
+		return nil
+	}
+	return decode(raw.Body, resp)
+}
+
+// Response is an undecoded REST response. S3 object calls use it because
+// their result is the body itself plus headers such as x-amz-request-id.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// DoRESTRaw sends req like DoRESTXML but returns the response undecoded.
+// Error statuses are still returned as *APIError.
+func (c *Client) DoRESTRaw(ctx context.Context, req Request) (Response, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	if err := c.credential.Validate(); err != nil {
-		return err
+	credential, err := c.resolveCredential(ctx)
+	if err != nil {
+		return Response{}, err
 	}
 	service := strings.TrimSpace(strings.ToLower(req.Service))
 	if service == "" {
-		return fmt.Errorf("aws client: empty service")
+		return Response{}, fmt.Errorf("aws client: empty service")
 	}
 
 	method := strings.ToUpper(strings.TrimSpace(req.Method))
@@ -218,15 +253,15 @@ func (c *Client) doREST(ctx context.Context, req Request, resp any, decode func(
 	body := append([]byte(nil), req.Body...)
 	query := httpclient.CloneValues(req.Query)
 	headers := httpclient.CloneHeader(req.Headers)
-	if service == "s3" && strings.TrimSpace(headers.Get("X-Amz-Content-Sha256")) == "" {
+	if (service == "s3" || service == "s3-control") && strings.TrimSpace(headers.Get("X-Amz-Content-Sha256")) == "" {
 		headers.Set("X-Amz-Content-Sha256", hashSHA256Hex(body))
 	}
 	scheme, host, path := c.resolveEndpoint(req, service, region)
 	timestamp := c.now().UTC()
 	contentType := strings.TrimSpace(headers.Get("Content-Type"))
-	signed, err := c.signer.Sign(c.credential, SignInput{
+	signed, err := c.signer.Sign(credential, SignInput{
 		Method:      method,
-		Service:     service,
+		Service:     signingName(service),
 		Region:      region,
 		Host:        host,
 		Path:        path,
@@ -237,42 +272,119 @@ func (c *Client) doREST(ctx context.Context, req Request, resp any, decode func(
 		Headers:     headers,
 	})
 	if err != nil {
-		return err
+		return Response{}, err
 	}
 
-	finalHeaders := c.buildHeaders(headers, contentType, signed)
+	finalHeaders := c.buildHeaders(headers, contentType, credential.SessionToken, signed)
 	requestURL := url.URL{
 		Scheme:   scheme,
 		Host:     host,
 		Path:     path,
 		RawQuery: query.Encode(),
 	}
-
-	httpResp, err := c.retryPolicy.Do(ctx, req.Idempotent, func() (*http.Response, error) {
+	return c.send(ctx, req.Idempotent, func() (*http.Request, error) {
 		httpReq, err := http.NewRequestWithContext(ctx, method, requestURL.String(), bytes.NewReader(body))
 		if err != nil {
 			return nil, err
 		}
 		httpReq.Host = host
 		httpReq.Header = finalHeaders.Clone()
+		return httpReq, nil
+	})
+}
+
+// PresignREST returns a URL that performs the body-less req without further
+// credentials until expires elapses, signed with SigV4 query parameters.
+func (c *Client) PresignREST(ctx context.Context, req Request, expires time.Duration) (string, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	credential, err := c.resolveCredential(ctx)
+	if err != nil {
+		return "", err
+	}
+	service := strings.TrimSpace(strings.ToLower(req.Service))
+	if service == "" {
+		return "", fmt.Errorf("aws client: empty service")
+	}
+	method := strings.ToUpper(strings.TrimSpace(req.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	region := normalizeServiceRegion(service, req.Region)
+	scheme, host, path := c.resolveEndpoint(req, service, region)
+	query, err := c.signer.Presign(credential, SignInput{
+		Method:    method,
+		Service:   signingName(service),
+		Region:    region,
+		Host:      host,
+		Path:      path,
+		Query:     req.Query,
+		Timestamp: c.now().UTC(),
+	}, expires)
+	if err != nil {
+		return "", err
+	}
+	presigned := url.URL{
+		Scheme:   scheme,
+		Host:     host,
+		Path:     path,
+		RawQuery: query.Encode(),
+	}
+	return presigned.String(), nil
+}
+
+// GetPresigned fetches a URL from PresignREST. The request carries no
+// credentials of its own, as an anonymous holder of the URL would send it.
+func (c *Client) GetPresigned(ctx context.Context, rawURL string) (Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.send(ctx, true, func() (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	})
+}
+
+func (c *Client) send(ctx context.Context, idempotent bool, build func() (*http.Request, error)) (Response, error) {
+	httpResp, err := c.retryPolicy.Do(ctx, idempotent, func() (*http.Response, error) {
+		httpReq, err := build()
+		if err != nil {
+			return nil, err
+		}
 		return c.httpClient.Do(httpReq)
 	})
 	if err != nil {
-		return err
+		return Response{}, err
 	}
 	defer httpclient.CloseResponse(httpResp)
 
 	responseBody, err := io.ReadAll(httpResp.Body)
 	if err != nil {
-		return fmt.Errorf("read aws response: %w", err)
+		return Response{}, fmt.Errorf("read aws response: %w", err)
 	}
 	if err := DecodeError(httpResp.StatusCode, responseBody); err != nil {
-		return err
+		return Response{}, err
 	}
-	if resp == nil || len(responseBody) == 0 {
-		return nil
+	return Response{
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+		Body:       responseBody,
+	}, nil
+}
+
+func (c *Client) resolveCredential(ctx context.Context) (auth.Credential, error) {
+	credential := c.credential
+	if c.credentials != nil {
+		resolved, err := c.credentials.Retrieve(ctx)
+		if err != nil {
+			return auth.Credential{}, err
+		}
+		credential = resolved
 	}
-	return decode(responseBody, resp)
+	if err := credential.Validate(); err != nil {
+		return auth.Credential{}, err
+	}
+	return credential, nil
 }
 
 func (c *Client) resolveEndpoint(req Request, service, region string) (string, string, string) {
@@ -289,6 +401,12 @@ func (c *Client) resolveEndpoint(req Request, service, region string) (string, s
 		}
 		path = httpclient.JoinPath(c.baseURL.Path, path)
 	}
+	// S3 Control addresses the account through a host prefix.
+	if service == "s3-control" && c.baseURL == nil {
+		if account := strings.TrimSpace(req.Headers.Get("x-amz-account-id")); account != "" {
+			host = account + "." + host
+		}
+	}
 	if req.Scheme != "" {
 		scheme = req.Scheme
 	}
@@ -298,14 +416,14 @@ func (c *Client) resolveEndpoint(req Request, service, region string) (string, s
 	return scheme, host, path
 }
 
-func (c *Client) buildHeaders(extra http.Header, contentType string, signed Signature) http.Header {
+func (c *Client) buildHeaders(extra http.Header, contentType, sessionToken string, signed Signature) http.Header {
 	headers := http.Header{}
 	if strings.TrimSpace(contentType) != "" {
 		headers.Set("Content-Type", contentType)
 	}
 	headers.Set("X-Amz-Date", signed.AmzDate)
 	headers.Set("Authorization", signed.Authorization)
-	if token := strings.TrimSpace(c.credential.SessionToken); token != "" {
+	if token := strings.TrimSpace(sessionToken); token != "" {
 		headers.Set("X-Amz-Security-Token", token)
 	}
 	for key, values := range extra {
@@ -335,9 +453,9 @@ func normalizeServiceRegion(service, region string) string {
 		return normalizeIAMRegion(region)
 	case "sts":
 		return normalizeSTSRegion(region)
-	case "ce":
-		// Cost Explorer is signed against us-east-1 (commercial) /
-		// cn-northwest-1 (China).
+	case "ce", "organizations":
+		// Cost Explorer and Organizations are signed against us-east-1
+		// (commercial) / cn-northwest-1 (China).
 		if strings.HasPrefix(region, "cn-") {
 			return "cn-northwest-1"
 		}
@@ -347,6 +465,14 @@ func normalizeServiceRegion(service, region string) string {
 	}
 }
 
+// signingName is the SigV4 service name of service. S3 Control signs as S3.
+func signingName(service string) string {
+	if service == "s3-control" {
+		return "s3"
+	}
+	return service
+}
+
 func defaultHost(service, region string) string {
 	if service == "sts" {
 		if strings.HasPrefix(region, "cn-") {
@@ -377,6 +503,14 @@ func defaultHost(service, region string) string {
 		}
 		return "ce.us-east-1.amazonaws.com"
 	}
+	if service == "organizations" {
+		// Organizations is global; commercial partition requests go to the
+		// us-east-1 endpoint, China to cn-northwest-1.
+		if strings.HasPrefix(region, "cn-") {
+			return "organizations.cn-northwest-1.amazonaws.com.cn"
+		}
+		return "organizations.us-east-1.amazonaws.com"
+	}
 	suffix := "amazonaws.com"
 	if strings.HasPrefix(region, "cn-") {
 		suffix = "amazonaws.com.cn"
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+)
+
+const (
+	// assumeRoleDuration is the session length requested per hop. Role
+	// chaining caps sessions at one hour regardless of the role's maximum.
+	assumeRoleDuration = time.Hour
+	// refreshWindow re-assumes the role this long before expiry so a request
+	// signed near the boundary never goes out with a stale token.
+	refreshWindow = 5 * time.Minute
+)
+
+// CredentialProvider supplies the credential used to sign each request.
+type CredentialProvider interface {
+	Retrieve(ctx context.Context) (auth.Credential, error)
+}
+
+// AssumeRoleProvider mints and caches temporary credentials for one
+// AssumeRole hop. The source client signs the sts:AssumeRole call, so a
+// multi-hop chain is built by pointing each hop at a client backed by the
+// previous hop (see Client.AssumeRoleChain).
+type AssumeRoleProvider struct {
+	source *Client
+	hop    auth.RoleHop
+	region string
+
+	mu         sync.Mutex
+	credential auth.Credential
+	expiration time.Time
+	mfaUsed    bool
+}
+
+func NewAssumeRoleProvider(source *Client, region string, hop auth.RoleHop) *AssumeRoleProvider {
+	return &AssumeRoleProvider{
+		source: source,
+		hop:    hop,
+		region: region,
+	}
+}
+
+// Retrieve returns the cached credential, re-assuming the role once it is
+// within refreshWindow of expiry. An MFA token code is single-use, so a hop
+// that required MFA cannot refresh and reports an explicit error instead.
+func (p *AssumeRoleProvider) Retrieve(ctx context.Context) (auth.Credential, error) {
+	if p == nil || p.source == nil {
+		return auth.Credential{}, errors.New("aws assume-role: nil source client")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.source.now()
+	if p.credential.AccessKeyID != "" && now.Add(refreshWindow).Before(p.expiration) {
+		return p.credential, nil
+	}
+	if p.mfaUsed {
+		return auth.Credential{}, fmt.Errorf("aws assume-role: session for %s expired; MFA-protected roles need a fresh mfaToken", p.hop.RoleARN)
+	}
+	out, err := p.source.AssumeRole(ctx, p.region, AssumeRoleInput{
+		RoleARN:         p.hop.RoleARN,
+		RoleSessionName: p.hop.SessionName,
+		ExternalID:      p.hop.ExternalID,
+		SerialNumber:    p.hop.MFASerial,
+		TokenCode:       p.hop.MFAToken,
+		DurationSeconds: int64(assumeRoleDuration / time.Second),
+	})
+	if err != nil {
+		return auth.Credential{}, fmt.Errorf("aws assume-role %s: %w", p.hop.RoleARN, err)
+	}
+	p.credential = auth.New(out.AccessKeyID, out.SecretAccessKey, out.SessionToken)
+	p.expiration = out.Expiration
+	if p.expiration.IsZero() {
+		p.expiration = now.Add(assumeRoleDuration)
+	}
+	p.mfaUsed = p.hop.MFASerial != ""
+	return p.credential, nil
+}
+
+// AssumeRoleChain returns a client whose requests are signed with the
+// credential of the last hop. Each hop shares the transport, retry policy and
+// clock of c; an empty chain returns c unchanged.
+func (c *Client) AssumeRoleChain(region string, hops []auth.RoleHop) *Client {
+	current := c
+	for _, hop := range hops {
+		provider := NewAssumeRoleProvider(current, region, hop)
+		next := *c
+		next.credentials = provider
+		current = &next
+	}
+	return current
+}
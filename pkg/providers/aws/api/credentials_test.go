@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+)
+
+func TestAssumeRoleChainCachesAndRefreshes(t *testing.T) {
+	now := time.Date(2026, 4, 18, 12, 0, 0, 0, time.UTC)
+	assumed := make([]string, 0)
+	callerKeys := make([]string, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form, _ := url.ParseQuery(readBody(t, r))
+		accessKey := strings.Split(strings.TrimPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential="), "/")[0]
+		switch form.Get("Action") {
+		case "AssumeRole":
+			assumed = append(assumed, accessKey+">"+form.Get("RoleArn"))
+			if form.Get("RoleSessionName") != "ctk" || form.Get("DurationSeconds") != "3600" {
+				t.Fatalf("unexpected assume-role form: %v", form)
+			}
+			if strings.HasSuffix(form.Get("RoleArn"), "/audit") && form.Get("ExternalId") != "ext" {
+				t.Fatalf("expected external id on audit hop, got %v", form)
+			}
+			key := "ASIA" + strings.ToUpper(form.Get("RoleArn")[strings.LastIndex(form.Get("RoleArn"), "/")+1:])
+			_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials>` +
+				`<AccessKeyId>` + key + `</AccessKeyId><SecretAccessKey>secret</SecretAccessKey><SessionToken>token-` + key + `</SessionToken>` +
+				`<Expiration>` + now.Add(time.Hour).Format(time.RFC3339) + `</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+		case "GetCallerIdentity":
+			if r.Header.Get("X-Amz-Security-Token") != "token-"+accessKey {
+				t.Fatalf("expected session token for %s, got %q", accessKey, r.Header.Get("X-Amz-Security-Token"))
+			}
+			callerKeys = append(callerKeys, accessKey)
+			_, _ = w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult><Arn>arn</Arn></GetCallerIdentityResult></GetCallerIdentityResponse>`))
+		default:
+			t.Fatalf("unexpected action: %v", form)
+		}
+	}))
+	defer server.Close()
+
+	base := NewClient(
+		auth.New("AKID", "SECRET", ""),
+		WithBaseURL(server.URL),
+		WithClock(func() time.Time { return now }),
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 1,
+			Sleep:       func(context.Context, time.Duration) error { return nil },
+		}),
+	)
+	client := base.AssumeRoleChain("us-east-1", []auth.RoleHop{
+		{RoleARN: "arn:aws:iam::111111111111:role/hub", SessionName: "ctk"},
+		{RoleARN: "arn:aws:iam::222222222222:role/audit", SessionName: "ctk", ExternalID: "ext"},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetCallerIdentity(context.Background(), "us-east-1"); err != nil {
+			t.Fatalf("GetCallerIdentity: %v", err)
+		}
+	}
+	if got := strings.Join(assumed, ","); got != "AKID>arn:aws:iam::111111111111:role/hub,ASIAHUB>arn:aws:iam::222222222222:role/audit" {
+		t.Fatalf("expected one assume per hop in chain order, got %s", got)
+	}
+
+	now = now.Add(56 * time.Minute)
+	if _, err := client.GetCallerIdentity(context.Background(), "us-east-1"); err != nil {
+		t.Fatalf("GetCallerIdentity after refresh: %v", err)
+	}
+	if len(assumed) != 4 {
+		t.Fatalf("expected both hops to refresh inside the expiry window, got %v", assumed)
+	}
+	if got := strings.Join(callerKeys, ","); got != "ASIAAUDIT,ASIAAUDIT,ASIAAUDIT" {
+		t.Fatalf("expected requests signed by the last hop, got %s", got)
+	}
+}
+
+func TestAssumeRoleProviderRejectsMFARefresh(t *testing.T) {
+	now := time.Date(2026, 4, 18, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		form, _ := url.ParseQuery(readBody(t, r))
+		if form.Get("SerialNumber") == "" || form.Get("TokenCode") != "123456" {
+			t.Fatalf("expected MFA parameters, got %v", form)
+		}
+		_, _ = w.Write([]byte(`<AssumeRoleResponse><AssumeRoleResult><Credentials><AccessKeyId>ASIA1</AccessKeyId>` +
+			`<SecretAccessKey>s</SecretAccessKey><SessionToken>t</SessionToken><Expiration>` + now.Add(time.Hour).Format(time.RFC3339) +
+			`</Expiration></Credentials></AssumeRoleResult></AssumeRoleResponse>`))
+	}))
+	defer server.Close()
+
+	source := NewClient(auth.New("AKID", "SECRET", ""), WithBaseURL(server.URL), WithClock(func() time.Time { return now }))
+	provider := NewAssumeRoleProvider(source, "us-east-1", auth.RoleHop{
+		RoleARN:     "arn:aws:iam::111111111111:role/mfa",
+		SessionName: "ctk",
+		MFASerial:   "arn:aws:iam::111111111111:mfa/alice",
+		MFAToken:    "123456",
+	})
+	if _, err := provider.Retrieve(context.Background()); err != nil {
+		t.Fatalf("Retrieve: %v", err)
+	}
+	now = now.Add(2 * time.Hour)
+	if _, err := provider.Retrieve(context.Background()); err == nil || !strings.Contains(err.Error(), "mfaToken") {
+		t.Fatalf("expected MFA refresh error, got %v", err)
+	}
+}
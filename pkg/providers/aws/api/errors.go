@@ -140,6 +140,15 @@ func ErrorCode(err error) string {
 	return ""
 }
 
+// ErrorRequestID returns the request ID of an *APIError in err's chain.
+func ErrorRequestID(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return strings.TrimSpace(apiErr.RequestID)
+	}
+	return ""
+}
+
 func IsAccessDenied(err error) bool {
 	if err == nil {
 		return false
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -120,7 +121,7 @@ func TestS3ListObjectsV2ParsesContentsAndNextToken(t *testing.T) {
 	defer server.Close()
 
 	client := newS3TestClient(server.URL)
-	got, err := client.ListObjectsV2(context.Background(), "ap-southeast-1", "demo-bucket", "page-2", 1000)
+	got, err := client.ListObjectsV2(context.Background(), "ap-southeast-1", "demo-bucket", "", "page-2", 1000)
 	if err != nil {
 		t.Fatalf("ListObjectsV2() error = %v", err)
 	}
@@ -169,3 +170,59 @@ func newS3TestClient(baseURL string) *Client {
 		}),
 	)
 }
+
+type captureTransport struct {
+	req  *http.Request
+	body string
+}
+
+func (c *captureTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	c.req = r
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(strings.NewReader(c.body)),
+		Request:    r,
+	}, nil
+}
+
+func TestS3ControlSignsAsS3AgainstAccountHost(t *testing.T) {
+	capture := &captureTransport{body: `<PublicAccessBlockConfiguration><BlockPublicAcls>true</BlockPublicAcls></PublicAccessBlockConfiguration>`}
+	client := NewClient(
+		auth.New("AKID", "SECRET", ""),
+		WithHTTPClient(&http.Client{Transport: capture}),
+		WithClock(func() time.Time { return time.Date(2026, 4, 18, 12, 0, 0, 0, time.UTC) }),
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: 1,
+			Sleep:       func(context.Context, time.Duration) error { return nil },
+		}),
+	)
+
+	got, err := client.GetAccountPublicAccessBlock(context.Background(), "us-east-1", "123456789012")
+	if err != nil {
+		t.Fatalf("GetAccountPublicAccessBlock() error = %v", err)
+	}
+	if !got.BlockPublicAcls {
+		t.Fatalf("unexpected response: %+v", got)
+	}
+	req := capture.req
+	if req.URL.Host != "123456789012.s3-control.us-east-1.amazonaws.com" {
+		t.Fatalf("unexpected host: %s", req.URL.Host)
+	}
+	if req.URL.Path != "/v20180820/configuration/publicAccessBlock" {
+		t.Fatalf("unexpected path: %s", req.URL.Path)
+	}
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != hashSHA256Hex(nil) {
+		t.Fatalf("unexpected payload hash header: %s", got)
+	}
+	if got := req.Header.Get("X-Amz-Account-Id"); got != "123456789012" {
+		t.Fatalf("unexpected account header: %s", got)
+	}
+	authz := req.Header.Get("Authorization")
+	if !strings.Contains(authz, "Credential=AKID/20260418/us-east-1/s3/aws4_request") {
+		t.Fatalf("expected s3 signing scope, got: %s", authz)
+	}
+	if !strings.Contains(authz, "x-amz-account-id") || !strings.Contains(authz, "x-amz-content-sha256") {
+		t.Fatalf("expected account and payload hash headers signed, got: %s", authz)
+	}
+}
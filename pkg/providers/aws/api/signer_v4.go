@@ -100,6 +100,59 @@ func (s SigV4Signer) Sign(credential auth.Credential, input SignInput) (Signatur
 	}, nil
 }
 
+// Presign signs input with SigV4 query-string authentication valid for
+// expires and returns the query to send, X-Amz-Signature included. Only the
+// host header is signed and the payload is UNSIGNED-PAYLOAD, as S3 expects
+// of pre-signed URLs.
+func (s SigV4Signer) Presign(credential auth.Credential, input SignInput, expires time.Duration) (url.Values, error) {
+	if err := credential.Validate(); err != nil {
+		return nil, err
+	}
+	service := strings.ToLower(strings.TrimSpace(input.Service))
+	region := strings.TrimSpace(input.Region)
+	host := strings.TrimSpace(input.Host)
+	if service == "" || region == "" || host == "" {
+		return nil, fmt.Errorf("aws signer: presign needs service, region and host")
+	}
+	if expires <= 0 || expires > 7*24*time.Hour {
+		return nil, fmt.Errorf("aws signer: presign expiry %s out of range", expires)
+	}
+	method := strings.ToUpper(strings.TrimSpace(input.Method))
+	if method == "" {
+		method = http.MethodGet
+	}
+	amzDate := input.Timestamp.UTC().Format("20060102T150405Z")
+	shortDate := input.Timestamp.UTC().Format("20060102")
+	credentialScope := shortDate + "/" + region + "/" + service + "/aws4_request"
+
+	query := httpclient.CloneValues(input.Query)
+	query.Del("X-Amz-Signature")
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", credential.AccessKeyID+"/"+credentialScope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires/time.Second)))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if token := strings.TrimSpace(credential.SessionToken); token != "" {
+		query.Set("X-Amz-Security-Token", token)
+	}
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(input.Path),
+		canonicalQuery(query),
+		"host:" + host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		credentialScope,
+		hashSHA256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	query.Set("X-Amz-Signature", hex.EncodeToString(signV4(credential.SecretAccessKey, shortDate, region, service, stringToSign)))
+	return query, nil
+}
+
 func buildCanonicalHeaders(host, contentType, amzDate, sessionToken string, payloadLength int, extra http.Header) (string, string) {
 	headers := map[string]string{
 		"host":       strings.TrimSpace(host),
@@ -4,6 +4,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const (
@@ -37,7 +40,7 @@ func NewTransport() *http.Transport {
 
 func NewHTTPClient() *http.Client {
 	return &http.Client{
-		Transport: NewTransport(),
+		Transport: ratelimit.Wrap("aws", telemetry.Wrap("aws", NewTransport())),
 		Timeout:   DefaultTimeout,
 	}
 }
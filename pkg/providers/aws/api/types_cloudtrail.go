@@ -13,11 +13,11 @@ const (
 )
 
 type LookupEventsInput struct {
-	StartTime        *float64           `json:"StartTime,omitempty"`
-	EndTime          *float64           `json:"EndTime,omitempty"`
-	MaxResults       *int64             `json:"MaxResults,omitempty"`
-	NextToken        *string            `json:"NextToken,omitempty"`
-	LookupAttributes []LookupAttribute  `json:"LookupAttributes,omitempty"`
+	StartTime        *float64          `json:"StartTime,omitempty"`
+	EndTime          *float64          `json:"EndTime,omitempty"`
+	MaxResults       *int64            `json:"MaxResults,omitempty"`
+	NextToken        *string           `json:"NextToken,omitempty"`
+	LookupAttributes []LookupAttribute `json:"LookupAttributes,omitempty"`
 }
 
 type LookupAttribute struct {
@@ -26,19 +26,19 @@ type LookupAttribute struct {
 }
 
 type LookupEventsOutput struct {
-	NextToken string         `json:"NextToken"`
+	NextToken string            `json:"NextToken"`
 	Events    []CloudTrailEvent `json:"Events"`
 }
 
 type CloudTrailEvent struct {
-	EventID         string                 `json:"EventId"`
-	EventName       string                 `json:"EventName"`
-	EventTime       float64                `json:"EventTime"`
-	EventSource     string                 `json:"EventSource"`
-	Username        string                 `json:"Username"`
-	AccessKeyID     string                 `json:"AccessKeyId"`
-	Resources       []CloudTrailResource   `json:"Resources"`
-	CloudTrailEvent string                 `json:"CloudTrailEvent"`
+	EventID         string               `json:"EventId"`
+	EventName       string               `json:"EventName"`
+	EventTime       float64              `json:"EventTime"`
+	EventSource     string               `json:"EventSource"`
+	Username        string               `json:"Username"`
+	AccessKeyID     string               `json:"AccessKeyId"`
+	Resources       []CloudTrailResource `json:"Resources"`
+	CloudTrailEvent string               `json:"CloudTrailEvent"`
 }
 
 type CloudTrailResource struct {
@@ -50,9 +50,10 @@ type CloudTrailResource struct {
 // CloudTrail. startTime / endTime are Unix seconds (0 = unset → CloudTrail
 // default 90-day window). nextToken paginates. The response includes both
 // the parsed event header *and* the original `CloudTrailEvent` JSON blob,
-// which the caller can re-parse for richer fields.
-func (c *Client) CloudTrailLookupEvents(ctx context.Context, region string, startTime, endTime int64, maxResults int64, nextToken string) (LookupEventsOutput, error) {
-	input := LookupEventsInput{}
+// which the caller can re-parse for richer fields. CloudTrail accepts at most
+// one lookup attribute per call.
+func (c *Client) CloudTrailLookupEvents(ctx context.Context, region string, startTime, endTime int64, maxResults int64, nextToken string, attributes ...LookupAttribute) (LookupEventsOutput, error) {
+	input := LookupEventsInput{LookupAttributes: attributes}
 	if startTime > 0 {
 		v := float64(startTime)
 		input.StartTime = &v
@@ -149,3 +149,217 @@ func (c *Client) DescribeInstances(ctx context.Context, region, nextToken string
 	}
 	return out, nil
 }
+
+type EC2SecurityGroup struct {
+	GroupID     string
+	GroupName   string
+	Description string
+	VpcID       string
+	Ingress     []EC2IPPermission
+	Egress      []EC2IPPermission
+}
+
+// EC2IPPermission is one ipPermissions entry. FromPort/ToPort are -1 (or
+// absent) when IPProtocol is "-1" (all traffic) or ICMP "any".
+type EC2IPPermission struct {
+	IPProtocol     string
+	FromPort       int
+	ToPort         int
+	CIDRs          []EC2PermissionPeer
+	GroupPeers     []EC2PermissionPeer
+	PrefixListPeer []EC2PermissionPeer
+}
+
+type EC2PermissionPeer struct {
+	Value       string
+	Description string
+}
+
+type DescribeSecurityGroupsOutput struct {
+	SecurityGroups []EC2SecurityGroup
+	NextToken      string
+}
+
+type EC2NetworkInterface struct {
+	NetworkInterfaceID string
+	InstanceID         string
+	Description        string
+	GroupIDs           []string
+}
+
+type DescribeNetworkInterfacesOutput struct {
+	NetworkInterfaces []EC2NetworkInterface
+	NextToken         string
+}
+
+type describeSecurityGroupsResponse struct {
+	XMLName        xml.Name               `xml:"DescribeSecurityGroupsResponse"`
+	SecurityGroups []ec2SecurityGroupWire `xml:"securityGroupInfo>item"`
+	NextToken      string                 `xml:"nextToken"`
+}
+
+type ec2SecurityGroupWire struct {
+	GroupID     string                `xml:"groupId"`
+	GroupName   string                `xml:"groupName"`
+	Description string                `xml:"groupDescription"`
+	VpcID       string                `xml:"vpcId"`
+	Ingress     []ec2IPPermissionWire `xml:"ipPermissions>item"`
+	Egress      []ec2IPPermissionWire `xml:"ipPermissionsEgress>item"`
+}
+
+type ec2IPPermissionWire struct {
+	IPProtocol  string             `xml:"ipProtocol"`
+	FromPort    string             `xml:"fromPort"`
+	ToPort      string             `xml:"toPort"`
+	Groups      []ec2GroupPairWire `xml:"groups>item"`
+	IPRanges    []ec2IPRangeWire   `xml:"ipRanges>item"`
+	IPv6Ranges  []ec2IPRangeWire   `xml:"ipv6Ranges>item"`
+	PrefixLists []ec2PrefixWire    `xml:"prefixListIds>item"`
+}
+
+type ec2GroupPairWire struct {
+	GroupID     string `xml:"groupId"`
+	Description string `xml:"description"`
+}
+
+type ec2IPRangeWire struct {
+	CIDRIP      string `xml:"cidrIp"`
+	CIDRIPv6    string `xml:"cidrIpv6"`
+	Description string `xml:"description"`
+}
+
+type ec2PrefixWire struct {
+	PrefixListID string `xml:"prefixListId"`
+	Description  string `xml:"description"`
+}
+
+type describeNetworkInterfacesResponse struct {
+	XMLName    xml.Name                  `xml:"DescribeNetworkInterfacesResponse"`
+	Interfaces []ec2NetworkInterfaceWire `xml:"networkInterfaceSet>item"`
+	NextToken  string                    `xml:"nextToken"`
+}
+
+type ec2NetworkInterfaceWire struct {
+	NetworkInterfaceID string             `xml:"networkInterfaceId"`
+	Description        string             `xml:"description"`
+	Attachment         ec2AttachmentWire  `xml:"attachment"`
+	Groups             []ec2GroupPairWire `xml:"groupSet>item"`
+}
+
+type ec2AttachmentWire struct {
+	InstanceID string `xml:"instanceId"`
+}
+
+func (c *Client) DescribeSecurityGroups(ctx context.Context, region, nextToken string, maxResults int) (DescribeSecurityGroupsOutput, error) {
+	query := url.Values{}
+	if nextToken = strings.TrimSpace(nextToken); nextToken != "" {
+		query.Set("NextToken", nextToken)
+	}
+	if maxResults > 0 {
+		query.Set("MaxResults", strconv.Itoa(maxResults))
+	}
+	var wire describeSecurityGroupsResponse
+	err := c.DoXML(ctx, Request{
+		Service:    "ec2",
+		Region:     region,
+		Action:     "DescribeSecurityGroups",
+		Version:    ec2APIVersion,
+		Method:     http.MethodPost,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &wire)
+	if err != nil {
+		return DescribeSecurityGroupsOutput{}, err
+	}
+	out := DescribeSecurityGroupsOutput{
+		SecurityGroups: make([]EC2SecurityGroup, 0, len(wire.SecurityGroups)),
+		NextToken:      strings.TrimSpace(wire.NextToken),
+	}
+	for _, group := range wire.SecurityGroups {
+		out.SecurityGroups = append(out.SecurityGroups, EC2SecurityGroup{
+			GroupID:     strings.TrimSpace(group.GroupID),
+			GroupName:   strings.TrimSpace(group.GroupName),
+			Description: strings.TrimSpace(group.Description),
+			VpcID:       strings.TrimSpace(group.VpcID),
+			Ingress:     convertIPPermissions(group.Ingress),
+			Egress:      convertIPPermissions(group.Egress),
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) DescribeNetworkInterfaces(ctx context.Context, region, nextToken string, maxResults int) (DescribeNetworkInterfacesOutput, error) {
+	query := url.Values{}
+	if nextToken = strings.TrimSpace(nextToken); nextToken != "" {
+		query.Set("NextToken", nextToken)
+	}
+	if maxResults > 0 {
+		query.Set("MaxResults", strconv.Itoa(maxResults))
+	}
+	var wire describeNetworkInterfacesResponse
+	err := c.DoXML(ctx, Request{
+		Service:    "ec2",
+		Region:     region,
+		Action:     "DescribeNetworkInterfaces",
+		Version:    ec2APIVersion,
+		Method:     http.MethodPost,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &wire)
+	if err != nil {
+		return DescribeNetworkInterfacesOutput{}, err
+	}
+	out := DescribeNetworkInterfacesOutput{
+		NetworkInterfaces: make([]EC2NetworkInterface, 0, len(wire.Interfaces)),
+		NextToken:         strings.TrimSpace(wire.NextToken),
+	}
+	for _, eni := range wire.Interfaces {
+		item := EC2NetworkInterface{
+			NetworkInterfaceID: strings.TrimSpace(eni.NetworkInterfaceID),
+			InstanceID:         strings.TrimSpace(eni.Attachment.InstanceID),
+			Description:        strings.TrimSpace(eni.Description),
+		}
+		for _, group := range eni.Groups {
+			if id := strings.TrimSpace(group.GroupID); id != "" {
+				item.GroupIDs = append(item.GroupIDs, id)
+			}
+		}
+		out.NetworkInterfaces = append(out.NetworkInterfaces, item)
+	}
+	return out, nil
+}
+
+func convertIPPermissions(wire []ec2IPPermissionWire) []EC2IPPermission {
+	out := make([]EC2IPPermission, 0, len(wire))
+	for _, perm := range wire {
+		item := EC2IPPermission{
+			IPProtocol: strings.TrimSpace(perm.IPProtocol),
+			FromPort:   parsePort(perm.FromPort),
+			ToPort:     parsePort(perm.ToPort),
+		}
+		for _, r := range perm.IPRanges {
+			item.CIDRs = append(item.CIDRs, EC2PermissionPeer{Value: strings.TrimSpace(r.CIDRIP), Description: strings.TrimSpace(r.Description)})
+		}
+		for _, r := range perm.IPv6Ranges {
+			item.CIDRs = append(item.CIDRs, EC2PermissionPeer{Value: strings.TrimSpace(r.CIDRIPv6), Description: strings.TrimSpace(r.Description)})
+		}
+		for _, g := range perm.Groups {
+			item.GroupPeers = append(item.GroupPeers, EC2PermissionPeer{Value: strings.TrimSpace(g.GroupID), Description: strings.TrimSpace(g.Description)})
+		}
+		for _, p := range perm.PrefixLists {
+			item.PrefixListPeer = append(item.PrefixListPeer, EC2PermissionPeer{Value: strings.TrimSpace(p.PrefixListID), Description: strings.TrimSpace(p.Description)})
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+func parsePort(raw string) int {
+	port, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return -1
+	}
+	return port
+}
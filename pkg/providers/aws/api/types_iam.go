@@ -179,6 +179,65 @@ type createAccessKeyResult struct {
 	AccessKey accessKeySecretWire `xml:"AccessKey"`
 }
 
+type IAMMFADevice struct {
+	UserName     string
+	SerialNumber string
+	EnableDate   *time.Time
+}
+
+type ListMFADevicesOutput struct {
+	Devices     []IAMMFADevice
+	Marker      string
+	IsTruncated bool
+	RequestID   string
+}
+
+// AccessKeyLastUsed reports the last call made with an access key.
+// ServiceName and Region are "N/A" and LastUsedDate is nil for a key that
+// was never used.
+type AccessKeyLastUsed struct {
+	UserName     string
+	LastUsedDate *time.Time
+	ServiceName  string
+	Region       string
+	RequestID    string
+}
+
+type mfaDeviceWire struct {
+	UserName     string `xml:"UserName"`
+	SerialNumber string `xml:"SerialNumber"`
+	EnableDate   string `xml:"EnableDate"`
+}
+
+type listMFADevicesResponse struct {
+	XMLName              xml.Name             `xml:"ListMFADevicesResponse"`
+	ListMFADevicesResult listMFADevicesResult `xml:"ListMFADevicesResult"`
+	Metadata             iamResponseMetadata  `xml:"ResponseMetadata"`
+}
+
+type listMFADevicesResult struct {
+	MFADevices  []mfaDeviceWire `xml:"MFADevices>member"`
+	IsTruncated bool            `xml:"IsTruncated"`
+	Marker      string          `xml:"Marker"`
+}
+
+type getAccessKeyLastUsedResponse struct {
+	XMLName                    xml.Name                   `xml:"GetAccessKeyLastUsedResponse"`
+	GetAccessKeyLastUsedResult getAccessKeyLastUsedResult `xml:"GetAccessKeyLastUsedResult"`
+	Metadata                   iamResponseMetadata        `xml:"ResponseMetadata"`
+}
+
+type getAccessKeyLastUsedResult struct {
+	UserName          string                `xml:"UserName"`
+	AccessKeyLastUsed accessKeyLastUsedWire `xml:"AccessKeyLastUsed"`
+}
+
+type accessKeyLastUsedWire struct {
+	LastUsedDate string `xml:"LastUsedDate"`
+	ServiceName  string `xml:"ServiceName"`
+	Region       string `xml:"Region"`
+}
+
 func (c *Client) ListUsers(ctx context.Context, region, marker string) (ListUsersOutput, error) {
 	query := url.Values{}
 	if marker = strings.TrimSpace(marker); marker != "" {
@@ -469,6 +528,71 @@ func (c *Client) DeleteAccessKey(ctx context.Context, region, userName, accessKe
 	}, nil)
 }
 
+func (c *Client) ListMFADevices(ctx context.Context, region, userName, marker string) (ListMFADevicesOutput, error) {
+	query := url.Values{}
+	if userName = strings.TrimSpace(userName); userName != "" {
+		query.Set("UserName", userName)
+	}
+	if marker = strings.TrimSpace(marker); marker != "" {
+		query.Set("Marker", marker)
+	}
+	var wire listMFADevicesResponse
+	err := c.DoXML(ctx, Request{
+		Service:    "iam",
+		Region:     region,
+		Action:     "ListMFADevices",
+		Version:    iamAPIVersion,
+		Method:     http.MethodPost,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &wire)
+	if err != nil {
+		return ListMFADevicesOutput{}, err
+	}
+	out := ListMFADevicesOutput{
+		Devices:     make([]IAMMFADevice, 0, len(wire.ListMFADevicesResult.MFADevices)),
+		Marker:      strings.TrimSpace(wire.ListMFADevicesResult.Marker),
+		IsTruncated: wire.ListMFADevicesResult.IsTruncated,
+		RequestID:   strings.TrimSpace(wire.Metadata.RequestID),
+	}
+	for _, device := range wire.ListMFADevicesResult.MFADevices {
+		out.Devices = append(out.Devices, IAMMFADevice{
+			UserName:     strings.TrimSpace(device.UserName),
+			SerialNumber: strings.TrimSpace(device.SerialNumber),
+			EnableDate:   parseAWSTime(device.EnableDate),
+		})
+	}
+	return out, nil
+}
+
+func (c *Client) GetAccessKeyLastUsed(ctx context.Context, region, accessKeyID string) (AccessKeyLastUsed, error) {
+	query := url.Values{}
+	setTrimmedQueryValue(query, "AccessKeyId", accessKeyID)
+	var wire getAccessKeyLastUsedResponse
+	err := c.DoXML(ctx, Request{
+		Service:    "iam",
+		Region:     region,
+		Action:     "GetAccessKeyLastUsed",
+		Version:    iamAPIVersion,
+		Method:     http.MethodPost,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &wire)
+	if err != nil {
+		return AccessKeyLastUsed{}, err
+	}
+	lastUsed := wire.GetAccessKeyLastUsedResult.AccessKeyLastUsed
+	return AccessKeyLastUsed{
+		UserName:     strings.TrimSpace(wire.GetAccessKeyLastUsedResult.UserName),
+		LastUsedDate: parseAWSTime(lastUsed.LastUsedDate),
+		ServiceName:  strings.TrimSpace(lastUsed.ServiceName),
+		Region:       strings.TrimSpace(lastUsed.Region),
+		RequestID:    strings.TrimSpace(wire.Metadata.RequestID),
+	}, nil
+}
+
 func normalizeIAMRegion(region string) string {
 	region = normalizeRegion(region)
 	if strings.HasPrefix(region, "cn-") {
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// AWS Organizations — JSON-1.1 RPC on the global organizations endpoint.
+const (
+	organizationsContentType  = "application/x-amz-json-1.1"
+	organizationsListAccounts = "AWSOrganizationsV20161128.ListAccounts"
+)
+
+type ListAccountsInput struct {
+	MaxResults *int64  `json:"MaxResults,omitempty"`
+	NextToken  *string `json:"NextToken,omitempty"`
+}
+
+type ListAccountsOutput struct {
+	Accounts  []OrganizationAccount `json:"Accounts"`
+	NextToken string                `json:"NextToken"`
+}
+
+type OrganizationAccount struct {
+	ID     string `json:"Id"`
+	Arn    string `json:"Arn"`
+	Email  string `json:"Email"`
+	Name   string `json:"Name"`
+	Status string `json:"Status"`
+}
+
+// OrganizationsListAccounts returns one page of member accounts. Only the
+// management account (or a delegated administrator) may call it.
+func (c *Client) OrganizationsListAccounts(ctx context.Context, region string, maxResults int64, nextToken string) (ListAccountsOutput, error) {
+	input := ListAccountsInput{}
+	if maxResults > 0 {
+		v := maxResults
+		input.MaxResults = &v
+	}
+	if nextToken != "" {
+		t := nextToken
+		input.NextToken = &t
+	}
+	body, err := json.Marshal(input)
+	if err != nil {
+		return ListAccountsOutput{}, err
+	}
+	headers := http.Header{}
+	headers.Set("Content-Type", organizationsContentType)
+	headers.Set("X-Amz-Target", organizationsListAccounts)
+	var out ListAccountsOutput
+	err = c.DoRESTJSON(ctx, Request{
+		Service:    "organizations",
+		Region:     region,
+		Method:     http.MethodPost,
+		Path:       "/",
+		Body:       body,
+		Headers:    headers,
+		Idempotent: true,
+	}, &out)
+	return out, err
+}
@@ -2,12 +2,14 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type S3Bucket struct {
@@ -113,9 +115,12 @@ func (c *Client) GetBucketLocation(ctx context.Context, region, bucket string) (
 	}, nil
 }
 
-func (c *Client) ListObjectsV2(ctx context.Context, region, bucket, continuationToken string, maxKeys int) (ListObjectsV2Output, error) {
+func (c *Client) ListObjectsV2(ctx context.Context, region, bucket, prefix, continuationToken string, maxKeys int) (ListObjectsV2Output, error) {
 	query := url.Values{}
 	query.Set("list-type", "2")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if continuationToken = strings.TrimSpace(continuationToken); continuationToken != "" {
 		query.Set("continuation-token", continuationToken)
 	}
@@ -269,3 +274,151 @@ func (c *Client) DeletePublicAccessBlock(ctx context.Context, region, bucket str
 		Query:   query,
 	}, nil)
 }
+
+// GetBucketPolicy returns bucket's policy document, or nil when the bucket
+// has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, region, bucket string) ([]byte, error) {
+	var doc json.RawMessage
+	query := url.Values{}
+	query.Set("policy", "")
+	err := c.DoRESTJSON(ctx, Request{
+		Service:    "s3",
+		Region:     region,
+		Method:     http.MethodGet,
+		Path:       "/" + strings.TrimSpace(bucket),
+		Query:      query,
+		Idempotent: true,
+	}, &doc)
+	if ErrorCode(err) == "NoSuchBucketPolicy" {
+		return nil, nil
+	}
+	return doc, err
+}
+
+// PublicAccessBlock is a bucket or account Public Access Block
+// configuration. The zero value blocks nothing.
+type PublicAccessBlock struct {
+	BlockPublicAcls       bool `xml:"BlockPublicAcls"`
+	IgnorePublicAcls      bool `xml:"IgnorePublicAcls"`
+	BlockPublicPolicy     bool `xml:"BlockPublicPolicy"`
+	RestrictPublicBuckets bool `xml:"RestrictPublicBuckets"`
+}
+
+// GetPublicAccessBlock returns bucket's Public Access Block, or the zero
+// value when none is set.
+func (c *Client) GetPublicAccessBlock(ctx context.Context, region, bucket string) (PublicAccessBlock, error) {
+	var out PublicAccessBlock
+	query := url.Values{}
+	query.Set("publicAccessBlock", "")
+	err := c.DoRESTXML(ctx, Request{
+		Service:    "s3",
+		Region:     region,
+		Method:     http.MethodGet,
+		Path:       "/" + strings.TrimSpace(bucket),
+		Query:      query,
+		Idempotent: true,
+	}, &out)
+	if ErrorCode(err) == "NoSuchPublicAccessBlockConfiguration" {
+		return PublicAccessBlock{}, nil
+	}
+	return out, err
+}
+
+// GetAccountPublicAccessBlock returns the account-wide Public Access Block
+// of accountID through S3 Control, or the zero value when none is set.
+func (c *Client) GetAccountPublicAccessBlock(ctx context.Context, region, accountID string) (PublicAccessBlock, error) {
+	var out PublicAccessBlock
+	headers := http.Header{}
+	headers.Set("x-amz-account-id", strings.TrimSpace(accountID))
+	err := c.DoRESTXML(ctx, Request{
+		Service:    "s3-control",
+		Region:     region,
+		Method:     http.MethodGet,
+		Path:       "/v20180820/configuration/publicAccessBlock",
+		Headers:    headers,
+		Idempotent: true,
+	}, &out)
+	if ErrorCode(err) == "NoSuchPublicAccessBlockConfiguration" {
+		return PublicAccessBlock{}, nil
+	}
+	return out, err
+}
+
+// ObjectOutput is the result of an S3 object call. RequestID is the
+// x-amz-request-id that server access logs and CloudTrail data events record.
+type ObjectOutput struct {
+	StatusCode int
+	RequestID  string
+	Body       []byte
+}
+
+func objectPath(bucket, key string) string {
+	return "/" + strings.TrimSpace(bucket) + "/" + strings.TrimPrefix(key, "/")
+}
+
+func objectOutput(resp Response) ObjectOutput {
+	return ObjectOutput{
+		StatusCode: resp.StatusCode,
+		RequestID:  strings.TrimSpace(resp.Header.Get("X-Amz-Request-Id")),
+		Body:       resp.Body,
+	}
+}
+
+// PutObject uploads body to bucket/key.
+func (c *Client) PutObject(ctx context.Context, region, bucket, key string, body []byte, contentType string) (ObjectOutput, error) {
+	headers := http.Header{}
+	if contentType = strings.TrimSpace(contentType); contentType != "" {
+		headers.Set("Content-Type", contentType)
+	}
+	resp, err := c.DoRESTRaw(ctx, Request{
+		Service:    "s3",
+		Region:     region,
+		Method:     http.MethodPut,
+		Path:       objectPath(bucket, key),
+		Body:       body,
+		Headers:    headers,
+		Idempotent: true,
+	})
+	return objectOutput(resp), err
+}
+
+// GetObject downloads bucket/key.
+func (c *Client) GetObject(ctx context.Context, region, bucket, key string) (ObjectOutput, error) {
+	resp, err := c.DoRESTRaw(ctx, Request{
+		Service:    "s3",
+		Region:     region,
+		Method:     http.MethodGet,
+		Path:       objectPath(bucket, key),
+		Idempotent: true,
+	})
+	return objectOutput(resp), err
+}
+
+// DeleteObject removes bucket/key.
+func (c *Client) DeleteObject(ctx context.Context, region, bucket, key string) (ObjectOutput, error) {
+	resp, err := c.DoRESTRaw(ctx, Request{
+		Service:    "s3",
+		Region:     region,
+		Method:     http.MethodDelete,
+		Path:       objectPath(bucket, key),
+		Idempotent: true,
+	})
+	return objectOutput(resp), err
+}
+
+// PresignGetObject returns a URL that downloads bucket/key without
+// credentials until expires elapses.
+func (c *Client) PresignGetObject(ctx context.Context, region, bucket, key string, expires time.Duration) (string, error) {
+	return c.PresignREST(ctx, Request{
+		Service: "s3",
+		Region:  region,
+		Method:  http.MethodGet,
+		Path:    objectPath(bucket, key),
+	}, expires)
+}
+
+// GetPresignedObject downloads an object through a PresignGetObject URL.
+func (c *Client) GetPresignedObject(ctx context.Context, rawURL string) (ObjectOutput, error) {
+	resp, err := c.GetPresigned(ctx, rawURL)
+	return objectOutput(resp), err
+}
@@ -42,6 +42,8 @@ type GetCommandInvocationOutput struct {
 	StatusDetails         string `json:"StatusDetails"`
 	StandardOutputContent string `json:"StandardOutputContent"`
 	StandardErrorContent  string `json:"StandardErrorContent"`
+	// ResponseCode is the plugin exit code; -1 until the command completes.
+	ResponseCode *int `json:"ResponseCode"`
 }
 
 // SSMSendCommand kicks off a command execution against one or more instances.
@@ -3,7 +3,11 @@ package api
 import (
 	"context"
 	"encoding/xml"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 type GetCallerIdentityOutput struct {
@@ -52,6 +56,93 @@ func (c *Client) GetCallerIdentity(ctx context.Context, region string) (GetCalle
 	}, nil
 }
 
+type AssumeRoleInput struct {
+	RoleARN         string
+	RoleSessionName string
+	ExternalID      string
+	SerialNumber    string
+	TokenCode       string
+	DurationSeconds int64
+}
+
+type AssumeRoleOutput struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Expiration      time.Time
+	AssumedRoleARN  string
+	AssumedRoleID   string
+	RequestID       string
+}
+
+type assumeRoleResponse struct {
+	XMLName          xml.Name                      `xml:"AssumeRoleResponse"`
+	AssumeRoleResult assumeRoleResult              `xml:"AssumeRoleResult"`
+	ResponseMetadata getCallerIdentityResponseMeta `xml:"ResponseMetadata"`
+}
+
+type assumeRoleResult struct {
+	Credentials struct {
+		AccessKeyID     string `xml:"AccessKeyId"`
+		SecretAccessKey string `xml:"SecretAccessKey"`
+		SessionToken    string `xml:"SessionToken"`
+		Expiration      string `xml:"Expiration"`
+	} `xml:"Credentials"`
+	AssumedRoleUser struct {
+		Arn           string `xml:"Arn"`
+		AssumedRoleID string `xml:"AssumedRoleId"`
+	} `xml:"AssumedRoleUser"`
+}
+
+// AssumeRole calls sts:AssumeRole with the client's current credential and
+// returns the temporary credential set for the target role.
+func (c *Client) AssumeRole(ctx context.Context, region string, input AssumeRoleInput) (AssumeRoleOutput, error) {
+	query := url.Values{}
+	query.Set("RoleArn", input.RoleARN)
+	query.Set("RoleSessionName", input.RoleSessionName)
+	if input.DurationSeconds > 0 {
+		query.Set("DurationSeconds", strconv.FormatInt(input.DurationSeconds, 10))
+	}
+	if input.ExternalID != "" {
+		query.Set("ExternalId", input.ExternalID)
+	}
+	if input.SerialNumber != "" {
+		query.Set("SerialNumber", input.SerialNumber)
+		query.Set("TokenCode", input.TokenCode)
+	}
+	var wire assumeRoleResponse
+	err := c.DoXML(ctx, Request{
+		Service:    "sts",
+		Region:     normalizeSTSRegion(region),
+		Action:     "AssumeRole",
+		Version:    "2011-06-15",
+		Method:     http.MethodPost,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &wire)
+	if err != nil {
+		return AssumeRoleOutput{}, err
+	}
+	creds := wire.AssumeRoleResult.Credentials
+	out := AssumeRoleOutput{
+		AccessKeyID:     creds.AccessKeyID,
+		SecretAccessKey: creds.SecretAccessKey,
+		SessionToken:    creds.SessionToken,
+		AssumedRoleARN:  wire.AssumeRoleResult.AssumedRoleUser.Arn,
+		AssumedRoleID:   wire.AssumeRoleResult.AssumedRoleUser.AssumedRoleID,
+		RequestID:       wire.ResponseMetadata.RequestID,
+	}
+	if creds.Expiration != "" {
+		expiration, err := time.Parse(time.RFC3339, creds.Expiration)
+		if err != nil {
+			return AssumeRoleOutput{}, fmt.Errorf("decode aws assume-role expiration: %w", err)
+		}
+		out.Expiration = expiration
+	}
+	return out, nil
+}
+
 func normalizeSTSRegion(region string) string {
 	return normalizeRegion(region)
 }
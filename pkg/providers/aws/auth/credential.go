@@ -2,6 +2,8 @@ package auth
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
@@ -47,3 +49,100 @@ func (c Credential) Validate() error {
 		return nil
 	}
 }
+
+// DefaultSessionName is the RoleSessionName used when the operator does not
+// set one. It shows up in the assumed-role ARN and therefore in CloudTrail.
+const DefaultSessionName = "ctk-validation"
+
+var sessionNamePattern = regexp.MustCompile(`^[\w+=,.@-]{2,64}$`)
+
+// RoleHop is one sts:AssumeRole step. A chain of hops is assumed in order,
+// each hop signing with the temporary credential minted by the previous one.
+type RoleHop struct {
+	RoleARN     string
+	ExternalID  string
+	SessionName string
+	MFASerial   string
+	MFAToken    string
+}
+
+// RoleChainFromOptions parses the optional AssumeRole settings. `roleArn`
+// accepts a comma-separated list for multi-hop chains; `externalId` is either
+// one value shared by every hop or one value per hop. MFA can only be
+// presented on the first hop, where the caller is still the IAM user that
+// owns the device. Returns nil when no role is configured.
+func RoleChainFromOptions(options schema.Options) ([]RoleHop, error) {
+	rawARNs, ok := options.GetMetadata(utils.AWSRoleArn)
+	if !ok {
+		return nil, nil
+	}
+	arns := splitList(rawARNs)
+	if len(arns) == 0 {
+		return nil, nil
+	}
+	externalIDs := []string{}
+	if raw, ok := options.GetMetadata(utils.AWSExternalId); ok {
+		// Keep empty positions so `,ext` means "no external ID on hop one".
+		for _, item := range strings.Split(raw, ",") {
+			externalIDs = append(externalIDs, strings.TrimSpace(item))
+		}
+	}
+	if len(externalIDs) > 1 && len(externalIDs) != len(arns) {
+		return nil, fmt.Errorf("aws credential: %d external IDs for %d roles; set one shared value or one per role", len(externalIDs), len(arns))
+	}
+	sessionName, err := SessionNameFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	mfaSerial, _ := options.GetMetadata(utils.AWSMFASerial)
+	mfaToken, _ := options.GetMetadata(utils.AWSMFAToken)
+	mfaSerial = strings.TrimSpace(mfaSerial)
+	mfaToken = strings.TrimSpace(mfaToken)
+	if mfaSerial != "" && mfaToken == "" {
+		return nil, fmt.Errorf("aws credential: %s requires %s", utils.AWSMFASerial, utils.AWSMFAToken)
+	}
+
+	hops := make([]RoleHop, 0, len(arns))
+	for i, arn := range arns {
+		if !strings.HasPrefix(arn, "arn:") || !strings.Contains(arn, ":role/") {
+			return nil, fmt.Errorf("aws credential: invalid role ARN %q", arn)
+		}
+		hop := RoleHop{RoleARN: arn, SessionName: sessionName}
+		switch len(externalIDs) {
+		case 0:
+		case 1:
+			hop.ExternalID = externalIDs[0]
+		default:
+			hop.ExternalID = externalIDs[i]
+		}
+		if i == 0 {
+			hop.MFASerial = mfaSerial
+			hop.MFAToken = mfaToken
+		}
+		hops = append(hops, hop)
+	}
+	return hops, nil
+}
+
+// SessionNameFromOptions returns the configured RoleSessionName or
+// DefaultSessionName, validated against the STS character set.
+func SessionNameFromOptions(options schema.Options) (string, error) {
+	sessionName := DefaultSessionName
+	if raw, ok := options.GetMetadata(utils.AWSSessionName); ok {
+		sessionName = strings.TrimSpace(raw)
+	}
+	if !sessionNamePattern.MatchString(sessionName) {
+		return "", fmt.Errorf("aws credential: invalid session name %q", sessionName)
+	}
+	return sessionName, nil
+}
+
+func splitList(raw string) []string {
+	out := make([]string, 0)
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
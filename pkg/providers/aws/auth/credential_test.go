@@ -31,3 +31,39 @@ func TestValidateRejectsEmptyFields(t *testing.T) {
 		t.Fatal("expected validation error for empty secret key")
 	}
 }
+
+func TestRoleChainFromOptions(t *testing.T) {
+	hops, err := RoleChainFromOptions(schema.Options{
+		utils.AWSRoleArn:    "arn:aws:iam::111111111111:role/hub, arn:aws:iam::222222222222:role/audit",
+		utils.AWSExternalId: "ext-1",
+		utils.AWSMFASerial:  "arn:aws:iam::111111111111:mfa/alice",
+		utils.AWSMFAToken:   "123456",
+	})
+	if err != nil {
+		t.Fatalf("RoleChainFromOptions() error = %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("expected 2 hops, got %+v", hops)
+	}
+	if hops[0].MFASerial == "" || hops[1].MFASerial != "" {
+		t.Fatalf("expected MFA on first hop only, got %+v", hops)
+	}
+	if hops[0].ExternalID != "ext-1" || hops[1].ExternalID != "ext-1" || hops[1].SessionName != DefaultSessionName {
+		t.Fatalf("unexpected hop defaults: %+v", hops)
+	}
+
+	if hops, err := RoleChainFromOptions(schema.Options{}); err != nil || hops != nil {
+		t.Fatalf("expected no chain without roleArn, got %+v / %v", hops, err)
+	}
+	invalid := []schema.Options{
+		{utils.AWSRoleArn: "not-an-arn"},
+		{utils.AWSRoleArn: "arn:aws:iam::1:role/a", utils.AWSMFASerial: "arn:aws:iam::1:mfa/a"},
+		{utils.AWSRoleArn: "arn:aws:iam::1:role/a", utils.AWSExternalId: "x,y"},
+		{utils.AWSRoleArn: "arn:aws:iam::1:role/a", utils.AWSSessionName: "bad name"},
+	}
+	for _, options := range invalid {
+		if _, err := RoleChainFromOptions(options); err == nil {
+			t.Fatalf("expected error for %+v", options)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	_ssm "github.com/404tk/cloudtoolkit/pkg/providers/aws/ssm"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/credverify"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -29,6 +30,8 @@ type Provider struct {
 	region        string
 	defaultRegion string
 	apiClient     *_api.Client
+	orgRole       string
+	sessionName   string
 }
 
 // New creates a new provider client for aws API
@@ -54,14 +57,25 @@ func NewWithConfig(options schema.Options, cfg ClientConfig) (*Provider, error)
 	if err := credential.Validate(); err != nil {
 		return nil, err
 	}
+	roleChain, err := _auth.RoleChainFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	sessionName, err := _auth.SessionNameFromOptions(options)
+	if err != nil {
+		return nil, err
+	}
 	region, _ := options.GetMetadata(utils.Region)
 	version, _ := options.GetMetadata(utils.Version)
+	orgRole, _ := options.GetMetadata(utils.AWSOrgRole)
 	defaultRegion := resolveBootstrapRegion(region, version)
-	apiClient := _api.NewClient(credential, cfg.APIOptions...)
+	apiClient := _api.NewClient(credential, cfg.APIOptions...).AssumeRoleChain(defaultRegion, roleChain)
 	provider := &Provider{
 		region:        region,
 		defaultRegion: defaultRegion,
 		apiClient:     apiClient,
+		orgRole:       strings.TrimSpace(orgRole),
+		sessionName:   sessionName,
 	}
 
 	if err := credverify.ForCloudlist(options, provider, cfg.SkipCredentialCache, func(ctx context.Context) (credverify.Result, error) {
@@ -86,15 +100,49 @@ func (p *Provider) Name() string {
 	return "aws"
 }
 
-// Resources returns the provider for an resource deployment source.
+// CredentialKey identifies the cached credential by the access key and the
+// role chain it assumes, so the same key used directly and through one or
+// more roles is stored as separate sessions. The external ID only enters as
+// a short hash since the key is also shown in the session list.
+func (p *Provider) CredentialKey(opts map[string]string) string {
+	key := opts[utils.AccessKey]
+	roles := []string{}
+	for _, arn := range strings.Split(opts[utils.AWSRoleArn], ",") {
+		if arn = strings.TrimSpace(arn); arn != "" {
+			roles = append(roles, arn)
+		}
+	}
+	if len(roles) == 0 {
+		return key
+	}
+	key += "->" + strings.Join(roles, "->")
+	if externalID := strings.TrimSpace(opts[utils.AWSExternalId]); externalID != "" {
+		key += "#" + utils.Md5Encode(externalID)[:8]
+	}
+	return key
+}
+
+// Resources returns the provider for an resource deployment source. With
+// orgRole set, the inventory fans out across every Organizations member
+// account instead (see organizationResources).
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
-	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&_billing.Driver{Client: p.apiClient}).QueryAccountBalance(ctx)
+	if p.orgRole != "" {
+		return p.organizationResources(ctx)
+	}
+	return p.collector(p.apiClient, true).Collect(ctx, env.From(ctx).Cloudlist)
+}
+
+// collector wires the per-category handlers against client. cacheHosts
+// controls whether enumerated hosts seed the SSM lookup cache, which only
+// makes sense when `shell` will later run with the same credential.
+func (p *Provider) collector(client *_api.Client, cacheHosts bool) *schema.ResourceCollector {
+	return schema.NewResourceCollector(p.Name()).
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&_billing.Driver{Client: client}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			ec2provider := &_ec2.Driver{
-				Client:        p.apiClient,
+				Client:        client,
 				Region:        p.region,
 				DefaultRegion: p.defaultRegion,
 			}
@@ -102,11 +150,13 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			schema.AppendAssets(list, hosts)
 			list.AddError("host", err)
 			list.AddError("host", ec2provider.PartialError())
-			_ssm.SetCacheHostList(hosts)
+			if cacheHosts {
+				_ssm.SetCacheHostList(hosts)
+			}
 		}).
 		Register("account", func(ctx context.Context, list *schema.Resources) {
 			iamprovider := &_iam.Driver{
-				Client:        p.apiClient,
+				Client:        client,
 				Region:        p.region,
 				DefaultRegion: p.defaultRegion,
 			}
@@ -115,20 +165,20 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			list.AddError("account", err)
 		}).
 		Register("bucket", func(ctx context.Context, list *schema.Resources) {
-			s3provider := &_s3.Driver{Client: p.apiClient, DefaultRegion: p.defaultRegion}
+			s3provider := &_s3.Driver{Client: client, DefaultRegion: p.defaultRegion}
 			storages, err := s3provider.GetBuckets(ctx)
 			schema.AppendAssets(list, storages)
 			list.AddError("bucket", err)
 		}).
 		Register("domain", func(ctx context.Context, list *schema.Resources) {
-			r53driver := &_route53.Driver{Client: p.apiClient}
+			r53driver := &_route53.Driver{Client: client}
 			domains, err := r53driver.GetDomains(ctx)
 			schema.AppendAssets(list, domains)
 			list.AddError("domain", err)
 		}).
 		Register("log", func(ctx context.Context, list *schema.Resources) {
 			logsDriver := &_logs.Driver{
-				Client:        p.apiClient,
+				Client:        client,
 				Region:        p.region,
 				DefaultRegion: p.defaultRegion,
 			}
@@ -139,7 +189,7 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 		}).
 		Register("database", func(ctx context.Context, list *schema.Resources) {
 			rdsDriver := &_rds.Driver{
-				Client:        p.apiClient,
+				Client:        client,
 				Region:        p.region,
 				DefaultRegion: p.defaultRegion,
 			}
@@ -147,9 +197,18 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			schema.AppendAssets(list, dbs)
 			list.AddError("database", err)
 			list.AddError("database", rdsDriver.PartialError())
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			ec2provider := &_ec2.Driver{
+				Client:        client,
+				Region:        p.region,
+				DefaultRegion: p.defaultRegion,
+			}
+			groups, err := ec2provider.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
+			list.AddError("securitygroup", ec2provider.PartialError())
 		})
-
-	return collector.Collect(ctx, env.From(ctx).Cloudlist)
 }
 
 func (p *Provider) UserManagement(action, username, password string) (schema.IAMResult, error) {
@@ -218,31 +277,15 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	s3provider := &_s3.Driver{Client: p.apiClient, DefaultRegion: p.defaultRegion}
 	switch action {
 	case "list":
-		var infos = make(map[string]string)
-		if bucketName == "all" {
-			buckets, err := s3provider.GetBuckets(context.Background())
-			if err != nil {
-				return nil, fmt.Errorf("list buckets: %w", err)
-			}
-			for _, b := range buckets {
-				infos[b.BucketName] = b.Region
-			}
-		} else {
-			infos[bucketName] = p.defaultRegion
+		infos, err := p.bucketInfos(ctx, s3provider, bucketName)
+		if err != nil {
+			return nil, err
 		}
 		return s3provider.ListObjects(ctx, infos)
 	case "total":
-		var infos = make(map[string]string)
-		if bucketName == "all" {
-			buckets, err := s3provider.GetBuckets(context.Background())
-			if err != nil {
-				return nil, fmt.Errorf("list buckets: %w", err)
-			}
-			for _, b := range buckets {
-				infos[b.BucketName] = b.Region
-			}
-		} else {
-			infos[bucketName] = p.defaultRegion
+		infos, err := p.bucketInfos(ctx, s3provider, bucketName)
+		if err != nil {
+			return nil, err
 		}
 		return s3provider.TotalObjects(ctx, infos)
 	default:
@@ -250,6 +293,34 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	s3provider := &_s3.Driver{Client: p.apiClient, DefaultRegion: p.defaultRegion}
+	infos, err := p.bucketInfos(ctx, s3provider, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return s3provider.WalkObjects(ctx, infos, q, visit)
+}
+
+// bucketInfos maps bucketName, or every bucket for "all", to its region.
+func (p *Provider) bucketInfos(ctx context.Context, driver *_s3.Driver, bucketName string) (map[string]string, error) {
+	infos := make(map[string]string)
+	if bucketName != "all" {
+		infos[bucketName] = p.defaultRegion
+		return infos, nil
+	}
+	buckets, err := driver.GetBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	for _, b := range buckets {
+		infos[b.BucketName] = b.Region
+	}
+	return infos, nil
+}
+
 // BucketACL implements schema.BucketACLManager for AWS S3. `level` accepts the
 // canned S3 ACL values (private / public-read / public-read-write /
 // authenticated-read / aws-exec-read) or friendly aliases resolved by
@@ -290,6 +361,14 @@ func (p *Provider) BucketACL(ctx context.Context, action, container, level strin
 	return result, fmt.Errorf("aws: unsupported bucket-acl action %q", action)
 }
 
+// ObjectAccess implements schema.ObjectAccessManager for S3. Object-level
+// requests reach CloudTrail only when data events are enabled on a trail;
+// server access logs record them by the returned request ID.
+func (p *Provider) ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	driver := &_s3.Driver{Client: p.apiClient, DefaultRegion: p.defaultRegion}
+	return driver.AccessObject(ctx, action, bucket, key, body)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for AWS IAM access
 // keys. `principal` is the IAM user name (required for create / delete); `list`
 // without a principal falls back to the calling identity. `credentialID` is
@@ -333,6 +412,18 @@ func (p *Provider) IAMCredential(ctx context.Context, action, principal, credent
 	return result, fmt.Errorf("aws: unsupported iam-credential action %q", action)
 }
 
+// IAMPosture implements schema.IAMPostureReader for AWS IAM users. Key last
+// use comes from GetAccessKeyLastUsed and console sign-in from
+// PasswordLastUsed.
+func (p *Provider) IAMPosture(ctx context.Context) ([]schema.UserPosture, error) {
+	driver := &_iam.Driver{
+		Client:        p.apiClient,
+		Region:        p.region,
+		DefaultRegion: p.defaultRegion,
+	}
+	return driver.Posture(ctx)
+}
+
 // EventDump implements schema.EventReader for AWS CloudTrail. The `dump`
 // action lists recent management-event records via `LookupEvents`. CloudTrail
 // is read-only — `whitelist` returns a clear unsupported error.
@@ -360,12 +451,23 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents implements schema.EventReader over CloudTrail LookupEvents.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &_cloudtrail.Driver{
+		Client:        p.apiClient,
+		Region:        p.region,
+		DefaultRegion: p.defaultRegion,
+	}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for AWS RDS by rotating the
 // instance master password. AWS RDS doesn't expose per-user create/delete
 // via API; rotating MasterUserPassword is the closest CSPM-detectable
 // management-plane signal (captured via CloudTrail).
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
-	driver := &_rds.Driver{Client: p.apiClient, Region: p.region, DefaultRegion: p.defaultRegion}
+	region := invcache.DatabaseRegion(ctx, instanceID, p.region)
+	driver := &_rds.Driver{Client: p.apiClient, Region: region, DefaultRegion: p.defaultRegion}
 	switch action {
 	case "useradd":
 		return driver.CreateAccount(ctx, instanceID)
@@ -387,17 +489,22 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
 		region := p.region
 		if region == "" || region == "all" {
-			region = p.defaultRegion
+			// A batch run enumerates hosts first, so the cache knows the
+			// instance's own region.
+			if cached, _, found := p.resolveInstance(ctx, instanceID); found && cached != "" {
+				region = cached
+			} else {
+				region = p.defaultRegion
+			}
 		}
 		if region == "" || region == "all" {
 			return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
 		}
 		driver := &_ssm.Driver{Client: p.apiClient, Region: region}
-		output := driver.RunCommand(instanceID, osType, command)
-		return schema.CommandResult{Output: output}, nil
+		return driver.Invoke(ctx, instanceID, osType, command)
 	}
 
-	region, osType, ok := (&_ssm.Driver{}).ResolveInstanceContext(instanceID)
+	region, osType, ok := p.resolveInstance(ctx, instanceID)
 	if !ok {
 		return schema.CommandResult{}, fmt.Errorf("unable to resolve instance metadata, run `cloudlist` first and retry")
 	}
@@ -409,6 +516,20 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 		return schema.CommandResult{}, err
 	}
 	driver := &_ssm.Driver{Client: p.apiClient, Region: region}
-	output := driver.RunCommand(instanceID, osType, strings.TrimSpace(string(command)))
-	return schema.CommandResult{Output: output}, nil
+	return driver.Invoke(ctx, instanceID, osType, strings.TrimSpace(string(command)))
+}
+
+// resolveInstance finds an instance's region and OS type in the persisted
+// inventory of the credential; this session's host list only answers when
+// no inventory store is attached (see invcache.ResolveHost).
+func (p *Provider) resolveInstance(ctx context.Context, instanceID string) (region, osType string, ok bool) {
+	host, ok := invcache.ResolveHost(ctx, instanceID, _ssm.GetCacheHostList())
+	if !ok {
+		return "", "", false
+	}
+	osType = host.OSType
+	if osType == "" {
+		osType = "linux"
+	}
+	return host.Region, osType, true
 }
@@ -7,8 +7,10 @@ package billing
 
 import (
 	"context"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -16,15 +18,15 @@ type Driver struct {
 	Client *api.Client
 }
 
-// QueryAccountBalance reports the current-month unblended cost via logger,
-// mirroring the alibaba/tencent/huawei balance drivers.
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+// QueryAccountBalance returns the current-month unblended cost as a spend
+// balance.
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	if d == nil || d.Client == nil {
-		return
+		return nil
 	}
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 	amount, unit, err := d.Client.CostExplorerCurrentMonthSpend(ctx)
@@ -33,10 +35,15 @@ func (d *Driver) QueryAccountBalance(ctx context.Context) {
 		// granted. Surface as Info, not a hard error, so cloudlist doesn't
 		// fail just because billing data isn't available.
 		logger.Info("AWS Cost Explorer query unavailable: " + err.Error())
-		return
+		return nil
 	}
 	if amount == "" {
-		return
+		return nil
 	}
-	logger.Warning("AWS current-month spend: " + amount + " " + unit)
+	return []schema.Balance{{
+		Kind:     schema.BalanceSpend,
+		Amount:   amount,
+		Currency: unit,
+		Period:   time.Now().UTC().Format("2006-01"),
+	}}
 }
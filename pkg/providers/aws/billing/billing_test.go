@@ -50,7 +50,9 @@ func TestQueryAccountBalanceSwallowsAccessDenied(t *testing.T) {
 
 	driver := &Driver{Client: newClient(server.URL)}
 	// Should not panic or propagate the error — balance is best-effort.
-	driver.QueryAccountBalance(context.Background())
+	if got := driver.QueryAccountBalance(context.Background()); got != nil {
+		t.Fatalf("expected no balance, got %+v", got)
+	}
 }
 
 func TestQueryAccountBalanceWithEmptyResultsIsNoOp(t *testing.T) {
@@ -60,7 +62,9 @@ func TestQueryAccountBalanceWithEmptyResultsIsNoOp(t *testing.T) {
 	defer server.Close()
 
 	driver := &Driver{Client: newClient(server.URL)}
-	driver.QueryAccountBalance(context.Background())
+	if got := driver.QueryAccountBalance(context.Background()); got != nil {
+		t.Fatalf("expected no balance, got %+v", got)
+	}
 }
 
 func TestCostExplorerSendsExpectedTimeWindow(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -12,10 +13,12 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
+var accessKeyPattern = regexp.MustCompile(`^(AKIA|ASIA)[A-Z0-9]{12,}$`)
+
 const (
-	defaultLookupRegion  = "us-east-1"
-	defaultMaxResults    = 50
-	maxPages             = 20
+	defaultLookupRegion = "us-east-1"
+	defaultMaxResults   = 50
+	maxPages            = 20
 )
 
 // Driver wraps the AWS CloudTrail `LookupEvents` action. CloudTrail records
@@ -32,39 +35,57 @@ type Driver struct {
 // `<startUnix>:<endUnix>` time window; pass "" to use the CloudTrail default
 // 90-day lookback.
 func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, error) {
-	if d == nil || d.Client == nil {
-		return nil, errors.New("aws cloudtrail: nil api client")
-	}
 	startTime, endTime, err := parseTimeWindow(args)
 	if err != nil {
 		return nil, err
 	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTime, endTime))
+}
+
+// QueryEvents returns the CloudTrail entries matching q. The time window and
+// the most selective of event name, principal and resource go to
+// LookupEvents; the rest of q is applied to the result.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	if d == nil || d.Client == nil {
+		return nil, errors.New("aws cloudtrail: nil api client")
+	}
+	startTime, endTime := q.UnixWindow()
+	attributes := lookupAttributes(q)
 	region := d.requestRegion()
 	out := make([]schema.Event, 0)
 	nextToken := ""
 	for page := 0; page < maxPages; page++ {
-		resp, err := d.Client.CloudTrailLookupEvents(ctx, region, startTime, endTime, defaultMaxResults, nextToken)
+		resp, err := d.Client.CloudTrailLookupEvents(ctx, region, startTime, endTime, defaultMaxResults, nextToken, attributes...)
 		if err != nil {
-			return out, err
+			return q.WithoutWindow().Filter(out), err
 		}
 		for _, ev := range resp.Events {
-			out = append(out, schema.Event{
-				Id:        ev.EventID,
-				Name:      ev.EventName,
-				Affected:  firstResourceName(ev.Resources),
-				API:       ev.EventName,
-				Status:    "成功", // CloudTrail surfaces failures via the embedded CloudTrailEvent JSON; default to success here
-				SourceIp:  "",   // SourceIPAddress lives in the embedded JSON blob; left empty in the summary view
-				AccessKey: ev.AccessKeyID,
-				Time:      formatEventTime(ev.EventTime),
-			})
+			out = append(out, toEvent(ev))
 		}
 		if resp.NextToken == "" || len(resp.Events) == 0 {
 			break
 		}
 		nextToken = resp.NextToken
 	}
-	return out, nil
+	return q.WithoutWindow().Filter(out), nil
+}
+
+// lookupAttributes picks the one attribute LookupEvents filters on. A
+// principal is only sent when it is a bare user name or access key ID: the
+// Username attribute does not match ARNs.
+func lookupAttributes(q schema.EventQuery) []api.LookupAttribute {
+	principal := strings.TrimSpace(q.Principal)
+	switch {
+	case q.Name != "" && !strings.ContainsAny(q.Name, "./"):
+		return []api.LookupAttribute{{AttributeKey: "EventName", AttributeValue: q.Name}}
+	case accessKeyPattern.MatchString(principal):
+		return []api.LookupAttribute{{AttributeKey: "AccessKeyId", AttributeValue: principal}}
+	case principal != "" && !strings.ContainsAny(principal, ":/"):
+		return []api.LookupAttribute{{AttributeKey: "Username", AttributeValue: principal}}
+	case q.Resource != "":
+		return []api.LookupAttribute{{AttributeKey: "ResourceName", AttributeValue: q.Resource}}
+	}
+	return nil
 }
 
 // HandleEvents is intentionally not supported — CloudTrail is read-only.
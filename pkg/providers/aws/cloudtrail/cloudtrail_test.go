@@ -8,9 +8,11 @@ import (
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func newTestDriver(t *testing.T, baseURL string) *Driver {
@@ -133,3 +135,102 @@ func TestHandleEventsUnsupported(t *testing.T) {
 		t.Fatalf("expected whitelist mention in error, got %q", err.Error())
 	}
 }
+
+func TestDumpEventsDecodesEmbeddedRecord(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		record := `{"userIdentity":{"type":"AssumedRole","arn":"arn:aws:sts::123456789012:assumed-role/audit/ctk","accessKeyId":"ASIAEXAMPLE"},` +
+			`"sourceIPAddress":"198.51.100.24","userAgent":"aws-cli/2.15.30","errorCode":"AccessDenied","errorMessage":"denied",` +
+			`"requestParameters":{"userName":"alice","tags":[{"key":"k"}]}}`
+		payload, _ := json.Marshal(map[string]any{
+			"Events": []map[string]any{
+				{"EventId": "e1", "EventName": "CreateUser", "EventTime": 1714694400, "CloudTrailEvent": record},
+				{"EventId": "e2", "EventName": "ListUsers", "EventTime": 1714694401, "Username": "bob", "AccessKeyId": "AK2", "CloudTrailEvent": "not-json"},
+			},
+		})
+		_, _ = w.Write(payload)
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(t, server.URL)
+	events, err := driver.DumpEvents(context.Background(), "")
+	if err != nil {
+		t.Fatalf("DumpEvents: %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("expected 2 events, got %d", len(events))
+	}
+	got := events[0]
+	if got.SourceIp != "198.51.100.24" || got.Status != "失败(AccessDenied)" {
+		t.Fatalf("unexpected source/status: %+v", got)
+	}
+	if got.Principal != "arn:aws:sts::123456789012:assumed-role/audit/ctk" || got.PrincipalType != "AssumedRole" {
+		t.Fatalf("unexpected principal: %+v", got)
+	}
+	if got.AccessKey != "ASIAEXAMPLE" || got.UserAgent != "aws-cli/2.15.30" {
+		t.Fatalf("unexpected access key / user agent: %+v", got)
+	}
+	if got.Request != `tags=[{"key":"k"}], userName=alice` {
+		t.Fatalf("unexpected request summary: %q", got.Request)
+	}
+	fallback := events[1]
+	if fallback.Status != "成功" || fallback.Principal != "bob" || fallback.AccessKey != "AK2" || fallback.SourceIp != "" {
+		t.Fatalf("expected header-only fallback for malformed blob, got %+v", fallback)
+	}
+}
+
+func TestQueryEventsSendsLookupAttributeAndFilters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			LookupAttributes []struct {
+				AttributeKey   string
+				AttributeValue string
+			}
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if len(body.LookupAttributes) != 1 || body.LookupAttributes[0].AttributeKey != "EventName" || body.LookupAttributes[0].AttributeValue != "CreateUser" {
+			t.Fatalf("unexpected lookup attributes: %+v", body.LookupAttributes)
+		}
+		_, _ = w.Write([]byte(`{"Events":[
+			{"EventId":"e1","EventName":"CreateUser","EventTime":1714694400,"Username":"alice","AccessKeyId":"AK1"},
+			{"EventId":"e2","EventName":"CreateUser","EventTime":1714694430,"Username":"bob","AccessKeyId":"AK2"}
+		]}`))
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(t, server.URL)
+	events, err := driver.QueryEvents(context.Background(), schema.EventQuery{Name: "CreateUser", Principal: "bob"})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Id != "e2" {
+		t.Fatalf("expected only bob's event, got %+v", events)
+	}
+}
+
+func TestSummarizeRequestTruncatesOnRuneBoundary(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+	}{
+		{name: "ascii", value: strings.Repeat("a", 300)},
+		// After the 7-byte "policy=" the cut at byte 157 ends a 3-byte
+		// rune; one more leading byte moves it into the middle of one.
+		{name: "multi-byte", value: strings.Repeat("策", 100)},
+		{name: "multi-byte offset", value: "x" + strings.Repeat("策", 100)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, _ := json.Marshal(map[string]string{"policy": tt.value})
+			got := summarizeRequest(raw)
+			if !utf8.ValidString(got) {
+				t.Fatalf("summary is not valid UTF-8: %q", got)
+			}
+			if len(got) > maxRequestSummary || !strings.HasSuffix(got, "...") {
+				t.Fatalf("summary = %q (%d bytes)", got, len(got))
+			}
+			if len(got) < maxRequestSummary-utf8.UTFMax {
+				t.Fatalf("summary cut too short: %d bytes", len(got))
+			}
+		})
+	}
+}
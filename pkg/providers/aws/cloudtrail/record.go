@@ -0,0 +1,159 @@
+package cloudtrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// maxRequestSummary caps the flattened requestParameters string so a single
+// verbose call (e.g. PutBucketPolicy with an inline document) cannot blow up
+// the event table.
+const maxRequestSummary = 160
+
+// eventRecord is the subset of the CloudTrail record format carried in the
+// `CloudTrailEvent` JSON blob of a LookupEvents response. Only the fields
+// surfaced through schema.Event are decoded.
+type eventRecord struct {
+	EventName         string          `json:"eventName"`
+	SourceIPAddress   string          `json:"sourceIPAddress"`
+	UserAgent         string          `json:"userAgent"`
+	ErrorCode         string          `json:"errorCode"`
+	ErrorMessage      string          `json:"errorMessage"`
+	RequestParameters json.RawMessage `json:"requestParameters"`
+	UserIdentity      userIdentity    `json:"userIdentity"`
+}
+
+type userIdentity struct {
+	Type        string `json:"type"`
+	PrincipalID string `json:"principalId"`
+	ARN         string `json:"arn"`
+	AccountID   string `json:"accountId"`
+	AccessKeyID string `json:"accessKeyId"`
+	UserName    string `json:"userName"`
+	InvokedBy   string `json:"invokedBy"`
+}
+
+// toEvent merges the LookupEvents header with the decoded CloudTrailEvent
+// blob. A missing or malformed blob degrades to the header-only view rather
+// than failing the whole dump.
+func toEvent(ev api.CloudTrailEvent) schema.Event {
+	out := schema.Event{
+		Id:        ev.EventID,
+		Name:      ev.EventName,
+		Affected:  firstResourceName(ev.Resources),
+		API:       ev.EventName,
+		Status:    "成功",
+		AccessKey: ev.AccessKeyID,
+		Principal: strings.TrimSpace(ev.Username),
+		Time:      formatEventTime(ev.EventTime),
+	}
+	record, ok := decodeRecord(ev.CloudTrailEvent)
+	if !ok {
+		return out
+	}
+	out.Status = recordStatus(record)
+	out.SourceIp = strings.TrimSpace(record.SourceIPAddress)
+	out.UserAgent = strings.TrimSpace(record.UserAgent)
+	out.Request = summarizeRequest(record.RequestParameters)
+	out.PrincipalType = strings.TrimSpace(record.UserIdentity.Type)
+	if principal := recordPrincipal(record.UserIdentity); principal != "" {
+		out.Principal = principal
+	}
+	if out.AccessKey == "" {
+		out.AccessKey = strings.TrimSpace(record.UserIdentity.AccessKeyID)
+	}
+	return out
+}
+
+func decodeRecord(raw string) (eventRecord, bool) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return eventRecord{}, false
+	}
+	var record eventRecord
+	if err := json.Unmarshal([]byte(raw), &record); err != nil {
+		return eventRecord{}, false
+	}
+	return record, true
+}
+
+// recordStatus mirrors the console: a record without errorCode succeeded;
+// otherwise the error code (or message when the code is absent) is kept so
+// AccessDenied probes stand out from generic failures.
+func recordStatus(record eventRecord) string {
+	code := strings.TrimSpace(record.ErrorCode)
+	message := strings.TrimSpace(record.ErrorMessage)
+	switch {
+	case code != "":
+		return "失败(" + code + ")"
+	case message != "":
+		return "失败(" + message + ")"
+	}
+	return "成功"
+}
+
+func recordPrincipal(identity userIdentity) string {
+	if arn := strings.TrimSpace(identity.ARN); arn != "" {
+		return arn
+	}
+	if invokedBy := strings.TrimSpace(identity.InvokedBy); invokedBy != "" {
+		return invokedBy
+	}
+	if name := strings.TrimSpace(identity.UserName); name != "" {
+		return name
+	}
+	return strings.TrimSpace(identity.PrincipalID)
+}
+
+// summarizeRequest flattens requestParameters into `key=value` pairs sorted
+// by key. Nested objects and arrays are kept as compact JSON.
+func summarizeRequest(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var params map[string]any
+	if err := json.Unmarshal(raw, &params); err != nil || len(params) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+formatParam(params[key]))
+	}
+	summary := strings.Join(parts, ", ")
+	if len(summary) > maxRequestSummary {
+		// Back up to a rune start so a multi-byte character is not split.
+		cut := maxRequestSummary - 3
+		for cut > 0 && !utf8.RuneStart(summary[cut]) {
+			cut--
+		}
+		summary = summary[:cut] + "..."
+	}
+	return summary
+}
+
+func formatParam(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return v
+	case bool, float64:
+		return fmt.Sprint(v)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -1,6 +1,11 @@
 package aws
 
-import "testing"
+import (
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/utils"
+)
 
 func TestResolveBootstrapRegion(t *testing.T) {
 	tests := []struct {
@@ -54,3 +59,25 @@ func TestConsoleURLForARN(t *testing.T) {
 		}
 	}
 }
+
+func TestCredentialKeyIncludesRoleChain(t *testing.T) {
+	p := &Provider{}
+	base := map[string]string{utils.AccessKey: "AKID"}
+	direct := p.CredentialKey(base)
+	if direct != "AKID" {
+		t.Fatalf("direct key = %q", direct)
+	}
+	role := p.CredentialKey(map[string]string{utils.AccessKey: "AKID", utils.AWSRoleArn: "arn:aws:iam::111111111111:role/a"})
+	chain := p.CredentialKey(map[string]string{utils.AccessKey: "AKID", utils.AWSRoleArn: "arn:aws:iam::111111111111:role/a, arn:aws:iam::222222222222:role/b"})
+	external := p.CredentialKey(map[string]string{utils.AccessKey: "AKID", utils.AWSRoleArn: "arn:aws:iam::111111111111:role/a", utils.AWSExternalId: "secret-ext"})
+	seen := map[string]bool{}
+	for _, key := range []string{direct, role, chain, external} {
+		if seen[key] {
+			t.Fatalf("duplicate credential key %q", key)
+		}
+		seen[key] = true
+	}
+	if strings.Contains(external, "secret-ext") {
+		t.Errorf("external ID leaked into key %q", external)
+	}
+}
@@ -126,6 +126,7 @@ func (d *Driver) listRegion(ctx context.Context, client *api.Client, region stri
 				DNSName:     instance.PublicDNSName,
 				Public:      ip4 != "",
 				Region:      region,
+				Tags:        tagMap(instance.Tags),
 			}
 			hosts = append(hosts, host)
 		}
@@ -159,6 +160,17 @@ func mergeRegionErrors(base, extra map[string]error) map[string]error {
 	return merged
 }
 
+func tagMap(tags []api.EC2Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[tag.Key] = tag.Value
+	}
+	return out
+}
+
 func pickHostName(tags []api.EC2Tag) string {
 	var fallback string
 	for _, tag := range tags {
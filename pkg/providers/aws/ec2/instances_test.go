@@ -160,13 +160,18 @@ func TestDriverGetResourceMapsInstancesAcrossPagesAndRegions(t *testing.T) {
 
 	hostsByID := make(map[string]string, len(got))
 	regionsByID := make(map[string]string, len(got))
+	tagsByID := make(map[string]map[string]string, len(got))
 	for _, host := range got {
 		hostsByID[host.ID] = host.HostName
 		regionsByID[host.ID] = host.Region
+		tagsByID[host.ID] = host.Tags
 	}
 	if hostsByID["i-sg-1"] != "stack-preferred" {
 		t.Fatalf("unexpected preferred hostname: %+v", got)
 	}
+	if tagsByID["i-sg-1"]["Name"] != "name-fallback" {
+		t.Fatalf("unexpected tags: %+v", tagsByID["i-sg-1"])
+	}
 	if hostsByID["i-sg-2"] != "" || regionsByID["i-sg-2"] != "ap-southeast-1" {
 		t.Fatalf("unexpected second singapore instance: %+v", got)
 	}
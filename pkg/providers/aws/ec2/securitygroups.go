@@ -0,0 +1,164 @@
+package ec2
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// GetSecurityGroups lists security groups per region with their ingress and
+// egress rules. Attached resources come from the network interfaces that
+// reference each group: the owning instance ID, or the ENI ID for interfaces
+// owned by managed services (ELB, RDS, Lambda ...).
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	d.partialErr = nil
+	logger.Info("List EC2 security groups ...")
+	client, err := d.requireClient()
+	if err != nil {
+		return list, err
+	}
+	regions, err := d.GetEC2Regions(ctx)
+	if err != nil {
+		logger.Error("GetEC2Regions failed.")
+		return list, err
+	}
+
+	tracker := processbar.NewRegionTracker()
+	defer tracker.Finish()
+	got, regionErrs := regionrun.ForEach(ctx, regions, 0, tracker, func(ctx context.Context, region string) ([]schema.SecurityGroup, error) {
+		return d.listSecurityGroups(ctx, client, region)
+	})
+	list = append(list, got...)
+	d.partialErr = regionrun.Wrap(regionErrs)
+	return list, nil
+}
+
+func (d *Driver) listSecurityGroups(ctx context.Context, client *api.Client, region string) ([]schema.SecurityGroup, error) {
+	groups, err := paginate.Fetch[api.EC2SecurityGroup, string](ctx, func(ctx context.Context, token string) (paginate.Page[api.EC2SecurityGroup, string], error) {
+		resp, err := client.DescribeSecurityGroups(ctx, region, token, 1000)
+		if err != nil {
+			return paginate.Page[api.EC2SecurityGroup, string]{}, err
+		}
+		return paginate.Page[api.EC2SecurityGroup, string]{
+			Items: resp.SecurityGroups,
+			Next:  resp.NextToken,
+			Done:  resp.NextToken == "",
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	attached, err := d.groupAttachments(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		item := schema.SecurityGroup{
+			GroupID:   group.GroupID,
+			GroupName: group.GroupName,
+			VpcID:     group.VpcID,
+			Region:    region,
+			Attached:  attached[group.GroupID],
+		}
+		item.Rules = append(item.Rules, convertPermissions("ingress", group.Ingress)...)
+		item.Rules = append(item.Rules, convertPermissions("egress", group.Egress)...)
+		item.FlagExposure()
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (d *Driver) groupAttachments(ctx context.Context, client *api.Client, region string) (map[string][]string, error) {
+	enis, err := paginate.Fetch[api.EC2NetworkInterface, string](ctx, func(ctx context.Context, token string) (paginate.Page[api.EC2NetworkInterface, string], error) {
+		resp, err := client.DescribeNetworkInterfaces(ctx, region, token, 1000)
+		if err != nil {
+			return paginate.Page[api.EC2NetworkInterface, string]{}, err
+		}
+		return paginate.Page[api.EC2NetworkInterface, string]{
+			Items: resp.NetworkInterfaces,
+			Next:  resp.NextToken,
+			Done:  resp.NextToken == "",
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]map[string]struct{})
+	for _, eni := range enis {
+		target := eni.InstanceID
+		if target == "" {
+			target = eni.NetworkInterfaceID
+		}
+		for _, groupID := range eni.GroupIDs {
+			if seen[groupID] == nil {
+				seen[groupID] = make(map[string]struct{})
+			}
+			seen[groupID][target] = struct{}{}
+		}
+	}
+	out := make(map[string][]string, len(seen))
+	for groupID, targets := range seen {
+		for target := range targets {
+			out[groupID] = append(out[groupID], target)
+		}
+		sort.Strings(out[groupID])
+	}
+	return out, nil
+}
+
+// convertPermissions expands every peer of an ipPermissions entry into its
+// own rule, so a permission open to both a CIDR and a peer group yields two
+// rows.
+func convertPermissions(direction string, perms []api.EC2IPPermission) []schema.FirewallRule {
+	var rules []schema.FirewallRule
+	for _, perm := range perms {
+		protocol := normalizeProtocol(perm.IPProtocol)
+		ports := "all"
+		if protocol != "all" {
+			ports = schema.PortRange(perm.FromPort, perm.ToPort)
+		}
+		add := func(peers []api.EC2PermissionPeer) {
+			for _, peer := range peers {
+				rules = append(rules, schema.FirewallRule{
+					Direction:   direction,
+					Action:      "allow",
+					Protocol:    protocol,
+					PortRange:   ports,
+					CIDR:        peer.Value,
+					Description: peer.Description,
+				})
+			}
+		}
+		add(perm.CIDRs)
+		add(perm.GroupPeers)
+		add(perm.PrefixListPeer)
+	}
+	return rules
+}
+
+func normalizeProtocol(protocol string) string {
+	switch strings.ToLower(strings.TrimSpace(protocol)) {
+	case "-1", "all", "":
+		return "all"
+	case "6", "tcp":
+		return "tcp"
+	case "17", "udp":
+		return "udp"
+	case "1", "icmp":
+		return "icmp"
+	case "58", "icmpv6":
+		return "icmpv6"
+	default:
+		return strings.ToLower(strings.TrimSpace(protocol))
+	}
+}
@@ -0,0 +1,101 @@
+package ec2
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestDriverGetSecurityGroupsMapsRulesAndAttachments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := mustParseEC2BodyValues(t, r)
+		switch values.Get("Action") {
+		case "DescribeSecurityGroups":
+			_, _ = w.Write([]byte(`
+<DescribeSecurityGroupsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <securityGroupInfo>
+    <item>
+      <groupId>sg-1</groupId>
+      <groupName>web</groupName>
+      <vpcId>vpc-1</vpcId>
+      <ipPermissions>
+        <item>
+          <ipProtocol>tcp</ipProtocol>
+          <fromPort>22</fromPort>
+          <toPort>22</toPort>
+          <ipRanges><item><cidrIp>0.0.0.0/0</cidrIp><description>ssh</description></item></ipRanges>
+          <groups><item><groupId>sg-2</groupId></item></groups>
+        </item>
+        <item>
+          <ipProtocol>6</ipProtocol>
+          <fromPort>8000</fromPort>
+          <toPort>8080</toPort>
+          <ipv6Ranges><item><cidrIpv6>::/0</cidrIpv6></item></ipv6Ranges>
+        </item>
+      </ipPermissions>
+      <ipPermissionsEgress>
+        <item>
+          <ipProtocol>-1</ipProtocol>
+          <ipRanges><item><cidrIp>0.0.0.0/0</cidrIp></item></ipRanges>
+        </item>
+      </ipPermissionsEgress>
+    </item>
+  </securityGroupInfo>
+</DescribeSecurityGroupsResponse>`))
+		case "DescribeNetworkInterfaces":
+			_, _ = w.Write([]byte(`
+<DescribeNetworkInterfacesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <networkInterfaceSet>
+    <item>
+      <networkInterfaceId>eni-1</networkInterfaceId>
+      <attachment><instanceId>i-1</instanceId></attachment>
+      <groupSet><item><groupId>sg-1</groupId></item></groupSet>
+    </item>
+    <item>
+      <networkInterfaceId>eni-2</networkInterfaceId>
+      <groupSet><item><groupId>sg-1</groupId></item></groupSet>
+    </item>
+  </networkInterfaceSet>
+</DescribeNetworkInterfacesResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", values.Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := &Driver{
+		Client:        newEC2DriverTestClient(server.URL),
+		Region:        "us-east-1",
+		DefaultRegion: "us-east-1",
+	}
+	got, err := driver.GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if err := driver.PartialError(); err != nil {
+		t.Fatalf("unexpected partial error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected one group, got %+v", got)
+	}
+	group := got[0]
+	if group.GroupID != "sg-1" || group.VpcID != "vpc-1" || group.Region != "us-east-1" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if !reflect.DeepEqual(group.Attached, []string{"eni-2", "i-1"}) {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	want := []schema.FirewallRule{
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "22", CIDR: "0.0.0.0/0", Description: "ssh", Exposed: true},
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "22", CIDR: "sg-2"},
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "8000-8080", CIDR: "::/0"},
+		{Direction: "egress", Action: "allow", Protocol: "all", PortRange: "all", CIDR: "0.0.0.0/0"},
+	}
+	if !reflect.DeepEqual(group.Rules, want) {
+		t.Fatalf("unexpected rules:\n got %+v\nwant %+v", group.Rules, want)
+	}
+}
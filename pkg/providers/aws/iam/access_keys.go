@@ -19,18 +19,7 @@ func (d *Driver) ListAccessKeys(ctx context.Context, userName string) ([]schema.
 	if err != nil {
 		return nil, err
 	}
-	region := d.requestRegion()
-	keys, err := paginate.Fetch[api.IAMAccessKey, string](ctx, func(ctx context.Context, marker string) (paginate.Page[api.IAMAccessKey, string], error) {
-		resp, err := client.ListAccessKeys(ctx, region, userName, marker)
-		if err != nil {
-			return paginate.Page[api.IAMAccessKey, string]{}, err
-		}
-		return paginate.Page[api.IAMAccessKey, string]{
-			Items: resp.AccessKeys,
-			Next:  resp.Marker,
-			Done:  !resp.IsTruncated || strings.TrimSpace(resp.Marker) == "",
-		}, nil
-	})
+	keys, err := listAccessKeys(ctx, client, d.requestRegion(), userName)
 	if err != nil {
 		return nil, err
 	}
@@ -82,3 +71,17 @@ func (d *Driver) DeleteAccessKey(ctx context.Context, userName, accessKeyID stri
 	}
 	return client.DeleteAccessKey(ctx, d.requestRegion(), userName, accessKeyID)
 }
+
+func listAccessKeys(ctx context.Context, client *api.Client, region, userName string) ([]api.IAMAccessKey, error) {
+	return paginate.Fetch[api.IAMAccessKey, string](ctx, func(ctx context.Context, marker string) (paginate.Page[api.IAMAccessKey, string], error) {
+		resp, err := client.ListAccessKeys(ctx, region, userName, marker)
+		if err != nil {
+			return paginate.Page[api.IAMAccessKey, string]{}, err
+		}
+		return paginate.Page[api.IAMAccessKey, string]{
+			Items: resp.AccessKeys,
+			Next:  resp.Marker,
+			Done:  !resp.IsTruncated || strings.TrimSpace(resp.Marker) == "",
+		}, nil
+	})
+}
@@ -0,0 +1,112 @@
+package iam
+
+import (
+	"context"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// Posture reads the credential state of every IAM user: the login profile,
+// MFA devices, access keys and GetAccessKeyLastUsed for each key. A per-user
+// call that fails is recorded as a gap instead of failing the whole report.
+func (d *Driver) Posture(ctx context.Context) ([]schema.UserPosture, error) {
+	client, err := d.requireClient()
+	if err != nil {
+		return nil, err
+	}
+	region := d.requestRegion()
+
+	users, err := paginate.Fetch[api.IAMUser, string](ctx, func(ctx context.Context, marker string) (paginate.Page[api.IAMUser, string], error) {
+		resp, err := client.ListUsers(ctx, region, marker)
+		if err != nil {
+			return paginate.Page[api.IAMUser, string]{}, err
+		}
+		return paginate.Page[api.IAMUser, string]{
+			Items: resp.Users,
+			Next:  resp.Marker,
+			Done:  !resp.IsTruncated || strings.TrimSpace(resp.Marker) == "",
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.UserPosture, 0, len(users))
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		posture := schema.UserPosture{
+			UserName: user.UserName,
+			UserID:   user.UserID,
+		}
+		if user.CreateDate != nil {
+			posture.CreateTime = *user.CreateDate
+		}
+		if user.PasswordLastUsed != nil {
+			posture.LastLogin = *user.PasswordLastUsed
+		}
+
+		profile, err := client.GetLoginProfile(ctx, region, user.UserName)
+		switch {
+		case err == nil:
+			posture.Password = true
+			if profile.CreateDate != nil {
+				posture.PasswordChanged = *profile.CreateDate
+			}
+		case isNoSuchEntity(err):
+		default:
+			posture.Password = user.PasswordLastUsed != nil
+			posture.Gaps = append(posture.Gaps, schema.PostureGapPasswordAge)
+		}
+
+		if devices, err := listMFADevices(ctx, client, region, user.UserName); err != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapMFA)
+		} else {
+			posture.MFA = len(devices) > 0
+		}
+
+		keys, err := listAccessKeys(ctx, client, region, user.UserName)
+		if err != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapAccessKeys)
+		}
+		for _, key := range keys {
+			keyPosture := schema.AccessKeyPosture{
+				ID:     key.AccessKeyID,
+				Active: key.Status == "Active",
+			}
+			if key.CreateDate != nil {
+				keyPosture.CreateTime = *key.CreateDate
+			}
+			lastUsed, err := client.GetAccessKeyLastUsed(ctx, region, key.AccessKeyID)
+			if err != nil {
+				if !posture.HasGap(schema.PostureGapKeyLastUsed) {
+					posture.Gaps = append(posture.Gaps, schema.PostureGapKeyLastUsed)
+				}
+			} else if lastUsed.LastUsedDate != nil {
+				keyPosture.LastUsed = *lastUsed.LastUsedDate
+				keyPosture.LastUsedService = lastUsed.ServiceName
+			}
+			posture.AccessKeys = append(posture.AccessKeys, keyPosture)
+		}
+		out = append(out, posture)
+	}
+	return out, nil
+}
+
+func listMFADevices(ctx context.Context, client *api.Client, region, userName string) ([]api.IAMMFADevice, error) {
+	return paginate.Fetch[api.IAMMFADevice, string](ctx, func(ctx context.Context, marker string) (paginate.Page[api.IAMMFADevice, string], error) {
+		resp, err := client.ListMFADevices(ctx, region, userName, marker)
+		if err != nil {
+			return paginate.Page[api.IAMMFADevice, string]{}, err
+		}
+		return paginate.Page[api.IAMMFADevice, string]{
+			Items: resp.Devices,
+			Next:  resp.Marker,
+			Done:  !resp.IsTruncated || strings.TrimSpace(resp.Marker) == "",
+		}, nil
+	})
+}
@@ -0,0 +1,146 @@
+package iam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestDriverPostureMapsCredentialsAndRecordsGaps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		values := mustParseIAMBodyValues(t, r)
+		switch values.Get("Action") {
+		case "ListUsers":
+			_, _ = w.Write([]byte(`
+<ListUsersResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <ListUsersResult>
+    <Users>
+      <member>
+        <UserName>alice</UserName>
+        <UserId>AIDAALICE</UserId>
+        <CreateDate>2026-01-18T10:00:00Z</CreateDate>
+        <PasswordLastUsed>2026-04-18T11:00:00Z</PasswordLastUsed>
+      </member>
+      <member>
+        <UserName>bot</UserName>
+        <UserId>AIDABOT</UserId>
+        <CreateDate>2026-02-18T09:00:00Z</CreateDate>
+      </member>
+    </Users>
+    <IsTruncated>false</IsTruncated>
+  </ListUsersResult>
+</ListUsersResponse>`))
+		case "GetLoginProfile":
+			if values.Get("UserName") == "bot" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchEntity</Code><Message>login profile not found</Message></Error></ErrorResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`
+<GetLoginProfileResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <GetLoginProfileResult>
+    <LoginProfile>
+      <CreateDate>2026-01-19T12:00:00Z</CreateDate>
+    </LoginProfile>
+  </GetLoginProfileResult>
+</GetLoginProfileResponse>`))
+		case "ListMFADevices":
+			if values.Get("UserName") != "alice" {
+				_, _ = w.Write([]byte(`<ListMFADevicesResponse><ListMFADevicesResult><MFADevices></MFADevices><IsTruncated>false</IsTruncated></ListMFADevicesResult></ListMFADevicesResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`
+<ListMFADevicesResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <ListMFADevicesResult>
+    <MFADevices>
+      <member>
+        <UserName>alice</UserName>
+        <SerialNumber>arn:aws:iam::123456789012:mfa/alice</SerialNumber>
+        <EnableDate>2026-01-19T12:30:00Z</EnableDate>
+      </member>
+    </MFADevices>
+    <IsTruncated>false</IsTruncated>
+  </ListMFADevicesResult>
+</ListMFADevicesResponse>`))
+		case "ListAccessKeys":
+			if values.Get("UserName") != "bot" {
+				_, _ = w.Write([]byte(`<ListAccessKeysResponse><ListAccessKeysResult><AccessKeyMetadata></AccessKeyMetadata><IsTruncated>false</IsTruncated></ListAccessKeysResult></ListAccessKeysResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`
+<ListAccessKeysResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <ListAccessKeysResult>
+    <AccessKeyMetadata>
+      <member>
+        <AccessKeyId>AKIABOT1</AccessKeyId>
+        <UserName>bot</UserName>
+        <Status>Active</Status>
+        <CreateDate>2026-02-18T09:05:00Z</CreateDate>
+      </member>
+      <member>
+        <AccessKeyId>AKIABOT2</AccessKeyId>
+        <UserName>bot</UserName>
+        <Status>Inactive</Status>
+        <CreateDate>2026-02-18T09:06:00Z</CreateDate>
+      </member>
+    </AccessKeyMetadata>
+    <IsTruncated>false</IsTruncated>
+  </ListAccessKeysResult>
+</ListAccessKeysResponse>`))
+		case "GetAccessKeyLastUsed":
+			if values.Get("AccessKeyId") == "AKIABOT2" {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>AccessDenied</Code><Message>denied</Message></Error></ErrorResponse>`))
+				return
+			}
+			_, _ = w.Write([]byte(`
+<GetAccessKeyLastUsedResponse xmlns="https://iam.amazonaws.com/doc/2010-05-08/">
+  <GetAccessKeyLastUsedResult>
+    <AccessKeyLastUsed>
+      <Region>us-east-1</Region>
+      <LastUsedDate>2026-04-10T08:00:00Z</LastUsedDate>
+      <ServiceName>s3</ServiceName>
+    </AccessKeyLastUsed>
+    <UserName>bot</UserName>
+  </GetAccessKeyLastUsedResult>
+</GetAccessKeyLastUsedResponse>`))
+		default:
+			t.Fatalf("unexpected action: %s", values.Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := &Driver{
+		Client: newIAMDriverTestClient(server.URL),
+		Region: "us-east-1",
+	}
+	got, err := driver.Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("unexpected user count: %d", len(got))
+	}
+
+	alice := got[0]
+	if !alice.Password || !alice.MFA || alice.PasswordChanged.IsZero() || alice.LastLogin.IsZero() || len(alice.Gaps) != 0 {
+		t.Fatalf("unexpected alice posture: %+v", alice)
+	}
+
+	bot := got[1]
+	if bot.Password || bot.MFA || len(bot.AccessKeys) != 2 {
+		t.Fatalf("unexpected bot posture: %+v", bot)
+	}
+	if key := bot.AccessKeys[0]; !key.Active || key.LastUsedService != "s3" || key.LastUsed.IsZero() {
+		t.Fatalf("unexpected active key: %+v", key)
+	}
+	if key := bot.AccessKeys[1]; key.Active || !key.LastUsed.IsZero() {
+		t.Fatalf("unexpected inactive key: %+v", key)
+	}
+	if !bot.HasGap(schema.PostureGapKeyLastUsed) || len(bot.Gaps) != 1 {
+		t.Fatalf("unexpected bot gaps: %v", bot.Gaps)
+	}
+}
@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	_api "github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	_auth "github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+	_organizations "github.com/404tk/cloudtoolkit/pkg/providers/aws/organizations"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// orgConcurrency bounds how many member accounts enumerate at once. Each
+// account fans out per region on its own, so this stays well below
+// regionrun.DefaultConcurrency.
+const orgConcurrency = 3
+
+// orgScope prefixes every error produced by the organization fan-out, so a
+// failed member reads as `org/<account-id>` and a failed category inside a
+// member as `org/<account-id>/<category>`.
+const orgScope = "org"
+
+type accountResources struct {
+	accountID string
+	resources schema.Resources
+}
+
+// organizationResources lists the organization's ACTIVE accounts, assumes
+// orgRole into each member (the caller's own account reuses the current
+// credential) and runs the regular collector per account. An account whose
+// role cannot be assumed is reported as a partial error keyed by account ID;
+// the other accounts still contribute to the merged inventory.
+func (p *Provider) organizationResources(ctx context.Context) (schema.Resources, error) {
+	list := schema.NewResources()
+	list.Provider = p.Name()
+
+	caller, err := p.apiClient.GetCallerIdentity(ctx, p.defaultRegion)
+	if err != nil {
+		list.AddError(orgScope, err)
+		return list, list.Err()
+	}
+	logger.Info("List Organizations accounts ...")
+	accounts, err := (&_organizations.Driver{Client: p.apiClient, DefaultRegion: p.defaultRegion}).ListAccounts(ctx)
+	if err != nil {
+		list.AddError(orgScope, err)
+		return list, list.Err()
+	}
+	ids := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		ids = append(ids, account.ID)
+	}
+
+	names := env.From(ctx).Cloudlist
+	got, accountErrs := regionrun.ForEach(ctx, ids, orgConcurrency, nil, func(ctx context.Context, accountID string) ([]accountResources, error) {
+		client := p.apiClient
+		if accountID != caller.Account {
+			client = p.memberClient(accountID)
+			if _, err := client.GetCallerIdentity(ctx, p.defaultRegion); err != nil {
+				return nil, err
+			}
+		}
+		logger.Info(fmt.Sprintf("Enumerate account %s ...", accountID))
+		resources, _ := p.collector(client, false).Collect(ctx, names)
+		resources.SetAccount(accountID)
+		return []accountResources{{accountID: accountID, resources: resources}}, nil
+	})
+
+	sort.Slice(got, func(i, j int) bool { return got[i].accountID < got[j].accountID })
+	for _, item := range got {
+		list.Assets = append(list.Assets, item.resources.Assets...)
+		for _, resErr := range item.resources.Errors {
+			list.Errors = append(list.Errors, schema.ResourceError{
+				Scope:   orgScope + "/" + item.accountID + "/" + resErr.Scope,
+				Message: resErr.Message,
+			})
+		}
+	}
+	list.AddError(orgScope, regionrun.Wrap(accountErrs))
+	return list, list.Err()
+}
+
+// memberClient returns a client that assumes orgRole in accountID, chained
+// from whatever credential the provider itself signs with.
+func (p *Provider) memberClient(accountID string) *_api.Client {
+	return p.apiClient.AssumeRoleChain(p.defaultRegion, []_auth.RoleHop{{
+		RoleARN:     memberRoleARN(p.defaultRegion, accountID, p.orgRole),
+		SessionName: p.sessionName,
+	}})
+}
+
+func memberRoleARN(region, accountID, role string) string {
+	partition := "aws"
+	if strings.HasPrefix(region, "cn-") {
+		partition = "aws-cn"
+	}
+	return "arn:" + partition + ":iam::" + accountID + ":role/" + strings.TrimPrefix(role, "/")
+}
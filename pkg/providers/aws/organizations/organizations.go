@@ -0,0 +1,59 @@
+package organizations
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+)
+
+const (
+	accountStatusActive = "ACTIVE"
+	defaultPageSize     = 20
+)
+
+// Account is one active member of the organization.
+type Account struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+// Driver wraps AWS Organizations ListAccounts for the multi-account
+// cloudlist fan-out.
+type Driver struct {
+	Client        *api.Client
+	DefaultRegion string
+}
+
+// ListAccounts pages through every account in the organization and keeps the
+// ACTIVE ones; suspended or pending-closure accounts cannot be assumed into.
+func (d *Driver) ListAccounts(ctx context.Context) ([]Account, error) {
+	if d == nil || d.Client == nil {
+		return nil, errors.New("aws organizations: nil api client")
+	}
+	items, err := paginate.Fetch[api.OrganizationAccount, string](ctx, func(ctx context.Context, token string) (paginate.Page[api.OrganizationAccount, string], error) {
+		resp, err := d.Client.OrganizationsListAccounts(ctx, d.DefaultRegion, defaultPageSize, token)
+		if err != nil {
+			return paginate.Page[api.OrganizationAccount, string]{}, err
+		}
+		return paginate.Page[api.OrganizationAccount, string]{
+			Items: resp.Accounts,
+			Next:  resp.NextToken,
+			Done:  resp.NextToken == "",
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([]Account, 0, len(items))
+	for _, item := range items {
+		if !strings.EqualFold(item.Status, accountStatusActive) || strings.TrimSpace(item.ID) == "" {
+			continue
+		}
+		out = append(out, Account{ID: item.ID, Name: item.Name, Email: item.Email})
+	}
+	return out, nil
+}
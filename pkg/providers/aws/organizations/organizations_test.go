@@ -0,0 +1,54 @@
+package organizations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+)
+
+func TestListAccountsPaginatesAndSkipsInactive(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Amz-Target"); got != "AWSOrganizationsV20161128.ListAccounts" {
+			t.Fatalf("unexpected target: %s", got)
+		}
+		if !strings.Contains(r.Header.Get("Authorization"), "/us-east-1/organizations/aws4_request") {
+			t.Fatalf("expected us-east-1 organizations scope, got %s", r.Header.Get("Authorization"))
+		}
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		calls++
+		if calls == 1 {
+			_, _ = w.Write([]byte(`{"NextToken":"p2","Accounts":[{"Id":"111111111111","Name":"mgmt","Status":"ACTIVE"},{"Id":"222222222222","Status":"SUSPENDED"}]}`))
+			return
+		}
+		if body["NextToken"] != "p2" {
+			t.Fatalf("expected NextToken=p2, got %v", body)
+		}
+		_, _ = w.Write([]byte(`{"Accounts":[{"Id":"333333333333","Name":"member","Status":"ACTIVE"}]}`))
+	}))
+	defer server.Close()
+
+	client := api.NewClient(
+		auth.New("AKID", "SECRET", ""),
+		api.WithBaseURL(server.URL),
+		api.WithClock(func() time.Time { return time.Date(2026, 4, 22, 12, 0, 0, 0, time.UTC) }),
+	)
+	accounts, err := (&Driver{Client: client, DefaultRegion: "eu-west-1"}).ListAccounts(context.Background())
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if calls != 2 || len(accounts) != 2 {
+		t.Fatalf("expected 2 calls / 2 active accounts, got %d / %+v", calls, accounts)
+	}
+	if accounts[0].ID != "111111111111" || accounts[1].Name != "member" {
+		t.Fatalf("unexpected accounts: %+v", accounts)
+	}
+}
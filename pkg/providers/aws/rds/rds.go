@@ -29,7 +29,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 	if d == nil || d.Client == nil {
 		return schema.DatabaseActionResult{}, errors.New("aws rds: nil api client")
 	}
-	configUser, password, err := parseRDSAccount()
+	configUser, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -87,8 +87,8 @@ func (d *Driver) requestRegion() string {
 	return region
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
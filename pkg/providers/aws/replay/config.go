@@ -5,6 +5,8 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws"
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const (
@@ -13,10 +15,16 @@ const (
 )
 
 func ClientConfig() aws.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every aws client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) aws.ClientConfig {
+	httpClient = telemetry.WrapClient("aws", httpClient)
 	return aws.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions: []api.Option{api.WithHTTPClient(httpClient)},
 	}
 }
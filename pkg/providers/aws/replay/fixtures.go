@@ -96,6 +96,7 @@ type iamUserFixture struct {
 	CreateDate     string
 	LastLoginDate  string
 	HasLogin       bool
+	MFASerial      string
 	AttachedPolicy []iamPolicyFixture
 }
 
@@ -133,6 +134,19 @@ func seedAWSAccessKeys() map[string][]iamAccessKeyFixture {
 	return out
 }
 
+// iamKeyLastUsedFixture is what GetAccessKeyLastUsed reports for a seeded
+// key; keys missing from demoAccessKeyLastUsed were never used.
+type iamKeyLastUsedFixture struct {
+	Date    string
+	Service string
+	Region  string
+}
+
+var demoAccessKeyLastUsed = map[string]iamKeyLastUsedFixture{
+	"AKIAIOSFODNN7EXAMPLE001": {Date: "2026-04-22T09:40:00Z", Service: "ec2", Region: "us-east-1"},
+	"AKIAIOSFODNN7EXAMPLE003": {Date: "2026-03-18T02:15:00Z", Service: "s3", Region: "us-east-1"},
+}
+
 var demoIAMUsers = []iamUserFixture{
 	{
 		UserName:      "ctk-demo-admin",
@@ -141,6 +155,7 @@ var demoIAMUsers = []iamUserFixture{
 		CreateDate:    "2026-01-12T08:00:00Z",
 		LastLoginDate: "2026-04-22T09:21:00Z",
 		HasLogin:      true,
+		MFASerial:     "arn:aws:iam::" + demoAccountID + ":mfa/ctk-demo-admin",
 		AttachedPolicy: []iamPolicyFixture{
 			{Name: "AdministratorAccess", Arn: "arn:aws:iam::aws:policy/AdministratorAccess"},
 		},
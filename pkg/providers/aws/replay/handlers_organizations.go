@@ -0,0 +1,52 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+const (
+	demoOrgRole             = "OrganizationAccountAccessRole"
+	demoLockedAccountID     = "345678901234"
+	demoSuspendedAccountID  = "456789012345"
+	demoOrganizationBaseArn = "arn:aws:organizations::" + demoAccountID + ":account/o-ctkdemo0001/"
+)
+
+// demoOrganizationAccounts models a small organization: the management
+// account, one member that trusts the management account through the default
+// OrganizationAccountAccessRole, one member whose role was removed (so
+// AssumeRole fails) and one suspended account that ListAccounts still
+// returns but cloudlist must skip.
+var demoOrganizationAccounts = []api.OrganizationAccount{
+	{ID: demoAccountID, Name: demoAccountAlias, Email: "aws-mgmt@ctk.example", Status: "ACTIVE"},
+	{ID: demoMemberAccountID, Name: "ctk-member", Email: "aws-member@ctk.example", Status: "ACTIVE"},
+	{ID: demoLockedAccountID, Name: "ctk-locked", Email: "aws-locked@ctk.example", Status: "ACTIVE"},
+	{ID: demoSuspendedAccountID, Name: "ctk-closed", Email: "aws-closed@ctk.example", Status: "SUSPENDED"},
+}
+
+func (t *transport) handleOrganizations(req *http.Request, _ []byte) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return apiErrorResponse(req, http.StatusMethodNotAllowed, "InvalidAction", "organizations replay expects POST"), nil
+	}
+	target := strings.TrimSpace(req.Header.Get("X-Amz-Target"))
+	if !strings.HasSuffix(target, ".ListAccounts") {
+		return apiErrorResponse(req, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("unsupported organizations target: %s", target)), nil
+	}
+	if session, ok := t.sessionFor(req); ok && session.AccountID != demoAccountID {
+		return apiErrorResponse(req, http.StatusBadRequest, "AWSOrganizationsNotInUseException", "Your account is not a member of an organization."), nil
+	}
+	resp := api.ListAccountsOutput{}
+	for _, account := range demoOrganizationAccounts {
+		account.Arn = demoOrganizationBaseArn + account.ID
+		resp.Accounts = append(resp.Accounts, account)
+	}
+	return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+}
+
+func isOrganizationsHost(host string) bool {
+	return strings.HasPrefix(host, "organizations.")
+}
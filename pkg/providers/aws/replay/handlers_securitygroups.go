@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+type securityGroupFixture struct {
+	Region  string
+	Wire    describeSGWire
+	Members []string
+}
+
+// demoSecurityGroups pairs with demoEC2Hosts: the bastion has SSH open to
+// the internet and the edge host exposes RDP, so a replayed cloudlist shows
+// both the flagged and the benign cases.
+var demoSecurityGroups = []securityGroupFixture{
+	{
+		Region: "us-east-1",
+		Wire: describeSGWire{
+			GroupID:     "sg-0ctkbastion0001",
+			GroupName:   "ctk-demo-bastion",
+			Description: "bastion ingress",
+			VpcID:       "vpc-0ctkdemo0001",
+			Ingress: []sgPermissionWire{
+				{IPProtocol: "tcp", FromPort: "22", ToPort: "22", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0", Description: "ssh from anywhere"}}},
+			},
+			Egress: []sgPermissionWire{
+				{IPProtocol: "-1", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0"}}},
+			},
+		},
+		Members: []string{"i-0a1b2c3d4e5f60001"},
+	},
+	{
+		Region: "us-east-1",
+		Wire: describeSGWire{
+			GroupID:     "sg-0ctkapp00000002",
+			GroupName:   "ctk-demo-app",
+			Description: "app tier",
+			VpcID:       "vpc-0ctkdemo0001",
+			Ingress: []sgPermissionWire{
+				{IPProtocol: "tcp", FromPort: "8080", ToPort: "8080", Groups: []sgGroupPairWire{{GroupID: "sg-0ctkbastion0001"}}},
+				{IPProtocol: "tcp", FromPort: "5432", ToPort: "5432", IPRanges: []sgIPRangeWire{{CIDRIP: "10.0.0.0/16"}}},
+			},
+			Egress: []sgPermissionWire{
+				{IPProtocol: "-1", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0"}}},
+			},
+		},
+		Members: []string{"i-0a1b2c3d4e5f60002"},
+	},
+	{
+		Region: "us-west-2",
+		Wire: describeSGWire{
+			GroupID:     "sg-0ctkedge0000101",
+			GroupName:   "ctk-demo-edge",
+			Description: "edge web and admin",
+			VpcID:       "vpc-0ctkdemo0101",
+			Ingress: []sgPermissionWire{
+				{IPProtocol: "tcp", FromPort: "443", ToPort: "443", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0"}}, IPv6Ranges: []sgIPRangeWire{{CIDRIPv6: "::/0"}}},
+				{IPProtocol: "tcp", FromPort: "3389", ToPort: "3389", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0", Description: "temporary rdp"}}},
+			},
+			Egress: []sgPermissionWire{
+				{IPProtocol: "-1", IPRanges: []sgIPRangeWire{{CIDRIP: "0.0.0.0/0"}}},
+			},
+		},
+		Members: []string{"i-0a1b2c3d4e5f60101", "eni-0ctkelb00000101"},
+	},
+}
+
+func (t *transport) handleDescribeSecurityGroups(req *http.Request, region string) (*http.Response, error) {
+	resp := ec2DescribeSecurityGroupsResponse{}
+	for _, group := range demoSecurityGroups {
+		if group.Region == region {
+			resp.SecurityGroups = append(resp.SecurityGroups, group.Wire)
+		}
+	}
+	return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+}
+
+// handleDescribeNetworkInterfaces derives one ENI per group member; members
+// that are not instance IDs stand in for service-owned interfaces.
+func (t *transport) handleDescribeNetworkInterfaces(req *http.Request, region string) (*http.Response, error) {
+	resp := ec2DescribeNetworkInterfacesResponse{}
+	for _, group := range demoSecurityGroups {
+		if group.Region != region {
+			continue
+		}
+		for _, member := range group.Members {
+			eni := ec2NetworkInterfaceWire{
+				Groups: []sgGroupPairWire{{GroupID: group.Wire.GroupID}},
+			}
+			if strings.HasPrefix(member, "i-") {
+				eni.NetworkInterfaceID = "eni-" + strings.TrimPrefix(member, "i-")
+				eni.Attachment.InstanceID = member
+			} else {
+				eni.NetworkInterfaceID = member
+				eni.Description = "ELB app/ctk-demo-edge"
+			}
+			resp.Interfaces = append(resp.Interfaces, eni)
+		}
+	}
+	return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+}
+
+type ec2DescribeSecurityGroupsResponse struct {
+	XMLName        xml.Name         `xml:"DescribeSecurityGroupsResponse"`
+	SecurityGroups []describeSGWire `xml:"securityGroupInfo>item"`
+}
+
+type describeSGWire struct {
+	GroupID     string             `xml:"groupId"`
+	GroupName   string             `xml:"groupName"`
+	Description string             `xml:"groupDescription"`
+	VpcID       string             `xml:"vpcId"`
+	Ingress     []sgPermissionWire `xml:"ipPermissions>item"`
+	Egress      []sgPermissionWire `xml:"ipPermissionsEgress>item"`
+}
+
+type sgPermissionWire struct {
+	IPProtocol string            `xml:"ipProtocol"`
+	FromPort   string            `xml:"fromPort,omitempty"`
+	ToPort     string            `xml:"toPort,omitempty"`
+	Groups     []sgGroupPairWire `xml:"groups>item"`
+	IPRanges   []sgIPRangeWire   `xml:"ipRanges>item"`
+	IPv6Ranges []sgIPRangeWire   `xml:"ipv6Ranges>item"`
+}
+
+type sgGroupPairWire struct {
+	GroupID string `xml:"groupId"`
+}
+
+type sgIPRangeWire struct {
+	CIDRIP      string `xml:"cidrIp,omitempty"`
+	CIDRIPv6    string `xml:"cidrIpv6,omitempty"`
+	Description string `xml:"description,omitempty"`
+}
+
+type ec2DescribeNetworkInterfacesResponse struct {
+	XMLName    xml.Name                  `xml:"DescribeNetworkInterfacesResponse"`
+	Interfaces []ec2NetworkInterfaceWire `xml:"networkInterfaceSet>item"`
+}
+
+type ec2NetworkInterfaceWire struct {
+	NetworkInterfaceID string            `xml:"networkInterfaceId"`
+	Description        string            `xml:"description,omitempty"`
+	Attachment         ec2AttachmentWire `xml:"attachment"`
+	Groups             []sgGroupPairWire `xml:"groupSet>item"`
+}
+
+type ec2AttachmentWire struct {
+	InstanceID string `xml:"instanceId,omitempty"`
+}
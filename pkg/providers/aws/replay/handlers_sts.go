@@ -0,0 +1,155 @@
+package replay
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/auth"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+const (
+	demoMemberAccountID = "210987654321"
+	demoAuditRoleARN    = "arn:aws:iam::" + demoAccountID + ":role/ctk-demo-audit"
+	demoMemberRoleARN   = "arn:aws:iam::" + demoMemberAccountID + ":role/ctk-member-audit"
+	demoMemberExternal  = "ctk-demo-external-id"
+)
+
+// assumableRoleFixture describes a role the replay STS endpoint will hand out
+// sessions for. ExternalID, when set, must match the AssumeRole request the
+// same way a trust-policy `sts:ExternalId` condition would.
+type assumableRoleFixture struct {
+	AccountID  string
+	ExternalID string
+}
+
+var demoAssumableRoles = map[string]assumableRoleFixture{
+	demoAuditRoleARN:  {AccountID: demoAccountID},
+	demoMemberRoleARN: {AccountID: demoMemberAccountID, ExternalID: demoMemberExternal},
+	// Organizations member role used by the multi-account cloudlist fan-out.
+	"arn:aws:iam::" + demoMemberAccountID + ":role/" + demoOrgRole: {AccountID: demoMemberAccountID},
+}
+
+// stsSession is a temporary credential minted by the replay AssumeRole
+// handler. Requests signed with it are accepted by verifyAuth and reported
+// as the assumed-role principal by GetCallerIdentity.
+type stsSession struct {
+	Credential  auth.Credential
+	RoleARN     string
+	SessionName string
+	AccountID   string
+	RoleID      string
+}
+
+func (s stsSession) assumedRoleARN() string {
+	roleName := s.RoleARN[strings.LastIndex(s.RoleARN, "/")+1:]
+	return "arn:aws:sts::" + s.AccountID + ":assumed-role/" + roleName + "/" + s.SessionName
+}
+
+func (s stsSession) callerIdentity() stsGetCallerIdentityResult {
+	return stsGetCallerIdentityResult{
+		Account: s.AccountID,
+		Arn:     s.assumedRoleARN(),
+		UserID:  s.RoleID + ":" + s.SessionName,
+	}
+}
+
+// signingCredential resolves the secret for an access key seen in a SigV4
+// Authorization header: either the long-lived demo key or a replay session.
+func (t *transport) signingCredential(accessKeyID string) (auth.Credential, bool) {
+	if accessKeyID == DemoAccessKeyID {
+		return auth.New(DemoAccessKeyID, DemoAccessKeySecret, ""), true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.stsSessions[accessKeyID]
+	return session.Credential, ok
+}
+
+func (t *transport) sessionFor(req *http.Request) (stsSession, bool) {
+	parsed, ok := parseSigV4Auth(strings.TrimSpace(req.Header.Get("Authorization")))
+	if !ok {
+		return stsSession{}, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	session, ok := t.stsSessions[parsed.AccessKey]
+	return session, ok
+}
+
+func (t *transport) handleAssumeRole(req *http.Request, form url.Values) (*http.Response, error) {
+	roleARN := strings.TrimSpace(form.Get("RoleArn"))
+	sessionName := strings.TrimSpace(form.Get("RoleSessionName"))
+	if roleARN == "" || sessionName == "" {
+		return apiErrorResponse(req, http.StatusBadRequest, "ValidationError", "RoleArn and RoleSessionName are required"), nil
+	}
+	role, ok := demoAssumableRoles[roleARN]
+	if !ok {
+		return apiErrorResponse(req, http.StatusForbidden, "AccessDenied", fmt.Sprintf("User is not authorized to perform: sts:AssumeRole on resource: %s", roleARN)), nil
+	}
+	if role.ExternalID != "" && form.Get("ExternalId") != role.ExternalID {
+		return apiErrorResponse(req, http.StatusForbidden, "AccessDenied", fmt.Sprintf("User is not authorized to perform: sts:AssumeRole on resource: %s", roleARN)), nil
+	}
+	if serial := strings.TrimSpace(form.Get("SerialNumber")); serial != "" && len(strings.TrimSpace(form.Get("TokenCode"))) != 6 {
+		return apiErrorResponse(req, http.StatusForbidden, "AccessDenied", "MultiFactorAuthentication failed with invalid MFA one time pass code."), nil
+	}
+
+	t.mu.Lock()
+	t.stsSessionSeq++
+	seq := t.stsSessionSeq
+	session := stsSession{
+		Credential: auth.New(
+			fmt.Sprintf("ASIAREPLAYSESSION%03d", seq),
+			fmt.Sprintf("replaySessionSecret%03dEXAMPLEKEY", seq),
+			fmt.Sprintf("FwoGZXIvYXdzEReplaySessionToken%03d", seq),
+		),
+		RoleARN:     roleARN,
+		SessionName: sessionName,
+		AccountID:   role.AccountID,
+		RoleID:      fmt.Sprintf("AROAREPLAYROLE%03d", seq),
+	}
+	t.stsSessions[session.Credential.AccessKeyID] = session
+	t.mu.Unlock()
+
+	resp := stsAssumeRoleResponse{
+		Metadata: awsResponseMetadata{RequestID: fmt.Sprintf("req-replay-sts-assume-%03d", seq)},
+	}
+	resp.Result.Credentials = stsCredentialsWire{
+		AccessKeyID:     session.Credential.AccessKeyID,
+		SecretAccessKey: session.Credential.SecretAccessKey,
+		SessionToken:    session.Credential.SessionToken,
+		Expiration:      time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+	}
+	resp.Result.AssumedRoleUser = stsAssumedRoleUserWire{
+		Arn:           session.assumedRoleARN(),
+		AssumedRoleID: session.RoleID + ":" + sessionName,
+	}
+	return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+}
+
+type stsAssumeRoleResponse struct {
+	XMLName  xml.Name            `xml:"AssumeRoleResponse"`
+	Result   stsAssumeRoleResult `xml:"AssumeRoleResult"`
+	Metadata awsResponseMetadata `xml:"ResponseMetadata"`
+}
+
+type stsAssumeRoleResult struct {
+	Credentials     stsCredentialsWire     `xml:"Credentials"`
+	AssumedRoleUser stsAssumedRoleUserWire `xml:"AssumedRoleUser"`
+}
+
+type stsCredentialsWire struct {
+	AccessKeyID     string `xml:"AccessKeyId"`
+	SecretAccessKey string `xml:"SecretAccessKey"`
+	SessionToken    string `xml:"SessionToken"`
+	Expiration      string `xml:"Expiration"`
+}
+
+type stsAssumedRoleUserWire struct {
+	Arn           string `xml:"Arn"`
+	AssumedRoleID string `xml:"AssumedRoleId"`
+}
@@ -0,0 +1,167 @@
+package replay
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+// TestReplayE2E_EventDump drives CloudTrail LookupEvents through the public
+// Provider so the embedded CloudTrailEvent fixtures stay decodable.
+func TestReplayE2E_EventDump(t *testing.T) {
+	provider := newReplayProvider(t)
+	result, err := provider.EventDump(context.Background(), "dump", "")
+	if err != nil {
+		t.Fatalf("EventDump: %v", err)
+	}
+	if len(result.Events) != len(demoCloudTrailEvents) {
+		t.Fatalf("expected %d events, got %d", len(demoCloudTrailEvents), len(result.Events))
+	}
+	for _, ev := range result.Events {
+		if ev.SourceIp == "" || ev.Principal == "" || ev.UserAgent == "" || ev.PrincipalType != "IAMUser" {
+			t.Fatalf("expected embedded record fields on %s, got %+v", ev.Id, ev)
+		}
+	}
+	if got := result.Events[0]; got.Status != "成功" || got.Request != "userName=ctk-demo-bot" {
+		t.Fatalf("unexpected CreateUser event: %+v", got)
+	}
+	if got := result.Events[len(result.Events)-1]; got.Status != "失败(AccessDenied)" {
+		t.Fatalf("expected AccessDenied status on last event, got %+v", got)
+	}
+}
+
+func newReplayProvider(t *testing.T) *aws.Provider {
+	t.Helper()
+	options := schema.Options{
+		utils.AccessKey: DemoAccessKeyID,
+		utils.SecretKey: DemoAccessKeySecret,
+		utils.Region:    "us-east-1",
+	}
+	provider, err := aws.NewWithConfig(options, ClientConfig())
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	return provider
+}
+
+// TestReplayE2E_AssumeRoleChain hops from the demo user into the audit role
+// and then across into the member-account role, checking the provider ends up
+// signing as the final assumed-role session.
+func TestReplayE2E_AssumeRoleChain(t *testing.T) {
+	options := schema.Options{
+		utils.AccessKey:  DemoAccessKeyID,
+		utils.SecretKey:  DemoAccessKeySecret,
+		utils.Region:     "us-east-1",
+		utils.Payload:    "cloudlist",
+		utils.AWSRoleArn: demoAuditRoleARN + "," + demoMemberRoleARN,
+	}
+	if _, err := aws.NewWithConfig(options, ClientConfig()); err == nil {
+		t.Fatalf("expected missing external id on the member hop to be rejected")
+	}
+
+	options[utils.AWSExternalId] = "," + demoMemberExternal
+	options[utils.AWSSessionName] = "ctk-e2e"
+	provider, err := aws.NewWithConfig(options, ClientConfig())
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	result, err := provider.IAMCredential(context.Background(), "list", "ctk-demo-admin", "")
+	if err != nil {
+		t.Fatalf("IAMCredential via assumed role: %v", err)
+	}
+	if len(result.Credentials) == 0 {
+		t.Fatalf("expected access keys listed through the assumed session")
+	}
+
+	options[utils.AWSRoleArn] = "arn:aws:iam::" + demoAccountID + ":role/not-trusted"
+	delete(options, utils.AWSExternalId)
+	if _, err := aws.NewWithConfig(options, ClientConfig()); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected AccessDenied for untrusted role, got %v", err)
+	}
+}
+
+// TestReplayE2E_OrganizationFanOut enumerates the demo organization: the
+// management account uses the caller credential, the member account is
+// reached through OrganizationAccountAccessRole, the locked account surfaces
+// as an org/<id> partial error and the suspended account is skipped.
+func TestReplayE2E_OrganizationFanOut(t *testing.T) {
+	env.SetActiveForTest(t, &env.Env{Cloudlist: []string{"account", "bucket"}})
+	options := schema.Options{
+		utils.AccessKey:  DemoAccessKeyID,
+		utils.SecretKey:  DemoAccessKeySecret,
+		utils.Region:     "us-east-1",
+		utils.AWSOrgRole: demoOrgRole,
+	}
+	provider, err := aws.NewWithConfig(options, ClientConfig())
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	resources, err := provider.Resources(context.Background())
+	if err == nil {
+		t.Fatalf("expected partial error for the locked account")
+	}
+	if len(resources.Errors) != 1 || resources.Errors[0].Scope != "org/"+demoLockedAccountID {
+		t.Fatalf("expected one org/%s error, got %+v", demoLockedAccountID, resources.Errors)
+	}
+	perAccount := map[string]int{}
+	for _, asset := range resources.Assets {
+		switch v := asset.(type) {
+		case schema.User:
+			perAccount[v.Account]++
+		case schema.Storage:
+			perAccount[v.Account]++
+		default:
+			t.Fatalf("unexpected asset type %T", asset)
+		}
+	}
+	if len(perAccount) != 2 || perAccount[demoAccountID] == 0 || perAccount[demoMemberAccountID] != perAccount[demoAccountID] {
+		t.Fatalf("expected matching inventories for management and member accounts, got %v", perAccount)
+	}
+}
+
+// TestReplayE2E_SecurityGroups enumerates every demo region and checks the
+// SSH and RDP rules open to 0.0.0.0/0 are the only ones flagged.
+func TestReplayE2E_SecurityGroups(t *testing.T) {
+	env.SetActiveForTest(t, &env.Env{Cloudlist: []string{"securitygroup"}})
+	options := schema.Options{
+		utils.AccessKey: DemoAccessKeyID,
+		utils.SecretKey: DemoAccessKeySecret,
+		utils.Region:    "all",
+	}
+	provider, err := aws.NewWithConfig(options, ClientConfig())
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	resources, err := provider.Resources(context.Background())
+	if err != nil {
+		t.Fatalf("Resources: %v", err)
+	}
+	exposed := map[string]string{}
+	attached := map[string][]string{}
+	for _, asset := range resources.Assets {
+		group, ok := asset.(schema.SecurityGroup)
+		if !ok {
+			t.Fatalf("unexpected asset type %T", asset)
+		}
+		attached[group.GroupID] = group.Attached
+		for _, rule := range group.Rules {
+			if rule.Exposed {
+				exposed[group.GroupID] = rule.PortRange
+			}
+		}
+	}
+	if len(attached) != len(demoSecurityGroups) {
+		t.Fatalf("expected %d groups, got %v", len(demoSecurityGroups), attached)
+	}
+	if len(exposed) != 2 || exposed["sg-0ctkbastion0001"] != "22" || exposed["sg-0ctkedge0000101"] != "3389" {
+		t.Fatalf("unexpected exposed rules: %v", exposed)
+	}
+	if got := strings.Join(attached["sg-0ctkedge0000101"], ","); got != "eni-0ctkelb00000101,i-0a1b2c3d4e5f60101" {
+		t.Fatalf("unexpected edge attachments: %s", got)
+	}
+}
@@ -25,6 +25,9 @@ type transport struct {
 	ssmInvocations map[string]ssmInvocation
 	accessKeys     map[string][]iamAccessKeyFixture
 	accessKeySeq   int
+	stsSessions    map[string]stsSession
+	stsSessionSeq  int
+	objects        map[string][]byte
 }
 
 func newTransport() *transport {
@@ -35,6 +38,8 @@ func newTransport() *transport {
 		bucketACL:      seedAWSBucketACL(),
 		ssmInvocations: make(map[string]ssmInvocation),
 		accessKeys:     seedAWSAccessKeys(),
+		stsSessions:    make(map[string]stsSession),
+		objects:        make(map[string][]byte),
 	}
 }
 
@@ -66,7 +71,7 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if err != nil {
 		return nil, err
 	}
-	switch verifyAuth(req, body) {
+	switch verifyAuth(req, body, t.signingCredential) {
 	case demoreplay.AuthInvalidAccessKey:
 		return apiErrorResponse(req, http.StatusForbidden, "InvalidClientTokenId", "The security token included in the request is invalid."), nil
 	case demoreplay.AuthInvalidSignature:
@@ -82,7 +87,10 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 	case isIAMHost(host):
 		return t.handleIAM(req, body)
 	case isS3Host(host):
-		return t.handleS3(req, host)
+		return t.handleS3(req, host, body)
+	case isS3ControlHost(host):
+		// The demo account has no account-wide Public Access Block.
+		return s3ErrorResponse(req, http.StatusNotFound, "NoSuchPublicAccessBlockConfiguration", "The public access block configuration was not found"), nil
 	case isSSMHost(host):
 		return t.handleSSM(req, body)
 	case isCloudTrailHost(host):
@@ -95,6 +103,8 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return t.handleCostExplorer(req, body)
 	case isLogsHost(host):
 		return t.handleLogs(req, body)
+	case isOrganizationsHost(host):
+		return t.handleOrganizations(req, body)
 	}
 	return apiErrorResponse(req, http.StatusNotFound, "InvalidEndpoint", fmt.Sprintf("unsupported replay host: %s", host)), nil
 }
@@ -115,7 +125,12 @@ func (t *transport) handleSTS(req *http.Request, body []byte) (*http.Response, e
 			},
 			Metadata: awsResponseMetadata{RequestID: "req-replay-sts-caller"},
 		}
+		if session, ok := t.sessionFor(req); ok {
+			resp.Result = session.callerIdentity()
+		}
 		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+	case "AssumeRole":
+		return t.handleAssumeRole(req, form)
 	}
 	return apiErrorResponse(req, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("unsupported sts action: %s", action)), nil
 }
@@ -155,6 +170,10 @@ func (t *transport) handleEC2(req *http.Request, body []byte) (*http.Response, e
 			resp.Reservations = append(resp.Reservations, reservation)
 		}
 		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+	case "DescribeSecurityGroups":
+		return t.handleDescribeSecurityGroups(req, region)
+	case "DescribeNetworkInterfaces":
+		return t.handleDescribeNetworkInterfaces(req, region)
 	}
 	return apiErrorResponse(req, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("unsupported ec2 action: %s", action)), nil
 }
@@ -328,6 +347,45 @@ func (t *transport) handleIAM(req *http.Request, body []byte) (*http.Response, e
 			})
 		}
 		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+	case "ListMFADevices":
+		userName := strings.TrimSpace(form.Get("UserName"))
+		if userName == "" {
+			userName = demoCallerUserName()
+		}
+		user, ok := t.findUser(userName)
+		if !ok {
+			return apiErrorResponse(req, http.StatusNotFound, "NoSuchEntity", fmt.Sprintf("user %s not found", userName)), nil
+		}
+		resp := iamListMFADevicesResponse{
+			Metadata: awsResponseMetadata{RequestID: "req-replay-iam-list-mfa-devices"},
+		}
+		if user.MFASerial != "" {
+			resp.Result.Devices = append(resp.Result.Devices, iamMFADeviceWire{
+				UserName:     user.UserName,
+				SerialNumber: user.MFASerial,
+				EnableDate:   user.CreateDate,
+			})
+		}
+		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
+	case "GetAccessKeyLastUsed":
+		accessKeyID := strings.TrimSpace(form.Get("AccessKeyId"))
+		userName, ok := t.accessKeyOwner(accessKeyID)
+		if !ok {
+			return apiErrorResponse(req, http.StatusNotFound, "NoSuchEntity", fmt.Sprintf("access key %s not found", accessKeyID)), nil
+		}
+		resp := iamGetAccessKeyLastUsedResponse{
+			Metadata: awsResponseMetadata{RequestID: "req-replay-iam-get-access-key-last-used"},
+		}
+		resp.Result.UserName = userName
+		resp.Result.LastUsed = iamAccessKeyLastUsedWire{ServiceName: "N/A", Region: "N/A"}
+		if used, ok := demoAccessKeyLastUsed[accessKeyID]; ok {
+			resp.Result.LastUsed = iamAccessKeyLastUsedWire{
+				LastUsedDate: used.Date,
+				ServiceName:  used.Service,
+				Region:       used.Region,
+			}
+		}
+		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
 	case "CreateAccessKey":
 		userName := strings.TrimSpace(form.Get("UserName"))
 		if userName == "" {
@@ -362,7 +420,7 @@ func (t *transport) handleIAM(req *http.Request, body []byte) (*http.Response, e
 	return apiErrorResponse(req, http.StatusBadRequest, "InvalidAction", fmt.Sprintf("unsupported iam action: %s", action)), nil
 }
 
-func (t *transport) handleS3(req *http.Request, host string) (*http.Response, error) {
+func (t *transport) handleS3(req *http.Request, host string, body []byte) (*http.Response, error) {
 	path := strings.TrimPrefix(req.URL.Path, "/")
 	query := req.URL.Query()
 
@@ -377,11 +435,14 @@ func (t *transport) handleS3(req *http.Request, host string) (*http.Response, er
 		return demoreplay.XMLResponse(req, http.StatusOK, resp), nil
 	}
 
-	bucketName, _, _ := strings.Cut(path, "/")
+	bucketName, key, _ := strings.Cut(path, "/")
 	bucket, ok := findS3Bucket(bucketName)
 	if !ok {
 		return s3ErrorResponse(req, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist"), nil
 	}
+	if key != "" {
+		return t.handleS3Object(req, bucket.Name, key, body)
+	}
 
 	if query.Has("acl") {
 		return t.handleS3BucketACL(req, bucket.Name)
@@ -392,6 +453,10 @@ func (t *transport) handleS3(req *http.Request, host string) (*http.Response, er
 		return s3ErrorResponse(req, http.StatusNotFound, "NoSuchPublicAccessBlockConfiguration", "The public access block configuration was not found"), nil
 	}
 
+	if query.Has("policy") {
+		return s3ErrorResponse(req, http.StatusNotFound, "NoSuchBucketPolicy", "The bucket policy does not exist"), nil
+	}
+
 	if req.Method != http.MethodGet {
 		return s3ErrorResponse(req, http.StatusMethodNotAllowed, "MethodNotAllowed", "the specified method is not allowed against this resource"), nil
 	}
@@ -566,6 +631,19 @@ func (t *transport) mintAccessKey(userName string) iamAccessKeyFixture {
 	return key
 }
 
+func (t *transport) accessKeyOwner(accessKeyID string) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for userName, keys := range t.accessKeys {
+		for _, k := range keys {
+			if k.AccessKeyID == accessKeyID {
+				return userName, true
+			}
+		}
+	}
+	return "", false
+}
+
 func (t *transport) deleteAccessKey(userName, accessKeyID string) bool {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -590,13 +668,20 @@ func policyNameFromARN(arn string) string {
 	return arn
 }
 
-func verifyAuth(req *http.Request, body []byte) demoreplay.AuthFailureKind {
+func verifyAuth(req *http.Request, body []byte, lookup func(string) (auth.Credential, bool)) demoreplay.AuthFailureKind {
+	if req.URL.Query().Has("X-Amz-Signature") {
+		return verifyPresigned(req, lookup)
+	}
 	authHeader := strings.TrimSpace(req.Header.Get("Authorization"))
 	parsed, ok := parseSigV4Auth(authHeader)
 	if !ok {
 		return demoreplay.AuthInvalidSignature
 	}
-	if parsed.AccessKey != DemoAccessKeyID {
+	credential, ok := lookup(parsed.AccessKey)
+	if !ok {
+		return demoreplay.AuthInvalidAccessKey
+	}
+	if credential.SessionToken != "" && !demoreplay.SubtleEqual(credential.SessionToken, strings.TrimSpace(req.Header.Get("X-Amz-Security-Token"))) {
 		return demoreplay.AuthInvalidAccessKey
 	}
 	amzDate := strings.TrimSpace(req.Header.Get("X-Amz-Date"))
@@ -611,7 +696,7 @@ func verifyAuth(req *http.Request, body []byte) demoreplay.AuthFailureKind {
 	extra := req.Header.Clone()
 	extra.Del("Authorization")
 	extra.Del("Host")
-	signed, err := (api.SigV4Signer{}).Sign(auth.New(DemoAccessKeyID, DemoAccessKeySecret, ""), api.SignInput{
+	signed, err := (api.SigV4Signer{}).Sign(credential, api.SignInput{
 		Method:      req.Method,
 		Service:     parsed.Service,
 		Region:      parsed.Region,
@@ -691,6 +776,12 @@ func isS3Host(host string) bool {
 	return strings.HasPrefix(host, "s3.")
 }
 
+// isS3ControlHost matches both the plain and the account-prefixed S3 Control
+// endpoint.
+func isS3ControlHost(host string) bool {
+	return strings.HasPrefix(host, "s3-control.") || strings.Contains(host, ".s3-control.")
+}
+
 func isSSMHost(host string) bool {
 	return strings.HasPrefix(host, "ssm.")
 }
@@ -852,6 +943,40 @@ type iamListAccessKeysResult struct {
 	Marker      string             `xml:"Marker,omitempty"`
 }
 
+type iamListMFADevicesResponse struct {
+	XMLName  xml.Name                `xml:"ListMFADevicesResponse"`
+	Result   iamListMFADevicesResult `xml:"ListMFADevicesResult"`
+	Metadata awsResponseMetadata     `xml:"ResponseMetadata"`
+}
+
+type iamListMFADevicesResult struct {
+	Devices     []iamMFADeviceWire `xml:"MFADevices>member"`
+	IsTruncated bool               `xml:"IsTruncated"`
+}
+
+type iamMFADeviceWire struct {
+	UserName     string `xml:"UserName"`
+	SerialNumber string `xml:"SerialNumber"`
+	EnableDate   string `xml:"EnableDate"`
+}
+
+type iamGetAccessKeyLastUsedResponse struct {
+	XMLName  xml.Name                      `xml:"GetAccessKeyLastUsedResponse"`
+	Result   iamGetAccessKeyLastUsedResult `xml:"GetAccessKeyLastUsedResult"`
+	Metadata awsResponseMetadata           `xml:"ResponseMetadata"`
+}
+
+type iamGetAccessKeyLastUsedResult struct {
+	UserName string                   `xml:"UserName"`
+	LastUsed iamAccessKeyLastUsedWire `xml:"AccessKeyLastUsed"`
+}
+
+type iamAccessKeyLastUsedWire struct {
+	LastUsedDate string `xml:"LastUsedDate,omitempty"`
+	ServiceName  string `xml:"ServiceName"`
+	Region       string `xml:"Region"`
+}
+
 type iamCreateAccessKeyResponse struct {
 	XMLName  xml.Name                 `xml:"CreateAccessKeyResponse"`
 	Result   iamCreateAccessKeyResult `xml:"CreateAccessKeyResult"`
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
@@ -19,10 +20,10 @@ const (
 )
 
 // AuditBucketACL enumerates buckets in scope and returns the canned ACL
-// summary for each. ACL state on AWS S3 is the union of canned ACL grants
-// and a bucket's optional Public Access Block; this view surfaces only the
-// canned-grant signal because that is what the bucket-acl-check `audit`
-// table is shaped around.
+// summary for each, along with the effective exposure once the bucket policy
+// and the account and bucket Public Access Blocks are taken into account:
+// IgnorePublicAcls voids public ACL grants and RestrictPublicBuckets voids
+// public policy statements.
 func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.BucketACLEntry, error) {
 	client, err := d.requireClient()
 	if err != nil {
@@ -32,6 +33,7 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 	if err != nil {
 		return nil, err
 	}
+	accountBlock, accountErr := d.accountPublicAccessBlock(ctx, client)
 	bucket = strings.TrimSpace(bucket)
 	out := make([]schema.BucketACLEntry, 0, len(storages))
 	for _, s := range storages {
@@ -46,14 +48,64 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 		if err != nil {
 			return out, fmt.Errorf("get acl for %s: %w", s.BucketName, err)
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     S3CannedACLFromGrants(acl),
-		})
+		}
+		var verdict exposure.Verdict
+		if accountErr != nil {
+			verdict.Unknown("account public access block", accountErr)
+		}
+		bucketBlock, err := client.GetPublicAccessBlock(ctx, region, s.BucketName)
+		if err != nil {
+			verdict.Unknown("bucket public access block", err)
+		}
+		aclGrant := exposure.ACL(entry.Level)
+		if blocker := publicAccessBlocker(accountBlock, bucketBlock, false); blocker != "" {
+			verdict.Block("acl", aclGrant, blocker)
+		} else {
+			verdict.Add("acl", aclGrant)
+		}
+		if doc, err := client.GetBucketPolicy(ctx, region, s.BucketName); err != nil {
+			verdict.Unknown(exposure.PolicyLayer, err)
+		} else if policyGrant, err := exposure.Policy(doc); err != nil {
+			verdict.Unknown(exposure.PolicyLayer, err)
+		} else if blocker := publicAccessBlocker(accountBlock, bucketBlock, true); blocker != "" {
+			verdict.Block(exposure.PolicyLayer, policyGrant, blocker)
+		} else {
+			verdict.Add(exposure.PolicyLayer, policyGrant)
+		}
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
 
+// accountPublicAccessBlock reads the caller account's Public Access Block.
+func (d *Driver) accountPublicAccessBlock(ctx context.Context, client *api.Client) (api.PublicAccessBlock, error) {
+	identity, err := client.GetCallerIdentity(ctx, d.defaultRegion())
+	if err != nil {
+		return api.PublicAccessBlock{}, err
+	}
+	return client.GetAccountPublicAccessBlock(ctx, d.defaultRegion(), identity.Account)
+}
+
+// publicAccessBlocker names the Public Access Block setting that voids
+// public ACLs, or public policies when policy is set; "" when none does.
+func publicAccessBlocker(account, bucket api.PublicAccessBlock, policy bool) string {
+	setting, accountOn, bucketOn := "IgnorePublicAcls", account.IgnorePublicAcls, bucket.IgnorePublicAcls
+	if policy {
+		setting, accountOn, bucketOn = "RestrictPublicBuckets", account.RestrictPublicBuckets, bucket.RestrictPublicBuckets
+	}
+	switch {
+	case accountOn:
+		return "account " + setting
+	case bucketOn:
+		return "bucket " + setting
+	}
+	return ""
+}
+
 // ExposeBucket sets a public canned ACL on bucket. AWS layers Public Access
 // Block on top of ACL grants — newer accounts have BPA enabled by default,
 // which silently overrides any public canned ACL. This helper deletes the
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -160,3 +161,51 @@ func TestNormalizeS3ACL(t *testing.T) {
 		}
 	}
 }
+
+func TestAuditBucketACLCombinesPolicyAndPublicAccessBlock(t *testing.T) {
+	restrict := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost:
+			_, _ = w.Write([]byte(`<GetCallerIdentityResponse><GetCallerIdentityResult><Account>111122223333</Account></GetCallerIdentityResult></GetCallerIdentityResponse>`))
+		case r.URL.Path == "/":
+			_, _ = w.Write([]byte(`<ListAllMyBucketsResult><Buckets><Bucket><Name>ctk-demo</Name></Bucket></Buckets></ListAllMyBucketsResult>`))
+		case r.URL.Path == "/v20180820/configuration/publicAccessBlock":
+			if got := r.Header.Get("x-amz-account-id"); got != "111122223333" {
+				t.Fatalf("unexpected account id header: %q", got)
+			}
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`<ErrorResponse><Error><Code>NoSuchPublicAccessBlockConfiguration</Code></Error></ErrorResponse>`))
+		case query.Has("location"):
+			_, _ = w.Write([]byte(`<LocationConstraint>us-west-2</LocationConstraint>`))
+		case query.Has("acl"):
+			_, _ = w.Write([]byte(`<AccessControlPolicy><Owner><ID>owner</ID></Owner><AccessControlList></AccessControlList></AccessControlPolicy>`))
+		case query.Has("publicAccessBlock"):
+			_, _ = w.Write([]byte(`<PublicAccessBlockConfiguration><RestrictPublicBuckets>` + strconv.FormatBool(restrict) + `</RestrictPublicBuckets></PublicAccessBlockConfiguration>`))
+		case query.Has("policy"):
+			_, _ = w.Write([]byte(`{"Statement":[{"Sid":"PublicRead","Effect":"Allow","Principal":{"AWS":"*"},"Action":"s3:GetObject","Resource":"arn:aws:s3:::ctk-demo/*"}]}`))
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	driver := &Driver{Client: newS3DriverTestClient(server.URL), DefaultRegion: "us-east-1"}
+	entries, err := driver.AuditBucketACL(context.Background(), "")
+	if err != nil {
+		t.Fatalf("AuditBucketACL: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Level != S3ACLPrivate || entries[0].Exposure != "public-read" || !strings.Contains(entries[0].Reason, "PublicRead") {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	restrict = true
+	entries, err = driver.AuditBucketACL(context.Background(), "ctk-demo")
+	if err != nil {
+		t.Fatalf("AuditBucketACL: %v", err)
+	}
+	if entries[0].Exposure != "private" || !strings.Contains(entries[0].Reason, "bucket RestrictPublicBuckets") {
+		t.Fatalf("unexpected entry with RestrictPublicBuckets: %+v", entries[0])
+	}
+}
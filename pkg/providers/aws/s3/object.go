@@ -10,37 +10,54 @@ import (
 	"github.com/404tk/cloudtoolkit/utils/processbar"
 )
 
+// ListObjects returns every object of each bucket.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's continuation tokens and hands the
+// objects matching q to visit.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	results := []schema.BucketResult{}
 	for b, r := range buckets {
-		resp, err := d.listObjectsPage(ctx, b, r, "", 100)
-		if err != nil {
-			return nil, fmt.Errorf("list objects in %s: %w", b, err)
-		}
-
-		objects := make([]schema.BucketObject, 0, len(resp.Objects))
-		for _, obj := range resp.Objects {
-			objects = append(objects, schema.BucketObject{
-				BucketName:   b,
-				Key:          obj.Key,
-				Size:         obj.Size,
-				LastModified: obj.LastModified,
-				StorageClass: obj.StorageClass,
-			})
+		walk := schema.NewObjectWalk(q, visit)
+		token := ""
+	pages:
+		for {
+			resp, err := d.listObjectsPage(ctx, b, r, q.Prefix, token, 1000)
+			if err != nil {
+				return results, fmt.Errorf("list objects in %s: %w", b, err)
+			}
+			for _, obj := range resp.Objects {
+				more, err := walk.Add(schema.BucketObject{
+					BucketName:   b,
+					Key:          obj.Key,
+					Size:         obj.Size,
+					LastModified: obj.LastModified,
+					StorageClass: obj.StorageClass,
+				})
+				if err != nil {
+					return results, err
+				}
+				if !more {
+					break pages
+				}
+			}
+			if !resp.IsTruncated || strings.TrimSpace(resp.NextContinuationToken) == "" {
+				break
+			}
+			token = resp.NextContinuationToken
+			if ctx.Err() != nil {
+				break
+			}
 		}
-
-		result := schema.BucketResult{
-			Action:      "list",
-			BucketName:  b,
-			ObjectCount: int64(len(objects)),
-			Objects:     objects,
-			Message:     fmt.Sprintf("%d objects found", len(objects)),
-		}
-		results = append(results, result)
+		results = append(results, walk.Result(b))
 
 		select {
 		case <-ctx.Done():
-			return results, nil
+			return results, ctx.Err()
 		default:
 		}
 	}
@@ -69,19 +86,19 @@ func (d *Driver) TotalObjects(ctx context.Context, buckets map[string]string) ([
 	return results, nil
 }
 
-func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, token string, maxKeys int) (api.ListObjectsV2Output, error) {
+func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, prefix, token string, maxKeys int) (api.ListObjectsV2Output, error) {
 	client, err := d.requireClient()
 	if err != nil {
 		return api.ListObjectsV2Output{}, err
 	}
-	return client.ListObjectsV2(ctx, d.resolveRegion(region), bucket, token, maxKeys)
+	return client.ListObjectsV2(ctx, d.resolveRegion(region), bucket, prefix, token, maxKeys)
 }
 
 func (d *Driver) countBucketObjects(ctx context.Context, bucket, region string, tracker *processbar.CountTracker) (int, error) {
 	count := 0
 	token := ""
 	for {
-		resp, err := d.listObjectsPage(ctx, bucket, region, token, 1000)
+		resp, err := d.listObjectsPage(ctx, bucket, region, "", token, 1000)
 		if err != nil {
 			return 0, err
 		}
@@ -0,0 +1,62 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// presignExpiry bounds the pre-signed URL the presign action fetches; the
+// URL is used once, immediately.
+const presignExpiry = 5 * time.Minute
+
+// AccessObject performs one object-access-check request on bucket/key. The
+// request ID of a failed call is kept so a denied access can be traced too.
+func (d *Driver) AccessObject(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	result := schema.ObjectAccessResult{Action: action, Bucket: bucket, Key: key}
+	bucket = strings.TrimSpace(bucket)
+	if bucket == "" || strings.TrimSpace(key) == "" {
+		return result, fmt.Errorf("aws s3: bucket and key are required")
+	}
+	client, err := d.requireClient()
+	if err != nil {
+		return result, err
+	}
+	location, err := client.GetBucketLocation(ctx, d.defaultRegion(), bucket)
+	if err != nil {
+		return result, err
+	}
+	// An empty location constraint is us-east-1.
+	region := location.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	var out api.ObjectOutput
+	switch action {
+	case "put":
+		out, err = client.PutObject(ctx, region, bucket, key, body, "text/plain")
+	case "get":
+		out, err = client.GetObject(ctx, region, bucket, key)
+	case "presign":
+		result.URL, err = client.PresignGetObject(ctx, region, bucket, key, presignExpiry)
+		if err == nil {
+			out, err = client.GetPresignedObject(ctx, result.URL)
+		}
+	case "delete":
+		out, err = client.DeleteObject(ctx, region, bucket, key)
+	default:
+		return result, fmt.Errorf("aws s3: unsupported object action %q", action)
+	}
+	result.StatusCode = out.StatusCode
+	result.RequestID = out.RequestID
+	result.Body = out.Body
+	if err != nil {
+		result.RequestID = api.ErrorRequestID(err)
+	}
+	return result, err
+}
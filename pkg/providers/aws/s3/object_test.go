@@ -2,10 +2,13 @@ package s3
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"sync"
 	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func TestListObjectsPageUsesDefaultRegionAndMaxKeys(t *testing.T) {
@@ -37,7 +40,7 @@ func TestListObjectsPageUsesDefaultRegionAndMaxKeys(t *testing.T) {
 		Client:        newS3DriverTestClient(server.URL),
 		DefaultRegion: "ap-southeast-1",
 	}
-	got, err := driver.listObjectsPage(context.Background(), "bucket-a", "", "", 100)
+	got, err := driver.listObjectsPage(context.Background(), "bucket-a", "", "", "", 100)
 	if err != nil {
 		t.Fatalf("listObjectsPage() error = %v", err)
 	}
@@ -100,3 +103,72 @@ func TestCountBucketObjectsPaginatesContinuationToken(t *testing.T) {
 		t.Fatalf("unexpected object count: %d", got)
 	}
 }
+
+func TestWalkObjectsFollowsTokensWithPrefixAndMax(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("prefix"); got != "logs/" {
+			t.Fatalf("unexpected prefix: %q", got)
+		}
+		if r.URL.Query().Get("continuation-token") == "" {
+			_, _ = w.Write([]byte(`
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>page-2</NextContinuationToken>
+  <Contents><Key>logs/a.txt</Key><Size>1</Size></Contents>
+  <Contents><Key>logs/b.gz</Key><Size>2</Size></Contents>
+</ListBucketResult>`))
+			return
+		}
+		_, _ = w.Write([]byte(`
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>page-3</NextContinuationToken>
+  <Contents><Key>logs/c.gz</Key><Size>3</Size></Contents>
+  <Contents><Key>logs/d.gz</Key><Size>4</Size></Contents>
+</ListBucketResult>`))
+	}))
+	defer server.Close()
+
+	driver := &Driver{Client: newS3DriverTestClient(server.URL)}
+	var keys []string
+	q := schema.ObjectQuery{Prefix: "logs/", Pattern: "*.gz", Max: 2}
+	got, err := driver.WalkObjects(context.Background(), map[string]string{"bucket-a": "us-east-1"}, q, func(obj schema.BucketObject) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WalkObjects() error = %v", err)
+	}
+	if len(keys) != 2 || keys[0] != "logs/b.gz" || keys[1] != "logs/c.gz" {
+		t.Fatalf("unexpected keys: %v", keys)
+	}
+	if len(got) != 1 || got[0].ObjectCount != 2 || len(got[0].Objects) != 0 {
+		t.Fatalf("unexpected results: %+v", got)
+	}
+}
+
+func TestWalkObjectsReturnsContextErrorWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`
+<ListBucketResult xmlns="http://s3.amazonaws.com/doc/2006-03-01/">
+  <IsTruncated>true</IsTruncated>
+  <NextContinuationToken>next</NextContinuationToken>
+  <Contents><Key>a.txt</Key><Size>1</Size></Contents>
+</ListBucketResult>`))
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	driver := &Driver{Client: newS3DriverTestClient(server.URL)}
+	got, err := driver.WalkObjects(ctx, map[string]string{"bucket-a": "us-east-1"}, schema.ObjectQuery{}, func(schema.BucketObject) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WalkObjects() error = %v, want %v", err, context.Canceled)
+	}
+	if len(got) != 1 || got[0].ObjectCount != 1 {
+		t.Fatalf("expected the partial result, got %+v", got)
+	}
+}
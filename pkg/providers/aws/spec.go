@@ -13,6 +13,12 @@ func init() {
 			{Name: utils.SecurityToken, Description: "Security Token", Sensitive: true},
 			{Name: utils.Region, Description: "Region", Default: "all"},
 			{Name: utils.Version, Description: "International or custom edition"},
+			{Name: utils.AWSRoleArn, Description: "Role ARN to assume (comma-separated for chains)"},
+			{Name: utils.AWSExternalId, Description: "External ID for AssumeRole", Sensitive: true},
+			{Name: utils.AWSSessionName, Description: "AssumeRole session name"},
+			{Name: utils.AWSMFASerial, Description: "MFA device serial for AssumeRole"},
+			{Name: utils.AWSMFAToken, Description: "MFA token code for AssumeRole", Sensitive: true},
+			{Name: utils.AWSOrgRole, Description: "Role name assumed in each Organizations member account for cloudlist"},
 		},
 		Regions: []registry.Suggestion{
 			{Text: "all", Description: "enumerate all configured regions"},
@@ -28,6 +34,6 @@ func init() {
 			{Text: "eu-west-1", Description: "Ireland"},
 			{Text: "eu-central-1", Description: "Frankfurt"},
 		},
-		Capabilities: []string{"cloudlist", "iam", "bucket", "iam-role", "bucket-acl", "vm", "event", "iam-credential", "database"},
+		Capabilities: []string{"cloudlist", "iam", "bucket", "iam-role", "bucket-acl", "vm", "event", "iam-credential", "database", "object-access", "iam-posture"},
 	})
 }
@@ -57,37 +57,45 @@ func (d *Driver) SetPollOptions(interval time.Duration, max int, sleep func(time
 // on hard failure — callers log via logger; the REPL surface is the same as
 // the existing alibaba/tencent ECS exec drivers.
 func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
+	result, err := d.Invoke(context.Background(), instanceID, osType, cmd)
+	if err != nil {
+		logger.Error(err)
+	}
+	return result.Output
+}
+
+// Invoke is RunCommand with the invocation's exit code and stderr. A
+// non-success terminal status is returned as an error alongside whatever
+// output the invocation produced.
+func (d *Driver) Invoke(ctx context.Context, instanceID, osType, cmd string) (schema.CommandResult, error) {
 	if d == nil || d.Client == nil {
-		logger.Error("aws ssm: nil client")
-		return ""
+		return schema.CommandResult{}, errors.New("aws ssm: nil client")
 	}
-	ctx := context.Background()
 	doc, ok := resolveDocumentName(osType)
 	if !ok {
-		logger.Error("aws ssm: unsupported os type", osType)
-		return ""
+		return schema.CommandResult{}, fmt.Errorf("aws ssm: unsupported os type %s", osType)
 	}
 	region := strings.TrimSpace(d.Region)
 	if region == "" || region == "all" {
-		logger.Error("aws ssm: empty region")
-		return ""
+		return schema.CommandResult{}, errors.New("aws ssm: empty region")
 	}
 	resp, err := d.Client.SSMSendCommand(ctx, region, doc, []string{instanceID}, []string{cmd})
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	commandID := strings.TrimSpace(resp.Command.CommandID)
 	if commandID == "" {
-		logger.Error("aws ssm: empty command id")
-		return ""
+		return schema.CommandResult{}, errors.New("aws ssm: empty command id")
 	}
 	return d.pollInvocation(ctx, region, commandID, instanceID)
 }
 
-func (d *Driver) pollInvocation(ctx context.Context, region, commandID, instanceID string) string {
+func (d *Driver) pollInvocation(ctx context.Context, region, commandID, instanceID string) (schema.CommandResult, error) {
 	for attempts := 0; attempts < d.pollLimit(); attempts++ {
 		d.sleepFor(d.pollDelay())
+		if err := ctx.Err(); err != nil {
+			return schema.CommandResult{}, err
+		}
 		invocation, err := d.Client.SSMGetCommandInvocation(ctx, region, commandID, instanceID)
 		if err != nil {
 			// SSM returns InvocationDoesNotExist for a brief window after
@@ -96,27 +104,30 @@ func (d *Driver) pollInvocation(ctx context.Context, region, commandID, instance
 			if isInvocationNotFound(err) && attempts+1 < d.pollLimit() {
 				continue
 			}
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		switch invocation.Status {
-		case "Pending", "InProgress", "Delayed":
+		case "Pending", "InProgress", "Delayed", "":
 			continue
-		case "Success":
-			return invocation.StandardOutputContent
-		case "":
-			continue
-		default:
-			if invocation.StandardErrorContent != "" {
-				logger.Error("Exception status: " + invocation.Status + " - " + invocation.StandardErrorContent)
-			} else {
-				logger.Error("Exception status: " + invocation.Status)
-			}
-			return invocation.StandardOutputContent
 		}
+		result := schema.CommandResult{
+			Output: invocation.StandardOutputContent,
+			Stderr: invocation.StandardErrorContent,
+			Status: invocation.Status,
+		}
+		if invocation.ResponseCode != nil && *invocation.ResponseCode >= 0 {
+			code := *invocation.ResponseCode
+			result.ExitCode = &code
+		}
+		if invocation.Status == "Success" {
+			return result, nil
+		}
+		if invocation.StandardErrorContent != "" {
+			return result, errors.New("Exception status: " + invocation.Status + " - " + invocation.StandardErrorContent)
+		}
+		return result, errors.New("Exception status: " + invocation.Status)
 	}
-	logger.Error("Timeout: SSM invocation did not complete in time.")
-	return ""
+	return schema.CommandResult{}, errors.New("aws ssm: invocation did not complete in time")
 }
 
 func resolveDocumentName(osType string) (string, bool) {
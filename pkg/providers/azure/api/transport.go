@@ -4,12 +4,15 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 func NewHTTPClient() *http.Client {
 	return &http.Client{
 		Timeout: 60 * time.Second,
-		Transport: &http.Transport{
+		Transport: ratelimit.Wrap("azure", telemetry.Wrap("azure", &http.Transport{
 			Proxy:                 http.ProxyFromEnvironment,
 			DialContext:           (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
 			ForceAttemptHTTP2:     true,
@@ -18,6 +21,6 @@ func NewHTTPClient() *http.Client {
 			IdleConnTimeout:       90 * time.Second,
 			TLSHandshakeTimeout:   10 * time.Second,
 			ExpectContinueTimeout: 1 * time.Second,
-		},
+		})),
 	}
 }
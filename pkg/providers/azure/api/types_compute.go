@@ -7,6 +7,7 @@ type VirtualMachine struct {
 	Name       string              `json:"name"`
 	Location   string              `json:"location"`
 	Status     string              `json:"status"`
+	Tags       map[string]string   `json:"tags"`
 	Properties VirtualMachineProps `json:"properties"`
 }
 
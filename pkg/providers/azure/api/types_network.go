@@ -27,11 +27,43 @@ type ResourceRef struct {
 }
 
 type PublicIPAddress struct {
-	ID         string             `json:"id"`
-	Name       string             `json:"name"`
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
 	Properties PublicIPAddressProps `json:"properties"`
 }
 
 type PublicIPAddressProps struct {
 	IPAddress string `json:"ipAddress"`
 }
+
+type NetworkSecurityGroup struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Location   string                    `json:"location"`
+	Properties NetworkSecurityGroupProps `json:"properties"`
+}
+
+type NetworkSecurityGroupProps struct {
+	SecurityRules     []SecurityRule `json:"securityRules"`
+	NetworkInterfaces []ResourceRef  `json:"networkInterfaces"`
+	Subnets           []ResourceRef  `json:"subnets"`
+}
+
+type SecurityRule struct {
+	Name       string            `json:"name"`
+	Properties SecurityRuleProps `json:"properties"`
+}
+
+type SecurityRuleProps struct {
+	Description                string   `json:"description"`
+	Protocol                   string   `json:"protocol"`
+	SourceAddressPrefix        string   `json:"sourceAddressPrefix"`
+	SourceAddressPrefixes      []string `json:"sourceAddressPrefixes"`
+	DestinationAddressPrefix   string   `json:"destinationAddressPrefix"`
+	DestinationAddressPrefixes []string `json:"destinationAddressPrefixes"`
+	DestinationPortRange       string   `json:"destinationPortRange"`
+	DestinationPortRanges      []string `json:"destinationPortRanges"`
+	Access                     string   `json:"access"`
+	Priority                   int      `json:"priority"`
+	Direction                  string   `json:"direction"`
+}
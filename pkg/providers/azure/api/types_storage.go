@@ -3,9 +3,17 @@ package api
 const StorageAPIVersion = "2022-05-01"
 
 type StorageAccount struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Location string `json:"location"`
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Location   string                    `json:"location"`
+	Properties *StorageAccountProperties `json:"properties,omitempty"`
+}
+
+// StorageAccountProperties keeps the account switch that gates anonymous
+// blob access. A nil AllowBlobPublicAccess is an account created before the
+// property existed, for which public access is allowed.
+type StorageAccountProperties struct {
+	AllowBlobPublicAccess *bool `json:"allowBlobPublicAccess,omitempty"`
 }
 
 type ListStorageAccountsResponse struct {
@@ -43,3 +51,14 @@ type ListBlobContainersResponse struct {
 type BlobContainerPatchRequest struct {
 	Properties BlobContainerProperties `json:"properties"`
 }
+
+// StorageAccountKey is one entry of the listKeys action result.
+type StorageAccountKey struct {
+	KeyName     string `json:"keyName"`
+	Value       string `json:"value"`
+	Permissions string `json:"permissions"`
+}
+
+type ListStorageAccountKeysResponse struct {
+	Keys []StorageAccountKey `json:"keys"`
+}
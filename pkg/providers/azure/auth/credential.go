@@ -110,6 +110,21 @@ func (c Cloud) MicrosoftGraphEndpoint() string {
 	}
 }
 
+// StorageEndpointSuffix returns the DNS suffix of storage data-plane
+// endpoints; blob endpoints are https://<account>.blob.<suffix>/.
+func (c Cloud) StorageEndpointSuffix() string {
+	switch normalizeCloud(c) {
+	case CloudChina:
+		return "core.chinacloudapi.cn"
+	case CloudUSGov:
+		return "core.usgovcloudapi.net"
+	case CloudGermany:
+		return "core.cloudapi.de"
+	default:
+		return "core.windows.net"
+	}
+}
+
 func cloudFromVersion(version string) Cloud {
 	version = strings.ToLower(strings.TrimSpace(version))
 	switch version {
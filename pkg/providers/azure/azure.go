@@ -12,16 +12,19 @@ import (
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
 	azauth "github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/billing"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/blob"
 	azcloud "github.com/404tk/cloudtoolkit/pkg/providers/azure/cloud"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/compute"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/dns"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/graph"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/insights"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/loganalytics"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/network"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/rbac"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/sqldb"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/storage"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -37,6 +40,8 @@ type Provider struct {
 	apiClient        *azapi.Client
 	graphTokenSource *azauth.TokenSource
 	graphHTTPClient  *http.Client
+	blobClient       *blob.Client
+	blobTokenSource  *azauth.TokenSource
 	subscriptionIDs  []string
 }
 
@@ -81,6 +86,8 @@ func NewWithConfig(options schema.Options, cfg ClientConfig) (*Provider, error)
 		apiClient:        client,
 		graphTokenSource: graphTokenSource,
 		graphHTTPClient:  httpClient,
+		blobClient:       blob.NewClient(httpClient, cred.Cloud.StorageEndpointSuffix()),
+		blobTokenSource:  azauth.NewTokenSourceForScope(cred, httpClient, blob.Scope),
 	}
 
 	subscriptionIDs := make([]string, 0, 1)
@@ -131,8 +138,8 @@ func (p *Provider) CredentialKey(opts map[string]string) string {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&billing.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}).QueryAccountBalance(ctx)
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&billing.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			vmProvider := &compute.Driver{
@@ -201,6 +208,15 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			dbs, err := sqlDriver.GetDatabases(ctx)
 			schema.AppendAssets(list, dbs)
 			list.AddError("database", err)
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			nsgDriver := &network.Driver{
+				Client:          p.apiClient,
+				SubscriptionIDs: p.subscriptionIDs,
+			}
+			groups, err := nsgDriver.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -293,6 +309,47 @@ func (p *Provider) RoleBinding(ctx context.Context, action, principal, role, sco
 	return result, fmt.Errorf("azure: unsupported role-binding action %q", action)
 }
 
+// BucketDump implements schema.BucketManager for Azure Blob storage.
+// bucketName is a container name, "account/container" when the name is
+// ambiguous, or "all"; results are keyed "account/container".
+func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([]schema.BucketResult, error) {
+	driver := &storage.Driver{
+		Client:          p.apiClient,
+		SubscriptionIDs: p.subscriptionIDs,
+		Blob:            p.blobClient,
+		BlobToken:       p.blobTokenSource,
+	}
+	switch action {
+	case "list", "total":
+	default:
+		return nil, fmt.Errorf("invalid action: %s (expected: list, total)", action)
+	}
+	containers, err := driver.FindContainers(ctx, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	if action == "list" {
+		return driver.ListObjects(ctx, containers)
+	}
+	return driver.TotalObjects(ctx, containers)
+}
+
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	driver := &storage.Driver{
+		Client:          p.apiClient,
+		SubscriptionIDs: p.subscriptionIDs,
+		Blob:            p.blobClient,
+		BlobToken:       p.blobTokenSource,
+	}
+	containers, err := driver.FindContainers(ctx, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	return driver.WalkObjects(ctx, containers, q, visit)
+}
+
 // BucketACL implements schema.BucketACLManager.
 func (p *Provider) BucketACL(ctx context.Context, action, container, level string) (schema.BucketACLResult, error) {
 	driver := &storage.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}
@@ -311,11 +368,7 @@ func (p *Provider) BucketACL(ctx context.Context, action, container, level strin
 			if container != "" && c.Name != container {
 				continue
 			}
-			result.Containers = append(result.Containers, schema.BucketACLEntry{
-				Account:   c.AccountName,
-				Container: c.Name,
-				Level:     c.PublicAccess,
-			})
+			result.Containers = append(result.Containers, c.ACLEntry())
 		}
 		result.Message = fmt.Sprintf("%d containers audited", len(result.Containers))
 		return result, nil
@@ -350,6 +403,13 @@ func (p *Provider) BucketACL(ctx context.Context, action, container, level strin
 	return result, fmt.Errorf("azure: unsupported bucket-acl action %q", action)
 }
 
+// IAMPosture implements schema.IAMPostureReader for Entra ID users through
+// Microsoft Graph.
+func (p *Provider) IAMPosture(ctx context.Context) ([]schema.UserPosture, error) {
+	graphClient := graph.NewClient(p.graphTokenSource, p.graphHTTPClient, p.cred.Cloud.MicrosoftGraphEndpoint())
+	return graphClient.Posture(ctx)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for Azure. The capability
 // maps to Microsoft Graph application password credential lifecycle: list /
 // addPassword / removePassword. `principal` is the Azure AD application ID
@@ -418,6 +478,12 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads the Activity Log of every configured subscription.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &insights.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for Azure SQL by rotating the
 // server administratorLoginPassword. Azure SQL has no native "user" API at
 // ARM (T-SQL is required); rotating the admin password is the closest
@@ -425,6 +491,7 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 // `<resourceGroup>/<serverName>`.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
 	driver := &sqldb.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}
+	instanceID = resolveSQLServer(ctx, instanceID)
 	switch action {
 	case "useradd":
 		return driver.CreateAccount(ctx, instanceID)
@@ -435,6 +502,25 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 	}
 }
 
+// resolveSQLServer expands a bare server name to the `<resourceGroup>/<server>`
+// id recorded by the last cloudlist run. Anything else is returned as is.
+func resolveSQLServer(ctx context.Context, instanceID string) string {
+	if strings.Contains(instanceID, "/") {
+		return instanceID
+	}
+	scope, ok := invcache.From(ctx)
+	if !ok {
+		return instanceID
+	}
+	entry, _ := scope.Store.Load(scope.Credential)
+	for _, db := range entry.Databases {
+		if _, server, ok := strings.Cut(db.InstanceId, "/"); ok && server == instanceID {
+			return db.InstanceId
+		}
+	}
+	return instanceID
+}
+
 // ExecuteCloudVMCommand routes through Microsoft.Compute virtualMachines/runCommand.
 // instanceID may be a full ARM VM ID, `<subscription>/<resourceGroup>/<vmName>`,
 // or the legacy `<resourceGroup>/<vmName>` shorthand. Headless `shell -t/-l`
@@ -442,22 +528,43 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 // REPL shell loop.
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	driver := &compute.Driver{Client: p.apiClient, SubscriptionIDs: p.subscriptionIDs}
+	defaultOS := "linux"
+	// A bare VM name carries no resource group; the inventory of the last
+	// cloudlist run maps it to the full ARM id.
+	if !strings.Contains(instanceID, "/") {
+		if host, ok := invcache.LookupHost(ctx, instanceID); ok && host.ID != "" {
+			instanceID = host.ID
+			if host.OSType != "" {
+				defaultOS = host.OSType
+			}
+		}
+	}
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
 		out, err := driver.RunCommand(ctx, instanceID, osType, command)
 		if err != nil {
 			return schema.CommandResult{}, err
 		}
-		return schema.CommandResult{Output: out}, nil
+		return runCommandResult(out), nil
 	}
 	command, err := base64.StdEncoding.DecodeString(cmd)
 	if err != nil {
 		return schema.CommandResult{}, err
 	}
-	out, err := driver.RunCommand(ctx, instanceID, "linux", strings.TrimSpace(string(command)))
+	out, err := driver.RunCommand(ctx, instanceID, defaultOS, strings.TrimSpace(string(command)))
 	if err != nil {
 		return schema.CommandResult{}, err
 	}
-	return schema.CommandResult{Output: out}, nil
+	return runCommandResult(out), nil
+}
+
+// runCommandResult splits Linux RunShellScript output into its stdout and
+// stderr sections. RunCommand reports no exit code, so ExitCode stays nil.
+func runCommandResult(out string) schema.CommandResult {
+	stdout, stderr, ok := compute.SplitOutput(out)
+	if !ok {
+		return schema.CommandResult{Output: out}
+	}
+	return schema.CommandResult{Output: stdout, Stderr: stderr}
 }
 
 // azureRoleNameFromDefinitionID extracts the role-definition GUID from a
@@ -1,8 +1,13 @@
 package azure
 
 import (
+	"context"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func TestUserManagementRejectsBareUsername(t *testing.T) {
@@ -15,3 +20,23 @@ func TestUserManagementRejectsBareUsername(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+func TestResolveSQLServerUsesInventory(t *testing.T) {
+	const credential = "0123456789abcdef0123456789abcdef"
+	store := &invcache.Store{Dir: t.TempDir(), TTL: time.Hour}
+	resources := schema.Resources{Provider: "azure", Assets: []schema.Asset{schema.Database{InstanceId: "rg-1/ctk-prod-sql"}}}
+	if err := store.Save(credential, []string{schema.AssetDatabase}, resources); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	ctx := invcache.With(context.Background(), store, credential)
+
+	if got := resolveSQLServer(ctx, "ctk-prod-sql"); got != "rg-1/ctk-prod-sql" {
+		t.Fatalf("resolveSQLServer(bare) = %q", got)
+	}
+	if got := resolveSQLServer(ctx, "rg-2/ctk-prod-sql"); got != "rg-2/ctk-prod-sql" {
+		t.Fatalf("resolveSQLServer(explicit) = %q", got)
+	}
+	if got := resolveSQLServer(context.Background(), "ctk-prod-sql"); got != "ctk-prod-sql" {
+		t.Fatalf("resolveSQLServer(no scope) = %q", got)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -23,13 +24,15 @@ type Driver struct {
 	SubscriptionIDs []string
 }
 
-// QueryAccountBalance reports current-month spend for each subscription via
-// logger. Errors are surfaced as Info to keep cloudlist resilient when the
-// caller credential lacks `Microsoft.CostManagement/query/action`.
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+// QueryAccountBalance returns current-month spend for each subscription.
+// Errors are surfaced as Info to keep cloudlist resilient when the caller
+// credential lacks `Microsoft.CostManagement/query/action`.
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	if d == nil || d.Client == nil {
-		return
+		return nil
 	}
+	var out []schema.Balance
+	period := time.Now().UTC().Format("2006-01")
 	for _, sub := range d.SubscriptionIDs {
 		sub = strings.TrimSpace(sub)
 		if sub == "" {
@@ -43,8 +46,15 @@ func (d *Driver) QueryAccountBalance(ctx context.Context) {
 		if amount == "" {
 			continue
 		}
-		logger.Warning(fmt.Sprintf("Azure subscription %s month-to-date spend: %s %s", sub, amount, currency))
+		out = append(out, schema.Balance{
+			Kind:     schema.BalanceSpend,
+			Amount:   amount,
+			Currency: currency,
+			Period:   period,
+			Scope:    sub,
+		})
 	}
+	return out
 }
 
 func (d *Driver) querySubscription(ctx context.Context, subscription string) (string, string, error) {
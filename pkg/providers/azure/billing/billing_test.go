@@ -10,6 +10,7 @@ import (
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/cloud"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 const tokenStub = `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`
@@ -53,7 +54,7 @@ const sampleQueryResponse = `{"id":"q1","name":"q","type":"Microsoft.CostManagem
   "rows":[[42.789,"USD"]]
 }}`
 
-func TestQueryAccountBalanceLogsCurrentMonthSpend(t *testing.T) {
+func TestQueryAccountBalanceReturnsCurrentMonthSpend(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/tenant/oauth2/v2.0/token":
@@ -71,7 +72,13 @@ func TestQueryAccountBalanceLogsCurrentMonthSpend(t *testing.T) {
 	defer server.Close()
 
 	driver := newTestDriver(t, server, []string{"sub-1"})
-	driver.QueryAccountBalance(context.Background())
+	got := driver.QueryAccountBalance(context.Background())
+	if len(got) != 1 {
+		t.Fatalf("expected 1 balance, got %+v", got)
+	}
+	if got[0].Kind != schema.BalanceSpend || got[0].Amount != "42.789" || got[0].Currency != "USD" || got[0].Scope != "sub-1" || got[0].Period == "" {
+		t.Fatalf("unexpected balance: %+v", got[0])
+	}
 }
 
 func TestQueryAccountBalanceSwallowsAccessDenied(t *testing.T) {
@@ -0,0 +1,293 @@
+// Package blob implements the slice of the Azure Blob service data-plane REST
+// API that bucket-check needs: List Blobs with marker pagination, signed
+// with either a storage account shared key or an AAD bearer token.
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
+)
+
+// APIVersion is sent as x-ms-version; bearer auth needs 2017-11-09 or later.
+const APIVersion = "2021-08-06"
+
+// Scope is the OAuth2 scope for storage data-plane tokens in every cloud.
+const Scope = "https://storage.azure.com/.default"
+
+// MaxPageSize is the service-side cap on maxresults.
+const MaxPageSize = 5000
+
+// Authorizer signs a data-plane request for one storage account.
+type Authorizer interface {
+	Authorize(ctx context.Context, req *http.Request, account string) error
+}
+
+// SharedKey authorizes with a storage account access key (from the ARM
+// listKeys action).
+type SharedKey struct {
+	Key string
+}
+
+func (k SharedKey) Authorize(_ context.Context, req *http.Request, account string) error {
+	signature, err := SharedKeySignature(req, account, k.Key)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "SharedKey "+account+":"+signature)
+	return nil
+}
+
+// Bearer authorizes with an AAD token; the principal needs a data-plane role
+// such as Storage Blob Data Reader.
+type Bearer struct {
+	Source *auth.TokenSource
+}
+
+func (b Bearer) Authorize(ctx context.Context, req *http.Request, _ string) error {
+	if b.Source == nil {
+		return errors.New("azure blob: nil token source")
+	}
+	token, err := b.Source.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// Client talks to https://<account>.blob.<suffix>/.
+type Client struct {
+	httpClient *http.Client
+	suffix     string
+	// endpoint overrides the account URL; tests point it at httptest.
+	endpoint func(account string) string
+}
+
+// NewClient returns a Blob client for the given storage endpoint suffix (see
+// auth.Cloud.StorageEndpointSuffix).
+func NewClient(httpClient *http.Client, suffix string) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+	if strings.TrimSpace(suffix) == "" {
+		suffix = "core.windows.net"
+	}
+	return &Client{httpClient: httpClient, suffix: suffix}
+}
+
+// Object is one entry of a List Blobs page.
+type Object struct {
+	Name         string
+	Size         int64
+	LastModified string
+	AccessTier   string
+}
+
+// Page is one List Blobs response; NextMarker is empty on the last page.
+type Page struct {
+	Objects    []Object
+	NextMarker string
+}
+
+type enumerationResults struct {
+	Blobs struct {
+		Blob []struct {
+			Name       string `xml:"Name"`
+			Properties struct {
+				LastModified  string `xml:"Last-Modified"`
+				ContentLength int64  `xml:"Content-Length"`
+				AccessTier    string `xml:"AccessTier"`
+			} `xml:"Properties"`
+		} `xml:"Blob"`
+	} `xml:"Blobs"`
+	NextMarker string `xml:"NextMarker"`
+}
+
+type errorBody struct {
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+// ListBlobs fetches one page of the container's blobs under prefix starting
+// at marker.
+func (c *Client) ListBlobs(ctx context.Context, authz Authorizer, account, container, prefix, marker string, maxResults int) (Page, error) {
+	query := url.Values{
+		"restype": {"container"},
+		"comp":    {"list"},
+	}
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if marker != "" {
+		query.Set("marker", marker)
+	}
+	if maxResults > 0 {
+		query.Set("maxresults", strconv.Itoa(min(maxResults, MaxPageSize)))
+	}
+	endpoint := c.accountURL(account) + "/" + url.PathEscape(container) + "?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return Page{}, err
+	}
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	req.Header.Set("x-ms-version", APIVersion)
+	if authz == nil {
+		return Page{}, errors.New("azure blob: nil authorizer")
+	}
+	if err := authz.Authorize(ctx, req, account); err != nil {
+		return Page{}, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Page{}, err
+	}
+	defer httpclient.CloseResponse(resp)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Page{}, fmt.Errorf("azure blob: read body: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return Page{}, decodeError(resp, body)
+	}
+
+	var parsed enumerationResults
+	if err := xml.Unmarshal(body, &parsed); err != nil {
+		return Page{}, fmt.Errorf("azure blob: decode list: %w", err)
+	}
+	page := Page{
+		Objects:    make([]Object, 0, len(parsed.Blobs.Blob)),
+		NextMarker: strings.TrimSpace(parsed.NextMarker),
+	}
+	for _, b := range parsed.Blobs.Blob {
+		page.Objects = append(page.Objects, Object{
+			Name:         b.Name,
+			Size:         b.Properties.ContentLength,
+			LastModified: b.Properties.LastModified,
+			AccessTier:   b.Properties.AccessTier,
+		})
+	}
+	return page, nil
+}
+
+func (c *Client) accountURL(account string) string {
+	if c.endpoint != nil {
+		return strings.TrimRight(c.endpoint(account), "/")
+	}
+	return "https://" + account + ".blob." + c.suffix
+}
+
+func decodeError(resp *http.Response, body []byte) error {
+	apiErr := &azapi.APIError{
+		StatusCode: resp.StatusCode,
+		Code:       resp.Header.Get("x-ms-error-code"),
+		RequestID:  resp.Header.Get("x-ms-request-id"),
+	}
+	var parsed errorBody
+	if err := xml.Unmarshal(body, &parsed); err == nil {
+		if apiErr.Code == "" {
+			apiErr.Code = strings.TrimSpace(parsed.Code)
+		}
+		// The service appends RequestId/Time lines to the message.
+		apiErr.Message, _, _ = strings.Cut(strings.TrimSpace(parsed.Message), "\n")
+	}
+	return apiErr
+}
+
+// IsAuthorizationError reports whether err is a 403 that another
+// Authorizer may get past (shared key disabled, missing data-plane role).
+func IsAuthorizationError(err error) bool {
+	var apiErr *azapi.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+// SharedKeySignature computes the Shared Key signature of req as described
+// in "Authorize with Shared Key". The replay transport uses it to verify
+// requests.
+func SharedKeySignature(req *http.Request, account, key string) (string, error) {
+	secret, err := base64.StdEncoding.DecodeString(strings.TrimSpace(key))
+	if err != nil {
+		return "", fmt.Errorf("azure blob: decode account key: %w", err)
+	}
+	contentLength := req.Header.Get("Content-Length")
+	if contentLength == "0" {
+		contentLength = ""
+	}
+	parts := []string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date: x-ms-date is used instead.
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+	}
+	stringToSign := strings.Join(parts, "\n") + "\n" + canonicalHeaders(req.Header) + canonicalResource(req.URL, account)
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+func canonicalHeaders(header http.Header) string {
+	names := make([]string, 0)
+	values := make(map[string]string)
+	for name, vals := range header {
+		lower := strings.ToLower(name)
+		if !strings.HasPrefix(lower, "x-ms-") {
+			continue
+		}
+		names = append(names, lower)
+		values[lower] = strings.TrimSpace(strings.Join(vals, ","))
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name + ":" + values[name] + "\n")
+	}
+	return b.String()
+}
+
+func canonicalResource(u *url.URL, account string) string {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	var b strings.Builder
+	b.WriteString("/" + account + path)
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	for _, name := range names {
+		vals := append([]string(nil), query[name]...)
+		sort.Strings(vals)
+		b.WriteString("\n" + strings.ToLower(name) + ":" + strings.Join(vals, ","))
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package blob
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testKey = "dGVzdC1hY2NvdW50LWtleQ=="
+
+func TestListBlobsSignsSharedKeyAndFollowsMarker(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want, err := SharedKeySignature(r, "acct", testKey)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		if got := r.Header.Get("Authorization"); got != "SharedKey acct:"+want {
+			t.Fatalf("unexpected authorization %q", got)
+		}
+		if r.Header.Get("x-ms-version") != APIVersion || r.Header.Get("x-ms-date") == "" {
+			t.Fatalf("missing x-ms headers: %v", r.Header)
+		}
+		if r.URL.Path != "/logs" || r.URL.Query().Get("comp") != "list" || r.URL.Query().Get("restype") != "container" {
+			t.Fatalf("unexpected request: %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		if r.URL.Query().Get("marker") == "" {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs><Blob><Name>a.txt</Name><Properties><Last-Modified>Thu, 30 Apr 2026 09:12:44 GMT</Last-Modified><Content-Length>12</Content-Length><AccessTier>Hot</AccessTier></Properties></Blob></Blobs><NextMarker>m2</NextMarker></EnumerationResults>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<EnumerationResults><Blobs><Blob><Name>b.txt</Name><Properties><Content-Length>3</Content-Length></Properties></Blob></Blobs><NextMarker /></EnumerationResults>`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), "")
+	client.endpoint = func(string) string { return server.URL }
+	first, err := client.ListBlobs(context.Background(), SharedKey{Key: testKey}, "acct", "logs", "", "", 10)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Objects) != 1 || first.Objects[0].Name != "a.txt" || first.Objects[0].Size != 12 || first.NextMarker != "m2" {
+		t.Fatalf("unexpected first page: %+v", first)
+	}
+	second, err := client.ListBlobs(context.Background(), SharedKey{Key: testKey}, "acct", "logs", "", first.NextMarker, 10)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Objects) != 1 || second.Objects[0].Name != "b.txt" || second.NextMarker != "" {
+		t.Fatalf("unexpected second page: %+v", second)
+	}
+}
+
+func TestListBlobsDecodesAuthorizationFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ms-error-code", "KeyBasedAuthenticationNotPermitted")
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("<Error><Code>KeyBasedAuthenticationNotPermitted</Code><Message>Key based authentication is not permitted on this storage account.\nRequestId:1\nTime:2026</Message></Error>"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.Client(), "")
+	client.endpoint = func(string) string { return server.URL }
+	_, err := client.ListBlobs(context.Background(), SharedKey{Key: testKey}, "acct", "logs", "", "", 0)
+	if !IsAuthorizationError(err) {
+		t.Fatalf("expected authorization error, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "KeyBasedAuthenticationNotPermitted") || strings.Contains(err.Error(), "RequestId") {
+		t.Fatalf("unexpected error text: %v", err)
+	}
+}
+
+func TestCanonicalResourceSortsQuery(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://acct.blob.core.windows.net/logs?restype=container&comp=list&marker=m2", nil)
+	got := canonicalResource(req.URL, "acct")
+	want := "/acct/logs\ncomp:list\nmarker:m2\nrestype:container"
+	if got != want {
+		t.Fatalf("canonicalResource = %q, want %q", got, want)
+	}
+}
@@ -114,6 +114,26 @@ func formatRunCommandOutput(r azapi.RunCommandResult) string {
 	return strings.Join(parts, "\n")
 }
 
+// SplitOutput separates the `[stdout]` / `[stderr]` sections the Linux
+// RunShellScript extension writes into its status message. ok is false when
+// the output does not carry those markers (Windows, or a failed invocation).
+func SplitOutput(output string) (stdout, stderr string, ok bool) {
+	const (
+		stdoutMarker = "[stdout]\n"
+		stderrMarker = "\n[stderr]\n"
+	)
+	start := strings.Index(output, stdoutMarker)
+	if start < 0 {
+		return "", "", false
+	}
+	rest := output[start+len(stdoutMarker):]
+	end := strings.LastIndex(rest, stderrMarker)
+	if end < 0 {
+		return rest, "", true
+	}
+	return rest[:end], rest[end+len(stderrMarker):], true
+}
+
 func (d *Driver) resolveRunCommandTarget(instanceID string) (runCommandTarget, error) {
 	instanceID = strings.TrimSpace(instanceID)
 	if instanceID == "" {
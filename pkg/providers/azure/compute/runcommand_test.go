@@ -118,6 +118,19 @@ func TestRunCommandTwoPartIDUsesDefaultSubscription(t *testing.T) {
 	}
 }
 
+func TestSplitOutputSeparatesShellStreams(t *testing.T) {
+	stdout, stderr, ok := SplitOutput("Enable succeeded: \n[stdout]\nroot\n\n[stderr]\nwarning\n")
+	if !ok {
+		t.Fatal("expected stream markers to be recognised")
+	}
+	if stdout != "root\n" || stderr != "warning\n" {
+		t.Fatalf("unexpected streams: stdout=%q stderr=%q", stdout, stderr)
+	}
+	if _, _, ok := SplitOutput("default subscription"); ok {
+		t.Fatal("expected plain output to be left unsplit")
+	}
+}
+
 func newRunCommandTestClient(t *testing.T, server *httptest.Server) *azapi.Client {
 	t.Helper()
 	httpClient := server.Client()
@@ -48,6 +48,7 @@ func (d *Driver) GetResource(ctx context.Context) ([]schema.Host, error) {
 					State:    vmState(vm),
 					HostName: vm.Name,
 					Region:   vm.Location,
+					Tags:     vm.Tags,
 				}
 				if vm.Properties.NetworkProfile == nil {
 					list = append(list, host)
@@ -57,20 +57,29 @@ type UserPasswordProfile struct {
 // User is a slim projection of the Microsoft Graph user resource for the
 // validation flow's needs.
 type User struct {
-	ID                string               `json:"id,omitempty"`
-	AccountEnabled    bool                 `json:"accountEnabled"`
-	DisplayName       string               `json:"displayName"`
-	MailNickname      string               `json:"mailNickname"`
-	UserPrincipalName string               `json:"userPrincipalName"`
-	CreatedDateTime   string               `json:"createdDateTime,omitempty"`
-	SignInActivity    *SignInActivity      `json:"signInActivity,omitempty"`
-	PasswordProfile   *UserPasswordProfile `json:"passwordProfile,omitempty"`
+	ID                         string               `json:"id,omitempty"`
+	AccountEnabled             bool                 `json:"accountEnabled"`
+	DisplayName                string               `json:"displayName"`
+	MailNickname               string               `json:"mailNickname"`
+	UserPrincipalName          string               `json:"userPrincipalName"`
+	CreatedDateTime            string               `json:"createdDateTime,omitempty"`
+	LastPasswordChangeDateTime string               `json:"lastPasswordChangeDateTime,omitempty"`
+	SignInActivity             *SignInActivity      `json:"signInActivity,omitempty"`
+	PasswordProfile            *UserPasswordProfile `json:"passwordProfile,omitempty"`
 }
 
 type SignInActivity struct {
 	LastSignInDateTime string `json:"lastSignInDateTime,omitempty"`
 }
 
+// UserRegistrationDetails is the slice of the authentication methods
+// registration report that iam-posture reads.
+type UserRegistrationDetails struct {
+	ID                string `json:"id"`
+	UserPrincipalName string `json:"userPrincipalName"`
+	IsMFARegistered   bool   `json:"isMfaRegistered"`
+}
+
 // Application is a partial projection of the Graph application resource
 // covering only the fields the iam-credential driver needs.
 type Application struct {
@@ -89,6 +98,11 @@ type usersListResponse struct {
 	ODataNextLink string `json:"@odata.nextLink"`
 }
 
+type registrationDetailsListResponse struct {
+	Value         []UserRegistrationDetails `json:"value"`
+	ODataNextLink string                    `json:"@odata.nextLink"`
+}
+
 type addPasswordRequest struct {
 	PasswordCredential PasswordCredential `json:"passwordCredential"`
 }
@@ -147,7 +161,7 @@ func (c *Client) RemovePassword(ctx context.Context, appOrObjectID, keyID string
 // ListUsers enumerates Microsoft Graph users for cloudlist account inventory.
 func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
 	query := url.Values{}
-	query.Set("$select", "id,displayName,userPrincipalName,accountEnabled,createdDateTime,signInActivity")
+	query.Set("$select", "id,displayName,userPrincipalName,accountEnabled,createdDateTime,lastPasswordChangeDateTime,signInActivity")
 	path := "/users?" + query.Encode()
 	out := make([]User, 0)
 	for page := 0; page < 50; page++ {
@@ -168,6 +182,29 @@ func (c *Client) ListUsers(ctx context.Context) ([]User, error) {
 	return out, fmt.Errorf("azure graph: user pagination exceeded 50 pages")
 }
 
+// ListUserRegistrationDetails pages the authentication methods registration
+// report, which says per user whether an MFA method is registered.
+func (c *Client) ListUserRegistrationDetails(ctx context.Context) ([]UserRegistrationDetails, error) {
+	path := "/reports/authenticationMethods/userRegistrationDetails"
+	out := make([]UserRegistrationDetails, 0)
+	for page := 0; page < 50; page++ {
+		var resp registrationDetailsListResponse
+		if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+			return out, err
+		}
+		out = append(out, resp.Value...)
+		if strings.TrimSpace(resp.ODataNextLink) == "" {
+			return out, nil
+		}
+		nextPath, err := graphPathFromNextLink(resp.ODataNextLink)
+		if err != nil {
+			return out, err
+		}
+		path = nextPath
+	}
+	return out, fmt.Errorf("azure graph: registration details pagination exceeded 50 pages")
+}
+
 // CreateUser provisions a Microsoft Graph user (Azure AD user) with the
 // supplied initial password.
 func (c *Client) CreateUser(ctx context.Context, displayName, userPrincipalName, password string) (User, error) {
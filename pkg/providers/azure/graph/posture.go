@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// Posture reads the credential state of every Entra ID user: sign-in
+// activity and the last password change from the user list, and MFA
+// registration from the authentication methods report. Users hold no access
+// keys; application secrets are covered by iam-credential instead. When the
+// registration report cannot be read, every user records an MFA gap.
+func (c *Client) Posture(ctx context.Context) ([]schema.UserPosture, error) {
+	users, err := c.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	mfa := make(map[string]bool)
+	details, detailsErr := c.ListUserRegistrationDetails(ctx)
+	for _, detail := range details {
+		mfa[detail.ID] = detail.IsMFARegistered
+	}
+
+	out := make([]schema.UserPosture, 0, len(users))
+	for _, user := range users {
+		posture := schema.UserPosture{
+			UserName:        firstNonEmpty(user.UserPrincipalName, user.DisplayName, user.ID),
+			UserID:          user.ID,
+			CreateTime:      parseGraphTime(user.CreatedDateTime),
+			PasswordChanged: parseGraphTime(user.LastPasswordChangeDateTime),
+			MFA:             mfa[user.ID],
+		}
+		posture.Password = !posture.PasswordChanged.IsZero()
+		if user.SignInActivity != nil {
+			posture.LastLogin = parseGraphTime(user.SignInActivity.LastSignInDateTime)
+		}
+		if detailsErr != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapMFA)
+		}
+		out = append(out, posture)
+	}
+	return out, nil
+}
+
+func parseGraphTime(value string) time.Time {
+	parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(value))
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
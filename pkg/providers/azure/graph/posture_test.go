@@ -0,0 +1,61 @@
+package graph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestPostureJoinsMFARegistration(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/users":
+			_, _ = w.Write([]byte(`{"value":[{"id":"u-1","userPrincipalName":"alice@example.com","accountEnabled":true,"createdDateTime":"2026-01-10T03:00:00Z","lastPasswordChangeDateTime":"2026-01-10T03:05:00Z","signInActivity":{"lastSignInDateTime":"2026-04-20T01:12:00Z"}},{"id":"u-2","userPrincipalName":"bob@example.com","accountEnabled":true}]}`))
+		case "/v1.0/reports/authenticationMethods/userRegistrationDetails":
+			_, _ = w.Write([]byte(`{"value":[{"id":"u-1","userPrincipalName":"alice@example.com","isMfaRegistered":true}]}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(newStubTokenSource("token"), server.Client(), server.URL)
+	got, err := client.Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(got))
+	}
+	if alice := got[0]; !alice.MFA || !alice.Password || alice.LastLogin.IsZero() || len(alice.Gaps) != 0 {
+		t.Errorf("unexpected alice posture: %+v", alice)
+	}
+	if bob := got[1]; bob.MFA || bob.Password || !bob.LastLogin.IsZero() {
+		t.Errorf("unexpected bob posture: %+v", bob)
+	}
+}
+
+func TestPostureRecordsMFAGapWhenReportUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.0/users":
+			_, _ = w.Write([]byte(`{"value":[{"id":"u-1","userPrincipalName":"alice@example.com","accountEnabled":true}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"error":{"code":"Authentication_RequestFromNonPremiumTenantOrB2CTenant"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(newStubTokenSource("token"), server.Client(), server.URL)
+	got, err := client.Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture: %v", err)
+	}
+	if len(got) != 1 || !got[0].HasGap(schema.PostureGapMFA) {
+		t.Fatalf("expected an MFA gap, got %+v", got)
+	}
+}
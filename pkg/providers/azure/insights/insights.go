@@ -30,19 +30,30 @@ const (
 // be `<startUnix>:<endUnix>` (eventTimestamp window), "all", or empty for the
 // service default 7-day lookback.
 func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, error) {
+	startTS, endTS, err := parseTimeWindow(args)
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTS, endTS))
+}
+
+// QueryEvents returns the activity log events matching q. The time window
+// and a resource ID go into `$filter`; the Activity Log API filters on
+// nothing else the query expresses, so the rest is applied client-side.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
 	if d == nil || d.Client == nil {
 		return nil, errors.New("azure insights: nil api client")
 	}
 	if len(d.SubscriptionIDs) == 0 || strings.TrimSpace(d.SubscriptionIDs[0]) == "" {
 		return nil, errors.New("azure insights: no subscription configured")
 	}
-	startTS, endTS, err := parseTimeWindow(args)
-	if err != nil {
-		return nil, err
+	startTS, endTS := q.UnixWindow()
+	filter := buildFilter(startTS, endTS)
+	if resource := strings.TrimSpace(q.Resource); strings.HasPrefix(resource, "/subscriptions/") {
+		filter += fmt.Sprintf(" and resourceUri eq '%s'", strings.ReplaceAll(resource, "'", "''"))
 	}
 	out := make([]schema.Event, 0)
 	for _, sub := range d.SubscriptionIDs {
-		filter := buildFilter(startTS, endTS)
 		query := url.Values{}
 		query.Set("api-version", azapi.InsightsAPIVersion)
 		query.Set("$filter", filter)
@@ -55,7 +66,7 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 		pager := azapi.NewPager[azapi.ActivityLogEvent](d.Client, req)
 		events, err := pager.All(ctx)
 		if err != nil {
-			return out, err
+			return q.WithoutWindow().Filter(out), err
 		}
 		for _, ev := range events {
 			out = append(out, schema.Event{
@@ -70,7 +81,7 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 			})
 		}
 	}
-	return out, nil
+	return q.WithoutWindow().Filter(out), nil
 }
 
 // HandleEvents is unsupported — Activity Log is read-only.
@@ -79,13 +90,14 @@ func (d *Driver) HandleEvents(ctx context.Context, _ string) (schema.EventAction
 }
 
 // buildFilter constructs the OData $filter expression used by the Activity
-// Log endpoint. Both bounds are required by the service; default to
-// last-7-days when caller omits them.
+// Log endpoint. Both bounds are required by the service; an open end is now
+// and an open start the 7 days before the end.
 func buildFilter(start, end int64) string {
-	if start <= 0 || end <= 0 {
-		now := time.Now().UTC()
-		end = now.Unix()
-		start = now.Add(-time.Hour * defaultLookbackHours).Unix()
+	if end <= 0 {
+		end = time.Now().UTC().Unix()
+	}
+	if start <= 0 {
+		start = time.Unix(end, 0).Add(-time.Hour * defaultLookbackHours).Unix()
 	}
 	startISO := time.Unix(start, 0).UTC().Format(time.RFC3339)
 	endISO := time.Unix(end, 0).UTC().Format(time.RFC3339)
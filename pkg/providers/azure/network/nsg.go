@@ -0,0 +1,146 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+type Driver struct {
+	Client          *azapi.Client
+	SubscriptionIDs []string
+}
+
+// GetSecurityGroups lists the network security groups of every subscription.
+// Only user-defined rules are returned; the built-in default rules are the
+// same for every NSG and would drown the ones that matter.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	logger.Info("List network security groups ...")
+	for _, subscription := range d.SubscriptionIDs {
+		pager := azapi.NewPager[azapi.NetworkSecurityGroup](d.Client, azapi.Request{
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Network/networkSecurityGroups", subscription),
+			Query:      url.Values{"api-version": {azapi.NetworkAPIVersion}},
+			Idempotent: true,
+		})
+		items, err := pager.All(ctx)
+		if err != nil {
+			return list, err
+		}
+		for _, nsg := range items {
+			list = append(list, convertNSG(nsg))
+		}
+	}
+	return list, nil
+}
+
+func convertNSG(nsg azapi.NetworkSecurityGroup) schema.SecurityGroup {
+	group := schema.SecurityGroup{
+		GroupID:   nsg.ID,
+		GroupName: nsg.Name,
+		Region:    nsg.Location,
+	}
+	if res, err := azapi.ParseResourceID(nsg.ID); err == nil {
+		group.GroupID = res.ResourceGroup + "/" + res.ResourceName
+	}
+	for _, nic := range nsg.Properties.NetworkInterfaces {
+		group.Attached = append(group.Attached, "nic:"+resourceName(nic.ID))
+	}
+	for _, subnet := range nsg.Properties.Subnets {
+		group.Attached = append(group.Attached, "subnet:"+subnetName(subnet.ID))
+	}
+	for _, rule := range nsg.Properties.SecurityRules {
+		group.Rules = append(group.Rules, convertRule(rule)...)
+	}
+	group.FlagExposure()
+	return group
+}
+
+func convertRule(rule azapi.SecurityRule) []schema.FirewallRule {
+	props := rule.Properties
+	direction := "ingress"
+	peers := prefixes(props.SourceAddressPrefix, props.SourceAddressPrefixes)
+	if strings.EqualFold(props.Direction, "Outbound") {
+		direction = "egress"
+		peers = prefixes(props.DestinationAddressPrefix, props.DestinationAddressPrefixes)
+	}
+	base := schema.FirewallRule{
+		Direction:   direction,
+		Action:      strings.ToLower(strings.TrimSpace(props.Access)),
+		Protocol:    normalizeProtocol(props.Protocol),
+		PortRange:   portRanges(props.DestinationPortRange, props.DestinationPortRanges),
+		Priority:    strconv.Itoa(props.Priority),
+		Description: firstNonEmpty(props.Description, rule.Name),
+	}
+	out := make([]schema.FirewallRule, 0, len(peers))
+	for _, peer := range peers {
+		item := base
+		item.CIDR = peer
+		out = append(out, item)
+	}
+	return out
+}
+
+func prefixes(single string, many []string) []string {
+	if single = strings.TrimSpace(single); single != "" {
+		return []string{single}
+	}
+	out := make([]string, 0, len(many))
+	for _, item := range many {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	if len(out) == 0 {
+		return []string{"*"}
+	}
+	return out
+}
+
+func portRanges(single string, many []string) string {
+	ranges := prefixes(single, many)
+	if len(ranges) == 1 && ranges[0] == "*" {
+		return "all"
+	}
+	return strings.Join(ranges, ",")
+}
+
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol == "*" || protocol == "" {
+		return "all"
+	}
+	return protocol
+}
+
+func resourceName(id string) string {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	return parts[len(parts)-1]
+}
+
+// subnetName turns .../virtualNetworks/<vnet>/subnets/<subnet> into
+// <vnet>/<subnet>, since subnet names alone are rarely unique.
+func subnetName(id string) string {
+	parts := strings.Split(strings.Trim(id, "/"), "/")
+	if len(parts) < 3 {
+		return id
+	}
+	return parts[len(parts)-3] + "/" + parts[len(parts)-1]
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
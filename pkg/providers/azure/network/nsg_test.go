@@ -0,0 +1,104 @@
+package network
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/cloud"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+const tokenStub = `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`
+
+func newTestDriver(t *testing.T, server *httptest.Server, subs []string) *Driver {
+	t.Helper()
+	httpClient := server.Client()
+	httpClient.Transport = tokenRewriteTransport{base: httpClient.Transport, target: mustParseURL(t, server.URL)}
+	ts := auth.NewTokenSource(auth.New("client", "secret", "tenant", "", auth.CloudPublic), httpClient)
+	client := azapi.NewClient(ts, cloud.For(auth.CloudPublic), azapi.WithHTTPClient(httpClient), azapi.WithBaseURL(server.URL))
+	return &Driver{Client: client, SubscriptionIDs: subs}
+}
+
+type tokenRewriteTransport struct {
+	base   http.RoundTripper
+	target *url.URL
+}
+
+func (rt tokenRewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "login.microsoftonline.com" {
+		clone := req.Clone(req.Context())
+		clone.URL.Scheme = rt.target.Scheme
+		clone.URL.Host = rt.target.Host
+		clone.Host = rt.target.Host
+		return rt.base.RoundTrip(clone)
+	}
+	return rt.base.RoundTrip(req)
+}
+
+func mustParseURL(t *testing.T, raw string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	return u
+}
+
+const sampleNSGs = `{"value":[
+  {"id":"/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.Network/networkSecurityGroups/web-nsg",
+   "name":"web-nsg","location":"eastus",
+   "properties":{
+     "securityRules":[
+       {"name":"allow-admin","properties":{"protocol":"Tcp","sourceAddressPrefix":"Internet","destinationPortRanges":["22","3389"],"access":"Allow","priority":100,"direction":"Inbound"}},
+       {"name":"allow-web","properties":{"protocol":"*","sourceAddressPrefixes":["10.0.0.0/8","192.168.0.0/16"],"destinationPortRange":"443","access":"Allow","priority":110,"direction":"Inbound","description":"internal https"}},
+       {"name":"deny-out","properties":{"protocol":"*","destinationAddressPrefix":"Internet","destinationPortRange":"*","access":"Deny","priority":4000,"direction":"Outbound"}}
+     ],
+     "networkInterfaces":[{"id":"/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.Network/networkInterfaces/web-nic"}],
+     "subnets":[{"id":"/subscriptions/sub-1/resourceGroups/rg-1/providers/Microsoft.Network/virtualNetworks/vnet-1/subnets/web"}]
+   }}
+]}`
+
+func TestGetSecurityGroupsMapsNSGRules(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tenant/oauth2/v2.0/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(tokenStub))
+		case "/subscriptions/sub-1/providers/Microsoft.Network/networkSecurityGroups":
+			_, _ = w.Write([]byte(sampleNSGs))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	groups, err := newTestDriver(t, server, []string{"sub-1"}).GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups: %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected one NSG, got %d", len(groups))
+	}
+	group := groups[0]
+	if group.GroupID != "rg-1/web-nsg" || group.Region != "eastus" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if !reflect.DeepEqual(group.Attached, []string{"nic:web-nic", "subnet:vnet-1/web"}) {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	want := []schema.FirewallRule{
+		{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "22,3389", CIDR: "Internet", Priority: "100", Description: "allow-admin", Exposed: true},
+		{Direction: "ingress", Action: "allow", Protocol: "all", PortRange: "443", CIDR: "10.0.0.0/8", Priority: "110", Description: "internal https"},
+		{Direction: "ingress", Action: "allow", Protocol: "all", PortRange: "443", CIDR: "192.168.0.0/16", Priority: "110", Description: "internal https"},
+		{Direction: "egress", Action: "deny", Protocol: "all", PortRange: "all", CIDR: "Internet", Priority: "4000", Description: "deny-out"},
+	}
+	if !reflect.DeepEqual(group.Rules, want) {
+		t.Fatalf("unexpected rules:\n got %+v\nwant %+v", group.Rules, want)
+	}
+}
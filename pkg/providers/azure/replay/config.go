@@ -4,14 +4,23 @@ import (
 	"net/http"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 // ClientConfig builds the demo replay configuration injected into
 // azure.NewWithConfig when replay is active for the azure provider.
 func ClientConfig() azure.ClientConfig {
-	transport := newTransport()
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every azure client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) azure.ClientConfig {
+	httpClient = telemetry.WrapClient("azure", httpClient)
 	return azure.ClientConfig{
-		HTTPClient:          &http.Client{Transport: transport},
-		SkipCredentialCache: true,
+		HTTPClient: httpClient,
 	}
 }
@@ -52,6 +52,33 @@ var demoStorageAccounts = []storageAccountFixture{
 	},
 }
 
+// demoStorageAccountKey is the base64 key returned by listKeys; the blob
+// handler verifies Shared Key signatures against it.
+const demoStorageAccountKey = "Y3RrLWRlbW8tc3RvcmFnZS1hY2NvdW50LWtleS1yZXBsYXktMDAwMDAwMDAwMA=="
+
+type blobFixture struct {
+	Name         string
+	Size         int64
+	LastModified string
+	AccessTier   string
+}
+
+// demoBlobs is keyed "account/container". exports spans several replay
+// pages so bucket-check follows NextMarker.
+var demoBlobs = map[string][]blobFixture{
+	"ctkdemologs/audit": {
+		{Name: "insights-logs/2026/04/30/activity.json", Size: 48213, LastModified: "Thu, 30 Apr 2026 09:12:44 GMT", AccessTier: "Hot"},
+		{Name: "insights-logs/2026/05/01/activity.json", Size: 51877, LastModified: "Fri, 01 May 2026 09:10:02 GMT", AccessTier: "Hot"},
+	},
+	"ctkdemologs/exports": {
+		{Name: "billing/2026-03.csv", Size: 183204, LastModified: "Wed, 01 Apr 2026 02:00:11 GMT", AccessTier: "Cool"},
+		{Name: "billing/2026-04.csv", Size: 190877, LastModified: "Fri, 01 May 2026 02:00:09 GMT", AccessTier: "Cool"},
+		{Name: "customers/ctk-demo-customers.csv", Size: 2097152, LastModified: "Mon, 27 Apr 2026 16:41:30 GMT", AccessTier: "Hot"},
+		{Name: "db-backups/ctk-demo-sql-20260429.bacpac", Size: 73400320, LastModified: "Wed, 29 Apr 2026 23:58:02 GMT", AccessTier: "Cool"},
+		{Name: "db-backups/ctk-demo-sql-20260430.bacpac", Size: 73924608, LastModified: "Thu, 30 Apr 2026 23:57:48 GMT", AccessTier: "Cool"},
+	},
+}
+
 // demoRoleDefinitions is a small, fixed catalog of the most commonly assigned
 // built-in roles. The replay returns only entries with names that match the
 // `$filter=roleName eq '...'` filter on roleDefinitions.
@@ -216,12 +243,14 @@ type graphPasswordFixture struct {
 }
 
 type graphUserFixture struct {
-	ID                 string
-	DisplayName        string
-	UserPrincipalName  string
-	AccountEnabled     bool
-	CreatedDateTime    string
-	LastSignInDateTime string
+	ID                         string
+	DisplayName                string
+	UserPrincipalName          string
+	AccountEnabled             bool
+	CreatedDateTime            string
+	LastSignInDateTime         string
+	LastPasswordChangeDateTime string
+	MFARegistered              bool
 }
 
 var demoGraphApplications = []graphApplicationFixture{
@@ -234,19 +263,22 @@ var demoGraphApplications = []graphApplicationFixture{
 
 var demoGraphUsers = []graphUserFixture{
 	{
-		ID:                 "user-1111-2222-3333-444455556666",
-		DisplayName:        "ctk demo operator",
-		UserPrincipalName:  "ctk-demo-operator@example.onmicrosoft.com",
-		AccountEnabled:     true,
-		CreatedDateTime:    "2026-04-20T08:00:00Z",
-		LastSignInDateTime: "2026-04-30T09:00:00Z",
+		ID:                         "user-1111-2222-3333-444455556666",
+		DisplayName:                "ctk demo operator",
+		UserPrincipalName:          "ctk-demo-operator@example.onmicrosoft.com",
+		AccountEnabled:             true,
+		CreatedDateTime:            "2026-04-20T08:00:00Z",
+		LastSignInDateTime:         "2026-04-30T09:00:00Z",
+		LastPasswordChangeDateTime: "2026-04-20T08:00:00Z",
 	},
 	{
-		ID:                "user-2222-3333-4444-555566667777",
-		DisplayName:       "ctk readonly",
-		UserPrincipalName: "ctk-readonly@example.onmicrosoft.com",
-		AccountEnabled:    false,
-		CreatedDateTime:   "2026-04-21T08:00:00Z",
+		ID:                         "user-2222-3333-4444-555566667777",
+		DisplayName:                "ctk readonly",
+		UserPrincipalName:          "ctk-readonly@example.onmicrosoft.com",
+		AccountEnabled:             false,
+		CreatedDateTime:            "2026-04-21T08:00:00Z",
+		LastPasswordChangeDateTime: "2026-04-21T08:00:00Z",
+		MFARegistered:              true,
 	},
 }
 
@@ -170,6 +170,8 @@ func (t *transport) handleSubscriptionProvider(req *http.Request, subscription s
 		return t.handleActivityLog(req, subscription)
 	case strings.EqualFold(provider, "Microsoft.Network") && len(rest) >= 1 && rest[0] == "dnsZones":
 		return t.handleListDNSZones(req, subscription)
+	case strings.EqualFold(provider, "Microsoft.Network") && len(rest) == 1 && rest[0] == "networkSecurityGroups":
+		return t.handleListNSGs(req, subscription)
 	case strings.EqualFold(provider, "Microsoft.Sql") && len(rest) == 1 && rest[0] == "servers":
 		return t.handleListSQLServers(req, subscription)
 	case strings.EqualFold(provider, "Microsoft.OperationalInsights") && len(rest) == 1 && rest[0] == "workspaces":
@@ -346,6 +348,9 @@ func (t *transport) handleStorageScoped(req *http.Request, subscription, group s
 			fmt.Sprintf("storage account %s not found in %s", accountName, group)), nil
 	}
 	rest := parts[1:]
+	if len(rest) == 1 && rest[0] == "listKeys" {
+		return t.handleListStorageAccountKeys(req)
+	}
 	if len(rest) >= 1 && rest[0] == "blobServices" {
 		// blobServices, blobServices/default, blobServices/default/containers, blobServices/default/containers/{name}
 		switch {
@@ -699,6 +704,8 @@ func (t *transport) handleGraph(req *http.Request, body []byte) (*http.Response,
 		return t.handleGraphCreateUser(req, body)
 	case len(parts) == 2 && parts[0] == "users" && req.Method == http.MethodDelete:
 		return t.handleGraphDeleteUser(req, parts[1])
+	case len(parts) == 3 && parts[0] == "reports" && parts[1] == "authenticationMethods" && parts[2] == "userRegistrationDetails":
+		return t.handleGraphUserRegistrationDetails(req)
 	}
 	return graphErrorResponse(req, http.StatusNotFound, "InvalidGraphPath",
 		"unsupported graph path: "+req.URL.Path), nil
@@ -745,6 +752,18 @@ func (t *transport) handleGraphListUsers(req *http.Request) (*http.Response, err
 	return jsonResponse(req, map[string]any{"value": users}), nil
 }
 
+func (t *transport) handleGraphUserRegistrationDetails(req *http.Request) (*http.Response, error) {
+	details := make([]map[string]any, 0, len(demoGraphUsers))
+	for _, user := range demoGraphUsers {
+		details = append(details, map[string]any{
+			"id":                user.ID,
+			"userPrincipalName": user.UserPrincipalName,
+			"isMfaRegistered":   user.MFARegistered,
+		})
+	}
+	return jsonResponse(req, map[string]any{"value": details}), nil
+}
+
 func (t *transport) handleGraphDeleteUser(req *http.Request, idOrUPN string) (*http.Response, error) {
 	// Lenient: removeGraphUser returns false if the user wasn't seen in this
 	// session, but we still want to surface a 204 because demo flows might
@@ -785,6 +804,9 @@ func buildGraphUser(user graphUserFixture) map[string]any {
 		"userPrincipalName": user.UserPrincipalName,
 		"createdDateTime":   user.CreatedDateTime,
 	}
+	if strings.TrimSpace(user.LastPasswordChangeDateTime) != "" {
+		out["lastPasswordChangeDateTime"] = user.LastPasswordChangeDateTime
+	}
 	if strings.TrimSpace(user.LastSignInDateTime) != "" {
 		out["signInActivity"] = map[string]any{
 			"lastSignInDateTime": user.LastSignInDateTime,
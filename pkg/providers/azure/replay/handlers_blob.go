@@ -0,0 +1,142 @@
+package replay
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/blob"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+// replayBlobPageSize caps List Blobs pages well below the real 5000 so the
+// demo exercises marker pagination.
+const replayBlobPageSize = 2
+
+func (t *transport) handleListStorageAccountKeys(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodPost {
+		return armErrorResponse(req, http.StatusMethodNotAllowed, "MethodNotAllowed",
+			"listKeys requires POST"), nil
+	}
+	resp := azapi.ListStorageAccountKeysResponse{Keys: []azapi.StorageAccountKey{
+		{KeyName: "key1", Value: demoStorageAccountKey, Permissions: "FULL"},
+	}}
+	return jsonResponse(req, resp), nil
+}
+
+type blobEnumerationResults struct {
+	XMLName         xml.Name        `xml:"EnumerationResults"`
+	ServiceEndpoint string          `xml:"ServiceEndpoint,attr"`
+	ContainerName   string          `xml:"ContainerName,attr"`
+	Marker          string          `xml:"Marker,omitempty"`
+	MaxResults      int             `xml:"MaxResults,omitempty"`
+	Blobs           []blobListEntry `xml:"Blobs>Blob"`
+	NextMarker      string          `xml:"NextMarker"`
+}
+
+type blobListEntry struct {
+	Name       string             `xml:"Name"`
+	Properties blobListProperties `xml:"Properties"`
+}
+
+type blobListProperties struct {
+	LastModified  string `xml:"Last-Modified"`
+	ContentLength int64  `xml:"Content-Length"`
+	BlobType      string `xml:"BlobType"`
+	AccessTier    string `xml:"AccessTier"`
+}
+
+type blobErrorBody struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+// handleBlob serves List Blobs (GET /{container}?restype=container&comp=list)
+// for the demo storage accounts, authorized by Shared Key or bearer token.
+func (t *transport) handleBlob(req *http.Request, account string) (*http.Response, error) {
+	if _, ok := storageAccountByName(account); !ok {
+		return blobErrorResponse(req, http.StatusNotFound, "ResourceNotFound",
+			"The specified resource does not exist."), nil
+	}
+	if !verifyBlobAuthorization(req, account) {
+		return blobErrorResponse(req, http.StatusForbidden, "AuthorizationFailure",
+			"This request is not authorized to perform this operation."), nil
+	}
+	query := req.URL.Query()
+	container := strings.Trim(req.URL.Path, "/")
+	if req.Method != http.MethodGet || query.Get("restype") != "container" || query.Get("comp") != "list" || strings.Contains(container, "/") {
+		return blobErrorResponse(req, http.StatusBadRequest, "UnsupportedQueryParameter",
+			fmt.Sprintf("unsupported replay blob request: %s %s?%s", req.Method, req.URL.Path, req.URL.RawQuery)), nil
+	}
+	blobs, ok := demoBlobs[account+"/"+container]
+	if !ok {
+		if account, _ := storageAccountByName(account); !containerExists(account, container) {
+			return blobErrorResponse(req, http.StatusNotFound, "ContainerNotFound",
+				"The specified container does not exist."), nil
+		}
+	}
+
+	pageSize := replayBlobPageSize
+	if n, err := strconv.Atoi(query.Get("maxresults")); err == nil && n > 0 && n < pageSize {
+		pageSize = n
+	}
+	start := 0
+	if marker := query.Get("marker"); marker != "" {
+		n, err := strconv.Atoi(strings.TrimPrefix(marker, "ctk-marker-"))
+		if err != nil || n < 0 || n > len(blobs) {
+			return blobErrorResponse(req, http.StatusBadRequest, "OutOfRangeInput",
+				"One of the request inputs is out of range."), nil
+		}
+		start = n
+	}
+	end := min(start+pageSize, len(blobs))
+
+	resp := blobEnumerationResults{
+		ServiceEndpoint: fmt.Sprintf("https://%s/", req.URL.Host),
+		ContainerName:   container,
+		Marker:          query.Get("marker"),
+		MaxResults:      pageSize,
+		Blobs:           make([]blobListEntry, 0, end-start),
+	}
+	for _, b := range blobs[start:end] {
+		resp.Blobs = append(resp.Blobs, blobListEntry{
+			Name: b.Name,
+			Properties: blobListProperties{
+				LastModified:  b.LastModified,
+				ContentLength: b.Size,
+				BlobType:      "BlockBlob",
+				AccessTier:    b.AccessTier,
+			},
+		})
+	}
+	if end < len(blobs) {
+		resp.NextMarker = fmt.Sprintf("ctk-marker-%d", end)
+	}
+	out := demoreplay.XMLResponse(req, http.StatusOK, resp)
+	out.Header.Set("x-ms-request-id", "req-replay-azure-blob")
+	return out, nil
+}
+
+func verifyBlobAuthorization(req *http.Request, account string) bool {
+	header := strings.TrimSpace(req.Header.Get("Authorization"))
+	if strings.HasPrefix(header, "Bearer ") {
+		return verifyBearerToken(req)
+	}
+	signed, ok := strings.CutPrefix(header, "SharedKey "+account+":")
+	if !ok {
+		return false
+	}
+	want, err := blob.SharedKeySignature(req, account, demoStorageAccountKey)
+	return err == nil && demoreplay.SubtleEqual(signed, want)
+}
+
+func blobErrorResponse(req *http.Request, statusCode int, code, message string) *http.Response {
+	resp := demoreplay.XMLResponse(req, statusCode, blobErrorBody{Code: code, Message: message})
+	resp.Header.Set("x-ms-error-code", code)
+	resp.Header.Set("x-ms-request-id", "req-replay-azure-blob")
+	return resp
+}
@@ -0,0 +1,75 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+)
+
+// nsgFixture is one network security group. NICs reference demoVMs by NIC
+// name and Subnets are `<vnet>/<subnet>` pairs.
+type nsgFixture struct {
+	Name    string
+	NICs    []string
+	Subnets []string
+	Rules   []azapi.SecurityRule
+}
+
+var demoNSGs = []nsgFixture{
+	{
+		Name: "ctk-demo-bastion-nsg",
+		NICs: []string{"ctk-demo-bastion-nic"},
+		Rules: []azapi.SecurityRule{
+			{Name: "allow-ssh-any", Properties: azapi.SecurityRuleProps{
+				Protocol: "Tcp", SourceAddressPrefix: "*", DestinationPortRange: "22",
+				Access: "Allow", Priority: 100, Direction: "Inbound", Description: "bastion ssh",
+			}},
+			{Name: "allow-https", Properties: azapi.SecurityRuleProps{
+				Protocol: "Tcp", SourceAddressPrefix: "Internet", DestinationPortRange: "443",
+				Access: "Allow", Priority: 110, Direction: "Inbound",
+			}},
+		},
+	},
+	{
+		Name:    "ctk-demo-app-nsg",
+		Subnets: []string{"ctk-demo-vnet/app"},
+		Rules: []azapi.SecurityRule{
+			{Name: "allow-app-from-bastion", Properties: azapi.SecurityRuleProps{
+				Protocol: "Tcp", SourceAddressPrefix: "10.0.1.10/32", DestinationPortRange: "8080",
+				Access: "Allow", Priority: 100, Direction: "Inbound",
+			}},
+			{Name: "deny-internet-egress", Properties: azapi.SecurityRuleProps{
+				Protocol: "*", DestinationAddressPrefix: "Internet", DestinationPortRange: "*",
+				Access: "Deny", Priority: 4000, Direction: "Outbound",
+			}},
+		},
+	},
+}
+
+func (t *transport) handleListNSGs(req *http.Request, subscription string) (*http.Response, error) {
+	prefix := fmt.Sprintf("/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Network", subscription, demoResourceGroup)
+	resp := struct {
+		Value []azapi.NetworkSecurityGroup `json:"value"`
+	}{}
+	for _, fixture := range demoNSGs {
+		nsg := azapi.NetworkSecurityGroup{
+			ID:       prefix + "/networkSecurityGroups/" + fixture.Name,
+			Name:     fixture.Name,
+			Location: demoLocation,
+			Properties: azapi.NetworkSecurityGroupProps{
+				SecurityRules: fixture.Rules,
+			},
+		}
+		for _, nic := range fixture.NICs {
+			nsg.Properties.NetworkInterfaces = append(nsg.Properties.NetworkInterfaces, azapi.ResourceRef{ID: prefix + "/networkInterfaces/" + nic})
+		}
+		for _, subnet := range fixture.Subnets {
+			vnet, name, _ := strings.Cut(subnet, "/")
+			nsg.Properties.Subnets = append(nsg.Properties.Subnets, azapi.ResourceRef{ID: prefix + "/virtualNetworks/" + vnet + "/subnets/" + name})
+		}
+		resp.Value = append(resp.Value, nsg)
+	}
+	return jsonResponse(req, resp), nil
+}
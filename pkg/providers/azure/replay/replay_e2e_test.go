@@ -0,0 +1,65 @@
+package replay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+// TestReplayE2E_BucketDump drives bucket-check through the Shared Key path:
+// listKeys on ARM, then signed List Blobs calls that page via NextMarker.
+func TestReplayE2E_BucketDump(t *testing.T) {
+	provider := newReplayProvider(t)
+	ctx := context.Background()
+
+	listed, err := provider.BucketDump(ctx, "list", "exports")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(listed) != 1 || listed[0].BucketName != "ctkdemologs/exports" {
+		t.Fatalf("unexpected list results: %+v", listed)
+	}
+	if got, want := len(listed[0].Objects), len(demoBlobs["ctkdemologs/exports"]); got != want {
+		t.Fatalf("expected %d objects across pages, got %d", want, got)
+	}
+	if obj := listed[0].Objects[2]; obj.Key != "customers/ctk-demo-customers.csv" || obj.Size != 2097152 || obj.StorageClass != "Hot" {
+		t.Fatalf("unexpected object mapping: %+v", obj)
+	}
+
+	totals, err := provider.BucketDump(ctx, "total", "all")
+	if err != nil {
+		t.Fatalf("total: %v", err)
+	}
+	counts := map[string]int64{}
+	for _, r := range totals {
+		counts[r.BucketName] = r.ObjectCount
+	}
+	if counts["ctkdemologs/audit"] != 2 || counts["ctkdemologs/exports"] != 5 {
+		t.Fatalf("unexpected totals: %+v", counts)
+	}
+
+	if _, err := provider.BucketDump(ctx, "list", "ctkdemologs/missing"); err == nil {
+		t.Fatalf("expected error for unknown container")
+	}
+	if _, err := provider.BucketDump(ctx, "purge", "all"); err == nil {
+		t.Fatalf("expected error for unknown action")
+	}
+}
+
+func newReplayProvider(t *testing.T) *azure.Provider {
+	t.Helper()
+	options := schema.Options{
+		utils.AzureClientId:       demoCredentials.AccessKey,
+		utils.AzureClientSecret:   demoCredentials.SecretKey,
+		utils.AzureTenantId:       demoTenantID,
+		utils.AzureSubscriptionId: demoSubscriptionID,
+	}
+	provider, err := azure.NewWithConfig(options, ClientConfig())
+	if err != nil {
+		t.Fatalf("NewWithConfig: %v", err)
+	}
+	return provider
+}
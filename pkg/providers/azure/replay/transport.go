@@ -52,6 +52,8 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 				"The access token is invalid."), nil
 		}
 		return t.handleGraph(req, body)
+	case isBlobHost(host):
+		return t.handleBlob(req, strings.TrimSuffix(host, blobHostSuffix(host)))
 	}
 	return armErrorResponse(req, http.StatusNotFound, "InvalidEndpoint",
 		fmt.Sprintf("unsupported replay host: %s", host)), nil
@@ -65,6 +67,27 @@ func isGraphHost(host string) bool {
 		host == "graph.microsoft.de"
 }
 
+var blobHostSuffixes = []string{
+	".blob.core.windows.net",
+	".blob.core.chinacloudapi.cn",
+	".blob.core.usgovcloudapi.net",
+	".blob.core.cloudapi.de",
+}
+
+func isBlobHost(host string) bool {
+	return blobHostSuffix(host) != ""
+}
+
+func blobHostSuffix(host string) string {
+	host = normalizeHost(host)
+	for _, suffix := range blobHostSuffixes {
+		if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
 func isLoginHost(host string) bool {
 	host = normalizeHost(host)
 	return host == "login.microsoftonline.com" ||
@@ -14,6 +14,6 @@ func init() {
 			{Name: utils.AzureSubscriptionId, Description: "Subscription ID"},
 			{Name: utils.Version, Description: "International or custom edition"},
 		},
-		Capabilities: []string{"cloudlist", "iam-role", "bucket-acl", "iam-credential", "event", "database", "iam", "vm"},
+		Capabilities: []string{"cloudlist", "iam-role", "bucket-acl", "iam-credential", "event", "database", "iam", "vm", "bucket", "iam-posture"},
 	})
 }
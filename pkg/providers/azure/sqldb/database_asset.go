@@ -16,7 +16,8 @@ import (
 // surfaces them as the cloudlist `database` asset. SQL Database resources
 // are nested under each server; we surface one row per server with the
 // fully-qualified domain name as Address — that matches CSPM signal value
-// (one server = one externally-reachable endpoint).
+// (one server = one externally-reachable endpoint). The ID is the
+// `<resourceGroup>/<server>` form DBManagement accepts.
 func (d *Driver) GetDatabases(ctx context.Context) ([]schema.Database, error) {
 	out := []schema.Database{}
 	if d == nil || d.Client == nil {
@@ -31,7 +32,7 @@ func (d *Driver) GetDatabases(ctx context.Context) ([]schema.Database, error) {
 		}
 		for _, s := range servers {
 			out = append(out, schema.Database{
-				InstanceId:    s.Name,
+				InstanceId:    serverInstanceID(s),
 				Engine:        "Microsoft.Sql",
 				EngineVersion: s.Properties.Version,
 				Region:        s.Location,
@@ -65,3 +66,13 @@ func networkType(s azapi.SQLServer) string {
 	}
 	return "Public"
 }
+
+// serverInstanceID returns `<resourceGroup>/<server>` for s, or the bare
+// server name when its ARM id cannot be parsed.
+func serverInstanceID(s azapi.SQLServer) string {
+	res, err := azapi.ParseResourceID(s.ID)
+	if err != nil || res.ResourceGroup == "" {
+		return s.Name
+	}
+	return res.ResourceGroup + "/" + s.Name
+}
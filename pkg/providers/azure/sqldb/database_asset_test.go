@@ -78,7 +78,7 @@ func TestGetDatabasesListsServers(t *testing.T) {
 	if len(dbs) != 2 {
 		t.Fatalf("expected 2 servers, got %d", len(dbs))
 	}
-	if dbs[0].InstanceId != "ctk-prod-sql" || dbs[0].Region != "eastus" {
+	if dbs[0].InstanceId != "rg-1/ctk-prod-sql" || dbs[0].Region != "eastus" {
 		t.Errorf("unexpected first db: %+v", dbs[0])
 	}
 	if dbs[0].NetworkType != "Public" || dbs[1].NetworkType != "Private" {
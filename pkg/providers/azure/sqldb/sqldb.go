@@ -40,7 +40,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -119,8 +119,8 @@ func splitInstanceID(instanceID string) (string, string, error) {
 	return parts[0], parts[1], nil
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -46,6 +48,33 @@ type ContainerInfo struct {
 	AccountName   string
 	Name          string
 	PublicAccess  string
+	// AccountPublicAccess is the account's allowBlobPublicAccess; when false
+	// Azure refuses anonymous requests whatever PublicAccess says.
+	AccountPublicAccess bool
+}
+
+// ACLEntry returns the container's audit entry. Blob and Container levels
+// both allow anonymous blob reads (Container also lists them), unless the
+// account disallows public access.
+func (c ContainerInfo) ACLEntry() schema.BucketACLEntry {
+	entry := schema.BucketACLEntry{
+		Account:   c.AccountName,
+		Container: c.Name,
+		Level:     c.PublicAccess,
+	}
+	var grant exposure.Grant
+	switch strings.ToLower(c.PublicAccess) {
+	case "blob", "container":
+		grant = exposure.Grant{Read: true, Reasons: []string{"container publicAccess " + c.PublicAccess}}
+	}
+	var verdict exposure.Verdict
+	if c.AccountPublicAccess {
+		verdict.Add("container access level", grant)
+	} else {
+		verdict.Block("container access level", grant, "account allowBlobPublicAccess is false")
+	}
+	verdict.Apply(&entry)
+	return entry
 }
 
 // ListBlobContainers returns containers across every configured subscription
@@ -91,11 +120,12 @@ func (d *Driver) ListBlobContainers(ctx context.Context) ([]ContainerInfo, error
 					level = container.Properties.PublicAccess
 				}
 				out = append(out, ContainerInfo{
-					Subscription:  subscription,
-					ResourceGroup: parsed.ResourceGroup,
-					AccountName:   account.Name,
-					Name:          container.Name,
-					PublicAccess:  level,
+					Subscription:        subscription,
+					ResourceGroup:       parsed.ResourceGroup,
+					AccountName:         account.Name,
+					Name:                container.Name,
+					PublicAccess:        level,
+					AccountPublicAccess: allowsBlobPublicAccess(account),
 				})
 			}
 		}
@@ -103,6 +133,13 @@ func (d *Driver) ListBlobContainers(ctx context.Context) ([]ContainerInfo, error
 	return out, nil
 }
 
+func allowsBlobPublicAccess(account azapi.StorageAccount) bool {
+	if account.Properties == nil || account.Properties.AllowBlobPublicAccess == nil {
+		return true
+	}
+	return *account.Properties.AllowBlobPublicAccess
+}
+
 // FindContainer locates a container by name across every configured
 // subscription. Returns the first match.
 func (d *Driver) FindContainer(ctx context.Context, name string) (ContainerInfo, error) {
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/blob"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// FindContainers resolves a bucket-check target: "all", a container name
+// (every account holding a container of that name), or "account/container".
+func (d *Driver) FindContainers(ctx context.Context, target string) ([]ContainerInfo, error) {
+	target = strings.TrimSpace(target)
+	containers, err := d.ListBlobContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if target == "all" {
+		return containers, nil
+	}
+	account, name, scoped := strings.Cut(target, "/")
+	if !scoped {
+		account, name = "", target
+	}
+	out := make([]ContainerInfo, 0, 1)
+	for _, c := range containers {
+		if c.Name == name && (account == "" || c.AccountName == account) {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("container %q not found", target)
+	}
+	return out, nil
+}
+
+// ListObjects returns every blob of each container.
+func (d *Driver) ListObjects(ctx context.Context, containers []ContainerInfo) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, containers, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each container's markers and hands the blobs matching
+// q to visit.
+func (d *Driver) WalkObjects(ctx context.Context, containers []ContainerInfo, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	results := []schema.BucketResult{}
+	authorizers := make(map[string]blob.Authorizer)
+	for _, c := range containers {
+		bucket := c.AccountName + "/" + c.Name
+		walk := schema.NewObjectWalk(q, visit)
+		var visitErr error
+		err := d.walkBlobs(ctx, authorizers, c, q.Prefix, blob.MaxPageSize, func(page blob.Page) bool {
+			for _, obj := range page.Objects {
+				more, err := walk.Add(schema.BucketObject{
+					BucketName:   bucket,
+					Key:          obj.Name,
+					Size:         obj.Size,
+					LastModified: obj.LastModified,
+					StorageClass: obj.AccessTier,
+				})
+				if err != nil {
+					visitErr = err
+					return false
+				}
+				if !more {
+					return false
+				}
+			}
+			return true
+		})
+		if visitErr != nil {
+			return results, visitErr
+		}
+		if err != nil {
+			return results, fmt.Errorf("list objects in %s: %w", bucket, err)
+		}
+		results = append(results, walk.Result(bucket))
+
+		select {
+		case <-ctx.Done():
+			return results, ctx.Err()
+		default:
+		}
+	}
+	return results, nil
+}
+
+// TotalObjects counts every blob in each container.
+func (d *Driver) TotalObjects(ctx context.Context, containers []ContainerInfo) ([]schema.BucketResult, error) {
+	tracker := processbar.NewCountTracker()
+	defer tracker.Finish()
+
+	results := []schema.BucketResult{}
+	authorizers := make(map[string]blob.Authorizer)
+	for _, c := range containers {
+		bucket := c.AccountName + "/" + c.Name
+		count := 0
+		err := d.walkBlobs(ctx, authorizers, c, "", blob.MaxPageSize, func(page blob.Page) bool {
+			count += len(page.Objects)
+			tracker.Update(bucket, count)
+			return true
+		})
+		if err != nil {
+			return results, fmt.Errorf("list objects in %s: %w", bucket, err)
+		}
+		results = append(results, schema.BucketResult{
+			Action:      "total",
+			BucketName:  bucket,
+			ObjectCount: int64(count),
+			Message:     fmt.Sprintf("%d objects", count),
+		})
+	}
+	return results, nil
+}
+
+// walkBlobs follows NextMarker until the last page or until fn returns
+// false. The first page decides the account's authorizer: shared key when
+// listKeys is allowed, falling back to the AAD token on a 403.
+func (d *Driver) walkBlobs(ctx context.Context, authorizers map[string]blob.Authorizer, c ContainerInfo, prefix string, pageSize int, fn func(blob.Page) bool) error {
+	if d.Blob == nil {
+		return fmt.Errorf("azure storage: nil blob client")
+	}
+	authz, cached := authorizers[c.AccountName]
+	if !cached {
+		authz = d.accountAuthorizer(ctx, c)
+	}
+	marker := ""
+	for {
+		page, err := d.Blob.ListBlobs(ctx, authz, c.AccountName, c.Name, prefix, marker, pageSize)
+		if err != nil && !cached && blob.IsAuthorizationError(err) {
+			if _, shared := authz.(blob.SharedKey); shared && d.BlobToken != nil {
+				authz = blob.Bearer{Source: d.BlobToken}
+				page, err = d.Blob.ListBlobs(ctx, authz, c.AccountName, c.Name, prefix, marker, pageSize)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		authorizers[c.AccountName], cached = authz, true
+		if !fn(page) || page.NextMarker == "" {
+			return nil
+		}
+		marker = page.NextMarker
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+func (d *Driver) accountAuthorizer(ctx context.Context, c ContainerInfo) blob.Authorizer {
+	key, err := d.AccountKey(ctx, c.Subscription, c.ResourceGroup, c.AccountName)
+	if err == nil && key != "" {
+		return blob.SharedKey{Key: key}
+	}
+	return blob.Bearer{Source: d.BlobToken}
+}
+
+// AccountKey returns the first storage account key via the ARM listKeys
+// action (needs Microsoft.Storage/storageAccounts/listkeys/action).
+func (d *Driver) AccountKey(ctx context.Context, subscription, group, account string) (string, error) {
+	if d == nil || d.Client == nil {
+		return "", fmt.Errorf("azure storage: nil client")
+	}
+	var resp azapi.ListStorageAccountKeysResponse
+	if err := d.Client.Do(ctx, azapi.Request{
+		Method: http.MethodPost,
+		Path: fmt.Sprintf(
+			"/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s/listKeys",
+			subscription, group, account,
+		),
+		Query: url.Values{"api-version": {azapi.StorageAPIVersion}},
+	}, &resp); err != nil {
+		return "", err
+	}
+	for _, key := range resp.Keys {
+		if key.Value != "" {
+			return key.Value, nil
+		}
+	}
+	return "", fmt.Errorf("storage account %s returned no keys", account)
+}
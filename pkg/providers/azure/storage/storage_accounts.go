@@ -7,6 +7,8 @@ import (
 	"net/url"
 
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/blob"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
@@ -14,6 +16,10 @@ import (
 type Driver struct {
 	Client          *azapi.Client
 	SubscriptionIDs []string
+	// Blob and BlobToken back bucket-check; BlobToken is the storage-scoped
+	// fallback when shared key access is unavailable.
+	Blob      *blob.Client
+	BlobToken *auth.TokenSource
 }
 
 func (d *Driver) GetStorages(ctx context.Context) ([]schema.Storage, error) {
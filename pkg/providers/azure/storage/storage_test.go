@@ -3,15 +3,19 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/auth"
+	"github.com/404tk/cloudtoolkit/pkg/providers/azure/blob"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure/cloud"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func TestDriverGetStoragesFollowsPagination(t *testing.T) {
@@ -160,6 +164,46 @@ func TestNormalizePublicAccess(t *testing.T) {
 	}
 }
 
+func TestWalkObjectsReturnsContextErrorWhenCancelled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/tenant/oauth2/v2.0/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"access_token":"tok","expires_in":3600,"token_type":"Bearer"}`)
+		case strings.HasSuffix(r.URL.Path, "/listKeys"):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"keys":[{"keyName":"key1","value":"c2VjcmV0"}]}`)
+		case r.URL.Query().Get("comp") == "list":
+			_, _ = io.WriteString(w, `<EnumerationResults><Blobs><Blob><Name>a.txt</Name><Properties><Content-Length>1</Content-Length></Properties></Blob></Blobs><NextMarker /></EnumerationResults>`)
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(t, server, []string{"sub-1"})
+	blobHTTP := server.Client()
+	blobHTTP.Transport = hostRewriteTransport{base: blobHTTP.Transport, target: mustParseURL(t, server.URL)}
+	driver.Blob = blob.NewClient(blobHTTP, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	containers := []ContainerInfo{
+		{Subscription: "sub-1", ResourceGroup: "rg", AccountName: "acct", Name: "one"},
+		{Subscription: "sub-1", ResourceGroup: "rg", AccountName: "acct", Name: "two"},
+	}
+	got, err := driver.WalkObjects(ctx, containers, schema.ObjectQuery{}, func(schema.BucketObject) error {
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WalkObjects() error = %v, want %v", err, context.Canceled)
+	}
+	if len(got) != 1 || got[0].ObjectCount != 1 {
+		t.Fatalf("expected the partial result, got %+v", got)
+	}
+}
+
 // --- helpers ---
 
 func newTestDriver(t *testing.T, server *httptest.Server, subscriptions []string) *Driver {
@@ -195,3 +239,17 @@ func mustParseURL(t *testing.T, raw string) *url.URL {
 	}
 	return u
 }
+
+// hostRewriteTransport sends every request, whatever its host, to target.
+type hostRewriteTransport struct {
+	base   http.RoundTripper
+	target *url.URL
+}
+
+func (rt hostRewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	clone := req.Clone(req.Context())
+	clone.URL.Scheme = rt.target.Scheme
+	clone.URL.Host = rt.target.Host
+	clone.Host = rt.target.Host
+	return rt.base.RoundTrip(clone)
+}
@@ -56,17 +56,65 @@ func WithRetryPolicy(p RetryPolicy) Option {
 	}
 }
 
+// Response is an undecoded API response, for calls whose body is not JSON
+// such as object media downloads.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
 func (c *Client) Do(ctx context.Context, req Request, out any) error {
+	httpResp, body, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := DecodeError(httpResp.StatusCode, body); err != nil {
+		return err
+	}
+	return httpclient.DecodeJSON(httpResp, body, "gcp", out)
+}
+
+// DoRaw sends req like Do but returns the response undecoded. An error
+// status is still returned as an error, alongside the response.
+func (c *Client) DoRaw(ctx context.Context, req Request) (Response, error) {
+	httpResp, body, err := c.send(ctx, req)
+	if err != nil {
+		return Response{}, err
+	}
+	resp := Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: body}
+	return resp, DecodeError(httpResp.StatusCode, body)
+}
+
+// GetPresigned fetches a signed URL. No bearer token is sent: the signature
+// in the URL authorizes the request.
+func (c *Client) GetPresigned(ctx context.Context, rawURL string) (Response, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return Response{}, err
+	}
+	httpResp, body, err := httpclient.Execute(ctx, c.httpClient, c.retryPolicy, httpReq, true)
+	if err != nil {
+		return Response{}, err
+	}
+	resp := Response{StatusCode: httpResp.StatusCode, Header: httpResp.Header, Body: body}
+	return resp, DecodeError(httpResp.StatusCode, body)
+}
+
+func (c *Client) send(ctx context.Context, req Request) (*http.Response, []byte, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 	if c.tokenSource == nil {
-		return fmt.Errorf("gcp client: nil token source")
+		return nil, nil, fmt.Errorf("gcp client: nil token source")
 	}
 
 	token, err := c.tokenSource.Token(ctx)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	method := strings.ToUpper(strings.TrimSpace(req.Method))
@@ -75,7 +123,7 @@ func (c *Client) Do(ctx context.Context, req Request, out any) error {
 	}
 	requestURL, err := resolveURL(req.BaseURL, req.Path, req.Query)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	headers := httpclient.CloneHeader(req.Headers)
@@ -86,18 +134,11 @@ func (c *Client) Do(ctx context.Context, req Request, out any) error {
 
 	httpReq, err := http.NewRequestWithContext(ctx, method, requestURL, bytes.NewReader(req.Body))
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	httpReq.Header = headers.Clone()
 
-	httpResp, body, err := httpclient.Execute(ctx, c.httpClient, c.retryPolicy, httpReq, req.Idempotent)
-	if err != nil {
-		return err
-	}
-	if err := DecodeError(httpResp.StatusCode, body); err != nil {
-		return err
-	}
-	return httpclient.DecodeJSON(httpResp, body, "gcp", out)
+	return httpclient.Execute(ctx, c.httpClient, c.retryPolicy, httpReq, req.Idempotent)
 }
 
 func resolveURL(baseURL, path string, query url.Values) (string, error) {
@@ -4,6 +4,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 func NewHTTPClient() *http.Client {
@@ -19,7 +22,7 @@ func NewHTTPClient() *http.Client {
 		ResponseHeaderTimeout: 15 * time.Second,
 	}
 	return &http.Client{
-		Transport: transport,
+		Transport: ratelimit.Wrap("gcp", telemetry.Wrap("gcp", transport)),
 		Timeout:   60 * time.Second,
 	}
 }
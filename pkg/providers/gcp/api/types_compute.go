@@ -13,6 +13,7 @@ type Instance struct {
 	Zone              string             `json:"zone"`
 	Status            string             `json:"status"`
 	NetworkInterfaces []NetworkInterface `json:"networkInterfaces"`
+	Labels            map[string]string  `json:"labels"`
 }
 
 type NetworkInterface struct {
@@ -77,3 +78,40 @@ type ComputeOperationErrorItem struct {
 	Location string `json:"location"`
 	Message  string `json:"message"`
 }
+
+// SerialPortOutput is one read of `compute.instances.getSerialPortOutput`.
+// Next is the offset to pass as `start` on the following read so the caller
+// only receives output written since.
+type SerialPortOutput struct {
+	Contents string `json:"contents"`
+	Start    int64  `json:"start,string"`
+	Next     int64  `json:"next,string"`
+}
+
+// Firewall is a VPC firewall rule. Compute Engine models each rule as its own
+// resource, applied to instances by network tag or service account.
+type Firewall struct {
+	Name                  string             `json:"name"`
+	Description           string             `json:"description"`
+	Network               string             `json:"network"`
+	Priority              int                `json:"priority"`
+	Direction             string             `json:"direction"`
+	SourceRanges          []string           `json:"sourceRanges"`
+	DestinationRanges     []string           `json:"destinationRanges"`
+	SourceTags            []string           `json:"sourceTags"`
+	TargetTags            []string           `json:"targetTags"`
+	TargetServiceAccounts []string           `json:"targetServiceAccounts"`
+	Allowed               []FirewallProtocol `json:"allowed"`
+	Denied                []FirewallProtocol `json:"denied"`
+	Disabled              bool               `json:"disabled"`
+}
+
+type FirewallProtocol struct {
+	IPProtocol string   `json:"IPProtocol"`
+	Ports      []string `json:"ports"`
+}
+
+type ListFirewallsResponse struct {
+	Items         []Firewall `json:"items"`
+	NextPageToken string     `json:"nextPageToken"`
+}
@@ -9,6 +9,14 @@ type GCSBucket struct {
 	StorageClass string `json:"storageClass"`
 	Location     string `json:"location"`
 	TimeCreated  string `json:"timeCreated"`
+
+	IamConfiguration GCSIamConfiguration `json:"iamConfiguration"`
+}
+
+// GCSIamConfiguration carries a bucket's public access prevention
+// ("enforced" or "inherited").
+type GCSIamConfiguration struct {
+	PublicAccessPrevention string `json:"publicAccessPrevention"`
 }
 
 type GCSBucketsListResponse struct {
@@ -8,12 +8,12 @@ package billing
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -26,28 +26,31 @@ type Driver struct {
 	Client *api.Client
 }
 
-// QueryAccountBalance prints the visible billing accounts via logger. Errors
-// are surfaced as Info to keep cloudlist resilient when the caller credential
-// lacks `billing.accounts.list`.
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+// QueryAccountBalance returns one amount-less balance per visible billing
+// account; Scope carries the account name, display name and a closed marker.
+// Errors are surfaced as Info to keep cloudlist resilient when the caller
+// credential lacks `billing.accounts.list`.
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	if d == nil || d.Client == nil {
-		return
+		return nil
 	}
 	accounts, err := d.list(ctx)
 	if err != nil {
 		logger.Info("GCP Cloud Billing query unavailable: " + err.Error())
-		return
+		return nil
 	}
-	if len(accounts) == 0 {
-		return
-	}
-	open := 0
+	out := make([]schema.Balance, 0, len(accounts))
 	for _, a := range accounts {
-		if a.Open {
-			open++
+		scope := a.Name
+		if a.DisplayName != "" {
+			scope += " (" + a.DisplayName + ")"
+		}
+		if !a.Open {
+			scope += " [closed]"
 		}
+		out = append(out, schema.Balance{Kind: schema.BalanceBillingAccount, Scope: scope})
 	}
-	logger.Warning(fmt.Sprintf("GCP billing accounts visible: %d (open=%d)", len(accounts), open))
+	return out
 }
 
 func (d *Driver) list(ctx context.Context) ([]api.BillingAccount, error) {
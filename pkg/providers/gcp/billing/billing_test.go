@@ -10,6 +10,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/auth"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/internal/testutil"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func newClient(t *testing.T, server *httptest.Server) *api.Client {
@@ -32,7 +33,7 @@ func newClient(t *testing.T, server *httptest.Server) *api.Client {
 	return api.NewClient(ts, api.WithHTTPClient(httpClient))
 }
 
-func TestQueryAccountBalanceLogsVisibleAccounts(t *testing.T) {
+func TestQueryAccountBalanceReturnsVisibleAccounts(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path == "/token" {
 			_, _ = w.Write([]byte(`{"access_token":"demo","token_type":"Bearer","expires_in":3600}`))
@@ -49,7 +50,16 @@ func TestQueryAccountBalanceLogsVisibleAccounts(t *testing.T) {
 	defer server.Close()
 
 	driver := &Driver{Client: newClient(t, server)}
-	driver.QueryAccountBalance(context.Background())
+	got := driver.QueryAccountBalance(context.Background())
+	if len(got) != 2 {
+		t.Fatalf("expected 2 balances, got %+v", got)
+	}
+	if got[0].Kind != schema.BalanceBillingAccount || got[0].Scope != "billingAccounts/01-AAA-BBB (Production)" {
+		t.Fatalf("unexpected open account: %+v", got[0])
+	}
+	if got[1].Scope != "billingAccounts/02-CCC-DDD (Sandbox) [closed]" {
+		t.Fatalf("unexpected closed account: %+v", got[1])
+	}
 }
 
 func TestQueryAccountBalanceSwallowsAccessDenied(t *testing.T) {
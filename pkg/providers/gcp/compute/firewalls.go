@@ -0,0 +1,114 @@
+package compute
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// GetSecurityGroups lists VPC firewall rules. Each firewall resource becomes
+// one group, keyed `<project>/<name>`, since GCP has no grouping above the
+// individual rule. Disabled firewalls are listed but never flagged exposed.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	logger.Info("List VPC firewalls ...")
+	for _, project := range d.Projects {
+		firewalls, err := d.listFirewalls(ctx, project)
+		if err != nil {
+			logger.Error(fmt.Sprintf("List projects/%s/global/firewalls failed: %s", project, err.Error()))
+			return list, err
+		}
+		for _, fw := range firewalls {
+			list = append(list, convertFirewall(project, fw))
+		}
+	}
+	return list, nil
+}
+
+func convertFirewall(project string, fw api.Firewall) schema.SecurityGroup {
+	group := schema.SecurityGroup{
+		GroupID:   project + "/" + fw.Name,
+		GroupName: fw.Name,
+		VpcID:     shortResourceName(fw.Network),
+		Region:    "global",
+	}
+	for _, tag := range fw.TargetTags {
+		group.Attached = append(group.Attached, "tag:"+tag)
+	}
+	for _, sa := range fw.TargetServiceAccounts {
+		group.Attached = append(group.Attached, "sa:"+sa)
+	}
+	if len(group.Attached) == 0 {
+		group.Attached = []string{"all instances"}
+	}
+
+	direction, peers := "ingress", fw.SourceRanges
+	if strings.EqualFold(fw.Direction, "EGRESS") {
+		direction, peers = "egress", fw.DestinationRanges
+	} else {
+		for _, tag := range fw.SourceTags {
+			peers = append(peers, "tag:"+tag)
+		}
+	}
+	if len(peers) == 0 {
+		// Compute Engine defaults an unrestricted rule to every address.
+		peers = []string{"0.0.0.0/0"}
+	}
+	description := fw.Description
+	if fw.Disabled {
+		description = strings.TrimSpace("disabled " + description)
+	}
+	add := func(action string, entries []api.FirewallProtocol) {
+		for _, entry := range entries {
+			for _, peer := range peers {
+				group.Rules = append(group.Rules, schema.FirewallRule{
+					Direction:   direction,
+					Action:      action,
+					Protocol:    normalizeProtocol(entry.IPProtocol),
+					PortRange:   firewallPorts(entry.Ports),
+					CIDR:        peer,
+					Priority:    strconv.Itoa(fw.Priority),
+					Description: description,
+				})
+			}
+		}
+	}
+	add("allow", fw.Allowed)
+	add("deny", fw.Denied)
+	if !fw.Disabled {
+		group.FlagExposure()
+	}
+	return group
+}
+
+func firewallPorts(ports []string) string {
+	if len(ports) == 0 {
+		return "all"
+	}
+	return strings.Join(ports, ",")
+}
+
+func normalizeProtocol(protocol string) string {
+	protocol = strings.ToLower(strings.TrimSpace(protocol))
+	if protocol == "" {
+		return "all"
+	}
+	return protocol
+}
+
+func (d *Driver) listFirewalls(ctx context.Context, project string) ([]api.Firewall, error) {
+	pager := api.NewPager[api.Firewall](d.Client, api.Request{
+		Method:     http.MethodGet,
+		BaseURL:    api.ComputeBaseURL,
+		Path:       "/compute/v1/projects/" + url.PathEscape(project) + "/global/firewalls",
+		Idempotent: true,
+	}, "items")
+	return pager.All(ctx)
+}
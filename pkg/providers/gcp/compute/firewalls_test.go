@@ -0,0 +1,64 @@
+package compute
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetSecurityGroupsMapsFirewalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`))
+		case "/compute/v1/projects/proj-1/global/firewalls":
+			_, _ = w.Write([]byte(`{"items":[
+				{"name":"allow-ssh","network":"https://www.googleapis.com/compute/v1/projects/proj-1/global/networks/default","priority":1000,"direction":"INGRESS","sourceRanges":["0.0.0.0/0"],"targetTags":["bastion"],"allowed":[{"IPProtocol":"tcp","ports":["22"]}]},
+				{"name":"allow-internal","network":"projects/proj-1/global/networks/default","priority":65534,"direction":"INGRESS","sourceRanges":["10.128.0.0/9"],"allowed":[{"IPProtocol":"all"}]},
+				{"name":"old-rdp","network":"projects/proj-1/global/networks/default","priority":900,"direction":"INGRESS","sourceRanges":["0.0.0.0/0"],"allowed":[{"IPProtocol":"tcp","ports":["3389"]}],"disabled":true},
+				{"name":"deny-egress","network":"projects/proj-1/global/networks/default","priority":100,"direction":"EGRESS","destinationRanges":["0.0.0.0/0"],"denied":[{"IPProtocol":"tcp","ports":["25","465-587"]}]}
+			]}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	driver := &Driver{Projects: []string{"proj-1"}, Client: newTestClient(t, server)}
+	groups, err := driver.GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if len(groups) != 4 {
+		t.Fatalf("unexpected group count: %d", len(groups))
+	}
+
+	ssh := groups[0]
+	if ssh.GroupID != "proj-1/allow-ssh" || ssh.VpcID != "default" || ssh.Region != "global" {
+		t.Fatalf("unexpected ssh group: %+v", ssh)
+	}
+	if len(ssh.Attached) != 1 || ssh.Attached[0] != "tag:bastion" {
+		t.Fatalf("unexpected ssh attachments: %v", ssh.Attached)
+	}
+	if len(ssh.Rules) != 1 || ssh.Rules[0].PortRange != "22" || ssh.Rules[0].Priority != "1000" || !ssh.Rules[0].Exposed {
+		t.Fatalf("unexpected ssh rules: %+v", ssh.Rules)
+	}
+
+	internal := groups[1]
+	if len(internal.Attached) != 1 || internal.Attached[0] != "all instances" {
+		t.Fatalf("unexpected internal attachments: %v", internal.Attached)
+	}
+	if r := internal.Rules[0]; r.Protocol != "all" || r.PortRange != "all" || r.Exposed {
+		t.Fatalf("unexpected internal rule: %+v", r)
+	}
+
+	if r := groups[2].Rules[0]; r.Exposed || r.Description != "disabled" {
+		t.Fatalf("disabled firewall should not be flagged: %+v", r)
+	}
+
+	if r := groups[3].Rules[0]; r.Direction != "egress" || r.Action != "deny" || r.PortRange != "25,465-587" || r.CIDR != "0.0.0.0/0" {
+		t.Fatalf("unexpected egress rule: %+v", r)
+	}
+}
@@ -43,6 +43,7 @@ func (d *Driver) GetResource(ctx context.Context) ([]schema.Host, error) {
 					HostName: hostName,
 					ID:       composeInstanceID(zoneShort, instanceName),
 					Region:   zoneShort,
+					Tags:     i.Labels,
 				}
 				foundPublic := false
 				for _, n := range i.NetworkInterfaces {
@@ -20,6 +20,7 @@ import (
 	_vmexec "github.com/404tk/cloudtoolkit/pkg/providers/gcp/vmexec"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/credverify"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -102,8 +103,8 @@ func (p *Provider) CredentialKey(opts map[string]string) string {
 // Resources returns the provider for an resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&_billing.Driver{Client: p.apiClient}).QueryAccountBalance(ctx)
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&_billing.Driver{Client: p.apiClient}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			instanceProvider := &_compute.Driver{Projects: p.projects, Client: p.apiClient}
@@ -140,6 +141,12 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			dbs, err := sqlProvider.GetDatabases(ctx)
 			schema.AppendAssets(list, dbs)
 			list.AddError("database", err)
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			firewallProvider := &_compute.Driver{Projects: p.projects, Client: p.apiClient}
+			groups, err := firewallProvider.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -148,17 +155,9 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 // BucketDump implements schema.BucketManager for GCP via GCS object listing.
 func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([]schema.BucketResult, error) {
 	driver := &_storage.Driver{Projects: p.projects, Client: p.apiClient}
-	infos := make(map[string]string)
-	if bucketName == "all" {
-		buckets, err := driver.GetBuckets(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("list buckets: %w", err)
-		}
-		for _, b := range buckets {
-			infos[b.BucketName] = b.Region
-		}
-	} else {
-		infos[bucketName] = ""
+	infos, err := p.bucketInfos(ctx, driver, bucketName)
+	if err != nil {
+		return nil, err
 	}
 	switch action {
 	case "list":
@@ -170,6 +169,43 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	driver := &_storage.Driver{Projects: p.projects, Client: p.apiClient}
+	infos, err := p.bucketInfos(ctx, driver, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return driver.WalkObjects(ctx, infos, q, visit)
+}
+
+// bucketInfos maps bucketName, or every bucket for "all", to its location.
+func (p *Provider) bucketInfos(ctx context.Context, driver *_storage.Driver, bucketName string) (map[string]string, error) {
+	infos := make(map[string]string)
+	if bucketName != "all" {
+		infos[bucketName] = ""
+		return infos, nil
+	}
+	buckets, err := driver.GetBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	for _, b := range buckets {
+		infos[b.BucketName] = b.Region
+	}
+	return infos, nil
+}
+
+// ObjectAccess implements schema.ObjectAccessManager for GCS. The requests
+// appear in the Data Access audit logs when they are enabled for Cloud
+// Storage; the returned upload ID only identifies the request to Google
+// support, so log entries are matched by object name.
+func (p *Provider) ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	driver := &_storage.Driver{Projects: p.projects, Client: p.apiClient, Credential: p.cred}
+	return driver.AccessObject(ctx, action, bucket, key, body)
+}
+
 // BucketACL implements schema.BucketACLManager for GCP. The GCS analogue of
 // "public" / "private" is the bucket IAM policy granting `allUsers` the
 // objectViewer role.
@@ -228,8 +264,8 @@ func (p *Provider) UserManagement(action, username, _ string) (schema.IAMResult,
 
 // ExecuteCloudVMCommand implements schema.VMExecutor for GCP via the metadata
 // startup-script + reboot path. See pkg/providers/gcp/vmexec/vmexec.go for the
-// rationale (PLAN.md decision T2.2/Task 10). Output is not captured —
-// startup-script stdout goes to the serial console.
+// rationale (PLAN.md decision T2.2/Task 10). Output is read back from the
+// serial console and the instance's previous startup-script is restored.
 //
 // `cmd` arrives in one of two encodings: the headless `__ctk_headless_sh__:`
 // vmexec spec (which carries an explicit osType), or a bare base64 string from
@@ -238,6 +274,13 @@ func (p *Provider) UserManagement(action, username, _ string) (schema.IAMResult,
 // metadata + Cloud-Init) is out of scope until separately validated.
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	driver := &_vmexec.Driver{Projects: p.projects, Client: p.apiClient}
+	// Resolve a bare instance name to its "<zone>/<instance>" id from the
+	// inventory before vmexec falls back to scanning every zone.
+	if !strings.Contains(instanceID, "/") {
+		if host, ok := invcache.LookupHost(ctx, instanceID); ok && strings.Contains(host.ID, "/") {
+			instanceID = host.ID
+		}
+	}
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
 		if osType == "windows" {
 			return schema.CommandResult{}, fmt.Errorf("gcp vmexec: windows targets are not supported on the startup-script path")
@@ -377,10 +420,23 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads Cloud Audit Logs, folding q into the entries:list filter.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &_logging.Driver{Client: p.apiClient, Projects: p.projects}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for GCP Cloud SQL. `useradd` /
 // `userdel` invoke the Cloud SQL Admin user APIs.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
 	driver := &_sqladmin.Driver{Client: p.apiClient, Projects: p.projects}
+	// The cached connection name ("project:region:instance") pins the
+	// project when the credential spans several.
+	if db, ok := invcache.LookupDatabase(ctx, instanceID); ok {
+		if project, _, ok := strings.Cut(db.DBNames, ":"); ok && project != "" {
+			driver.Projects = []string{project}
+		}
+	}
 	switch action {
 	case "useradd":
 		return driver.CreateAccount(ctx, instanceID)
@@ -31,16 +31,24 @@ const (
 // `<startUnix>:<endUnix>` time window, "all", or empty (default 7-day
 // lookback).
 func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, error) {
+	startTS, endTS, err := parseTimeWindow(args)
+	if err != nil {
+		return nil, err
+	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTS, endTS))
+}
+
+// QueryEvents returns the Cloud Audit log entries matching q. The logging
+// filter language covers every field of the query, so the client-side pass
+// only tightens the substring matches used for names and resources.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
 	if d == nil || d.Client == nil {
 		return nil, errors.New("gcp logging: nil api client")
 	}
 	if len(d.Projects) == 0 || strings.TrimSpace(d.Projects[0]) == "" {
 		return nil, errors.New("gcp logging: no project configured")
 	}
-	startTS, endTS, err := parseTimeWindow(args)
-	if err != nil {
-		return nil, err
-	}
+	filter := buildQueryFilter(q)
 	resourceNames := make([]string, 0, len(d.Projects))
 	for _, p := range d.Projects {
 		p = strings.TrimSpace(p)
@@ -55,7 +63,7 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 	for page := 0; page < maxPages; page++ {
 		body := api.ListLogEntriesRequest{
 			ResourceNames: resourceNames,
-			Filter:        buildFilter(startTS, endTS),
+			Filter:        filter,
 			OrderBy:       "timestamp desc",
 			PageSize:      defaultPageSize,
 			PageToken:     pageToken,
@@ -72,7 +80,7 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 			Body:    raw,
 		}, &resp)
 		if err != nil {
-			return out, err
+			return q.WithoutWindow().Filter(out), err
 		}
 		for _, entry := range resp.Entries {
 			out = append(out, schema.Event{
@@ -91,7 +99,7 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 		}
 		pageToken = resp.NextPageToken
 	}
-	return out, nil
+	return q.WithoutWindow().Filter(out), nil
 }
 
 // HandleEvents is unsupported — Cloud Audit Logs are read-only.
@@ -120,6 +128,40 @@ func buildFilter(start, end int64) string {
 	return strings.Join(clauses, " AND ")
 }
 
+// buildQueryFilter extends buildFilter with the rest of q. Names,
+// principals and resources use the `:` (has) operator because the query
+// also matches their last path segment.
+func buildQueryFilter(q schema.EventQuery) string {
+	var start, end int64
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		start, end = q.UnixWindow()
+		if end <= 0 {
+			end = time.Now().UTC().Unix()
+		}
+	}
+	clauses := []string{buildFilter(start, end)}
+	if start <= 0 && end > 0 {
+		clauses = append(clauses, fmt.Sprintf(`timestamp<="%s"`, time.Unix(end, 0).UTC().Format(time.RFC3339)))
+	}
+	for _, item := range []struct{ field, op, value string }{
+		{"protoPayload.methodName", ":", q.Name},
+		{"protoPayload.authenticationInfo.principalEmail", ":", q.Principal},
+		{"protoPayload.requestMetadata.callerIp", "=", q.SourceIP},
+		{"protoPayload.resourceName", ":", q.Resource},
+	} {
+		if value := strings.TrimSpace(item.value); value != "" {
+			clauses = append(clauses, item.field+item.op+strconv.Quote(value))
+		}
+	}
+	switch q.Result {
+	case schema.EventResultSuccess:
+		clauses = append(clauses, "NOT protoPayload.status.code>0")
+	case schema.EventResultFailure:
+		clauses = append(clauses, "protoPayload.status.code>0")
+	}
+	return strings.Join(clauses, " AND ")
+}
+
 func statusLabel(code int) string {
 	if code == 0 {
 		return "Success"
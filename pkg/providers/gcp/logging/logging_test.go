@@ -11,6 +11,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/auth"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/internal/testutil"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func newLoggingClient(t *testing.T, server *httptest.Server) *api.Client {
@@ -100,3 +101,35 @@ func TestHandleEventsReturnsUnsupported(t *testing.T) {
 		t.Fatalf("expected error from HandleEvents")
 	}
 }
+
+func TestQueryEventsTranslatesFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			_, _ = w.Write([]byte(`{"access_token":"demo","token_type":"Bearer","expires_in":3600}`))
+			return
+		}
+		var body api.ListLogEntriesRequest
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, want := range []string{
+			`protoPayload.methodName:"storage.buckets.update"`,
+			`protoPayload.requestMetadata.callerIp="1.1.1.1"`,
+			"protoPayload.status.code>0",
+		} {
+			if !strings.Contains(body.Filter, want) {
+				t.Fatalf("filter lacks %s: %s", want, body.Filter)
+			}
+		}
+		_, _ = w.Write([]byte(`{"entries":[{"insertId":"i1","timestamp":"2026-04-22T09:14:00Z","protoPayload":{"methodName":"storage.buckets.update","requestMetadata":{"callerIp":"1.1.1.1"},"status":{"code":0}}},{"insertId":"i2","timestamp":"2026-04-22T09:15:00Z","protoPayload":{"methodName":"storage.buckets.update","requestMetadata":{"callerIp":"1.1.1.1"},"status":{"code":7}}}]}`))
+	}))
+	defer server.Close()
+
+	driver := &Driver{Client: newLoggingClient(t, server), Projects: []string{"proj-1"}}
+	q := schema.EventQuery{Name: "storage.buckets.update", SourceIP: "1.1.1.1", Result: schema.EventResultFailure}
+	events, err := driver.QueryEvents(context.Background(), q)
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(events) != 1 || events[0].Id != "i2" {
+		t.Fatalf("expected only the failed call, got %+v", events)
+	}
+}
@@ -4,14 +4,23 @@ import (
 	"net/http"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 // ClientConfig builds the demo replay configuration injected into
 // gcp.NewWithConfig when replay is active for the gcp provider.
 func ClientConfig() gcp.ClientConfig {
-	transport := newTransport()
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every gcp client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) gcp.ClientConfig {
+	httpClient = telemetry.WrapClient("gcp", httpClient)
 	return gcp.ClientConfig{
-		HTTPClient:          &http.Client{Transport: transport},
-		SkipCredentialCache: true,
+		HTTPClient: httpClient,
 	}
 }
@@ -1,6 +1,10 @@
 package replay
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+)
 
 type instanceFixture struct {
 	Name      string
@@ -9,6 +13,11 @@ type instanceFixture struct {
 	Status    string
 	PrivateIP string
 	PublicIP  string
+	// StartupScript seeds the instance metadata so vmexec has a previous
+	// script to restore; Commands are the canned outputs its serial console
+	// reports for injected commands.
+	StartupScript string
+	Commands      map[string][]string
 }
 
 var demoZones = []string{"us-central1-a", "us-east1-b", "asia-east1-a"}
@@ -21,13 +30,25 @@ var demoInstances = []instanceFixture{
 		Status:    "RUNNING",
 		PrivateIP: "10.10.0.21",
 		PublicIP:  "203.0.113.41",
+		Commands: map[string][]string{
+			"whoami": {"root"},
+			"id":     {"uid=0(root) gid=0(root) groups=0(root)"},
+			"pwd":    {"/"},
+			"ls":     {"bin", "boot", "etc", "home", "opt"},
+		},
 	},
 	{
-		Name:      "ctk-demo-app",
-		Hostname:  "ctk-demo-app.c.ctk-demo-project.internal",
-		Zone:      "us-central1-a",
-		Status:    "RUNNING",
-		PrivateIP: "10.10.0.22",
+		Name:          "ctk-demo-app",
+		Hostname:      "ctk-demo-app.c.ctk-demo-project.internal",
+		Zone:          "us-central1-a",
+		Status:        "RUNNING",
+		PrivateIP:     "10.10.0.22",
+		StartupScript: "#!/bin/bash\n/opt/ctk-demo-app/bootstrap.sh\n",
+		Commands: map[string][]string{
+			"whoami": {"root"},
+			"pwd":    {"/"},
+			"ls":     {"app", "audit.log", "tmp"},
+		},
 	},
 	{
 		Name:      "ctk-demo-edge",
@@ -39,6 +60,43 @@ var demoInstances = []instanceFixture{
 	},
 }
 
+// demoFirewalls covers the cases cloudlist flags: SSH open to the internet
+// on the bastion tag, a default-network internal allow, and an RDP rule that
+// is open but disabled.
+var demoFirewalls = []api.Firewall{
+	{
+		Name:         "ctk-demo-allow-ssh",
+		Description:  "bastion ssh",
+		Network:      "https://www.googleapis.com/compute/v1/projects/ctk-demo-project/global/networks/default",
+		Priority:     1000,
+		Direction:    "INGRESS",
+		SourceRanges: []string{"0.0.0.0/0"},
+		TargetTags:   []string{"bastion"},
+		Allowed:      []api.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"22"}}},
+	},
+	{
+		Name:         "default-allow-internal",
+		Network:      "https://www.googleapis.com/compute/v1/projects/ctk-demo-project/global/networks/default",
+		Priority:     65534,
+		Direction:    "INGRESS",
+		SourceRanges: []string{"10.128.0.0/9"},
+		Allowed: []api.FirewallProtocol{
+			{IPProtocol: "tcp", Ports: []string{"0-65535"}},
+			{IPProtocol: "udp", Ports: []string{"0-65535"}},
+			{IPProtocol: "icmp"},
+		},
+	},
+	{
+		Name:         "ctk-demo-legacy-rdp",
+		Network:      "https://www.googleapis.com/compute/v1/projects/ctk-demo-project/global/networks/default",
+		Priority:     900,
+		Direction:    "INGRESS",
+		SourceRanges: []string{"0.0.0.0/0"},
+		Allowed:      []api.FirewallProtocol{{IPProtocol: "tcp", Ports: []string{"3389"}}},
+		Disabled:     true,
+	},
+}
+
 func instancesForZone(zone string) []instanceFixture {
 	zone = strings.TrimSpace(zone)
 	out := make([]instanceFixture, 0, len(demoInstances))
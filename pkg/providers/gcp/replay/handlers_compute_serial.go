@@ -0,0 +1,107 @@
+package replay
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+// serialScriptRunner is the prefix the guest agent writes in front of every
+// startup-script line it copies to serial port 1.
+const serialScriptRunner = "google_metadata_script_runner[812]: startup-script: "
+
+// handleGetSerialPortOutput serves `compute.instances.getSerialPortOutput`.
+// Only port 1 carries anything; `start` is a byte offset into the log the
+// same way Compute Engine treats it, and `next` tells the caller where to
+// resume.
+func (t *transport) handleGetSerialPortOutput(req *http.Request, zone, instance string) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return apiErrorResponse(req, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED",
+			fmt.Sprintf("method %s not supported on instances.getSerialPortOutput", req.Method)), nil
+	}
+	if _, ok := findInstanceByZoneAndName(zone, instance); !ok {
+		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND",
+			fmt.Sprintf("instance %s not found in zone %s", instance, zone)), nil
+	}
+	query := req.URL.Query()
+	log := ""
+	if port := strings.TrimSpace(query.Get("port")); port == "" || port == "1" {
+		log = t.snapshotSerialLog(instance)
+	}
+	start := demoreplay.ParseInt(query.Get("start"), 0)
+	if start < 0 || start > len(log) {
+		start = len(log)
+	}
+	return demoreplay.JSONResponse(req, http.StatusOK, api.SerialPortOutput{
+		Contents: log[start:],
+		Start:    int64(start),
+		Next:     int64(len(log)),
+	}), nil
+}
+
+func (t *transport) appendSerialLog(instance, text string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.serialLogs[instance] += text
+}
+
+func (t *transport) snapshotSerialLog(instance string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.serialLogs[instance]
+}
+
+// bootSerialLog renders what one reboot of `inst` prints on serial port 1:
+// a short boot banner, then the output of the startup-script in metadata.
+func bootSerialLog(inst instanceFixture, metadata api.InstanceMetadata) string {
+	lines := []string{
+		"[    0.000000] Linux version 6.1.0-28-cloud-amd64 (debian-kernel@lists.debian.org)",
+		"[    2.104512] systemd[1]: Starting google-startup-scripts.service - Google Compute Engine Startup Scripts...",
+	}
+	for _, item := range metadata.Items {
+		if item.Key != "startup-script" {
+			continue
+		}
+		for _, line := range startupScriptOutput(inst, item.Value) {
+			lines = append(lines, inst.Name+" "+serialScriptRunner+line)
+		}
+		lines = append(lines, inst.Name+" google_metadata_script_runner[812]: startup-script exit status 0")
+	}
+	lines = append(lines, "[    4.861230] systemd[1]: Finished google-startup-scripts.service - Google Compute Engine Startup Scripts.")
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// startupScriptOutput plays back the marked block the vmexec wrapper prints,
+// answering the wrapped command from the instance's canned outputs. Scripts
+// without the wrapper print nothing.
+func startupScriptOutput(inst instanceFixture, script string) []string {
+	_, rest, ok := strings.Cut(script, "<<'CTK_SCRIPT_")
+	if !ok {
+		return nil
+	}
+	nonce, body, ok := strings.Cut(rest, "'\n")
+	if !ok {
+		return nil
+	}
+	body, _, _ = strings.Cut(body, "\nCTK_SCRIPT_"+nonce+"\n")
+	command := strings.TrimSpace(strings.TrimPrefix(body, "#!/bin/bash\nset -e\n"))
+
+	output := fmt.Sprintf("gce-replay output for %s", inst.Name)
+	if lines, ok := inst.Commands[command]; ok {
+		output = strings.Join(lines, "\n")
+	}
+	encoded := base64.StdEncoding.EncodeToString([]byte(output))
+	out := []string{"CTK-BEGIN-" + nonce}
+	for len(encoded) > 76 {
+		out = append(out, "CTK-DATA-"+nonce+" "+encoded[:76])
+		encoded = encoded[76:]
+	}
+	if encoded != "" {
+		out = append(out, "CTK-DATA-"+nonce+" "+encoded)
+	}
+	return append(out, "CTK-END-"+nonce+" rc=0")
+}
@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+// handleStorageObject models the JSON API object data plane
+// object-access-check drives: media uploads, media downloads and deletes of
+// objects written during the session. Every response carries an
+// X-GUploader-UploadID.
+func (t *transport) handleStorageObject(req *http.Request, bucket, key string, body []byte) (*http.Response, error) {
+	if !demoBucketExists(bucket) {
+		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("bucket %s not found", bucket)), nil
+	}
+	id := bucket + "/" + key
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var resp *http.Response
+	switch req.Method {
+	case http.MethodPost:
+		t.objects[id] = body
+		now := time.Now().UTC().Format(time.RFC3339)
+		resp = demoreplay.JSONResponse(req, http.StatusOK, api.GCSObject{
+			Kind:         "storage#object",
+			ID:           id,
+			Name:         key,
+			Bucket:       bucket,
+			StorageClass: "STANDARD",
+			Size:         strconv.Itoa(len(body)),
+			Updated:      now,
+			TimeCreated:  now,
+		})
+	case http.MethodGet:
+		resp = t.objectMediaLocked(req, id)
+	case http.MethodDelete:
+		if _, ok := t.objects[id]; !ok {
+			resp = apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND", "No such object: "+id)
+			break
+		}
+		delete(t.objects, id)
+		resp = demoreplay.Response(req, http.StatusNoContent, "", nil)
+	default:
+		resp = apiErrorResponse(req, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED", "unsupported object method "+req.Method)
+	}
+	t.objectSeq++
+	resp.Header.Set("X-GUploader-UploadID", fmt.Sprintf("ADPycdtCTKREPLAY%08d", t.objectSeq))
+	return resp, nil
+}
+
+// handleSignedStorage serves a V4 signed URL on the XML API host. The replay
+// signs with a per-process key it cannot see, so the signature is checked
+// for shape, signer and expiry rather than recomputed, as with the token
+// endpoint's JWT assertion.
+func (t *transport) handleSignedStorage(req *http.Request) (*http.Response, error) {
+	if !verifySignedURL(req.URL.Query(), time.Now()) {
+		return apiErrorResponse(req, http.StatusForbidden, "PERMISSION_DENIED", "SignatureDoesNotMatch: the signed url is invalid or expired"), nil
+	}
+	bucket, key, ok := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	if !ok || key == "" || req.Method != http.MethodGet || !demoBucketExists(bucket) {
+		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND", "unsupported signed url "+req.URL.Path), nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	resp := t.objectMediaLocked(req, bucket+"/"+key)
+	t.objectSeq++
+	resp.Header.Set("X-GUploader-UploadID", fmt.Sprintf("ADPycdtCTKREPLAY%08d", t.objectSeq))
+	return resp, nil
+}
+
+func (t *transport) objectMediaLocked(req *http.Request, id string) *http.Response {
+	data, ok := t.objects[id]
+	if !ok {
+		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND", "No such object: "+id)
+	}
+	return demoreplay.Response(req, http.StatusOK, "text/plain", data)
+}
+
+func verifySignedURL(query url.Values, now time.Time) bool {
+	if query.Get("X-Goog-Algorithm") != "GOOG4-RSA-SHA256" || query.Get("X-Goog-SignedHeaders") != "host" {
+		return false
+	}
+	if !strings.HasPrefix(query.Get("X-Goog-Credential"), demoClientEmail+"/") {
+		return false
+	}
+	signedAt, err := time.Parse("20060102T150405Z", query.Get("X-Goog-Date"))
+	if err != nil {
+		return false
+	}
+	expires, err := strconv.Atoi(query.Get("X-Goog-Expires"))
+	if err != nil || now.After(signedAt.Add(time.Duration(expires)*time.Second)) {
+		return false
+	}
+	signature, err := hex.DecodeString(query.Get("X-Goog-Signature"))
+	return err == nil && len(signature) == 256
+}
+
+// storageObjectRequest extracts the bucket and object name of a JSON API
+// media upload or object request. Object names arrive path-escaped, slashes
+// included, so the escaped path is split.
+func storageObjectRequest(req *http.Request) (string, string, bool) {
+	path := strings.Trim(req.URL.EscapedPath(), "/")
+	if rest, ok := strings.CutPrefix(path, "upload/storage/v1/b/"); ok {
+		bucket, tail, _ := strings.Cut(rest, "/")
+		name := req.URL.Query().Get("name")
+		return bucket, name, tail == "o" && name != ""
+	}
+	rest, ok := strings.CutPrefix(path, "storage/v1/b/")
+	if !ok {
+		return "", "", false
+	}
+	parts := strings.SplitN(rest, "/", 3)
+	if len(parts) != 3 || parts[1] != "o" || parts[2] == "" {
+		return "", "", false
+	}
+	key, err := url.PathUnescape(parts[2])
+	if err != nil {
+		return "", "", false
+	}
+	return parts[0], key, true
+}
+
+func demoBucketExists(name string) bool {
+	for _, bucket := range demoGCSBuckets() {
+		if bucket.Name == name {
+			return true
+		}
+	}
+	return false
+}
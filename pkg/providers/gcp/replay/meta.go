@@ -4,4 +4,5 @@ const (
 	demoProjectID   = "ctk-demo-project"
 	demoAccessToken = "ctk.demo.gcp.bearer"
 	demoZone        = "us-central1-a"
+	demoClientEmail = "ctk-demo@ctk-demo-project.iam.gserviceaccount.com"
 )
@@ -25,6 +25,9 @@ type transport struct {
 	gcsPolicy          map[string]api.GCSPolicy
 	instanceMetadata   map[string]api.InstanceMetadata
 	instanceMetadataEt int64
+	serialLogs         map[string]string
+	objects            map[string][]byte
+	objectSeq          int
 }
 
 func newTransport() *transport {
@@ -34,6 +37,8 @@ func newTransport() *transport {
 		sqlUsers:         make(map[string][]string),
 		gcsPolicy:        make(map[string]api.GCSPolicy),
 		instanceMetadata: make(map[string]api.InstanceMetadata),
+		serialLogs:       make(map[string]string),
+		objects:          make(map[string][]byte),
 	}
 }
 
@@ -129,6 +134,9 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		}
 		return t.handleSQLAdmin(req, body)
 	case "storage.googleapis.com":
+		if req.URL.Query().Has("X-Goog-Signature") {
+			return t.handleSignedStorage(req)
+		}
 		if !verifyBearer(req) {
 			return apiErrorResponse(req, http.StatusUnauthorized, "UNAUTHENTICATED",
 				"Request had invalid authentication credentials."), nil
@@ -239,6 +247,7 @@ func (t *transport) handleCompute(req *http.Request) (*http.Response, error) {
 	// expected forms:
 	//   compute/v1/projects/{p}/zones
 	//   compute/v1/projects/{p}/zones/{zone}/instances
+	//   compute/v1/projects/{p}/global/firewalls
 	if len(parts) < 5 || parts[0] != "compute" || parts[1] != "v1" || parts[2] != "projects" {
 		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND",
 			fmt.Sprintf("unsupported compute path: %s", path)), nil
@@ -251,6 +260,8 @@ func (t *transport) handleCompute(req *http.Request) (*http.Response, error) {
 	switch {
 	case len(parts) == 5 && parts[4] == "zones":
 		return handleListZones(req)
+	case len(parts) == 6 && parts[4] == "global" && parts[5] == "firewalls":
+		return demoreplay.JSONResponse(req, http.StatusOK, api.ListFirewallsResponse{Items: demoFirewalls}), nil
 	case len(parts) == 7 && parts[4] == "zones" && parts[6] == "instances":
 		return handleListInstances(req, parts[5])
 	case len(parts) == 7 && parts[4] == "zones" && parts[6] != "instances":
@@ -262,6 +273,8 @@ func (t *transport) handleCompute(req *http.Request) (*http.Response, error) {
 		return t.handleSetInstanceMetadata(req, parts[5], parts[7])
 	case len(parts) == 9 && parts[4] == "zones" && parts[6] == "instances" && parts[8] == "reset":
 		return t.handleResetInstance(req, parts[5], parts[7])
+	case len(parts) == 9 && parts[4] == "zones" && parts[6] == "instances" && parts[8] == "serialPort":
+		return t.handleGetSerialPortOutput(req, parts[5], parts[7])
 	}
 	return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND",
 		fmt.Sprintf("unsupported compute path: %s", path)), nil
@@ -353,18 +366,21 @@ func (t *transport) handleSetInstanceMetadata(req *http.Request, zone, instance
 	}), nil
 }
 
-// handleResetInstance serves `compute.instances.reset`. The driver does not
-// poll the operation, so the response just needs to surface a terminal DONE
-// state with no error.
+// handleResetInstance serves `compute.instances.reset`. The response
+// surfaces a terminal DONE state with no error, so the driver has no
+// operation to poll. The reboot is simulated on the serial console,
+// including whatever the current startup-script prints.
 func (t *transport) handleResetInstance(req *http.Request, zone, instance string) (*http.Response, error) {
 	if req.Method != http.MethodPost {
 		return apiErrorResponse(req, http.StatusMethodNotAllowed, "METHOD_NOT_ALLOWED",
 			fmt.Sprintf("method %s not supported on instances.reset", req.Method)), nil
 	}
-	if _, ok := findInstanceByZoneAndName(zone, instance); !ok {
+	inst, ok := findInstanceByZoneAndName(zone, instance)
+	if !ok {
 		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND",
 			fmt.Sprintf("instance %s not found in zone %s", instance, zone)), nil
 	}
+	t.appendSerialLog(instance, bootSerialLog(inst, t.snapshotInstanceMetadata(instance)))
 	return demoreplay.JSONResponse(req, http.StatusOK, api.ComputeOperation{
 		Name:          fmt.Sprintf("operation-replay-reset-%s", instance),
 		Zone:          fmt.Sprintf("https://www.googleapis.com/compute/v1/projects/%s/zones/%s", demoProjectID, zone),
@@ -382,9 +398,15 @@ func (t *transport) snapshotInstanceMetadata(instance string) api.InstanceMetada
 	if md, ok := t.instanceMetadata[instance]; ok {
 		return md
 	}
-	return api.InstanceMetadata{
+	md := api.InstanceMetadata{
 		Fingerprint: t.metadataFingerprint(),
 	}
+	for _, inst := range demoInstances {
+		if inst.Name == instance && inst.StartupScript != "" {
+			md.Items = append(md.Items, api.InstanceMetadataItem{Key: "startup-script", Value: inst.StartupScript})
+		}
+	}
+	return md
 }
 
 func (t *transport) commitInstanceMetadata(instance string, payload api.InstanceMetadata) {
@@ -892,13 +914,24 @@ func buildAuditEntry(insertID, methodName, resourceName, ts, principal, ip strin
 	}
 }
 
-func (t *transport) handleStorage(req *http.Request, _ []byte) (*http.Response, error) {
+func (t *transport) handleStorage(req *http.Request, body []byte) (*http.Response, error) {
+	if bucket, key, ok := storageObjectRequest(req); ok {
+		return t.handleStorageObject(req, bucket, key, body)
+	}
 	path := strings.TrimPrefix(strings.Trim(req.URL.Path, "/"), "storage/v1/")
 	parts := strings.Split(path, "/")
 	switch {
 	case len(parts) == 1 && parts[0] == "b" && req.Method == http.MethodGet:
 		resp := api.GCSBucketsListResponse{Items: demoGCSBuckets()}
 		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case len(parts) == 2 && parts[0] == "b" && req.Method == http.MethodGet:
+		for _, bucket := range demoGCSBuckets() {
+			if bucket.Name == parts[1] {
+				bucket.IamConfiguration.PublicAccessPrevention = "inherited"
+				return demoreplay.JSONResponse(req, http.StatusOK, bucket), nil
+			}
+		}
+		return apiErrorResponse(req, http.StatusNotFound, "NOT_FOUND", fmt.Sprintf("bucket %s not found", parts[1])), nil
 	case len(parts) == 3 && parts[0] == "b" && parts[2] == "o" && req.Method == http.MethodGet:
 		resp := api.GCSObjectsListResponse{Items: demoGCSObjects(parts[1])}
 		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
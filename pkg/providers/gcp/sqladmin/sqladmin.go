@@ -33,7 +33,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -74,7 +74,7 @@ func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.D
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
-	user, _, err := parseRDSAccount()
+	user, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -107,8 +107,8 @@ func (d *Driver) project() (string, error) {
 	return "", errors.New("gcp sqladmin: no project configured")
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -10,16 +10,23 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 const (
-	allUsersMember         = "allUsers"
-	objectViewerRole       = "roles/storage.objectViewer"
-	legacyBucketReaderRole = "roles/storage.legacyBucketReader"
+	allUsersMember              = "allUsers"
+	allAuthenticatedUsersMember = "allAuthenticatedUsers"
+	objectViewerRole            = "roles/storage.objectViewer"
+	legacyBucketReaderRole      = "roles/storage.legacyBucketReader"
+
+	publicAccessPreventionEnforced = "enforced"
 )
 
-// AuditBucketACL returns the public-access posture of each bucket.
+// AuditBucketACL returns the public-access posture of each bucket. Level
+// reflects the IAM bindings alone; the effective exposure also honours the
+// bucket's public access prevention, which makes GCS refuse allUsers and
+// allAuthenticatedUsers whatever the bindings say.
 func (d *Driver) AuditBucketACL(ctx context.Context, container string) ([]schema.BucketACLEntry, error) {
 	if d == nil || d.Client == nil {
 		return nil, errors.New("gcp storage: nil api client")
@@ -49,10 +56,24 @@ func (d *Driver) AuditBucketACL(ctx context.Context, container string) ([]schema
 				break
 			}
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: name,
 			Level:     level,
-		})
+		}
+		var verdict exposure.Verdict
+		grant := iamGrant(policy)
+		bucket, err := d.getBucket(ctx, name)
+		switch {
+		case err != nil:
+			verdict.Unknown("public access prevention", err)
+			verdict.Add("iam", grant)
+		case bucket.IamConfiguration.PublicAccessPrevention == publicAccessPreventionEnforced:
+			verdict.Block("iam", grant, "public access prevention enforced")
+		default:
+			verdict.Add("iam", grant)
+		}
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
@@ -98,6 +119,18 @@ func (d *Driver) getBucketIamPolicy(ctx context.Context, bucket string) (api.GCS
 	return resp, err
 }
 
+func (d *Driver) getBucket(ctx context.Context, bucket string) (api.GCSBucket, error) {
+	var resp api.GCSBucket
+	err := d.Client.Do(ctx, api.Request{
+		Method:     http.MethodGet,
+		BaseURL:    api.StorageBaseURL,
+		Path:       fmt.Sprintf("/storage/v1/b/%s", url.PathEscape(bucket)),
+		Query:      url.Values{"fields": {"name,iamConfiguration"}},
+		Idempotent: true,
+	}, &resp)
+	return resp, err
+}
+
 func (d *Driver) setBucketIamPolicy(ctx context.Context, bucket string, policy api.GCSPolicy) error {
 	body, err := json.Marshal(policy)
 	if err != nil {
@@ -145,9 +178,43 @@ func removeMember(bindings []api.GCSPolicyBind, member string) []api.GCSPolicyBi
 	return out
 }
 
+// iamGrant reads what the bindings for allUsers and allAuthenticatedUsers
+// open a bucket to. Roles that are neither predefined readers nor writers
+// count as read access.
+func iamGrant(policy api.GCSPolicy) exposure.Grant {
+	var g exposure.Grant
+	for _, b := range policy.Bindings {
+		read, write := roleAccess(b.Role)
+		for _, m := range b.Members {
+			switch m {
+			case allUsersMember:
+				g.Read = g.Read || read
+				g.Write = g.Write || write
+			case allAuthenticatedUsersMember:
+				g.Authenticated = true
+			default:
+				continue
+			}
+			g.Reasons = append(g.Reasons, fmt.Sprintf("iam %s has %s", m, b.Role))
+		}
+	}
+	return g
+}
+
+func roleAccess(role string) (read, write bool) {
+	name := strings.ToLower(role[strings.LastIndex(role, ".")+1:])
+	switch {
+	case strings.Contains(name, "admin"), strings.Contains(name, "owner"), strings.Contains(name, "editor"), name == "objectuser":
+		return true, true
+	case strings.Contains(name, "creator"), strings.Contains(name, "writer"):
+		return false, true
+	}
+	return true, false
+}
+
 func isPublicMember(b api.GCSPolicyBind) bool {
 	for _, m := range b.Members {
-		if m == allUsersMember || m == "allAuthenticatedUsers" {
+		if m == allUsersMember || m == allAuthenticatedUsersMember {
 			return true
 		}
 	}
@@ -12,6 +12,7 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/auth"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
@@ -23,6 +24,9 @@ const (
 type Driver struct {
 	Client   *api.Client
 	Projects []string
+	// Credential signs object URLs; only the object-access presign step
+	// needs it.
+	Credential auth.Credential
 }
 
 // GetBuckets lists GCS buckets across the configured projects.
@@ -81,28 +85,33 @@ func (d *Driver) listBuckets(ctx context.Context, project string) ([]api.GCSBuck
 
 // ListObjects walks objects in `infos` (bucket → "" since GCS is global).
 func (d *Driver) ListObjects(ctx context.Context, infos map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, infos, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's page tokens and hands the objects
+// matching q to visit.
+func (d *Driver) WalkObjects(ctx context.Context, infos map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	results := make([]schema.BucketResult, 0, len(infos))
 	for bucket := range infos {
-		objects, err := d.listObjects(ctx, bucket)
-		if err != nil {
+		walk := schema.NewObjectWalk(q, visit)
+		if err := d.walkObjects(ctx, bucket, q.Prefix, walk); err != nil {
 			return results, err
 		}
-		results = append(results, schema.BucketResult{
-			Action:      "list",
-			BucketName:  bucket,
-			ObjectCount: int64(len(objects)),
-			Objects:     objects,
-		})
+		results = append(results, walk.Result(bucket))
 	}
 	return results, nil
 }
 
-func (d *Driver) listObjects(ctx context.Context, bucket string) ([]schema.BucketObject, error) {
-	out := make([]schema.BucketObject, 0)
+func (d *Driver) walkObjects(ctx context.Context, bucket, prefix string, walk *schema.ObjectWalk) error {
 	pageToken := ""
-	for page := 0; page < maxPages; page++ {
+	for {
 		query := url.Values{}
 		query.Set("maxResults", strconv.Itoa(defaultPageSize))
+		if prefix != "" {
+			query.Set("prefix", prefix)
+		}
 		if pageToken != "" {
 			query.Set("pageToken", pageToken)
 		}
@@ -114,22 +123,31 @@ func (d *Driver) listObjects(ctx context.Context, bucket string) ([]schema.Bucke
 			Query:      query,
 			Idempotent: true,
 		}, &resp); err != nil {
-			return out, err
+			return err
 		}
 		for _, item := range resp.Items {
 			size, _ := strconv.ParseInt(item.Size, 10, 64)
-			out = append(out, schema.BucketObject{
-				BucketName:   item.Bucket,
+			name := item.Bucket
+			if name == "" {
+				name = bucket
+			}
+			more, err := walk.Add(schema.BucketObject{
+				BucketName:   name,
 				Key:          item.Name,
 				Size:         size,
 				LastModified: item.Updated,
 				StorageClass: item.StorageClass,
 			})
+			if err != nil || !more {
+				return err
+			}
 		}
 		if resp.NextPageToken == "" {
-			break
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 		pageToken = resp.NextPageToken
 	}
-	return out, nil
 }
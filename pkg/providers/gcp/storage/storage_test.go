@@ -2,12 +2,20 @@ package storage
 
 import (
 	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/auth"
@@ -94,10 +102,13 @@ func TestListAndTotalObjects(t *testing.T) {
 
 func TestBucketACLAuditExposeAndUnexpose(t *testing.T) {
 	public := false
+	prevention := "inherited"
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
 		case "/token":
 			writeToken(w)
+		case "/storage/v1/b/bucket-one":
+			_, _ = w.Write([]byte(`{"name":"bucket-one","iamConfiguration":{"publicAccessPrevention":"` + prevention + `"}}`))
 		case "/storage/v1/b/bucket-one/iam":
 			switch r.Method {
 			case http.MethodGet:
@@ -141,9 +152,17 @@ func TestBucketACLAuditExposeAndUnexpose(t *testing.T) {
 	if err != nil {
 		t.Fatalf("AuditBucketACL after expose: %v", err)
 	}
-	if len(entries) != 1 || entries[0].Level != "Public" {
+	if len(entries) != 1 || entries[0].Level != "Public" || entries[0].Exposure != "public-read" {
 		t.Fatalf("expected public bucket, got %+v", entries)
 	}
+	prevention = "enforced"
+	entries, err = driver.AuditBucketACL(context.Background(), "bucket-one")
+	if err != nil {
+		t.Fatalf("AuditBucketACL with prevention enforced: %v", err)
+	}
+	if entries[0].Level != "Public" || entries[0].Exposure != "private" || !strings.Contains(entries[0].Reason, "public access prevention enforced") {
+		t.Fatalf("expected prevention to void allUsers, got %+v", entries[0])
+	}
 	if err := driver.UnexposeBucket(context.Background(), "bucket-one"); err != nil {
 		t.Fatalf("UnexposeBucket: %v", err)
 	}
@@ -195,3 +214,96 @@ func policyHasMember(policy api.GCSPolicy, member string) bool {
 	}
 	return false
 }
+
+func TestAccessObjectRoundTripAndSignedURL(t *testing.T) {
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-GUploader-UploadID", "upload-"+r.Method)
+		switch {
+		case r.URL.Path == "/token":
+			writeToken(w)
+		case r.URL.Path == "/upload/storage/v1/b/bucket-one/o" && r.Method == http.MethodPost:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Query().Get("name")] = body
+			_, _ = w.Write([]byte(`{"name":"` + r.URL.Query().Get("name") + `"}`))
+		case r.URL.Path == "/storage/v1/b/bucket-one/o/dir/a.txt":
+			if r.URL.EscapedPath() != "/storage/v1/b/bucket-one/o/dir%2Fa.txt" {
+				t.Fatalf("object name not escaped: %s", r.URL.EscapedPath())
+			}
+			if r.Method == http.MethodDelete {
+				delete(objects, "dir/a.txt")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			if r.URL.Query().Get("alt") != "media" {
+				t.Fatalf("expected media download, got %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write(objects["dir/a.txt"])
+		case r.URL.Path == "/bucket-one/dir/a.txt":
+			if r.Header.Get("Authorization") != "" {
+				t.Fatalf("signed url request carried a bearer token")
+			}
+			_, _ = w.Write(objects["dir/a.txt"])
+		default:
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	cred := auth.Credential{Type: "service_account", ProjectID: "proj-1", PrivateKeyPEM: testutil.PKCS8PrivateKeyPEM, ClientEmail: "demo@example.com"}
+	driver := &Driver{Projects: []string{"proj-1"}, Client: newStorageClient(t, server), Credential: cred}
+	ctx := context.Background()
+	if out, err := driver.AccessObject(ctx, "put", "bucket-one", "dir/a.txt", []byte("canary")); err != nil || out.RequestID != "upload-POST" || out.Body != nil {
+		t.Fatalf("put: %+v %v", out, err)
+	}
+	if out, err := driver.AccessObject(ctx, "get", "bucket-one", "dir/a.txt", nil); err != nil || string(out.Body) != "canary" {
+		t.Fatalf("get: %+v %v", out, err)
+	}
+	out, err := driver.AccessObject(ctx, "presign", "bucket-one", "dir/a.txt", nil)
+	if err != nil || string(out.Body) != "canary" {
+		t.Fatalf("presign: %+v %v", out, err)
+	}
+	if !strings.HasPrefix(out.URL, "https://storage.googleapis.com/bucket-one/dir/a.txt?X-Goog-Algorithm=GOOG4-RSA-SHA256&X-Goog-Credential=demo%40example.com%2F") {
+		t.Fatalf("unexpected signed url: %s", out.URL)
+	}
+	if out, err := driver.AccessObject(ctx, "delete", "bucket-one", "dir/a.txt", nil); err != nil || out.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete: %+v %v", out, err)
+	}
+	if len(objects) != 0 {
+		t.Fatalf("object not deleted: %v", objects)
+	}
+}
+
+func TestSignedURLVerifiesWithServiceAccountKey(t *testing.T) {
+	cred := auth.Credential{Type: "service_account", ProjectID: "proj-1", PrivateKeyPEM: testutil.PKCS8PrivateKeyPEM, ClientEmail: "demo@example.com"}
+	now := time.Date(2026, 4, 20, 10, 15, 0, 0, time.UTC)
+	raw, err := signedURL(cred, http.MethodGet, "bucket-one", "dir/a.txt", now, 5*time.Minute)
+	if err != nil {
+		t.Fatalf("signedURL: %v", err)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	query, signature, _ := strings.Cut(u.RawQuery, "&X-Goog-Signature=")
+	if !strings.Contains(query, "X-Goog-Date=20260420T101500Z&X-Goog-Expires=300&X-Goog-SignedHeaders=host") {
+		t.Fatalf("unexpected query: %s", query)
+	}
+	canonical := "GET\n/bucket-one/dir/a.txt\n" + query + "\nhost:storage.googleapis.com\n\nhost\nUNSIGNED-PAYLOAD"
+	canonicalHash := sha256.Sum256([]byte(canonical))
+	stringToSign := "GOOG4-RSA-SHA256\n20260420T101500Z\n20260420/auto/storage/goog4_request\n" + hex.EncodeToString(canonicalHash[:])
+	digest := sha256.Sum256([]byte(stringToSign))
+
+	block, _ := pem.Decode([]byte(testutil.RSAPublicKeyPEM))
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("parse public key: %v", err)
+	}
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		t.Fatalf("decode signature: %v", err)
+	}
+	if err := rsa.VerifyPKCS1v15(pub.(*rsa.PublicKey), crypto.SHA256, digest[:], sig); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+}
@@ -7,24 +7,36 @@
 //  1. instance.get to read current metadata + fingerprint
 //  2. instance.setMetadata to add a `startup-script` key with the command
 //  3. instance.reset to trigger the startup script on next boot
+//  4. instance.getSerialPortOutput until the marked output block appears
+//  5. instance.setMetadata to put the previous `startup-script` back
 //
-// We do NOT capture stdout — startup-script output goes to the serial
-// console which requires a separate IAM permission to read. The instance-cmd
-// payload surfaces "command queued" with a clear note. Operators should
-// follow up with `gcloud compute instances get-serial-port-output` to read
-// the result. This is documented in the demo replay.
+// setMetadata and reset return zone operations that finish asynchronously;
+// each is polled until DONE so the reboot never races the metadata write and
+// the restore has landed before Execute returns.
+//
+// The guest agent copies startup-script output to serial port 1, so the
+// command runs inside a wrapper that prints its combined output between
+// begin/end markers carrying a per-run nonce. Reading the serial console
+// needs compute.instances.getSerialPortOutput; the driver checks it before
+// touching the instance so a caller without it does not reboot a VM blind.
 package vmexec
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
@@ -33,11 +45,30 @@ const startupScriptKey = "startup-script"
 type Driver struct {
 	Projects []string
 	Client   *api.Client
+
+	// pollInterval / maxPolls / sleep are wired up by tests so the polling
+	// loops do not actually wait. Production paths use the defaults, which
+	// also stop sleeping as soon as the context is cancelled.
+	pollInterval time.Duration
+	maxPolls     int
+	sleep        func(time.Duration)
+}
+
+// SetPollOptions overrides the default serial console polling cadence. Used
+// by tests; the production driver should keep the defaults (5s interval, 60
+// polls), which leave a VM about five minutes to reboot and run the script.
+func (d *Driver) SetPollOptions(interval time.Duration, max int, sleep func(time.Duration)) {
+	d.pollInterval = interval
+	d.maxPolls = max
+	d.sleep = sleep
 }
 
-// Execute writes a startup-script and reboots `instanceID`. instanceID may be
-// `<zone>/<instance>` (preferred) or just `<instance>` (driver scans the first
-// configured project's zones for a matching instance).
+// Execute writes a marked startup-script, reboots `instanceID` and reads the
+// command output back from the serial console. The previous startup-script
+// (or its absence) is restored once the run finishes or times out.
+// instanceID may be `<zone>/<instance>` (preferred) or just `<instance>`
+// (driver scans the first configured project's zones for a matching
+// instance).
 func (d *Driver) Execute(ctx context.Context, instanceID, command string) (schema.CommandResult, error) {
 	if d == nil || d.Client == nil {
 		return schema.CommandResult{}, errors.New("gcp compute: nil api client")
@@ -53,20 +84,78 @@ func (d *Driver) Execute(ctx context.Context, instanceID, command string) (schem
 	if err != nil {
 		return schema.CommandResult{}, fmt.Errorf("get instance: %w", err)
 	}
-	updated := mergeStartupScript(current.Metadata, wrapStartupScript(command))
+	serial, err := d.getSerialPortOutput(ctx, project, zone, instance, 0)
+	if err != nil {
+		return schema.CommandResult{}, fmt.Errorf("getSerialPortOutput: %w", err)
+	}
+	nonce, err := newMarkerNonce()
+	if err != nil {
+		return schema.CommandResult{}, err
+	}
+	previous, hadPrevious := startupScript(current.Metadata)
+	updated := mergeStartupScript(current.Metadata, markedStartupScript(wrapStartupScript(command), nonce))
 	if err := d.setMetadata(ctx, project, zone, instance, updated); err != nil {
 		return schema.CommandResult{}, fmt.Errorf("setMetadata: %w", err)
 	}
+
+	result, runErr := d.run(ctx, project, zone, instance, nonce, serial.Next)
+	if err := d.restoreStartupScript(context.WithoutCancel(ctx), project, zone, instance, previous, hadPrevious); err != nil {
+		return result, errors.Join(runErr, fmt.Errorf("restore startup-script: %w", err))
+	}
+	return result, runErr
+}
+
+// run resets the instance and polls the serial console from offset `start`
+// until the block marked with `nonce` is complete.
+func (d *Driver) run(ctx context.Context, project, zone, instance, nonce string, start int64) (schema.CommandResult, error) {
 	if err := d.reset(ctx, project, zone, instance); err != nil {
 		return schema.CommandResult{}, fmt.Errorf("reset: %w", err)
 	}
-	return schema.CommandResult{
-		Output: fmt.Sprintf(
-			"Command queued via startup-script on projects/%s/zones/%s/instances/%s. "+
-				"Reboot triggered; read serial console for output.",
-			project, zone, instance,
-		),
-	}, nil
+	var console strings.Builder
+	for attempts := 0; attempts < d.pollLimit(); attempts++ {
+		if attempts > 0 {
+			if err := d.sleepFor(ctx, d.pollDelay()); err != nil {
+				return schema.CommandResult{}, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return schema.CommandResult{}, err
+		}
+		serial, err := d.getSerialPortOutput(ctx, project, zone, instance, start)
+		if err != nil {
+			return schema.CommandResult{}, fmt.Errorf("getSerialPortOutput: %w", err)
+		}
+		console.WriteString(serial.Contents)
+		start = serial.Next
+		output, exitCode, ok := extractMarkedOutput(console.String(), nonce)
+		if !ok {
+			continue
+		}
+		result := schema.CommandResult{Output: output, ExitCode: &exitCode, Status: "Success"}
+		if exitCode != 0 {
+			result.Status = "Failed"
+			return result, fmt.Errorf("gcp compute: startup-script exited with code %d", exitCode)
+		}
+		return result, nil
+	}
+	return schema.CommandResult{Status: "Timeout"}, fmt.Errorf(
+		"gcp compute: no marked output on the serial console of projects/%s/zones/%s/instances/%s in time",
+		project, zone, instance)
+}
+
+// restoreStartupScript re-reads the fingerprint (the injected script bumped
+// it) and puts the previous startup-script back, or drops the key when the
+// instance had none.
+func (d *Driver) restoreStartupScript(ctx context.Context, project, zone, instance, previous string, hadPrevious bool) error {
+	current, err := d.getInstance(ctx, project, zone, instance)
+	if err != nil {
+		return err
+	}
+	restored := removeStartupScript(current.Metadata)
+	if hadPrevious {
+		restored = mergeStartupScript(current.Metadata, previous)
+	}
+	return d.setMetadata(ctx, project, zone, instance, restored)
 }
 
 func (d *Driver) resolveTarget(ctx context.Context, raw string) (string, string, string, error) {
@@ -137,7 +226,7 @@ func (d *Driver) setMetadata(ctx context.Context, project, zone, instance string
 	}, &op); err != nil {
 		return err
 	}
-	return operationError(op)
+	return d.waitOperation(ctx, project, zone, op)
 }
 
 func (d *Driver) reset(ctx context.Context, project, zone, instance string) error {
@@ -150,7 +239,54 @@ func (d *Driver) reset(ctx context.Context, project, zone, instance string) erro
 	}, &op); err != nil {
 		return err
 	}
-	return operationError(op)
+	return d.waitOperation(ctx, project, zone, op)
+}
+
+// waitOperation polls the zone operation until it is DONE and returns its
+// error, if any. An operation without a name cannot be polled and is taken
+// as returned.
+func (d *Driver) waitOperation(ctx context.Context, project, zone string, op api.ComputeOperation) error {
+	for attempts := 0; ; attempts++ {
+		if err := operationError(op); err != nil {
+			return err
+		}
+		if op.Status == "DONE" || op.Name == "" {
+			return nil
+		}
+		if attempts >= d.pollLimit() {
+			return fmt.Errorf("gcp compute: operation %s still %s after %d polls", op.Name, op.Status, attempts)
+		}
+		if err := d.sleepFor(ctx, d.pollDelay()); err != nil {
+			return err
+		}
+		name := op.Name
+		op = api.ComputeOperation{}
+		if err := d.Client.Do(ctx, api.Request{
+			Method:     http.MethodGet,
+			BaseURL:    api.ComputeBaseURL,
+			Path:       fmt.Sprintf("/compute/v1/projects/%s/zones/%s/operations/%s", url.PathEscape(project), url.PathEscape(zone), url.PathEscape(name)),
+			Idempotent: true,
+		}, &op); err != nil {
+			return fmt.Errorf("operation %s: %w", name, err)
+		}
+	}
+}
+
+func (d *Driver) getSerialPortOutput(ctx context.Context, project, zone, instance string, start int64) (api.SerialPortOutput, error) {
+	query := url.Values{}
+	query.Set("port", "1")
+	if start > 0 {
+		query.Set("start", strconv.FormatInt(start, 10))
+	}
+	var resp api.SerialPortOutput
+	err := d.Client.Do(ctx, api.Request{
+		Method:     http.MethodGet,
+		BaseURL:    api.ComputeBaseURL,
+		Path:       fmt.Sprintf("/compute/v1/projects/%s/zones/%s/instances/%s/serialPort", url.PathEscape(project), url.PathEscape(zone), url.PathEscape(instance)),
+		Query:      query,
+		Idempotent: true,
+	}, &resp)
+	return resp, err
 }
 
 func operationError(op api.ComputeOperation) error {
@@ -182,6 +318,30 @@ func mergeStartupScript(existing api.InstanceMetadata, script string) api.Instan
 	return out
 }
 
+// removeStartupScript drops the startup-script key, keeping the other items
+// and the fingerprint.
+func removeStartupScript(existing api.InstanceMetadata) api.InstanceMetadata {
+	out := api.InstanceMetadata{
+		Fingerprint: existing.Fingerprint,
+		Items:       make([]api.InstanceMetadataItem, 0, len(existing.Items)),
+	}
+	for _, item := range existing.Items {
+		if item.Key != startupScriptKey {
+			out.Items = append(out.Items, item)
+		}
+	}
+	return out
+}
+
+func startupScript(metadata api.InstanceMetadata) (string, bool) {
+	for _, item := range metadata.Items {
+		if item.Key == startupScriptKey {
+			return item.Value, true
+		}
+	}
+	return "", false
+}
+
 // wrapStartupScript adds a small shebang so plain `cmd` strings work even when
 // callers pass single-line shell snippets without explicit interpreter.
 func wrapStartupScript(command string) string {
@@ -192,6 +352,95 @@ func wrapStartupScript(command string) string {
 	return "#!/bin/bash\nset -e\n" + command + "\n"
 }
 
+// markedStartupScript runs `script` from a temp file and prints its combined
+// output base64-encoded between the begin/end markers, with the exit code on
+// the end line. The guest agent prefixes each line it copies to the serial
+// console and boot messages may interleave, so every payload line is tagged
+// with the nonce as well.
+func markedStartupScript(script, nonce string) string {
+	return fmt.Sprintf(`#!/bin/bash
+ctk_script=$(mktemp)
+cat > "$ctk_script" <<'CTK_SCRIPT_%[1]s'
+%[2]s
+CTK_SCRIPT_%[1]s
+chmod +x "$ctk_script"
+ctk_out=$("$ctk_script" 2>&1)
+ctk_rc=$?
+rm -f "$ctk_script"
+echo "%[3]s"
+printf '%%s' "$ctk_out" | base64 | sed 's/^/%[4]s /'
+echo "%[5]s rc=$ctk_rc"
+`, nonce, strings.TrimRight(script, "\n"), beginMarker(nonce), dataMarker(nonce), endMarker(nonce))
+}
+
+func beginMarker(nonce string) string { return "CTK-BEGIN-" + nonce }
+
+func dataMarker(nonce string) string { return "CTK-DATA-" + nonce }
+
+func endMarker(nonce string) string { return "CTK-END-" + nonce }
+
+// extractMarkedOutput decodes the block markedStartupScript printed. ok is
+// false until both markers have reached the serial console.
+func extractMarkedOutput(console, nonce string) (string, int, bool) {
+	begin, data, end := beginMarker(nonce), dataMarker(nonce), endMarker(nonce)
+	var payload strings.Builder
+	inBlock := false
+	for _, line := range strings.Split(console, "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case !inBlock && strings.HasSuffix(line, begin):
+			inBlock = true
+			payload.Reset()
+		case inBlock && strings.Contains(line, end):
+			_, rc, _ := strings.Cut(line[strings.Index(line, end)+len(end):], "rc=")
+			code, err := strconv.Atoi(strings.TrimSpace(rc))
+			if err != nil {
+				code = -1
+			}
+			decoded, err := base64.StdEncoding.DecodeString(payload.String())
+			if err != nil {
+				return payload.String(), code, true
+			}
+			return string(decoded), code, true
+		case inBlock:
+			if i := strings.Index(line, data); i >= 0 {
+				payload.WriteString(strings.TrimSpace(line[i+len(data):]))
+			}
+		}
+	}
+	return "", 0, false
+}
+
+func newMarkerNonce() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("gcp compute: marker nonce: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (d *Driver) pollDelay() time.Duration {
+	if d.pollInterval > 0 {
+		return d.pollInterval
+	}
+	return 5 * time.Second
+}
+
+func (d *Driver) pollLimit() int {
+	if d.maxPolls > 0 {
+		return d.maxPolls
+	}
+	return 60
+}
+
+func (d *Driver) sleepFor(ctx context.Context, delay time.Duration) error {
+	if d.sleep != nil {
+		d.sleep(delay)
+		return ctx.Err()
+	}
+	return httpclient.SleepWithContext(ctx, delay)
+}
+
 // listZones / listInstanceNames are minimal helpers used by the resolver path.
 func (d *Driver) listZones(ctx context.Context, project string) ([]api.Zone, error) {
 	var resp api.ListZonesResponse
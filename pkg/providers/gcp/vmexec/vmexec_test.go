@@ -2,10 +2,16 @@ package vmexec
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp/auth"
@@ -32,54 +38,156 @@ func newClient(t *testing.T, server *httptest.Server) *api.Client {
 	return api.NewClient(ts, api.WithHTTPClient(httpClient))
 }
 
-func TestExecuteSetsStartupScriptAndResets(t *testing.T) {
-	var sawSetMetadata, sawReset bool
-	var setMetadataBody string
-	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path == "/token" {
-			_, _ = w.Write([]byte(`{"access_token":"demo","token_type":"Bearer","expires_in":3600}`))
-			return
+// fakeInstance serves the instances.get / setMetadata / reset /
+// getSerialPortOutput slice the driver walks, writing the marked block for
+// the injected startup-script to the serial console on reset. With pending
+// set, setMetadata and reset return running operations that are DONE on the
+// first zoneOperations.get; calls logs the mutations and operation polls in
+// order.
+type fakeInstance struct {
+	t         *testing.T
+	items     []api.InstanceMetadataItem
+	serial    string
+	setBodies []string
+	resets    int
+	output    string
+	rc        int
+	silent    bool
+	pending   bool
+	calls     []string
+}
+
+// operation answers a mutating call with a DONE operation, or a RUNNING one
+// when the fake is pending.
+func (f *fakeInstance) operation(w http.ResponseWriter, name string) {
+	status := "DONE"
+	if f.pending {
+		status = "RUNNING"
+	}
+	_, _ = fmt.Fprintf(w, `{"name":%q,"status":%q}`, name, status)
+}
+
+func (f *fakeInstance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/token" {
+		_, _ = w.Write([]byte(`{"access_token":"demo","token_type":"Bearer","expires_in":3600}`))
+		return
+	}
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/instances/vm-1") && r.Method == http.MethodGet:
+		_ = json.NewEncoder(w).Encode(api.InstanceWithMetadata{
+			Name:     "vm-1",
+			Zone:     "us-central1-a",
+			Status:   "RUNNING",
+			Metadata: api.InstanceMetadata{Fingerprint: fmt.Sprintf("FP%d", len(f.setBodies)+1), Items: f.items},
+		})
+	case strings.HasSuffix(r.URL.Path, "/instances/vm-1/setMetadata"):
+		body, _ := io.ReadAll(r.Body)
+		f.setBodies = append(f.setBodies, string(body))
+		var metadata api.InstanceMetadata
+		if err := json.Unmarshal(body, &metadata); err != nil {
+			f.t.Fatalf("decode setMetadata: %v", err)
 		}
-		switch {
-		case strings.HasSuffix(r.URL.Path, "/instances/vm-1") && r.Method == http.MethodGet:
-			_, _ = w.Write([]byte(`{"name":"vm-1","zone":"us-central1-a","status":"RUNNING","metadata":{"fingerprint":"FP1","items":[{"key":"foo","value":"bar"}]}}`))
-		case strings.HasSuffix(r.URL.Path, "/instances/vm-1/setMetadata"):
-			sawSetMetadata = true
-			buf := make([]byte, r.ContentLength)
-			_, _ = r.Body.Read(buf)
-			setMetadataBody = string(buf)
-			_, _ = w.Write([]byte(`{"name":"op-set","status":"DONE"}`))
-		case strings.HasSuffix(r.URL.Path, "/instances/vm-1/reset"):
-			sawReset = true
-			_, _ = w.Write([]byte(`{"name":"op-reset","status":"DONE"}`))
-		default:
-			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		f.items = metadata.Items
+		f.calls = append(f.calls, "setMetadata")
+		f.operation(w, fmt.Sprintf("op-set-%d", len(f.setBodies)))
+	case strings.HasSuffix(r.URL.Path, "/instances/vm-1/reset"):
+		f.resets++
+		f.calls = append(f.calls, "reset")
+		f.serial += "[    1.204] systemd[1]: Started Google Compute Engine Startup Scripts.\n"
+		if script, ok := startupScript(api.InstanceMetadata{Items: f.items}); ok && !f.silent {
+			nonce := script[strings.Index(script, "CTK-BEGIN-")+len("CTK-BEGIN-"):]
+			nonce = nonce[:strings.IndexAny(nonce, "\"\n")]
+			prefix := "vm-1 google_metadata_script_runner[812]: startup-script: "
+			f.serial += prefix + "CTK-BEGIN-" + nonce + "\n"
+			f.serial += "[    2.731] kernel: random: crng init done\n"
+			f.serial += prefix + "CTK-DATA-" + nonce + " " + base64.StdEncoding.EncodeToString([]byte(f.output)) + "\n"
+			f.serial += prefix + fmt.Sprintf("CTK-END-%s rc=%d", nonce, f.rc) + "\n"
 		}
-	}))
-	defer server.Close()
+		f.operation(w, "op-reset")
+	case strings.Contains(r.URL.Path, "/zones/us-central1-a/operations/") && r.Method == http.MethodGet:
+		name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		f.calls = append(f.calls, "wait "+name)
+		_, _ = fmt.Fprintf(w, `{"name":%q,"status":"DONE"}`, name)
+	case strings.HasSuffix(r.URL.Path, "/instances/vm-1/serialPort"):
+		if got := r.URL.Query().Get("port"); got != "1" {
+			f.t.Fatalf("unexpected serial port: %s", got)
+		}
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		if start > len(f.serial) {
+			start = len(f.serial)
+		}
+		_, _ = fmt.Fprintf(w, `{"contents":%q,"start":"%d","next":"%d"}`, f.serial[start:], start, len(f.serial))
+	default:
+		f.t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}
+}
 
+func newFakeInstanceDriver(t *testing.T, fake *fakeInstance) *Driver {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
 	driver := &Driver{Client: newClient(t, server), Projects: []string{"proj-1"}}
-	res, err := driver.Execute(context.Background(), "us-central1-a/vm-1", "echo hello")
+	driver.SetPollOptions(time.Millisecond, 3, func(time.Duration) {})
+	return driver
+}
+
+func TestExecuteCapturesSerialOutputAndRestoresScript(t *testing.T) {
+	fake := &fakeInstance{
+		t:      t,
+		items:  []api.InstanceMetadataItem{{Key: "foo", Value: "bar"}, {Key: "startup-script", Value: "#!/bin/bash\n/opt/boot.sh\n"}},
+		serial: "[    0.000] Linux version 6.1.0\n",
+		output: "hello\nworld\n",
+	}
+	res, err := newFakeInstanceDriver(t, fake).Execute(context.Background(), "us-central1-a/vm-1", "echo hello")
 	if err != nil {
 		t.Fatalf("Execute: %v", err)
 	}
-	if !sawSetMetadata {
-		t.Error("expected setMetadata call")
+	if res.Output != "hello\nworld\n" || res.ExitCode == nil || *res.ExitCode != 0 || res.Status != "Success" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if fake.resets != 1 || len(fake.setBodies) != 2 {
+		t.Fatalf("expected 1 reset and 2 setMetadata calls, got %d and %d", fake.resets, len(fake.setBodies))
+	}
+	injected := fake.setBodies[0]
+	if !strings.Contains(injected, `"fingerprint":"FP1"`) || !strings.Contains(injected, `"key":"foo"`) {
+		t.Errorf("expected fingerprint and existing items passed through, got: %s", injected)
+	}
+	if !strings.Contains(injected, "echo hello") || !strings.Contains(injected, "CTK-BEGIN-") {
+		t.Errorf("expected marked command in startup-script, got: %s", injected)
+	}
+	restored := fake.setBodies[1]
+	if !strings.Contains(restored, `"fingerprint":"FP2"`) || !strings.Contains(restored, `/opt/boot.sh`) || strings.Contains(restored, "CTK-BEGIN-") {
+		t.Errorf("expected previous startup-script restored, got: %s", restored)
 	}
-	if !sawReset {
-		t.Error("expected reset call")
+}
+
+func TestExecuteReportsNonZeroExit(t *testing.T) {
+	fake := &fakeInstance{t: t, output: "ls: cannot access '/nope'", rc: 2}
+	res, err := newFakeInstanceDriver(t, fake).Execute(context.Background(), "us-central1-a/vm-1", "ls /nope")
+	if err == nil {
+		t.Fatal("expected error for non-zero exit")
+	}
+	if res.ExitCode == nil || *res.ExitCode != 2 || res.Output != "ls: cannot access '/nope'" {
+		t.Fatalf("unexpected result: %+v", res)
 	}
-	if !strings.Contains(setMetadataBody, "startup-script") {
-		t.Errorf("setMetadata body missing startup-script key: %s", setMetadataBody)
+	if _, ok := startupScript(api.InstanceMetadata{Items: fake.items}); ok {
+		t.Errorf("expected injected startup-script removed, got %+v", fake.items)
+	}
+}
+
+func TestExecuteTimesOutAndStillRestores(t *testing.T) {
+	fake := &fakeInstance{t: t, silent: true}
+	res, err := newFakeInstanceDriver(t, fake).Execute(context.Background(), "us-central1-a/vm-1", "echo hi")
+	if err == nil || !strings.Contains(err.Error(), "no marked output") {
+		t.Fatalf("expected timeout error, got %v", err)
 	}
-	if !strings.Contains(setMetadataBody, `"fingerprint":"FP1"`) {
-		t.Errorf("expected fingerprint passthrough, got: %s", setMetadataBody)
+	if res.Status != "Timeout" {
+		t.Errorf("unexpected status: %q", res.Status)
 	}
-	if !strings.Contains(setMetadataBody, `"key":"foo"`) {
-		t.Errorf("expected existing metadata items preserved, got: %s", setMetadataBody)
+	if len(fake.setBodies) != 2 {
+		t.Fatalf("expected restore after timeout, got %d setMetadata calls", len(fake.setBodies))
 	}
-	if !strings.Contains(res.Output, "Reboot triggered") {
-		t.Errorf("unexpected output: %q", res.Output)
+	if _, ok := startupScript(api.InstanceMetadata{Items: fake.items}); ok {
+		t.Errorf("expected injected startup-script removed, got %+v", fake.items)
 	}
 }
 
@@ -99,6 +207,8 @@ func TestExecutePropagatesOperationError(t *testing.T) {
 		switch {
 		case strings.HasSuffix(r.URL.Path, "/instances/vm-1") && r.Method == http.MethodGet:
 			_, _ = w.Write([]byte(`{"name":"vm-1","metadata":{"fingerprint":"FP","items":[]}}`))
+		case strings.HasSuffix(r.URL.Path, "/serialPort"):
+			_, _ = w.Write([]byte(`{"contents":"","start":"0","next":"0"}`))
 		case strings.HasSuffix(r.URL.Path, "/setMetadata"):
 			_, _ = w.Write([]byte(`{"name":"op","status":"DONE","error":{"errors":[{"code":"PERMISSION_DENIED","message":"missing compute.instances.setMetadata"}]}}`))
 		default:
@@ -136,3 +246,58 @@ func TestMergeStartupScriptDeduplicates(t *testing.T) {
 		t.Errorf("expected new startup-script, got %+v", merged.Items[1])
 	}
 }
+
+func TestExtractMarkedOutputSkipsInterleavedLines(t *testing.T) {
+	console := strings.Join([]string{
+		"vm startup-script: CTK-BEGIN-abc",
+		"[    3.1] kernel: eth0: link up",
+		"vm startup-script: CTK-DATA-abc " + base64.StdEncoding.EncodeToString([]byte("uid=0(root)")),
+		"vm startup-script: CTK-END-abc rc=0",
+	}, "\n")
+	output, code, ok := extractMarkedOutput(console, "abc")
+	if !ok || code != 0 || output != "uid=0(root)" {
+		t.Fatalf("got %q, %d, %v", output, code, ok)
+	}
+	if _, _, ok := extractMarkedOutput("vm startup-script: CTK-BEGIN-abc\n", "abc"); ok {
+		t.Fatal("expected an open block to be incomplete")
+	}
+}
+
+func TestExecuteWaitsForOperations(t *testing.T) {
+	fake := &fakeInstance{t: t, output: "ok", pending: true}
+	if _, err := newFakeInstanceDriver(t, fake).Execute(context.Background(), "us-central1-a/vm-1", "true"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := []string{"setMetadata", "wait op-set-1", "reset", "wait op-reset", "setMetadata", "wait op-set-2"}
+	if strings.Join(fake.calls, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("calls = %v, want %v", fake.calls, want)
+	}
+}
+
+func TestExecuteStopsPollingWhenCancelled(t *testing.T) {
+	fake := &fakeInstance{t: t, silent: true}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel once the first post-reset serial read is answered, so the next
+	// step is the hour-long poll sleep.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.ServeHTTP(w, r)
+		if strings.HasSuffix(r.URL.Path, "/serialPort") && fake.resets > 0 {
+			cancel()
+		}
+	}))
+	t.Cleanup(server.Close)
+	driver := &Driver{Client: newClient(t, server), Projects: []string{"proj-1"}}
+	driver.SetPollOptions(time.Hour, 3, nil)
+
+	started := time.Now()
+	if _, err := driver.Execute(ctx, "us-central1-a/vm-1", "echo hi"); err == nil {
+		t.Fatal("expected an error after cancellation")
+	}
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Fatalf("Execute kept sleeping for %s after cancellation", elapsed)
+	}
+	if _, ok := startupScript(api.InstanceMetadata{Items: fake.items}); ok {
+		t.Errorf("expected injected startup-script removed, got %+v", fake.items)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const (
@@ -32,7 +35,7 @@ func NewHTTPClient() *http.Client {
 	transport.ExpectContinueTimeout = defaultExpectContinue
 
 	return &http.Client{
-		Transport: transport,
+		Transport: ratelimit.Wrap("huawei", telemetry.Wrap("huawei", transport)),
 		Timeout:   DefaultTimeout,
 	}
 }
@@ -5,6 +5,7 @@ type ShowCustomerAccountBalancesResponse struct {
 }
 
 type AccountBalance struct {
-	AccountType int32 `json:"account_type"`
-	Amount      any   `json:"amount"`
+	AccountType int32  `json:"account_type"`
+	Amount      any    `json:"amount"`
+	Currency    string `json:"currency"`
 }
@@ -6,10 +6,18 @@ type ListECSServersDetailsResponse struct {
 }
 
 type ECSServerDetail struct {
-	ID        string                        `json:"id"`
-	Status    string                        `json:"status"`
-	Name      string                        `json:"name"`
-	Addresses map[string][]ECSServerAddress `json:"addresses"`
+	ID             string                        `json:"id"`
+	Status         string                        `json:"status"`
+	Name           string                        `json:"name"`
+	Addresses      map[string][]ECSServerAddress `json:"addresses"`
+	SecurityGroups []ECSServerSecurityGroup      `json:"security_groups"`
+	// Tags are reported as "key=value" strings.
+	Tags []string `json:"tags"`
+}
+
+type ECSServerSecurityGroup struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
 }
 
 type ECSServerAddress struct {
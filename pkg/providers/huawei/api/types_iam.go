@@ -10,7 +10,8 @@ type Region struct {
 
 type ShowPermanentAccessKeyResponse struct {
 	Credential struct {
-		UserID string `json:"user_id"`
+		UserID      string `json:"user_id"`
+		LastUseTime string `json:"last_use_time,omitempty"`
 	} `json:"credential"`
 	ErrorMsg string `json:"error_msg"`
 }
@@ -49,6 +50,29 @@ type ShowUserResponse struct {
 	} `json:"user"`
 }
 
+// ShowUserDetailResponse is the IAM v3.0 OS-USER view of a user, which
+// carries the console access mode and the last login time.
+type ShowUserDetailResponse struct {
+	User IAMUserDetail `json:"user"`
+}
+
+type IAMUserDetail struct {
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Enabled       bool   `json:"enabled"`
+	AccessMode    string `json:"access_mode,omitempty"`
+	CreateTime    string `json:"create_time,omitempty"`
+	LastLoginTime string `json:"last_login_time,omitempty"`
+}
+
+type ShowUserLoginProtectResponse struct {
+	LoginProtect struct {
+		UserID             string `json:"user_id"`
+		Enabled            bool   `json:"enabled"`
+		VerificationMethod string `json:"verification_method"`
+	} `json:"login_protect"`
+}
+
 type ListUsersResponse struct {
 	Users []IAMUser `json:"users"`
 }
@@ -0,0 +1,29 @@
+package api
+
+// ListSecurityGroupsResponse is the VPC v1 security group listing. Rules are
+// embedded in each group, so one call per page covers both.
+type ListSecurityGroupsResponse struct {
+	SecurityGroups []VPCSecurityGroup `json:"security_groups"`
+}
+
+type VPCSecurityGroup struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	VpcID       string                 `json:"vpc_id"`
+	Rules       []VPCSecurityGroupRule `json:"security_group_rules"`
+}
+
+// VPCSecurityGroupRule is a v1 rule. v1 rules are always "allow"; a nil
+// port bound means every port.
+type VPCSecurityGroupRule struct {
+	ID             string `json:"id"`
+	Description    string `json:"description"`
+	Direction      string `json:"direction"`
+	Ethertype      string `json:"ethertype"`
+	Protocol       string `json:"protocol"`
+	PortRangeMin   *int   `json:"port_range_min"`
+	PortRangeMax   *int   `json:"port_range_max"`
+	RemoteIPPrefix string `json:"remote_ip_prefix"`
+	RemoteGroupID  string `json:"remote_group_id"`
+}
@@ -7,7 +7,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/auth"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -22,10 +22,10 @@ func (d *Driver) client() *api.Client {
 	return d.Client
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 
@@ -37,13 +37,27 @@ func (d *Driver) QueryAccountBalance(ctx context.Context) {
 		Path:       "/v2/accounts/customer-accounts/balances",
 		Idempotent: true,
 	}, &resp); err != nil {
-		return
+		return nil
 	}
 
+	// account_type 1 is the cash account; the others are credit lines,
+	// coupons and reserved funds.
 	for _, account := range resp.AccountBalances {
-		if account.AccountType == 1 {
-			logger.Warning(fmt.Sprintf("Available cash amount: %v", account.Amount))
-			return
+		if account.AccountType != 1 || account.Amount == nil {
+			continue
 		}
+		currency := account.Currency
+		if currency == "" {
+			currency = "CNY"
+			if d.Cred.Intl {
+				currency = "USD"
+			}
+		}
+		return []schema.Balance{{
+			Kind:     schema.BalanceCredit,
+			Amount:   fmt.Sprint(account.Amount),
+			Currency: currency,
+		}}
 	}
+	return nil
 }
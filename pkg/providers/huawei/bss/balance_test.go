@@ -12,18 +12,20 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/auth"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
 func TestDriverQueryAccountBalanceUsesExpectedEndpointAndPrefersCashAccount(t *testing.T) {
 	tests := []struct {
-		name      string
-		intl      bool
-		wantHost  string
-		wantValue string
+		name         string
+		intl         bool
+		wantHost     string
+		wantValue    string
+		wantCurrency string
 	}{
-		{name: "china", intl: false, wantHost: "bss.myhuaweicloud.com", wantValue: "66.80"},
-		{name: "intl", intl: true, wantHost: "bss-intl.myhuaweicloud.com", wantValue: "88.90"},
+		{name: "china", intl: false, wantHost: "bss.myhuaweicloud.com", wantValue: "66.80", wantCurrency: "CNY"},
+		{name: "intl", intl: true, wantHost: "bss-intl.myhuaweicloud.com", wantValue: "88.90", wantCurrency: "USD"},
 	}
 
 	for _, tc := range tests {
@@ -45,16 +47,17 @@ func TestDriverQueryAccountBalanceUsesExpectedEndpointAndPrefersCashAccount(t *t
 				),
 			}
 
-			driver.QueryAccountBalance(context.Background())
+			balances := driver.QueryAccountBalance(context.Background())
 
 			if rt.gotPath != "/v2/accounts/customer-accounts/balances" {
 				t.Fatalf("unexpected path: %s", rt.gotPath)
 			}
-			if !strings.Contains(stdout.String(), "Available cash amount: "+tc.wantValue) {
-				t.Fatalf("unexpected stdout logs: %s", stdout.String())
+			want := schema.Balance{Kind: schema.BalanceCredit, Amount: tc.wantValue, Currency: tc.wantCurrency}
+			if len(balances) != 1 || balances[0] != want {
+				t.Fatalf("unexpected balances: %+v", balances)
 			}
-			if stderr.Len() != 0 {
-				t.Fatalf("unexpected stderr logs: %s", stderr.String())
+			if stdout.Len() != 0 || stderr.Len() != 0 {
+				t.Fatalf("unexpected logs: %s%s", stdout.String(), stderr.String())
 			}
 		})
 	}
@@ -166,7 +166,10 @@ func (d *Driver) ExecuteOS(ctx context.Context, instanceID, osType, command stri
 			if err != nil {
 				return schema.CommandResult{}, fmt.Errorf("fetch execution %s batch %d: %w", executeUUID, batchIndex, err)
 			}
-			return schema.CommandResult{Output: aggregateBatchOutput(batch, executeUUID, status)}, nil
+			return schema.CommandResult{
+				Output: aggregateBatchOutput(batch, executeUUID, status),
+				Status: strings.ToUpper(strings.TrimSpace(status)),
+			}, nil
 		}
 		d.sleep(pollInterval)
 	}
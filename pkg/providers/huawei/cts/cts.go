@@ -19,6 +19,8 @@ const (
 	defaultRegion    = "cn-north-4"
 	defaultPageLimit = 200
 	maxPages         = 20
+	// traceRetention is how far back CTS keeps management traces.
+	traceRetention = 7 * 24 * time.Hour
 )
 
 // Driver wraps Huawei CTS `ListTraces` so event-check can review recent
@@ -44,6 +46,17 @@ func (d *Driver) client() *api.Client {
 // DumpEvents queries CTS management traces and optionally filters the result by
 // source IP when `sourceFilter` is not empty and not "all".
 func (d *Driver) DumpEvents(ctx context.Context, sourceFilter string) ([]schema.Event, error) {
+	var q schema.EventQuery
+	if sourceFilter = strings.TrimSpace(sourceFilter); !strings.EqualFold(sourceFilter, "all") {
+		q.SourceIP = sourceFilter
+	}
+	return d.QueryEvents(ctx, q)
+}
+
+// QueryEvents queries CTS management traces matching q. ListTraces filters
+// on the time window, trace name, user and resource name; the source IP and
+// result are matched client-side.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
 	if d == nil {
 		return nil, errors.New("huawei cts: nil driver")
 	}
@@ -52,7 +65,7 @@ func (d *Driver) DumpEvents(ctx context.Context, sourceFilter string) ([]schema.
 	out := make([]schema.Event, 0)
 	regionErrs := make([]string, 0)
 	for _, region := range regions {
-		events, err := d.listRegionEvents(ctx, region, sourceFilter)
+		events, err := d.listRegionEvents(ctx, region, q)
 		if err != nil {
 			switch {
 			case api.IsProjectNotFound(err):
@@ -78,17 +91,33 @@ func (d *Driver) HandleEvents(ctx context.Context, _ string) (schema.EventAction
 	return schema.EventActionResult{}, errors.New("huawei cts: whitelist action is not supported (CTS is read-only)")
 }
 
-func (d *Driver) listRegionEvents(ctx context.Context, region, sourceFilter string) ([]schema.Event, error) {
+func (d *Driver) listRegionEvents(ctx context.Context, region string, q schema.EventQuery) ([]schema.Event, error) {
 	projectID, err := d.resolveProjectID(ctx, region)
 	if err != nil {
 		return nil, err
 	}
 
-	sourceFilter = strings.TrimSpace(sourceFilter)
 	query := url.Values{}
 	query.Set("trace_type", "system")
 	query.Set("tracker_name", "system")
 	query.Set("limit", strconv.Itoa(defaultPageLimit))
+	// CTS takes the window in milliseconds and only with both ends set.
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		from, to := q.Since, q.Until
+		if to.IsZero() {
+			to = time.Now()
+		}
+		if from.IsZero() {
+			from = to.Add(-traceRetention)
+		}
+		query.Set("from", strconv.FormatInt(from.UnixMilli(), 10))
+		query.Set("to", strconv.FormatInt(to.UnixMilli(), 10))
+	}
+	for name, value := range map[string]string{"trace_name": q.Name, "user": q.Principal, "resource_name": q.Resource} {
+		if value = strings.TrimSpace(value); value != "" && !strings.ContainsAny(value, "./:") {
+			query.Set(name, value)
+		}
+	}
 
 	out := make([]schema.Event, 0)
 	for page := 0; page < maxPages; page++ {
@@ -105,9 +134,6 @@ func (d *Driver) listRegionEvents(ctx context.Context, region, sourceFilter stri
 			return out, err
 		}
 		for _, trace := range resp.Traces {
-			if !matchSourceIP(trace.SourceIP, sourceFilter) {
-				continue
-			}
 			out = append(out, schema.Event{
 				Id:        strings.TrimSpace(trace.TraceID),
 				Name:      firstNonEmpty(trace.TraceName, trace.OperationID),
@@ -116,6 +142,7 @@ func (d *Driver) listRegionEvents(ctx context.Context, region, sourceFilter stri
 				Status:    statusLabel(trace.Code, trace.TraceRating),
 				SourceIp:  strings.TrimSpace(trace.SourceIP),
 				AccessKey: strings.TrimSpace(trace.User.AccessKeyID),
+				Principal: firstNonEmpty(trace.User.UserName, trace.User.Name),
 				Time:      formatUnixMillis(trace.Time),
 			})
 		}
@@ -125,7 +152,7 @@ func (d *Driver) listRegionEvents(ctx context.Context, region, sourceFilter stri
 		}
 		query.Set("next", next)
 	}
-	return out, nil
+	return q.WithoutWindow().Filter(out), nil
 }
 
 func (d *Driver) resolveProjectID(ctx context.Context, region string) (string, error) {
@@ -179,14 +206,6 @@ func (d *Driver) resolveRegions() []string {
 	return []string{region}
 }
 
-func matchSourceIP(value, filter string) bool {
-	filter = strings.TrimSpace(filter)
-	if filter == "" || strings.EqualFold(filter, "all") {
-		return true
-	}
-	return strings.EqualFold(strings.TrimSpace(value), filter)
-}
-
 func statusLabel(code, rating string) string {
 	code = strings.TrimSpace(code)
 	if len(code) > 0 {
@@ -127,6 +127,7 @@ func (d *Driver) listRegion(ctx context.Context, region string) ([]schema.Host,
 				PrivateIpv4: privateIPv4,
 				Public:      ipv4 != "",
 				Region:      region,
+				Tags:        tagMap(instance.Tags),
 			})
 		}
 		done := len(resp.Servers) == 0 ||
@@ -197,3 +198,16 @@ func mapHostIPs(addresses map[string][]api.ECSServerAddress) (string, string) {
 	}
 	return publicIPv4, privateIPv4
 }
+
+// tagMap splits the "key=value" strings ECS reports for server tags.
+func tagMap(tags []string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		key, value, _ := strings.Cut(tag, "=")
+		out[key] = value
+	}
+	return out
+}
@@ -0,0 +1,192 @@
+package ecs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// GetSecurityGroups lists VPC security groups per region through the v1 API,
+// which embeds the rules in each group. Attached servers come from the
+// security_groups field of the ECS server details.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	logger.Info("List VPC security groups ...")
+
+	tracker := processbar.NewRegionTracker()
+	defer tracker.Finish()
+	got, regionErrs := regionrun.ForEach(ctx, d.Regions, 0, tracker, func(ctx context.Context, r string) ([]schema.SecurityGroup, error) {
+		items, err := d.listSecurityGroups(ctx, r)
+		if err != nil {
+			if api.IsProjectNotFound(err) {
+				return nil, regionrun.SkipRegion()
+			}
+			return nil, err
+		}
+		return items, nil
+	})
+	list = append(list, got...)
+	return list, regionrun.Wrap(regionErrs)
+}
+
+func (d *Driver) listSecurityGroups(ctx context.Context, region string) ([]schema.SecurityGroup, error) {
+	projectID, err := d.resolveProjectID(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+	const limit = 100
+	groups, err := paginate.Fetch(ctx, func(ctx context.Context, marker string) (paginate.Page[api.VPCSecurityGroup, string], error) {
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(limit))
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		var resp api.ListSecurityGroupsResponse
+		err := d.client().DoJSON(ctx, api.Request{
+			Service:    "vpc",
+			Region:     region,
+			Intl:       d.Cred.Intl,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/v1/%s/security-groups", projectID),
+			Query:      query,
+			Idempotent: true,
+		}, &resp)
+		if err != nil {
+			return paginate.Page[api.VPCSecurityGroup, string]{}, err
+		}
+		page := paginate.Page[api.VPCSecurityGroup, string]{
+			Items: resp.SecurityGroups,
+			Done:  len(resp.SecurityGroups) < limit,
+		}
+		if !page.Done {
+			page.Next = resp.SecurityGroups[len(resp.SecurityGroups)-1].ID
+		}
+		return page, nil
+	})
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	attached, err := d.groupAttachments(ctx, region, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		item := schema.SecurityGroup{
+			GroupID:   group.ID,
+			GroupName: group.Name,
+			VpcID:     group.VpcID,
+			Region:    region,
+			Attached:  attached[group.ID],
+		}
+		for _, rule := range group.Rules {
+			item.Rules = append(item.Rules, convertRule(rule))
+		}
+		item.FlagExposure()
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func (d *Driver) groupAttachments(ctx context.Context, region, projectID string) (map[string][]string, error) {
+	const limit = int32(100)
+	servers, err := paginate.Fetch(ctx, func(ctx context.Context, page int32) (paginate.Page[api.ECSServerDetail, int32], error) {
+		if page == 0 {
+			page = 1
+		}
+		query := url.Values{}
+		query.Set("limit", strconv.Itoa(int(limit)))
+		query.Set("offset", strconv.Itoa(int(page)))
+
+		var resp api.ListECSServersDetailsResponse
+		err := d.client().DoJSON(ctx, api.Request{
+			Service:    "ecs",
+			Region:     region,
+			Intl:       d.Cred.Intl,
+			Method:     http.MethodGet,
+			Path:       fmt.Sprintf("/v1/%s/cloudservers/detail", projectID),
+			Query:      query,
+			Idempotent: true,
+		}, &resp)
+		if err != nil {
+			return paginate.Page[api.ECSServerDetail, int32]{}, err
+		}
+		done := len(resp.Servers) == 0 ||
+			(resp.Count > 0 && page*limit >= resp.Count) ||
+			(resp.Count == 0 && int32(len(resp.Servers)) < limit)
+		return paginate.Page[api.ECSServerDetail, int32]{
+			Items: resp.Servers,
+			Next:  page + 1,
+			Done:  done,
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for _, server := range servers {
+		for _, group := range server.SecurityGroups {
+			out[group.ID] = append(out[group.ID], server.ID)
+		}
+	}
+	for groupID := range out {
+		sort.Strings(out[groupID])
+	}
+	return out, nil
+}
+
+func convertRule(rule api.VPCSecurityGroupRule) schema.FirewallRule {
+	protocol := strings.ToLower(strings.TrimSpace(rule.Protocol))
+	if protocol == "" {
+		protocol = "all"
+	}
+	ports := "all"
+	if rule.PortRangeMin != nil || rule.PortRangeMax != nil {
+		from, to := derefPort(rule.PortRangeMin), derefPort(rule.PortRangeMax)
+		if from == 0 {
+			from = to
+		}
+		if to == 0 {
+			to = from
+		}
+		ports = schema.PortRange(from, to)
+	}
+	peer := strings.TrimSpace(rule.RemoteIPPrefix)
+	if peer == "" {
+		peer = strings.TrimSpace(rule.RemoteGroupID)
+	}
+	if peer == "" {
+		// An empty remote matches every address of the rule's ethertype.
+		peer = "0.0.0.0/0"
+		if strings.EqualFold(rule.Ethertype, "IPv6") {
+			peer = "::/0"
+		}
+	}
+	return schema.FirewallRule{
+		Direction:   strings.ToLower(strings.TrimSpace(rule.Direction)),
+		Action:      "allow",
+		Protocol:    protocol,
+		PortRange:   ports,
+		CIDR:        peer,
+		Description: rule.Description,
+	}
+}
+
+func derefPort(v *int) int {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,62 @@
+package ecs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestDriverGetSecurityGroupsMapsRulesAndServers(t *testing.T) {
+	transport := &routingTransport{
+		routes: map[string]routeResponse{
+			"GET iam.cn-north-4.myhuaweicloud.com /v3/projects?name=cn-north-4": {
+				body: `{"projects":[{"id":"project-n4","name":"cn-north-4","domain_id":"d-1","enabled":true}]}`,
+			},
+			"GET vpc.cn-north-4.myhuaweicloud.com /v1/project-n4/security-groups?limit=100": {
+				body: `{"security_groups":[{"id":"sg-1","name":"web","vpc_id":"vpc-1","security_group_rules":[
+					{"direction":"ingress","ethertype":"IPv4","protocol":"tcp","port_range_min":22,"port_range_max":22,"remote_ip_prefix":"0.0.0.0/0","description":"ssh"},
+					{"direction":"ingress","ethertype":"IPv4","remote_group_id":"sg-1"},
+					{"direction":"egress","ethertype":"IPv6"}
+				]}]}`,
+			},
+			"GET ecs.cn-north-4.myhuaweicloud.com /v1/project-n4/cloudservers/detail?limit=100&offset=1": {
+				body: `{"count":2,"servers":[{"id":"i-2","security_groups":[{"id":"sg-1","name":"web"}]},{"id":"i-1","security_groups":[{"id":"sg-1","name":"web"}]}]}`,
+			},
+		},
+	}
+
+	driver := newTestDriver([]string{"cn-north-4"}, "d-1", transport)
+	var (
+		got []schema.SecurityGroup
+		err error
+	)
+	_ = captureStdout(t, func() {
+		got, err = driver.GetSecurityGroups(context.Background())
+	})
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("unexpected group count: %d", len(got))
+	}
+	group := got[0]
+	if group.GroupID != "sg-1" || group.VpcID != "vpc-1" || group.Region != "cn-north-4" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if len(group.Attached) != 2 || group.Attached[0] != "i-1" || group.Attached[1] != "i-2" {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	if len(group.Rules) != 3 {
+		t.Fatalf("unexpected rules: %+v", group.Rules)
+	}
+	if r := group.Rules[0]; r.PortRange != "22" || r.Protocol != "tcp" || !r.Exposed {
+		t.Fatalf("unexpected ssh rule: %+v", r)
+	}
+	if r := group.Rules[1]; r.CIDR != "sg-1" || r.Protocol != "all" || r.PortRange != "all" || r.Exposed {
+		t.Fatalf("unexpected group rule: %+v", r)
+	}
+	if r := group.Rules[2]; r.Direction != "egress" || r.CIDR != "::/0" {
+		t.Fatalf("unexpected egress rule: %+v", r)
+	}
+}
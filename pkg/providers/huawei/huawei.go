@@ -22,6 +22,7 @@ import (
 	_rds "github.com/404tk/cloudtoolkit/pkg/providers/huawei/rds"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/credverify"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -142,9 +143,9 @@ func (p *Provider) newOBSClient(cred huaweiauth.Credential) *_obs.Client {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
 			d := &_bss.Driver{Cred: p.cred, Client: p.newAPIClient(p.cred)}
-			d.QueryAccountBalance(ctx)
+			schema.AppendAssets(list, d.QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			cred := p.iamCredential()
@@ -198,6 +199,14 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			result, err := smsprovider.GetResource(ctx)
 			list.Sms = result
 			list.AddError("sms", err)
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			cred := p.iamCredential()
+			regions, projects := p.projectServiceRegions(ctx, "ecs")
+			ecsprovider := &ecs.Driver{Cred: cred, Regions: regions, DomainID: p.domainID, Client: p.newAPIClient(cred), ProjectCatalog: projects}
+			groups, err := ecsprovider.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -226,7 +235,12 @@ func (p *Provider) UserManagement(action, username, password string) (schema.IAM
 // bare REPL commands default to the Linux/SHELL path.
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	cred := p.iamCredential()
-	driver := &_coc.Driver{Cred: cred, Regions: p.regions, DomainID: p.domainID, Client: p.newAPIClient(cred)}
+	regions := p.regions
+	if host, ok := invcache.LookupHost(ctx, instanceID); ok && host.Region != "" {
+		// COC is regional; a cached inventory knows where the instance lives.
+		regions = []string{host.Region}
+	}
+	driver := &_coc.Driver{Cred: cred, Regions: regions, DomainID: p.domainID, Client: p.newAPIClient(cred)}
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
 		return driver.ExecuteOS(ctx, instanceID, osType, command)
 	}
@@ -280,32 +294,9 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	cred := p.iamCredential()
 	obsprovider := &_obs.Driver{Cred: cred, Regions: p.regions, Client: p.newOBSClient(cred)}
 
-	infos := make(map[string]string)
-	if bucketName == "all" {
-		buckets, err := obsprovider.GetBuckets(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("list buckets: %w", err)
-		}
-		for _, bucket := range buckets {
-			infos[bucket.BucketName] = bucket.Region
-		}
-	} else {
-		// For a specific bucket, we need to find its region first
-		buckets, err := obsprovider.GetBuckets(context.Background())
-		if err != nil {
-			return nil, fmt.Errorf("list buckets: %w", err)
-		}
-		found := false
-		for _, bucket := range buckets {
-			if bucket.BucketName == bucketName {
-				infos[bucketName] = bucket.Region
-				found = true
-				break
-			}
-		}
-		if !found {
-			return nil, fmt.Errorf("bucket %s not found", bucketName)
-		}
+	infos, err := p.bucketInfos(ctx, obsprovider, bucketName)
+	if err != nil {
+		return nil, err
 	}
 
 	switch action {
@@ -318,6 +309,54 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	cred := p.iamCredential()
+	obsprovider := &_obs.Driver{Cred: cred, Regions: p.regions, Client: p.newOBSClient(cred)}
+	infos, err := p.bucketInfos(ctx, obsprovider, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	return obsprovider.WalkObjects(ctx, infos, q, visit)
+}
+
+// ObjectAccess implements schema.ObjectAccessManager for OBS. The requests
+// appear in the bucket's access logs and, when data events are tracked, in
+// CTS under the returned request ID.
+func (p *Provider) ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	cred := p.iamCredential()
+	driver := &_obs.Driver{Cred: cred, Regions: p.regions, Client: p.newOBSClient(cred)}
+	return driver.AccessObject(ctx, action, bucket, key, body)
+}
+
+// bucketInfos maps bucketName, or every bucket for "all", to its region.
+// OBS addresses buckets by regional endpoint, so a named bucket is looked up
+// in the listing first.
+func (p *Provider) bucketInfos(ctx context.Context, driver *_obs.Driver, bucketName string) (map[string]string, error) {
+	buckets, err := driver.GetBuckets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	infos := make(map[string]string)
+	for _, bucket := range buckets {
+		if bucketName == "all" || bucket.BucketName == bucketName {
+			infos[bucket.BucketName] = bucket.Region
+		}
+	}
+	if bucketName != "all" && len(infos) == 0 {
+		return nil, fmt.Errorf("bucket %s not found", bucketName)
+	}
+	return infos, nil
+}
+
+// IAMPosture implements schema.IAMPostureReader for huawei IAM users.
+func (p *Provider) IAMPosture(ctx context.Context) ([]schema.UserPosture, error) {
+	cred := p.iamCredential()
+	driver := &_iam.Driver{Cred: cred, DomainID: p.domainID, Client: p.newAPIClient(cred)}
+	return driver.Posture(ctx)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for huawei IAM
 // permanent access keys. `principal` is the IAM user name (required for
 // create; optional for list — empty resolves to the calling principal).
@@ -429,17 +468,32 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads CTS traces across the regions with a CTS project.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	cred := p.iamCredential()
+	regions, projects := p.projectServiceRegions(ctx, "cts")
+	driver := &_cts.Driver{
+		Cred:           cred,
+		Regions:        regions,
+		DomainID:       p.domainID,
+		Client:         p.newAPIClient(cred),
+		ProjectCatalog: projects,
+	}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for Huawei RDS. `useradd` provisions
 // a database account on the named instance using the `rds-account-check`
 // config; `userdel` removes it.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
 	cred := p.iamCredential()
 	driver := &_rds.Driver{Cred: cred, Regions: p.regions, DomainID: p.domainID, Client: p.newAPIClient(cred)}
+	region := invcache.DatabaseRegion(ctx, instanceID, cred.Region)
 	switch action {
 	case "useradd":
-		return driver.CreateAccount(ctx, cred.Region, instanceID)
+		return driver.CreateAccount(ctx, region, instanceID)
 	case "userdel":
-		return driver.DeleteAccount(ctx, cred.Region, instanceID)
+		return driver.DeleteAccount(ctx, region, instanceID)
 	default:
 		return schema.DatabaseActionResult{}, fmt.Errorf("invalid action: %s (expected: useradd, userdel)", action)
 	}
@@ -0,0 +1,57 @@
+package iam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestPostureReadsIAMCredentialState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v5/users":
+			_, _ = w.Write([]byte(`{"users":[{"user_id":"u-1","user_name":"alice","enabled":true}]}`))
+		case "/v3.0/OS-USER/users/u-1":
+			_, _ = w.Write([]byte(`{"user":{"id":"u-1","name":"alice","enabled":true,"access_mode":"default","create_time":"2026-01-10 03:00:00.0","last_login_time":"2026-04-20 01:12:00.0"}}`))
+		case "/v3.0/OS-USER/users/u-1/login-protect":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":"IAM.0004","error_msg":"login protect not found"}`))
+		case "/v3.0/OS-CREDENTIAL/credentials":
+			if got := r.URL.Query().Get("user_id"); got != "u-1" {
+				t.Fatalf("unexpected user_id: %s", got)
+			}
+			_, _ = w.Write([]byte(`{"credentials":[{"access":"HWAK1","user_id":"u-1","status":"active","create_time":"2026-01-11T00:00:00.000000"}]}`))
+		case "/v3.0/OS-CREDENTIAL/credentials/HWAK1":
+			_, _ = w.Write([]byte(`{"credential":{"user_id":"u-1","last_use_time":"2026-04-21T06:37:40.123059Z"}}`))
+		default:
+			t.Fatalf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	got, err := newTestDriver(server.URL, "cn-north-4").Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(got))
+	}
+	user := got[0]
+	if !user.Password || user.MFA || len(user.Gaps) != 0 || user.HasGap(schema.PostureGapMFA) {
+		t.Fatalf("unexpected posture: %+v", user)
+	}
+	if want := time.Date(2026, 4, 20, 1, 12, 0, 0, time.UTC); !user.LastLogin.Equal(want) {
+		t.Errorf("LastLogin = %v, want %v", user.LastLogin, want)
+	}
+	if len(user.AccessKeys) != 1 {
+		t.Fatalf("expected 1 key, got %+v", user.AccessKeys)
+	}
+	key := user.AccessKeys[0]
+	if !key.Active || key.CreateTime.IsZero() || key.LastUsed.IsZero() {
+		t.Errorf("unexpected key: %+v", key)
+	}
+}
@@ -3,6 +3,7 @@ package obs
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/endpoint"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
@@ -115,6 +117,75 @@ func (c *Client) GetBucketACL(ctx context.Context, bucket, region string) (strin
 	return CollapseGrants(out), nil
 }
 
+// GetBucketPolicy returns bucket's policy document, or nil when the bucket
+// has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket, region string) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := c.credential.Validate(); err != nil {
+		return nil, err
+	}
+	bucket = strings.TrimSpace(bucket)
+	region = strings.TrimSpace(region)
+	if bucket == "" || region == "" {
+		return nil, fmt.Errorf("huawei obs client: empty bucket or region")
+	}
+
+	rawURL := endpoint.For("obs", region, c.credential.Intl)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("huawei obs client: invalid endpoint %q: %w", rawURL, err)
+	}
+	u.Path = "/" + bucket
+	query := url.Values{}
+	query.Set("policy", "")
+	u.RawQuery = query.Encode()
+
+	signed, err := Sign(&SignRequest{
+		Method:    http.MethodGet,
+		Path:      "/" + bucket,
+		Query:     query,
+		Scheme:    authSchemeV2,
+		AccessKey: c.credential.AK,
+		SecretKey: c.credential.SK,
+		Timestamp: c.now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpResp, err := c.retryPolicy.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Host = u.Host
+		req.Header = signed.Clone()
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if httpResp == nil {
+		return nil, fmt.Errorf("huawei obs client: empty response")
+	}
+	defer httpclient.CloseResponse(httpResp)
+
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read huawei obs response: %w", err)
+	}
+	if err := decodeError(httpResp.StatusCode, httpResp.Header, body); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.Code == "NoSuchBucketPolicy" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
 // PutBucketACL sets a canned ACL on bucket via the `x-obs-acl` header.
 func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) error {
 	if ctx == nil {
@@ -184,7 +255,8 @@ func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) e
 	return decodeError(httpResp.StatusCode, httpResp.Header, body)
 }
 
-// AuditBucketACL enumerates buckets in scope and returns the canned ACL state.
+// AuditBucketACL enumerates buckets in scope and returns the canned ACL state
+// and effective exposure, which also weighs the bucket policy.
 func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.BucketACLEntry, error) {
 	storages, err := d.GetBuckets(ctx)
 	if err != nil {
@@ -205,10 +277,15 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 		if err != nil {
 			return out, fmt.Errorf("get acl for %s: %w", s.BucketName, err)
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     acl,
-		})
+		}
+		var verdict exposure.Verdict
+		verdict.Add("acl", exposure.ACL(acl))
+		verdict.AddPolicy(client.GetBucketPolicy(ctx, s.BucketName, region))
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
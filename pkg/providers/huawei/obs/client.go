@@ -132,7 +132,7 @@ func (c *Client) ListBuckets(ctx context.Context, endpointRegion string) (*ListB
 	return &out, nil
 }
 
-func (c *Client) ListObjects(ctx context.Context, bucket, region, marker string, maxKeys int) (ListObjectsResponse, error) {
+func (c *Client) ListObjects(ctx context.Context, bucket, region, prefix, marker string, maxKeys int) (ListObjectsResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -163,6 +163,9 @@ func (c *Client) ListObjects(ctx context.Context, bucket, region, marker string,
 	u.Path = "/" + bucket
 	query := url.Values{}
 	query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if strings.TrimSpace(marker) != "" {
 		query.Set("marker", marker)
 	}
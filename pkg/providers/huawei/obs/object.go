@@ -10,15 +10,25 @@ import (
 	"github.com/404tk/cloudtoolkit/utils/processbar"
 )
 
+// ListObjects returns every object of each bucket.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's markers and hands the objects matching
+// q to visit. A failing bucket is reported once the others are listed.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	results := make([]schema.BucketResult, 0, len(buckets))
 	var errs []string
 	for bucket, region := range buckets {
 		marker := ""
-		objects := make([]schema.BucketObject, 0)
+		walk := schema.NewObjectWalk(q, visit)
 		failed := false
+	pages:
 		for {
-			resp, err := d.listObjectsPage(ctx, bucket, region, marker, 100)
+			resp, err := d.listObjectsPage(ctx, bucket, region, q.Prefix, marker, 1000)
 			if err != nil {
 				errs = append(errs, fmt.Sprintf("%s: %v", bucket, err))
 				failed = true
@@ -26,13 +36,19 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 			}
 
 			for _, obj := range resp.Objects {
-				objects = append(objects, schema.BucketObject{
+				more, err := walk.Add(schema.BucketObject{
 					BucketName:   bucket,
 					Key:          obj.Key,
 					Size:         obj.Size,
 					LastModified: obj.LastModified,
 					StorageClass: obj.StorageClass,
 				})
+				if err != nil {
+					return append(results, walk.Result(bucket)), err
+				}
+				if !more {
+					break pages
+				}
 			}
 
 			if !resp.IsTruncated {
@@ -42,25 +58,12 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 
 			select {
 			case <-ctx.Done():
-				results = append(results, schema.BucketResult{
-					Action:      "list",
-					BucketName:  bucket,
-					ObjectCount: int64(len(objects)),
-					Objects:     objects,
-					Message:     fmt.Sprintf("%d objects found", len(objects)),
-				})
-				return results, nil
+				return append(results, walk.Result(bucket)), ctx.Err()
 			default:
 			}
 		}
-		if !failed || len(objects) > 0 {
-			results = append(results, schema.BucketResult{
-				Action:      "list",
-				BucketName:  bucket,
-				ObjectCount: int64(len(objects)),
-				Objects:     objects,
-				Message:     fmt.Sprintf("%d objects found", len(objects)),
-			})
+		if !failed || walk.Matched > 0 {
+			results = append(results, walk.Result(bucket))
 		}
 	}
 	if len(errs) > 0 {
@@ -94,19 +97,19 @@ func (d *Driver) TotalObjects(ctx context.Context, buckets map[string]string) ([
 	return results, nil
 }
 
-func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, marker string, maxKeys int) (ListObjectsResponse, error) {
+func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, prefix, marker string, maxKeys int) (ListObjectsResponse, error) {
 	client := d.client()
 	if client == nil {
 		return ListObjectsResponse{}, fmt.Errorf("huawei obs: nil client")
 	}
-	return client.ListObjects(ctx, bucket, d.resolveBucketRegion(region), marker, maxKeys)
+	return client.ListObjects(ctx, bucket, d.resolveBucketRegion(region), prefix, marker, maxKeys)
 }
 
 func (d *Driver) countBucketObjects(ctx context.Context, bucket, region string, tracker *processbar.CountTracker) (int, error) {
 	count := 0
 	marker := ""
 	for {
-		resp, err := d.listObjectsPage(ctx, bucket, region, marker, 1000)
+		resp, err := d.listObjectsPage(ctx, bucket, region, "", marker, 1000)
 		if err != nil {
 			return 0, err
 		}
@@ -0,0 +1,93 @@
+package obs
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const objectAccessBuckets = `<ListAllMyBucketsResult><Buckets><Bucket><Name>ctk-demo</Name><Location>cn-south-1</Location></Bucket></Buckets></ListAllMyBucketsResult>`
+
+func TestAccessObjectRoundTripsCanary(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]string{}
+	driver := &Driver{Client: newACLClient(func(req *http.Request) (*http.Response, error) {
+		respond := func(status int, body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"X-Obs-Request-Id": []string{"req-" + strings.ToLower(req.Method)}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}
+		if req.URL.Path == "/" {
+			return respond(http.StatusOK, objectAccessBuckets)
+		}
+		if req.URL.Host != "obs.cn-south-1.myhuaweicloud.com" || req.URL.Path != "/ctk-demo/ctk-canary/20260422T120000Z-0a1b2c3d.txt" {
+			t.Fatalf("unexpected object url: %s", req.URL)
+		}
+		presigned := req.URL.Query().Get("Signature") != ""
+		if signed := strings.Contains(req.Header.Get("Authorization"), " AKIDEXAMPLE:"); signed == presigned {
+			t.Fatalf("%s: authorization=%q presigned=%v", req.Method, req.Header.Get("Authorization"), presigned)
+		}
+		switch req.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(req.Body)
+			stored[req.URL.Path] = string(data)
+		case http.MethodGet:
+			return respond(http.StatusOK, stored[req.URL.Path])
+		case http.MethodDelete:
+			delete(stored, req.URL.Path)
+			return respond(http.StatusNoContent, "")
+		}
+		return respond(http.StatusOK, "")
+	})}
+
+	ctx := context.Background()
+	const key = "ctk-canary/20260422T120000Z-0a1b2c3d.txt"
+	put, err := driver.AccessObject(ctx, "put", "ctk-demo", key, []byte("canary"))
+	if err != nil || put.RequestID != "req-put" {
+		t.Fatalf("put = %+v, %v", put, err)
+	}
+	get, err := driver.AccessObject(ctx, "get", "ctk-demo", key, nil)
+	if err != nil || string(get.Body) != "canary" {
+		t.Fatalf("get = %+v, %v", get, err)
+	}
+	presign, err := driver.AccessObject(ctx, "presign", "ctk-demo", key, nil)
+	if err != nil || string(presign.Body) != "canary" || !strings.Contains(presign.URL, "Expires=") {
+		t.Fatalf("presign = %+v, %v", presign, err)
+	}
+	if _, err := driver.AccessObject(ctx, "delete", "ctk-demo", key, nil); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Fatalf("canary left behind: %v", stored)
+	}
+}
+
+func TestAccessObjectKeepsRequestIDOfDeniedCall(t *testing.T) {
+	t.Parallel()
+
+	driver := &Driver{Client: newACLClient(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path == "/" {
+			return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(objectAccessBuckets)), Request: req}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{"X-Obs-Request-Id": []string{"req-denied"}},
+			Body: io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>AccessDenied</Code><Message>denied</Message><RequestId>req-denied</RequestId></Error>`)),
+			Request: req,
+		}, nil
+	})}
+	got, err := driver.AccessObject(context.Background(), "get", "ctk-demo", "ctk-canary/x.txt", nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got.RequestID != "req-denied" || got.StatusCode != http.StatusForbidden {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
@@ -51,7 +51,7 @@ func TestClientListObjectsUsesBucketScopedEndpoint(t *testing.T) {
 		WithClock(func() time.Time { return ts }),
 	)
 
-	resp, err := client.ListObjects(context.Background(), "examplebucket", "cn-south-1", "", 100)
+	resp, err := client.ListObjects(context.Background(), "examplebucket", "cn-south-1", "", "", 100)
 	if err != nil {
 		t.Fatalf("ListObjects() error = %v", err)
 	}
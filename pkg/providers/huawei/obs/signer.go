@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -128,6 +129,33 @@ func Sign(req *SignRequest) (http.Header, error) {
 	return headers, nil
 }
 
+// Presign returns query with OBS query-string authentication added, valid
+// until expires: AccessKeyId, Expires and Signature. The expiry takes the
+// place of the Date header in the string to sign.
+func Presign(method, path string, query url.Values, accessKey, secretKey string, expires time.Time) (url.Values, error) {
+	if strings.TrimSpace(accessKey) == "" {
+		return nil, fmt.Errorf("huawei obs signer: empty access key")
+	}
+	if strings.TrimSpace(secretKey) == "" {
+		return nil, fmt.Errorf("huawei obs signer: empty secret key")
+	}
+	out := url.Values{}
+	for key, values := range query {
+		out[key] = append([]string(nil), values...)
+	}
+	for _, key := range []string{"AccessKeyId", "Expires", "Signature"} {
+		out.Del(key)
+	}
+	expiresAt := strconv.FormatInt(expires.Unix(), 10)
+	headers := http.Header{}
+	headers.Set(dateHeader, expiresAt)
+	stringToSign := buildStringToSign(strings.ToUpper(method), path, out, headers)
+	out.Set("AccessKeyId", accessKey)
+	out.Set("Expires", expiresAt)
+	out.Set("Signature", signString(stringToSign, secretKey))
+	return out, nil
+}
+
 func buildStringToSign(method, path string, query url.Values, headers http.Header) string {
 	normalized := normalizeHeaders(headers)
 	dateValue := normalized["date"]
@@ -18,7 +18,7 @@ import (
 // (POST). engine routing is left to RDS itself — the MySQL path also serves
 // PostgreSQL with the same payload shape.
 func (d *Driver) CreateAccount(ctx context.Context, region, instanceID string) (schema.DatabaseActionResult, error) {
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -60,7 +60,7 @@ func (d *Driver) CreateAccount(ctx context.Context, region, instanceID string) (
 
 // DeleteAccount revokes the account named by the `rds-account-check` config.
 func (d *Driver) DeleteAccount(ctx context.Context, region, instanceID string) (schema.DatabaseActionResult, error) {
-	user, _, err := parseRDSAccount()
+	user, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -99,8 +99,8 @@ func (d *Driver) requestRegion() string {
 	return ""
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -6,16 +6,24 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei"
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/obs"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 // ClientConfig builds the demo replay configuration injected into
 // huawei.NewWithConfig when replay is active for the huawei provider.
 func ClientConfig() huawei.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every huawei client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) huawei.ClientConfig {
+	httpClient = telemetry.WrapClient("huawei", httpClient)
 	return huawei.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		OBSOptions:          []obs.Option{obs.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions: []api.Option{api.WithHTTPClient(httpClient)},
+		OBSOptions: []obs.Option{obs.WithHTTPClient(httpClient)},
 	}
 }
@@ -47,33 +47,52 @@ func findProjectByID(id string) (projectFixture, bool) {
 }
 
 type iamUserFixture struct {
-	ID       string
-	Name     string
-	Enabled  bool
-	DomainID string
+	ID            string
+	Name          string
+	Enabled       bool
+	DomainID      string
+	AccessMode    string
+	CreateTime    string
+	LastLoginTime string
+	LoginProtect  bool
 }
 
 var demoBaseIAMUsers = []iamUserFixture{
 	{
-		ID:       demoUserID,
-		Name:     demoUserName,
-		Enabled:  true,
-		DomainID: demoDomainID,
+		ID:            demoUserID,
+		Name:          demoUserName,
+		Enabled:       true,
+		DomainID:      demoDomainID,
+		AccessMode:    "default",
+		CreateTime:    "2025-11-03 08:00:00.0",
+		LastLoginTime: "2026-04-22 10:05:00.0",
 	},
 	{
-		ID:       "06f1d2dca680f0a02fa4c01acc0e0100",
-		Name:     "ctk-demo-readonly",
-		Enabled:  true,
-		DomainID: demoDomainID,
+		ID:            "06f1d2dca680f0a02fa4c01acc0e0100",
+		Name:          "ctk-demo-readonly",
+		Enabled:       true,
+		DomainID:      demoDomainID,
+		AccessMode:    "console",
+		CreateTime:    "2026-01-12 09:30:00.0",
+		LastLoginTime: "2026-09-30 14:20:00.0",
+		LoginProtect:  true,
 	},
 	{
-		ID:       "06f1d2dca680f0a02fa4c01acc0e0101",
-		Name:     "ctk-demo-bot",
-		Enabled:  false,
-		DomainID: demoDomainID,
+		ID:         "06f1d2dca680f0a02fa4c01acc0e0101",
+		Name:       "ctk-demo-bot",
+		Enabled:    false,
+		DomainID:   demoDomainID,
+		AccessMode: "programmatic",
+		CreateTime: "2025-06-01 02:00:00.0",
 	},
 }
 
+// demoIAMKeyLastUsed is the last_use_time reported per permanent access
+// key; keys missing here have never been used.
+var demoIAMKeyLastUsed = map[string]string{
+	demoCredentials.AccessKey: "2026-04-22T09:58:11.000000Z",
+}
+
 type iamGroupFixture struct {
 	ID   string
 	Name string
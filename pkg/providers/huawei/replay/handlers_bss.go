@@ -14,8 +14,8 @@ func (t *transport) handleBSS(req *http.Request, _ []byte) (*http.Response, erro
 	}
 	resp := api.ShowCustomerAccountBalancesResponse{
 		AccountBalances: []api.AccountBalance{
-			{AccountType: 1, Amount: "1024.88"},
-			{AccountType: 2, Amount: "0.00"},
+			{AccountType: 1, Amount: "1024.88", Currency: "CNY"},
+			{AccountType: 2, Amount: "0.00", Currency: "CNY"},
 		},
 	}
 	return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
@@ -53,8 +53,10 @@ func (t *transport) handleECS(req *http.Request, _ string, _ []byte) (*http.Resp
 	resp := api.ListECSServersDetailsResponse{Count: int32(total)}
 	for _, host := range page {
 		entry := api.ECSServerDetail{
-			Status: host.Status,
-			Name:   host.Name,
+			ID:             host.ID,
+			Status:         host.Status,
+			Name:           host.Name,
+			SecurityGroups: securityGroupsForServer(host.ID),
 			Addresses: map[string][]api.ECSServerAddress{
 				"vpc-replay": {
 					{Addr: host.PrivateIP, OSEXTIPStype: "fixed"},
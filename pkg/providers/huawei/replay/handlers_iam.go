@@ -22,6 +22,10 @@ func (t *transport) handleIAM(req *http.Request, _ string, body []byte) (*http.R
 		return t.handleCreatePermanentAccessKey(req, body)
 	case method == http.MethodDelete && strings.HasPrefix(path, "/v3.0/OS-CREDENTIAL/credentials/"):
 		return t.handleDeletePermanentAccessKey(req, path)
+	case method == http.MethodGet && strings.HasPrefix(path, "/v3.0/OS-USER/users/") && strings.HasSuffix(path, "/login-protect"):
+		return t.handleShowUserLoginProtect(req, path)
+	case method == http.MethodGet && strings.HasPrefix(path, "/v3.0/OS-USER/users/"):
+		return t.handleShowUserDetail(req, path)
 	case method == http.MethodGet && strings.HasPrefix(path, "/v3/users/") && strings.HasSuffix(path, "/groups"):
 		return t.handleListGroupsForUser(req, path)
 	case method == http.MethodGet && strings.HasPrefix(path, "/v3/users/") && !strings.Contains(strings.TrimPrefix(path, "/v3/users/"), "/"):
@@ -111,12 +115,50 @@ func (t *transport) handleDeletePermanentAccessKey(req *http.Request, path strin
 
 func (t *transport) handleShowPermanentAccessKey(req *http.Request, path string) (*http.Response, error) {
 	ak := strings.TrimSpace(strings.TrimPrefix(path, "/v3.0/OS-CREDENTIAL/credentials/"))
-	if ak != demoCredentials.AccessKey {
+	resp := api.ShowPermanentAccessKeyResponse{}
+	if key, ok := t.iam.findAccessKey(ak); ok {
+		resp.Credential.UserID = key.UserID
+	} else if ak == demoCredentials.AccessKey {
+		resp.Credential.UserID = demoUserID
+	} else {
 		return apiErrorResponse(req, http.StatusNotFound, "IAM.0007",
 			fmt.Sprintf("access key %s not found", ak)), nil
 	}
-	resp := api.ShowPermanentAccessKeyResponse{}
-	resp.Credential.UserID = demoUserID
+	resp.Credential.LastUseTime = demoIAMKeyLastUsed[ak]
+	return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+}
+
+func (t *transport) handleShowUserDetail(req *http.Request, path string) (*http.Response, error) {
+	id := strings.TrimSpace(strings.TrimPrefix(path, "/v3.0/OS-USER/users/"))
+	user, ok := t.iam.findByID(id)
+	if !ok {
+		return apiErrorResponse(req, http.StatusNotFound, "IAM.0009",
+			fmt.Sprintf("user %s not found", id)), nil
+	}
+	resp := api.ShowUserDetailResponse{User: api.IAMUserDetail{
+		ID:            user.ID,
+		Name:          user.Name,
+		Enabled:       user.Enabled,
+		AccessMode:    user.AccessMode,
+		CreateTime:    user.CreateTime,
+		LastLoginTime: user.LastLoginTime,
+	}}
+	return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+}
+
+func (t *transport) handleShowUserLoginProtect(req *http.Request, path string) (*http.Response, error) {
+	id := strings.TrimSuffix(strings.TrimPrefix(path, "/v3.0/OS-USER/users/"), "/login-protect")
+	user, ok := t.iam.findByID(id)
+	if !ok {
+		return apiErrorResponse(req, http.StatusNotFound, "IAM.0009",
+			fmt.Sprintf("user %s not found", id)), nil
+	}
+	resp := api.ShowUserLoginProtectResponse{}
+	resp.LoginProtect.UserID = user.ID
+	resp.LoginProtect.Enabled = user.LoginProtect
+	if user.LoginProtect {
+		resp.LoginProtect.VerificationMethod = "vmfa"
+	}
 	return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
 }
 
@@ -14,7 +14,11 @@ import (
 )
 
 func (t *transport) handleOBS(req *http.Request, region string, body []byte) (*http.Response, error) {
-	switch verifyOBSAuth(req, body) {
+	verify := verifyOBSAuth
+	if req.URL.Query().Has("Signature") {
+		verify = verifyOBSPresigned
+	}
+	switch verify(req, body) {
 	case demoreplay.AuthInvalidAccessKey:
 		return obsErrorResponse(req, http.StatusForbidden, "InvalidAccessKeyId",
 			"The Access Key Id you provided does not exist in our records."), nil
@@ -32,10 +36,17 @@ func (t *transport) handleOBS(req *http.Request, region string, body []byte) (*h
 		}
 		return handleListBuckets(req)
 	}
-	bucket, _, _ := strings.Cut(path, "/")
+	bucket, key, _ := strings.Cut(path, "/")
+	if key != "" {
+		return t.handleOBSObject(req, bucket, key, body)
+	}
 	if query.Has("acl") {
 		return t.handleOBSBucketACL(req, bucket, region)
 	}
+	if query.Has("policy") {
+		return obsErrorResponse(req, http.StatusNotFound, "NoSuchBucketPolicy",
+			"The bucket policy does not exist."), nil
+	}
 	if req.Method != http.MethodGet {
 		return obsErrorResponse(req, http.StatusMethodNotAllowed, "MethodNotAllowed",
 			"The specified method is not allowed against this resource."), nil
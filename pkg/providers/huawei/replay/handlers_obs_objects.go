@@ -0,0 +1,64 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/obs"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+// handleOBSObject models the object data plane object-access-check drives:
+// objects written during the session can be read back, fetched through a
+// pre-signed URL and deleted. Every response carries an x-obs-request-id.
+func (t *transport) handleOBSObject(req *http.Request, bucket, key string, body []byte) (*http.Response, error) {
+	id := bucket + "/" + key
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.objectSeq++
+	requestID := fmt.Sprintf("0000018A7C3E%020X", t.objectSeq)
+
+	var resp *http.Response
+	switch req.Method {
+	case http.MethodPut:
+		t.objects[id] = body
+		resp = demoreplay.Response(req, http.StatusOK, "", nil)
+	case http.MethodGet:
+		data, ok := t.objects[id]
+		if !ok {
+			resp = obsErrorResponse(req, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			break
+		}
+		resp = demoreplay.Response(req, http.StatusOK, "text/plain", data)
+	case http.MethodDelete:
+		delete(t.objects, id)
+		resp = demoreplay.Response(req, http.StatusNoContent, "", nil)
+	default:
+		resp = obsErrorResponse(req, http.StatusMethodNotAllowed, "MethodNotAllowed", "The specified method is not allowed against this resource.")
+	}
+	resp.Header.Set("X-Obs-Request-Id", requestID)
+	return resp, nil
+}
+
+// verifyOBSPresigned checks OBS query-string authentication the way OBS
+// checks a pre-signed URL, expiry included.
+func verifyOBSPresigned(req *http.Request, _ []byte) demoreplay.AuthFailureKind {
+	query := req.URL.Query()
+	if query.Get("AccessKeyId") != demoCredentials.AccessKey {
+		return demoreplay.AuthInvalidAccessKey
+	}
+	expires, err := strconv.ParseInt(query.Get("Expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return demoreplay.AuthInvalidSignature
+	}
+	expected, err := obs.Presign(req.Method, req.URL.Path, query, demoCredentials.AccessKey, demoCredentials.SecretKey, time.Unix(expires, 0))
+	if err != nil {
+		return demoreplay.AuthInvalidSignature
+	}
+	if !demoreplay.SubtleEqual(expected.Get("Signature"), query.Get("Signature")) {
+		return demoreplay.AuthInvalidSignature
+	}
+	return demoreplay.AuthOK
+}
@@ -0,0 +1,118 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+)
+
+type securityGroupFixture struct {
+	api.VPCSecurityGroup
+	Region  string
+	Members []string
+}
+
+func intPtr(v int) *int { return &v }
+
+// demoSecurityGroups exposes SSH on the cn-north-4 bastion and keeps the app
+// tier reachable only from the bastion group.
+var demoSecurityGroups = []securityGroupFixture{
+	{
+		VPCSecurityGroup: api.VPCSecurityGroup{
+			ID:          "5a1f0001-0000-4000-8000-000000000001",
+			Name:        "ctk-demo-bastion",
+			Description: "bastion ssh",
+			VpcID:       "vpc-demo-north4",
+			Rules: []api.VPCSecurityGroupRule{
+				{ID: "r-0001", Direction: "ingress", Ethertype: "IPv4", Protocol: "tcp", PortRangeMin: intPtr(22), PortRangeMax: intPtr(22), RemoteIPPrefix: "0.0.0.0/0", Description: "ssh"},
+				{ID: "r-0002", Direction: "egress", Ethertype: "IPv4"},
+			},
+		},
+		Region:  "cn-north-4",
+		Members: []string{"0f001"},
+	},
+	{
+		VPCSecurityGroup: api.VPCSecurityGroup{
+			ID:          "5a1f0001-0000-4000-8000-000000000002",
+			Name:        "ctk-demo-app",
+			Description: "app tier",
+			VpcID:       "vpc-demo-north4",
+			Rules: []api.VPCSecurityGroupRule{
+				{ID: "r-0003", Direction: "ingress", Ethertype: "IPv4", Protocol: "tcp", PortRangeMin: intPtr(8080), PortRangeMax: intPtr(8080), RemoteGroupID: "5a1f0001-0000-4000-8000-000000000001"},
+				{ID: "r-0004", Direction: "ingress", Ethertype: "IPv4", Protocol: "tcp", PortRangeMin: intPtr(443), PortRangeMax: intPtr(443), RemoteIPPrefix: "0.0.0.0/0"},
+			},
+		},
+		Region:  "cn-north-4",
+		Members: []string{"0f002"},
+	},
+	{
+		VPCSecurityGroup: api.VPCSecurityGroup{
+			ID:    "5a1f0001-0000-4000-8000-000000000003",
+			Name:  "ctk-demo-edge",
+			VpcID: "vpc-demo-east3",
+			Rules: []api.VPCSecurityGroupRule{
+				{ID: "r-0005", Direction: "ingress", Ethertype: "IPv4", Protocol: "tcp", PortRangeMin: intPtr(3389), PortRangeMax: intPtr(3389), RemoteIPPrefix: "0.0.0.0/0", Description: "rdp"},
+			},
+		},
+		Region:  "cn-east-3",
+		Members: []string{"0f101"},
+	},
+}
+
+func securityGroupsForServer(serverID string) []api.ECSServerSecurityGroup {
+	var out []api.ECSServerSecurityGroup
+	for _, group := range demoSecurityGroups {
+		for _, member := range group.Members {
+			if member == serverID {
+				out = append(out, api.ECSServerSecurityGroup{ID: group.ID, Name: group.Name})
+			}
+		}
+	}
+	return out
+}
+
+// handleVPC serves the v1 security group listing used by cloudlist.
+func (t *transport) handleVPC(req *http.Request, _ string) (*http.Response, error) {
+	path := req.URL.Path
+	method := strings.ToUpper(req.Method)
+	if method != http.MethodGet || !strings.HasPrefix(path, "/v1/") || !strings.HasSuffix(path, "/security-groups") {
+		return apiErrorResponse(req, http.StatusNotFound, "VPC.0101",
+			fmt.Sprintf("unsupported vpc path: %s %s", method, path)), nil
+	}
+	projectID := strings.TrimSuffix(strings.TrimPrefix(path, "/v1/"), "/security-groups")
+	project, ok := findProjectByID(projectID)
+	if !ok {
+		return apiErrorResponse(req, http.StatusForbidden, "VPC.0101",
+			fmt.Sprintf("project %s not visible to current user", projectID)), nil
+	}
+
+	query := req.URL.Query()
+	limit, _ := strconv.Atoi(strings.TrimSpace(query.Get("limit")))
+	if limit <= 0 {
+		limit = 100
+	}
+	marker := strings.TrimSpace(query.Get("marker"))
+
+	var groups []api.VPCSecurityGroup
+	for _, group := range demoSecurityGroups {
+		if group.Region == project.Name {
+			groups = append(groups, group.VPCSecurityGroup)
+		}
+	}
+	if marker != "" {
+		for i, group := range groups {
+			if group.ID == marker {
+				groups = groups[i+1:]
+				break
+			}
+		}
+	}
+	if len(groups) > limit {
+		groups = groups[:limit]
+	}
+	return demoreplay.JSONResponse(req, http.StatusOK, api.ListSecurityGroupsResponse{SecurityGroups: groups}), nil
+}
@@ -54,6 +54,19 @@ func (s *iamMutationState) snapshotAccessKeys(userID string) []huaweiAccessKeyFi
 	return keys
 }
 
+func (s *iamMutationState) findAccessKey(accessKey string) (huaweiAccessKeyFixture, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, keys := range s.accessKeys {
+		for _, k := range keys {
+			if k.Access == accessKey {
+				return k, true
+			}
+		}
+	}
+	return huaweiAccessKeyFixture{}, false
+}
+
 func (s *iamMutationState) mintAccessKey(userID string) huaweiAccessKeyFixture {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -134,10 +147,11 @@ func (s *iamMutationState) ensureUser(name string) iamUserFixture {
 	}
 	s.sequence++
 	user := iamUserFixture{
-		ID:       newSyntheticUserID(s.sequence),
-		Name:     name,
-		Enabled:  true,
-		DomainID: demoDomainID,
+		ID:         newSyntheticUserID(s.sequence),
+		Name:       name,
+		Enabled:    true,
+		DomainID:   demoDomainID,
+		AccessMode: "default",
 	}
 	s.created[name] = user
 	return user
@@ -18,6 +18,8 @@ type transport struct {
 	mu          sync.Mutex
 	bucketACL   map[string]string
 	rdsAccounts map[string][]string
+	objects     map[string][]byte
+	objectSeq   int
 }
 
 func newTransport() *transport {
@@ -25,6 +27,7 @@ func newTransport() *transport {
 		iam:         newIAMMutationState(),
 		bucketACL:   seedHuaweiBucketACL(),
 		rdsAccounts: make(map[string][]string),
+		objects:     make(map[string][]byte),
 	}
 }
 
@@ -87,6 +90,8 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return t.handleIAM(req, region, body)
 	case "ecs":
 		return t.handleECS(req, region, body)
+	case "vpc":
+		return t.handleVPC(req, region)
 	case "rds":
 		return t.handleRDS(req, region, body)
 	case "bss":
@@ -118,6 +123,8 @@ func classifyHost(host string) (string, string) {
 		return "iam", trimSuffix(strings.TrimPrefix(host, "iam."), ".myhuaweicloud.com")
 	case strings.HasPrefix(host, "ecs."):
 		return "ecs", trimSuffix(strings.TrimPrefix(host, "ecs."), ".myhuaweicloud.com")
+	case strings.HasPrefix(host, "vpc."):
+		return "vpc", trimSuffix(strings.TrimPrefix(host, "vpc."), ".myhuaweicloud.com")
 	case strings.HasPrefix(host, "rds."):
 		return "rds", trimSuffix(strings.TrimPrefix(host, "rds."), ".myhuaweicloud.com")
 	case strings.HasPrefix(host, "cts."):
@@ -0,0 +1,130 @@
+// Package exposure combines the access layers of an object-storage bucket —
+// its ACL, bucket policy or IAM bindings, and account-wide blocks — into the
+// effective public-exposure verdict the bucket-acl-check audit reports.
+package exposure
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// Grant is what one access layer opens a bucket to.
+type Grant struct {
+	// Read and Write are granted to anonymous callers.
+	Read  bool
+	Write bool
+	// Authenticated is granted to any signed-in principal of the cloud,
+	// whatever its account.
+	Authenticated bool
+	// Conditional is anonymous access narrowed by policy conditions such as
+	// a source network or referer.
+	Conditional bool
+	Reasons     []string
+}
+
+// Open reports whether the layer grants anything beyond the owner.
+func (g Grant) Open() bool {
+	return g.Read || g.Write || g.Authenticated || g.Conditional
+}
+
+func (g *Grant) merge(other Grant) {
+	g.Read = g.Read || other.Read
+	g.Write = g.Write || other.Write
+	g.Authenticated = g.Authenticated || other.Authenticated
+	g.Conditional = g.Conditional || other.Conditional
+	g.Reasons = append(g.Reasons, other.Reasons...)
+}
+
+// ACL maps a canned ACL level to its grant. The public levels of every
+// supported cloud share the S3 names; OBS adds "-delivered" variants that
+// also cover objects.
+func ACL(level string) Grant {
+	normalized := strings.ToLower(strings.TrimSpace(level))
+	normalized = strings.TrimSuffix(normalized, "-delivered")
+	reason := []string{"acl " + strings.TrimSpace(level)}
+	switch normalized {
+	case "public-read-write":
+		return Grant{Read: true, Write: true, Reasons: reason}
+	case "public-read":
+		return Grant{Read: true, Reasons: reason}
+	case "authenticated-read":
+		return Grant{Authenticated: true, Reasons: reason}
+	}
+	return Grant{}
+}
+
+// PolicyLayer names the bucket policy in verdict reasons.
+const PolicyLayer = "bucket policy"
+
+// Verdict accumulates the layers of one bucket.
+type Verdict struct {
+	grant   Grant
+	layers  []string
+	notes   []string
+	unknown []string
+}
+
+// Add records layer and what it grants.
+func (v *Verdict) Add(layer string, g Grant) {
+	v.layers = append(v.layers, layer)
+	v.grant.merge(g)
+}
+
+// AddPolicy records the bucket policy layer from the document and error a
+// policy fetch returned; a nil document means the bucket has no policy.
+func (v *Verdict) AddPolicy(doc []byte, err error) {
+	if err == nil {
+		var g Grant
+		if g, err = Policy(doc); err == nil {
+			v.Add(PolicyLayer, g)
+			return
+		}
+	}
+	v.Unknown(PolicyLayer, err)
+}
+
+// Block records layer as checked but overridden by blocker, such as a public
+// ACL that an account-wide block makes the cloud ignore.
+func (v *Verdict) Block(layer string, g Grant, blocker string) {
+	v.layers = append(v.layers, layer)
+	if g.Open() {
+		v.notes = append(v.notes, fmt.Sprintf("%s ignored: %s", strings.Join(g.Reasons, ", "), blocker))
+	}
+}
+
+// Unknown records a layer that could not be read.
+func (v *Verdict) Unknown(layer string, err error) {
+	v.unknown = append(v.unknown, fmt.Sprintf("%s unreadable: %v", layer, err))
+}
+
+// Exposure returns the effective verdict and the reason behind it. A layer
+// that could not be read turns an otherwise private bucket into unknown.
+func (v Verdict) Exposure() (string, string) {
+	reasons := append([]string(nil), v.grant.Reasons...)
+	reasons = append(reasons, v.notes...)
+	reasons = append(reasons, v.unknown...)
+	exposure := schema.ExposurePrivate
+	switch {
+	case v.grant.Write:
+		exposure = schema.ExposurePublicReadWrite
+	case v.grant.Read:
+		exposure = schema.ExposurePublicRead
+	case v.grant.Authenticated:
+		exposure = schema.ExposureAuthenticated
+	case v.grant.Conditional:
+		exposure = schema.ExposureConditional
+	case len(v.unknown) > 0:
+		exposure = schema.ExposureUnknown
+	}
+	if exposure == schema.ExposurePrivate && len(reasons) == 0 && len(v.layers) > 0 {
+		reasons = append(reasons, "no public grant in "+strings.Join(v.layers, ", "))
+	}
+	return exposure, strings.Join(reasons, "; ")
+}
+
+// Apply stores the verdict on entry.
+func (v Verdict) Apply(entry *schema.BucketACLEntry) {
+	entry.Exposure, entry.Reason = v.Exposure()
+}
@@ -0,0 +1,113 @@
+package exposure
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestPolicyClassifiesStatements(t *testing.T) {
+	cases := []struct {
+		name     string
+		doc      string
+		want     string
+		inReason string
+	}{
+		{
+			name:     "anonymous read",
+			doc:      `{"Statement":[{"Sid":"Public","Effect":"Allow","Principal":"*","Action":"s3:GetObject","Resource":"arn:aws:s3:::b/*"}]}`,
+			want:     schema.ExposurePublicRead,
+			inReason: "policy Public allows anonymous read",
+		},
+		{
+			name: "anonymous write through NotAction",
+			doc:  `{"Statement":{"Effect":"Allow","Principal":{"AWS":["*"]},"NotAction":"s3:DeleteBucket"}}`,
+			want: schema.ExposurePublicReadWrite,
+		},
+		{
+			name:     "source ip narrows",
+			doc:      `{"Statement":[{"Effect":"Allow","Principal":"*","Action":["oss:GetObject"],"Condition":{"IpAddress":{"acs:SourceIp":["203.0.113.0/24"]}}}]}`,
+			want:     schema.ExposureConditional,
+			inReason: "when acs:SourceIp",
+		},
+		{
+			name: "all networks and secure transport do not narrow",
+			doc:  `{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:GetObject","Condition":{"IpAddress":{"aws:SourceIp":"0.0.0.0/0"},"Bool":{"aws:SecureTransport":"true"}}}]}`,
+			want: schema.ExposurePublicRead,
+		},
+		{
+			name: "cos anyone in lower case",
+			doc:  `{"statement":[{"effect":"allow","principal":{"qcs":["qcs::cam::anyone:anyone"]},"action":["name/cos:GetObject"]}]}`,
+			want: schema.ExposurePublicRead,
+		},
+		{
+			name:     "any account",
+			doc:      `{"Statement":[{"Effect":"Allow","Principal":{"ID":["domain/*:user/*"]},"Action":["ListBucket"]}]}`,
+			want:     schema.ExposureAuthenticated,
+			inReason: "any-account",
+		},
+		{
+			name: "deny and named principal",
+			doc:  `{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*"},{"Effect":"Allow","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"s3:*"}]}`,
+			want: schema.ExposurePrivate,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var v Verdict
+			v.AddPolicy([]byte(tc.doc), nil)
+			got, reason := v.Exposure()
+			if got != tc.want {
+				t.Fatalf("exposure = %q (%s), want %q", got, reason, tc.want)
+			}
+			if !strings.Contains(reason, tc.inReason) {
+				t.Fatalf("reason %q does not contain %q", reason, tc.inReason)
+			}
+		})
+	}
+}
+
+func TestVerdictCombinesLayers(t *testing.T) {
+	var v Verdict
+	v.Add("acl", ACL("public-read-delivered"))
+	v.AddPolicy(nil, nil)
+	if got, reason := v.Exposure(); got != schema.ExposurePublicRead || reason != "acl public-read-delivered" {
+		t.Fatalf("got %q %q", got, reason)
+	}
+
+	var blocked Verdict
+	blocked.Block("acl", ACL("public-read-write"), "account IgnorePublicAcls")
+	blocked.AddPolicy(nil, nil)
+	got, reason := blocked.Exposure()
+	if got != schema.ExposurePrivate || reason != "acl public-read-write ignored: account IgnorePublicAcls" {
+		t.Fatalf("got %q %q", got, reason)
+	}
+
+	var quiet Verdict
+	quiet.Add("acl", ACL("private"))
+	quiet.AddPolicy(nil, nil)
+	if got, reason := quiet.Exposure(); got != schema.ExposurePrivate || reason != "no public grant in acl, bucket policy" {
+		t.Fatalf("got %q %q", got, reason)
+	}
+}
+
+func TestVerdictUnreadableLayer(t *testing.T) {
+	var v Verdict
+	v.Add("acl", ACL("private"))
+	v.AddPolicy(nil, errors.New("AccessDenied"))
+	entry := schema.BucketACLEntry{Container: "b", Level: "private"}
+	v.Apply(&entry)
+	if entry.Exposure != schema.ExposureUnknown || entry.Reason != "bucket policy unreadable: AccessDenied" {
+		t.Fatalf("got %+v", entry)
+	}
+
+	// A readable public layer still wins over one that could not be read.
+	var open Verdict
+	open.Add("acl", ACL("public-read"))
+	open.AddPolicy([]byte("{not json"), nil)
+	if got, _ := open.Exposure(); got != schema.ExposurePublicRead {
+		t.Fatalf("exposure = %q, want public-read", got)
+	}
+}
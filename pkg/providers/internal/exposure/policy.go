@@ -0,0 +1,217 @@
+package exposure
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// policyDocument is the S3-style bucket policy shared, with per-cloud
+// principal and action spellings, by S3, OSS, COS, OBS and TOS. Key matching
+// is case-insensitive, which also covers COS's lower-case documents.
+type policyDocument struct {
+	Statement statements `json:"Statement"`
+}
+
+type statement struct {
+	Sid          string                                `json:"Sid"`
+	Effect       string                                `json:"Effect"`
+	Principal    json.RawMessage                       `json:"Principal"`
+	NotPrincipal json.RawMessage                       `json:"NotPrincipal"`
+	Action       json.RawMessage                       `json:"Action"`
+	NotAction    json.RawMessage                       `json:"NotAction"`
+	Condition    map[string]map[string]json.RawMessage `json:"Condition"`
+}
+
+// statements accepts both a statement list and a lone statement object.
+type statements []statement
+
+func (s *statements) UnmarshalJSON(data []byte) error {
+	var list []statement
+	if err := json.Unmarshal(data, &list); err == nil {
+		*s = list
+		return nil
+	}
+	var one statement
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*s = statements{one}
+	return nil
+}
+
+// Policy analyses a bucket policy document. An Allow statement grants
+// anonymous access when its principal is "*" (or COS's anyone), any-account
+// access when the principal wildcards the account, and is only conditional
+// when a condition other than an all-networks source IP narrows it. Deny
+// statements are not subtracted, so the verdict errs towards exposure.
+func Policy(doc []byte) (Grant, error) {
+	var g Grant
+	if len(strings.TrimSpace(string(doc))) == 0 {
+		return g, nil
+	}
+	var policy policyDocument
+	if err := json.Unmarshal(doc, &policy); err != nil {
+		return g, fmt.Errorf("decode bucket policy: %w", err)
+	}
+	for i, st := range policy.Statement {
+		if !strings.EqualFold(strings.TrimSpace(st.Effect), "Allow") {
+			continue
+		}
+		audience := principalAudience(st)
+		if audience == "" {
+			continue
+		}
+		read, write := actionAccess(st)
+		if !read && !write {
+			continue
+		}
+		name := st.Sid
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		access := accessLabel(read, write)
+		if keys := narrowingConditions(st.Condition); len(keys) > 0 {
+			g.Conditional = true
+			g.Reasons = append(g.Reasons, fmt.Sprintf("policy %s allows %s %s when %s", name, audience, access, strings.Join(keys, ", ")))
+			continue
+		}
+		if audience == audienceAnyAccount {
+			g.Authenticated = true
+		} else {
+			g.Read = g.Read || read
+			g.Write = g.Write || write
+		}
+		g.Reasons = append(g.Reasons, fmt.Sprintf("policy %s allows %s %s", name, audience, access))
+	}
+	return g, nil
+}
+
+const (
+	audienceAnonymous  = "anonymous"
+	audienceAnyAccount = "any-account"
+)
+
+func principalAudience(st statement) string {
+	// Allow with NotPrincipal grants everyone but the listed principals.
+	if len(st.NotPrincipal) > 0 && string(st.NotPrincipal) != "null" {
+		return audienceAnonymous
+	}
+	audience := ""
+	for _, value := range flatten(st.Principal) {
+		value = strings.ToLower(strings.TrimSpace(value))
+		switch {
+		case value == "*", strings.Contains(value, "::anyone"):
+			return audienceAnonymous
+		case strings.Contains(value, "::*:"), strings.HasPrefix(value, "domain/*"):
+			audience = audienceAnyAccount
+		}
+	}
+	return audience
+}
+
+var (
+	readVerbs  = []string{"get", "list", "head"}
+	writeVerbs = []string{"put", "delete", "post", "append", "abort", "restore", "copy", "create", "upload"}
+)
+
+func actionAccess(st statement) (read, write bool) {
+	// NotAction grants everything except the listed actions.
+	if len(st.NotAction) > 0 && string(st.NotAction) != "null" {
+		return true, true
+	}
+	for _, action := range flatten(st.Action) {
+		name := strings.ToLower(strings.TrimSpace(action))
+		if i := strings.LastIndexAny(name, ":/"); i >= 0 {
+			name = name[i+1:]
+		}
+		if name == "*" {
+			return true, true
+		}
+		for _, verb := range readVerbs {
+			read = read || strings.HasPrefix(name, verb)
+		}
+		for _, verb := range writeVerbs {
+			write = write || strings.HasPrefix(name, verb)
+		}
+	}
+	return read, write
+}
+
+func accessLabel(read, write bool) string {
+	switch {
+	case read && write:
+		return "read-write"
+	case write:
+		return "write"
+	}
+	return "read"
+}
+
+// narrowingConditions returns the condition keys that restrict who can use
+// a statement. Transport requirements and a source IP condition open to
+// every network restrict nothing.
+func narrowingConditions(conditions map[string]map[string]json.RawMessage) []string {
+	var keys []string
+	for operator, entries := range conditions {
+		for key, raw := range entries {
+			if strings.HasSuffix(strings.ToLower(key), "securetransport") || isOpenNetwork(operator, key, flatten(raw)) {
+				continue
+			}
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func isOpenNetwork(operator, key string, values []string) bool {
+	key = strings.ToLower(key)
+	if !strings.HasSuffix(key, "sourceip") && !strings.HasSuffix(key, ":ip") {
+		return false
+	}
+	if strings.Contains(strings.ToLower(operator), "not") || len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "0.0.0.0/0" && value != "::/0" {
+			return false
+		}
+	}
+	return true
+}
+
+// flatten collects the strings of a policy value, which may be a string, a
+// list, or a map of either (e.g. {"AWS": [...]}).
+func flatten(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var value any
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil
+	}
+	var out []string
+	var walk func(any)
+	walk = func(v any) {
+		switch t := v.(type) {
+		case string:
+			out = append(out, t)
+		case bool:
+			out = append(out, fmt.Sprint(t))
+		case float64:
+			out = append(out, fmt.Sprint(t))
+		case []any:
+			for _, item := range t {
+				walk(item)
+			}
+		case map[string]any:
+			for _, item := range t {
+				walk(item)
+			}
+		}
+	}
+	walk(value)
+	return out
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 	"net/http"
 	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 type RetryDecision struct {
@@ -105,6 +107,7 @@ func (p RetryPolicy) Do(ctx context.Context, idempotent bool, fn func() (*http.R
 			return resp, err
 		}
 
+		telemetry.ObserveRetry(ctx, telemetry.DescribeOutcome(resp, err))
 		CloseResponse(resp)
 		if err := sleepFn(ctx, decision.Delay); err != nil {
 			return nil, err
@@ -26,22 +26,38 @@ const (
 	defaultEventLimit = 20
 	defaultPageSize   = defaultEventLimit
 	maxPages          = 1
+	// maxFilteredPages bounds the scan when the query filters client-side,
+	// where a single page rarely holds enough matches.
+	maxFilteredPages = 10
 )
 
 // DumpEvents returns recent AuditTrail events. `args` is interpreted as
 // `<startUnix>:<endUnix>` (or "all" / empty for service default lookback).
 func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, error) {
-	if d == nil || d.Client == nil {
-		return nil, errors.New("jdcloud audittrail: nil api client")
-	}
 	startTime, endTime, err := parseTimeWindow(args)
 	if err != nil {
 		return nil, err
 	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTime, endTime))
+}
+
+// QueryEvents returns AuditTrail events matching q. The time window goes to
+// lookupEvents; the other filters are applied to each page, since the
+// lookup attributes are already spent on the credential's access key.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	if d == nil || d.Client == nil {
+		return nil, errors.New("jdcloud audittrail: nil api client")
+	}
+	startTime, endTime := q.UnixWindow()
+	pages := maxPages
+	if q.HasFilters() {
+		pages = maxFilteredPages
+	}
+	rest := q.WithoutWindow()
 	out := make([]schema.Event, 0, defaultEventLimit)
 	seen := int64(0)
 	lookupAttributes := accessKeyLookupAttributes(d.AccessKey)
-	for page := 1; page <= maxPages; page++ {
+	for page := 1; page <= pages; page++ {
 		resp, err := d.Client.DescribeActionTrailEvents(ctx, d.Region, startTime, endTime, page, defaultPageSize, lookupAttributes)
 		if err != nil {
 			return out, err
@@ -50,15 +66,20 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 			if len(out) >= defaultEventLimit {
 				break
 			}
-			out = append(out, schema.Event{
+			event := schema.Event{
 				// Id:   ev.EventID,
 				Name: ev.EventName,
 				// API:      ev.EventName,
-				Status:   eventStatus(ev.ErrorCode, ev.ErrorMessage),
-				SourceIp: ev.IP,
+				Affected:  eventResource(ev.Resources),
+				Status:    eventStatus(ev.ErrorCode, ev.ErrorMessage),
+				SourceIp:  ev.IP,
+				Principal: ev.Identity.Principal,
 				// AccessKey: ev.AccessKeyID,
 				Time: formatEventTime(ev.EventTime),
-			})
+			}
+			if rest.Match(event) {
+				out = append(out, event)
+			}
 		}
 		seen += int64(len(resp.Result.Events))
 		if len(resp.Result.Events) == 0 {
@@ -90,6 +111,17 @@ func eventStatus(errorCode, errorMessage string) string {
 	return "Success"
 }
 
+func eventResource(resources []api.ActionTrailResource) string {
+	for _, res := range resources {
+		for _, value := range []string{res.ResourceID, res.ID, res.ResourceName, res.Name} {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
 func formatEventTime(value api.ActionTrailTimestamp) string {
 	ts := int64(value)
 	if ts <= 0 {
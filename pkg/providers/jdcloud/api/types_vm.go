@@ -16,4 +16,10 @@ type Instance struct {
 	OSType           string `json:"osType"`
 	PrivateIPAddress string `json:"privateIpAddress"`
 	ElasticIPAddress string `json:"elasticIpAddress"`
+	Tags             []Tag  `json:"tags"`
+}
+
+type Tag struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
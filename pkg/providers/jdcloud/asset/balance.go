@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/api"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -13,14 +13,14 @@ type Driver struct {
 	Region string
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 	if d.Client == nil {
-		return
+		return nil
 	}
 
 	region := d.requestRegion()
@@ -32,9 +32,14 @@ func (d *Driver) QueryAccountBalance(ctx context.Context) {
 		Version: "v1",
 		Path:    "/regions/" + region + "/assets:describeAccountAmount",
 	}, &resp)
-	if err == nil && resp.Result.AvailableAmount != "" {
-		logger.Warning("Available cash amount:", resp.Result.AvailableAmount)
+	if err != nil || resp.Result.AvailableAmount == "" {
+		return nil
 	}
+	return []schema.Balance{{
+		Kind:     schema.BalanceCredit,
+		Amount:   resp.Result.AvailableAmount,
+		Currency: "CNY",
+	}}
 }
 
 func (d *Driver) requestRegion() string {
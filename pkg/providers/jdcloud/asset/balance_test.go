@@ -12,6 +12,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/auth"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -38,7 +39,7 @@ func TestDriverQueryAccountBalanceUsesExpectedEndpoint(t *testing.T) {
 		Region: "all",
 	}
 
-	driver.QueryAccountBalance(context.Background())
+	balances := driver.QueryAccountBalance(context.Background())
 
 	if rt.gotHost != "asset.jdcloud-api.com" {
 		t.Fatalf("unexpected host: %s", rt.gotHost)
@@ -46,11 +47,12 @@ func TestDriverQueryAccountBalanceUsesExpectedEndpoint(t *testing.T) {
 	if rt.gotPath != "/v1/regions/cn-north-1/assets:describeAccountAmount" {
 		t.Fatalf("unexpected path: %s", rt.gotPath)
 	}
-	if !strings.Contains(stdout.String(), "Available cash amount: 66.80") {
-		t.Fatalf("unexpected stdout logs: %s", stdout.String())
+	want := schema.Balance{Kind: schema.BalanceCredit, Amount: "66.80", Currency: "CNY"}
+	if len(balances) != 1 || balances[0] != want {
+		t.Fatalf("unexpected balances: %+v", balances)
 	}
-	if stderr.Len() != 0 {
-		t.Fatalf("unexpected stderr logs: %s", stderr.String())
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatalf("unexpected logs: %s%s", stdout.String(), stderr.String())
 	}
 }
 
@@ -9,10 +9,12 @@ import (
 	"fmt"
 	"math/big"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -32,16 +34,25 @@ type Driver struct {
 var errNilAPIClient = errors.New("jdcloud assistant: nil api client")
 
 func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
-	ctx := context.Background()
-	if d.Client == nil {
-		logger.Error(errNilAPIClient)
+	result, err := d.Invoke(context.Background(), instanceID, osType, cmd)
+	if err != nil {
+		logger.Error(err)
 		return ""
 	}
+	return result.Output
+}
+
+// Invoke runs cmd on one instance and returns its decoded output and exit
+// code. A non-finish invocation status is returned as an error alongside
+// whatever output the assistant reported.
+func (d *Driver) Invoke(ctx context.Context, instanceID, osType, cmd string) (schema.CommandResult, error) {
+	if d.Client == nil {
+		return schema.CommandResult{}, errNilAPIClient
+	}
 
 	region := strings.TrimSpace(d.Region)
 	if region == "" {
-		logger.Error("jdcloud assistant: empty region; run `cloudlist` to populate the host cache or set a region explicitly")
-		return ""
+		return schema.CommandResult{}, errors.New("jdcloud assistant: empty region; run `cloudlist` to populate the host cache or set a region explicitly")
 	}
 
 	commandType := resolveCommandType(osType)
@@ -51,12 +62,10 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 
 	commandID, err := d.createCommand(ctx, region, commandName, commandType, commandContent)
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	if commandID == "" {
-		logger.Error("Missing command id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing command id.")
 	}
 	// The temporary command is always cleaned up; the console shell dispatches
 	// a fresh CreateCommand per keystroke so leaving these around would leak
@@ -65,12 +74,10 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 
 	invokeID, err := d.invokeCommand(ctx, region, commandID, instanceID)
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	if invokeID == "" {
-		logger.Error("Missing invocation id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing invocation id.")
 	}
 
 	return d.pollInvocation(ctx, region, invokeID)
@@ -123,7 +130,7 @@ func (d *Driver) invokeCommand(ctx context.Context, region, commandID, instanceI
 	return strings.TrimSpace(resp.Result.InvokeID), nil
 }
 
-func (d *Driver) pollInvocation(ctx context.Context, region, invokeID string) string {
+func (d *Driver) pollInvocation(ctx context.Context, region, invokeID string) (schema.CommandResult, error) {
 	attempts := 0
 	for {
 		d.sleepFor(d.pollDelay())
@@ -135,8 +142,7 @@ func (d *Driver) pollInvocation(ctx context.Context, region, invokeID string) st
 			InvokeIDs:  []string{invokeID},
 		})
 		if err != nil {
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		var resp api.DescribeInvocationsResponse
 		if err := d.Client.DoJSON(ctx, api.Request{
@@ -147,15 +153,13 @@ func (d *Driver) pollInvocation(ctx context.Context, region, invokeID string) st
 			Path:    "/regions/" + region + "/describeInvocations",
 			Body:    body,
 		}, &resp); err != nil {
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		if len(resp.Result.Invocations) == 0 {
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Missing invocation record.")
-			return ""
+			return schema.CommandResult{}, errors.New("Missing invocation record.")
 		}
 
 		inv := resp.Result.Invocations[0]
@@ -165,22 +169,24 @@ func (d *Driver) pollInvocation(ctx context.Context, region, invokeID string) st
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Timeout: Wait for command to finish. Last status:", status)
-			return ""
-		case "finish":
-			return decodeOutput(invocationOutput(inv))
-		default:
-			// failed / partial_failed / stopped / per-instance invalid / timeout
-			// / terminated / aborted / cancel / error all land here. Surface the
-			// ErrorInfo — that's where "agent offline" / "instance unreachable"
-			// signals live since JDCloud has no agent-status preflight.
-			if info := invocationErrorInfo(inv); info != "" {
-				logger.Error("Exception status: " + status + " - " + info)
-				return ""
-			}
-			logger.Error("Exception status: " + status)
-			return ""
+			return schema.CommandResult{}, fmt.Errorf("Timeout: Wait for command to finish. Last status: %s", status)
+		}
+		result := schema.CommandResult{
+			Output:   decodeOutput(invocationOutput(inv)),
+			ExitCode: invocationExitCode(inv),
+			Status:   status,
+		}
+		if status == "finish" {
+			return result, nil
+		}
+		// failed / partial_failed / stopped / per-instance invalid / timeout
+		// / terminated / aborted / cancel / error all land here. Surface the
+		// ErrorInfo — that's where "agent offline" / "instance unreachable"
+		// signals live since JDCloud has no agent-status preflight.
+		if info := invocationErrorInfo(inv); info != "" {
+			return result, errors.New("Exception status: " + status + " - " + info)
 		}
+		return result, errors.New("Exception status: " + status)
 	}
 }
 
@@ -217,6 +223,19 @@ func invocationOutput(inv api.Invocation) string {
 	return inv.InvocationInstances[0].Output
 }
 
+// invocationExitCode parses the per-instance exit code, which the assistant
+// reports as a string and leaves empty until the command has exited.
+func invocationExitCode(inv api.Invocation) *int {
+	if len(inv.InvocationInstances) == 0 {
+		return nil
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(inv.InvocationInstances[0].ExitCode))
+	if err != nil {
+		return nil
+	}
+	return &code
+}
+
 func invocationErrorInfo(inv api.Invocation) string {
 	if len(inv.InvocationInstances) > 0 {
 		if info := strings.TrimSpace(inv.InvocationInstances[0].ErrorInfo); info != "" {
@@ -249,6 +249,17 @@ func TestInvocationErrorInfoPrefersTopLevelErrorInfoOverInstanceStatus(t *testin
 	}
 }
 
+func TestInvocationExitCodeParsesInstanceExitCode(t *testing.T) {
+	inv := api.Invocation{InvocationInstances: []api.InvocationInstance{{ExitCode: "3"}}}
+	if got := invocationExitCode(inv); got == nil || *got != 3 {
+		t.Fatalf("unexpected exit code: %v", got)
+	}
+	pending := api.Invocation{InvocationInstances: []api.InvocationInstance{{ExitCode: ""}}}
+	if got := invocationExitCode(pending); got != nil {
+		t.Fatalf("expected nil exit code before exit, got %d", *got)
+	}
+}
+
 func newTestClient(baseURL string) *api.Client {
 	return api.NewClient(
 		auth.New("AKID", "SECRET", ""),
@@ -21,6 +21,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/rds"
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/vm"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -103,8 +104,8 @@ func (p *Provider) Name() string {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&asset.Driver{Client: p.apiClient, Region: p.region}).QueryAccountBalance(ctx)
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&asset.Driver{Client: p.apiClient, Region: p.region}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			vmDriver := &vm.Driver{Client: p.apiClient, Region: p.region}
@@ -230,6 +231,17 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	driver := p.newOSSDriver(p.region)
+	infos, err := p.bucketInfos(ctx, driver, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	return driver.WalkObjects(ctx, infos, q, visit)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for JDCloud IAM. The
 // access-key lifecycle follows the same sub-user action family as
 // `:describeAttachedPolicies` and `:attachSubUserPolicy`.
@@ -330,11 +342,18 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads the AuditTrail events of the configured access key.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &actiontrail.Driver{Client: p.apiClient, Region: p.region, AccessKey: p.accessKey}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for JDCloud RDS. `useradd` /
 // `userdel` create and revoke validation accounts under
 // `/v1/regions/<region>/instances/<id>/accounts`.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
-	driver := &rds.Driver{Client: p.apiClient, Region: p.region}
+	region := invcache.DatabaseRegion(ctx, instanceID, p.region)
+	driver := &rds.Driver{Client: p.apiClient, Region: region}
 	switch action {
 	case "useradd":
 		return driver.CreateAccount(ctx, instanceID)
@@ -351,18 +370,22 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 // regardless of the session's current region setting.
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
-		if p.region == "" || p.region == "all" {
-			return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+		region := p.region
+		if region == "" || region == "all" {
+			host, found := p.lookupHost(ctx, instanceID)
+			if !found || host.Region == "" {
+				return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+			}
+			region = host.Region
 		}
-		driver := &assistant.Driver{Client: p.apiClient, Region: p.region}
-		output := driver.RunCommand(instanceID, osType, command)
-		return schema.CommandResult{Output: output}, nil
+		driver := &assistant.Driver{Client: p.apiClient, Region: region}
+		return driver.Invoke(ctx, instanceID, osType, command)
 	}
 
 	if strings.HasPrefix(instanceID, "lavm-") {
 		return schema.CommandResult{}, fmt.Errorf("JDCloud shell currently supports VM only")
 	}
-	host, ok := p.lookupHost(instanceID)
+	host, ok := p.lookupHost(ctx, instanceID)
 	if !ok {
 		return schema.CommandResult{}, fmt.Errorf("unable to resolve instance metadata, run `cloudlist` first and retry")
 	}
@@ -371,17 +394,14 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 		return schema.CommandResult{}, err
 	}
 	driver := &assistant.Driver{Client: p.apiClient, Region: host.Region}
-	output := driver.RunCommand(instanceID, host.OSType, string(command))
-	return schema.CommandResult{Output: output}, nil
+	return driver.Invoke(ctx, instanceID, host.OSType, string(command))
 }
 
-func (p *Provider) lookupHost(instanceID string) (schema.Host, bool) {
-	for _, host := range vm.GetCacheHostList() {
-		if host.ID == instanceID || host.HostName == instanceID {
-			return host, true
-		}
-	}
-	return schema.Host{}, false
+// lookupHost resolves an instance from the persisted inventory of the
+// credential; this session's host list only answers when no inventory store
+// is attached (see invcache.ResolveHost).
+func (p *Provider) lookupHost(ctx context.Context, instanceID string) (schema.Host, bool) {
+	return invcache.ResolveHost(ctx, instanceID, vm.GetCacheHostList())
 }
 
 func (p *Provider) newOSSDriver(region string) *oss.Driver {
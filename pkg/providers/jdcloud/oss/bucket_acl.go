@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	awsapi "github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
@@ -148,10 +149,14 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 		if err != nil {
 			return out, fmt.Errorf("get acl for %s: %w", s.BucketName, err)
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     CannedACLFromGrants(acl),
-		})
+		}
+		var verdict exposure.Verdict
+		verdict.Add("acl", exposure.ACL(entry.Level))
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
@@ -104,7 +104,7 @@ func TestClientListObjectsV2UsesServiceHostAndContinuationToken(t *testing.T) {
 		}),
 	)
 
-	got, err := client.ListObjectsV2(context.Background(), "demo-bucket", "cn-north-1", "page-2", 100)
+	got, err := client.ListObjectsV2(context.Background(), "demo-bucket", "cn-north-1", "", "page-2", 100)
 	if err != nil {
 		t.Fatalf("ListObjectsV2() error = %v", err)
 	}
@@ -22,13 +22,16 @@ func NewClient(credential jdauth.Credential, opts ...awsapi.Option) *Client {
 	return &Client{api: awsapi.NewClient(awsCredential, opts...)}
 }
 
-func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, continuationToken string, maxKeys int) (ListObjectsV2Output, error) {
+func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, prefix, continuationToken string, maxKeys int) (ListObjectsV2Output, error) {
 	if c == nil || c.api == nil {
 		return ListObjectsV2Output{}, errors.New("jdcloud oss: nil object client")
 	}
 
 	query := url.Values{}
 	query.Set("list-type", "2")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if continuationToken = strings.TrimSpace(continuationToken); continuationToken != "" {
 		query.Set("continuation-token", continuationToken)
 	}
@@ -6,16 +6,24 @@ import (
 	awsapi "github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud"
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/api"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 // ClientConfig builds the demo replay configuration injected into
 // jdcloud.NewWithConfig when replay is active for the jdcloud provider.
 func ClientConfig() jdcloud.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every jdcloud client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) jdcloud.ClientConfig {
+	httpClient = telemetry.WrapClient("jdcloud", httpClient)
 	return jdcloud.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		ObjectAPIOptions:    []awsapi.Option{awsapi.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions:       []api.Option{api.WithHTTPClient(httpClient)},
+		ObjectAPIOptions: []awsapi.Option{awsapi.WithHTTPClient(httpClient)},
 	}
 }
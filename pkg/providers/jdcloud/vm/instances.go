@@ -121,6 +121,7 @@ func (d *Driver) listRegion(ctx context.Context, region string) ([]schema.Host,
 				OSType:      i.OSType,
 				Public:      ipv4 != "",
 				Region:      region,
+				Tags:        tagMap(i.Tags),
 			})
 		}
 
@@ -165,3 +166,14 @@ func (d *Driver) normalizedRegion() string {
 	}
 	return region
 }
+
+func tagMap(tags []api.Tag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[tag.Key] = tag.Value
+	}
+	return out
+}
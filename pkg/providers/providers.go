@@ -2,28 +2,42 @@ package providers
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/alibaba"
+	aliapi "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/api"
 	alireplay "github.com/404tk/cloudtoolkit/pkg/providers/alibaba/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/aws"
+	awsapi "github.com/404tk/cloudtoolkit/pkg/providers/aws/api"
 	awsreplay "github.com/404tk/cloudtoolkit/pkg/providers/aws/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/azure"
+	azapi "github.com/404tk/cloudtoolkit/pkg/providers/azure/api"
 	azreplay "github.com/404tk/cloudtoolkit/pkg/providers/azure/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/gcp"
+	gcpapi "github.com/404tk/cloudtoolkit/pkg/providers/gcp/api"
 	gcpreplay "github.com/404tk/cloudtoolkit/pkg/providers/gcp/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/huawei"
+	hwapi "github.com/404tk/cloudtoolkit/pkg/providers/huawei/api"
 	hwreplay "github.com/404tk/cloudtoolkit/pkg/providers/huawei/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/jdcloud"
+	jdapi "github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/api"
 	jdreplay "github.com/404tk/cloudtoolkit/pkg/providers/jdcloud/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
 	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/cassette"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent"
+	txapi "github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
 	txreplay "github.com/404tk/cloudtoolkit/pkg/providers/tencent/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud"
+	ucapi "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/api"
 	ucreplay "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/replay"
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine"
+	volcapi "github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
 	volcreplay "github.com/404tk/cloudtoolkit/pkg/providers/volcengine/replay"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils"
 )
 
 type Info struct {
@@ -34,6 +48,12 @@ type Info struct {
 type entry struct {
 	info Info
 	new  func(schema.Options) (schema.Provider, error)
+	// http builds the provider with every client routed through one HTTP
+	// client; cassette record mode and playback hook in here.
+	http func(schema.Options, *http.Client, bool) (schema.Provider, error)
+	// client returns the provider's default HTTP client; record mode wraps
+	// it so recordings keep the live timeout, transport and rate limiter.
+	client func() *http.Client
 }
 
 var catalog = []entry{
@@ -45,6 +65,12 @@ var catalog = []entry{
 			}
 			return alibaba.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := alireplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return alibaba.NewWithConfig(block, cfg)
+		},
+		client: aliapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "aws", Desc: "Amazon Web Service"},
@@ -54,6 +80,12 @@ var catalog = []entry{
 			}
 			return aws.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := awsreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return aws.NewWithConfig(block, cfg)
+		},
+		client: awsapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "tencent", Desc: "Tencent Cloud"},
@@ -63,6 +95,12 @@ var catalog = []entry{
 			}
 			return tencent.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := txreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return tencent.NewWithConfig(block, cfg)
+		},
+		client: txapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "huawei", Desc: "Huawei Cloud"},
@@ -72,6 +110,12 @@ var catalog = []entry{
 			}
 			return huawei.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := hwreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return huawei.NewWithConfig(block, cfg)
+		},
+		client: hwapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "azure", Desc: "Microsoft Azure"},
@@ -81,6 +125,12 @@ var catalog = []entry{
 			}
 			return azure.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := azreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return azure.NewWithConfig(block, cfg)
+		},
+		client: azapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "volcengine", Desc: "Volcengine"},
@@ -90,6 +140,12 @@ var catalog = []entry{
 			}
 			return volcengine.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := volcreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return volcengine.NewWithConfig(block, cfg)
+		},
+		client: volcapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "jdcloud", Desc: "JDCloud"},
@@ -99,6 +155,12 @@ var catalog = []entry{
 			}
 			return jdcloud.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := jdreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return jdcloud.NewWithConfig(block, cfg)
+		},
+		client: jdapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "gcp", Desc: "Google Cloud Platform"},
@@ -108,6 +170,12 @@ var catalog = []entry{
 			}
 			return gcp.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := gcpreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return gcp.NewWithConfig(block, cfg)
+		},
+		client: gcpapi.NewHTTPClient,
 	},
 	{
 		info: Info{Name: "ucloud", Desc: "UCloud"},
@@ -117,6 +185,12 @@ var catalog = []entry{
 			}
 			return ucloud.New(block)
 		},
+		http: func(block schema.Options, client *http.Client, skipCache bool) (schema.Provider, error) {
+			cfg := ucreplay.HTTPClientConfig(client)
+			cfg.SkipCredentialCache = skipCache
+			return ucloud.NewWithConfig(block, cfg)
+		},
+		client: ucapi.NewHTTPClient,
 	},
 }
 
@@ -148,5 +222,38 @@ func New(name string, block schema.Options) (schema.Provider, error) {
 	if !ok {
 		return nil, fmt.Errorf("invalid provider name found: %s", name)
 	}
+	if replay.IsActiveForProvider(name) {
+		return item.new(block)
+	}
+	if path := cassette.PlaybackPath(name); path != "" {
+		transport, err := cassette.TransportFor(name, path)
+		if err != nil {
+			return nil, err
+		}
+		return item.http(block, &http.Client{Transport: faults.Wrap(transport)}, true)
+	}
+	if path := cassette.RecordPath(name); path != "" {
+		recorder := cassette.RecorderFor(name, path)
+		seedRedactor(recorder.Redactor, block)
+		// Recording talks to the live cloud through the provider's own
+		// client, so it keeps the timeout and pacing of item.new.
+		return item.http(block, recorder.Client(item.client()), false)
+	}
 	return item.new(block)
 }
+
+// seedRedactor registers the session's own credentials and tenant
+// identifiers, which the recorder could not otherwise tell apart from
+// ordinary values.
+func seedRedactor(redactor *cassette.Redactor, block schema.Options) {
+	for name, value := range block {
+		switch {
+		case name == utils.AccessKey || name == utils.AzureClientId:
+			redactor.Add(cassette.KindAccessKey, value)
+		case name == utils.AzureTenantId || name == utils.AzureSubscriptionId:
+			redactor.Add(cassette.KindAccount, value)
+		case registry.SensitiveOption(name):
+			redactor.Add(cassette.KindSecret, value)
+		}
+	}
+}
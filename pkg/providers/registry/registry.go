@@ -182,6 +182,8 @@ func optionDescriptions() map[string]string {
 	}
 	items[utils.Payload] = "Validation payload (Default: cloudlist)"
 	items[utils.Metadata] = "Set the payload with additional arguments (Optional)"
+	items[utils.Format] = "Result output format: text, json, ndjson, csv or findings (Default: text)"
+	items[utils.Detect] = "Wait this long (e.g. 10m) for a mutation to appear in the audit trail (Default: off)"
 	return items
 }
 
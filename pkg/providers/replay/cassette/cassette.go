@@ -0,0 +1,156 @@
+// Package cassette records live provider traffic into redacted fixture files
+// and serves them back through a generic replay transport.
+//
+// Record mode wraps the HTTP client a provider is built with (every provider
+// accepts one through its ClientConfig) so each request/response pair is
+// captured after credentials, signatures, tokens, account IDs and IP
+// addresses are swapped for stable pseudonyms. Playback answers requests from
+// a cassette by method, host, path and the normalized action parameters, so
+// the real provider and driver stack runs unchanged against recorded data.
+//
+// Both modes are switched on through the environment: RecordEnv names the
+// cassette to write and PlaybackEnv the cassette to serve. Either may point
+// at a directory, in which case the file is <dir>/<provider>.cassette.json.
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// RecordEnv enables record mode for live providers.
+	RecordEnv = "CTK_REPLAY_RECORD"
+	// PlaybackEnv serves providers from a recorded cassette.
+	PlaybackEnv = "CTK_REPLAY_CASSETTE"
+)
+
+// Version is the cassette file format version.
+const Version = 1
+
+const base64Encoding = "base64"
+
+// Cassette is the on-disk recording of one provider session.
+type Cassette struct {
+	Version      int           `json:"version"`
+	Provider     string        `json:"provider"`
+	RecordedAt   time.Time     `json:"recorded_at"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is one redacted request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the redacted request. Authentication headers are never stored;
+// Action carries the API action when it travels outside the URL or body
+// (X-TC-Action, X-Amz-Target, ...).
+type Request struct {
+	Method       string `json:"method"`
+	URL          string `json:"url"`
+	Action       string `json:"action,omitempty"`
+	ContentType  string `json:"content_type,omitempty"`
+	Body         string `json:"body,omitempty"`
+	BodyEncoding string `json:"body_encoding,omitempty"`
+}
+
+// Response is the redacted response.
+type Response struct {
+	Status       int                 `json:"status"`
+	Header       map[string][]string `json:"header,omitempty"`
+	Body         string              `json:"body,omitempty"`
+	BodyEncoding string              `json:"body_encoding,omitempty"`
+}
+
+// RecordPath returns the cassette record mode writes for provider, or "" when
+// record mode is off.
+func RecordPath(provider string) string {
+	return resolvePath(os.Getenv(RecordEnv), provider)
+}
+
+// PlaybackPath returns the cassette served for provider, or "" when playback
+// is off.
+func PlaybackPath(provider string) string {
+	return resolvePath(os.Getenv(PlaybackEnv), provider)
+}
+
+// Playing reports whether providers are served from a cassette. Callers use
+// it to keep pseudonymized data out of persistent state.
+func Playing() bool {
+	return strings.TrimSpace(os.Getenv(PlaybackEnv)) != ""
+}
+
+func resolvePath(value, provider string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	if info, err := os.Stat(value); (err == nil && info.IsDir()) || strings.HasSuffix(value, string(os.PathSeparator)) {
+		return filepath.Join(value, provider+".cassette.json")
+	}
+	return value
+}
+
+// Load reads a cassette file.
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("cassette: decode %s: %w", path, err)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("cassette: %s has version %d, want %d", path, c.Version, Version)
+	}
+	return &c, nil
+}
+
+// Save writes c to path through a temp file.
+func (c *Cassette) Save(path string) error {
+	if c == nil {
+		return errors.New("cassette: nil cassette")
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// encodeBody stores text bodies verbatim and anything else as base64.
+func encodeBody(body []byte) (string, string) {
+	if len(body) == 0 {
+		return "", ""
+	}
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), base64Encoding
+}
+
+func decodeBody(body, encoding string) ([]byte, error) {
+	if encoding == base64Encoding {
+		return base64.StdEncoding.DecodeString(body)
+	}
+	return []byte(body), nil
+}
@@ -0,0 +1,140 @@
+package cassette
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// actionHeaders carry the API action for clouds that keep it out of the URL.
+var actionHeaders = []string{"X-TC-Action", "X-Amz-Target", "X-Acs-Action"}
+
+// volatileParams change on every call (signing material, nonces, clocks) and
+// never take part in matching.
+var volatileParams = map[string]bool{
+	"signature":        true,
+	"signaturemethod":  true,
+	"signatureversion": true,
+	"signaturenonce":   true,
+	"timestamp":        true,
+	"nonce":            true,
+	"expires":          true,
+	"accesskeyid":      true,
+	"accesskey":        true,
+	"securitytoken":    true,
+	"token":            true,
+	"publickey":        true,
+	"_":                true,
+}
+
+var volatileParamPrefixes = []string{"x-amz-", "x-tos-", "x-acs-signature", "x-jdcloud-"}
+
+var accountNumberPattern = regexp.MustCompile(`\b\d{12}\b`)
+
+// key identifies a request for playback. exact covers method, host, path,
+// action and every stable parameter (query, form body, canonical JSON body);
+// loose drops the parameters so a request whose body carries a random name
+// or generated password still finds its recording.
+type key struct {
+	exact string
+	loose string
+}
+
+func requestKey(method, rawURL, action, contentType string, body []byte) key {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		u = &url.URL{Path: rawURL}
+	}
+	params := url.Values{}
+	for name, values := range u.Query() {
+		params[name] = append(params[name], values...)
+	}
+	var canonical string
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for name, values := range form {
+				params[name] = append(params[name], values...)
+			}
+		}
+	case len(body) > 0 && json.Valid(body):
+		var doc any
+		if json.Unmarshal(body, &doc) == nil {
+			data, _ := json.Marshal(doc)
+			canonical = string(data)
+		}
+	}
+	if action == "" {
+		action = params.Get("Action")
+	}
+	host := strings.ToLower(u.Hostname())
+	loose := strings.Join([]string{strings.ToUpper(method), normalize(host), normalize(u.EscapedPath()), action}, " ")
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if !volatileParam(name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(loose)
+	for _, name := range names {
+		values := append([]string(nil), params[name]...)
+		sort.Strings(values)
+		for _, value := range values {
+			b.WriteString(" ")
+			b.WriteString(name)
+			b.WriteString("=")
+			b.WriteString(normalize(value))
+		}
+	}
+	if canonical != "" {
+		b.WriteString(" ")
+		b.WriteString(normalize(canonical))
+	}
+	return key{exact: b.String(), loose: loose}
+}
+
+// Fingerprint identifies req the way playback matches it: signing material,
+// nonces and clocks are ignored, so every attempt of one logical request
+// shares a fingerprint.
+func Fingerprint(req *http.Request, body []byte) string {
+	return requestKey(req.Method, req.URL.String(), requestAction(req.Header), req.Header.Get("Content-Type"), body).exact
+}
+
+// requestAction reads the API action from the headers that carry it.
+func requestAction(header http.Header) string {
+	for _, name := range actionHeaders {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// normalize folds identifiers that differ between the recording and the
+// session replaying it (subscription and tenant UUIDs, 12-digit account IDs,
+// IP addresses) to placeholders, so a cassette recorded under one account
+// answers a session configured with another.
+func normalize(s string) string {
+	s = uuidPattern.ReplaceAllString(s, "{uuid}")
+	s = accountNumberPattern.ReplaceAllString(s, "{account}")
+	return ipv4Pattern.ReplaceAllString(s, "{ip}")
+}
+
+func volatileParam(name string) bool {
+	name = strings.ToLower(name)
+	if volatileParams[name] {
+		return true
+	}
+	for _, prefix := range volatileParamPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
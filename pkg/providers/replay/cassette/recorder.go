@@ -0,0 +1,226 @@
+package cassette
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// droppedResponseHeaders never reach a cassette: they identify the caller or
+// are recomputed on playback.
+var droppedResponseHeaders = map[string]bool{
+	"Set-Cookie":     true,
+	"Authorization":  true,
+	"Content-Length": true,
+	"Date":           true,
+	"X-Amz-Id-2":     true,
+}
+
+// FlushInterval bounds how long recorded exchanges stay in memory before
+// the cassette is rewritten. Batching keeps recording linear in the number of
+// exchanges; an interrupted session loses at most this window.
+const FlushInterval = 2 * time.Second
+
+// Recorder is an http.RoundTripper that forwards to Next and appends every
+// exchange, redacted, to the cassette at Path. The file is written at most
+// once per FlushInterval and again on Flush or Close.
+type Recorder struct {
+	Next     http.RoundTripper
+	Path     string
+	Redactor *Redactor
+
+	mu       sync.Mutex
+	cassette Cassette
+	dirty    bool
+	timer    *time.Timer
+}
+
+var (
+	recordersMu sync.Mutex
+	recorders   = map[string]*Recorder{}
+)
+
+// RecorderFor returns the process-wide recorder for path, so every provider
+// built during one session appends to the same cassette.
+func RecorderFor(provider, path string) *Recorder {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	if rec, ok := recorders[path]; ok {
+		return rec
+	}
+	rec := &Recorder{
+		Path:     path,
+		Redactor: NewRedactor(),
+		cassette: Cassette{Version: Version, Provider: provider, RecordedAt: time.Now().UTC()},
+	}
+	recorders[path] = rec
+	return rec
+}
+
+// FlushAll writes every pending recording. Callers run it before the process
+// exits.
+func FlushAll() {
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+	for _, rec := range recorders {
+		if err := rec.Close(); err != nil {
+			logger.Warning("Replay cassette not written:", err)
+		}
+	}
+}
+
+// Client returns a copy of base that records through r. base keeps its
+// timeout and its transport — the provider's tuned, rate-limited stack —
+// which r sits in front of; a nil base records through r.Next.
+func (r *Recorder) Client(base *http.Client) *http.Client {
+	if base == nil {
+		return &http.Client{Transport: r}
+	}
+	client := *base
+	client.Transport = &recordingTransport{rec: r, next: base.Transport}
+	return &client
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	return r.roundTrip(r.Next, req)
+}
+
+// recordingTransport records one client's exchanges into a shared Recorder.
+type recordingTransport struct {
+	rec  *Recorder
+	next http.RoundTripper
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.rec.roundTrip(t.next, req)
+}
+
+func (r *Recorder) roundTrip(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	action := requestAction(req.Header)
+
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	r.record(req, action, body, resp, respBody)
+	return resp, nil
+}
+
+// Flush writes the cassette if exchanges were recorded since the last write.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.flushLocked()
+}
+
+// Close stops the flush timer and writes any pending exchanges. The recorder
+// stays usable; a later exchange schedules a new write.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	return r.flushLocked()
+}
+
+func (r *Recorder) flushLocked() error {
+	if !r.dirty {
+		return nil
+	}
+	if err := r.cassette.Save(r.Path); err != nil {
+		return err
+	}
+	r.dirty = false
+	return nil
+}
+
+func (r *Recorder) record(req *http.Request, action string, body []byte, resp *http.Response, respBody []byte) {
+	redactor := r.Redactor
+	// The response goes first: account IDs it reveals are then also
+	// redacted from the request path and body.
+	response := Response{Status: resp.StatusCode, Header: map[string][]string{}}
+	respContentType := resp.Header.Get("Content-Type")
+	response.Body, response.BodyEncoding = encodeBody(respBody)
+	if response.BodyEncoding == "" {
+		response.Body = string(redactor.Body(respContentType, respBody))
+	}
+	for name, values := range resp.Header {
+		if droppedResponseHeaders[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		redacted := make([]string, len(values))
+		for i, value := range values {
+			redacted[i] = redactor.String(value)
+		}
+		response.Header[name] = redacted
+	}
+
+	contentType := req.Header.Get("Content-Type")
+
+	u := *req.URL
+	u.User = nil
+	query := u.Query()
+	redactor.Values(query)
+	u.RawQuery = query.Encode()
+	u.Host = redactor.String(u.Host)
+	u.Path = redactor.String(u.Path)
+	u.RawPath = ""
+
+	reqBody, reqEncoding := encodeBody(body)
+	if reqEncoding == "" {
+		reqBody = string(redactor.Body(contentType, body))
+	}
+	out := Interaction{
+		Request: Request{
+			Method:       req.Method,
+			URL:          u.String(),
+			Action:       action,
+			ContentType:  contentType,
+			Body:         reqBody,
+			BodyEncoding: reqEncoding,
+		},
+		Response: response,
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, out)
+	r.dirty = true
+	if r.timer == nil {
+		r.timer = time.AfterFunc(FlushInterval, r.flushTimer)
+	}
+}
+
+func (r *Recorder) flushTimer() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timer = nil
+	if err := r.flushLocked(); err != nil {
+		logger.Warning("Replay cassette not written:", err)
+	}
+}
@@ -0,0 +1,312 @@
+package cassette
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Kind classifies a sensitive value and picks the shape of its pseudonym.
+type Kind string
+
+const (
+	KindAccessKey Kind = "access-key"
+	KindSecret    Kind = "secret"
+	KindAccount   Kind = "account"
+	KindIP        Kind = "ip"
+)
+
+var (
+	// Access key IDs of the supported clouds, recognised wherever they
+	// appear (credential reports, CloudTrail records, ...).
+	accessKeyPattern = regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b|\bLTAI[A-Za-z0-9]{12,30}\b|\bAKID[A-Za-z0-9]{13,40}\b|\bAKLT[A-Za-z0-9_-]{20,60}\b`)
+	jwtPattern       = regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}`)
+	arnPattern       = regexp.MustCompile(`arn:aws[a-z-]*:[a-z0-9-]*:[a-z0-9-]*:(\d{12}):`)
+	ipv4Pattern      = regexp.MustCompile(`\b\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}\b`)
+	dashedIPPattern  = regexp.MustCompile(`\b\d{1,3}-\d{1,3}-\d{1,3}-\d{1,3}\b`)
+	numberPattern    = regexp.MustCompile(`\b\d{4,}\b`)
+	xmlFieldPattern  = regexp.MustCompile(`<([A-Za-z_][\w.-]*)>([^<]{1,1000})</([A-Za-z_][\w.-]*)>`)
+)
+
+// Redactor swaps credentials, tokens, account IDs and IP addresses for
+// pseudonyms. A value always maps to the same pseudonym within one Redactor,
+// so an ID returned by one call and sent back in the next stays linked.
+type Redactor struct {
+	mu       sync.Mutex
+	values   map[string]string
+	counts   map[Kind]int
+	uuids    int
+	replacer *strings.Replacer
+}
+
+// NewRedactor returns an empty Redactor.
+func NewRedactor() *Redactor {
+	return &Redactor{values: map[string]string{}, counts: map[Kind]int{}}
+}
+
+// Add registers value as sensitive and returns its pseudonym.
+func (r *Redactor) Add(kind Kind, value string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.add(kind, value)
+}
+
+// String redacts every registered value and every recognised pattern in s.
+func (r *Redactor) String(s string) string {
+	if s == "" {
+		return s
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.learnPatterns(s)
+	return r.replace(s)
+}
+
+// Body learns sensitive values from the field names of a JSON, XML or form
+// body and returns the body with everything registered redacted.
+func (r *Redactor) Body(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	text := string(body)
+	switch {
+	case strings.Contains(contentType, "json") || json.Valid(body):
+		var doc any
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		if decoder.Decode(&doc) == nil {
+			r.learnJSON("", doc)
+		}
+	case strings.Contains(contentType, "xml") || strings.HasPrefix(strings.TrimSpace(text), "<"):
+		for _, match := range xmlFieldPattern.FindAllStringSubmatch(text, -1) {
+			if match[1] == match[3] {
+				r.learnField(match[1], match[2])
+			}
+		}
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		if form, err := url.ParseQuery(text); err == nil {
+			r.learnValues(form)
+		}
+	}
+	r.learnPatterns(text)
+	return []byte(r.replace(text))
+}
+
+// Values learns from and redacts query or form values in place.
+func (r *Redactor) Values(values url.Values) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.learnValues(values)
+	for key, items := range values {
+		for i, item := range items {
+			r.learnPatterns(item)
+			items[i] = r.replace(item)
+		}
+		values[key] = items
+	}
+}
+
+func (r *Redactor) learnValues(values url.Values) {
+	for key, items := range values {
+		for _, item := range items {
+			r.learnField(key, item)
+		}
+	}
+}
+
+func (r *Redactor) learnJSON(key string, node any) {
+	switch value := node.(type) {
+	case map[string]any:
+		for k, v := range value {
+			r.learnJSON(k, v)
+		}
+	case []any:
+		for _, v := range value {
+			r.learnJSON(key, v)
+		}
+	case string:
+		r.learnField(key, value)
+	case json.Number:
+		r.learnField(key, value.String())
+	}
+}
+
+func (r *Redactor) learnField(name, value string) {
+	if kind, ok := fieldKind(name); ok {
+		r.add(kind, value)
+	}
+}
+
+func (r *Redactor) learnPatterns(s string) {
+	for _, match := range accessKeyPattern.FindAllString(s, -1) {
+		r.add(KindAccessKey, match)
+	}
+	for _, match := range jwtPattern.FindAllString(s, -1) {
+		r.add(KindSecret, match)
+	}
+	for _, match := range arnPattern.FindAllStringSubmatch(s, -1) {
+		r.add(KindAccount, match[1])
+	}
+	for _, match := range ipv4Pattern.FindAllString(s, -1) {
+		if redactableIP(match) {
+			r.add(KindIP, match)
+		}
+	}
+	// Public DNS names embed the address with dashes (ec2-203-0-113-10...).
+	for _, match := range dashedIPPattern.FindAllString(s, -1) {
+		dotted := strings.ReplaceAll(match, "-", ".")
+		if _, ok := r.values[match]; !ok && redactableIP(dotted) {
+			r.values[match] = strings.ReplaceAll(r.add(KindIP, dotted), ".", "-")
+			r.replacer = nil
+		}
+	}
+}
+
+// add registers value; callers hold mu.
+func (r *Redactor) add(kind Kind, value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 4 {
+		return value
+	}
+	if pseudonym, ok := r.values[value]; ok {
+		return pseudonym
+	}
+	var pseudonym string
+	if isUUID(value) {
+		// Client, tenant and subscription IDs keep their shape so
+		// playback can still recognise them in URL paths.
+		r.uuids++
+		pseudonym = fmt.Sprintf("00000000-0000-0000-0000-%012d", r.uuids)
+	} else {
+		r.counts[kind]++
+		pseudonym = pseudonymFor(kind, value, r.counts[kind])
+	}
+	r.values[value] = pseudonym
+	r.replacer = nil
+	return pseudonym
+}
+
+// replace swaps registered values in s; callers hold mu. Numbers and IPv4
+// addresses are matched on token boundaries so "10.0.0.1" never rewrites part
+// of "10.0.0.12"; everything else is a literal, longest value first.
+func (r *Redactor) replace(s string) string {
+	if r.replacer == nil {
+		literals := make([]string, 0, len(r.values))
+		for value := range r.values {
+			if !isDigits(value) && net.ParseIP(value) == nil {
+				literals = append(literals, value)
+			}
+		}
+		sort.Slice(literals, func(i, j int) bool {
+			if len(literals[i]) != len(literals[j]) {
+				return len(literals[i]) > len(literals[j])
+			}
+			return literals[i] < literals[j]
+		})
+		pairs := make([]string, 0, 2*len(literals))
+		for _, value := range literals {
+			pairs = append(pairs, value, r.values[value])
+		}
+		r.replacer = strings.NewReplacer(pairs...)
+	}
+	s = r.replacer.Replace(s)
+	lookup := func(match string) string {
+		if pseudonym, ok := r.values[match]; ok {
+			return pseudonym
+		}
+		return match
+	}
+	s = ipv4Pattern.ReplaceAllStringFunc(s, lookup)
+	return numberPattern.ReplaceAllStringFunc(s, lookup)
+}
+
+// fieldKind classifies a JSON key, XML element or form parameter name.
+// Pagination cursors are left alone even though they are called "...Token".
+func fieldKind(name string) (Kind, bool) {
+	key := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(name))
+	switch {
+	case key == "":
+		return "", false
+	case strings.Contains(key, "next"), strings.Contains(key, "page"),
+		strings.Contains(key, "continuation"), strings.Contains(key, "marker"):
+		return "", false
+	case key == "accesskeyid", key == "accesskey", key == "akid", key == "secretid",
+		key == "tmpsecretid", key == "clientid", key == "xamzcredential":
+		return KindAccessKey, true
+	case key == "accountid", key == "account", key == "owneraccount", key == "ownerid",
+		key == "owneruin", key == "uin", key == "mainuin", key == "payeruin", key == "operatoruin",
+		key == "domainid", key == "tenantid", key == "subscriptionid", key == "awsaccountid":
+		return KindAccount, true
+	case strings.HasSuffix(key, "token"), strings.HasSuffix(key, "secret"),
+		strings.HasSuffix(key, "password"), strings.HasSuffix(key, "signature"),
+		key == "secretkey", key == "secretaccesskey", key == "accesskeysecret", key == "tmpsecretkey",
+		key == "privatekey", key == "assertion", key == "authorization":
+		return KindSecret, true
+	}
+	return "", false
+}
+
+func pseudonymFor(kind Kind, value string, n int) string {
+	switch kind {
+	case KindAccessKey:
+		return fmt.Sprintf("REDACTEDAK%06d", n)
+	case KindSecret:
+		return fmt.Sprintf("REDACTED-SECRET-%04d", n)
+	case KindIP:
+		// 198.18.0.0/15 is reserved for benchmarking and never routes.
+		return fmt.Sprintf("198.%d.%d.%d", 18+(n/65536)%2, (n/256)%256, n%256)
+	case KindAccount:
+		switch {
+		case isDigits(value):
+			// A leading 9 keeps JSON numbers valid and the length intact.
+			return fmt.Sprintf("9%0*d", len(value)-1, n)
+		case isHex(value):
+			return fmt.Sprintf("%0*x", len(value), n)
+		}
+		return fmt.Sprintf("account-%04d", n)
+	}
+	return fmt.Sprintf("REDACTED-%04d", n)
+}
+
+// redactableIP keeps wildcard, loopback, link-local and broadcast addresses:
+// they carry meaning (0.0.0.0/0 in a security group rule) and identify
+// nobody.
+func redactableIP(value string) bool {
+	ip := net.ParseIP(value).To4()
+	if ip == nil {
+		return false
+	}
+	return !ip.IsUnspecified() && !ip.IsLoopback() && !ip.IsLinkLocalUnicast() &&
+		!ip.Equal(net.IPv4bcast) && ip[0] != 255
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func isHex(value string) bool {
+	for _, r := range strings.ToLower(value) {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return len(value) >= 16
+}
+
+var uuidPattern = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+
+func isUUID(value string) bool {
+	return len(value) == 36 && uuidPattern.MatchString(value)
+}
@@ -0,0 +1,131 @@
+package cassette
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport is an http.RoundTripper that answers requests from a cassette.
+// Interactions sharing a key are served in recorded order and the last one
+// repeats, so list-then-mutate-then-list sessions replay their state changes.
+type Transport struct {
+	cassette *Cassette
+
+	mu     sync.Mutex
+	exact  map[string][]int
+	loose  map[string][]int
+	served map[string]int
+}
+
+var (
+	transportsMu sync.Mutex
+	transports   = map[string]*Transport{}
+)
+
+// NewTransport indexes c for playback.
+func NewTransport(c *Cassette) *Transport {
+	t := &Transport{
+		cassette: c,
+		exact:    map[string][]int{},
+		loose:    map[string][]int{},
+		served:   map[string]int{},
+	}
+	for i, interaction := range c.Interactions {
+		req := interaction.Request
+		body, err := decodeBody(req.Body, req.BodyEncoding)
+		if err != nil {
+			continue
+		}
+		k := requestKey(req.Method, req.URL, req.Action, req.ContentType, body)
+		t.exact[k.exact] = append(t.exact[k.exact], i)
+		t.loose[k.loose] = append(t.loose[k.loose], i)
+	}
+	return t
+}
+
+// TransportFor loads the cassette at path once per process and returns its
+// shared transport, so playback order carries across providers rebuilt
+// during one session.
+func TransportFor(provider, path string) (*Transport, error) {
+	transportsMu.Lock()
+	defer transportsMu.Unlock()
+	if t, ok := transports[path]; ok {
+		return t, nil
+	}
+	c, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if c.Provider != "" && c.Provider != provider {
+		return nil, fmt.Errorf("cassette: %s was recorded for %s, not %s", path, c.Provider, provider)
+	}
+	t := NewTransport(c)
+	transports[path] = t
+	return t, nil
+}
+
+// Client returns an HTTP client served by t.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	k := requestKey(req.Method, req.URL.String(), requestAction(req.Header), req.Header.Get("Content-Type"), body)
+
+	t.mu.Lock()
+	index, ok := t.next("exact "+k.exact, t.exact[k.exact])
+	if !ok {
+		index, ok = t.next("loose "+k.loose, t.loose[k.loose])
+	}
+	t.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("cassette: no recorded interaction for %s", k.loose)
+	}
+
+	recorded := t.cassette.Interactions[index].Response
+	respBody, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return nil, fmt.Errorf("cassette: interaction %d: %w", index, err)
+	}
+	header := http.Header{}
+	for name, values := range recorded.Header {
+		header[name] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		StatusCode:    recorded.Status,
+		Status:        fmt.Sprintf("%d %s", recorded.Status, strings.TrimSpace(http.StatusText(recorded.Status))),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(respBody)),
+		ContentLength: int64(len(respBody)),
+		Request:       req,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}, nil
+}
+
+// next picks the first unserved interaction of candidates, repeating the last
+// once all have been served. Callers hold mu.
+func (t *Transport) next(counter string, candidates []int) (int, bool) {
+	if len(candidates) == 0 {
+		return 0, false
+	}
+	n := t.served[counter]
+	t.served[counter] = n + 1
+	if n >= len(candidates) {
+		n = len(candidates) - 1
+	}
+	return candidates[n], true
+}
@@ -0,0 +1,168 @@
+// Package faults injects failures into the demo replay transports so the
+// retry policies, partial-error paths and exit codes of the real provider
+// stack run end to end without a live account.
+//
+// A profile is a comma-separated list of faults, each optionally followed by
+// ":<arg>":
+//
+//	throttle[:seconds]   every 5th request answers 429 with Retry-After once
+//	flaky[:n]            every nth request (default 3) answers 503 once
+//	auth-expiry[:n]      every request after the nth (default 15) answers 401
+//	slow[:duration]      every response is delayed (default 500ms)
+//	truncate             follow-up pages of paginated listings come back cut short
+//	region:<name>        requests to region <name> answer 403
+//
+// throttle and flaky fail only the first attempt of a request, so a working
+// retry policy recovers fully; auth-expiry, truncate and region produce
+// ResourceErrors and a partial cloudlist.
+package faults
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fault names accepted in a profile.
+const (
+	Throttle   = "throttle"
+	Flaky      = "flaky"
+	AuthExpiry = "auth-expiry"
+	Slow       = "slow"
+	Truncate   = "truncate"
+	Region     = "region"
+)
+
+var descriptions = map[string]string{
+	Throttle:   "every 5th request answers 429 with Retry-After once (arg: seconds, default 1)",
+	Flaky:      "every nth request answers 503 once (arg: n, default 3)",
+	AuthExpiry: "credentials expire after n requests (arg: n, default 15)",
+	Slow:       "delay every response (arg: duration, default 500ms)",
+	Truncate:   "follow-up pages of paginated listings come back cut short",
+	Region:     "requests to one region answer 403 (arg: region, required)",
+}
+
+// Profile is a parsed fault profile. The zero value injects nothing.
+type Profile struct {
+	Spec string
+
+	throttleAfter time.Duration
+	throttle      bool
+	flakyEvery    int
+	expireAfter   int
+	delay         time.Duration
+	truncate      bool
+	regions       []string
+}
+
+// Empty reports whether p injects nothing.
+func (p Profile) Empty() bool {
+	return !p.throttle && p.flakyEvery == 0 && p.expireAfter == 0 && p.delay == 0 && !p.truncate && len(p.regions) == 0
+}
+
+// Parse reads a profile such as "throttle,slow:200ms,region:us-west-2".
+func Parse(spec string) (Profile, error) {
+	p := Profile{Spec: strings.TrimSpace(spec)}
+	for _, item := range strings.Split(p.Spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, arg, _ := strings.Cut(item, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		arg = strings.TrimSpace(arg)
+		switch name {
+		case Throttle:
+			seconds, err := positiveInt(name, arg, 1)
+			if err != nil {
+				return Profile{}, err
+			}
+			p.throttle = true
+			p.throttleAfter = time.Duration(seconds) * time.Second
+		case Flaky:
+			every, err := positiveInt(name, arg, 3)
+			if err != nil {
+				return Profile{}, err
+			}
+			p.flakyEvery = every
+		case AuthExpiry:
+			after, err := positiveInt(name, arg, 15)
+			if err != nil {
+				return Profile{}, err
+			}
+			p.expireAfter = after
+		case Slow:
+			p.delay = 500 * time.Millisecond
+			if arg != "" {
+				delay, err := time.ParseDuration(arg)
+				if err != nil || delay <= 0 {
+					return Profile{}, fmt.Errorf("fault %s: invalid duration %q", name, arg)
+				}
+				p.delay = delay
+			}
+		case Truncate:
+			p.truncate = true
+		case Region:
+			if arg == "" {
+				return Profile{}, fmt.Errorf("fault %s needs a region, e.g. region:us-west-2", name)
+			}
+			p.regions = append(p.regions, arg)
+		default:
+			return Profile{}, fmt.Errorf("unknown fault %q (expected one of: %s)", name, strings.Join(Names(), ", "))
+		}
+	}
+	return p, nil
+}
+
+func positiveInt(name, arg string, fallback int) (int, error) {
+	if arg == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("fault %s: invalid count %q", name, arg)
+	}
+	return n, nil
+}
+
+// Names lists the fault names accepted by Parse.
+func Names() []string {
+	names := make([]string, 0, len(descriptions))
+	for name := range descriptions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Describe returns the one-line help of a fault name.
+func Describe(name string) string {
+	return descriptions[name]
+}
+
+var (
+	activeMu sync.RWMutex
+	active   Profile
+)
+
+// Enable makes p the profile every replay transport applies.
+func Enable(p Profile) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+	active = p
+}
+
+// Disable stops injecting faults.
+func Disable() {
+	Enable(Profile{})
+}
+
+// Active returns the profile in effect.
+func Active() Profile {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return active
+}
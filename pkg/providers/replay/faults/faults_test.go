@@ -0,0 +1,119 @@
+package faults
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type okTransport struct{ calls int }
+
+func (o *okTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	o.calls++
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"Items":[1,2,3,4,5,6,7,8]}`)),
+		Request:    req,
+	}, nil
+}
+
+func get(t *testing.T, rt http.RoundTripper, rawURL string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("RoundTrip(%s) error = %v", rawURL, err)
+	}
+	return resp
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse(" throttle:2, flaky , slow:10ms,region:us-west-2 ")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !p.throttle || p.throttleAfter != 2*time.Second || p.flakyEvery != 3 || p.delay != 10*time.Millisecond || p.regions[0] != "us-west-2" {
+		t.Fatalf("Parse() = %+v", p)
+	}
+	if p, err := Parse(""); err != nil || !p.Empty() {
+		t.Fatalf("Parse(\"\") = %+v, %v", p, err)
+	}
+	for _, spec := range []string{"meteor", "region", "flaky:0", "slow:soon"} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("Parse(%q) accepted", spec)
+		}
+	}
+}
+
+func TestThrottleFailsOnceAndRetryPasses(t *testing.T) {
+	p, _ := Parse("throttle:3")
+	Enable(p)
+	defer Disable()
+	next := &okTransport{}
+	rt := Wrap(next)
+
+	for i := 1; i <= 4; i++ {
+		if resp := get(t, rt, "https://ec2.amazonaws.com/?Action=Describe&n="+strconv.Itoa(i)); resp.StatusCode != http.StatusOK {
+			t.Fatalf("request %d status = %d", i, resp.StatusCode)
+		}
+	}
+	target := "https://ec2.amazonaws.com/?Action=DescribeRegions&Signature=a"
+	resp := get(t, rt, target)
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "3" {
+		t.Fatalf("5th request = %d Retry-After %q", resp.StatusCode, resp.Header.Get("Retry-After"))
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "<Code>Throttling</Code>") {
+		t.Fatalf("error body = %s", body)
+	}
+	if resp := get(t, rt, strings.Replace(target, "Signature=a", "Signature=b", 1)); resp.StatusCode != http.StatusOK {
+		t.Fatalf("retry status = %d", resp.StatusCode)
+	}
+	if next.calls != 5 {
+		t.Fatalf("upstream calls = %d, want 5", next.calls)
+	}
+}
+
+func TestAuthExpiryRegionAndTruncate(t *testing.T) {
+	p, _ := Parse("auth-expiry:2,region:europe-west1,truncate")
+	Enable(p)
+	defer Disable()
+	rt := Wrap(&okTransport{})
+
+	if resp := get(t, rt, "https://compute.googleapis.com/compute/v1/projects/p/zones/europe-west1-b/instances"); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("region request status = %d", resp.StatusCode)
+	}
+	first := get(t, rt, "https://compute.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instances")
+	full, _ := io.ReadAll(first.Body)
+	next := get(t, rt, "https://compute.googleapis.com/compute/v1/projects/p/zones/us-central1-a/instances?pageToken="+url.QueryEscape("abc"))
+	cut, _ := io.ReadAll(next.Body)
+	if len(cut) != len(full)/2 {
+		t.Fatalf("follow-up page = %q, first page = %q", cut, full)
+	}
+	expired := get(t, rt, "https://compute.googleapis.com/compute/v1/projects/p/global/networks")
+	body, _ := io.ReadAll(expired.Body)
+	if expired.StatusCode != http.StatusUnauthorized || !strings.Contains(string(body), `"code":401`) {
+		t.Fatalf("expired request = %d %s", expired.StatusCode, body)
+	}
+}
+
+func TestSlowHonorsContext(t *testing.T) {
+	p, _ := Parse("slow:1h")
+	Enable(p)
+	defer Disable()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/", nil)
+	if _, err := Wrap(&okTransport{}).RoundTrip(req); err == nil {
+		t.Fatal("slow request ignored the context deadline")
+	}
+}
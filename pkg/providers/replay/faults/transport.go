@@ -0,0 +1,316 @@
+package faults
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/cassette"
+)
+
+// Transport applies the active profile in front of Next. Faults are decided
+// per request, so enabling or changing a profile takes effect on providers
+// that are already built.
+type Transport struct {
+	Next http.RoundTripper
+
+	mu       sync.Mutex
+	spec     string
+	requests int
+	// failed holds the fingerprints of requests answered with a one-shot
+	// fault; their retry goes through.
+	failed map[string]bool
+}
+
+// Wrap returns next behind a fault-injecting Transport.
+func Wrap(next http.RoundTripper) *Transport {
+	return &Transport{Next: next}
+}
+
+type injection struct {
+	status     int
+	code       string
+	message    string
+	retryAfter time.Duration
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	profile := Active()
+	if profile.Empty() {
+		return next.RoundTrip(req)
+	}
+
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if profile.delay > 0 {
+		if err := sleep(req.Context(), profile.delay); err != nil {
+			return nil, err
+		}
+	}
+	if fault, ok := t.decide(profile, req, body); ok {
+		return errorResponse(req, fault), nil
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil || !profile.truncate || !followUpPage(req, body) {
+		return resp, err
+	}
+	return truncated(resp)
+}
+
+func (t *Transport) decide(profile Profile, req *http.Request, body []byte) (injection, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.spec != profile.Spec || t.failed == nil {
+		t.spec = profile.Spec
+		t.requests = 0
+		t.failed = map[string]bool{}
+	}
+
+	if region, ok := targetsRegion(profile.regions, req, body); ok {
+		return injection{
+			status:  http.StatusForbidden,
+			code:    "RegionUnavailable",
+			message: fmt.Sprintf("region %s is unavailable for this account (injected fault)", region),
+		}, true
+	}
+
+	fingerprint := cassette.Fingerprint(req, body)
+	if t.failed[fingerprint] {
+		delete(t.failed, fingerprint)
+		return injection{}, false
+	}
+	t.requests++
+	n := t.requests
+
+	switch {
+	case profile.expireAfter > 0 && n > profile.expireAfter:
+		return injection{
+			status:  http.StatusUnauthorized,
+			code:    "ExpiredToken",
+			message: "the security token included in the request is expired (injected fault)",
+		}, true
+	case profile.throttle && n%5 == 0:
+		t.failed[fingerprint] = true
+		return injection{
+			status:     http.StatusTooManyRequests,
+			code:       "Throttling",
+			message:    "rate exceeded (injected fault)",
+			retryAfter: profile.throttleAfter,
+		}, true
+	case profile.flakyEvery > 0 && n%profile.flakyEvery == 0:
+		t.failed[fingerprint] = true
+		return injection{
+			status:  http.StatusServiceUnavailable,
+			code:    "ServiceUnavailable",
+			message: "service is temporarily unavailable (injected fault)",
+		}, true
+	}
+	return injection{}, false
+}
+
+// targetsRegion reports whether req addresses one of regions through its
+// host, path, query, region header or body, covering endpoint-per-region
+// clouds as well as those passing the region as a parameter.
+func targetsRegion(regions []string, req *http.Request, body []byte) (string, bool) {
+	if len(regions) == 0 {
+		return "", false
+	}
+	haystack := strings.ToLower(strings.Join([]string{
+		req.URL.Host,
+		req.URL.Path,
+		req.URL.RawQuery,
+		req.Header.Get("X-TC-Region"),
+		string(body),
+	}, " "))
+	for _, region := range regions {
+		if strings.Contains(haystack, strings.ToLower(region)) {
+			return region, true
+		}
+	}
+	return "", false
+}
+
+// followUpPage reports whether req asks for anything but the first page of
+// a listing: a continuation token, a page number past one or a non-zero
+// offset in the query, form or JSON body.
+func followUpPage(req *http.Request, body []byte) bool {
+	params := req.URL.Query()
+	contentType := req.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for name, values := range form {
+				params[name] = append(params[name], values...)
+			}
+		}
+	case len(body) > 0 && json.Valid(body):
+		var doc map[string]any
+		if json.Unmarshal(body, &doc) == nil {
+			for name, value := range doc {
+				params.Add(name, fmt.Sprint(value))
+			}
+		}
+	}
+	for name := range params {
+		value := strings.TrimSpace(params.Get(name))
+		switch strings.ToLower(strings.TrimPrefix(name, "$")) {
+		case "nexttoken", "marker", "pagetoken", "continuationtoken", "continuation-token",
+			"skiptoken", "cursor", "nextmarker", "start-after":
+			if value != "" {
+				return true
+			}
+		case "page", "pagenumber", "pageno", "pagenum":
+			if n, err := strconv.Atoi(value); err == nil && n > 1 {
+				return true
+			}
+		case "offset":
+			if n, err := strconv.Atoi(value); err == nil && n > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// truncated cuts a successful body in half, as a connection dropped
+// mid-page would.
+func truncated(resp *http.Response) (*http.Response, error) {
+	if resp == nil || resp.StatusCode >= http.StatusBadRequest || resp.Body == nil {
+		return resp, nil
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	data = data[:len(data)/2]
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	resp.ContentLength = int64(len(data))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func errorResponse(req *http.Request, fault injection) *http.Response {
+	contentType, body := errorBody(req, fault)
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	if fault.retryAfter > 0 {
+		header.Set("Retry-After", strconv.Itoa(int(fault.retryAfter/time.Second)))
+	}
+	return &http.Response{
+		StatusCode:    fault.status,
+		Status:        fmt.Sprintf("%d %s", fault.status, http.StatusText(fault.status)),
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+	}
+}
+
+const requestID = "ctk-injected-fault"
+
+type xmlError struct {
+	XMLName   xml.Name `xml:"Error"`
+	Type      string   `xml:"Type,omitempty"`
+	Code      string   `xml:"Code"`
+	Message   string   `xml:"Message"`
+	RequestID string   `xml:"RequestId,omitempty"`
+}
+
+type xmlErrorResponse struct {
+	XMLName   xml.Name `xml:"ErrorResponse"`
+	Error     xmlError `xml:"Error"`
+	RequestID string   `xml:"RequestId"`
+}
+
+// errorBody renders fault in the error envelope the target cloud uses, so
+// each provider's decoder surfaces the injected code and message.
+func errorBody(req *http.Request, fault injection) (string, []byte) {
+	host := strings.ToLower(req.URL.Hostname())
+	contentType := req.Header.Get("Content-Type")
+	var payload any
+	switch {
+	case objectStorage(host):
+		data, _ := xml.Marshal(xmlError{Code: fault.code, Message: fault.message, RequestID: requestID})
+		return "application/xml", data
+	case strings.HasSuffix(host, "amazonaws.com") && !strings.Contains(contentType, "json"):
+		data, _ := xml.Marshal(xmlErrorResponse{
+			Error:     xmlError{Type: "Sender", Code: fault.code, Message: fault.message},
+			RequestID: requestID,
+		})
+		return "text/xml", data
+	case strings.HasSuffix(host, "googleapis.com"), strings.Contains(host, "jdcloud"):
+		payload = map[string]any{"error": map[string]any{
+			"code":    fault.status,
+			"status":  strings.ToUpper(strings.ReplaceAll(http.StatusText(fault.status), " ", "_")),
+			"message": fault.message,
+		}}
+	case strings.Contains(host, "microsoftonline"):
+		payload = map[string]any{"error": fault.code, "error_description": fault.message}
+	case strings.Contains(host, "azure"):
+		payload = map[string]any{"error": map[string]any{"code": fault.code, "message": fault.message}}
+	case strings.Contains(host, "ucloud"):
+		payload = map[string]any{"RetCode": fault.status, "Message": fault.message, "Action": "FaultResponse"}
+	default:
+		// Alibaba, Tencent, Volcengine, Huawei and AWS JSON protocols each
+		// read one of these envelopes and ignore the others.
+		envelope := map[string]any{"Code": fault.code, "Message": fault.message}
+		payload = map[string]any{
+			"Code":             fault.code,
+			"Message":          fault.message,
+			"RequestId":        requestID,
+			"__type":           fault.code,
+			"Response":         map[string]any{"Error": envelope, "RequestId": requestID},
+			"ResponseMetadata": map[string]any{"RequestId": requestID, "Error": envelope},
+			"error_code":       fault.code,
+			"error_msg":        fault.message,
+		}
+	}
+	data, _ := json.Marshal(payload)
+	return "application/json", data
+}
+
+func objectStorage(host string) bool {
+	for _, marker := range []string{"s3.", "s3-", ".oss-", "oss-", ".cos.", ".obs.", "tos-", ".blob.core."} {
+		if strings.Contains(host, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -57,9 +57,12 @@ var providers = map[string]providerMeta{
 			"event-check",
 			"rds-account-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
 			"role-binding-check",
 			"bucket-acl-check",
 			"iam-credential-check",
+			"object-access-check",
+			"iam-posture",
 		},
 	},
 	"volcengine": {
@@ -72,6 +75,7 @@ var providers = map[string]providerMeta{
 			"iam-user-check",
 			"bucket-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
 			"role-binding-check",
 			"bucket-acl-check",
 			"iam-credential-check",
@@ -89,11 +93,14 @@ var providers = map[string]providerMeta{
 			"iam-user-check",
 			"bucket-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
 			"role-binding-check",
 			"bucket-acl-check",
 			"event-check",
 			"iam-credential-check",
 			"rds-account-check",
+			"object-access-check",
+			"iam-posture",
 		},
 	},
 	"aws": {
@@ -108,9 +115,12 @@ var providers = map[string]providerMeta{
 			"role-binding-check",
 			"bucket-acl-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
 			"event-check",
 			"iam-credential-check",
 			"rds-account-check",
+			"object-access-check",
+			"iam-posture",
 		},
 	},
 	"huawei": {
@@ -128,6 +138,9 @@ var providers = map[string]providerMeta{
 			"iam-credential-check",
 			"rds-account-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
+			"object-access-check",
+			"iam-posture",
 		},
 	},
 	"azure": {
@@ -151,6 +164,9 @@ var providers = map[string]providerMeta{
 			"rds-account-check",
 			"iam-user-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
+			"bucket-check",
+			"iam-posture",
 		},
 	},
 	"gcp": {
@@ -170,6 +186,8 @@ var providers = map[string]providerMeta{
 			"bucket-check",
 			"bucket-acl-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
+			"object-access-check",
 		},
 	},
 	"jdcloud": {
@@ -187,6 +205,7 @@ var providers = map[string]providerMeta{
 			"rds-account-check",
 			"iam-credential-check",
 			"instance-cmd-check",
+			"instance-cmd-batch",
 		},
 	},
 	"ucloud": {
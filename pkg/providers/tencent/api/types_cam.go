@@ -208,9 +208,11 @@ type GetUserRequest struct {
 
 type GetUserResponse struct {
 	Response struct {
-		Uin       *uint64 `json:"Uin"`
-		Name      *string `json:"Name"`
-		RequestID string  `json:"RequestId"`
+		Uin               *uint64 `json:"Uin"`
+		Name              *string `json:"Name"`
+		ConsoleLogin      *uint64 `json:"ConsoleLogin"`
+		RecentlyLoginTime *string `json:"RecentlyLoginTime"`
+		RequestID         string  `json:"RequestId"`
 	} `json:"Response"`
 }
 
@@ -471,6 +473,61 @@ func (c *Client) DeleteAccessKey(ctx context.Context, targetUin uint64, accessKe
 	return resp, err
 }
 
+type GetSecurityLastUsedRequest struct {
+	SecretIDList []string `json:"SecretIdList"`
+}
+
+type GetSecurityLastUsedResponse struct {
+	Response struct {
+		SecretIDLastUsedRows []SecretIDLastUsed `json:"SecretIdLastUsedRows"`
+		RequestID            string             `json:"RequestId"`
+	} `json:"Response"`
+}
+
+// SecretIDLastUsed reports the last use of one access key. LastUsedDate is
+// a day; LastSecretUsedDate is a Unix timestamp and 0 when never used.
+type SecretIDLastUsed struct {
+	SecretID           *string `json:"SecretId"`
+	LastUsedDate       *string `json:"LastUsedDate"`
+	LastSecretUsedDate *uint64 `json:"LastSecretUsedDate"`
+}
+
+func (c *Client) GetSecurityLastUsed(ctx context.Context, secretIDs []string) (GetSecurityLastUsedResponse, error) {
+	var resp GetSecurityLastUsedResponse
+	err := c.DoJSON(ctx, "cam", camVersion, "GetSecurityLastUsed", "", GetSecurityLastUsedRequest{SecretIDList: secretIDs}, &resp)
+	return resp, err
+}
+
+type DescribeSafeAuthFlagCollRequest struct {
+	SubUin *uint64 `json:"SubUin,omitempty"`
+}
+
+type DescribeSafeAuthFlagCollResponse struct {
+	Response struct {
+		LoginFlag  *LoginActionFlag `json:"LoginFlag"`
+		ActionFlag *LoginActionFlag `json:"ActionFlag"`
+		RequestID  string           `json:"RequestId"`
+	} `json:"Response"`
+}
+
+// LoginActionFlag lists the verification factors required at login or for
+// sensitive actions; each is 1 when enabled.
+type LoginActionFlag struct {
+	Phone    *uint64 `json:"Phone"`
+	Token    *uint64 `json:"Token"`
+	Stoken   *uint64 `json:"Stoken"`
+	Wechat   *uint64 `json:"Wechat"`
+	Custom   *uint64 `json:"Custom"`
+	Mail     *uint64 `json:"Mail"`
+	U2FToken *uint64 `json:"U2FToken"`
+}
+
+func (c *Client) DescribeSafeAuthFlagColl(ctx context.Context, subUin uint64) (DescribeSafeAuthFlagCollResponse, error) {
+	var resp DescribeSafeAuthFlagCollResponse
+	err := c.DoJSON(ctx, "cam", camVersion, "DescribeSafeAuthFlagColl", "", DescribeSafeAuthFlagCollRequest{SubUin: uint64Ptr(subUin)}, &resp)
+	return resp, err
+}
+
 func stringPtr(v string) *string {
 	return &v
 }
@@ -79,8 +79,9 @@ type CloudAuditEvent struct {
 // caller behaviour mirrors the existing alibaba SAS dump: surface the most
 // recent operations so a CSPM detection can be cross-referenced. StartTime /
 // EndTime are unix seconds; pass 0 to leave them unset and fall back to the
-// CloudAudit default lookback window.
-func (c *Client) LookUpEvents(ctx context.Context, region string, startTime, endTime int64, maxResults int64, nextToken, accessKeyID string) (LookUpEventsResponse, error) {
+// CloudAudit default lookback window. attributes are sent after the access
+// key one.
+func (c *Client) LookUpEvents(ctx context.Context, region string, startTime, endTime int64, maxResults int64, nextToken, accessKeyID string, attributes ...LookupAttribute) (LookUpEventsResponse, error) {
 	req := LookUpEventsRequest{}
 	if startTime > 0 {
 		ts := startTime
@@ -104,6 +105,7 @@ func (c *Client) LookUpEvents(ctx context.Context, region string, startTime, end
 			},
 		}
 	}
+	req.LookupAttributes = append(req.LookupAttributes, attributes...)
 	var resp LookUpEventsResponse
 	err := c.DoJSON(ctx, "cloudaudit", cloudAuditVersion, "LookUpEvents", region, req, &resp)
 	return resp, err
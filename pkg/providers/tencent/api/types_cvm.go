@@ -50,6 +50,13 @@ type CVMInstanceInfo struct {
 	PublicIPAddresses  []string `json:"PublicIpAddresses"`
 	PrivateIPAddresses []string `json:"PrivateIpAddresses"`
 	OSName             *string  `json:"OsName"`
+	SecurityGroupIDs   []string `json:"SecurityGroupIds"`
+	Tags               []CVMTag `json:"Tags"`
+}
+
+type CVMTag struct {
+	Key   *string `json:"Key"`
+	Value *string `json:"Value"`
 }
 
 func (c *Client) DescribeCVMInstances(ctx context.Context, region string, offset, limit int64) (DescribeCVMInstancesResponse, error) {
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"strconv"
+)
+
+const (
+	vpcVersion                = "2017-03-12"
+	defaultSecurityGroupLimit = 100
+)
+
+// DescribeSecurityGroupsRequest pages with string Offset/Limit; the VPC API
+// predates the integer paging used by CVM.
+type DescribeSecurityGroupsRequest struct {
+	Offset *string `json:"Offset,omitempty"`
+	Limit  *string `json:"Limit,omitempty"`
+}
+
+type DescribeSecurityGroupsResponse struct {
+	Response struct {
+		TotalCount       *int64              `json:"TotalCount"`
+		SecurityGroupSet []SecurityGroupInfo `json:"SecurityGroupSet"`
+		RequestID        string              `json:"RequestId"`
+	} `json:"Response"`
+}
+
+type SecurityGroupInfo struct {
+	SecurityGroupID   *string `json:"SecurityGroupId"`
+	SecurityGroupName *string `json:"SecurityGroupName"`
+	SecurityGroupDesc *string `json:"SecurityGroupDesc"`
+}
+
+func (c *Client) DescribeSecurityGroups(ctx context.Context, region string, offset, limit int64) (DescribeSecurityGroupsResponse, error) {
+	if limit <= 0 {
+		limit = defaultSecurityGroupLimit
+	}
+	var resp DescribeSecurityGroupsResponse
+	err := c.DoJSON(
+		ctx,
+		"vpc",
+		vpcVersion,
+		"DescribeSecurityGroups",
+		normalizeRegion(region),
+		DescribeSecurityGroupsRequest{
+			Offset: stringPtr(strconv.FormatInt(offset, 10)),
+			Limit:  stringPtr(strconv.FormatInt(limit, 10)),
+		},
+		&resp,
+	)
+	return resp, err
+}
+
+type DescribeSecurityGroupPoliciesRequest struct {
+	SecurityGroupID *string `json:"SecurityGroupId"`
+}
+
+type DescribeSecurityGroupPoliciesResponse struct {
+	Response struct {
+		SecurityGroupPolicySet SecurityGroupPolicySet `json:"SecurityGroupPolicySet"`
+		RequestID              string                 `json:"RequestId"`
+	} `json:"Response"`
+}
+
+type SecurityGroupPolicySet struct {
+	Ingress []SecurityGroupPolicy `json:"Ingress"`
+	Egress  []SecurityGroupPolicy `json:"Egress"`
+}
+
+// SecurityGroupPolicy is one rule. Port is "ALL", a single port, a range
+// ("8000-8010") or a comma list; the peer is whichever of CidrBlock,
+// Ipv6CidrBlock, SecurityGroupId or AddressTemplate is set.
+type SecurityGroupPolicy struct {
+	PolicyIndex       *int64                        `json:"PolicyIndex"`
+	Protocol          *string                       `json:"Protocol"`
+	Port              *string                       `json:"Port"`
+	CidrBlock         *string                       `json:"CidrBlock"`
+	Ipv6CidrBlock     *string                       `json:"Ipv6CidrBlock"`
+	SecurityGroupID   *string                       `json:"SecurityGroupId"`
+	AddressTemplate   *AddressTemplateSpecification `json:"AddressTemplate"`
+	Action            *string                       `json:"Action"`
+	PolicyDescription *string                       `json:"PolicyDescription"`
+}
+
+type AddressTemplateSpecification struct {
+	AddressID      *string `json:"AddressId"`
+	AddressGroupID *string `json:"AddressGroupId"`
+}
+
+func (c *Client) DescribeSecurityGroupPolicies(ctx context.Context, region, groupID string) (DescribeSecurityGroupPoliciesResponse, error) {
+	var resp DescribeSecurityGroupPoliciesResponse
+	err := c.DoJSON(
+		ctx,
+		"vpc",
+		vpcVersion,
+		"DescribeSecurityGroupPolicies",
+		normalizeRegion(region),
+		DescribeSecurityGroupPoliciesRequest{SecurityGroupID: stringPtr(groupID)},
+		&resp,
+	)
+	return resp, err
+}
@@ -2,11 +2,11 @@ package billing
 
 import (
 	"context"
-	"fmt"
+	"strconv"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/auth"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -23,24 +23,30 @@ func (d *Driver) SetClientOptions(opts ...api.Option) {
 	d.clientOptions = append([]api.Option(nil), opts...)
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+// QueryAccountBalance returns the available cash balance. The API reports
+// cents.
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 	resp, err := d.newClient().DescribeAccountBalance(ctx, d.Region)
-	if err == nil {
-		var realBalance float64
-		switch {
-		case resp.Response.RealBalance != nil:
-			realBalance = *resp.Response.RealBalance
-		case resp.Response.Balance != nil:
-			realBalance = float64(*resp.Response.Balance)
-		default:
-			return
-		}
-		cash := realBalance / 100
-		logger.Warning(fmt.Sprintf("Available cash amount: %v", cash))
+	if err != nil {
+		return nil
 	}
+	var realBalance float64
+	switch {
+	case resp.Response.RealBalance != nil:
+		realBalance = *resp.Response.RealBalance
+	case resp.Response.Balance != nil:
+		realBalance = float64(*resp.Response.Balance)
+	default:
+		return nil
+	}
+	return []schema.Balance{{
+		Kind:     schema.BalanceCredit,
+		Amount:   strconv.FormatFloat(realBalance/100, 'f', 2, 64),
+		Currency: "CNY",
+	}}
 }
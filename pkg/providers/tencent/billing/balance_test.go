@@ -1,26 +1,18 @@
 package billing
 
 import (
-	"bytes"
 	"context"
 	"net/http"
 	"net/http/httptest"
-	"strings"
 	"testing"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/auth"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
-func TestQueryAccountBalanceLogsAvailableCashAmount(t *testing.T) {
-	original := &bytes.Buffer{}
-	logger.SetOutput(original)
-	t.Cleanup(func() {
-		logger.SetOutput(nil)
-	})
-
+func TestQueryAccountBalanceReturnsAvailableCashAmount(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if got := r.Header.Get("X-TC-Action"); got != "DescribeAccountBalance" {
 			t.Fatalf("unexpected action: %s", got)
@@ -42,9 +34,10 @@ func TestQueryAccountBalanceLogsAvailableCashAmount(t *testing.T) {
 		},
 	}
 
-	driver.QueryAccountBalance(context.Background())
+	balances := driver.QueryAccountBalance(context.Background())
 
-	if got := original.String(); !strings.Contains(got, "Available cash amount: 123.45") {
-		t.Fatalf("unexpected logger output: %s", got)
+	want := schema.Balance{Kind: schema.BalanceCredit, Amount: "123.45", Currency: "CNY"}
+	if len(balances) != 1 || balances[0] != want {
+		t.Fatalf("unexpected balances: %+v", balances)
 	}
 }
@@ -14,9 +14,9 @@ const accountHost = "%"
 
 // CreateAccount provisions a CDB account on the named instance using the
 // `rds-account-check` config. The username/password come from
-// `env.Active().RDSAccount` (form `username:password`).
+// `env.From(ctx).RDSAccount` (form `username:password`).
 func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.DatabaseActionResult, error) {
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -35,7 +35,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 // DeleteAccount removes the CDB account named by the `rds-account-check`
 // config from the supplied instance.
 func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.DatabaseActionResult, error) {
-	user, _, err := parseRDSAccount()
+	user, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -50,8 +50,8 @@ func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.D
 	}, nil
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -47,11 +48,25 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 	if err != nil {
 		return nil, err
 	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTime, endTime))
+}
+
+// QueryEvents returns CloudAudit events matching q. An API name goes to
+// LookUpEvents as an EventName attribute; everything else is matched on the
+// returned pages. An open window falls back to the last defaultLookback.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	startTime, endTime := lookupWindow(q, d.now())
+	var attributes []api.LookupAttribute
+	if apiNamePattern.MatchString(q.Name) {
+		key, value := "EventName", q.Name
+		attributes = append(attributes, api.LookupAttribute{AttributeKey: &key, AttributeValue: &value})
+	}
+	rest := q.WithoutWindow()
 	client := d.newClient()
 	out := make([]schema.Event, 0)
 	nextToken := ""
 	for page := 0; page < maxPages; page++ {
-		resp, err := client.LookUpEvents(ctx, defaultLookupRegion, startTime, endTime, defaultMaxResults, nextToken, d.Credential.SecretID)
+		resp, err := client.LookUpEvents(ctx, defaultLookupRegion, startTime, endTime, defaultMaxResults, nextToken, d.Credential.SecretID, attributes...)
 		if err != nil {
 			return out, err
 		}
@@ -59,16 +74,20 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 			if len(out) >= defaultEventLimit {
 				break
 			}
-			out = append(out, schema.Event{
-				Id:       derefString(ev.EventID),
-				Name:     derefString(ev.EventNameCn),
-				Affected: derefString(ev.ResourceName),
-				API:      derefString(ev.EventName),
-				Status:   statusLabel(derefUint64(ev.Status)),
-				SourceIp: derefString(ev.SourceIPAddress),
+			event := schema.Event{
+				Id:        derefString(ev.EventID),
+				Name:      derefString(ev.EventNameCn),
+				Affected:  derefString(ev.ResourceName),
+				API:       derefString(ev.EventName),
+				Status:    statusLabel(derefUint64(ev.Status)),
+				SourceIp:  derefString(ev.SourceIPAddress),
+				Principal: derefString(ev.Username),
 				// AccessKey: derefString(ev.SecretID),
 				Time: formatEventTime(derefString(ev.EventTime)),
-			})
+			}
+			if rest.Match(event) {
+				out = append(out, event)
+			}
 		}
 		if len(out) >= defaultEventLimit {
 			break
@@ -100,6 +119,24 @@ func (d *Driver) now() time.Time {
 	return time.Now().UTC()
 }
 
+// apiNamePattern matches CloudAudit API names such as RunInstances, as
+// opposed to the localized EventNameCn the Name filter also accepts.
+var apiNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
+// lookupWindow fills the open bounds of q's window from now and
+// defaultLookback, since LookUpEvents wants both.
+func lookupWindow(q schema.EventQuery, now time.Time) (int64, int64) {
+	until := q.Until
+	if until.IsZero() {
+		until = now.UTC()
+	}
+	since := q.Since
+	if since.IsZero() {
+		since = until.Add(-defaultLookback)
+	}
+	return since.Unix(), until.Unix()
+}
+
 func parseTimeWindow(args string, now time.Time) (int64, int64, error) {
 	args = strings.TrimSpace(args)
 	if args == "" || args == "all" {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/auth"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 func newTestDriver(t *testing.T, baseURL string) *Driver {
@@ -145,3 +146,32 @@ func TestStatusLabel(t *testing.T) {
 		}
 	}
 }
+
+func TestQueryEventsSendsEventNameAndDefaultsWindow(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("read body: %v", err)
+		}
+		for _, want := range []string{
+			`{"AttributeKey":"AccessKeyId","AttributeValue":"AKIDCURRENT"}`,
+			`{"AttributeKey":"EventName","AttributeValue":"RunInstances"}`,
+			`"EndTime":1776458501`,
+		} {
+			if !strings.Contains(string(body), want) {
+				t.Fatalf("request lacks %s: %s", want, body)
+			}
+		}
+		_, _ = w.Write([]byte(`{"Response":{"ListOver":true,"Events":[{"EventId":"e1","EventName":"RunInstances","Username":"alice"},{"EventId":"e2","EventName":"RunInstances","Username":"bob"}],"RequestId":"r1"}}`))
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(t, server.URL)
+	got, err := driver.QueryEvents(context.Background(), schema.EventQuery{Name: "RunInstances", Principal: "alice"})
+	if err != nil {
+		t.Fatalf("QueryEvents: %v", err)
+	}
+	if len(got) != 1 || got[0].Id != "e1" {
+		t.Fatalf("expected only alice's event, got %+v", got)
+	}
+}
@@ -3,11 +3,13 @@ package cos
 import (
 	"context"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
@@ -75,6 +77,60 @@ func (c *Client) GetBucketACL(ctx context.Context, bucket, region string) (strin
 	return CollapseGrants(out), nil
 }
 
+// GetBucketPolicy returns bucket's policy document, or nil when the bucket
+// has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket, region string) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := c.credential.Validate(); err != nil {
+		return nil, err
+	}
+	bucket = strings.TrimSpace(bucket)
+	region = strings.TrimSpace(region)
+	if bucket == "" || region == "" || region == "all" {
+		return nil, fmt.Errorf("tencent cos client: empty bucket or region")
+	}
+	u, err := c.bucketURL(bucket, region)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("policy", "")
+	u.RawQuery = q.Encode()
+
+	httpResp, err := c.retryPolicy.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		if err := Sign(req, c.credential, c.now().UTC()); err != nil {
+			return nil, err
+		}
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if httpResp == nil {
+		return nil, fmt.Errorf("tencent cos client: empty response")
+	}
+	defer httpclient.CloseResponse(httpResp)
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read tencent cos response: %w", err)
+	}
+	if err := decodeError(httpResp, body); err != nil {
+		// COS answers a bucket without a policy with a 404 policy error.
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Code != "NoSuchBucket" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return body, nil
+}
+
 // PutBucketACL sets the canned ACL on bucket via the `x-cos-acl` header.
 func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) error {
 	if ctx == nil {
@@ -125,7 +181,8 @@ func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) e
 	return decodeError(httpResp, body)
 }
 
-// AuditBucketACL enumerates buckets and returns their canned ACL summary.
+// AuditBucketACL enumerates buckets and returns their canned ACL summary
+// and effective exposure, which also weighs the bucket policy.
 func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.BucketACLEntry, error) {
 	storages, err := d.GetBuckets(ctx)
 	if err != nil {
@@ -133,18 +190,24 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 	}
 	bucket = strings.TrimSpace(bucket)
 	out := make([]schema.BucketACLEntry, 0, len(storages))
+	client := d.client()
 	for _, s := range storages {
 		if bucket != "" && s.BucketName != bucket {
 			continue
 		}
-		acl, err := d.client().GetBucketACL(ctx, s.BucketName, s.Region)
+		acl, err := client.GetBucketACL(ctx, s.BucketName, s.Region)
 		if err != nil {
 			return out, fmt.Errorf("get acl for %s: %w", s.BucketName, err)
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     acl,
-		})
+		}
+		var verdict exposure.Verdict
+		verdict.Add("acl", exposure.ACL(acl))
+		verdict.AddPolicy(client.GetBucketPolicy(ctx, s.BucketName, s.Region))
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
@@ -121,7 +121,7 @@ func (c *Client) ListBuckets(ctx context.Context) (*ListBucketsResponse, error)
 	return &out, nil
 }
 
-func (c *Client) ListObjects(ctx context.Context, bucket, region, marker string, maxKeys int) (ListObjectsResponse, error) {
+func (c *Client) ListObjects(ctx context.Context, bucket, region, prefix, marker string, maxKeys int) (ListObjectsResponse, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -146,6 +146,9 @@ func (c *Client) ListObjects(ctx context.Context, bucket, region, marker string,
 	}
 	query := u.Query()
 	query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if marker = strings.TrimSpace(marker); marker != "" {
 		query.Set("marker", marker)
 	}
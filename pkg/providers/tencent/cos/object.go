@@ -9,37 +9,56 @@ import (
 	"github.com/404tk/cloudtoolkit/utils/processbar"
 )
 
+// ListObjects returns every object of each bucket.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's markers and hands the objects matching
+// q to visit.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	results := []schema.BucketResult{}
 	for bucket, region := range buckets {
-		resp, err := d.listObjectsPage(ctx, bucket, region, "", 100)
-		if err != nil {
-			return nil, fmt.Errorf("list objects in %s: %w", bucket, err)
-		}
-
-		objects := make([]schema.BucketObject, 0, len(resp.Objects))
-		for _, obj := range resp.Objects {
-			objects = append(objects, schema.BucketObject{
-				BucketName:   bucket,
-				Key:          obj.Key,
-				Size:         obj.Size,
-				LastModified: obj.LastModified,
-				StorageClass: obj.StorageClass,
-			})
+		walk := schema.NewObjectWalk(q, visit)
+		marker := ""
+	pages:
+		for {
+			resp, err := d.listObjectsPage(ctx, bucket, region, q.Prefix, marker, 1000)
+			if err != nil {
+				return results, fmt.Errorf("list objects in %s: %w", bucket, err)
+			}
+			for _, obj := range resp.Objects {
+				more, err := walk.Add(schema.BucketObject{
+					BucketName:   bucket,
+					Key:          obj.Key,
+					Size:         obj.Size,
+					LastModified: obj.LastModified,
+					StorageClass: obj.StorageClass,
+				})
+				if err != nil {
+					return results, err
+				}
+				if !more {
+					break pages
+				}
+			}
+			if !resp.IsTruncated {
+				break
+			}
+			if marker = nextMarker(resp); marker == "" {
+				return results, fmt.Errorf("tencent cos: truncated response for bucket %s missing continuation marker", bucket)
+			}
+			if ctx.Err() != nil {
+				break
+			}
 		}
-
-		result := schema.BucketResult{
-			Action:      "list",
-			BucketName:  bucket,
-			ObjectCount: int64(len(objects)),
-			Objects:     objects,
-			Message:     fmt.Sprintf("%d objects found", len(objects)),
-		}
-		results = append(results, result)
+		results = append(results, walk.Result(bucket))
 
 		select {
 		case <-ctx.Done():
-			return results, nil
+			return results, ctx.Err()
 		default:
 		}
 	}
@@ -68,19 +87,19 @@ func (d *Driver) TotalObjects(ctx context.Context, buckets map[string]string) ([
 	return results, nil
 }
 
-func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, marker string, maxKeys int) (ListObjectsResponse, error) {
+func (d *Driver) listObjectsPage(ctx context.Context, bucket, region, prefix, marker string, maxKeys int) (ListObjectsResponse, error) {
 	client := d.client()
 	if client == nil {
 		return ListObjectsResponse{}, fmt.Errorf("tencent cos: nil client")
 	}
-	return client.ListObjects(ctx, bucket, normalizeRegion(region), marker, maxKeys)
+	return client.ListObjects(ctx, bucket, normalizeRegion(region), prefix, marker, maxKeys)
 }
 
 func (d *Driver) countBucketObjects(ctx context.Context, bucket, region string, tracker *processbar.CountTracker) (int, error) {
 	count := 0
 	marker := ""
 	for {
-		resp, err := d.listObjectsPage(ctx, bucket, region, marker, 1000)
+		resp, err := d.listObjectsPage(ctx, bucket, region, "", marker, 1000)
 		if err != nil {
 			return 0, err
 		}
@@ -0,0 +1,178 @@
+package cos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// presignExpiry bounds the pre-signed URL the presign action fetches; the
+// URL is used once, immediately.
+const presignExpiry = 5 * time.Minute
+
+// ObjectResponse is the result of a COS object request. RequestID is the
+// x-cos-request-id that COS access logs and CloudAudit data events record.
+type ObjectResponse struct {
+	StatusCode int
+	RequestID  string
+	Body       []byte
+}
+
+// PutObject uploads body to bucket/key.
+func (c *Client) PutObject(ctx context.Context, bucket, region, key string, body []byte, contentType string) (ObjectResponse, error) {
+	u, err := c.objectURL(bucket, region, key)
+	if err != nil {
+		return ObjectResponse{}, err
+	}
+	return c.doObject(ctx, http.MethodPut, u, body, contentType, true)
+}
+
+// GetObject downloads bucket/key.
+func (c *Client) GetObject(ctx context.Context, bucket, region, key string) (ObjectResponse, error) {
+	u, err := c.objectURL(bucket, region, key)
+	if err != nil {
+		return ObjectResponse{}, err
+	}
+	return c.doObject(ctx, http.MethodGet, u, nil, "", true)
+}
+
+// DeleteObject removes bucket/key.
+func (c *Client) DeleteObject(ctx context.Context, bucket, region, key string) (ObjectResponse, error) {
+	u, err := c.objectURL(bucket, region, key)
+	if err != nil {
+		return ObjectResponse{}, err
+	}
+	return c.doObject(ctx, http.MethodDelete, u, nil, "", true)
+}
+
+// PresignGetObject returns a URL that downloads bucket/key without
+// credentials until expires elapses.
+func (c *Client) PresignGetObject(bucket, region, key string, expires time.Duration) (string, error) {
+	u, err := c.objectURL(bucket, region, key)
+	if err != nil {
+		return "", err
+	}
+	if err := Presign(u, http.MethodGet, c.credential, c.now(), expires); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// GetPresignedObject downloads an object through a PresignGetObject URL.
+func (c *Client) GetPresignedObject(ctx context.Context, rawURL string) (ObjectResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ObjectResponse{}, fmt.Errorf("tencent cos client: invalid presigned url: %w", err)
+	}
+	return c.doObject(ctx, http.MethodGet, u, nil, "", false)
+}
+
+func (c *Client) doObject(ctx context.Context, method string, u *url.URL, body []byte, contentType string, sign bool) (ObjectResponse, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if sign {
+		if err := c.credential.Validate(); err != nil {
+			return ObjectResponse{}, err
+		}
+	}
+	httpResp, err := c.retryPolicy.Do(ctx, true, func() (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		if sign {
+			if err := Sign(req, c.credential, c.now().UTC()); err != nil {
+				return nil, err
+			}
+		}
+		return c.httpClient.Do(req)
+	})
+	if err != nil {
+		return ObjectResponse{}, err
+	}
+	if httpResp == nil {
+		return ObjectResponse{}, fmt.Errorf("tencent cos client: empty response")
+	}
+	defer httpclient.CloseResponse(httpResp)
+
+	out := ObjectResponse{
+		StatusCode: httpResp.StatusCode,
+		RequestID:  headerValueIgnoreCase(httpResp.Header, "X-Cos-Request-Id"),
+	}
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return out, fmt.Errorf("read tencent cos response: %w", err)
+	}
+	if err := decodeError(httpResp, respBody); err != nil {
+		return out, err
+	}
+	out.Body = respBody
+	return out, nil
+}
+
+func (c *Client) objectURL(bucket, region, key string) (*url.URL, error) {
+	bucket = strings.TrimSpace(bucket)
+	region = strings.TrimSpace(region)
+	key = strings.TrimPrefix(key, "/")
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("tencent cos client: empty bucket or key")
+	}
+	if region == "" || region == "all" {
+		return nil, fmt.Errorf("tencent cos client: empty region")
+	}
+	u, err := c.bucketURL(bucket, region)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = "/" + key
+	return u, nil
+}
+
+// AccessObject performs one object-access-check request on bucket/key. The
+// request ID of a failed call is kept so a denied access can be traced too.
+func (d *Driver) AccessObject(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	result := schema.ObjectAccessResult{Action: action, Bucket: bucket, Key: key}
+	region, err := d.bucketRegion(ctx, bucket)
+	if err != nil {
+		return result, err
+	}
+	client := d.client()
+
+	var out ObjectResponse
+	switch action {
+	case "put":
+		out, err = client.PutObject(ctx, bucket, region, key, body, "text/plain")
+	case "get":
+		out, err = client.GetObject(ctx, bucket, region, key)
+	case "presign":
+		result.URL, err = client.PresignGetObject(bucket, region, key, presignExpiry)
+		if err == nil {
+			out, err = client.GetPresignedObject(ctx, result.URL)
+		}
+	case "delete":
+		out, err = client.DeleteObject(ctx, bucket, region, key)
+	default:
+		return result, fmt.Errorf("tencent cos: unsupported object action %q", action)
+	}
+	result.StatusCode = out.StatusCode
+	result.RequestID = out.RequestID
+	result.Body = out.Body
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RequestID != "" {
+		result.RequestID = apiErr.RequestID
+	}
+	return result, err
+}
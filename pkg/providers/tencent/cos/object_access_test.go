@@ -0,0 +1,101 @@
+package cos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const objectAccessBuckets = `<ListAllMyBucketsResult>
+	<Buckets>
+		<Bucket>
+			<Name>ctk-demo-1250000000</Name>
+			<Location>ap-guangzhou</Location>
+		</Bucket>
+	</Buckets>
+</ListAllMyBucketsResult>`
+
+func TestAccessObjectRoundTripsCanary(t *testing.T) {
+	t.Parallel()
+
+	stored := map[string]string{}
+	driver := &Driver{Client: newACLClient(t, func(req *http.Request) (*http.Response, error) {
+		respond := func(status int, body string) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: status,
+				Header:     http.Header{"X-Cos-Request-Id": []string{"req-" + strings.ToLower(req.Method)}},
+				Body:       io.NopCloser(strings.NewReader(body)),
+				Request:    req,
+			}, nil
+		}
+		if req.URL.Host == "service.cos.myqcloud.com" {
+			return respond(http.StatusOK, objectAccessBuckets)
+		}
+		if req.URL.Host != "ctk-demo-1250000000.cos.ap-guangzhou.myqcloud.com" {
+			t.Fatalf("unexpected host: %s", req.URL.Host)
+		}
+		presigned := req.URL.Query().Get("q-signature") != ""
+		if signed := strings.Contains(req.Header.Get("Authorization"), "q-signature="); signed == presigned {
+			t.Fatalf("%s %s: authorization=%q presigned=%v", req.Method, req.URL.Path, req.Header.Get("Authorization"), presigned)
+		}
+		switch req.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(req.Body)
+			stored[req.URL.Path] = string(data)
+		case http.MethodGet:
+			return respond(http.StatusOK, stored[req.URL.Path])
+		case http.MethodDelete:
+			delete(stored, req.URL.Path)
+			return respond(http.StatusNoContent, "")
+		}
+		return respond(http.StatusOK, "")
+	})}
+
+	ctx := context.Background()
+	const bucket, key = "ctk-demo-1250000000", "ctk-canary/20260422T120000Z-0a1b2c3d.txt"
+	put, err := driver.AccessObject(ctx, "put", bucket, key, []byte("canary"))
+	if err != nil || put.RequestID != "req-put" {
+		t.Fatalf("put = %+v, %v", put, err)
+	}
+	get, err := driver.AccessObject(ctx, "get", bucket, key, nil)
+	if err != nil || string(get.Body) != "canary" {
+		t.Fatalf("get = %+v, %v", get, err)
+	}
+	presign, err := driver.AccessObject(ctx, "presign", bucket, key, nil)
+	if err != nil || string(presign.Body) != "canary" || !strings.Contains(presign.URL, "q-sign-time=") {
+		t.Fatalf("presign = %+v, %v", presign, err)
+	}
+	del, err := driver.AccessObject(ctx, "delete", bucket, key, nil)
+	if err != nil || del.StatusCode != http.StatusNoContent {
+		t.Fatalf("delete = %+v, %v", del, err)
+	}
+	if len(stored) != 0 {
+		t.Fatalf("canary left behind: %v", stored)
+	}
+}
+
+func TestAccessObjectKeepsRequestIDOfDeniedCall(t *testing.T) {
+	t.Parallel()
+
+	driver := &Driver{Client: newACLClient(t, func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host == "service.cos.myqcloud.com" {
+			return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(objectAccessBuckets)), Request: req}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusForbidden,
+			Header:     http.Header{"Content-Type": []string{"application/xml"}},
+			Body: io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>AccessDenied</Code><Message>denied</Message><RequestId>req-denied</RequestId></Error>`)),
+			Request: req,
+		}, nil
+	})}
+	got, err := driver.AccessObject(context.Background(), "get", "ctk-demo-1250000000", "ctk-canary/x.txt", nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got.RequestID != "req-denied" || got.StatusCode != http.StatusForbidden {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
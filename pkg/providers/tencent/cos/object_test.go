@@ -63,7 +63,7 @@ func TestClientListObjectsUsesBucketScopedEndpoint(t *testing.T) {
 		WithClock(func() time.Time { return time.Date(2026, 4, 19, 12, 0, 0, 0, time.UTC) }),
 	)
 
-	resp, err := client.ListObjects(context.Background(), "examplebucket-1250000000", "ap-guangzhou", "", 100)
+	resp, err := client.ListObjects(context.Background(), "examplebucket-1250000000", "ap-guangzhou", "", "", 100)
 	if err != nil {
 		t.Fatalf("ListObjects() error = %v", err)
 	}
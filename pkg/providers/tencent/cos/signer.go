@@ -82,6 +82,41 @@ func Sign(req *http.Request, cred auth.Credential, now time.Time) error {
 	return addAuthorizationHeader(req, cred, window)
 }
 
+// Presign adds COS query-string authentication to u, valid from now for
+// expires: the q-sign-* parameters of the Authorization header, plus
+// x-cos-security-token for temporary credentials. Only the host is signed,
+// so the URL works from any client.
+func Presign(u *url.URL, method string, cred auth.Credential, now time.Time, expires time.Duration) error {
+	if u == nil {
+		return fmt.Errorf("tencent cos signer: nil request url")
+	}
+	if err := cred.Validate(); err != nil {
+		return err
+	}
+	now = now.UTC()
+	window := &authTime{
+		SignStartTime: now,
+		SignEndTime:   now.Add(expires),
+		KeyStartTime:  now,
+		KeyEndTime:    now.Add(expires),
+	}
+	req := &http.Request{Method: method, URL: u, Header: make(http.Header)}
+	authorization, err := buildAuthorization(cred.SecretID, cred.SecretKey, req, window, true)
+	if err != nil {
+		return err
+	}
+	// Escape the ';' of the time ranges, keeping the parameters themselves.
+	query := encodeURIComponent(authorization, []byte{'&', '='})
+	if cred.Token != "" {
+		query += "&x-cos-security-token=" + url.QueryEscape(cred.Token)
+	}
+	if u.RawQuery != "" {
+		query = u.RawQuery + "&" + query
+	}
+	u.RawQuery = query
+	return nil
+}
+
 func addAuthorizationHeader(req *http.Request, cred auth.Credential, window *authTime) error {
 	if req.Header == nil {
 		req.Header = make(http.Header)
@@ -141,6 +141,7 @@ func mapHost(instance api.CVMInstanceInfo, region string) schema.Host {
 		PrivateIpv4: privateIPv4,
 		Public:      ipv4 != "",
 		Region:      region,
+		Tags:        tagMap(instance.Tags),
 	}
 	if strings.EqualFold(strings.Split(derefString(instance.OSName), " ")[0], "Windows") {
 		host.OSType = "WINDOWS"
@@ -189,3 +190,14 @@ func normalizedRegion(region string) string {
 		return region
 	}
 }
+
+func tagMap(tags []api.CVMTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[derefString(tag.Key)] = derefString(tag.Value)
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package cvm
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// GetSecurityGroups lists VPC security groups per region with their
+// policies. Attached instances come from the SecurityGroupIds reported by
+// CVM DescribeInstances; Lighthouse instances use firewalls, not groups.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	d.partialErr = nil
+	logger.Info("List security groups ...")
+	var regions []string
+	client := d.newClient()
+	if d.Region == "all" {
+		resp, err := client.DescribeCVMRegions(ctx, d.Region)
+		if err != nil {
+			logger.Error("List regions failed.")
+			return list, err
+		}
+		for _, r := range resp.Response.RegionSet {
+			if region := derefString(r.Region); region != "" {
+				regions = append(regions, region)
+			}
+		}
+	} else {
+		regions = append(regions, normalizedRegion(d.Region))
+	}
+
+	tracker := processbar.NewRegionTracker()
+	defer tracker.Finish()
+	got, regionErrs := regionrun.ForEach(ctx, regions, 0, tracker, func(ctx context.Context, r string) ([]schema.SecurityGroup, error) {
+		return d.listSecurityGroups(ctx, client, r)
+	})
+	list = append(list, got...)
+	d.partialErr = regionrun.Wrap(regionErrs)
+	return list, nil
+}
+
+func (d *Driver) listSecurityGroups(ctx context.Context, client *api.Client, region string) ([]schema.SecurityGroup, error) {
+	groups, err := paginate.Fetch(ctx, func(ctx context.Context, offset int64) (paginate.Page[api.SecurityGroupInfo, int64], error) {
+		response, err := client.DescribeSecurityGroups(ctx, region, offset, 100)
+		if err != nil {
+			return paginate.Page[api.SecurityGroupInfo, int64]{}, err
+		}
+		count := int64(len(response.Response.SecurityGroupSet))
+		return paginate.Page[api.SecurityGroupInfo, int64]{
+			Items: response.Response.SecurityGroupSet,
+			Next:  offset + count,
+			Done:  doneByTotal(offset, count, derefInt64(response.Response.TotalCount), 100),
+		}, nil
+	})
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	attached, err := groupAttachments(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		groupID := derefString(group.SecurityGroupID)
+		policies, err := client.DescribeSecurityGroupPolicies(ctx, region, groupID)
+		if err != nil {
+			return out, err
+		}
+		item := schema.SecurityGroup{
+			GroupID:   groupID,
+			GroupName: derefString(group.SecurityGroupName),
+			Region:    region,
+			Attached:  attached[groupID],
+		}
+		set := policies.Response.SecurityGroupPolicySet
+		item.Rules = append(item.Rules, convertPolicies("ingress", set.Ingress)...)
+		item.Rules = append(item.Rules, convertPolicies("egress", set.Egress)...)
+		item.FlagExposure()
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func groupAttachments(ctx context.Context, client *api.Client, region string) (map[string][]string, error) {
+	instances, err := paginate.Fetch(ctx, func(ctx context.Context, offset int64) (paginate.Page[api.CVMInstanceInfo, int64], error) {
+		response, err := client.DescribeCVMInstances(ctx, region, offset, 100)
+		if err != nil {
+			return paginate.Page[api.CVMInstanceInfo, int64]{}, err
+		}
+		count := int64(len(response.Response.InstanceSet))
+		return paginate.Page[api.CVMInstanceInfo, int64]{
+			Items: response.Response.InstanceSet,
+			Next:  offset + count,
+			Done:  doneByTotal(offset, count, derefInt64(response.Response.TotalCount), 100),
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string][]string)
+	for _, instance := range instances {
+		for _, groupID := range instance.SecurityGroupIDs {
+			out[groupID] = append(out[groupID], derefString(instance.InstanceID))
+		}
+	}
+	for groupID := range out {
+		sort.Strings(out[groupID])
+	}
+	return out, nil
+}
+
+func convertPolicies(direction string, policies []api.SecurityGroupPolicy) []schema.FirewallRule {
+	rules := make([]schema.FirewallRule, 0, len(policies))
+	for _, policy := range policies {
+		action := "allow"
+		if strings.EqualFold(derefString(policy.Action), "DROP") {
+			action = "deny"
+		}
+		port := strings.TrimSpace(derefString(policy.Port))
+		if port == "" || strings.EqualFold(port, "ALL") {
+			port = "all"
+		}
+		protocol := strings.ToLower(strings.TrimSpace(derefString(policy.Protocol)))
+		if protocol == "" {
+			protocol = "all"
+		}
+		rule := schema.FirewallRule{
+			Direction:   direction,
+			Action:      action,
+			Protocol:    protocol,
+			PortRange:   port,
+			CIDR:        policyPeer(policy),
+			Description: derefString(policy.PolicyDescription),
+		}
+		if policy.PolicyIndex != nil {
+			rule.Priority = strconv.FormatInt(*policy.PolicyIndex, 10)
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func policyPeer(policy api.SecurityGroupPolicy) string {
+	for _, peer := range []*string{policy.CidrBlock, policy.Ipv6CidrBlock, policy.SecurityGroupID} {
+		if v := strings.TrimSpace(derefString(peer)); v != "" {
+			return v
+		}
+	}
+	if t := policy.AddressTemplate; t != nil {
+		if v := strings.TrimSpace(derefString(t.AddressID)); v != "" {
+			return v
+		}
+		return strings.TrimSpace(derefString(t.AddressGroupID))
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package cvm
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+func TestGetSecurityGroupsMapsPoliciesAndInstances(t *testing.T) {
+	logger.SetOutput(io.Discard)
+	t.Cleanup(func() {
+		logger.SetOutput(nil)
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Header.Get("X-TC-Action") {
+		case "DescribeSecurityGroups":
+			if body := readBody(t, r); body != `{"Offset":"0","Limit":"100"}` {
+				t.Fatalf("unexpected DescribeSecurityGroups body: %s", body)
+			}
+			_, _ = w.Write([]byte(`{"Response":{"TotalCount":1,"SecurityGroupSet":[{"SecurityGroupId":"sg-1","SecurityGroupName":"web"}],"RequestId":"req-sg"}}`))
+		case "DescribeInstances":
+			_, _ = w.Write([]byte(`{"Response":{"TotalCount":2,"InstanceSet":[{"InstanceId":"ins-2","SecurityGroupIds":["sg-1"]},{"InstanceId":"ins-1","SecurityGroupIds":["sg-1","sg-2"]}],"RequestId":"req-ins"}}`))
+		case "DescribeSecurityGroupPolicies":
+			if body := readBody(t, r); body != `{"SecurityGroupId":"sg-1"}` {
+				t.Fatalf("unexpected DescribeSecurityGroupPolicies body: %s", body)
+			}
+			_, _ = w.Write([]byte(`{"Response":{"SecurityGroupPolicySet":{
+				"Ingress":[
+					{"PolicyIndex":0,"Protocol":"TCP","Port":"22,3389","CidrBlock":"0.0.0.0/0","Action":"ACCEPT","PolicyDescription":"admin"},
+					{"PolicyIndex":1,"Protocol":"ALL","Port":"ALL","SecurityGroupId":"sg-9","Action":"DROP"}
+				],
+				"Egress":[{"PolicyIndex":0,"Protocol":"ALL","Port":"ALL","Ipv6CidrBlock":"::/0","Action":"ACCEPT"}]
+			},"RequestId":"req-pol"}}`))
+		default:
+			t.Fatalf("unexpected action: %s", r.Header.Get("X-TC-Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(server.URL, "ap-guangzhou")
+	groups, err := driver.GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if err := driver.PartialError(); err != nil {
+		t.Fatalf("PartialError() = %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("unexpected group count: %d", len(groups))
+	}
+	group := groups[0]
+	if group.GroupID != "sg-1" || group.GroupName != "web" || group.Region != "ap-guangzhou" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if len(group.Attached) != 2 || group.Attached[0] != "ins-1" || group.Attached[1] != "ins-2" {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	if len(group.Rules) != 3 {
+		t.Fatalf("unexpected rules: %+v", group.Rules)
+	}
+	if r := group.Rules[0]; r.Protocol != "tcp" || r.PortRange != "22,3389" || r.Priority != "0" || !r.Exposed {
+		t.Fatalf("unexpected admin rule: %+v", r)
+	}
+	if r := group.Rules[1]; r.Action != "deny" || r.PortRange != "all" || r.CIDR != "sg-9" || r.Exposed {
+		t.Fatalf("unexpected drop rule: %+v", r)
+	}
+	if r := group.Rules[2]; r.Direction != "egress" || r.CIDR != "::/0" || r.Exposed {
+		t.Fatalf("unexpected egress rule: %+v", r)
+	}
+}
@@ -0,0 +1,117 @@
+package iam
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// camTimeZone is the zone CAM timestamps are written in; they carry no
+// offset of their own.
+var camTimeZone = time.FixedZone("UTC+8", 8*60*60)
+
+// Posture reads the credential state of every CAM sub-user: GetUser for the
+// last console login, DescribeSafeAuthFlagColl for the login verification
+// factors, and the access keys with GetSecurityLastUsed. CAM reports neither
+// password age nor the service a key last called. A per-user call that fails
+// is recorded as a gap.
+func (d *Driver) Posture(ctx context.Context) ([]schema.UserPosture, error) {
+	client := d.newClient()
+	users, err := client.ListUsers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.UserPosture, 0, len(users.Response.Data))
+	for _, user := range users.Response.Data {
+		if ctx.Err() != nil {
+			return out, ctx.Err()
+		}
+		uin := derefUint64(user.Uin)
+		posture := schema.UserPosture{
+			UserName:   derefString(user.Name),
+			UserID:     fmt.Sprintf("%v", uin),
+			CreateTime: parseCAMTime(derefString(user.CreateTime)),
+			Password:   derefUint64(user.ConsoleLogin) == 1,
+		}
+
+		if detail, err := client.GetUser(ctx, posture.UserName); err != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapLastLogin)
+		} else {
+			posture.LastLogin = parseCAMTime(derefString(detail.Response.RecentlyLoginTime))
+		}
+
+		if flags, err := client.DescribeSafeAuthFlagColl(ctx, uin); err != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapMFA)
+		} else {
+			posture.MFA = loginFactorEnabled(flags.Response.LoginFlag)
+		}
+
+		keys, err := client.ListAccessKeys(ctx, uin)
+		if err != nil {
+			posture.Gaps = append(posture.Gaps, schema.PostureGapAccessKeys)
+		}
+		ids := make([]string, 0, len(keys.Response.AccessKeys))
+		for _, key := range keys.Response.AccessKeys {
+			id := derefString(key.AccessKeyID)
+			ids = append(ids, id)
+			posture.AccessKeys = append(posture.AccessKeys, schema.AccessKeyPosture{
+				ID:         id,
+				Active:     strings.EqualFold(derefString(key.Status), "Active"),
+				CreateTime: parseCAMTime(derefString(key.CreateTime)),
+			})
+		}
+		if len(ids) > 0 {
+			lastUsed, err := client.GetSecurityLastUsed(ctx, ids)
+			if err != nil {
+				posture.Gaps = append(posture.Gaps, schema.PostureGapKeyLastUsed)
+			}
+			for _, row := range lastUsed.Response.SecretIDLastUsedRows {
+				for i := range posture.AccessKeys {
+					if posture.AccessKeys[i].ID == derefString(row.SecretID) {
+						posture.AccessKeys[i].LastUsed = secretLastUsed(row)
+					}
+				}
+			}
+		}
+		out = append(out, posture)
+	}
+	return out, nil
+}
+
+// loginFactorEnabled reports whether console login asks for a second factor
+// on top of the password.
+func loginFactorEnabled(flag *api.LoginActionFlag) bool {
+	if flag == nil {
+		return false
+	}
+	for _, factor := range []*uint64{flag.Token, flag.Stoken, flag.U2FToken, flag.Phone, flag.Wechat, flag.Mail, flag.Custom} {
+		if derefUint64(factor) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func secretLastUsed(row api.SecretIDLastUsed) time.Time {
+	if ts := derefUint64(row.LastSecretUsedDate); ts > 0 {
+		return time.Unix(int64(ts), 0).UTC()
+	}
+	day, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(derefString(row.LastUsedDate)), camTimeZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return day
+}
+
+func parseCAMTime(value string) time.Time {
+	parsed, err := time.ParseInLocation("2006-01-02 15:04:05", strings.TrimSpace(value), camTimeZone)
+	if err != nil {
+		return time.Time{}
+	}
+	return parsed
+}
@@ -0,0 +1,62 @@
+package iam
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostureReadsCAMCredentialState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(t, r)
+		switch r.Header.Get("X-TC-Action") {
+		case "ListUsers":
+			_, _ = w.Write([]byte(`{"Response":{"Data":[{"Uin":42,"Name":"alice","ConsoleLogin":1,"CreateTime":"2026-01-20 09:00:00"}],"RequestId":"r1"}}`))
+		case "GetUser":
+			_, _ = w.Write([]byte(`{"Response":{"Uin":42,"Name":"alice","RecentlyLoginTime":"2026-04-22 10:05:00","RequestId":"r2"}}`))
+		case "DescribeSafeAuthFlagColl":
+			if !strings.Contains(body, `"SubUin":42`) {
+				t.Fatalf("unexpected DescribeSafeAuthFlagColl body: %s", body)
+			}
+			_, _ = w.Write([]byte(`{"Response":{"LoginFlag":{"Phone":0,"Token":0,"Stoken":1,"Wechat":0},"RequestId":"r3"}}`))
+		case "ListAccessKeys":
+			_, _ = w.Write([]byte(`{"Response":{"AccessKeys":[{"AccessKeyId":"AKID111","Status":"Active","CreateTime":"2026-01-20 09:00:00"},{"AccessKeyId":"AKID222","Status":"Inactive","CreateTime":"2026-01-21 09:00:00"}],"RequestId":"r4"}}`))
+		case "GetSecurityLastUsed":
+			if !strings.Contains(body, `"SecretIdList":["AKID111","AKID222"]`) {
+				t.Fatalf("unexpected GetSecurityLastUsed body: %s", body)
+			}
+			_, _ = w.Write([]byte(`{"Response":{"SecretIdLastUsedRows":[{"SecretId":"AKID111","LastUsedDate":"2026-04-22","LastSecretUsedDate":1776830400},{"SecretId":"AKID222","LastSecretUsedDate":0}],"RequestId":"r5"}}`))
+		default:
+			t.Fatalf("unexpected action: %s", r.Header.Get("X-TC-Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := newTestDriver(server.URL)
+	got, err := driver.Posture(context.Background())
+	if err != nil {
+		t.Fatalf("Posture: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(got))
+	}
+	user := got[0]
+	if user.UserID != "42" || !user.Password || !user.MFA || len(user.Gaps) != 0 {
+		t.Fatalf("unexpected posture: %+v", user)
+	}
+	if want := time.Date(2026, 4, 22, 2, 5, 0, 0, time.UTC); !user.LastLogin.Equal(want) {
+		t.Errorf("LastLogin = %v, want %v", user.LastLogin, want)
+	}
+	if len(user.AccessKeys) != 2 {
+		t.Fatalf("expected 2 keys, got %+v", user.AccessKeys)
+	}
+	if key := user.AccessKeys[0]; !key.Active || !key.LastUsed.Equal(time.Unix(1776830400, 0)) {
+		t.Errorf("unexpected first key: %+v", key)
+	}
+	if key := user.AccessKeys[1]; key.Active || !key.LastUsed.IsZero() {
+		t.Errorf("unexpected second key: %+v", key)
+	}
+}
@@ -1,16 +1,26 @@
 package replay
 
 import (
+	"net/http"
+
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/cos"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 func ClientConfig() tencent.ClientConfig {
-	httpClient := replayHTTPClient()
+	cfg := HTTPClientConfig(replayHTTPClient())
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every tencent client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) tencent.ClientConfig {
+	httpClient = telemetry.WrapClient("tencent", httpClient)
 	return tencent.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		COSOptions:          []cos.Option{cos.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions: []api.Option{api.WithHTTPClient(httpClient)},
+		COSOptions: []cos.Option{cos.WithHTTPClient(httpClient)},
 	}
 }
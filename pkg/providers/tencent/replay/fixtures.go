@@ -15,11 +15,12 @@ type camPolicyFixture struct {
 }
 
 type camUserFixture struct {
-	UIN          uint64
-	Name         string
-	ConsoleLogin bool
-	CreateTime   string
-	Policies     []camPolicyFixture
+	UIN           uint64
+	Name          string
+	ConsoleLogin  bool
+	CreateTime    string
+	LastLoginTime string
+	Policies      []camPolicyFixture
 }
 
 type cvmFixture struct {
@@ -30,6 +31,8 @@ type cvmFixture struct {
 	PublicIP     string
 	PrivateIP    string
 	OSName       string
+	// SecurityGroups lists the demoSecurityGroups the instance belongs to.
+	SecurityGroups []string
 }
 
 type lighthouseFixture struct {
@@ -152,6 +155,13 @@ func seedTencentAccessKeys() map[uint64][]camAccessKeyFixture {
 	return out
 }
 
+// demoCAMKeyLastUsed is the Unix time GetSecurityLastUsed reports for a
+// seeded key; keys missing from it were never used.
+var demoCAMKeyLastUsed = map[string]uint64{
+	"AKIDz8krbsJ5yKBZQpn74WFkm101EXAMPLE": 1776830400,
+	"AKIDz8krbsJ5yKBZQpn74WFkmLPx3OWNER":  1776924000,
+}
+
 var demoCredentials = loadDemoCredentials()
 
 var demoRegions = []string{
@@ -176,10 +186,11 @@ var demoPolicies = []camPolicyFixture{
 
 var demoCAMUsers = []camUserFixture{
 	{
-		UIN:          100000101,
-		Name:         "admin",
-		ConsoleLogin: true,
-		CreateTime:   "2026-04-20 09:00:00",
+		UIN:           100000101,
+		Name:          "admin",
+		ConsoleLogin:  true,
+		CreateTime:    "2026-04-20 09:00:00",
+		LastLoginTime: "2026-04-22 10:05:00",
 		Policies: []camPolicyFixture{
 			demoPolicies[0],
 		},
@@ -197,22 +208,24 @@ var demoCAMUsers = []camUserFixture{
 
 var demoCVMInstances = []cvmFixture{
 	{
-		InstanceID:   "ins-cvm001",
-		InstanceName: "cvm-01",
-		State:        "RUNNING",
-		Region:       "ap-guangzhou",
-		PublicIP:     "203.0.113.31",
-		PrivateIP:    "10.10.1.31",
-		OSName:       "TencentOS Server 3.1",
+		InstanceID:     "ins-cvm001",
+		InstanceName:   "cvm-01",
+		State:          "RUNNING",
+		Region:         "ap-guangzhou",
+		PublicIP:       "203.0.113.31",
+		PrivateIP:      "10.10.1.31",
+		OSName:         "TencentOS Server 3.1",
+		SecurityGroups: []string{"sg-cvm001"},
 	},
 	{
-		InstanceID:   "ins-cvm002",
-		InstanceName: "cvm-02",
-		State:        "RUNNING",
-		Region:       "ap-shanghai",
-		PublicIP:     "203.0.113.32",
-		PrivateIP:    "10.10.2.32",
-		OSName:       "Windows Server 2019 Datacenter",
+		InstanceID:     "ins-cvm002",
+		InstanceName:   "cvm-02",
+		State:          "RUNNING",
+		Region:         "ap-shanghai",
+		PublicIP:       "203.0.113.32",
+		PrivateIP:      "10.10.2.32",
+		OSName:         "Windows Server 2019 Datacenter",
+		SecurityGroups: []string{"sg-cvm002"},
 	},
 }
 
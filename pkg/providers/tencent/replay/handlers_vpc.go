@@ -0,0 +1,125 @@
+package replay
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/api"
+)
+
+type securityGroupPolicyFixture struct {
+	Protocol    string
+	Port        string
+	Peer        string
+	Action      string
+	Description string
+}
+
+type securityGroupFixture struct {
+	ID      string
+	Name    string
+	Region  string
+	Ingress []securityGroupPolicyFixture
+	Egress  []securityGroupPolicyFixture
+}
+
+// demoSecurityGroups leaves RDP open to the internet on the Windows host in
+// Shanghai; the Guangzhou group only exposes HTTPS.
+var demoSecurityGroups = []securityGroupFixture{
+	{
+		ID:     "sg-cvm001",
+		Name:   "ctk-demo-web",
+		Region: "ap-guangzhou",
+		Ingress: []securityGroupPolicyFixture{
+			{Protocol: "TCP", Port: "443", Peer: "0.0.0.0/0", Action: "ACCEPT", Description: "https"},
+			{Protocol: "TCP", Port: "22", Peer: "10.10.0.0/16", Action: "ACCEPT", Description: "ssh from vpc"},
+		},
+		Egress: []securityGroupPolicyFixture{
+			{Protocol: "ALL", Port: "ALL", Peer: "0.0.0.0/0", Action: "ACCEPT"},
+		},
+	},
+	{
+		ID:     "sg-cvm002",
+		Name:   "ctk-demo-windows",
+		Region: "ap-shanghai",
+		Ingress: []securityGroupPolicyFixture{
+			{Protocol: "TCP", Port: "3389", Peer: "0.0.0.0/0", Action: "ACCEPT", Description: "rdp"},
+			{Protocol: "ALL", Port: "ALL", Peer: "0.0.0.0/0", Action: "DROP"},
+		},
+	},
+}
+
+// handleVPC serves the cloudlist `securitygroup` actions.
+func (t *transport) handleVPC(req *http.Request, action string, body []byte) (*http.Response, error) {
+	switch action {
+	case "DescribeSecurityGroups":
+		var payload api.DescribeSecurityGroupsRequest
+		_ = json.Unmarshal(body, &payload)
+		region := tcRegion(req)
+		items := make([]securityGroupFixture, 0, len(demoSecurityGroups))
+		for _, group := range demoSecurityGroups {
+			if group.Region == region {
+				items = append(items, group)
+			}
+		}
+		offset, _ := strconv.Atoi(derefString(payload.Offset))
+		limit, err := strconv.Atoi(derefString(payload.Limit))
+		if err != nil {
+			limit = 100
+		}
+		w := demoreplay.OffsetWindow(len(items), offset, limit)
+		resp := api.DescribeSecurityGroupsResponse{}
+		total := int64(len(items))
+		resp.Response.TotalCount = &total
+		resp.Response.RequestID = "req-replay-vpc-security-groups"
+		resp.Response.SecurityGroupSet = make([]api.SecurityGroupInfo, 0, w.End-w.Start)
+		for _, item := range items[w.Start:w.End] {
+			id, name := item.ID, item.Name
+			resp.Response.SecurityGroupSet = append(resp.Response.SecurityGroupSet, api.SecurityGroupInfo{
+				SecurityGroupID:   &id,
+				SecurityGroupName: &name,
+			})
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case "DescribeSecurityGroupPolicies":
+		var payload api.DescribeSecurityGroupPoliciesRequest
+		_ = json.Unmarshal(body, &payload)
+		groupID := derefString(payload.SecurityGroupID)
+		for _, group := range demoSecurityGroups {
+			if group.ID != groupID {
+				continue
+			}
+			resp := api.DescribeSecurityGroupPoliciesResponse{}
+			resp.Response.RequestID = "req-replay-vpc-security-group-policies"
+			resp.Response.SecurityGroupPolicySet = api.SecurityGroupPolicySet{
+				Ingress: securityGroupPolicies(group.Ingress),
+				Egress:  securityGroupPolicies(group.Egress),
+			}
+			return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+		}
+		return openAPIErrorResponse(req, http.StatusBadRequest, "ResourceNotFound",
+			fmt.Sprintf("The security group %s does not exist.", groupID)), nil
+	}
+	return openAPIErrorResponse(req, http.StatusNotFound, "InvalidAction.NotFound",
+		fmt.Sprintf("Unsupported replay action: %s", action)), nil
+}
+
+func securityGroupPolicies(fixtures []securityGroupPolicyFixture) []api.SecurityGroupPolicy {
+	out := make([]api.SecurityGroupPolicy, 0, len(fixtures))
+	for i, f := range fixtures {
+		index := int64(i)
+		protocol, port, peer, action, description := f.Protocol, f.Port, f.Peer, f.Action, f.Description
+		out = append(out, api.SecurityGroupPolicy{
+			PolicyIndex:       &index,
+			Protocol:          &protocol,
+			Port:              &port,
+			CidrBlock:         &peer,
+			Action:            &action,
+			PolicyDescription: &description,
+		})
+	}
+	return out
+}
@@ -3,6 +3,8 @@ package replay
 import (
 	"net/http"
 	"sync"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 )
 
 var (
@@ -16,7 +18,7 @@ func replayHTTPClient() *http.Client {
 	defer replayMu.Unlock()
 	if replayClient == nil {
 		replayState = newTransport()
-		replayClient = &http.Client{Transport: replayState}
+		replayClient = &http.Client{Transport: faults.Wrap(replayState)}
 	}
 	return replayClient
 }
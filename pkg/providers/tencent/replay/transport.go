@@ -39,6 +39,8 @@ type transport struct {
 	accessKeys   map[uint64][]camAccessKeyFixture
 	accessKeySeq int
 	cdbAccounts  map[string][]cdbAccountFixture
+	objects      map[string][]byte
+	objectSeq    int
 }
 
 func newTransport() *transport {
@@ -50,6 +52,7 @@ func newTransport() *transport {
 		tasks:        make(map[string]invocationResult),
 		accessKeys:   seedTencentAccessKeys(),
 		cdbAccounts:  make(map[string][]cdbAccountFixture),
+		objects:      make(map[string][]byte),
 	}
 }
 
@@ -120,7 +123,7 @@ func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return nil, err
 	}
 	if isCOSHost(req.URL.Hostname()) {
-		return t.handleCOS(req)
+		return t.handleCOS(req, body)
 	}
 	return t.handleOpenAPI(req, body)
 }
@@ -164,6 +167,8 @@ func (t *transport) handleOpenAPI(req *http.Request, body []byte) (*http.Respons
 		return t.handleCLS(req, action)
 	case "sms":
 		return t.handleSMS(req, action)
+	case "vpc":
+		return t.handleVPC(req, action, body)
 	default:
 		return openAPIErrorResponse(req, http.StatusNotFound, "InvalidAction.NotFound", fmt.Sprintf("Unsupported replay action: %s", action)), nil
 	}
@@ -273,6 +278,13 @@ func (t *transport) handleCAM(req *http.Request, action string, body []byte) (*h
 		resp := api.GetUserResponse{}
 		resp.Response.Uin = uint64Ptr(user.UIN)
 		resp.Response.Name = stringPtr(user.Name)
+		resp.Response.ConsoleLogin = uint64Ptr(0)
+		if user.ConsoleLogin {
+			resp.Response.ConsoleLogin = uint64Ptr(1)
+		}
+		if user.LastLoginTime != "" {
+			resp.Response.RecentlyLoginTime = stringPtr(user.LastLoginTime)
+		}
 		resp.Response.RequestID = "req-replay-cam-get-user"
 		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
 	case "AttachUserPolicy":
@@ -367,6 +379,34 @@ func (t *transport) handleCAM(req *http.Request, action string, body []byte) (*h
 		}
 		resp.Response.RequestID = "req-replay-cam-list-access-keys"
 		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case "GetSecurityLastUsed":
+		var payload api.GetSecurityLastUsedRequest
+		_ = json.Unmarshal(body, &payload)
+		resp := api.GetSecurityLastUsedResponse{}
+		for _, id := range payload.SecretIDList {
+			row := api.SecretIDLastUsed{SecretID: stringPtr(id), LastSecretUsedDate: uint64Ptr(0)}
+			if ts, ok := demoCAMKeyLastUsed[id]; ok {
+				row.LastSecretUsedDate = uint64Ptr(ts)
+				row.LastUsedDate = stringPtr(time.Unix(int64(ts), 0).In(time.FixedZone("UTC+8", 8*60*60)).Format("2006-01-02"))
+			}
+			resp.Response.SecretIDLastUsedRows = append(resp.Response.SecretIDLastUsedRows, row)
+		}
+		resp.Response.RequestID = "req-replay-cam-security-last-used"
+		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case "DescribeSafeAuthFlagColl":
+		var payload api.DescribeSafeAuthFlagCollRequest
+		_ = json.Unmarshal(body, &payload)
+		if _, ok := t.findUserByUIN(derefUint64(payload.SubUin)); !ok {
+			return openAPIErrorResponse(req, http.StatusNotFound, "ResourceNotFound.User", "The specified user does not exist."), nil
+		}
+		// No demo user has a login verification factor bound.
+		off := uint64Ptr(0)
+		flag := api.LoginActionFlag{Phone: off, Token: off, Stoken: off, Wechat: off, Custom: off, Mail: off, U2FToken: off}
+		resp := api.DescribeSafeAuthFlagCollResponse{}
+		resp.Response.LoginFlag = &flag
+		resp.Response.ActionFlag = &flag
+		resp.Response.RequestID = "req-replay-cam-safe-auth-flag"
+		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
 	case "CreateAccessKey":
 		var payload api.CreateAccessKeyRequest
 		_ = json.Unmarshal(body, &payload)
@@ -461,6 +501,7 @@ func (t *transport) handleCVM(req *http.Request, action string, body []byte) (*h
 				PublicIPAddresses:  demoreplay.NonEmptyStrings(item.PublicIP),
 				PrivateIPAddresses: demoreplay.NonEmptyStrings(item.PrivateIP),
 				OSName:             &osName,
+				SecurityGroupIDs:   item.SecurityGroups,
 			})
 		}
 		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
@@ -879,8 +920,12 @@ func (t *transport) handleTAT(req *http.Request, action string, body []byte) (*h
 	}
 }
 
-func (t *transport) handleCOS(req *http.Request) (*http.Response, error) {
-	switch verifyCOSAuth(req) {
+func (t *transport) handleCOS(req *http.Request, body []byte) (*http.Response, error) {
+	verify := verifyCOSAuth
+	if req.URL.Query().Has("q-signature") {
+		verify = verifyCOSPresigned
+	}
+	switch verify(req) {
 	case demoreplay.AuthInvalidAccessKey:
 		return xmlErrorResponse(req, http.StatusForbidden, "InvalidAccessKeyId", "The Access Key Id you provided does not exist in our records."), nil
 	case demoreplay.AuthInvalidSignature:
@@ -911,9 +956,15 @@ func (t *transport) handleCOS(req *http.Request) (*http.Response, error) {
 		return xmlErrorResponse(req, http.StatusNotFound, "NoSuchBucket", "The specified bucket does not exist."), nil
 	}
 
+	if key := strings.TrimPrefix(req.URL.Path, "/"); key != "" {
+		return t.handleCOSObject(req, bucket.Name, key, body)
+	}
 	if req.URL.Query().Has("acl") {
 		return t.handleCOSBucketACL(req, bucket.Name)
 	}
+	if req.URL.Query().Has("policy") {
+		return xmlErrorResponse(req, http.StatusNotFound, "NoSuchBucketPolicy", "The bucket policy does not exist."), nil
+	}
 
 	switch req.Method {
 	case http.MethodGet:
@@ -24,6 +24,6 @@ func init() {
 			{Text: "ap-seoul", Description: "Seoul"},
 			{Text: "ap-tokyo", Description: "Tokyo"},
 		},
-		Capabilities: []string{"cloudlist", "iam", "bucket", "vm", "iam-role", "bucket-acl", "event", "iam-credential", "database"},
+		Capabilities: []string{"cloudlist", "iam", "bucket", "vm", "iam-role", "bucket-acl", "event", "iam-credential", "database", "object-access", "iam-posture"},
 	})
 }
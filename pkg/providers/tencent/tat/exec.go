@@ -3,6 +3,8 @@ package tat
 import (
 	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"strings"
 	"sync"
 	"time"
@@ -48,12 +50,21 @@ func (d *Driver) SetClientOptions(opts ...api.Option) {
 }
 
 func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
-	ctx := context.Background()
+	result, err := d.Invoke(context.Background(), instanceID, osType, cmd)
+	if err != nil {
+		logger.Error(err)
+		return ""
+	}
+	return result.Output
+}
+
+// Invoke runs cmd through TAT and returns the task's decoded output and exit
+// code. A non-success task status is returned as an error.
+func (d *Driver) Invoke(ctx context.Context, instanceID, osType, cmd string) (schema.CommandResult, error) {
 	client := d.newClient()
 	commandType, ok := resolveCommandType(osType)
 	if !ok {
-		logger.Error("Unknown ostype", osType)
-		return ""
+		return schema.CommandResult{}, fmt.Errorf("Unknown ostype %s", osType)
 	}
 	response, err := client.RunTATCommand(
 		ctx,
@@ -63,48 +74,41 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 		[]string{instanceID},
 	)
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	invocationID := derefString(response.Response.InvocationID)
 	if invocationID == "" {
-		logger.Error("Missing invocation id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing invocation id.")
 	}
 	return d.describeInvocations(ctx, client, invocationID)
 }
 
-func (d *Driver) describeInvocations(ctx context.Context, client *api.Client, invocationID string) string {
+func (d *Driver) describeInvocations(ctx context.Context, client *api.Client, invocationID string) (schema.CommandResult, error) {
 	response, err := client.DescribeTATInvocations(ctx, d.Region, []string{invocationID})
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	if len(response.Response.InvocationSet) == 0 || len(response.Response.InvocationSet[0].InvocationTaskBasicInfoSet) == 0 {
-		logger.Error("Missing invocation task metadata.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing invocation task metadata.")
 	}
 	taskID := derefString(response.Response.InvocationSet[0].InvocationTaskBasicInfoSet[0].InvocationTaskID)
 	if taskID == "" {
-		logger.Error("Missing invocation task id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing invocation task id.")
 	}
 	return d.describeInvocationTasks(ctx, client, taskID)
 }
 
-func (d *Driver) describeInvocationTasks(ctx context.Context, client *api.Client, taskID string) string {
+func (d *Driver) describeInvocationTasks(ctx context.Context, client *api.Client, taskID string) (schema.CommandResult, error) {
 	attempts := 0
 	for {
 		d.sleepFor(d.pollDelay())
 		attempts++
 		response, err := client.DescribeTATInvocationTasks(ctx, d.Region, []string{taskID}, false)
 		if err != nil {
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		if len(response.Response.InvocationTaskSet) == 0 {
-			logger.Error("Missing invocation task detail.")
-			return ""
+			return schema.CommandResult{}, errors.New("Missing invocation task detail.")
 		}
 		task := response.Response.InvocationTaskSet[0]
 		status := strings.ToUpper(derefString(task.TaskStatus))
@@ -113,27 +117,32 @@ func (d *Driver) describeInvocationTasks(ctx context.Context, client *api.Client
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Timeout: Wait 5s by default.")
-			return ""
-		case "SUCCESS":
-			output := ""
-			if task.TaskResult != nil {
-				output = derefString(task.TaskResult.Output)
+			return schema.CommandResult{}, errors.New("Timeout: Wait 5s by default.")
+		}
+		result := schema.CommandResult{Status: status}
+		if task.TaskResult != nil {
+			if task.TaskResult.ExitCode != nil {
+				code := int(*task.TaskResult.ExitCode)
+				result.ExitCode = &code
 			}
+			output := derefString(task.TaskResult.Output)
 			raw, err := base64.StdEncoding.DecodeString(output)
-			if err != nil {
-				logger.Error(output, err)
-				return ""
-			}
-			return string(raw)
-		default:
-			if msg := derefString(task.ErrorInfo); msg != "" {
-				logger.Error("Exception status: " + status + " - " + msg)
-				return ""
+			switch {
+			case err == nil:
+				result.Output = string(raw)
+			case status == "SUCCESS":
+				return result, fmt.Errorf("%s %w", output, err)
+			default:
+				result.Output = output
 			}
-			logger.Error("Exception status: " + status)
-			return ""
 		}
+		if status == "SUCCESS" {
+			return result, nil
+		}
+		if msg := derefString(task.ErrorInfo); msg != "" {
+			return result, errors.New("Exception status: " + status + " - " + msg)
+		}
+		return result, errors.New("Exception status: " + status)
 	}
 }
 
@@ -21,6 +21,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/lighthouse"
 	"github.com/404tk/cloudtoolkit/pkg/providers/tencent/tat"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -109,10 +110,10 @@ func (p *Provider) Name() string {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
 			d := &billing.Driver{Cred: p.apiCredential, Region: p.region}
 			d.SetClientOptions(p.clientOptions...)
-			d.QueryAccountBalance(ctx)
+			schema.AppendAssets(list, d.QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			cvmprovider := &cvm.Driver{Credential: p.apiCredential, Region: p.region}
@@ -185,6 +186,14 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			result, err := smsDriver.GetResource(ctx)
 			list.Sms = result
 			list.AddError("sms", err)
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			cvmprovider := &cvm.Driver{Credential: p.apiCredential, Region: p.region}
+			cvmprovider.SetClientOptions(p.clientOptions...)
+			groups, err := cvmprovider.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
+			list.AddError("securitygroup", cvmprovider.PartialError())
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -277,6 +286,24 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	cosprovider := p.newCOSDriver()
+	infos, err := p.bucketInfos(ctx, cosprovider, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	return cosprovider.WalkObjects(ctx, infos, q, visit)
+}
+
+// IAMPosture implements schema.IAMPostureReader for CAM sub-users.
+func (p *Provider) IAMPosture(ctx context.Context) ([]schema.UserPosture, error) {
+	driver := &iam.Driver{Credential: p.apiCredential}
+	driver.SetClientOptions(p.clientOptions...)
+	return driver.Posture(ctx)
+}
+
 // IAMCredential implements schema.IAMCredentialManager for tencent CAM
 // AccessKey lifecycle. `principal` is the CAM user name (required for create
 // and delete; optional for list). `credentialID` is the AccessKeyId.
@@ -319,7 +346,8 @@ func (p *Provider) IAMCredential(ctx context.Context, action, principal, credent
 // DBManagement implements schema.DBManager for Tencent CDB. `useradd` provisions
 // an account from the `rds-account-check` config; `userdel` removes it.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
-	cdbprovider := &cdb.Driver{Credential: p.apiCredential, Region: p.region}
+	region := invcache.DatabaseRegion(ctx, instanceID, p.region)
+	cdbprovider := &cdb.Driver{Credential: p.apiCredential, Region: region}
 	cdbprovider.SetClientOptions(p.clientOptions...)
 	switch action {
 	case "useradd":
@@ -331,6 +359,13 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 	}
 }
 
+// ObjectAccess implements schema.ObjectAccessManager for COS. The requests
+// appear in the bucket's access logs and, when data events are tracked, in
+// CloudAudit under the returned request ID.
+func (p *Provider) ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (schema.ObjectAccessResult, error) {
+	return p.newCOSDriver().AccessObject(ctx, action, bucket, key, body)
+}
+
 // BucketACL implements schema.BucketACLManager for tencent COS. `level`
 // accepts canned COS ACL values (private / public-read / public-read-write
 // / authenticated-read) or friendly aliases resolved by cos.NormalizeCOSACL.
@@ -394,18 +429,29 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads the CloudAudit events of the current SecretId.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	d := &cloudaudit.Driver{Credential: p.apiCredential}
+	d.SetClientOptions(p.clientOptions...)
+	return d.QueryEvents(ctx, q)
+}
+
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
-		if p.region == "" || p.region == "all" {
-			return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+		region := p.region
+		if region == "" || region == "all" {
+			host, found := p.lookupHost(ctx, instanceID)
+			if !found || host.Region == "" {
+				return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+			}
+			region = host.Region
 		}
-		d := tat.Driver{Credential: p.apiCredential, Region: p.region}
+		d := tat.Driver{Credential: p.apiCredential, Region: region}
 		d.SetClientOptions(p.clientOptions...)
-		output := d.RunCommand(instanceID, osType, command)
-		return schema.CommandResult{Output: output}, nil
+		return d.Invoke(ctx, instanceID, osType, command)
 	}
 
-	host, ok := p.lookupHost(instanceID)
+	host, ok := p.lookupHost(ctx, instanceID)
 	if !ok {
 		return schema.CommandResult{}, fmt.Errorf("unable to resolve instance metadata, retry: shell <instance-id>")
 	}
@@ -415,17 +461,14 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 	if err != nil {
 		return schema.CommandResult{}, err
 	}
-	output := d.RunCommand(instanceID, host.OSType, string(command))
-	return schema.CommandResult{Output: output}, nil
+	return d.Invoke(ctx, instanceID, host.OSType, string(command))
 }
 
-func (p *Provider) lookupHost(instanceID string) (schema.Host, bool) {
-	for _, host := range tat.GetCacheHostList() {
-		if host.ID == instanceID || host.HostName == instanceID {
-			return host, true
-		}
-	}
-	return schema.Host{}, false
+// lookupHost resolves an instance from the persisted inventory of the
+// credential; this session's host list only answers when no inventory store
+// is attached (see invcache.ResolveHost).
+func (p *Provider) lookupHost(ctx context.Context, instanceID string) (schema.Host, bool) {
+	return invcache.ResolveHost(ctx, instanceID, tat.GetCacheHostList())
 }
 
 func (p *Provider) bucketInfos(ctx context.Context, driver *cos.Driver, bucketName string) (map[string]string, error) {
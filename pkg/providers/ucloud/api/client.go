@@ -14,6 +14,8 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	ucloudauth "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/auth"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const (
@@ -61,11 +63,17 @@ func (e *APIError) Error() string {
 	}
 }
 
+// NewHTTPClient returns the paced, instrumented client every UCloud API call
+// goes through by default.
+func NewHTTPClient() *http.Client {
+	return ratelimit.WrapClient("ucloud", telemetry.WrapClient("ucloud", &http.Client{Timeout: 30 * time.Second}))
+}
+
 func NewClient(credential ucloudauth.Credential, opts ...Option) *Client {
 	client := &Client{
 		baseURL:     DefaultBaseURL,
 		credential:  credential,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  NewHTTPClient(),
 		retryPolicy: DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
@@ -6,7 +6,7 @@ import (
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud/api"
 	ucloudauth "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/auth"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -15,26 +15,31 @@ type Driver struct {
 	ProjectID  string
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 
 	var resp api.GetBalanceResponse
 	err := d.client().Do(ctx, api.Request{Action: "GetBalance"}, &resp)
 	if err != nil {
-		return
+		return nil
 	}
 
 	amount := strings.TrimSpace(resp.AccountInfo.AmountAvailable)
 	if amount == "" {
 		amount = strings.TrimSpace(resp.AccountInfo.Amount)
 	}
-	if amount != "" {
-		logger.Warning("Available cash amount:", amount)
+	if amount == "" {
+		return nil
 	}
+	return []schema.Balance{{
+		Kind:     schema.BalanceCredit,
+		Amount:   amount,
+		Currency: "CNY",
+	}}
 }
 
 func (d *Driver) client() *api.Client {
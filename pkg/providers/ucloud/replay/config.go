@@ -3,17 +3,25 @@ package replay
 import (
 	"net/http"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud"
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 // ClientConfig builds the demo replay configuration injected into
 // ucloud.NewWithConfig when replay is active for the ucloud provider.
 func ClientConfig() ucloud.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every ucloud client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) ucloud.ClientConfig {
+	httpClient = telemetry.WrapClient("ucloud", httpClient)
 	return ucloud.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions: []api.Option{api.WithHTTPClient(httpClient)},
 	}
 }
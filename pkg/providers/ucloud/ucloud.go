@@ -17,6 +17,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud/uhost"
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud/ulog"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/cache"
@@ -122,12 +123,12 @@ func (p *Provider) newClient() *api.Client {
 // Resources returns cloud assets for the asset inventory payload.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&billing.Driver{
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&billing.Driver{
 				Credential: p.credential,
 				Client:     p.newClient(),
 				ProjectID:  p.projectID,
-			}).QueryAccountBalance(ctx)
+			}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			d := &uhost.Driver{
@@ -222,6 +223,17 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	driver := p.newUFileDriver()
+	infos, err := p.bucketInfos(ctx, driver, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	return driver.WalkObjects(ctx, infos, q, visit)
+}
+
 // BucketACL implements schema.BucketACLManager for UCloud UFile. `level`
 // accepts the UFile bucket access type (`private` / `public` / `limited`) or
 // a friendly alias resolved by the driver.
@@ -414,6 +426,16 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads the project operation log.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &ulog.Driver{
+		Credential: p.credential,
+		Client:     p.newClient(),
+		ProjectID:  p.projectID,
+	}
+	return driver.QueryEvents(ctx, q)
+}
+
 // DBManagement implements schema.DBManager for UCloud UDB. `useradd` /
 // `userdel` use the `CreateUDBUser` / `DeleteUDBUser` actions.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
@@ -421,7 +443,7 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 		Credential: p.credential,
 		Client:     p.newClient(),
 		ProjectID:  p.projectID,
-		Regions:    []string{p.region},
+		Regions:    []string{invcache.DatabaseRegion(ctx, instanceID, p.region)},
 	}
 	switch action {
 	case "useradd":
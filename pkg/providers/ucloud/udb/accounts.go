@@ -28,7 +28,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 	if d == nil {
 		return schema.DatabaseActionResult{}, errors.New("ucloud udb: nil driver")
 	}
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -64,7 +64,7 @@ func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.D
 	if d == nil {
 		return schema.DatabaseActionResult{}, errors.New("ucloud udb: nil driver")
 	}
-	user, _, err := parseRDSAccount()
+	user, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -91,8 +91,8 @@ func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.D
 	}, nil
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
 	"github.com/404tk/cloudtoolkit/pkg/providers/ucloud/api"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
@@ -52,10 +53,14 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 			if bucket != "" && name != bucket {
 				continue
 			}
-			out = append(out, schema.BucketACLEntry{
+			entry := schema.BucketACLEntry{
 				Container: name,
 				Level:     normalizeUFileType(item.Type),
-			})
+			}
+			var verdict exposure.Verdict
+			verdict.Add("bucket type", typeGrant(entry.Level))
+			verdict.Apply(&entry)
+			out = append(out, entry)
 		}
 		if len(resp.DataSet) == 0 || len(resp.DataSet) < pageSize {
 			break
@@ -109,6 +114,19 @@ func (d *Driver) UnexposeBucket(ctx context.Context, bucket string) error {
 	}, &resp)
 }
 
+// typeGrant maps a UFile bucket type to its grant; limited buckets are only
+// readable by requests that pass the bucket's referer or IP rules.
+func typeGrant(level string) exposure.Grant {
+	reasons := []string{"bucket type " + level}
+	switch level {
+	case UFileTypePublic:
+		return exposure.Grant{Read: true, Reasons: reasons}
+	case UFileTypeLimited:
+		return exposure.Grant{Conditional: true, Reasons: reasons}
+	}
+	return exposure.Grant{}
+}
+
 // normalizeUFileType maps friendly aliases to the canonical UFile type
 // values. Unknown values are passed through lower-cased so the audit table
 // surfaces whatever the API returned, even if it's a future-added type.
@@ -16,6 +16,8 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/internal/httpclient"
 	ucloudapi "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/api"
 	ucloudauth "github.com/404tk/cloudtoolkit/pkg/providers/ucloud/auth"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 const defaultBucketEndpointFormat = "https://%s.%s.ufileos.com"
@@ -37,7 +39,7 @@ type FileClientOption func(*FileClient)
 func NewFileClient(cred ucloudauth.Credential, opts ...FileClientOption) *FileClient {
 	c := &FileClient{
 		credential:  cred,
-		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		httpClient:  ratelimit.WrapClient("ucloud", telemetry.WrapClient("ucloud", &http.Client{Timeout: 30 * time.Second})),
 		retryPolicy: ucloudapi.DefaultRetryPolicy(),
 		now:         time.Now,
 		endpointFmt: defaultBucketEndpointFormat,
@@ -24,9 +24,18 @@ func (d *Driver) fileClient() *FileClient {
 }
 
 // ListObjects fans out across the resolved bucket→region map and returns one
-// schema.BucketResult per bucket containing the page of objects discovered.
+// schema.BucketResult per bucket containing every object discovered.
 // Mirrors the alibaba/aws/tencent BucketDump shape.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's PrefixFileList markers and hands the
+// objects matching q to visit. A failing bucket keeps its error in the
+// result message, as TotalObjects does.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	out := make([]schema.BucketResult, 0, len(buckets))
 	if len(buckets) == 0 {
 		return out, nil
@@ -42,29 +51,44 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 		if region == "" {
 			region = strings.TrimSpace(d.Region)
 		}
-		resp, err := client.PrefixFileList(ctx, bucket, region, "", "", listObjectsLimit)
-		result := schema.BucketResult{
-			Action:     "list",
-			BucketName: bucket,
-		}
-		if err != nil {
-			result.Message = err.Error()
-			out = append(out, result)
-			continue
+		walk := schema.NewObjectWalk(q, visit)
+		marker := ""
+		var listErr error
+	pages:
+		for {
+			resp, err := client.PrefixFileList(ctx, bucket, region, q.Prefix, marker, listObjectsLimit)
+			if err != nil {
+				listErr = err
+				break
+			}
+			for _, item := range resp.DataSet {
+				more, err := walk.Add(schema.BucketObject{
+					BucketName:   bucket,
+					Key:          item.FileName,
+					Size:         item.Size,
+					LastModified: formatModifyTime(item.ModifyTime),
+					StorageClass: item.StorageClass,
+				})
+				if err != nil {
+					return append(out, walk.Result(bucket)), err
+				}
+				if !more {
+					break pages
+				}
+			}
+			if strings.TrimSpace(resp.NextMarker) == "" || len(resp.DataSet) == 0 || ctx.Err() != nil {
+				break
+			}
+			marker = resp.NextMarker
 		}
-		objects := make([]schema.BucketObject, 0, len(resp.DataSet))
-		for _, item := range resp.DataSet {
-			objects = append(objects, schema.BucketObject{
-				BucketName:   bucket,
-				Key:          item.FileName,
-				Size:         item.Size,
-				LastModified: formatModifyTime(item.ModifyTime),
-				StorageClass: item.StorageClass,
-			})
+		result := walk.Result(bucket)
+		if listErr != nil {
+			result.Message = listErr.Error()
 		}
-		result.Objects = objects
-		result.ObjectCount = int64(len(objects))
 		out = append(out, result)
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
 	}
 	return out, nil
 }
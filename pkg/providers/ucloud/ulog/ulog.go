@@ -32,6 +32,8 @@ func (d *Driver) client() *api.Client {
 const (
 	defaultPageSize = 20
 	maxPages        = 1
+	// maxFilteredPages bounds the scan when the query filters client-side.
+	maxFilteredPages = 10
 )
 
 // DumpEvents returns recent operation-log entries. `args` may be a
@@ -41,9 +43,22 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 	if err != nil {
 		return nil, err
 	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTime, endTime))
+}
+
+// QueryEvents returns operation-log entries matching q. The time window maps
+// to BeginTime/EndTime; GetUserOperationEvents has no other filters, so the
+// rest is matched on up to maxFilteredPages pages.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	startTime, endTime := q.UnixWindow()
+	pages := maxPages
+	if q.HasFilters() {
+		pages = maxFilteredPages
+	}
+	rest := q.WithoutWindow()
 	out := make([]schema.Event, 0)
 	nextToken := ""
-	for page := 0; page < maxPages; page++ {
+	for page := 0; page < pages; page++ {
 		params := map[string]any{
 			"MaxResults": strconv.Itoa(defaultPageSize),
 		}
@@ -69,15 +84,19 @@ func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, e
 			return out, err
 		}
 		for _, ev := range resp.Events {
-			out = append(out, schema.Event{
+			event := schema.Event{
 				// Name:     ev.API,
-				Affected: operationEventAffected(ev),
-				API:      ev.API,
-				Status:   operationEventStatus(ev.IsSuccess),
-				Time:     formatOperateTime(ev.OperateTime),
-			})
+				Affected:  operationEventAffected(ev),
+				API:       ev.API,
+				Status:    operationEventStatus(ev.IsSuccess),
+				Principal: operationEventPrincipal(ev),
+				Time:      formatOperateTime(ev.OperateTime),
+			}
+			if rest.Match(event) {
+				out = append(out, event)
+			}
 		}
-		if resp.NextToken == "" {
+		if len(out) >= defaultPageSize || resp.NextToken == "" {
 			break
 		}
 		nextToken = resp.NextToken
@@ -102,6 +121,13 @@ func operationEventAffected(ev api.UCloudOperationEvent) string {
 	return ev.UserName
 }
 
+func operationEventPrincipal(ev api.UCloudOperationEvent) string {
+	if ev.UserEmail != "" {
+		return ev.UserEmail
+	}
+	return ev.UserName
+}
+
 func operationEventStatus(success bool) string {
 	if success {
 		return "Success"
@@ -62,9 +62,18 @@ type LookupConditionForLookupEventsInput struct {
 	LookupConditionValue *string `json:"LookupConditionValue,omitempty"`
 }
 
+// LookupCondition returns a LookupEvents condition for key and value.
+func LookupCondition(key, value string) *LookupConditionForLookupEventsInput {
+	return &LookupConditionForLookupEventsInput{
+		LookupConditionKey:   stringPtr(key),
+		LookupConditionValue: stringPtr(value),
+	}
+}
+
 // LookupAuditEvents calls the Volcengine CloudTrail LookupEvents action.
 // `start` and `end` are unix seconds (0 = unset, fall back to service default).
-func (c *Client) LookupAuditEvents(ctx context.Context, region string, start, end int64, pageSize int, nextToken, accessKey string) (LookupEventsResponse, error) {
+// conditions are sent after the access key one.
+func (c *Client) LookupAuditEvents(ctx context.Context, region string, start, end int64, pageSize int, nextToken, accessKey string, conditions ...*LookupConditionForLookupEventsInput) (LookupEventsResponse, error) {
 	input := lookupEventsInput{}
 	if start > 0 {
 		input.StartTime = &start
@@ -80,13 +89,9 @@ func (c *Client) LookupAuditEvents(ctx context.Context, region string, start, en
 		input.NextToken = &nextToken
 	}
 	if accessKey != "" {
-		input.LookupConditions = []*LookupConditionForLookupEventsInput{
-			{
-				LookupConditionKey:   stringPtr("AccessKeyID"),
-				LookupConditionValue: stringPtr(accessKey),
-			},
-		}
+		input.LookupConditions = []*LookupConditionForLookupEventsInput{LookupCondition("AccessKeyID", accessKey)}
 	}
+	input.LookupConditions = append(input.LookupConditions, conditions...)
 	body, err := json.Marshal(input)
 	if err != nil {
 		return LookupEventsResponse{}, err
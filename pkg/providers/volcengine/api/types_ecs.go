@@ -37,6 +37,12 @@ type ECSInstance struct {
 	OSType            string                `json:"OsType"`
 	EipAddress        ECSEipAddress         `json:"EipAddress"`
 	NetworkInterfaces []ECSNetworkInterface `json:"NetworkInterfaces"`
+	Tags              []ECSTag              `json:"Tags"`
+}
+
+type ECSTag struct {
+	Key   string `json:"Key"`
+	Value string `json:"Value"`
 }
 
 type ECSEipAddress struct {
@@ -109,6 +115,7 @@ type ECSInvocationResult struct {
 	ErrorInfo              string `json:"ErrorInfo"`
 	ErrorMessage           string `json:"ErrorMessage"`
 	ErrorCode              string `json:"ErrorCode"`
+	ExitCode               *int   `json:"ExitCode"`
 }
 
 func (r ECSInvocationResult) Status() string {
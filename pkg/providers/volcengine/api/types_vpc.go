@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const vpcAPIVersion = "2020-04-01"
+
+type DescribeSecurityGroupsResponse struct {
+	ResponseMetadata ResponseMetadata `json:"ResponseMetadata"`
+	Result           struct {
+		NextToken      string             `json:"NextToken"`
+		SecurityGroups []VPCSecurityGroup `json:"SecurityGroups"`
+	} `json:"Result"`
+}
+
+type VPCSecurityGroup struct {
+	SecurityGroupID   string `json:"SecurityGroupId"`
+	SecurityGroupName string `json:"SecurityGroupName"`
+	Description       string `json:"Description"`
+	VpcID             string `json:"VpcId"`
+}
+
+type DescribeSecurityGroupAttributesResponse struct {
+	ResponseMetadata ResponseMetadata `json:"ResponseMetadata"`
+	Result           struct {
+		SecurityGroupID string                  `json:"SecurityGroupId"`
+		VpcID           string                  `json:"VpcId"`
+		Permissions     []VPCSecurityPermission `json:"Permissions"`
+	} `json:"Result"`
+}
+
+// VPCSecurityPermission is one security group rule. PortStart/PortEnd are -1
+// when the rule covers every port.
+type VPCSecurityPermission struct {
+	Direction     string `json:"Direction"`
+	Policy        string `json:"Policy"`
+	Protocol      string `json:"Protocol"`
+	PortStart     int    `json:"PortStart"`
+	PortEnd       int    `json:"PortEnd"`
+	CidrIP        string `json:"CidrIp"`
+	SourceGroupID string `json:"SourceGroupId"`
+	PrefixListID  string `json:"PrefixListId"`
+	Priority      int    `json:"Priority"`
+	Description   string `json:"Description"`
+}
+
+type DescribeNetworkInterfacesResponse struct {
+	ResponseMetadata ResponseMetadata `json:"ResponseMetadata"`
+	Result           struct {
+		NextToken            string                `json:"NextToken"`
+		NetworkInterfaceSets []VPCNetworkInterface `json:"NetworkInterfaceSets"`
+	} `json:"Result"`
+}
+
+type VPCNetworkInterface struct {
+	NetworkInterfaceID string   `json:"NetworkInterfaceId"`
+	DeviceID           string   `json:"DeviceId"`
+	SecurityGroupIDs   []string `json:"SecurityGroupIds"`
+}
+
+func (c *Client) DescribeSecurityGroups(ctx context.Context, region string, maxResults int32, nextToken string) (DescribeSecurityGroupsResponse, error) {
+	query := url.Values{}
+	query.Set("MaxResults", strconv.FormatInt(int64(maxResults), 10))
+	setTrimmedQueryValue(query, "NextToken", nextToken)
+	var out DescribeSecurityGroupsResponse
+	err := c.DoOpenAPI(ctx, Request{
+		Service:    "vpc",
+		Version:    vpcAPIVersion,
+		Action:     "DescribeSecurityGroups",
+		Method:     http.MethodGet,
+		Region:     region,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &out)
+	return out, err
+}
+
+func (c *Client) DescribeSecurityGroupAttributes(ctx context.Context, region, groupID string) (DescribeSecurityGroupAttributesResponse, error) {
+	query := url.Values{}
+	query.Set("SecurityGroupId", groupID)
+	var out DescribeSecurityGroupAttributesResponse
+	err := c.DoOpenAPI(ctx, Request{
+		Service:    "vpc",
+		Version:    vpcAPIVersion,
+		Action:     "DescribeSecurityGroupAttributes",
+		Method:     http.MethodGet,
+		Region:     region,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &out)
+	return out, err
+}
+
+func (c *Client) DescribeNetworkInterfaces(ctx context.Context, region string, maxResults int32, nextToken string) (DescribeNetworkInterfacesResponse, error) {
+	query := url.Values{}
+	query.Set("MaxResults", strconv.FormatInt(int64(maxResults), 10))
+	setTrimmedQueryValue(query, "NextToken", nextToken)
+	var out DescribeNetworkInterfacesResponse
+	err := c.DoOpenAPI(ctx, Request{
+		Service:    "vpc",
+		Version:    vpcAPIVersion,
+		Action:     "DescribeNetworkInterfaces",
+		Method:     http.MethodGet,
+		Region:     region,
+		Path:       "/",
+		Query:      query,
+		Idempotent: true,
+	}, &out)
+	return out, err
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -22,37 +23,64 @@ type Driver struct {
 const (
 	pageSize = 20
 	maxPages = 1
+	// maxFilteredPages bounds the scan when the query filters client-side.
+	maxFilteredPages = 10
 )
 
+// apiNamePattern matches API names such as RunInstances, as opposed to the
+// localized EventNameDisplay the Name filter also accepts.
+var apiNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9]*$`)
+
 // DumpEvents returns recent Audit events. `args` may be a `<startUnix>:<endUnix>`
 // time window, "all", or empty (service default lookback).
 func (d *Driver) DumpEvents(ctx context.Context, args string) ([]schema.Event, error) {
-	if d == nil || d.Client == nil {
-		return nil, errors.New("volcengine audit: nil api client")
-	}
 	startTime, endTime, err := parseTimeWindow(args)
 	if err != nil {
 		return nil, err
 	}
+	return d.QueryEvents(ctx, schema.EventWindow(startTime, endTime))
+}
+
+// QueryEvents returns Audit events matching q. The window and an API name go
+// to LookupEvents; the other filters are matched on the returned pages.
+func (d *Driver) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	if d == nil || d.Client == nil {
+		return nil, errors.New("volcengine audit: nil api client")
+	}
+	startTime, endTime := q.UnixWindow()
+	var conditions []*api.LookupConditionForLookupEventsInput
+	if apiNamePattern.MatchString(q.Name) {
+		conditions = append(conditions, api.LookupCondition("EventName", q.Name))
+	}
+	pages := maxPages
+	if q.HasFilters() {
+		pages = maxFilteredPages
+	}
+	rest := q.WithoutWindow()
 	out := make([]schema.Event, 0)
 	nextToken := ""
-	for page := 0; page < maxPages; page++ {
-		resp, err := d.Client.LookupAuditEvents(ctx, d.Region, startTime, endTime, pageSize, nextToken, d.AccessKey)
+	for page := 0; page < pages; page++ {
+		resp, err := d.Client.LookupAuditEvents(ctx, d.Region, startTime, endTime, pageSize, nextToken, d.AccessKey, conditions...)
 		if err != nil {
 			return out, err
 		}
 		for _, ev := range resp.Result.Trails {
-			out = append(out, schema.Event{
+			event := schema.Event{
 				// Id:        ev.EventID,
-				Name:     ev.EventNameDisplay,
-				API:      ev.EventName,
-				Status:   auditEventStatus(ev),
-				SourceIp: ev.SourceIPAddress,
+				Name:      ev.EventNameDisplay,
+				Affected:  auditEventResource(ev),
+				API:       ev.EventName,
+				Status:    auditEventStatus(ev),
+				SourceIp:  ev.SourceIPAddress,
+				Principal: ev.UserName,
 				// AccessKey: ev.AccessKeyID,
 				Time: ev.EventTime,
-			})
+			}
+			if rest.Match(event) {
+				out = append(out, event)
+			}
 		}
-		if resp.Result.NextToken == "" {
+		if len(out) >= pageSize || resp.Result.NextToken == "" {
 			break
 		}
 		nextToken = resp.Result.NextToken
@@ -74,6 +102,15 @@ func auditEventStatus(ev api.AuditEvent) string {
 	return "Success"
 }
 
+func auditEventResource(ev api.AuditEvent) string {
+	for _, res := range ev.RelatedResources {
+		if res.ResourceID != "" {
+			return res.ResourceID
+		}
+	}
+	return ""
+}
+
 func parseTimeWindow(args string) (int64, int64, error) {
 	args = strings.TrimSpace(args)
 	if args == "" || args == "all" {
@@ -4,7 +4,7 @@ import (
 	"context"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
-	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
 type Driver struct {
@@ -12,19 +12,24 @@ type Driver struct {
 	Region string
 }
 
-func (d *Driver) QueryAccountBalance(ctx context.Context) {
+func (d *Driver) QueryAccountBalance(ctx context.Context) []schema.Balance {
 	select {
 	case <-ctx.Done():
-		return
+		return nil
 	default:
 	}
 	if d.Client == nil {
-		return
+		return nil
 	}
 	resp, err := d.Client.QueryBalanceAcct(ctx, d.requestRegion())
-	if err == nil && resp.Result.AvailableBalance != "" {
-		logger.Warning("Available cash amount:", resp.Result.AvailableBalance)
+	if err != nil || resp.Result.AvailableBalance == "" {
+		return nil
 	}
+	return []schema.Balance{{
+		Kind:     schema.BalanceCredit,
+		Amount:   resp.Result.AvailableBalance,
+		Currency: "CNY",
+	}}
 }
 
 func (d *Driver) requestRegion() string {
@@ -4,33 +4,43 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
 func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
-	ctx := context.Background()
-	client, err := d.requireClient()
+	result, err := d.Invoke(context.Background(), instanceID, osType, cmd)
 	if err != nil {
 		logger.Error(err)
 		return ""
 	}
+	return result.Output
+}
+
+// Invoke runs cmd through Cloud Assistant and returns the invocation's
+// decoded output and exit code. A non-success status is returned as an
+// error.
+func (d *Driver) Invoke(ctx context.Context, instanceID, osType, cmd string) (schema.CommandResult, error) {
+	client, err := d.requireClient()
+	if err != nil {
+		return schema.CommandResult{}, err
+	}
 
 	commandType, ok := resolveCommandType(osType)
 	if !ok {
-		logger.Error("Unknown ostype", osType)
-		return ""
+		return schema.CommandResult{}, fmt.Errorf("Unknown ostype %s", osType)
 	}
 
 	region := d.requestRegion()
 	if err := d.ensureCloudAssistantRunning(ctx, client, instanceID); err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 	commandContent := base64.StdEncoding.EncodeToString([]byte(cmd))
 
@@ -44,16 +54,14 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 		"Base64",
 	)
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 
 	commandID := strings.TrimSpace(createResp.Result.CommandID)
 	if commandID == "" {
-		logger.Error("Missing command id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing command id.")
 	}
-	defer d.deleteCommand(ctx, client, commandID)
+	defer d.deleteCommand(context.WithoutCancel(ctx), client, commandID)
 
 	invocationName := buildCloudAssistantName("ctk")
 	invokeResp, err := client.InvokeCommand(
@@ -64,14 +72,12 @@ func (d *Driver) RunCommand(instanceID, osType, cmd string) string {
 		[]string{instanceID},
 	)
 	if err != nil {
-		logger.Error(err)
-		return ""
+		return schema.CommandResult{}, err
 	}
 
 	invocationID := strings.TrimSpace(invokeResp.Result.InvocationID)
 	if invocationID == "" {
-		logger.Error("Missing invocation id.")
-		return ""
+		return schema.CommandResult{}, errors.New("Missing invocation id.")
 	}
 
 	return d.describeInvocationResults(ctx, client, instanceID, commandID, invocationID)
@@ -105,7 +111,7 @@ func (d *Driver) ensureCloudAssistantRunning(ctx context.Context, client *api.Cl
 	return fmt.Errorf("cloud assistant agent status is %s, command execution requires RUNNING", status)
 }
 
-func (d *Driver) describeInvocationResults(ctx context.Context, client *api.Client, instanceID, commandID, invocationID string) string {
+func (d *Driver) describeInvocationResults(ctx context.Context, client *api.Client, instanceID, commandID, invocationID string) (schema.CommandResult, error) {
 	attempts := 0
 	lastStatus := ""
 	lastMessage := ""
@@ -114,41 +120,42 @@ func (d *Driver) describeInvocationResults(ctx context.Context, client *api.Clie
 		attempts++
 		resp, err := client.DescribeInvocationResults(ctx, d.requestRegion(), invocationID, commandID, instanceID, 1)
 		if err != nil {
-			logger.Error(err)
-			return ""
+			return schema.CommandResult{}, err
 		}
 		if len(resp.Result.InvocationResults) == 0 {
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Missing invocation result.")
-			return ""
+			return schema.CommandResult{}, errors.New("Missing invocation result.")
 		}
 
 		result := resp.Result.InvocationResults[0]
 		lastStatus = strings.ToUpper(result.Status())
 		lastMessage = result.Message()
-		switch status := strings.ToUpper(result.Status()); status {
+		status := strings.ToUpper(result.Status())
+		switch status {
 		case "PENDING", "RUNNING", "CREATED", "DELIVERING", "IN_PROGRESS":
 			if attempts < d.pollLimit() {
 				continue
 			}
-			logger.Error("Timeout: Wait 20s by default. Last status:", lastStatus, lastMessage)
-			return ""
+			return schema.CommandResult{}, fmt.Errorf("Timeout: Wait 20s by default. Last status: %s %s", lastStatus, lastMessage)
+		}
+		out := schema.CommandResult{
+			Output:   decodeInvocationOutput(result.Output),
+			ExitCode: result.ExitCode,
+			Status:   status,
+		}
+		switch status {
 		case "SUCCESS", "FINISHED", "SUCCEEDED":
-			return decodeInvocationOutput(result.Output)
-		default:
-			if message := result.Message(); message != "" && result.ErrorCode != "" {
-				logger.Error("Exception status: " + status + " - " + result.ErrorCode + " - " + message)
-				return ""
-			}
-			if message := result.Message(); message != "" {
-				logger.Error("Exception status: " + status + " - " + message)
-				return ""
-			}
-			logger.Error("Exception status: " + status)
-			return ""
+			return out, nil
+		}
+		if message := result.Message(); message != "" && result.ErrorCode != "" {
+			return out, errors.New("Exception status: " + status + " - " + result.ErrorCode + " - " + message)
+		}
+		if message := result.Message(); message != "" {
+			return out, errors.New("Exception status: " + status + " - " + message)
 		}
+		return out, errors.New("Exception status: " + status)
 	}
 }
 
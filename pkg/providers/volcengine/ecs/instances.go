@@ -140,6 +140,7 @@ func (d *Driver) listRegion(ctx context.Context, client *api.Client, region stri
 				OSType:      i.OSType,
 				Public:      ipv4 != "",
 				Region:      region,
+				Tags:        tagMap(i.Tags),
 			})
 		}
 		done := len(resp.Result.Instances) < 100 || strings.TrimSpace(resp.Result.NextToken) == ""
@@ -176,3 +177,14 @@ func (d *Driver) requestRegion() string {
 	}
 	return region
 }
+
+func tagMap(tags []api.ECSTag) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tags))
+	for _, tag := range tags {
+		out[tag.Key] = tag.Value
+	}
+	return out
+}
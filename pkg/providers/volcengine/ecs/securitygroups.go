@@ -0,0 +1,147 @@
+package ecs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/paginate"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/regionrun"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/cloudtoolkit/utils/processbar"
+)
+
+// GetSecurityGroups lists VPC security groups per region with their rules.
+// Attached resources come from the network interfaces that reference each
+// group: the owning instance ID, or the interface ID when it is detached.
+func (d *Driver) GetSecurityGroups(ctx context.Context) ([]schema.SecurityGroup, error) {
+	list := []schema.SecurityGroup{}
+	logger.Info("List VPC security groups ...")
+	client, err := d.requireClient()
+	if err != nil {
+		return list, err
+	}
+	regions, err := d.getRegions(ctx, client)
+	if err != nil {
+		logger.Error("List regions failed.")
+		return list, err
+	}
+
+	tracker := processbar.NewRegionTracker()
+	defer tracker.Finish()
+	got, regionErrs := regionrun.ForEach(ctx, regions, 0, tracker, func(ctx context.Context, r string) ([]schema.SecurityGroup, error) {
+		return listSecurityGroups(ctx, client, r)
+	})
+	list = append(list, got...)
+	return list, regionrun.Wrap(regionErrs)
+}
+
+func listSecurityGroups(ctx context.Context, client *api.Client, region string) ([]schema.SecurityGroup, error) {
+	groups, err := paginate.Fetch[api.VPCSecurityGroup, string](ctx, func(ctx context.Context, token string) (paginate.Page[api.VPCSecurityGroup, string], error) {
+		resp, err := client.DescribeSecurityGroups(ctx, region, 100, token)
+		if err != nil {
+			return paginate.Page[api.VPCSecurityGroup, string]{}, err
+		}
+		return paginate.Page[api.VPCSecurityGroup, string]{
+			Items: resp.Result.SecurityGroups,
+			Next:  resp.Result.NextToken,
+			Done:  strings.TrimSpace(resp.Result.NextToken) == "",
+		}, nil
+	})
+	if err != nil || len(groups) == 0 {
+		return nil, err
+	}
+	attached, err := groupAttachments(ctx, client, region)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]schema.SecurityGroup, 0, len(groups))
+	for _, group := range groups {
+		attrs, err := client.DescribeSecurityGroupAttributes(ctx, region, group.SecurityGroupID)
+		if err != nil {
+			return out, err
+		}
+		item := schema.SecurityGroup{
+			GroupID:   group.SecurityGroupID,
+			GroupName: group.SecurityGroupName,
+			VpcID:     group.VpcID,
+			Region:    region,
+			Attached:  attached[group.SecurityGroupID],
+		}
+		for _, perm := range attrs.Result.Permissions {
+			item.Rules = append(item.Rules, convertPermission(perm))
+		}
+		item.FlagExposure()
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+func groupAttachments(ctx context.Context, client *api.Client, region string) (map[string][]string, error) {
+	enis, err := paginate.Fetch[api.VPCNetworkInterface, string](ctx, func(ctx context.Context, token string) (paginate.Page[api.VPCNetworkInterface, string], error) {
+		resp, err := client.DescribeNetworkInterfaces(ctx, region, 100, token)
+		if err != nil {
+			return paginate.Page[api.VPCNetworkInterface, string]{}, err
+		}
+		return paginate.Page[api.VPCNetworkInterface, string]{
+			Items: resp.Result.NetworkInterfaceSets,
+			Next:  resp.Result.NextToken,
+			Done:  strings.TrimSpace(resp.Result.NextToken) == "",
+		}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]map[string]struct{})
+	for _, eni := range enis {
+		target := eni.DeviceID
+		if target == "" {
+			target = eni.NetworkInterfaceID
+		}
+		for _, groupID := range eni.SecurityGroupIDs {
+			if seen[groupID] == nil {
+				seen[groupID] = make(map[string]struct{})
+			}
+			seen[groupID][target] = struct{}{}
+		}
+	}
+	out := make(map[string][]string, len(seen))
+	for groupID, targets := range seen {
+		for target := range targets {
+			out[groupID] = append(out[groupID], target)
+		}
+		sort.Strings(out[groupID])
+	}
+	return out, nil
+}
+
+func convertPermission(perm api.VPCSecurityPermission) schema.FirewallRule {
+	action := "allow"
+	if strings.EqualFold(perm.Policy, "drop") {
+		action = "deny"
+	}
+	protocol := strings.ToLower(strings.TrimSpace(perm.Protocol))
+	if protocol == "" {
+		protocol = "all"
+	}
+	peer := strings.TrimSpace(perm.CidrIP)
+	if peer == "" {
+		peer = strings.TrimSpace(perm.SourceGroupID)
+	}
+	if peer == "" {
+		peer = strings.TrimSpace(perm.PrefixListID)
+	}
+	return schema.FirewallRule{
+		Direction:   strings.ToLower(strings.TrimSpace(perm.Direction)),
+		Action:      action,
+		Protocol:    protocol,
+		PortRange:   schema.PortRange(perm.PortStart, perm.PortEnd),
+		CIDR:        peer,
+		Priority:    strconv.Itoa(perm.Priority),
+		Description: perm.Description,
+	}
+}
@@ -0,0 +1,63 @@
+package ecs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDriverGetSecurityGroupsMapsPermissionsAndInterfaces(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("Action") {
+		case "DescribeSecurityGroups":
+			_, _ = w.Write([]byte(`{"Result":{"SecurityGroups":[{"SecurityGroupId":"sg-1","SecurityGroupName":"web","VpcId":"vpc-1"}]}}`))
+		case "DescribeNetworkInterfaces":
+			_, _ = w.Write([]byte(`{"Result":{"NetworkInterfaceSets":[
+				{"NetworkInterfaceId":"eni-2","DeviceId":"i-1","SecurityGroupIds":["sg-1"]},
+				{"NetworkInterfaceId":"eni-1","DeviceId":"i-1","SecurityGroupIds":["sg-1"]},
+				{"NetworkInterfaceId":"eni-9","SecurityGroupIds":["sg-1"]}
+			]}}`))
+		case "DescribeSecurityGroupAttributes":
+			if got := r.URL.Query().Get("SecurityGroupId"); got != "sg-1" {
+				t.Fatalf("unexpected SecurityGroupId: %s", got)
+			}
+			_, _ = w.Write([]byte(`{"Result":{"SecurityGroupId":"sg-1","Permissions":[
+				{"Direction":"ingress","Policy":"accept","Protocol":"tcp","PortStart":6379,"PortEnd":6379,"CidrIp":"0.0.0.0/0","Priority":1},
+				{"Direction":"ingress","Policy":"drop","Protocol":"all","PortStart":-1,"PortEnd":-1,"SourceGroupId":"sg-2","Priority":100},
+				{"Direction":"egress","Policy":"accept","Protocol":"all","PortStart":-1,"PortEnd":-1,"CidrIp":"0.0.0.0/0","Priority":1}
+			]}}`))
+		default:
+			t.Fatalf("unexpected action: %s", r.URL.Query().Get("Action"))
+		}
+	}))
+	defer server.Close()
+
+	driver := &Driver{Client: newTestClient(server.URL), Region: "cn-beijing"}
+	groups, err := driver.GetSecurityGroups(context.Background())
+	if err != nil {
+		t.Fatalf("GetSecurityGroups() error = %v", err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("unexpected group count: %d", len(groups))
+	}
+	group := groups[0]
+	if group.GroupID != "sg-1" || group.VpcID != "vpc-1" || group.Region != "cn-beijing" {
+		t.Fatalf("unexpected group: %+v", group)
+	}
+	if len(group.Attached) != 2 || group.Attached[0] != "eni-9" || group.Attached[1] != "i-1" {
+		t.Fatalf("unexpected attachments: %v", group.Attached)
+	}
+	if len(group.Rules) != 3 {
+		t.Fatalf("unexpected rules: %+v", group.Rules)
+	}
+	if r := group.Rules[0]; r.PortRange != "6379" || r.Priority != "1" || !r.Exposed {
+		t.Fatalf("unexpected redis rule: %+v", r)
+	}
+	if r := group.Rules[1]; r.Action != "deny" || r.PortRange != "all" || r.CIDR != "sg-2" || r.Exposed {
+		t.Fatalf("unexpected drop rule: %+v", r)
+	}
+	if r := group.Rules[2]; r.Direction != "egress" || r.Exposed {
+		t.Fatalf("unexpected egress rule: %+v", r)
+	}
+}
@@ -18,7 +18,7 @@ func (d *Driver) CreateAccount(ctx context.Context, instanceID string) (schema.D
 	if d == nil || d.Client == nil {
 		return schema.DatabaseActionResult{}, errNilAPIClient
 	}
-	user, password, err := parseRDSAccount()
+	user, password, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -40,7 +40,7 @@ func (d *Driver) DeleteAccount(ctx context.Context, instanceID string) (schema.D
 	if d == nil || d.Client == nil {
 		return schema.DatabaseActionResult{}, errNilAPIClient
 	}
-	user, _, err := parseRDSAccount()
+	user, _, err := parseRDSAccount(ctx)
 	if err != nil {
 		return schema.DatabaseActionResult{}, err
 	}
@@ -71,8 +71,8 @@ func serviceForInstance(instanceID string) string {
 	}
 }
 
-func parseRDSAccount() (string, string, error) {
-	accountName, accountPassword, ok := strings.Cut(env.Active().RDSAccount, ":")
+func parseRDSAccount(ctx context.Context) (string, string, error) {
+	accountName, accountPassword, ok := strings.Cut(env.From(ctx).RDSAccount, ":")
 	if !ok || strings.TrimSpace(accountName) == "" || strings.TrimSpace(accountPassword) == "" {
 		return "", "", fmt.Errorf("RDS account metadata is invalid: expected username:password")
 	}
@@ -3,17 +3,25 @@ package replay
 import (
 	"net/http"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine"
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/tos"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 )
 
 func ClientConfig() volcengine.ClientConfig {
-	transport := newTransport()
-	httpClient := &http.Client{Transport: transport}
+	cfg := HTTPClientConfig(&http.Client{Transport: faults.Wrap(newTransport())})
+	cfg.SkipCredentialCache = true
+	return cfg
+}
+
+// HTTPClientConfig routes every volcengine client through httpClient. Cassette
+// record mode and playback inject their transport here.
+func HTTPClientConfig(httpClient *http.Client) volcengine.ClientConfig {
+	httpClient = telemetry.WrapClient("volcengine", httpClient)
 	return volcengine.ClientConfig{
-		APIOptions:          []api.Option{api.WithHTTPClient(httpClient)},
-		TOSOptions:          []tos.Option{tos.WithHTTPClient(httpClient)},
-		SkipCredentialCache: true,
+		APIOptions: []api.Option{api.WithHTTPClient(httpClient)},
+		TOSOptions: []tos.Option{tos.WithHTTPClient(httpClient)},
 	}
 }
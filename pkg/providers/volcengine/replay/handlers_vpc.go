@@ -0,0 +1,96 @@
+package replay
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
+)
+
+type securityGroupFixture struct {
+	api.VPCSecurityGroup
+	Region      string
+	Interfaces  []api.VPCNetworkInterface
+	Permissions []api.VPCSecurityPermission
+}
+
+// demoSecurityGroups leaves Redis open to the internet on the Beijing app
+// host and SSH restricted to an office range on the Guangzhou jump host.
+var demoSecurityGroups = []securityGroupFixture{
+	{
+		VPCSecurityGroup: api.VPCSecurityGroup{
+			SecurityGroupID:   "sg-volc001",
+			SecurityGroupName: "ctk-demo-app",
+			Description:       "app tier",
+			VpcID:             "vpc-volc001",
+		},
+		Region:     "cn-beijing",
+		Interfaces: []api.VPCNetworkInterface{{NetworkInterfaceID: "eni-volc001", DeviceID: "i-volc001"}},
+		Permissions: []api.VPCSecurityPermission{
+			{Direction: "ingress", Policy: "accept", Protocol: "tcp", PortStart: 443, PortEnd: 443, CidrIP: "0.0.0.0/0", Priority: 1, Description: "https"},
+			{Direction: "ingress", Policy: "accept", Protocol: "tcp", PortStart: 6379, PortEnd: 6379, CidrIP: "0.0.0.0/0", Priority: 1, Description: "redis debug"},
+			{Direction: "egress", Policy: "accept", Protocol: "all", PortStart: -1, PortEnd: -1, CidrIP: "0.0.0.0/0", Priority: 1},
+		},
+	},
+	{
+		VPCSecurityGroup: api.VPCSecurityGroup{
+			SecurityGroupID:   "sg-volc002",
+			SecurityGroupName: "ctk-demo-jump",
+			Description:       "bastion",
+			VpcID:             "vpc-volc002",
+		},
+		Region:     "cn-guangzhou",
+		Interfaces: []api.VPCNetworkInterface{{NetworkInterfaceID: "eni-volc002", DeviceID: "i-volc002"}},
+		Permissions: []api.VPCSecurityPermission{
+			{Direction: "ingress", Policy: "accept", Protocol: "tcp", PortStart: 22, PortEnd: 22, CidrIP: "198.51.100.0/24", Priority: 1, Description: "office ssh"},
+		},
+	},
+}
+
+// handleVPC serves the cloudlist `securitygroup` actions.
+func (t *transport) handleVPC(req *http.Request, action string) (*http.Response, error) {
+	region := requestRegion(req)
+	switch action {
+	case "DescribeSecurityGroups":
+		resp := api.DescribeSecurityGroupsResponse{}
+		resp.ResponseMetadata.RequestID = "req-vpc-security-groups"
+		for _, group := range demoSecurityGroups {
+			if group.Region == region {
+				resp.Result.SecurityGroups = append(resp.Result.SecurityGroups, group.VPCSecurityGroup)
+			}
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case "DescribeNetworkInterfaces":
+		resp := api.DescribeNetworkInterfacesResponse{}
+		resp.ResponseMetadata.RequestID = "req-vpc-network-interfaces"
+		for _, group := range demoSecurityGroups {
+			if group.Region != region {
+				continue
+			}
+			for _, eni := range group.Interfaces {
+				eni.SecurityGroupIDs = []string{group.SecurityGroupID}
+				resp.Result.NetworkInterfaceSets = append(resp.Result.NetworkInterfaceSets, eni)
+			}
+		}
+		return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+	case "DescribeSecurityGroupAttributes":
+		groupID := strings.TrimSpace(req.URL.Query().Get("SecurityGroupId"))
+		for _, group := range demoSecurityGroups {
+			if group.SecurityGroupID != groupID {
+				continue
+			}
+			resp := api.DescribeSecurityGroupAttributesResponse{}
+			resp.ResponseMetadata.RequestID = "req-vpc-security-group-attributes"
+			resp.Result.SecurityGroupID = group.SecurityGroupID
+			resp.Result.VpcID = group.VpcID
+			resp.Result.Permissions = group.Permissions
+			return demoreplay.JSONResponse(req, http.StatusOK, resp), nil
+		}
+		return openAPIErrorResponse(req, http.StatusNotFound, "InvalidSecurityGroup.NotFound",
+			fmt.Sprintf("The specified security group %s does not exist.", groupID)), nil
+	}
+	return openAPIErrorResponse(req, http.StatusNotFound, "InvalidAction",
+		fmt.Sprintf("unsupported vpc action: %s", action)), nil
+}
@@ -132,6 +132,8 @@ func (t *transport) handleOpenAPI(req *http.Request, body []byte) (*http.Respons
 		return t.handleDNS(req, action, body)
 	case "ecs":
 		return t.handleECS(req, action)
+	case "vpc":
+		return t.handleVPC(req, action)
 	case api.ServiceRDSMySQL:
 		return t.handleRDSMySQL(req, action, body)
 	case api.ServiceRDSPostgreSQL:
@@ -682,6 +684,9 @@ func (t *transport) handleTOS(req *http.Request, body []byte) (*http.Response, e
 		if _, hasACL := query["acl"]; hasACL {
 			return t.handleTOSBucketACL(req, bucket, region)
 		}
+		if _, hasPolicy := query["policy"]; hasPolicy {
+			return tosErrorResponse(req, http.StatusNotFound, "NoSuchBucketPolicy", "The bucket policy does not exist."), nil
+		}
 		if query.Get("list-type") == "2" {
 			return t.handleListObjects(req, bucket, region, query)
 		}
@@ -1037,6 +1042,8 @@ func openAPIService(host string) string {
 		return "cloudtrail"
 	case strings.HasPrefix(host, "ecs."):
 		return "ecs"
+	case strings.HasPrefix(host, "vpc."):
+		return "vpc"
 	case strings.HasPrefix(host, "rds-mysql."):
 		return api.ServiceRDSMySQL
 	case strings.HasPrefix(host, "rds-postgresql."):
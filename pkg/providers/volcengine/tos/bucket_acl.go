@@ -2,11 +2,15 @@ package tos
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/internal/exposure"
+	volcapi "github.com/404tk/cloudtoolkit/pkg/providers/volcengine/api"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 )
 
@@ -61,6 +65,29 @@ func (c *Client) GetBucketACL(ctx context.Context, bucket, region string) (strin
 	return collapseTOSGrants(out), nil
 }
 
+// GetBucketPolicy returns bucket's policy document, or nil when the bucket
+// has none.
+func (c *Client) GetBucketPolicy(ctx context.Context, bucket, region string) ([]byte, error) {
+	bucket = strings.TrimSpace(bucket)
+	if bucket == "" {
+		return nil, fmt.Errorf("volcengine tos: empty bucket")
+	}
+	query := url.Values{}
+	query.Set("policy", "")
+	var doc json.RawMessage
+	err := c.doJSON(ctx, request{
+		Method: http.MethodGet,
+		Host:   bucketHost(bucket, region),
+		Path:   "/",
+		Query:  query,
+	}, &doc)
+	var apiErr *volcapi.APIError
+	if errors.As(err, &apiErr) && apiErr.Code == "NoSuchBucketPolicy" {
+		return nil, nil
+	}
+	return doc, err
+}
+
 // PutBucketACL sets the canned ACL on bucket via the `x-tos-acl` header.
 func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) error {
 	bucket = strings.TrimSpace(bucket)
@@ -84,7 +111,8 @@ func (c *Client) PutBucketACL(ctx context.Context, bucket, region, acl string) e
 	}, nil)
 }
 
-// AuditBucketACL enumerates buckets in scope and returns their canned ACL.
+// AuditBucketACL enumerates buckets in scope and returns their canned ACL and
+// effective exposure, which also weighs the bucket policy.
 func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.BucketACLEntry, error) {
 	client, err := d.NewClient()
 	if err != nil {
@@ -108,10 +136,15 @@ func (d *Driver) AuditBucketACL(ctx context.Context, bucket string) ([]schema.Bu
 		if err != nil {
 			return out, fmt.Errorf("get acl for %s: %w", s.BucketName, err)
 		}
-		out = append(out, schema.BucketACLEntry{
+		entry := schema.BucketACLEntry{
 			Container: s.BucketName,
 			Level:     acl,
-		})
+		}
+		var verdict exposure.Verdict
+		verdict.Add("acl", exposure.ACL(acl))
+		verdict.AddPolicy(client.GetBucketPolicy(ctx, s.BucketName, region))
+		verdict.Apply(&entry)
+		out = append(out, entry)
 	}
 	return out, nil
 }
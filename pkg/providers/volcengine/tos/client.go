@@ -105,9 +105,12 @@ func (c *Client) ListBuckets(ctx context.Context, region string) (ListBucketsOut
 	return out, err
 }
 
-func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, token string, maxKeys int) (ListObjectsV2Output, error) {
+func (c *Client) ListObjectsV2(ctx context.Context, bucket, region, prefix, token string, maxKeys int) (ListObjectsV2Output, error) {
 	query := url.Values{}
 	query.Set("list-type", "2")
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
 	if maxKeys > 0 {
 		query.Set("max-keys", fmt.Sprintf("%d", maxKeys))
 	}
@@ -118,7 +118,7 @@ func TestClientListObjectsV2UsesBucketHostAndContinuationToken(t *testing.T) {
 		WithRetryPolicy(volcapi.RetryPolicy{MaxAttempts: 1}),
 	)
 
-	resp, err := client.ListObjectsV2(context.Background(), "demo-bucket", "cn-guangzhou", "next-token", 100)
+	resp, err := client.ListObjectsV2(context.Background(), "demo-bucket", "cn-guangzhou", "", "next-token", 100)
 	if err != nil {
 		t.Fatalf("ListObjectsV2() error = %v", err)
 	}
@@ -10,7 +10,17 @@ import (
 	"github.com/404tk/cloudtoolkit/utils/processbar"
 )
 
+// ListObjects returns every object of each bucket.
 func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]schema.BucketResult, error) {
+	return schema.CollectObjects(func(visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+		return d.WalkObjects(ctx, buckets, schema.ObjectQuery{}, visit)
+	})
+}
+
+// WalkObjects follows each bucket's continuation tokens and hands the
+// objects matching q to visit. A failing bucket is reported once the others
+// are listed.
+func (d *Driver) WalkObjects(ctx context.Context, buckets map[string]string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
 	client, err := d.NewClient()
 	if err != nil {
 		return nil, err
@@ -20,10 +30,11 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 	var errs []string
 	for bucket, region := range buckets {
 		token := ""
-		objects := make([]schema.BucketObject, 0)
+		walk := schema.NewObjectWalk(q, visit)
 		failed := false
+	pages:
 		for {
-			resp, err := client.ListObjectsV2(ctx, bucket, normalizeBucketRegion(region), token, 100)
+			resp, err := client.ListObjectsV2(ctx, bucket, normalizeBucketRegion(region), q.Prefix, token, 1000)
 			if err != nil {
 				errs = append(errs, fmt.Sprintf("%s: %v", bucket, err))
 				failed = true
@@ -31,13 +42,19 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 			}
 
 			for _, object := range resp.Contents {
-				objects = append(objects, schema.BucketObject{
+				more, err := walk.Add(schema.BucketObject{
 					BucketName:   bucket,
 					Key:          object.Key,
 					Size:         object.Size,
 					LastModified: object.LastModified,
 					StorageClass: object.StorageClass,
 				})
+				if err != nil {
+					return append(results, walk.Result(bucket)), err
+				}
+				if !more {
+					break pages
+				}
 			}
 
 			if !resp.IsTruncated {
@@ -52,25 +69,12 @@ func (d *Driver) ListObjects(ctx context.Context, buckets map[string]string) ([]
 
 			select {
 			case <-ctx.Done():
-				results = append(results, schema.BucketResult{
-					Action:      "list",
-					BucketName:  bucket,
-					ObjectCount: int64(len(objects)),
-					Objects:     objects,
-					Message:     fmt.Sprintf("%d objects found", len(objects)),
-				})
-				return results, nil
+				return append(results, walk.Result(bucket)), ctx.Err()
 			default:
 			}
 		}
-		if !failed || len(objects) > 0 {
-			results = append(results, schema.BucketResult{
-				Action:      "list",
-				BucketName:  bucket,
-				ObjectCount: int64(len(objects)),
-				Objects:     objects,
-				Message:     fmt.Sprintf("%d objects found", len(objects)),
-			})
+		if !failed || walk.Matched > 0 {
+			results = append(results, walk.Result(bucket))
 		}
 	}
 	if len(errs) > 0 {
@@ -113,7 +117,7 @@ func (d *Driver) countBucketObjects(ctx context.Context, bucket, region string,
 	token := ""
 	count := 0
 	for {
-		resp, err := client.ListObjectsV2(ctx, bucket, region, token, 1000)
+		resp, err := client.ListObjectsV2(ctx, bucket, region, "", token, 1000)
 		if err != nil {
 			return 0, err
 		}
@@ -19,6 +19,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/tls"
 	"github.com/404tk/cloudtoolkit/pkg/providers/volcengine/tos"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
@@ -91,8 +92,8 @@ func (p *Provider) Name() string {
 // Resources returns the provider for a resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 	collector := schema.NewResourceCollector(p.Name()).
-		Register("balance", func(ctx context.Context, _ *schema.Resources) {
-			(&billing.Driver{Client: p.apiClient, Region: p.region}).QueryAccountBalance(ctx)
+		Register("balance", func(ctx context.Context, list *schema.Resources) {
+			schema.AppendAssets(list, (&billing.Driver{Client: p.apiClient, Region: p.region}).QueryAccountBalance(ctx))
 		}).
 		Register("host", func(ctx context.Context, list *schema.Resources) {
 			d := &ecs.Driver{Client: p.apiClient, Region: p.region}
@@ -144,6 +145,12 @@ func (p *Provider) Resources(ctx context.Context) (schema.Resources, error) {
 			result, err := d.GetResource(ctx)
 			list.Sms = result
 			list.AddError("sms", err)
+		}).
+		Register("securitygroup", func(ctx context.Context, list *schema.Resources) {
+			d := &ecs.Driver{Client: p.apiClient, Region: p.region}
+			groups, err := d.GetSecurityGroups(ctx)
+			schema.AppendAssets(list, groups)
+			list.AddError("securitygroup", err)
 		})
 
 	return collector.Collect(ctx, env.From(ctx).Cloudlist)
@@ -269,6 +276,12 @@ func (p *Provider) EventDump(ctx context.Context, action, args string) (schema.E
 	}
 }
 
+// QueryEvents reads the CloudTrail events of the configured access key.
+func (p *Provider) QueryEvents(ctx context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	driver := &audit.Driver{Client: p.apiClient, Region: p.region, AccessKey: p.credential.AccessKey}
+	return driver.QueryEvents(ctx, q)
+}
+
 func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([]schema.BucketResult, error) {
 	driver := p.newTOSDriver(p.region)
 	infos, err := p.bucketInfos(context.Background(), driver, bucketName)
@@ -286,6 +299,17 @@ func (p *Provider) BucketDump(ctx context.Context, action, bucketName string) ([
 	}
 }
 
+// WalkObjects implements schema.BucketManager, resolving bucketName like
+// BucketDump's list action.
+func (p *Provider) WalkObjects(ctx context.Context, bucketName string, q schema.ObjectQuery, visit schema.ObjectVisitor) ([]schema.BucketResult, error) {
+	driver := p.newTOSDriver(p.region)
+	infos, err := p.bucketInfos(ctx, driver, bucketName)
+	if err != nil {
+		return nil, fmt.Errorf("list buckets: %w", err)
+	}
+	return driver.WalkObjects(ctx, infos, q, visit)
+}
+
 // BucketACL implements schema.BucketACLManager for volcengine TOS. `level`
 // accepts the canned TOS ACL values (private / public-read / public-read-write)
 // or friendly aliases resolved by tos.NormalizeTOSACL.
@@ -328,7 +352,8 @@ func (p *Provider) BucketACL(ctx context.Context, action, container, level strin
 // auto-detects the sub-service (mysql / postgres / mssql) from the instanceID
 // prefix; username/password come from the `rds-account-check` config.
 func (p *Provider) DBManagement(ctx context.Context, action, instanceID string) (schema.DatabaseActionResult, error) {
-	driver := &rds.Driver{Client: p.apiClient, Region: p.region}
+	region := invcache.DatabaseRegion(ctx, instanceID, p.region)
+	driver := &rds.Driver{Client: p.apiClient, Region: region}
 	switch action {
 	case "useradd":
 		return driver.CreateAccount(ctx, instanceID)
@@ -341,15 +366,19 @@ func (p *Provider) DBManagement(ctx context.Context, action, instanceID string)
 
 func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd string) (schema.CommandResult, error) {
 	if osType, command, ok := vmexecspec.Parse(cmd); ok {
-		if p.region == "" || p.region == "all" {
-			return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+		region := p.region
+		if region == "" || region == "all" {
+			host, found := p.lookupHost(ctx, instanceID)
+			if !found || host.Region == "" {
+				return schema.CommandResult{}, fmt.Errorf("headless shell requires explicit region")
+			}
+			region = host.Region
 		}
-		driver := &ecs.Driver{Client: p.apiClient, Region: p.region}
-		output := driver.RunCommand(instanceID, osType, command)
-		return schema.CommandResult{Output: output}, nil
+		driver := &ecs.Driver{Client: p.apiClient, Region: region}
+		return driver.Invoke(ctx, instanceID, osType, command)
 	}
 
-	host, ok := p.lookupHost(instanceID)
+	host, ok := p.lookupHost(ctx, instanceID)
 	if !ok {
 		return schema.CommandResult{}, fmt.Errorf("unable to resolve instance metadata, run `cloudlist` first and retry")
 	}
@@ -360,17 +389,14 @@ func (p *Provider) ExecuteCloudVMCommand(ctx context.Context, instanceID, cmd st
 	}
 
 	driver := &ecs.Driver{Client: p.apiClient, Region: host.Region}
-	output := driver.RunCommand(instanceID, host.OSType, string(command))
-	return schema.CommandResult{Output: output}, nil
+	return driver.Invoke(ctx, instanceID, host.OSType, string(command))
 }
 
-func (p *Provider) lookupHost(instanceID string) (schema.Host, bool) {
-	for _, host := range ecs.GetCacheHostList() {
-		if host.ID == instanceID || host.HostName == instanceID {
-			return host, true
-		}
-	}
-	return schema.Host{}, false
+// lookupHost resolves an instance from the persisted inventory of the
+// credential; this session's host list only answers when no inventory store
+// is attached (see invcache.ResolveHost).
+func (p *Provider) lookupHost(ctx context.Context, instanceID string) (schema.Host, bool) {
+	return invcache.ResolveHost(ctx, instanceID, ecs.GetCacheHostList())
 }
 
 func (p *Provider) bucketInfos(ctx context.Context, driver *tos.Driver, bucketName string) (map[string]string, error) {
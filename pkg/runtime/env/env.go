@@ -9,10 +9,12 @@
 //     `ctk run --timeout 5m` does not pollute REPL state or other concurrent
 //     runs.
 //
-//  2. Process-active singleton (fallback) — capability methods that lack
-//     ctx (EventDump, parseRDSAccount) read env.Active(). Replaced atomically
-//     so concurrent reads see a consistent snapshot, unlike the previous
-//     unsynchronised globals.
+//  2. Process-active singleton (fallback) — From(ctx) falls back to
+//     env.Active() when nothing is attached, so the REPL can pin its config
+//     once. Replaced atomically so concurrent reads see a consistent
+//     snapshot, unlike the previous unsynchronised globals. Code that may run
+//     concurrently with other jobs (ctk serve) must not read Active()
+//     directly.
 //
 //  3. Tests — env.SetActiveForTest pins a value and registers a cleanup that
 //     restores the previous active env, so 94 unit tests can keep using
@@ -43,6 +45,12 @@ type Env struct {
 	IAMUserCheck string
 	RDSAccount   string
 	RunTimeout   time.Duration
+	// RateLimit layers ratelimit.Parse rules over the built-in per-cloud
+	// request quotas; "off" disables client-side limiting.
+	RateLimit string
+	// ConfineOutput keeps payload output files under LogDir. Set for jobs
+	// submitted to `ctk serve`, whose metadata comes from a remote caller.
+	ConfineOutput bool
 }
 
 // Clone returns a deep copy. Use when constructing a per-run override so the
@@ -0,0 +1,352 @@
+// Package invcache persists the hosts and databases of the last cloudlist
+// run per credential, so `shell <instance>` and rds-account-check can resolve
+// an instance's region and OS type after a restart instead of asking for a
+// fresh `cloudlist`.
+//
+// Entries are keyed by the credential UUID the session cache already uses
+// (utils/cache.CredentialUUID) and stored one file per credential under
+// ~/.config/cloudtoolkit/inventory. Each asset kind carries its own timestamp
+// and is ignored once older than the store's TTL.
+//
+// Providers never see the UUID: the payload layer attaches a Scope to the
+// context and providers resolve instances through ResolveHost /
+// ResolveDatabase, which prefer the store over any in-process list.
+package invcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+// DefaultTTL bounds how long a cached inventory is trusted.
+const DefaultTTL = 24 * time.Hour
+
+// TTLEnv overrides DefaultTTL for the default store (e.g. "2h", "0" to
+// disable the cache).
+const TTLEnv = "CTK_INVENTORY_TTL"
+
+// Entry is the cached inventory of one credential.
+type Entry struct {
+	Credential  string            `json:"credential_uuid"`
+	Provider    string            `json:"provider"`
+	Hosts       []schema.Host     `json:"hosts,omitempty"`
+	HostsAt     time.Time         `json:"hosts_at,omitempty"`
+	Databases   []schema.Database `json:"databases,omitempty"`
+	DatabasesAt time.Time         `json:"databases_at,omitempty"`
+}
+
+// Store is a directory of per-credential entries.
+type Store struct {
+	Dir string
+	TTL time.Duration
+	Now func() time.Time
+	mu  sync.Mutex
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultStore *Store
+)
+
+// Default returns the store kept next to the credential cache under
+// ~/.config/cloudtoolkit.
+func Default() *Store {
+	defaultOnce.Do(func() {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		ttl := DefaultTTL
+		if value := strings.TrimSpace(os.Getenv(TTLEnv)); value != "" {
+			if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+				ttl = parsed
+			}
+		}
+		defaultStore = &Store{Dir: filepath.Join(home, ".config", "cloudtoolkit", "inventory"), TTL: ttl}
+	})
+	return defaultStore
+}
+
+// Save records the hosts and databases in resources for credential. Only the
+// asset kinds named in kinds (the cloudlist selection of the run) are
+// replaced, so a host-only run keeps the databases of an earlier full run.
+func (s *Store) Save(credential string, kinds []string, resources schema.Resources) error {
+	if s.disabled() {
+		return nil
+	}
+	path, err := s.path(credential)
+	if err != nil {
+		return err
+	}
+	replaceHosts, replaceDatabases := false, false
+	for _, kind := range kinds {
+		switch kind {
+		case schema.AssetHost:
+			replaceHosts = true
+		case schema.AssetDatabase:
+			replaceDatabases = true
+		}
+	}
+	if !replaceHosts && !replaceDatabases {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, err := s.read(path)
+	if err != nil {
+		return err
+	}
+	entry.Credential = credential
+	if resources.Provider != "" {
+		entry.Provider = resources.Provider
+	}
+	now := s.now()
+	if replaceHosts {
+		entry.Hosts = entry.Hosts[:0]
+		entry.HostsAt = now
+	}
+	if replaceDatabases {
+		entry.Databases = entry.Databases[:0]
+		entry.DatabasesAt = now
+	}
+	for _, asset := range resources.Assets {
+		switch item := asset.(type) {
+		case schema.Host:
+			if replaceHosts {
+				entry.Hosts = append(entry.Hosts, item)
+			}
+		case schema.Database:
+			if replaceDatabases {
+				entry.Databases = append(entry.Databases, item)
+			}
+		}
+	}
+	return s.write(path, entry)
+}
+
+// Load returns the cached entry for credential with expired asset kinds
+// dropped. ok is false when nothing usable is cached.
+func (s *Store) Load(credential string) (Entry, bool) {
+	if s.disabled() {
+		return Entry{}, false
+	}
+	path, err := s.path(credential)
+	if err != nil {
+		return Entry{}, false
+	}
+	s.mu.Lock()
+	entry, err := s.read(path)
+	s.mu.Unlock()
+	if err != nil {
+		return Entry{}, false
+	}
+	if s.expired(entry.HostsAt) {
+		entry.Hosts = nil
+	}
+	if s.expired(entry.DatabasesAt) {
+		entry.Databases = nil
+	}
+	return entry, len(entry.Hosts)+len(entry.Databases) > 0
+}
+
+// Forget removes the entry for credential.
+func (s *Store) Forget(credential string) error {
+	path, err := s.path(credential)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Host returns the cached host whose ID or host name is id.
+func (s *Store) Host(credential, id string) (schema.Host, bool) {
+	entry, ok := s.Load(credential)
+	if !ok {
+		return schema.Host{}, false
+	}
+	for _, host := range entry.Hosts {
+		if host.ID == id || host.HostName == id {
+			return host, true
+		}
+	}
+	return schema.Host{}, false
+}
+
+// Database returns the cached database whose instance ID is id.
+func (s *Store) Database(credential, id string) (schema.Database, bool) {
+	entry, ok := s.Load(credential)
+	if !ok {
+		return schema.Database{}, false
+	}
+	for _, db := range entry.Databases {
+		if db.InstanceId == id {
+			return db, true
+		}
+	}
+	return schema.Database{}, false
+}
+
+func (s *Store) disabled() bool {
+	return s == nil || s.TTL <= 0
+}
+
+func (s *Store) expired(at time.Time) bool {
+	return at.IsZero() || s.now().Sub(at) > s.TTL
+}
+
+func (s *Store) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// path maps a credential UUID to its file. UUIDs are hex digests; anything
+// else is rejected so a crafted value cannot escape Dir.
+func (s *Store) path(credential string) (string, error) {
+	credential = strings.TrimSpace(credential)
+	if credential == "" {
+		return "", errors.New("invcache: empty credential")
+	}
+	for _, r := range credential {
+		if !strings.ContainsRune("0123456789abcdefABCDEF-", r) {
+			return "", fmt.Errorf("invcache: invalid credential id %q", credential)
+		}
+	}
+	return filepath.Join(s.Dir, credential+".json"), nil
+}
+
+// read loads the entry at path; a missing file yields an empty entry.
+// Callers hold mu.
+func (s *Store) read(path string) (Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Entry{}, nil
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return Entry{}, fmt.Errorf("invcache: decode %s: %w", path, err)
+	}
+	return entry, nil
+}
+
+// write persists entry through a temp file. Callers hold mu.
+func (s *Store) write(path string, entry Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Scope ties a context to one credential's cached inventory.
+type Scope struct {
+	Store      *Store
+	Credential string
+}
+
+type ctxKey struct{}
+
+// With attaches the inventory of credential in store to ctx.
+func With(ctx context.Context, store *Store, credential string) context.Context {
+	if store == nil || credential == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxKey{}, Scope{Store: store, Credential: credential})
+}
+
+// From returns the scope attached to ctx, if any.
+func From(ctx context.Context) (Scope, bool) {
+	if ctx == nil {
+		return Scope{}, false
+	}
+	scope, ok := ctx.Value(ctxKey{}).(Scope)
+	return scope, ok
+}
+
+// LookupHost resolves id against the inventory attached to ctx.
+func LookupHost(ctx context.Context, id string) (schema.Host, bool) {
+	scope, ok := From(ctx)
+	if !ok {
+		return schema.Host{}, false
+	}
+	return scope.Store.Host(scope.Credential, id)
+}
+
+// LookupDatabase resolves id against the inventory attached to ctx.
+func LookupDatabase(ctx context.Context, id string) (schema.Database, bool) {
+	scope, ok := From(ctx)
+	if !ok {
+		return schema.Database{}, false
+	}
+	return scope.Store.Database(scope.Credential, id)
+}
+
+// ResolveHost resolves id with the persisted inventory as the single source
+// of truth whenever ctx carries an enabled store. session — the hosts a
+// provider kept in memory from its last enumeration — is consulted only when
+// no store is attached: replay sessions never attach one so their fixtures
+// stay hermetic, and CTK_INVENTORY_TTL=0 disables persistence altogether.
+// The in-memory lists are process-global rather than keyed by credential,
+// so they must never shadow the store.
+func ResolveHost(ctx context.Context, id string, session []schema.Host) (schema.Host, bool) {
+	if scope, ok := From(ctx); ok && !scope.Store.disabled() {
+		return scope.Store.Host(scope.Credential, id)
+	}
+	for _, host := range session {
+		if host.ID == id || host.HostName == id {
+			return host, true
+		}
+	}
+	return schema.Host{}, false
+}
+
+// ResolveDatabase is ResolveHost for databases.
+func ResolveDatabase(ctx context.Context, id string, session []schema.Database) (schema.Database, bool) {
+	if scope, ok := From(ctx); ok && !scope.Store.disabled() {
+		return scope.Store.Database(scope.Credential, id)
+	}
+	for _, db := range session {
+		if db.InstanceId == id {
+			return db, true
+		}
+	}
+	return schema.Database{}, false
+}
+
+// DatabaseRegion returns region unless it is empty or "all", in which case
+// the cached region of database id is used when known.
+func DatabaseRegion(ctx context.Context, id, region string) string {
+	if region != "" && region != "all" {
+		return region
+	}
+	if db, ok := LookupDatabase(ctx, id); ok && db.Region != "" {
+		return db.Region
+	}
+	return region
+}
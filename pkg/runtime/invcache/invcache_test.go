@@ -0,0 +1,145 @@
+package invcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+const testCredential = "0123456789abcdef0123456789abcdef"
+
+func newTestStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+	return &Store{Dir: t.TempDir(), TTL: time.Hour, Now: func() time.Time { return *now }}
+}
+
+func testResources() schema.Resources {
+	return schema.Resources{
+		Provider: "alibaba",
+		Assets: []schema.Asset{
+			schema.Host{ID: "i-1", HostName: "web", Region: "cn-hangzhou", OSType: "linux"},
+			schema.Database{InstanceId: "rm-1", Region: "cn-beijing"},
+		},
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newTestStore(t, &now)
+	if err := store.Save(testCredential, []string{schema.AssetHost, schema.AssetDatabase}, testResources()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	entry, ok := store.Load(testCredential)
+	if !ok {
+		t.Fatal("Load() found nothing")
+	}
+	if entry.Provider != "alibaba" || len(entry.Hosts) != 1 || len(entry.Databases) != 1 {
+		t.Fatalf("Load() = %+v", entry)
+	}
+	if host, ok := store.Host(testCredential, "web"); !ok || host.ID != "i-1" {
+		t.Fatalf("Host(web) = %+v, %v", host, ok)
+	}
+	if db, ok := store.Database(testCredential, "rm-1"); !ok || db.Region != "cn-beijing" {
+		t.Fatalf("Database(rm-1) = %+v, %v", db, ok)
+	}
+}
+
+func TestLoadDropsExpiredKinds(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newTestStore(t, &now)
+	if err := store.Save(testCredential, []string{schema.AssetDatabase}, testResources()); err != nil {
+		t.Fatalf("Save(database) error = %v", err)
+	}
+	now = now.Add(45 * time.Minute)
+	if err := store.Save(testCredential, []string{schema.AssetHost}, testResources()); err != nil {
+		t.Fatalf("Save(host) error = %v", err)
+	}
+	entry, _ := store.Load(testCredential)
+	if len(entry.Hosts) != 1 || len(entry.Databases) != 1 {
+		t.Fatalf("host-only save dropped databases: %+v", entry)
+	}
+
+	now = now.Add(30 * time.Minute)
+	entry, ok := store.Load(testCredential)
+	if !ok || len(entry.Hosts) != 1 || entry.Databases != nil {
+		t.Fatalf("Load() after database TTL = %+v, %v", entry, ok)
+	}
+	now = now.Add(time.Hour)
+	if _, ok := store.Load(testCredential); ok {
+		t.Fatal("Load() returned a fully expired entry")
+	}
+}
+
+func TestSaveRejectsInvalidCredential(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(t, &now)
+	for _, credential := range []string{"", "../escape", "abc/def"} {
+		if err := store.Save(credential, []string{schema.AssetHost}, testResources()); err == nil {
+			t.Fatalf("Save(%q) succeeded", credential)
+		}
+	}
+}
+
+func TestDisabledStore(t *testing.T) {
+	store := &Store{Dir: t.TempDir()}
+	if err := store.Save(testCredential, []string{schema.AssetHost}, testResources()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok := store.Load(testCredential); ok {
+		t.Fatal("Load() on a zero-TTL store returned an entry")
+	}
+}
+
+func TestContextLookup(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(t, &now)
+	if err := store.Save(testCredential, []string{schema.AssetHost, schema.AssetDatabase}, testResources()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok := LookupHost(context.Background(), "i-1"); ok {
+		t.Fatal("LookupHost() without a scope found a host")
+	}
+	ctx := With(context.Background(), store, testCredential)
+	if host, ok := LookupHost(ctx, "i-1"); !ok || host.Region != "cn-hangzhou" {
+		t.Fatalf("LookupHost(i-1) = %+v, %v", host, ok)
+	}
+	if got := DatabaseRegion(ctx, "rm-1", "all"); got != "cn-beijing" {
+		t.Fatalf("DatabaseRegion(all) = %q, want cn-beijing", got)
+	}
+	if got := DatabaseRegion(ctx, "rm-1", "cn-shanghai"); got != "cn-shanghai" {
+		t.Fatalf("DatabaseRegion(explicit) = %q, want cn-shanghai", got)
+	}
+}
+
+func TestResolvePrefersStoreOverSessionList(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(t, &now)
+	if err := store.Save(testCredential, []string{schema.AssetHost, schema.AssetDatabase}, testResources()); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// A host enumerated under another credential in this process.
+	session := []schema.Host{{ID: "i-2", HostName: "web", Region: "us-east-1"}}
+	dbs := []schema.Database{{InstanceId: "rm-2", Region: "us-east-1"}}
+
+	ctx := With(context.Background(), store, testCredential)
+	if host, ok := ResolveHost(ctx, "web", session); !ok || host.ID != "i-1" {
+		t.Fatalf("ResolveHost(web) = %+v, %v", host, ok)
+	}
+	if _, ok := ResolveHost(ctx, "i-2", session); ok {
+		t.Fatal("ResolveHost() fell back to the session list while a store is attached")
+	}
+	if _, ok := ResolveDatabase(ctx, "rm-2", dbs); ok {
+		t.Fatal("ResolveDatabase() fell back to the session list while a store is attached")
+	}
+
+	// Without a scope, or with persistence disabled, the session list answers.
+	if host, ok := ResolveHost(context.Background(), "web", session); !ok || host.ID != "i-2" {
+		t.Fatalf("ResolveHost(no scope) = %+v, %v", host, ok)
+	}
+	disabled := With(context.Background(), &Store{Dir: t.TempDir()}, testCredential)
+	if db, ok := ResolveDatabase(disabled, "rm-2", dbs); !ok || db.Region != "us-east-1" {
+		t.Fatalf("ResolveDatabase(disabled) = %+v, %v", db, ok)
+	}
+}
@@ -0,0 +1,310 @@
+// Package ratelimit paces provider API calls with token buckets so the
+// concurrent region workers of a large tenant stay under each cloud's
+// request quotas instead of leaning on 429 retries.
+//
+// Buckets are kept per provider and service, or per action when a rule names
+// one, and are shared by every request of the process that resolves to the
+// same limits, so parallel regions, retries and concurrent serve jobs draw
+// from one budget.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Off is the spec (and rule value) that disables limiting.
+const Off = "off"
+
+// Rule limits one scope. An empty Service applies to every service of the
+// provider and an empty Action to every action of the service; the most
+// specific matching rule wins. A zero Rate leaves the scope unlimited.
+type Rule struct {
+	Provider string
+	Service  string
+	Action   string
+	Rate     float64 // requests per second
+	Burst    int
+
+	builtin bool
+}
+
+func (r Rule) scope() string {
+	parts := []string{r.Provider}
+	if r.Service != "" || r.Action != "" {
+		parts = append(parts, r.Service)
+	}
+	if r.Action != "" {
+		parts = append(parts, r.Action)
+	}
+	return strings.ToLower(strings.Join(parts, "/"))
+}
+
+func (r Rule) burst() int {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return int(math.Max(1, math.Ceil(r.Rate)))
+}
+
+// defaults follow the read quotas each cloud documents, per account and
+// API, rounded down so a run keeps headroom for other tooling on the same
+// account.
+var defaults = []Rule{
+	{Provider: "alibaba", Rate: 20, Burst: 40},
+	{Provider: "alibaba", Service: "oss", Rate: 50, Burst: 100},
+	{Provider: "aws", Rate: 20, Burst: 50},
+	{Provider: "aws", Service: "ec2", Rate: 20, Burst: 100},
+	{Provider: "aws", Service: "iam", Rate: 10, Burst: 20},
+	{Provider: "aws", Service: "ce", Rate: 5, Burst: 5},
+	{Provider: "aws", Service: "route53", Rate: 5, Burst: 5},
+	{Provider: "aws", Service: "s3", Rate: 50, Burst: 100},
+	{Provider: "azure", Rate: 25, Burst: 250},
+	{Provider: "gcp", Rate: 20, Burst: 40},
+	{Provider: "huawei", Rate: 10, Burst: 20},
+	{Provider: "jdcloud", Rate: 10, Burst: 20},
+	{Provider: "tencent", Rate: 20, Burst: 20},
+	{Provider: "ucloud", Rate: 10, Burst: 20},
+	{Provider: "volcengine", Rate: 10, Burst: 20},
+}
+
+// Defaults returns the built-in rules. They only pace the clouds' public
+// API endpoints, not custom ones (see Scope).
+func Defaults() []Rule {
+	rules := append([]Rule(nil), defaults...)
+	for i := range rules {
+		rules[i].builtin = true
+	}
+	return rules
+}
+
+// Parse reads a comma-separated list of scope=rate[:burst] rules, where
+// scope is provider[/service[/action]] and rate is requests per second or
+// "off". A bare "off" disables every built-in rule, and provider=off disables
+// that provider's built-in rules down to the service and action level. Rules
+// are layered over the built-in ones; a later rule for the same scope
+// replaces an earlier one.
+//
+//	aws/ec2=10:40,tencent/cvm/DescribeInstances=5,gcp=off
+func Parse(spec string) ([]Rule, error) {
+	rules := Defaults()
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if strings.EqualFold(item, Off) {
+			rules = nil
+			continue
+		}
+		scope, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: want scope=rate[:burst]", item)
+		}
+		rule, err := parseScope(scope)
+		if err != nil {
+			return nil, err
+		}
+		value = strings.TrimSpace(value)
+		if !strings.EqualFold(value, Off) {
+			rate, burst, hasBurst := strings.Cut(value, ":")
+			if rule.Rate, err = strconv.ParseFloat(strings.TrimSpace(rate), 64); err != nil || rule.Rate <= 0 || math.IsInf(rule.Rate, 0) {
+				return nil, fmt.Errorf("rate limit %q: rate must be a positive number of requests per second or %q", item, Off)
+			}
+			if hasBurst {
+				if rule.Burst, err = strconv.Atoi(strings.TrimSpace(burst)); err != nil || rule.Burst <= 0 {
+					return nil, fmt.Errorf("rate limit %q: burst must be a positive integer", item)
+				}
+			}
+		}
+		if rule.Service == "" && rule.Rate == 0 {
+			rules = dropBuiltin(rules, rule.Provider)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// dropBuiltin removes the built-in rules of provider, leaving rules the user
+// configured for its services in place.
+func dropBuiltin(rules []Rule, provider string) []Rule {
+	kept := rules[:0]
+	for _, rule := range rules {
+		if rule.builtin && strings.EqualFold(rule.Provider, provider) {
+			continue
+		}
+		kept = append(kept, rule)
+	}
+	return kept
+}
+
+func parseScope(scope string) (Rule, error) {
+	parts := strings.SplitN(strings.TrimSpace(scope), "/", 3)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+		if parts[i] == "" {
+			return Rule{}, fmt.Errorf("rate limit scope %q: want provider[/service[/action]]", scope)
+		}
+	}
+	rule := Rule{Provider: parts[0]}
+	if len(parts) > 1 {
+		rule.Service = parts[1]
+	}
+	if len(parts) > 2 {
+		rule.Action = parts[2]
+	}
+	return rule, nil
+}
+
+// Limiter hands out tokens from its buckets.
+type Limiter struct {
+	rules map[string]Rule
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	now     func() time.Time
+	sleep   func(context.Context, time.Duration) error
+}
+
+// New returns a limiter enforcing rules; for equal scopes the last one wins.
+func New(rules []Rule) *Limiter {
+	l := &Limiter{
+		rules:   make(map[string]Rule, len(rules)),
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+		sleep:   sleep,
+	}
+	for _, rule := range rules {
+		l.rules[rule.scope()] = rule
+	}
+	return l
+}
+
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*Limiter{}
+)
+
+// For returns the process-wide limiter for spec, so every request resolving
+// to the same limits shares its buckets.
+func For(spec string) (*Limiter, error) {
+	spec = strings.TrimSpace(spec)
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
+	if l, ok := limiters[spec]; ok {
+		return l, nil
+	}
+	rules, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	l := New(rules)
+	limiters[spec] = l
+	return l, nil
+}
+
+// Scope names the API a request goes to. Custom marks an endpoint other than
+// the cloud's public API (a private deployment or a mock), which only
+// explicitly configured rules pace.
+type Scope struct {
+	Provider string
+	Service  string
+	Action   string
+	Custom   bool
+}
+
+// ErrPastDeadline is returned by Wait when the token for a request would
+// only be available after ctx's deadline. HTTP clients count their Timeout
+// from before the transport runs, so such a request would time out waiting
+// for the limiter instead of reaching the cloud.
+var ErrPastDeadline = errors.New("ratelimit: wait exceeds the request deadline")
+
+// Wait blocks until a request to scope may go out, or ctx is done. When the
+// wait would outlast ctx's deadline it fails at once with ErrPastDeadline and
+// hands the token back.
+func (l *Limiter) Wait(ctx context.Context, scope Scope) error {
+	rule, key, ok := l.match(scope)
+	if !ok {
+		return nil
+	}
+	l.mu.Lock()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{rate: rule.Rate, burst: float64(rule.burst()), tokens: float64(rule.burst()), last: l.now()}
+		l.buckets[key] = b
+	}
+	now := l.now()
+	delay := b.reserve(now)
+	if deadline, ok := ctx.Deadline(); ok && delay > 0 && !now.Add(delay).Before(deadline) {
+		b.tokens = math.Min(b.burst, b.tokens+1)
+		l.mu.Unlock()
+		return fmt.Errorf("%w: %s needs %s, %s left; lower the concurrency or raise the rate limit", ErrPastDeadline, key, delay.Round(time.Millisecond), deadline.Sub(now).Round(time.Millisecond))
+	}
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	if err := l.sleep(ctx, delay); err != nil {
+		l.mu.Lock()
+		b.tokens = math.Min(b.burst, b.tokens+1)
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// match returns the most specific rule for scope and its bucket key.
+func (l *Limiter) match(scope Scope) (Rule, string, bool) {
+	base := strings.ToLower(scope.Provider + "/" + scope.Service)
+	candidates := []struct{ scope, key string }{
+		{base + "/" + strings.ToLower(scope.Action), base + "/" + strings.ToLower(scope.Action)},
+		{base, base},
+		{strings.ToLower(scope.Provider), base},
+	}
+	for _, c := range candidates {
+		rule, ok := l.rules[c.scope]
+		if !ok || (scope.Custom && rule.builtin) {
+			continue
+		}
+		return rule, c.key, rule.Rate > 0
+	}
+	return Rule{}, "", false
+}
+
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve takes a token, going into debt when the bucket is empty, and
+// returns how long the caller must wait for it.
+func (b *bucket) reserve(now time.Time) time.Duration {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rate)
+		b.last = now
+	}
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
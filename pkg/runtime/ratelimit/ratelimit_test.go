@@ -0,0 +1,173 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+)
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(" aws/ec2=5:10 , tencent/cvm/DescribeInstances=2,gcp=off ")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	l := New(rules)
+	if rule, key, ok := l.match(Scope{Provider: "aws", Service: "ec2", Action: "DescribeInstances"}); !ok || rule.Rate != 5 || rule.Burst != 10 || key != "aws/ec2" {
+		t.Fatalf("aws/ec2 = %+v %q %v", rule, key, ok)
+	}
+	if rule, key, ok := l.match(Scope{Provider: "aws", Service: "sts", Action: "GetCallerIdentity"}); !ok || rule.Rate != 20 || key != "aws/sts" {
+		t.Fatalf("aws/sts fell back to %+v %q %v", rule, key, ok)
+	}
+	if rule, key, ok := l.match(Scope{Provider: "tencent", Service: "cvm", Action: "describeinstances"}); !ok || rule.Rate != 2 || rule.burst() != 2 || key != "tencent/cvm/describeinstances" {
+		t.Fatalf("tencent action = %+v %q %v", rule, key, ok)
+	}
+	if _, _, ok := l.match(Scope{Provider: "gcp", Service: "compute", Action: "GET instances"}); ok {
+		t.Fatal("gcp=off still limited")
+	}
+	if _, _, ok := l.match(Scope{Provider: "aws", Service: "iam", Custom: true}); ok {
+		t.Fatal("built-in rule paced a custom endpoint")
+	}
+	if rule, _, ok := New(append(Defaults(), Rule{Provider: "aws", Rate: 3})).match(Scope{Provider: "aws", Service: "ec2", Custom: true}); !ok || rule.Rate != 3 {
+		t.Fatalf("configured rule on a custom endpoint = %+v %v", rule, ok)
+	}
+	if rules, err := Parse("off,aws=1"); err != nil || len(rules) != 1 {
+		t.Fatalf("Parse(off,aws=1) = %+v, %v", rules, err)
+	}
+	for _, spec := range []string{"aws", "aws=fast", "aws=0", "aws=1:0", "aws//x=1", "=1"} {
+		if _, err := Parse(spec); err == nil {
+			t.Fatalf("Parse(%q) accepted", spec)
+		}
+	}
+}
+
+func TestParseProviderOffDisablesServiceDefaults(t *testing.T) {
+	tests := []struct {
+		spec  string
+		scope Scope
+		rate  float64 // 0 means unlimited
+	}{
+		{spec: "aws=off", scope: Scope{Provider: "aws", Service: "ec2", Action: "DescribeInstances"}},
+		{spec: "AWS=off", scope: Scope{Provider: "aws", Service: "iam", Action: "ListUsers"}},
+		{spec: "aws=off", scope: Scope{Provider: "aws", Service: "sts"}},
+		{spec: "aws=off,aws/ec2=4", scope: Scope{Provider: "aws", Service: "ec2"}, rate: 4},
+		{spec: "aws/s3=7,aws=off", scope: Scope{Provider: "aws", Service: "s3"}, rate: 7},
+		{spec: "aws=off", scope: Scope{Provider: "alibaba", Service: "oss"}, rate: 50},
+		{spec: "aws/ec2=off", scope: Scope{Provider: "aws", Service: "iam"}, rate: 10},
+	}
+	for _, tt := range tests {
+		rules, err := Parse(tt.spec)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.spec, err)
+		}
+		rule, _, ok := New(rules).match(tt.scope)
+		if tt.rate == 0 && ok {
+			t.Errorf("Parse(%q): %s/%s still limited by %+v", tt.spec, tt.scope.Provider, tt.scope.Service, rule)
+		}
+		if tt.rate != 0 && (!ok || rule.Rate != tt.rate) {
+			t.Errorf("Parse(%q): %s/%s = %+v %v, want rate %v", tt.spec, tt.scope.Provider, tt.scope.Service, rule, ok, tt.rate)
+		}
+	}
+}
+
+func TestWaitPacesAfterBurst(t *testing.T) {
+	now := time.Unix(0, 0)
+	var slept []time.Duration
+	l := New([]Rule{{Provider: "aws", Rate: 2, Burst: 2}})
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	ec2 := Scope{Provider: "aws", Service: "ec2", Action: "DescribeInstances"}
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(context.Background(), ec2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(slept) != 2 || slept[0] != 500*time.Millisecond || slept[1] != time.Second {
+		t.Fatalf("slept %v, want [500ms 1s]", slept)
+	}
+	// Another service has a bucket of its own.
+	if err := l.Wait(context.Background(), Scope{Provider: "aws", Service: "iam", Action: "ListUsers"}); err != nil || len(slept) != 2 {
+		t.Fatalf("iam waited: %v %v", slept, err)
+	}
+	// Two seconds refill the debt and one token.
+	now = now.Add(2 * time.Second)
+	if err := l.Wait(context.Background(), ec2); err != nil || len(slept) != 2 {
+		t.Fatalf("refilled bucket waited: %v %v", slept, err)
+	}
+}
+
+func TestWaitRefundsOnCancel(t *testing.T) {
+	l := New([]Rule{{Provider: "aws", Rate: 1, Burst: 1}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = l.Wait(ctx, Scope{Provider: "aws", Service: "ec2"})
+	if err := l.Wait(ctx, Scope{Provider: "aws", Service: "ec2"}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() error = %v", err)
+	}
+	if tokens := l.buckets["aws/ec2"].tokens; tokens > 0.01 || tokens < -0.01 {
+		t.Fatalf("tokens after refund = %v", tokens)
+	}
+}
+
+func TestWaitFailsFastPastDeadline(t *testing.T) {
+	now := time.Now()
+	l := New([]Rule{{Provider: "aws", Rate: 1, Burst: 1}})
+	l.now = func() time.Time { return now }
+	l.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+
+	ec2 := Scope{Provider: "aws", Service: "ec2"}
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(1500*time.Millisecond))
+	defer cancel()
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, ec2); err != nil {
+			t.Fatalf("Wait(%d) error = %v", i, err)
+		}
+	}
+	// The third token is due in 2s, after the deadline.
+	if err := l.Wait(ctx, ec2); !errors.Is(err, ErrPastDeadline) || !strings.Contains(err.Error(), "aws/ec2") {
+		t.Fatalf("Wait() error = %v, want %v", err, ErrPastDeadline)
+	}
+	if tokens := l.buckets["aws/ec2"].tokens; tokens > -0.99 || tokens < -1.01 {
+		t.Fatalf("tokens after refund = %v, want -1", tokens)
+	}
+}
+
+type countingTransport struct{ calls int }
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestTransportUsesEnvSpec(t *testing.T) {
+	next := &countingTransport{}
+	client := &http.Client{Transport: Wrap("demo", Wrap("demo", next))}
+	ctx := env.With(context.Background(), &env.Env{RateLimit: "demo=1000:1"})
+	started := time.Now()
+	for i := 0; i < 3; i++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1/v1/things", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if next.calls != 3 || time.Since(started) < 2*time.Millisecond {
+		t.Fatalf("calls = %d in %s", next.calls, time.Since(started))
+	}
+
+	ctx = env.With(context.Background(), &env.Env{RateLimit: "demo=nope"})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1/", nil)
+	if _, err := client.Do(req); err == nil {
+		t.Fatal("invalid spec was not reported")
+	}
+}
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"net/http"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
+)
+
+// Transport waits for a token before every attempt that passes through
+// Next. The limits come from the RateLimit spec of the run's env. The wait
+// counts against the client's Timeout, so a token due after the request
+// deadline fails the attempt at once with ErrPastDeadline.
+type Transport struct {
+	// Provider labels requests to hosts telemetry.Describe does not
+	// recognise.
+	Provider string
+	Next     http.RoundTripper
+}
+
+// Wrap returns next behind a limiting Transport. Wrapping an already
+// limiting transport is a no-op so requests never pay twice.
+func Wrap(provider string, next http.RoundTripper) http.RoundTripper {
+	if _, ok := next.(*Transport); ok {
+		return next
+	}
+	return &Transport{Provider: provider, Next: next}
+}
+
+// WrapClient returns a copy of client whose transport limits through Wrap.
+func WrapClient(provider string, client *http.Client) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	wrapped := *client
+	next := wrapped.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	wrapped.Transport = Wrap(provider, next)
+	return &wrapped
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	ctx := req.Context()
+	limiter, err := For(env.From(ctx).RateLimit)
+	if err != nil {
+		return nil, err
+	}
+	labels := telemetry.Describe(req, t.Provider)
+	scope := Scope{
+		Provider: labels.Provider,
+		Service:  labels.Service,
+		Action:   labels.Action,
+		Custom:   !telemetry.KnownHost(req.URL.Hostname()),
+	}
+	if err := limiter.Wait(ctx, scope); err != nil {
+		return nil, err
+	}
+	return next.RoundTrip(req)
+}
@@ -0,0 +1,93 @@
+package telemetry
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ContentType is the media type of WriteOpenMetrics output.
+const ContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// WriteOpenMetrics writes c in the OpenMetrics text format:
+//
+//	ctk_api_calls_total{provider,service,action,region,status}
+//	ctk_api_retries_total{provider,service,action,region}
+//	ctk_api_call_duration_seconds{provider,service,action,region} (histogram)
+func (c *Collector) WriteOpenMetrics(w io.Writer) error {
+	c.mu.Lock()
+	keys := make([]Labels, 0, len(c.series))
+	snapshot := make(map[Labels]series, len(c.series))
+	for labels, s := range c.series {
+		keys = append(keys, labels)
+		copied := *s
+		copied.buckets = append([]uint64(nil), s.buckets...)
+		copied.statuses = make(map[string]uint64, len(s.statuses))
+		for status, n := range s.statuses {
+			copied.statuses[status] = n
+		}
+		snapshot[labels] = copied
+	}
+	c.mu.Unlock()
+	sort.Slice(keys, func(i, j int) bool { return labelsLess(keys[i], keys[j]) })
+
+	b := bufio.NewWriter(w)
+	b.WriteString("# TYPE ctk_api_calls counter\n")
+	b.WriteString("# HELP ctk_api_calls Provider API attempts by HTTP status.\n")
+	for _, labels := range keys {
+		s := snapshot[labels]
+		statuses := make([]string, 0, len(s.statuses))
+		for status := range s.statuses {
+			statuses = append(statuses, status)
+		}
+		sort.Strings(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(b, "ctk_api_calls_total{%s,status=\"%s\"} %d\n", labelSet(labels), escape(status), s.statuses[status])
+		}
+	}
+	b.WriteString("# TYPE ctk_api_retries counter\n")
+	b.WriteString("# HELP ctk_api_retries Provider API attempts repeated by the retry policy.\n")
+	for _, labels := range keys {
+		if s := snapshot[labels]; s.retries > 0 {
+			fmt.Fprintf(b, "ctk_api_retries_total{%s} %d\n", labelSet(labels), s.retries)
+		}
+	}
+	b.WriteString("# TYPE ctk_api_call_duration_seconds histogram\n")
+	b.WriteString("# UNIT ctk_api_call_duration_seconds seconds\n")
+	b.WriteString("# HELP ctk_api_call_duration_seconds Provider API attempt latency.\n")
+	for _, labels := range keys {
+		s := snapshot[labels]
+		set := labelSet(labels)
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(b, "ctk_api_call_duration_seconds_bucket{%s,le=\"%s\"} %d\n", set, strconv.FormatFloat(bound, 'f', -1, 64), s.buckets[i])
+		}
+		fmt.Fprintf(b, "ctk_api_call_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", set, s.count)
+		fmt.Fprintf(b, "ctk_api_call_duration_seconds_count{%s} %d\n", set, s.count)
+		fmt.Fprintf(b, "ctk_api_call_duration_seconds_sum{%s} %s\n", set, strconv.FormatFloat(s.sum, 'f', -1, 64))
+	}
+	b.WriteString("# EOF\n")
+	return b.Flush()
+}
+
+// Handler serves the Default collector for scraping.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		_ = Default().WriteOpenMetrics(w)
+	})
+}
+
+func labelSet(l Labels) string {
+	return fmt.Sprintf("provider=\"%s\",service=\"%s\",action=\"%s\",region=\"%s\"",
+		escape(l.Provider), escape(l.Service), escape(l.Action), escape(l.Region))
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
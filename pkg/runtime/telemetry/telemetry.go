@@ -0,0 +1,212 @@
+// Package telemetry counts provider API calls, their latency and the retries
+// spent on them. Every provider HTTP client records through Transport and
+// httpclient.RetryPolicy records its retries, into the collector carried by
+// the request context and, through its parents, into the process-wide
+// Default collector.
+//
+// A run opens its own collector with Scope, so its Summary and OpenMetrics
+// dump cover exactly the calls it made even when other runs share the
+// process (ctk serve).
+package telemetry
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Labels identify a series. Service and Action are derived from the request
+// (see Describe) and Region is empty for global endpoints.
+type Labels struct {
+	Provider string `json:"provider"`
+	Service  string `json:"service"`
+	Action   string `json:"action"`
+	Region   string `json:"region,omitempty"`
+}
+
+// StatusError is recorded for calls that got no HTTP response at all.
+const StatusError = "error"
+
+// latencyBuckets are the upper bounds, in seconds, of the latency histogram.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type series struct {
+	statuses map[string]uint64
+	retries  uint64
+	buckets  []uint64
+	count    uint64
+	sum      float64
+	max      float64
+}
+
+// Collector aggregates calls. Its zero value is not usable; use New or
+// Scope.
+type Collector struct {
+	parent *Collector
+
+	mu      sync.Mutex
+	started time.Time
+	series  map[Labels]*series
+}
+
+var defaultCollector = New()
+
+// New returns an empty collector without a parent.
+func New() *Collector {
+	return &Collector{started: time.Now(), series: map[Labels]*series{}}
+}
+
+// Default returns the process-wide collector every call ends up in.
+func Default() *Collector {
+	return defaultCollector
+}
+
+type ctxKey struct{}
+
+// Scope returns a context carrying a fresh collector whose observations
+// also reach the collector already on ctx (or Default).
+func Scope(ctx context.Context) (context.Context, *Collector) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	c := New()
+	c.parent = From(ctx)
+	return context.WithValue(ctx, ctxKey{}, c), c
+}
+
+// From returns the collector carried by ctx, or Default.
+func From(ctx context.Context) *Collector {
+	if ctx != nil {
+		if c, ok := ctx.Value(ctxKey{}).(*Collector); ok && c != nil {
+			return c
+		}
+	}
+	return defaultCollector
+}
+
+// ObserveCall records one HTTP attempt.
+func ObserveCall(ctx context.Context, labels Labels, status string, elapsed time.Duration) {
+	seconds := elapsed.Seconds()
+	for c := From(ctx); c != nil; c = c.parent {
+		c.observe(labels, func(s *series) {
+			s.statuses[status]++
+			s.count++
+			s.sum += seconds
+			if seconds > s.max {
+				s.max = seconds
+			}
+			for i, bound := range latencyBuckets {
+				if seconds <= bound {
+					s.buckets[i]++
+				}
+			}
+		})
+	}
+}
+
+// ObserveRetry records that an attempt is about to be repeated.
+func ObserveRetry(ctx context.Context, labels Labels) {
+	for c := From(ctx); c != nil; c = c.parent {
+		c.observe(labels, func(s *series) { s.retries++ })
+	}
+}
+
+func (c *Collector) observe(labels Labels, update func(*series)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.series[labels]
+	if !ok {
+		s = &series{statuses: map[string]uint64{}, buckets: make([]uint64, len(latencyBuckets))}
+		c.series[labels] = s
+	}
+	update(s)
+}
+
+// Reset drops everything c has recorded.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.started = time.Now()
+	c.series = map[Labels]*series{}
+}
+
+// Summary is the JSON digest of a collector.
+type Summary struct {
+	Calls    uint64          `json:"calls"`
+	Retries  uint64          `json:"retries"`
+	Failures uint64          `json:"failures"`
+	Seconds  float64         `json:"seconds"`
+	Actions  []ActionSummary `json:"actions,omitempty"`
+}
+
+// ActionSummary is one series of a Summary. Failures counts attempts
+// answered with a 4xx/5xx status or no response at all.
+type ActionSummary struct {
+	Labels
+	Calls       uint64            `json:"calls"`
+	Retries     uint64            `json:"retries,omitempty"`
+	Failures    uint64            `json:"failures,omitempty"`
+	Statuses    map[string]uint64 `json:"statuses"`
+	MeanSeconds float64           `json:"mean_seconds"`
+	MaxSeconds  float64           `json:"max_seconds"`
+}
+
+// Summary returns the totals of c and one entry per series, busiest first.
+func (c *Collector) Summary() Summary {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var out Summary
+	for labels, s := range c.series {
+		item := ActionSummary{
+			Labels:     labels,
+			Calls:      s.count,
+			Retries:    s.retries,
+			Statuses:   make(map[string]uint64, len(s.statuses)),
+			MaxSeconds: round(s.max),
+		}
+		for status, n := range s.statuses {
+			item.Statuses[status] = n
+			if failed(status) {
+				item.Failures += n
+			}
+		}
+		if s.count > 0 {
+			item.MeanSeconds = round(s.sum / float64(s.count))
+		}
+		out.Calls += item.Calls
+		out.Retries += item.Retries
+		out.Failures += item.Failures
+		out.Seconds += s.sum
+		out.Actions = append(out.Actions, item)
+	}
+	out.Seconds = round(out.Seconds)
+	sort.Slice(out.Actions, func(i, j int) bool {
+		a, b := out.Actions[i], out.Actions[j]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		return labelsLess(a.Labels, b.Labels)
+	})
+	return out
+}
+
+func failed(status string) bool {
+	return status == StatusError || (len(status) == 3 && status[0] >= '4')
+}
+
+func round(seconds float64) float64 {
+	return float64(int64(seconds*1e6+0.5)) / 1e6
+}
+
+func labelsLess(a, b Labels) bool {
+	switch {
+	case a.Provider != b.Provider:
+		return a.Provider < b.Provider
+	case a.Service != b.Service:
+		return a.Service < b.Service
+	case a.Action != b.Action:
+		return a.Action < b.Action
+	}
+	return a.Region < b.Region
+}
@@ -0,0 +1,163 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newRequest(t *testing.T, method, rawURL, contentType, body string) *http.Request {
+	t.Helper()
+	req, err := http.NewRequest(method, rawURL, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req
+}
+
+func TestDescribe(t *testing.T) {
+	tencent := newRequest(t, http.MethodPost, "https://cvm.tencentcloudapi.com/", "application/json", `{"Limit":100}`)
+	tencent.Header.Set("X-TC-Action", "DescribeInstances")
+	tencent.Header.Set("X-TC-Region", "ap-guangzhou")
+
+	cases := []struct {
+		req  *http.Request
+		want Labels
+	}{
+		{
+			newRequest(t, http.MethodPost, "https://ec2.us-east-1.amazonaws.com/", "application/x-www-form-urlencoded", "Action=DescribeInstances&Version=2016-11-15"),
+			Labels{Provider: "aws", Service: "ec2", Action: "DescribeInstances", Region: "us-east-1"},
+		},
+		{tencent, Labels{Provider: "tencent", Service: "cvm", Action: "DescribeInstances", Region: "ap-guangzhou"}},
+		{
+			newRequest(t, http.MethodGet, "https://compute.googleapis.com/compute/v1/projects/demo/zones/us-central1-a/instances/vm-1", "", ""),
+			Labels{Provider: "gcp", Service: "compute", Action: "GET instances", Region: "us-central1-a"},
+		},
+		{
+			newRequest(t, http.MethodGet, "https://management.azure.com/subscriptions/0000/providers/Microsoft.Compute/virtualMachines?api-version=2023-03-01", "", ""),
+			Labels{Provider: "azure", Service: "Microsoft.Compute", Action: "GET virtualMachines"},
+		},
+		{
+			newRequest(t, http.MethodGet, "https://logs-1.oss-cn-hangzhou.aliyuncs.com/?acl", "", ""),
+			Labels{Provider: "alibaba", Service: "oss", Action: "GET ?acl", Region: "cn-hangzhou"},
+		},
+		{
+			newRequest(t, http.MethodPost, "https://api.ucloud.cn/", "application/json", `{"Action":"DescribeUHostInstance","Region":"cn-bj2"}`),
+			Labels{Provider: "ucloud", Service: "api", Action: "DescribeUHostInstance", Region: "cn-bj2"},
+		},
+		{
+			newRequest(t, http.MethodGet, "http://127.0.0.1:8080/v1/things", "", ""),
+			Labels{Provider: "demo", Service: "127", Action: "GET things"},
+		},
+	}
+	for _, tc := range cases {
+		if got := Describe(tc.req, "demo"); got != tc.want {
+			t.Errorf("Describe(%s) = %+v, want %+v", tc.req.URL, got, tc.want)
+		}
+	}
+
+	transportErr := &url.Error{Op: "Post", URL: "https://ecs.cn-beijing.aliyuncs.com/?Action=DescribeInstances", Err: errors.New("reset")}
+	if got := DescribeOutcome(nil, transportErr); got.Provider != "alibaba" || got.Action != "DescribeInstances" || got.Region != "cn-beijing" {
+		t.Fatalf("DescribeOutcome(url error) = %+v", got)
+	}
+}
+
+type stubTransport struct {
+	status int
+	err    error
+}
+
+func (s stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &http.Response{StatusCode: s.status, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+func TestScopeSummaryAndOpenMetrics(t *testing.T) {
+	outer, parent := Scope(context.Background())
+	ctx, run := Scope(outer)
+	other, sibling := Scope(outer)
+
+	client := &http.Client{Transport: Wrap("aws", stubTransport{status: http.StatusOK})}
+	throttled := &http.Client{Transport: Wrap("aws", stubTransport{status: http.StatusTooManyRequests})}
+	broken := &http.Client{Transport: Wrap("aws", stubTransport{err: errors.New("connection reset")})}
+	do := func(ctx context.Context, c *http.Client) {
+		req := newRequest(t, http.MethodPost, "https://ec2.us-east-1.amazonaws.com/", "application/x-www-form-urlencoded", "Action=DescribeInstances")
+		if resp, err := c.Do(req.WithContext(ctx)); err == nil {
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				ObserveRetry(ctx, DescribeOutcome(resp, nil))
+			}
+		}
+	}
+	do(ctx, throttled)
+	do(ctx, client)
+	do(ctx, client)
+	do(ctx, broken)
+	do(other, client)
+
+	summary := run.Summary()
+	if summary.Calls != 4 || summary.Retries != 1 || summary.Failures != 2 || len(summary.Actions) != 1 {
+		t.Fatalf("run summary = %+v", summary)
+	}
+	action := summary.Actions[0]
+	if action.Statuses["200"] != 2 || action.Statuses["429"] != 1 || action.Statuses[StatusError] != 1 {
+		t.Fatalf("statuses = %+v", action.Statuses)
+	}
+	if got := sibling.Summary().Calls; got != 1 {
+		t.Fatalf("sibling scope saw %d calls", got)
+	}
+	if got := parent.Summary().Calls; got != 5 {
+		t.Fatalf("parent scope saw %d calls", got)
+	}
+	if Default().Summary().Calls < 5 {
+		t.Fatal("calls did not reach the default collector")
+	}
+
+	var b strings.Builder
+	if err := run.WriteOpenMetrics(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	set := `provider="aws",service="ec2",action="DescribeInstances",region="us-east-1"`
+	for _, want := range []string{
+		"# TYPE ctk_api_calls counter\n",
+		"ctk_api_calls_total{" + set + `,status="200"} 2`,
+		"ctk_api_calls_total{" + set + `,status="error"} 1`,
+		"ctk_api_retries_total{" + set + "} 1",
+		"ctk_api_call_duration_seconds_bucket{" + set + `,le="+Inf"} 4`,
+		"ctk_api_call_duration_seconds_count{" + set + "} 4",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("OpenMetrics output lacks %q:\n%s", want, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Fatalf("OpenMetrics output not terminated:\n%s", out)
+	}
+}
+
+func TestHistogramBuckets(t *testing.T) {
+	ctx, c := Scope(context.Background())
+	labels := Labels{Provider: "gcp", Service: "compute", Action: "GET instances"}
+	ObserveCall(ctx, labels, "200", 30*time.Millisecond)
+	ObserveCall(ctx, labels, "200", 700*time.Millisecond)
+	ObserveCall(ctx, labels, "503", 40*time.Second)
+
+	s := c.series[labels]
+	if s.buckets[0] != 1 || s.buckets[4] != 2 || s.buckets[len(s.buckets)-1] != 2 || s.count != 3 {
+		t.Fatalf("buckets = %v count = %d", s.buckets, s.count)
+	}
+	if summary := c.Summary(); summary.Actions[0].MaxSeconds != 40 || summary.Failures != 1 {
+		t.Fatalf("summary = %+v", summary)
+	}
+}
@@ -0,0 +1,295 @@
+package telemetry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transport records every attempt that passes through Next.
+type Transport struct {
+	// Provider labels requests to hosts Describe does not recognise.
+	Provider string
+	Next     http.RoundTripper
+}
+
+// Wrap returns next behind a recording Transport. Wrapping an already
+// recording transport is a no-op so calls are never counted twice.
+func Wrap(provider string, next http.RoundTripper) http.RoundTripper {
+	if _, ok := next.(*Transport); ok {
+		return next
+	}
+	return &Transport{Provider: provider, Next: next}
+}
+
+// WrapClient returns a copy of client whose transport records through Wrap.
+func WrapClient(provider string, client *http.Client) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	wrapped := *client
+	next := wrapped.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	wrapped.Transport = Wrap(provider, next)
+	return &wrapped
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	labels := Describe(req, t.Provider)
+	started := time.Now()
+	resp, err := next.RoundTrip(req)
+	status := StatusError
+	if err == nil && resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	ObserveCall(req.Context(), labels, status, time.Since(started))
+	return resp, err
+}
+
+// DescribeOutcome labels the attempt behind a retry decision: the request
+// of resp when there is one, else the URL of a transport error.
+func DescribeOutcome(resp *http.Response, err error) Labels {
+	if resp != nil && resp.Request != nil {
+		return Describe(resp.Request, "")
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			method := http.MethodGet
+			if urlErr.Op != "" {
+				method = strings.ToUpper(urlErr.Op)
+			}
+			return Describe(&http.Request{Method: method, URL: u, Header: http.Header{}}, "")
+		}
+	}
+	return Labels{Provider: "unknown", Service: "unknown", Action: "unknown"}
+}
+
+var providerHosts = []struct {
+	suffix   string
+	provider string
+}{
+	{"amazonaws.com", "aws"},
+	{"amazonaws.com.cn", "aws"},
+	{"amazonaws.cn", "aws"},
+	{"aliyuncs.com", "alibaba"},
+	{"tencentcloudapi.com", "tencent"},
+	{"myqcloud.com", "tencent"},
+	{"myhuaweicloud.com", "huawei"},
+	{"azure.com", "azure"},
+	{"azure.cn", "azure"},
+	{"windows.net", "azure"},
+	{"chinacloudapi.cn", "azure"},
+	{"usgovcloudapi.net", "azure"},
+	{"microsoftonline.com", "azure"},
+	{"microsoft.com", "azure"},
+	{"volcengineapi.com", "volcengine"},
+	{"volces.com", "volcengine"},
+	{"jdcloud-api.com", "jdcloud"},
+	{"jdcloud-oss.com", "jdcloud"},
+	{"googleapis.com", "gcp"},
+	{"ucloud.cn", "ucloud"},
+	{"ufileos.com", "ucloud"},
+}
+
+// providerForHost maps an API host to its provider, so retries recorded by
+// the shared retry policy land on the same series as their attempts.
+func providerForHost(host string) string {
+	for _, item := range providerHosts {
+		if host == item.suffix || strings.HasSuffix(host, "."+item.suffix) {
+			return item.provider
+		}
+	}
+	return ""
+}
+
+// KnownHost reports whether host is one of the supported clouds' API
+// endpoints.
+func KnownHost(host string) bool {
+	return providerForHost(strings.ToLower(host)) != ""
+}
+
+// objectStorageLabels name the storage service in virtual-hosted bucket
+// endpoints, whose first label is the bucket.
+var objectStorageLabels = []string{"s3", "oss", "cos", "obs", "tos", "blob", "ufileos"}
+
+var (
+	hostRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+(-?\d+[a-z]?)?$`)
+	resourcePattern   = regexp.MustCompile(`^[A-Za-z][A-Za-z]+$`)
+)
+
+// maxDescribedBody caps how much of a request body Describe reads for the
+// action and region parameters.
+const maxDescribedBody = 64 << 10
+
+// Describe labels req. provider is used when the host is not one of the
+// supported clouds' API endpoints.
+func Describe(req *http.Request, provider string) Labels {
+	host := strings.ToLower(req.URL.Hostname())
+	labels := Labels{Provider: providerForHost(host)}
+	if labels.Provider == "" {
+		labels.Provider = provider
+	}
+	if labels.Provider == "" {
+		labels.Provider = "unknown"
+	}
+	params := requestParams(req)
+	labels.Service = service(host, req.URL.Path)
+	labels.Action = action(req, params)
+	labels.Region = region(req, host, params)
+	return labels
+}
+
+func requestParams(req *http.Request) url.Values {
+	params := url.Values{}
+	for name, values := range req.URL.Query() {
+		params[name] = values
+	}
+	if req.GetBody == nil || req.ContentLength <= 0 || req.ContentLength > maxDescribedBody {
+		return params
+	}
+	rc, err := req.GetBody()
+	if err != nil {
+		return params
+	}
+	defer rc.Close()
+	body, err := io.ReadAll(io.LimitReader(rc, maxDescribedBody))
+	if err != nil {
+		return params
+	}
+	contentType := req.Header.Get("Content-Type")
+	switch {
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		if form, err := url.ParseQuery(string(body)); err == nil {
+			for name, values := range form {
+				params[name] = append(params[name], values...)
+			}
+		}
+	case bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")):
+		var doc map[string]any
+		if json.Unmarshal(body, &doc) == nil {
+			for _, name := range []string{"Action", "Region", "RegionId"} {
+				if value, ok := doc[name].(string); ok {
+					params.Set(name, value)
+				}
+			}
+		}
+	}
+	return params
+}
+
+func service(host, path string) string {
+	labels := strings.Split(host, ".")
+	for _, label := range labels {
+		for _, known := range objectStorageLabels {
+			if label == known || strings.HasPrefix(label, known+"-") {
+				return known
+			}
+		}
+	}
+	segments := pathSegments(path)
+	switch {
+	case host == "management.azure.com" || strings.HasPrefix(host, "management."):
+		for i, segment := range segments {
+			if strings.EqualFold(segment, "providers") && i+1 < len(segments) {
+				return segments[i+1]
+			}
+		}
+		return "management"
+	case host == "www.googleapis.com" && len(segments) > 0:
+		return segments[0]
+	}
+	if len(labels) > 0 && labels[0] != "" {
+		return labels[0]
+	}
+	return host
+}
+
+func action(req *http.Request, params url.Values) string {
+	for _, name := range []string{"X-TC-Action", "X-Acs-Action"} {
+		if value := strings.TrimSpace(req.Header.Get(name)); value != "" {
+			return value
+		}
+	}
+	if target := strings.TrimSpace(req.Header.Get("X-Amz-Target")); target != "" {
+		if i := strings.LastIndex(target, "."); i >= 0 {
+			return target[i+1:]
+		}
+		return target
+	}
+	if value := strings.TrimSpace(params.Get("Action")); value != "" {
+		return value
+	}
+	// REST APIs: the method and the innermost named collection, which keeps
+	// IDs and names out of the label.
+	segments := pathSegments(req.URL.Path)
+	for i := len(segments) - 1; i >= 0; i-- {
+		if resourcePattern.MatchString(segments[i]) {
+			return req.Method + " " + segments[i]
+		}
+	}
+	names := make([]string, 0)
+	for name := range req.URL.Query() {
+		if resourcePattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) > 0 {
+		sort.Strings(names)
+		return req.Method + " ?" + names[0]
+	}
+	return req.Method + " /"
+}
+
+func region(req *http.Request, host string, params url.Values) string {
+	if value := strings.TrimSpace(req.Header.Get("X-TC-Region")); value != "" {
+		return value
+	}
+	for _, name := range []string{"RegionId", "Region", "regionId", "region"} {
+		if value := strings.TrimSpace(params.Get(name)); value != "" {
+			return value
+		}
+	}
+	segments := pathSegments(req.URL.Path)
+	for i, segment := range segments {
+		switch strings.ToLower(segment) {
+		case "regions", "zones", "locations":
+			if i+1 < len(segments) {
+				return segments[i+1]
+			}
+		}
+	}
+	for _, label := range strings.Split(host, ".") {
+		for _, prefix := range []string{"oss-", "tos-", "s3-"} {
+			label = strings.TrimPrefix(label, prefix)
+		}
+		if hostRegionPattern.MatchString(label) {
+			return label
+		}
+	}
+	return ""
+}
+
+func pathSegments(path string) []string {
+	var out []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			out = append(out, segment)
+		}
+	}
+	return out
+}
@@ -3,7 +3,10 @@ package schema
 import (
 	"context"
 	"fmt"
+	"path"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Provider is the minimum contract every cloud must satisfy. Capability
@@ -35,10 +38,14 @@ type IAMResult struct {
 	Message   string
 }
 
-// BucketManager powers the bucket-check payload.
+// BucketManager powers the bucket-check payload. WalkObjects follows each
+// bucket's continuation tokens to the end (or q.Max matches) and hands every
+// matching object to visit instead of collecting it, so huge buckets list in
+// constant memory; its results carry the per-bucket counts only.
 type BucketManager interface {
 	Provider
 	BucketDump(ctx context.Context, action, bucketName string) ([]BucketResult, error)
+	WalkObjects(ctx context.Context, bucketName string, q ObjectQuery, visit ObjectVisitor) ([]BucketResult, error)
 }
 
 type BucketResult struct {
@@ -57,6 +64,113 @@ type BucketObject struct {
 	StorageClass string
 }
 
+// ObjectQuery narrows a bucket-check listing. Prefix is sent to the storage
+// API; Pattern, ModifiedSince and MinSize are matched on each object. A
+// Pattern without a slash is matched against the key's base name, so "*.sql"
+// finds dumps at any depth. Max caps the matches per bucket, zero meaning all.
+type ObjectQuery struct {
+	Prefix        string
+	Pattern       string
+	ModifiedSince time.Time
+	MinSize       int64
+	Max           int64
+}
+
+// Match reports whether obj satisfies q. Objects whose modification time
+// does not parse are kept by ModifiedSince.
+func (q ObjectQuery) Match(obj BucketObject) bool {
+	if q.Prefix != "" && !strings.HasPrefix(obj.Key, q.Prefix) {
+		return false
+	}
+	if q.Pattern != "" {
+		name := obj.Key
+		if !strings.Contains(q.Pattern, "/") {
+			name = path.Base(name)
+		}
+		if ok, _ := path.Match(q.Pattern, name); !ok {
+			return false
+		}
+	}
+	if q.MinSize > 0 && obj.Size < q.MinSize {
+		return false
+	}
+	if !q.ModifiedSince.IsZero() {
+		if at, ok := parseObjectTime(obj.LastModified); ok && at.Before(q.ModifiedSince) {
+			return false
+		}
+	}
+	return true
+}
+
+// objectTimeLayouts are the LastModified formats of the storage APIs: ISO
+// 8601 for the S3 family and GCS, RFC 1123 for Azure Blob.
+var objectTimeLayouts = []string{time.RFC3339Nano, time.RFC1123, time.RFC1123Z}
+
+func parseObjectTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	for _, layout := range objectTimeLayouts {
+		if at, err := time.Parse(layout, value); err == nil {
+			return at, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// ObjectVisitor receives the objects of a WalkObjects listing; returning an
+// error stops the listing with that error.
+type ObjectVisitor func(BucketObject) error
+
+// ObjectWalk applies an ObjectQuery to the pages of one bucket.
+type ObjectWalk struct {
+	query   ObjectQuery
+	visit   ObjectVisitor
+	Matched int64
+}
+
+func NewObjectWalk(q ObjectQuery, visit ObjectVisitor) *ObjectWalk {
+	return &ObjectWalk{query: q, visit: visit}
+}
+
+// Add hands obj to the visitor when it matches and reports whether the
+// listing should fetch on.
+func (w *ObjectWalk) Add(obj BucketObject) (bool, error) {
+	if !w.query.Match(obj) {
+		return true, nil
+	}
+	if w.visit != nil {
+		if err := w.visit(obj); err != nil {
+			return false, err
+		}
+	}
+	w.Matched++
+	return w.query.Max <= 0 || w.Matched < w.query.Max, nil
+}
+
+// Result returns the list result of the walked bucket.
+func (w *ObjectWalk) Result(bucketName string) BucketResult {
+	return BucketResult{
+		Action:      "list",
+		BucketName:  bucketName,
+		ObjectCount: w.Matched,
+		Message:     fmt.Sprintf("%d objects found", w.Matched),
+	}
+}
+
+// CollectObjects runs walk with a visitor that keeps every object and
+// attaches them to their bucket's result, for callers that want the listing
+// in memory.
+func CollectObjects(walk func(ObjectVisitor) ([]BucketResult, error)) ([]BucketResult, error) {
+	objects := make(map[string][]BucketObject)
+	results, err := walk(func(obj BucketObject) error {
+		objects[obj.BucketName] = append(objects[obj.BucketName], obj)
+		return nil
+	})
+	for i := range results {
+		results[i].Objects = objects[results[i].BucketName]
+	}
+	return results, err
+}
+
 func AggregateBucketResults(action, bucketName string, results []BucketResult) BucketResult {
 	result := BucketResult{
 		Action:     action,
@@ -88,10 +202,13 @@ func AggregateBucketResults(action, bucketName string, results []BucketResult) B
 	return result
 }
 
-// EventReader powers the event-check payload.
+// EventReader powers the event-check payload. QueryEvents translates what it
+// can of the query into the audit service's native filters and applies the
+// rest client-side with EventQuery.Filter.
 type EventReader interface {
 	Provider
 	EventDump(context.Context, string, string) (EventActionResult, error)
+	QueryEvents(context.Context, EventQuery) ([]Event, error)
 }
 
 // VMExecutor powers the instance-cmd-check / shell payloads.
@@ -150,10 +267,51 @@ type BucketACLResult struct {
 	Message    string
 }
 
+// BucketACLEntry is one container of a bucket-acl-check audit. Level is the
+// container's own ACL setting; Exposure is the effective verdict once every
+// access layer the provider has (ACL, bucket policy or IAM, account-wide
+// blocks) is combined, and Reason says which layers decided it.
 type BucketACLEntry struct {
 	Account   string
 	Container string
 	Level     string
+	Exposure  string
+	Reason    string
+}
+
+// Effective exposure verdicts of a BucketACLEntry, from most to least open.
+const (
+	ExposurePublicReadWrite = "public-read-write"
+	ExposurePublicRead      = "public-read"
+	ExposureAuthenticated   = "authenticated"
+	ExposureConditional     = "conditional"
+	ExposureUnknown         = "unknown"
+	ExposurePrivate         = "private"
+)
+
+// ObjectAccessManager powers the object-access-check payload. Each call is
+// one data-plane request on bucket/key — `put` writes body, `get` reads it
+// back, `presign` reads it anonymously through a short-lived pre-signed URL
+// and `delete` removes it — so the operator can find every request in the
+// provider's data-event or access logs.
+type ObjectAccessManager interface {
+	Provider
+	ObjectAccess(ctx context.Context, action, bucket, key string, body []byte) (ObjectAccessResult, error)
+}
+
+// ObjectAccessResult is the outcome of one ObjectAccess request. RequestID is
+// the identifier the provider returned for it (x-amz-request-id,
+// x-oss-request-id, ...) and is also set when the request failed. Body holds
+// the object read by get and presign; URL is the pre-signed URL presign
+// fetched.
+type ObjectAccessResult struct {
+	Action     string
+	Bucket     string
+	Key        string
+	RequestID  string
+	StatusCode int
+	Body       []byte
+	URL        string
 }
 
 // IAMCredentialManager powers the iam-credential-check payload. It validates
@@ -182,6 +340,61 @@ type IAMCredential struct {
 	ValidBefore    string
 }
 
+// IAMPostureReader powers the read-only iam-posture payload. It returns the
+// credential state of every IAM user — console password, MFA, access keys
+// and when each was last used — and leaves the scoring to the payload.
+type IAMPostureReader interface {
+	Provider
+	IAMPosture(ctx context.Context) ([]UserPosture, error)
+}
+
+// UserPosture is the credential state of one IAM user. A zero time means
+// never or not reported. Gaps names the facts the provider could not read
+// for this user (PostureGapMFA, ...) so their absence is not scored.
+type UserPosture struct {
+	Account    string
+	UserName   string
+	UserID     string
+	CreateTime time.Time
+	// LastLogin is the last console sign-in.
+	LastLogin time.Time
+	Password  bool
+	// PasswordChanged is when the console password was set or last changed.
+	PasswordChanged time.Time
+	MFA             bool
+	AccessKeys      []AccessKeyPosture
+	Gaps            []string
+}
+
+// HasGap reports whether the provider could not read fact for this user.
+func (u UserPosture) HasGap(fact string) bool {
+	for _, gap := range u.Gaps {
+		if gap == fact {
+			return true
+		}
+	}
+	return false
+}
+
+// AccessKeyPosture is one long-lived access key of a UserPosture.
+// LastUsedService is the service the key last called, when reported.
+type AccessKeyPosture struct {
+	ID              string
+	Active          bool
+	CreateTime      time.Time
+	LastUsed        time.Time
+	LastUsedService string
+}
+
+// Facts a provider may be unable to read for a UserPosture.
+const (
+	PostureGapLastLogin   = "last-login"
+	PostureGapPasswordAge = "password-age"
+	PostureGapMFA         = "mfa"
+	PostureGapAccessKeys  = "access-keys"
+	PostureGapKeyLastUsed = "key-last-used"
+)
+
 type EventActionResult struct {
 	Action  string
 	Scope   string
@@ -190,8 +403,15 @@ type EventActionResult struct {
 	Message string
 }
 
+// CommandResult is the outcome of one instance command. Output carries stdout,
+// or the combined output when the exec service does not split streams.
+// Stderr, ExitCode and Status are set when the service reports them; a nil
+// ExitCode means the exit status is unknown.
 type CommandResult struct {
-	Output string
+	Output   string
+	Stderr   string
+	ExitCode *int
+	Status   string
 }
 
 type DatabaseActionResult struct {
@@ -209,6 +429,14 @@ type Asset interface {
 	AssetType() string
 }
 
+// AccountScoped is implemented by asset types that carry an owning-account
+// column. Multi-account enumerations stamp it so a merged inventory stays
+// attributable; single-account runs leave it empty.
+type AccountScoped interface {
+	Asset
+	WithAccount(id string) Asset
+}
+
 // Asset type constants. Providers and payloads should reference these rather
 // than raw strings to keep the grouping key canonical.
 const (
@@ -218,6 +446,8 @@ const (
 	AssetDatabase = "database"
 	AssetDomain   = "domain"
 	AssetLog      = "log"
+	AssetSecurity = "securitygroup"
+	AssetBalance  = "balance"
 )
 
 // NewResources creates a new resources structure
@@ -305,6 +535,15 @@ func (r Resources) Err() error {
 	return fmt.Errorf("partial enumeration errors: %s", strings.Join(messages, "; "))
 }
 
+// SetAccount stamps id on every AccountScoped asset.
+func (r *Resources) SetAccount(id string) {
+	for i, a := range r.Assets {
+		if scoped, ok := a.(AccountScoped); ok {
+			r.Assets[i] = scoped.WithAccount(id)
+		}
+	}
+}
+
 // Grouped returns assets partitioned by AssetType() while preserving insertion
 // order within each bucket. Used by the asset-inventory printer so each asset
 // type renders as its own table.
@@ -326,28 +565,38 @@ func AppendAssets[T Asset](r *Resources, items []T) {
 	}
 }
 
+// Account, here and on the other asset types, is only set on multi-account
+// runs. The table writer skips a column no row fills and the CSV export drops
+// empty omitempty columns, so single-account output has no Account column.
 type Host struct {
-	HostName    string `table:"HostName"`
-	ID          string `table:"Instance ID"`
-	State       string `table:"State"`
-	PublicIPv4  string `table:"Public IP"`
-	PrivateIpv4 string `table:"Private IP"`
-	OSType      string `table:"OS Type"`
-	DNSName     string `table:"DNS Name"`
-	Public      bool   `table:"Public"`
-	Region      string `table:"Region"`
+	HostName    string            `table:"HostName"`
+	ID          string            `table:"Instance ID"`
+	State       string            `table:"State"`
+	PublicIPv4  string            `table:"Public IP"`
+	PrivateIpv4 string            `table:"Private IP"`
+	OSType      string            `table:"OS Type"`
+	DNSName     string            `table:"DNS Name"`
+	Public      bool              `table:"Public"`
+	Region      string            `table:"Region"`
+	Tags        map[string]string `table:"-" json:",omitempty"`
+	Account     string            `table:"Account" json:",omitempty"`
 }
 
 func (Host) AssetType() string { return AssetHost }
 
+func (h Host) WithAccount(id string) Asset { h.Account = id; return h }
+
 type Storage struct {
 	BucketName  string `table:"Bucket"`
 	AccountName string `table:"Storage Account"`
 	Region      string `table:"Region"`
+	Account     string `table:"Account" json:",omitempty"`
 }
 
 func (Storage) AssetType() string { return AssetStorage }
 
+func (s Storage) WithAccount(id string) Asset { s.Account = id; return s }
+
 type User struct {
 	UserName    string `table:"User"`
 	UserId      string `table:"ID"`
@@ -355,10 +604,13 @@ type User struct {
 	EnableLogin bool   `table:"EnableLogin"`
 	LastLogin   string `table:"LastLogin"`
 	CreateTime  string `table:"CreateTime"`
+	Account     string `table:"Account" json:",omitempty"`
 }
 
 func (User) AssetType() string { return AssetUser }
 
+func (u User) WithAccount(id string) Asset { u.Account = id; return u }
+
 type Database struct {
 	InstanceId    string `table:"ID"`
 	Engine        string `table:"Engine"`
@@ -367,17 +619,23 @@ type Database struct {
 	Address       string `table:"Address"`
 	NetworkType   string `table:"NetworkType"`
 	DBNames       string `table:"DBName"`
+	Account       string `table:"Account" json:",omitempty"`
 }
 
 func (Database) AssetType() string { return AssetDatabase }
 
+func (d Database) WithAccount(id string) Asset { d.Account = id; return d }
+
 type Domain struct {
 	DomainName string
 	Records    []Record
+	Account    string `json:",omitempty"`
 }
 
 func (Domain) AssetType() string { return AssetDomain }
 
+func (d Domain) WithAccount(id string) Asset { d.Account = id; return d }
+
 type Record struct {
 	RR     string
 	Type   string
@@ -404,14 +662,196 @@ type SmsTemplate struct {
 }
 
 type Event struct {
-	Id        string
+	Id            string
+	Name          string
+	Affected      string
+	API           string
+	Status        string
+	SourceIp      string `table:"Source IP"`
+	AccessKey     string
+	Principal     string `table:"Principal"`
+	PrincipalType string `table:"Principal Type"`
+	UserAgent     string `table:"User Agent"`
+	Request       string `table:"Request"`
+	Time          string
+}
+
+// Event query results.
+const (
+	EventResultSuccess = "success"
+	EventResultFailure = "failure"
+)
+
+// EventQuery is the provider-neutral event-check filter. Empty fields match
+// everything; zero Since/Until leave the audit service's default lookback.
+// Name, Principal and Resource match a whole field (name or API; principal
+// or access key; affected resource) or its last dotted, slashed or colon
+// separated segment, so "alice" finds arn:aws:iam::1:user/alice. SourceIP
+// matches exactly. All comparisons ignore case.
+type EventQuery struct {
+	Since     time.Time
+	Until     time.Time
 	Name      string
-	Affected  string
-	API       string
-	Status    string
-	SourceIp  string `table:"Source IP"`
-	AccessKey string
-	Time      string
+	Principal string
+	SourceIP  string
+	Resource  string
+	Result    string
+}
+
+// EventWindow returns the query for a Unix-seconds time window; zero leaves
+// a bound open.
+func EventWindow(start, end int64) EventQuery {
+	var q EventQuery
+	if start > 0 {
+		q.Since = time.Unix(start, 0).UTC()
+	}
+	if end > 0 {
+		q.Until = time.Unix(end, 0).UTC()
+	}
+	return q
+}
+
+// UnixWindow returns Since and Until as Unix seconds, zero when unset.
+func (q EventQuery) UnixWindow() (int64, int64) {
+	var start, end int64
+	if !q.Since.IsZero() {
+		start = q.Since.Unix()
+	}
+	if !q.Until.IsZero() {
+		end = q.Until.Unix()
+	}
+	return start, end
+}
+
+// WithoutWindow returns q with an open time window, for the client-side
+// pass of drivers whose audit API already applied the window.
+func (q EventQuery) WithoutWindow() EventQuery {
+	q.Since, q.Until = time.Time{}, time.Time{}
+	return q
+}
+
+// HasFilters reports whether q narrows events beyond the time window.
+func (q EventQuery) HasFilters() bool {
+	return q.Name != "" || q.Principal != "" || q.SourceIP != "" || q.Resource != "" || q.Result != ""
+}
+
+// String renders q in the event-check metadata syntax, "all" when empty.
+func (q EventQuery) String() string {
+	var parts []string
+	if !q.Since.IsZero() {
+		parts = append(parts, "since="+q.Since.UTC().Format(time.RFC3339))
+	}
+	if !q.Until.IsZero() {
+		parts = append(parts, "until="+q.Until.UTC().Format(time.RFC3339))
+	}
+	for _, item := range []struct{ key, value string }{
+		{"name", q.Name},
+		{"principal", q.Principal},
+		{"ip", q.SourceIP},
+		{"resource", q.Resource},
+		{"result", q.Result},
+	} {
+		if item.value == "" {
+			continue
+		}
+		if strings.ContainsAny(item.value, " \t\"'") {
+			item.value = strconv.Quote(item.value)
+		}
+		parts = append(parts, item.key+"="+item.value)
+	}
+	if len(parts) == 0 {
+		return "all"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Match reports whether e satisfies q. Events whose time does not parse (see
+// ParseEventTime) are kept by the time bounds rather than silently dropped.
+func (q EventQuery) Match(e Event) bool {
+	if !q.Since.IsZero() || !q.Until.IsZero() {
+		if at, ok := ParseEventTime(e.Time); ok {
+			if (!q.Since.IsZero() && at.Before(q.Since)) || (!q.Until.IsZero() && at.After(q.Until)) {
+				return false
+			}
+		}
+	}
+	if q.Name != "" && !matchIdentifier(q.Name, e.Name, e.API) {
+		return false
+	}
+	if q.Principal != "" && !matchIdentifier(q.Principal, e.Principal, e.AccessKey) {
+		return false
+	}
+	if q.SourceIP != "" && !strings.EqualFold(strings.TrimSpace(e.SourceIp), q.SourceIP) {
+		return false
+	}
+	if q.Resource != "" && !matchIdentifier(q.Resource, e.Affected) {
+		return false
+	}
+	switch q.Result {
+	case EventResultSuccess:
+		return EventSucceeded(e.Status)
+	case EventResultFailure:
+		return !EventSucceeded(e.Status)
+	}
+	return true
+}
+
+var eventTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+}
+
+// ParseEventTime reads an Event.Time. It understands the zoned timestamps
+// most event readers emit; zone-less values may be provider-local time, so
+// they are not parsed and callers keep such events instead of judging them
+// by a guessed clock.
+func ParseEventTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range eventTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Filter returns the events of events that match q.
+func (q EventQuery) Filter(events []Event) []Event {
+	out := events[:0:0]
+	for _, e := range events {
+		if q.Match(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// EventSucceeded reports whether an audit status label means the call
+// succeeded. Providers label failures with an error code or a "failed"
+// variant, so anything that is not a known success is a failure.
+func EventSucceeded(status string) bool {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "success", "succeeded", "accepted", "started", "成功", "正常":
+		return true
+	}
+	return false
+}
+
+func matchIdentifier(want string, values ...string) bool {
+	want = strings.TrimSpace(want)
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if strings.EqualFold(value, want) {
+			return true
+		}
+		if i := strings.LastIndexAny(value, "./:"); i >= 0 && strings.EqualFold(value[i+1:], want) {
+			return true
+		}
+	}
+	return false
 }
 
 type Log struct {
@@ -419,10 +859,170 @@ type Log struct {
 	Region         string
 	Description    string
 	LastModifyTime string
+	Account        string `table:"Account" json:",omitempty"`
 }
 
 func (Log) AssetType() string { return AssetLog }
 
+func (l Log) WithAccount(id string) Asset { l.Account = id; return l }
+
+// SecurityGroup is a stateful network filter attached to instances or
+// interfaces: an AWS/Alibaba/Tencent/Huawei/Volcengine security group, an
+// Azure NSG, or a GCP VPC firewall rule (one group per firewall resource).
+type SecurityGroup struct {
+	GroupID   string
+	GroupName string
+	VpcID     string
+	Region    string
+	// Attached lists the instances, interfaces, subnets or target tags the
+	// group applies to, in whatever form the provider reports them.
+	Attached []string
+	Rules    []FirewallRule
+	Account  string `json:",omitempty"`
+}
+
+func (SecurityGroup) AssetType() string { return AssetSecurity }
+
+func (g SecurityGroup) WithAccount(id string) Asset { g.Account = id; return g }
+
+// Balance kinds. BalanceCredit is prepaid funds still available,
+// BalanceSpend is cost accrued so far in Period, and BalanceBillingAccount
+// records a visible billing account whose amount the API does not expose.
+const (
+	BalanceCredit         = "credit"
+	BalanceSpend          = "spend"
+	BalanceBillingAccount = "billing-account"
+)
+
+// Balance is one billing figure of an account. Amount is a decimal string in
+// whole currency units; Period is the "2006-01" month for spend and empty
+// for a point-in-time credit. Scope names the subscription or billing
+// account when the provider reports more than one.
+type Balance struct {
+	Kind     string `table:"Kind"`
+	Amount   string `table:"Amount"`
+	Currency string `table:"Currency"`
+	Period   string `table:"Period"`
+	Scope    string `table:"Scope"`
+	Account  string `table:"Account" json:",omitempty"`
+}
+
+func (Balance) AssetType() string { return AssetBalance }
+
+func (b Balance) WithAccount(id string) Asset { b.Account = id; return b }
+
+// FlagExposure sets Exposed on every rule of g.
+func (g *SecurityGroup) FlagExposure() {
+	for i := range g.Rules {
+		g.Rules[i].Exposed = g.Rules[i].InternetExposed()
+	}
+}
+
+// FirewallRule is one normalized rule of a SecurityGroup. Direction is
+// "ingress" or "egress", Action "allow" or "deny", Protocol lower-case with
+// "all" for any protocol, and PortRange "all", "22", "8000-8080" or a comma
+// list. CIDR holds the peer address range, or the referenced group / tag
+// when the rule does not target an address range.
+type FirewallRule struct {
+	Direction   string `table:"Direction"`
+	Action      string `table:"Action"`
+	Protocol    string `table:"Protocol"`
+	PortRange   string `table:"Port Range"`
+	CIDR        string `table:"CIDR"`
+	Priority    string `table:"Priority"`
+	Description string `table:"Description"`
+	Exposed     bool   `table:"Exposed"`
+}
+
+// AdminPorts are the remote-administration and data-store ports whose
+// exposure to the whole internet FirewallRule.InternetExposed flags.
+var AdminPorts = []int{
+	22,    // SSH
+	23,    // Telnet
+	135,   // MSRPC
+	445,   // SMB
+	1433,  // SQL Server
+	1521,  // Oracle
+	2375,  // Docker API
+	2376,  // Docker API (TLS)
+	3306,  // MySQL
+	3389,  // RDP
+	5432,  // PostgreSQL
+	5900,  // VNC
+	5985,  // WinRM
+	5986,  // WinRM (TLS)
+	6379,  // Redis
+	6443,  // Kubernetes API
+	9200,  // Elasticsearch
+	10250, // kubelet
+	11211, // Memcached
+	27017, // MongoDB
+}
+
+// InternetExposed reports an allow rule that admits traffic from any
+// address to at least one of AdminPorts.
+func (r FirewallRule) InternetExposed() bool {
+	if r.Direction != "ingress" || r.Action != "allow" || !isAnyAddress(r.CIDR) {
+		return false
+	}
+	switch r.Protocol {
+	case "all", "tcp", "udp":
+	default:
+		return false
+	}
+	for _, port := range AdminPorts {
+		if portRangeCovers(r.PortRange, port) {
+			return true
+		}
+	}
+	return false
+}
+
+func isAnyAddress(cidr string) bool {
+	switch strings.ToLower(strings.TrimSpace(cidr)) {
+	case "0.0.0.0/0", "::/0", "*", "any", "internet":
+		return true
+	}
+	return false
+}
+
+func portRangeCovers(ranges string, port int) bool {
+	ranges = strings.TrimSpace(ranges)
+	if ranges == "" || strings.EqualFold(ranges, "all") {
+		return true
+	}
+	for _, part := range strings.Split(ranges, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, found := strings.Cut(part, "-")
+		from, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			continue
+		}
+		to := from
+		if found {
+			if to, err = strconv.Atoi(strings.TrimSpace(hi)); err != nil {
+				continue
+			}
+		}
+		if from <= port && port <= to {
+			return true
+		}
+	}
+	return false
+}
+
+// PortRange renders a numeric from/to pair as a FirewallRule.PortRange.
+// Providers use -1, 0 or 1-65535 to mean every port.
+func PortRange(from, to int) string {
+	if (from <= 0 && to <= 0) || (from <= 1 && to >= 65535) {
+		return "all"
+	}
+	if to <= 0 || from == to {
+		return strconv.Itoa(from)
+	}
+	return strconv.Itoa(from) + "-" + strconv.Itoa(to)
+}
+
 // ErrNoSuchKey means no such key exists in metadata.
 type ErrNoSuchKey struct {
 	Name string
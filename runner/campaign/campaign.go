@@ -0,0 +1,206 @@
+// Package campaign runs declarative validation playbooks: an ordered list of
+// payload steps, each with an expected outcome, an optional wait for the
+// detection pipeline and an optional cleanup step that runs even when the
+// campaign fails part way through.
+package campaign
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"gopkg.in/yaml.v3"
+)
+
+// Expected step outcomes.
+const (
+	ExpectSuccess = "success"
+	ExpectFailure = "failure"
+	ExpectAny     = "any"
+	// ExpectPartial passes when the payload completes but reports
+	// per-resource errors, the outcome a fault profile should provoke.
+	ExpectPartial = "partial"
+)
+
+// Campaign is a playbook file. Provider and Options are defaults inherited
+// by every step; credentials are deliberately not part of the file and come
+// from the runner (or from the demo credentials when Replay is set). Faults
+// is a replay fault profile (see package faults) applied to every step.
+type Campaign struct {
+	Name              string            `yaml:"name" json:"name"`
+	Description       string            `yaml:"description,omitempty" json:"description,omitempty"`
+	Provider          string            `yaml:"provider,omitempty" json:"provider,omitempty"`
+	Replay            bool              `yaml:"replay,omitempty" json:"replay,omitempty"`
+	Faults            string            `yaml:"faults,omitempty" json:"faults,omitempty"`
+	Options           map[string]string `yaml:"options,omitempty" json:"options,omitempty"`
+	ContinueOnFailure bool              `yaml:"continue_on_failure,omitempty" json:"continue_on_failure,omitempty"`
+	Steps             []Step            `yaml:"steps" json:"steps"`
+}
+
+// Step runs one payload. WaitForDetection is a Go duration ("90s", "5m")
+// slept after the step succeeds so the detection pipeline can catch up
+// before the next step. Cleanup may not have a cleanup of its own.
+type Step struct {
+	Name             string `yaml:"name,omitempty" json:"name,omitempty"`
+	Provider         string `yaml:"provider,omitempty" json:"provider,omitempty"`
+	Payload          string `yaml:"payload" json:"payload"`
+	Metadata         string `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	Expect           string `yaml:"expect,omitempty" json:"expect,omitempty"`
+	WaitForDetection string `yaml:"wait_for_detection,omitempty" json:"wait_for_detection,omitempty"`
+	Cleanup          *Step  `yaml:"cleanup,omitempty" json:"cleanup,omitempty"`
+
+	wait time.Duration
+}
+
+// Wait returns the parsed detection window.
+func (s Step) Wait() time.Duration {
+	return s.wait
+}
+
+// Load reads a campaign from a .json file or, for any other extension, a
+// YAML file, and validates it. Unknown fields are rejected so a typo in a
+// key does not silently drop a cleanup step.
+func Load(path string) (Campaign, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Campaign{}, err
+	}
+	var c Campaign
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&c)
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&c)
+	}
+	if err != nil {
+		return Campaign{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if c.Name == "" {
+		c.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if err := c.Validate(); err != nil {
+		return Campaign{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Validate normalizes step defaults in place and reports the first invalid
+// step.
+func (c *Campaign) Validate() error {
+	if len(c.Steps) == 0 {
+		return errors.New("campaign has no steps")
+	}
+	c.Provider = strings.TrimSpace(c.Provider)
+	c.Faults = strings.TrimSpace(c.Faults)
+	if c.Faults != "" {
+		if !c.Replay {
+			return errors.New("faults require replay: true")
+		}
+		if _, err := faults.Parse(c.Faults); err != nil {
+			return err
+		}
+	}
+	for i := range c.Steps {
+		step := &c.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step %d", i+1)
+		}
+		if err := c.validateStep(step); err != nil {
+			return fmt.Errorf("%s: %w", step.Name, err)
+		}
+		if step.Cleanup == nil {
+			continue
+		}
+		cleanup := step.Cleanup
+		if cleanup.Name == "" {
+			cleanup.Name = step.Name + " cleanup"
+		}
+		if cleanup.Provider == "" {
+			cleanup.Provider = step.Provider
+		}
+		if cleanup.Cleanup != nil {
+			return fmt.Errorf("%s: cleanup steps cannot have their own cleanup", cleanup.Name)
+		}
+		if err := c.validateStep(cleanup); err != nil {
+			return fmt.Errorf("%s: %w", cleanup.Name, err)
+		}
+	}
+	return nil
+}
+
+func (c *Campaign) validateStep(step *Step) error {
+	step.Provider = strings.TrimSpace(step.Provider)
+	if step.Provider == "" {
+		step.Provider = c.Provider
+	}
+	if step.Provider == "" {
+		return errors.New("provider is required on the step or the campaign")
+	}
+	if _, ok := registry.Lookup(step.Provider); !ok {
+		return fmt.Errorf("unsupported provider: %s", step.Provider)
+	}
+
+	_, name, ok := payloads.Lookup(step.Payload)
+	if !ok {
+		return fmt.Errorf("unsupported payload: %s", step.Payload)
+	}
+	step.Payload = name
+	if capability := payloads.PayloadCapability(name); capability != "" && !registry.SupportsCapability(step.Provider, capability) {
+		return fmt.Errorf("%s does not support %s", step.Provider, name)
+	}
+	if c.Replay && !replaySupports(step.Provider, name) {
+		return fmt.Errorf("replay does not cover %s on %s", name, step.Provider)
+	}
+
+	step.Expect = strings.ToLower(strings.TrimSpace(step.Expect))
+	switch step.Expect {
+	case "":
+		step.Expect = ExpectSuccess
+	case ExpectSuccess, ExpectFailure, ExpectPartial, ExpectAny:
+	default:
+		return fmt.Errorf("unsupported expect %q: expected success, failure, partial or any", step.Expect)
+	}
+
+	if v := strings.TrimSpace(step.WaitForDetection); v != "" {
+		wait, err := time.ParseDuration(v)
+		if err != nil || wait < 0 {
+			return fmt.Errorf("invalid wait_for_detection %q", v)
+		}
+		step.wait = wait
+	}
+	return nil
+}
+
+func replaySupports(provider, payload string) bool {
+	for _, name := range replay.SupportedPayloads(provider) {
+		if name == payload {
+			return true
+		}
+	}
+	return false
+}
+
+// AllSteps returns the steps followed by their cleanup steps, in file order.
+// Callers use it to ask for approval of every sensitive action up front.
+func (c Campaign) AllSteps() []Step {
+	out := make([]Step, 0, len(c.Steps)*2)
+	for _, step := range c.Steps {
+		out = append(out, step)
+		if step.Cleanup != nil {
+			out = append(out, *step.Cleanup)
+		}
+	}
+	return out
+}
@@ -0,0 +1,132 @@
+package campaign
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	registerTestPayload(t, nil)
+
+	tests := []struct {
+		name    string
+		c       Campaign
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			c:    Campaign{Provider: "tencent", Steps: []Step{{Payload: testPayloadName, Cleanup: &Step{Payload: testPayloadName}}}},
+		},
+		{name: "no steps", c: Campaign{Provider: "tencent"}, wantErr: "no steps"},
+		{name: "no provider", c: Campaign{Steps: []Step{{Payload: testPayloadName}}}, wantErr: "provider is required"},
+		{name: "unknown provider", c: Campaign{Provider: "nimbus", Steps: []Step{{Payload: testPayloadName}}}, wantErr: "unsupported provider"},
+		{name: "unknown payload", c: Campaign{Provider: "tencent", Steps: []Step{{Payload: "no-such-payload"}}}, wantErr: "unsupported payload"},
+		{name: "unknown expect", c: Campaign{Provider: "tencent", Steps: []Step{{Payload: testPayloadName, Expect: "maybe"}}}, wantErr: "unsupported expect"},
+		{name: "bad wait", c: Campaign{Provider: "tencent", Steps: []Step{{Payload: testPayloadName, WaitForDetection: "soon"}}}, wantErr: "invalid wait_for_detection"},
+		{name: "faults without replay", c: Campaign{Provider: "tencent", Faults: "flaky", Steps: []Step{{Payload: testPayloadName}}}, wantErr: "faults require replay"},
+		{
+			name: "nested cleanup",
+			c: Campaign{Provider: "tencent", Steps: []Step{{
+				Name:    "expose",
+				Payload: testPayloadName,
+				Cleanup: &Step{Payload: testPayloadName, Cleanup: &Step{Payload: testPayloadName}},
+			}}},
+			wantErr: "expose cleanup: cleanup steps cannot have their own cleanup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateFillsDefaults(t *testing.T) {
+	registerTestPayload(t, nil)
+
+	c := Campaign{Provider: "tencent", Steps: []Step{{
+		Payload:          " " + testPayloadName,
+		Expect:           " Partial ",
+		WaitForDetection: "90s",
+		Cleanup:          &Step{Payload: testPayloadName},
+	}}}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	step := c.Steps[0]
+	if step.Name != "step 1" || step.Provider != "tencent" || step.Payload != testPayloadName || step.Expect != ExpectPartial || step.Wait().Seconds() != 90 {
+		t.Errorf("step = %+v", step)
+	}
+	cleanup := step.Cleanup
+	if cleanup.Name != "step 1 cleanup" || cleanup.Provider != "tencent" || cleanup.Expect != ExpectSuccess {
+		t.Errorf("cleanup = %+v", cleanup)
+	}
+	if got := c.AllSteps(); len(got) != 2 || got[1].Name != "step 1 cleanup" {
+		t.Errorf("AllSteps() = %+v", got)
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	registerTestPayload(t, nil)
+
+	tests := []struct {
+		file    string
+		data    string
+		wantErr string
+	}{
+		{
+			file:    "typo.yaml",
+			data:    "provider: tencent\nsteps:\n  - payload: " + testPayloadName + "\n    clean_up:\n      payload: " + testPayloadName + "\n",
+			wantErr: "clean_up",
+		},
+		{
+			file:    "typo.json",
+			data:    `{"provider":"tencent","steps":[{"payload":"` + testPayloadName + `","expected":"failure"}]}`,
+			wantErr: "expected",
+		},
+		{
+			file:    "nested.yaml",
+			data:    "provider: tencent\nsteps:\n  - payload: " + testPayloadName + "\n    cleanup:\n      payload: " + testPayloadName + "\n      cleanup:\n        payload: " + testPayloadName + "\n",
+			wantErr: "cannot have their own cleanup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Load(path); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadNamesCampaignAfterFile(t *testing.T) {
+	registerTestPayload(t, nil)
+
+	path := filepath.Join(t.TempDir(), "smoke.yml")
+	data := "provider: tencent\nsteps:\n  - payload: " + testPayloadName + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if c.Name != "smoke" || len(c.Steps) != 1 || c.Steps[0].Provider != "tencent" {
+		t.Errorf("Load() = %+v", c)
+	}
+}
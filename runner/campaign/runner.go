@@ -0,0 +1,287 @@
+package campaign
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
+	tencentreplay "github.com/404tk/cloudtoolkit/pkg/providers/tencent/replay"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// Step and campaign statuses recorded in the report.
+const (
+	StatusPassed  = "passed"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Report is the outcome of one campaign run. Status is failed when any step
+// or cleanup step did not meet its expectation.
+type Report struct {
+	Campaign   string       `json:"campaign"`
+	Replay     bool         `json:"replay,omitempty"`
+	Faults     string       `json:"faults,omitempty"`
+	Status     string       `json:"status"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+	Steps      []StepResult `json:"steps"`
+	Cleanup    []StepResult `json:"cleanup,omitempty"`
+}
+
+// Passed reports whether every step and cleanup step met its expectation.
+func (r Report) Passed() bool {
+	return r.Status == StatusPassed
+}
+
+type StepResult struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Payload  string `json:"payload"`
+	Metadata string `json:"metadata,omitempty"`
+	Expect   string `json:"expect"`
+	Status   string `json:"status"`
+	Partial  bool   `json:"partial,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+	Waited   string `json:"waited,omitempty"`
+	Result   any    `json:"result,omitempty"`
+}
+
+// Runner executes campaigns. Options returns the provider options
+// (credentials, region, ...) for a provider and is not consulted for replay
+// campaigns, which use the demo credentials instead.
+type Runner struct {
+	Options func(provider string) (map[string]string, error)
+	// Sleep waits out a detection window. It defaults to a timer that stops
+	// early when ctx is cancelled.
+	Sleep func(ctx context.Context, d time.Duration) error
+	Now   func() time.Time
+}
+
+// Run executes c. Steps stop at the first unmet expectation unless
+// ContinueOnFailure is set; the cleanup steps of every step that was
+// attempted then run in reverse order, on a context that outlives a
+// cancelled run, so validation artifacts are not left behind.
+func (r Runner) Run(ctx context.Context, c Campaign) Report {
+	report := Report{
+		Campaign:  c.Name,
+		Replay:    c.Replay,
+		Faults:    c.Faults,
+		Status:    StatusPassed,
+		StartedAt: r.now(),
+	}
+	if c.Replay {
+		profile, _ := faults.Parse(c.Faults)
+		faults.Enable(profile)
+		defer resetReplay()
+	}
+
+	var cleanups []Step
+	halted := false
+	for _, step := range c.Steps {
+		if halted || ctx.Err() != nil {
+			report.Steps = append(report.Steps, skipped(step))
+			continue
+		}
+		res := r.runStep(ctx, c, step)
+		if step.Cleanup != nil {
+			cleanups = append(cleanups, *step.Cleanup)
+		}
+		if res.Status == StatusPassed && step.Wait() > 0 {
+			logger.Info(fmt.Sprintf("Waiting %s for detection after %s ...", step.Wait(), step.Name))
+			started := r.now()
+			if err := r.sleep(ctx, step.Wait()); err != nil {
+				res.Status = StatusFailed
+				res.Error = fmt.Sprintf("detection wait interrupted: %s", err)
+			}
+			res.Waited = elapsed(started, r.now())
+		}
+		report.Steps = append(report.Steps, res)
+		if res.Status == StatusFailed {
+			report.Status = StatusFailed
+			halted = !c.ContinueOnFailure
+		}
+	}
+
+	cleanupCtx := context.WithoutCancel(ctx)
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		res := r.runStep(cleanupCtx, c, cleanups[i])
+		report.Cleanup = append(report.Cleanup, res)
+		if res.Status == StatusFailed {
+			report.Status = StatusFailed
+		}
+	}
+	report.FinishedAt = r.now()
+	return report
+}
+
+func (r Runner) runStep(ctx context.Context, c Campaign, step Step) (res StepResult) {
+	res = StepResult{
+		Name:     step.Name,
+		Provider: step.Provider,
+		Payload:  step.Payload,
+		Metadata: step.Metadata,
+		Expect:   step.Expect,
+	}
+	started := r.now()
+	defer func() { res.Duration = elapsed(started, r.now()) }()
+
+	logger.Info(fmt.Sprintf("Campaign step %s: %s %s", step.Name, step.Provider, step.Payload))
+	config, err := r.config(c, step)
+	if err != nil {
+		res.Status = StatusFailed
+		res.Error = err.Error()
+		return res
+	}
+	payload, _, ok := payloads.Lookup(step.Payload)
+	if !ok {
+		res.Status = StatusFailed
+		res.Error = "unsupported payload: " + step.Payload
+		return res
+	}
+	producer, ok := payload.(payloads.ResultProducer)
+	if !ok {
+		res.Status = StatusFailed
+		res.Error = fmt.Sprintf("payload %s has no structured result", step.Payload)
+		return res
+	}
+	if c.Replay {
+		replay.Enable(step.Provider)
+	}
+
+	result, err := producer.Result(ctx, config)
+	if err != nil {
+		res.Error = err.Error()
+		if resultErr, ok := err.(payloads.ResultError); ok {
+			result = resultErr.ResultPayload()
+		}
+	}
+	res.Result = result
+	res.Partial = err == nil && partial(result)
+	res.Status = outcome(step.Expect, err == nil, res.Partial)
+	return res
+}
+
+// outcome matches a step result against its expectation. A partial result
+// still counts as success so existing playbooks keep passing when a region
+// is unreachable; expect: partial asks for the resource errors explicitly.
+func outcome(expect string, succeeded, partial bool) string {
+	switch {
+	case expect == ExpectAny:
+		return StatusPassed
+	case expect == ExpectFailure && !succeeded:
+		return StatusPassed
+	case expect == ExpectSuccess && succeeded:
+		return StatusPassed
+	case expect == ExpectPartial && succeeded && partial:
+		return StatusPassed
+	}
+	return StatusFailed
+}
+
+// partial reports whether result completed with per-resource errors, the
+// same condition headless runs exit with exitPartial on.
+func partial(result any) bool {
+	switch cloud := result.(type) {
+	case *payloads.CloudListResult:
+		return cloud != nil && len(cloud.Errors) > 0
+	case payloads.CloudListResult:
+		return len(cloud.Errors) > 0
+	}
+	return false
+}
+
+// config builds the payload config for step: provider defaults, then the
+// campaign options, then the credential source, so a playbook can pin a
+// region without carrying secrets and a command-line flag still wins.
+func (r Runner) config(c Campaign, step Step) (map[string]string, error) {
+	config, ok := registry.DefaultConfig(step.Provider)
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", step.Provider)
+	}
+	var source map[string]string
+	if c.Replay {
+		source = replayOptions(step.Provider)
+	} else if r.Options != nil {
+		var err error
+		if source, err = r.Options(step.Provider); err != nil {
+			return nil, err
+		}
+	}
+	for _, layer := range []map[string]string{c.Options, source} {
+		for key, value := range layer {
+			if value != "" {
+				config[key] = value
+			}
+		}
+	}
+	config[utils.Provider] = step.Provider
+	config[utils.Payload] = step.Payload
+	config[utils.Metadata] = step.Metadata
+	return config, nil
+}
+
+func replayOptions(provider string) map[string]string {
+	creds, ok := replay.CredentialsFor(provider)
+	if !ok {
+		return nil
+	}
+	options := map[string]string{
+		utils.AccessKey: creds.AccessKey,
+		utils.SecretKey: creds.SecretKey,
+	}
+	for _, extra := range creds.Extras {
+		if extra.Name != "" {
+			options[extra.Name] = extra.Value
+		}
+	}
+	return options
+}
+
+func resetReplay() {
+	replay.Disable()
+	faults.Disable()
+	tencentreplay.Reset()
+}
+
+func skipped(step Step) StepResult {
+	return StepResult{
+		Name:     step.Name,
+		Provider: step.Provider,
+		Payload:  step.Payload,
+		Metadata: step.Metadata,
+		Expect:   step.Expect,
+		Status:   StatusSkipped,
+	}
+}
+
+func elapsed(from, to time.Time) string {
+	return to.Sub(from).Round(time.Millisecond).String()
+}
+
+func (r Runner) now() time.Time {
+	if r.Now != nil {
+		return r.Now()
+	}
+	return time.Now()
+}
+
+func (r Runner) sleep(ctx context.Context, d time.Duration) error {
+	if r.Sleep != nil {
+		return r.Sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,268 @@
+package campaign
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+const testPayloadName = "campaign-test"
+
+// testPayload answers according to the step metadata: "fail" returns an
+// error, "partial" a cloudlist result with a resource error, anything else
+// succeeds. Every call is logged by metadata, and hook runs first.
+type testPayload struct {
+	mu    *sync.Mutex
+	calls *[]string
+	hook  func(metadata string)
+}
+
+func (testPayload) Run(context.Context, map[string]string) {}
+func (testPayload) Desc() string                           { return "test payload" }
+
+func (p testPayload) Result(ctx context.Context, config map[string]string) (any, error) {
+	metadata := config[utils.Metadata]
+	p.mu.Lock()
+	*p.calls = append(*p.calls, metadata)
+	p.mu.Unlock()
+	if p.hook != nil {
+		p.hook(metadata)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	switch metadata {
+	case "fail":
+		return nil, errors.New("injected failure")
+	case "partial":
+		return &payloads.CloudListResult{Provider: "tencent", Errors: []schema.ResourceError{{Scope: "ap-guangzhou", Message: "denied"}}}, nil
+	}
+	return &payloads.CloudListResult{Provider: "tencent"}, nil
+}
+
+func registerTestPayload(t *testing.T, hook func(metadata string)) *[]string {
+	t.Helper()
+	calls := &[]string{}
+	payloads.Payloads[testPayloadName] = testPayload{mu: &sync.Mutex{}, calls: calls, hook: hook}
+	t.Cleanup(func() { delete(payloads.Payloads, testPayloadName) })
+	return calls
+}
+
+func step(metadata, cleanup string) Step {
+	s := Step{Name: metadata, Payload: testPayloadName, Metadata: metadata}
+	if cleanup != "" {
+		s.Cleanup = &Step{Name: cleanup, Payload: testPayloadName, Metadata: cleanup}
+	}
+	return s
+}
+
+func validCampaign(t *testing.T, c Campaign) Campaign {
+	t.Helper()
+	c.Provider = "tencent"
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	return c
+}
+
+func statuses(results []StepResult) []string {
+	out := make([]string, 0, len(results))
+	for _, res := range results {
+		out = append(out, res.Name+"="+res.Status)
+	}
+	return out
+}
+
+func TestRunHaltsOnFirstFailure(t *testing.T) {
+	tests := []struct {
+		name      string
+		keepGoing bool
+		calls     []string
+		steps     []string
+		cleanup   []string
+	}{
+		{
+			name:    "halt",
+			calls:   []string{"create", "fail", "undo-fail", "undo-create"},
+			steps:   []string{"create=passed", "fail=failed", "later=skipped"},
+			cleanup: []string{"undo-fail=passed", "undo-create=passed"},
+		},
+		{
+			name:      "continue_on_failure",
+			keepGoing: true,
+			calls:     []string{"create", "fail", "later", "undo-later", "undo-fail", "undo-create"},
+			steps:     []string{"create=passed", "fail=failed", "later=passed"},
+			cleanup:   []string{"undo-later=passed", "undo-fail=passed", "undo-create=passed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := registerTestPayload(t, nil)
+			c := validCampaign(t, Campaign{
+				ContinueOnFailure: tt.keepGoing,
+				Steps:             []Step{step("create", "undo-create"), step("fail", "undo-fail"), step("later", "undo-later")},
+			})
+			report := Runner{}.Run(context.Background(), c)
+			if report.Status != StatusFailed || report.Passed() {
+				t.Errorf("report status = %q, want failed", report.Status)
+			}
+			if !reflect.DeepEqual(*calls, tt.calls) {
+				t.Errorf("calls = %v, want %v", *calls, tt.calls)
+			}
+			if got := statuses(report.Steps); !reflect.DeepEqual(got, tt.steps) {
+				t.Errorf("steps = %v, want %v", got, tt.steps)
+			}
+			if got := statuses(report.Cleanup); !reflect.DeepEqual(got, tt.cleanup) {
+				t.Errorf("cleanup = %v, want %v", got, tt.cleanup)
+			}
+		})
+	}
+}
+
+func TestRunCleansUpAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := registerTestPayload(t, func(metadata string) {
+		if metadata == "interrupted" {
+			cancel()
+		}
+	})
+	c := validCampaign(t, Campaign{
+		Steps: []Step{step("create", "undo-create"), step("interrupted", "undo-interrupted"), step("later", "undo-later")},
+	})
+
+	report := Runner{}.Run(ctx, c)
+	if want := []string{"create", "interrupted", "undo-interrupted", "undo-create"}; !reflect.DeepEqual(*calls, want) {
+		t.Errorf("calls = %v, want %v", *calls, want)
+	}
+	if want := []string{"create=passed", "interrupted=failed", "later=skipped"}; !reflect.DeepEqual(statuses(report.Steps), want) {
+		t.Errorf("steps = %v, want %v", statuses(report.Steps), want)
+	}
+	if want := []string{"undo-interrupted=passed", "undo-create=passed"}; !reflect.DeepEqual(statuses(report.Cleanup), want) {
+		t.Errorf("cleanup = %v, want %v (cleanup must outlive the cancelled run)", statuses(report.Cleanup), want)
+	}
+	if report.Status != StatusFailed {
+		t.Errorf("report status = %q, want failed", report.Status)
+	}
+}
+
+func TestRunExpectations(t *testing.T) {
+	tests := []struct {
+		expect   string
+		metadata string
+		want     string
+	}{
+		{expect: "", metadata: "ok", want: StatusPassed},
+		{expect: ExpectSuccess, metadata: "fail", want: StatusFailed},
+		{expect: ExpectSuccess, metadata: "partial", want: StatusPassed},
+		{expect: ExpectFailure, metadata: "fail", want: StatusPassed},
+		{expect: ExpectFailure, metadata: "ok", want: StatusFailed},
+		{expect: ExpectFailure, metadata: "partial", want: StatusFailed},
+		{expect: ExpectPartial, metadata: "partial", want: StatusPassed},
+		{expect: ExpectPartial, metadata: "ok", want: StatusFailed},
+		{expect: ExpectPartial, metadata: "fail", want: StatusFailed},
+		{expect: ExpectAny, metadata: "ok", want: StatusPassed},
+		{expect: ExpectAny, metadata: "fail", want: StatusPassed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expect+"/"+tt.metadata, func(t *testing.T) {
+			registerTestPayload(t, nil)
+			s := step(tt.metadata, "")
+			s.Expect = tt.expect
+			report := Runner{}.Run(context.Background(), validCampaign(t, Campaign{Steps: []Step{s}}))
+			res := report.Steps[0]
+			if res.Status != tt.want || report.Status != tt.want {
+				t.Fatalf("step %+v, report %q, want %q", res, report.Status, tt.want)
+			}
+			if res.Partial != (tt.metadata == "partial") {
+				t.Errorf("Partial = %v", res.Partial)
+			}
+			if (res.Error != "") != (tt.metadata == "fail") {
+				t.Errorf("Error = %q", res.Error)
+			}
+		})
+	}
+}
+
+func TestRunDetectionWait(t *testing.T) {
+	tests := []struct {
+		name     string
+		sleepErr error
+		steps    []string
+		calls    []string
+	}{
+		{
+			name:  "completed",
+			steps: []string{"create=passed", "next=passed"},
+			calls: []string{"create", "next", "undo-create"},
+		},
+		{
+			name:     "interrupted",
+			sleepErr: context.Canceled,
+			steps:    []string{"create=failed", "next=skipped"},
+			calls:    []string{"create", "undo-create"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := registerTestPayload(t, nil)
+			now := time.Unix(0, 0)
+			var slept []time.Duration
+			r := Runner{
+				Now: func() time.Time { return now },
+				Sleep: func(ctx context.Context, d time.Duration) error {
+					slept = append(slept, d)
+					now = now.Add(d / 2)
+					return tt.sleepErr
+				},
+			}
+			first := step("create", "undo-create")
+			first.WaitForDetection = "2m"
+			report := r.Run(context.Background(), validCampaign(t, Campaign{Steps: []Step{first, step("next", "")}}))
+
+			if !reflect.DeepEqual(slept, []time.Duration{2 * time.Minute}) {
+				t.Errorf("slept %v, want one 2m wait", slept)
+			}
+			if got := statuses(report.Steps); !reflect.DeepEqual(got, tt.steps) {
+				t.Errorf("steps = %v, want %v", got, tt.steps)
+			}
+			if !reflect.DeepEqual(*calls, tt.calls) {
+				t.Errorf("calls = %v, want %v", *calls, tt.calls)
+			}
+			if res := report.Steps[0]; res.Waited != "1m0s" || (res.Error != "") != (tt.sleepErr != nil) {
+				t.Errorf("first step = %+v", res)
+			}
+			if report.Passed() != (tt.sleepErr == nil) {
+				t.Errorf("report status = %q", report.Status)
+			}
+		})
+	}
+}
+
+func TestRunReplayFaultsProducePartial(t *testing.T) {
+	c := Campaign{
+		Provider: "tencent",
+		Replay:   true,
+		Faults:   "region:ap-shanghai",
+		Steps:    []Step{{Payload: "cloudlist", Expect: ExpectPartial}},
+	}
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	ctx := env.With(context.Background(), &env.Env{Cloudlist: []string{"host"}})
+	report := Runner{}.Run(ctx, c)
+	if !report.Passed() {
+		t.Fatalf("report = %+v: %+v", report.Steps, report.Steps[0].Result)
+	}
+	if res := report.Steps[0]; !res.Partial {
+		t.Errorf("step = %+v, want a partial cloudlist", res.Result)
+	}
+}
@@ -0,0 +1,164 @@
+package console
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/runner/ledger"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/cloudtoolkit/utils/cache"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/table"
+)
+
+type ledgerRow struct {
+	ID        string `table:"ID"`
+	Provider  string `table:"Provider"`
+	Payload   string `table:"Payload"`
+	Resource  string `table:"Resource"`
+	Undo      string `table:"Undo"`
+	CreatedAt string `table:"Created"`
+}
+
+func cleanup(args []string) {
+	if len(args) == 0 || (len(args) == 1 && args[0] == "list") {
+		listCleanup()
+		return
+	}
+	switch args[0] {
+	case "run":
+		runCleanup(args[1:])
+		return
+	case "resolve":
+		if len(args) >= 2 {
+			for _, id := range args[1:] {
+				e, err := ledger.Default().Resolve(id, ledger.ResolvedManually)
+				if err != nil {
+					logger.Error(err.Error())
+					continue
+				}
+				logger.Info(fmt.Sprintf("Resolved %s (%s %s).", e.ID, e.Provider, e.Resource))
+			}
+			return
+		}
+	}
+	fmt.Println("Usage of cleanup:\n\tlist, show outstanding artifacts\n\trun [id|all], reverse outstanding artifacts\n\tresolve <id>, mark an artifact removed without touching the cloud")
+}
+
+func listCleanup() {
+	entries, err := ledger.Default().Outstanding()
+	if err != nil {
+		logger.Error("Read cleanup ledger failed:", err.Error())
+		return
+	}
+	if len(entries) == 0 {
+		logger.Info("No outstanding artifacts.")
+		return
+	}
+	rows := make([]ledgerRow, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, ledgerRow{
+			ID:        e.ID,
+			Provider:  e.Provider,
+			Payload:   e.Payload,
+			Resource:  e.Resource,
+			Undo:      e.Undo,
+			CreatedAt: e.CreatedAt.Local().Format(time.DateTime),
+		})
+	}
+	table.Output(rows)
+}
+
+// runCleanup reverses the selected entries newest first, asking for
+// confirmation on each one like `run` does. An entry is reversed with the
+// cached session it was recorded with, falling back to the current provider
+// session; UndoEntry refuses either one if it is a different credential.
+func runCleanup(ids []string) {
+	if replay.IsActive() {
+		logger.Error("Cleanup targets real accounts; leave demo mode first.")
+		return
+	}
+	outstanding, err := ledger.Default().Outstanding()
+	if err != nil {
+		logger.Error("Read cleanup ledger failed:", err.Error())
+		return
+	}
+	selected := outstanding
+	if len(ids) > 0 && !(len(ids) == 1 && ids[0] == "all") {
+		selected = selected[:0:0]
+		for _, id := range ids {
+			found := false
+			for _, e := range outstanding {
+				if e.ID == id {
+					selected = append(selected, e)
+					found = true
+					break
+				}
+			}
+			if !found {
+				logger.Error("No outstanding ledger entry", id)
+				return
+			}
+		}
+	}
+	if len(selected) == 0 {
+		logger.Info("No outstanding artifacts.")
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(env.With(context.Background(), env.Active()), os.Interrupt)
+	defer stop()
+	for _, e := range ledger.UndoOrder(selected) {
+		if ctx.Err() != nil {
+			logger.Info("Interrupted, remaining entries left outstanding.")
+			return
+		}
+		entryConfig, ok := cleanupConfig(e)
+		if !ok {
+			logger.Error(fmt.Sprintf("%s: no session for credential %s; reopen it with `sessions -i` or `use %s`.", e.ID, e.Credential, e.Provider))
+			continue
+		}
+		if !confirmIfSensitive(entryConfig) {
+			logger.Info(fmt.Sprintf("%s skipped.", e.ID))
+			continue
+		}
+		if _, err := payloads.UndoEntry(ctx, e, entryConfig); err != nil {
+			logger.Error(fmt.Sprintf("%s: %s", e.ID, err))
+			continue
+		}
+		logger.Info(fmt.Sprintf("%s resolved: %s %s", e.ID, e.Payload, e.Undo))
+	}
+}
+
+func cleanupConfig(e ledger.Entry) (map[string]string, bool) {
+	var source map[string]string
+	if data := cache.Cfg.CredSelect(e.Credential); data != "" {
+		source, _ = decodeSessionConfig(data)
+	}
+	if source == nil {
+		if config[utils.Provider] != e.Provider {
+			return nil, false
+		}
+		source = config
+	}
+	entryConfig, ok := registry.DefaultConfig(e.Provider)
+	if !ok {
+		return nil, false
+	}
+	for key, value := range source {
+		if value != "" {
+			entryConfig[key] = value
+		}
+	}
+	entryConfig[utils.Provider] = e.Provider
+	entryConfig[utils.Payload] = e.Payload
+	entryConfig[utils.Metadata] = e.Undo
+	return entryConfig, true
+}
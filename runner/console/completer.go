@@ -60,6 +60,8 @@ func rootSuggestions(ctx CompletionContext, args []string, word string) []prompt
 		return sessionsSuggestions(args, word)
 	case "note":
 		return noteSuggestions(args, word)
+	case "cleanup":
+		return cleanupSuggestions(args, word)
 	}
 	return []prompt.Suggest{}
 }
@@ -85,6 +87,10 @@ func providerSuggestions(ctx CompletionContext, args []string, word string) []pr
 		return sessionsSuggestions(args, word)
 	case "note":
 		return noteSuggestions(args, word)
+	case "cleanup":
+		return cleanupSuggestions(args, word)
+	case "demo":
+		return demoSuggestions(word)
 	}
 	return []prompt.Suggest{}
 }
@@ -125,6 +131,10 @@ func setSuggestions(ctx CompletionContext, args []string, word string) []prompt.
 		return prompt.FilterContains(getProviderRegionSuggestions(ctx.Provider), word, true)
 	case utils.Metadata:
 		return prompt.FilterContains(getPayloadMetadataSuggestions(ctx.Payload), word, true)
+	case utils.Format:
+		return prompt.FilterHasPrefix(formatSuggestionsData, word, true)
+	case utils.Detect:
+		return prompt.FilterHasPrefix(detectSuggestionsData, word, true)
 	}
 	return []prompt.Suggest{}
 }
@@ -200,10 +210,12 @@ func helpTopicKeysForContext(ctx CompletionContext) []string {
 }
 
 func optionSuggestions(ctx CompletionContext) []prompt.Suggest {
-	keys := []string{utils.Payload, utils.Metadata}
+	keys := []string{utils.Payload, utils.Metadata, utils.Format, utils.Detect}
 	seen := map[string]struct{}{
 		utils.Payload:  {},
 		utils.Metadata: {},
+		utils.Format:   {},
+		utils.Detect:   {},
 	}
 
 	for _, key := range providerConfigKeys() {
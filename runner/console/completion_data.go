@@ -8,7 +8,9 @@ import (
 	"strings"
 
 	demoreplay "github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/runner/ledger"
 	"github.com/404tk/cloudtoolkit/runner/payloads"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -37,6 +39,7 @@ var commandSuggestionDescriptions = map[string]string{
 	"use":      "enter provider mode",
 	"sessions": "list or reuse sessions",
 	"note":     "annotate a session",
+	"cleanup":  "reverse artifacts left by validation payloads",
 	"clear":    "clear the current screen",
 	"exit":     "leave the current mode",
 	// "quit":     "leave the current mode",
@@ -53,6 +56,7 @@ var rootCommandNames = []string{
 	"use",
 	"sessions",
 	"note",
+	"cleanup",
 	"clear",
 	"exit",
 }
@@ -67,6 +71,14 @@ var sessionsCommandSuggestions = []prompt.Suggest{
 	{Text: "-i", Description: "interact with a cached session by ID"},
 	{Text: "-k", Description: "delete a cached session by ID"},
 	{Text: "-c", Description: "check one cached session or all sessions"},
+	{Text: "lock", Description: "encrypt and lock the credential cache"},
+	{Text: "unlock", Description: "decrypt the credential cache for this console"},
+}
+
+var cleanupCommandSuggestions = []prompt.Suggest{
+	{Text: "list", Description: "show outstanding artifacts"},
+	{Text: "run", Description: "reverse outstanding artifacts"},
+	{Text: "resolve", Description: "mark an artifact removed without touching the cloud"},
 }
 
 var providerCommandNames = []string{
@@ -78,6 +90,7 @@ var providerCommandNames = []string{
 	"shell",
 	"sessions",
 	"note",
+	"cleanup",
 	"use",
 	"clear",
 	"exit",
@@ -93,6 +106,20 @@ var versionSuggestionsData = []prompt.Suggest{
 	{Text: "China", Description: "china edition"},
 }
 
+var formatSuggestionsData = []prompt.Suggest{
+	{Text: "text", Description: "tables (default)"},
+	{Text: "json", Description: "one indented JSON document"},
+	{Text: "ndjson", Description: "one JSON record per asset"},
+	{Text: "csv", Description: "one CSV section per asset type"},
+	{Text: "findings", Description: "exposure findings only"},
+}
+
+var detectSuggestionsData = []prompt.Suggest{
+	{Text: "5m", Description: "poll the audit trail for five minutes"},
+	{Text: "15m", Description: "allow for slow trails such as CloudTrail"},
+	{Text: "off", Description: "do not wait for the audit event"},
+}
+
 func currentCompletionContext() CompletionContext {
 	helpCtx := currentHelpContext()
 	return CompletionContext{
@@ -202,6 +229,43 @@ func sessionsSuggestions(args []string, word string) []prompt.Suggest {
 	return []prompt.Suggest{}
 }
 
+func cleanupSuggestions(args []string, word string) []prompt.Suggest {
+	if len(args) == 2 {
+		return prompt.FilterContains(cleanupCommandSuggestions, word, true)
+	}
+	switch args[1] {
+	case "run":
+		suggestions := append([]prompt.Suggest{{Text: "all", Description: "reverse every outstanding artifact"}}, ledgerIDSuggestions()...)
+		return prompt.FilterContains(suggestions, word, true)
+	case "resolve":
+		return prompt.FilterContains(ledgerIDSuggestions(), word, true)
+	}
+	return []prompt.Suggest{}
+}
+
+func demoSuggestions(word string) []prompt.Suggest {
+	suggestions := make([]prompt.Suggest, 0, len(faults.Names()))
+	for _, name := range faults.Names() {
+		suggestions = append(suggestions, prompt.Suggest{Text: name, Description: faults.Describe(name)})
+	}
+	return prompt.FilterHasPrefix(suggestions, word, true)
+}
+
+func ledgerIDSuggestions() []prompt.Suggest {
+	entries, err := ledger.Default().Outstanding()
+	if err != nil {
+		return nil
+	}
+	suggestions := make([]prompt.Suggest, 0, len(entries))
+	for _, e := range entries {
+		suggestions = append(suggestions, prompt.Suggest{
+			Text:        e.ID,
+			Description: fmt.Sprintf("%s / %s", e.Provider, e.Resource),
+		})
+	}
+	return suggestions
+}
+
 func sessionIDSuggestions() []prompt.Suggest {
 	loadCred()
 	suggestions := make([]prompt.Suggest, 0, len(creds))
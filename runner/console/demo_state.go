@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/faults"
 	tencentreplay "github.com/404tk/cloudtoolkit/pkg/providers/tencent/replay"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/logger"
@@ -13,11 +14,13 @@ import (
 
 func resetDemoReplay() {
 	replay.Disable()
+	faults.Disable()
 	tencentreplay.Reset()
 }
 
-func enableDemoReplay(provider string) {
+func enableDemoReplay(provider string, profile faults.Profile) {
 	replay.Enable(provider)
+	faults.Enable(profile)
 	preloadDemoOptions(provider)
 }
 
@@ -59,7 +62,7 @@ func isDemoRunHandledByProviderReplay() bool {
 	return replay.SupportsProvider(config[utils.Provider])
 }
 
-func demoCommand() {
+func demoCommand(args []string) {
 	provider := strings.TrimSpace(config[utils.Provider])
 	if config == nil || provider == "" {
 		logger.Error("Demo replay only works inside provider mode. Run `use <provider>` first.")
@@ -73,9 +76,14 @@ func demoCommand() {
 		logger.Error("Mock replay is already enabled. Use `exit` to return to the live provider session.")
 		return
 	}
+	profile, err := faults.Parse(strings.Join(args, ","))
+	if err != nil {
+		logger.Error(err)
+		return
+	}
 
-	enableDemoReplay(provider)
-	printDemoBanner(provider)
+	enableDemoReplay(provider, profile)
+	printDemoBanner(provider, profile)
 
 	p := prompt.New(
 		demoExecutor,
@@ -147,7 +155,7 @@ func closeDemoPrompt() {
 	prevConsole.Run()
 }
 
-func printDemoBanner(provider string) {
+func printDemoBanner(provider string, profile faults.Profile) {
 	credentials, _ := replay.CredentialsFor(provider)
 	lines := []string{
 		"!!! USE THESE DEMO CREDENTIALS !!!",
@@ -169,6 +177,9 @@ func printDemoBanner(provider string) {
 		}
 		lines = append(lines, fmt.Sprintf("%s: %s", extra.Name, value))
 	}
+	if !profile.Empty() {
+		lines = append(lines, "", fmt.Sprintf("Injected faults: %s", profile.Spec))
+	}
 	printDemoSection("MOCK REPLAY MODE", lines...)
 }
 
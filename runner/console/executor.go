@@ -9,7 +9,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/cassette"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/runner/export"
 	"github.com/404tk/cloudtoolkit/runner/payloads"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/cache"
@@ -41,14 +43,17 @@ func Executor(s string) {
 			sessions(args)
 		case "note":
 			note(args)
+		case "cleanup":
+			cleanup(args)
 		case "demo":
-			demoCommand()
+			demoCommand(args)
 		case "help":
 			help(args)
 		case "clear":
 			os.Stdout.Write([]byte("\033[2J\033[H"))
 		case "exit", "quit":
 			cache.SaveFile()
+			cassette.FlushAll()
 			os.Exit(0)
 		default:
 			logger.Error("Unsupported command:", cmd)
@@ -115,6 +120,25 @@ func set(args []string) {
 	if key == utils.Provider {
 		return
 	}
+	if key == utils.Format {
+		format, err := export.Parse(args[1])
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		config[key] = format
+		fmt.Printf("%s => %s\n", key, format)
+		return
+	}
+	if key == utils.Detect {
+		if _, err := payloads.ParseDetectionWindow(args[1]); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		config[key] = args[1]
+		fmt.Printf("%s => %s\n", key, args[1])
+		return
+	}
 
 	if _, ok := config[key]; ok || key == utils.Metadata || key == utils.Payload {
 		value := args[1]
@@ -153,12 +177,38 @@ func set(args []string) {
 func run(ctx context.Context) {
 	if v, name, ok := payloads.Lookup(config[utils.Payload]); ok {
 		config[utils.Payload] = name
+		if format := config[utils.Format]; export.Structured(format) {
+			runStructured(ctx, v, format)
+			return
+		}
 		v.Run(ctx, config)
 	} else {
 		logger.Error("Please type `show payloads` to confirm the required payload.")
 	}
 }
 
+// runStructured writes the payload result in the format chosen with
+// `set format` instead of letting the payload print its tables.
+func runStructured(ctx context.Context, p payloads.Payload, format string) {
+	producer, ok := p.(payloads.ResultProducer)
+	if !ok {
+		logger.Error(fmt.Sprintf("Payload %s has no structured output; use `set format text`.", config[utils.Payload]))
+		return
+	}
+	result, err := producer.Result(ctx, config)
+	if err != nil {
+		resultErr, ok := err.(payloads.ResultError)
+		if !ok {
+			logger.Error(err.Error())
+			return
+		}
+		result = resultErr.ResultPayload()
+	}
+	if err := export.Write(os.Stdout, format, result); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
 func runWithCancellation(parent context.Context) {
 	if parent == nil {
 		parent = context.Background()
@@ -38,6 +38,7 @@ var helpTopicOrder = []string{
 	"use",
 	"sessions",
 	"note",
+	"cleanup",
 	"show",
 	"set",
 	"run",
@@ -72,16 +73,21 @@ var helpTopics = map[string]helpTopic{
 			"sessions -i <id>",
 			"sessions -k <id>",
 			"sessions -c [id]",
+			"sessions lock",
+			"sessions unlock",
 		},
 		Details: []string{
 			"`sessions` lists the cached credential entries known to the console.",
 			"`-i` reopens provider mode with the selected cached session.",
 			"`-k` removes a cached session entry, and `-c` validates one or all cached entries.",
+			"`lock` encrypts the cache with a passphrase (scrypt + AES-256-GCM) and clears it from memory; `unlock` decrypts it again.",
+			"Sessions captured while the cache is locked are merged in on `unlock`; set CTK_CACHE_PASSPHRASE to unlock without a prompt.",
 		},
 		Examples: []string{
 			"sessions",
 			"sessions -i 1",
 			"sessions -c",
+			"sessions unlock",
 		},
 	},
 	"note": {
@@ -98,6 +104,26 @@ var helpTopics = map[string]helpTopic{
 			"note 1 lab-aws",
 		},
 	},
+	"cleanup": {
+		Title:   "Cleanup",
+		Summary: "Review and reverse the artifacts left by mutating validation payloads.",
+		Usage: []string{
+			"cleanup [list]",
+			"cleanup run [id|all]",
+			"cleanup resolve <id>",
+		},
+		Details: []string{
+			"Successful `iam-user-check add`, `iam-credential-check create`, `role-binding-check add`, `bucket-acl-check expose` and `rds-account-check useradd` runs, and `object-access-check run` canaries whose delete failed, are recorded in ~/.config/cloudtoolkit/ledger.json; the matching reverse action resolves them.",
+			"`run` replays the recorded reverse action with the cached session the artifact was created with, or the current provider session, after the usual confirmation.",
+			"`resolve` closes an entry without touching the cloud, e.g. after removing the artifact by hand.",
+			"Demo replay runs are not recorded.",
+		},
+		Examples: []string{
+			"cleanup",
+			"cleanup run all",
+			"cleanup resolve 3f9a1c2e",
+		},
+	},
 	"show": {
 		Title:   "Show",
 		Summary: "Display the current provider configuration or the visible validation payloads.",
@@ -121,16 +147,21 @@ var helpTopics = map[string]helpTopic{
 			"set <option> <value>",
 			"set payload <payload-name>",
 			"set metadata <payload-specific-args>",
+			"set format <text|json|ndjson|csv|findings>",
+			"set detect <window|off>",
 		},
 		Details: []string{
 			"`set payload <name>` stores the exact payload name used by `run`.",
 			"`set metadata` stores the payload-specific argument string used by `run`.",
 			"Changing the payload may reset metadata defaults for payloads that provide them.",
+			"`set format` switches `run` from tables to structured output on stdout; `text` restores tables.",
+			"`set detect 10m` makes mutating payloads poll the provider audit trail for the matching event and report its latency or `not_observed`; `off` disables it.",
 		},
 		Examples: []string{
 			"set accesskey <value>",
 			"set payload iam-user-check",
 			"set metadata add demo-user 'TempPassw0rd!'",
+			"set format ndjson",
 		},
 	},
 	"run": {
@@ -168,6 +199,7 @@ var helpTopics = map[string]helpTopic{
 		Summary: "Enable deterministic replay mode inside the current provider session.",
 		Usage: []string{
 			"demo",
+			"demo <fault>[:<arg>][,<fault>[:<arg>]...]",
 		},
 		Details: []string{
 			"`demo` opens a nested provider mock session and changes the prompt to `ctk > <provider>[mock] >`.",
@@ -175,6 +207,8 @@ var helpTopics = map[string]helpTopic{
 			"After enabling demo replay, use the designated demo access key and secret key with the existing `set` commands.",
 			"When the designated demo credentials match, supported payloads and shell sessions replay deterministic provider responses through the real provider and payload flow.",
 			"If the credentials do not match, mock mode returns a natural authentication failure and does not silently fall through to live provider execution.",
+			"An optional fault profile makes the replay misbehave so retries and partial results can be checked: `throttle[:seconds]` answers every 5th request with 429 and Retry-After, `flaky[:n]` answers every nth request with 503, `auth-expiry[:n]` expires the credentials after n requests, `slow[:duration]` delays every response, `truncate` cuts follow-up pages short, and `region:<name>` fails one region.",
+			"throttle and flaky fail a request only once, so payloads should recover through their retry policy; auth-expiry, truncate and region faults should surface as resource errors and a partial result.",
 		},
 		Examples: []string{
 			"use <provider>",
@@ -183,6 +217,7 @@ var helpTopics = map[string]helpTopic{
 			"set secretkey <demo-secret-key>",
 			"run",
 			"exit",
+			"demo throttle,region:us-west-2",
 		},
 	},
 	"payload": {
@@ -232,6 +267,12 @@ var helpOptionOrder = []string{
 	utils.AzureTenantId,
 	utils.AzureSubscriptionId,
 	utils.GCPserviceAccountJSON,
+	utils.AWSRoleArn,
+	utils.AWSExternalId,
+	utils.AWSSessionName,
+	utils.AWSMFASerial,
+	utils.AWSMFAToken,
+	utils.AWSOrgRole,
 }
 
 func currentHelpContext() HelpContext {
@@ -8,6 +8,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/cache"
+	"github.com/404tk/cloudtoolkit/utils/confirm"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 	"github.com/404tk/table"
 )
@@ -53,11 +54,20 @@ func sessions(args []string) {
 				return
 			}
 		}
-	} else if len(args) == 1 && args[0] == "-c" {
-		checkCred("all")
-		return
+	} else if len(args) == 1 {
+		switch args[0] {
+		case "-c":
+			checkCred("all")
+			return
+		case "lock":
+			lockSessions()
+			return
+		case "unlock":
+			unlockSessions()
+			return
+		}
 	}
-	fmt.Println("Usage of sessions:\n\t-i, interact [id]\n\t-k, kill [id]\n\t-c, check [id|all]")
+	fmt.Println("Usage of sessions:\n\t-i, interact [id]\n\t-k, kill [id]\n\t-c, check [id|all]\n\tlock, encrypt and lock the credential cache\n\tunlock, decrypt the credential cache")
 }
 
 func note(args []string) {
@@ -98,9 +108,66 @@ func loadCred() {
 
 func listSessions() {
 	loadCred()
+	if cache.Cfg.Locked() {
+		logger.Warning("Credential cache is locked, run `sessions unlock` to list stored sessions.")
+	}
 	table.Output(creds)
 }
 
+// lockSessions encrypts a plaintext cache under a new passphrase, or drops
+// the key of an already encrypted one, leaving the stored sessions
+// unreadable until `sessions unlock`.
+func lockSessions() {
+	if cache.Cfg.Locked() {
+		logger.Info("Credential cache is already locked.")
+		return
+	}
+	if !cache.Cfg.Encrypted() {
+		passphrase, err := confirm.Passphrase("New passphrase")
+		if err != nil {
+			logger.Error("Read passphrase failed:", err.Error())
+			return
+		}
+		again, err := confirm.Passphrase("Repeat passphrase")
+		if err != nil {
+			logger.Error("Read passphrase failed:", err.Error())
+			return
+		}
+		if passphrase != again {
+			logger.Error("Passphrases do not match.")
+			return
+		}
+		if err := cache.Cfg.SetPassphrase(passphrase); err != nil {
+			logger.Error("Encrypt credential cache failed:", err.Error())
+			return
+		}
+	}
+	if err := cache.Cfg.Lock(); err != nil {
+		logger.Error("Lock credential cache failed:", err.Error())
+		return
+	}
+	loadCred()
+	logger.Info(fmt.Sprintf("Credential cache locked. Set %s to unlock it in headless runs.", cache.PassphraseEnv))
+}
+
+func unlockSessions() {
+	if !cache.Cfg.Locked() {
+		logger.Info("Credential cache is not locked.")
+		return
+	}
+	passphrase, err := confirm.Passphrase("Passphrase")
+	if err != nil {
+		logger.Error("Read passphrase failed:", err.Error())
+		return
+	}
+	if err := cache.Cfg.Unlock(passphrase); err != nil {
+		logger.Error("Unlock credential cache failed:", err.Error())
+		return
+	}
+	loadCred()
+	logger.Info(fmt.Sprintf("Credential cache unlocked, %d session(s) available.", len(creds)))
+}
+
 func internation(uuid string) {
 	m, ok := decodeSessionConfig(cache.Cfg.CredSelect(uuid))
 	if !ok {
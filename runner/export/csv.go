@@ -0,0 +1,226 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+// Table is the CSV section for one asset type. Every row starts with the
+// asset type and provider, so sections stay self-describing when a
+// spreadsheet import splits them on the blank separator line.
+type Table struct {
+	Type   string
+	Header []string
+	Rows   [][]string
+}
+
+// securityGroupRule is one CSV row of a security group: the group columns
+// repeated for every rule, as in the cloudlist table output.
+type securityGroupRule struct {
+	GroupID     string
+	GroupName   string
+	VpcID       string
+	Region      string
+	Attached    []string
+	Direction   string
+	Action      string
+	Protocol    string
+	PortRange   string
+	CIDR        string
+	Priority    string
+	Description string
+	Exposed     bool
+	Account     string `json:",omitempty"`
+}
+
+type domainRecord struct {
+	DomainName string
+	RR         string
+	Type       string
+	Value      string
+	Status     string
+	Account    string `json:",omitempty"`
+}
+
+// Tables splits result into one CSV section per asset type. Results from
+// payloads other than cloudlist get one section per list field.
+func Tables(result any) []Table {
+	provider := providerOf(result)
+	cloud, ok := cloudListOf(result)
+	if !ok {
+		var out []Table
+		if acl, ok := result.(payloads.BucketACLCheckResult); ok && len(acl.Containers) > 0 {
+			return appendTable(out, bucketACLType, provider, reflect.ValueOf(bucketExposures(acl)))
+		}
+		for _, field := range listFields(result) {
+			out = appendTable(out, field.name, provider, field.value)
+		}
+		return out
+	}
+
+	var out []Table
+	out = appendTable(out, schema.AssetBalance, provider, reflect.ValueOf(cloud.Balances))
+	out = appendTable(out, schema.AssetHost, provider, reflect.ValueOf(cloud.Hosts))
+	out = appendTable(out, schema.AssetStorage, provider, reflect.ValueOf(cloud.Storages))
+	out = appendTable(out, schema.AssetUser, provider, reflect.ValueOf(cloud.Users))
+	out = appendTable(out, schema.AssetDatabase, provider, reflect.ValueOf(cloud.Databases))
+
+	var records []domainRecord
+	for _, domain := range cloud.Domains {
+		for _, r := range domain.Records {
+			records = append(records, domainRecord{
+				DomainName: domain.DomainName,
+				RR:         r.RR,
+				Type:       r.Type,
+				Value:      r.Value,
+				Status:     r.Status,
+				Account:    domain.Account,
+			})
+		}
+	}
+	out = appendTable(out, schema.AssetDomain, provider, reflect.ValueOf(records))
+	out = appendTable(out, schema.AssetLog, provider, reflect.ValueOf(cloud.Logs))
+
+	var rules []securityGroupRule
+	for _, group := range cloud.Security {
+		base := securityGroupRule{
+			GroupID:   group.GroupID,
+			GroupName: group.GroupName,
+			VpcID:     group.VpcID,
+			Region:    group.Region,
+			Attached:  group.Attached,
+			Account:   group.Account,
+		}
+		if len(group.Rules) == 0 {
+			rules = append(rules, base)
+			continue
+		}
+		for _, rule := range group.Rules {
+			row := base
+			row.Direction = rule.Direction
+			row.Action = rule.Action
+			row.Protocol = rule.Protocol
+			row.PortRange = rule.PortRange
+			row.CIDR = rule.CIDR
+			row.Priority = rule.Priority
+			row.Description = rule.Description
+			row.Exposed = rule.Exposed
+			rules = append(rules, row)
+		}
+	}
+	out = appendTable(out, schema.AssetSecurity, provider, reflect.ValueOf(rules))
+	out = appendTable(out, "sms-sign", provider, reflect.ValueOf(cloud.SMS.Signs))
+	out = appendTable(out, "sms-template", provider, reflect.ValueOf(cloud.SMS.Templates))
+	out = appendTable(out, "error", provider, reflect.ValueOf(cloud.Errors))
+	return out
+}
+
+// appendTable adds a section for a slice of structs. Scalar and string-slice
+// fields become columns; nested structs are left to the JSON formats.
+func appendTable(out []Table, kind, provider string, items reflect.Value) []Table {
+	if items.Kind() != reflect.Slice || items.Len() == 0 {
+		return out
+	}
+	elem := items.Type().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	var fields []int
+	table := Table{Type: kind, Header: []string{"AssetType", "Provider"}}
+	for i := 0; i < elem.NumField(); i++ {
+		f := elem.Field(i)
+		if !f.IsExported() || !csvColumn(f.Type) {
+			continue
+		}
+		if omitEmpty(f) && emptyColumn(items, i) {
+			continue
+		}
+		fields = append(fields, i)
+		table.Header = append(table.Header, f.Name)
+	}
+	for i := 0; i < items.Len(); i++ {
+		item := reflect.Indirect(items.Index(i))
+		if !item.IsValid() {
+			continue
+		}
+		row := []string{kind, provider}
+		for _, idx := range fields {
+			row = append(row, csvValue(item.Field(idx)))
+		}
+		table.Rows = append(table.Rows, row)
+	}
+	return append(out, table)
+}
+
+// omitEmpty reports whether f is tagged `json:",omitempty"`. Such a column
+// (e.g. Account, set only on multi-account runs) is left out of a section in
+// which no row has a value, as it is from the JSON and terminal output.
+func omitEmpty(f reflect.StructField) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			return true
+		}
+	}
+	return false
+}
+
+func emptyColumn(items reflect.Value, field int) bool {
+	for i := 0; i < items.Len(); i++ {
+		item := reflect.Indirect(items.Index(i))
+		if item.IsValid() && !item.Field(field).IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
+func csvColumn(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+func csvValue(v reflect.Value) string {
+	if v.Kind() == reflect.Slice {
+		items := make([]string, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			items = append(items, v.Index(i).String())
+		}
+		return strings.Join(items, ";")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// writeCSV writes each table with its own header row, separated by a blank
+// line.
+func writeCSV(w io.Writer, tables []Table) error {
+	for i, table := range tables {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		cw := csv.NewWriter(w)
+		if err := cw.Write(table.Header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(table.Rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/table"
+)
+
+func TestAccountColumnOnlyOnMultiAccountRuns(t *testing.T) {
+	single := []schema.Host{{HostName: "web", ID: "i-1", Region: "us-east-1"}}
+	multi := []schema.Host{{HostName: "web", ID: "i-1", Region: "us-east-1", Account: "111111111111"}, {HostName: "db", ID: "i-2"}}
+
+	tests := []struct {
+		name  string
+		hosts []schema.Host
+		want  bool
+	}{
+		{name: "single account", hosts: single, want: false},
+		{name: "multi account", hosts: multi, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Contains(table.Table(tt.hosts), "Account"); got != tt.want {
+				t.Errorf("table has Account column = %v, want %v", got, tt.want)
+			}
+			tables := Tables(&payloads.CloudListResult{Provider: "aws", Hosts: tt.hosts})
+			if len(tables) != 1 {
+				t.Fatalf("got %d CSV sections, want 1", len(tables))
+			}
+			if got := slices.Contains(tables[0].Header, "Account"); got != tt.want {
+				t.Errorf("CSV header %v has Account = %v, want %v", tables[0].Header, got, tt.want)
+			}
+			for _, row := range tables[0].Rows {
+				if len(row) != len(tables[0].Header) {
+					t.Fatalf("row %v does not match header %v", row, tables[0].Header)
+				}
+			}
+		})
+	}
+}
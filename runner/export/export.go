@@ -0,0 +1,74 @@
+// Package export renders payload results in the machine-readable formats
+// selected with `--format` in headless mode or `set format` in the console:
+// indented JSON, one NDJSON record per asset, CSV per asset type and a
+// findings report that lists only exposure issues.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	Text     = "text"
+	JSON     = "json"
+	NDJSON   = "ndjson"
+	CSV      = "csv"
+	Findings = "findings"
+)
+
+// Formats lists every accepted format name; Text is the default table output
+// rendered by the payload itself.
+var Formats = []string{Text, JSON, NDJSON, CSV, Findings}
+
+// Parse normalizes a user-supplied format name. An empty value selects Text.
+func Parse(value string) (string, error) {
+	format := strings.ToLower(strings.TrimSpace(value))
+	if format == "" {
+		return Text, nil
+	}
+	for _, item := range Formats {
+		if format == item {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported format %q: expected one of %s", value, strings.Join(Formats, ", "))
+}
+
+// Structured reports whether format is written by this package instead of
+// the payload's own table output.
+func Structured(format string) bool {
+	return format != "" && format != Text
+}
+
+// Write renders result, a value returned by payloads.ResultProducer, to w.
+func Write(w io.Writer, format string, result any) error {
+	switch format {
+	case JSON:
+		return writeIndented(w, result)
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		for _, record := range Records(result) {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		return writeCSV(w, Tables(result))
+	case Findings:
+		return writeIndented(w, BuildReport(result))
+	}
+	return fmt.Errorf("format %q has no structured writer", format)
+}
+
+func writeIndented(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
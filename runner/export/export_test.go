@@ -0,0 +1,261 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+// partialInventory is a cloudlist run in which the database listing failed:
+// the assets that were read are exported next to the error.
+func partialInventory() *payloads.CloudListResult {
+	return &payloads.CloudListResult{
+		Provider: "aws",
+		Hosts: []schema.Host{
+			{HostName: "web", ID: "i-0a1", State: "running", PublicIPv4: "203.0.113.10", PrivateIpv4: "10.0.0.10", OSType: "linux", Public: true, Region: "us-east-1"},
+			{HostName: "db", ID: "i-0b2", State: "stopped", PrivateIpv4: "10.0.0.20", OSType: "linux", Region: "us-east-1"},
+		},
+		Storages: []schema.Storage{{BucketName: "ctk-logs", Region: "us-east-1"}},
+		Security: []schema.SecurityGroup{{
+			GroupID: "sg-01", GroupName: "web", VpcID: "vpc-1", Region: "us-east-1", Attached: []string{"i-0a1"},
+			Rules: []schema.FirewallRule{
+				{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "22", CIDR: "0.0.0.0/0", Exposed: true},
+				{Direction: "ingress", Action: "allow", Protocol: "tcp", PortRange: "443", CIDR: "0.0.0.0/0"},
+			},
+		}},
+		Errors: []schema.ResourceError{{Scope: "database", Message: "AccessDenied: rds:DescribeDBInstances"}},
+	}
+}
+
+func TestWriteGolden(t *testing.T) {
+	tests := []struct {
+		name   string
+		result any
+		format string
+		want   string
+	}{
+		{
+			name:   "empty json",
+			result: &payloads.CloudListResult{Provider: "aws"},
+			format: JSON,
+			want: `{
+  "provider": "aws",
+  "sms": {
+    "Signs": null,
+    "Templates": null,
+    "DailySize": 0
+  }
+}
+`,
+		},
+		{
+			name:   "empty ndjson",
+			result: &payloads.CloudListResult{Provider: "aws"},
+			format: NDJSON,
+			want:   "",
+		},
+		{
+			name:   "empty csv",
+			result: &payloads.CloudListResult{Provider: "aws"},
+			format: CSV,
+			want:   "",
+		},
+		{
+			name:   "empty findings",
+			result: &payloads.CloudListResult{Provider: "aws"},
+			format: Findings,
+			want: `{
+  "tool": "cloudtoolkit",
+  "provider": "aws",
+  "rules": [],
+  "findings": []
+}
+`,
+		},
+		{
+			name:   "partial json",
+			result: partialInventory(),
+			format: JSON,
+			want: `{
+  "provider": "aws",
+  "hosts": [
+    {
+      "HostName": "web",
+      "ID": "i-0a1",
+      "State": "running",
+      "PublicIPv4": "203.0.113.10",
+      "PrivateIpv4": "10.0.0.10",
+      "OSType": "linux",
+      "DNSName": "",
+      "Public": true,
+      "Region": "us-east-1"
+    },
+    {
+      "HostName": "db",
+      "ID": "i-0b2",
+      "State": "stopped",
+      "PublicIPv4": "",
+      "PrivateIpv4": "10.0.0.20",
+      "OSType": "linux",
+      "DNSName": "",
+      "Public": false,
+      "Region": "us-east-1"
+    }
+  ],
+  "storages": [
+    {
+      "BucketName": "ctk-logs",
+      "AccountName": "",
+      "Region": "us-east-1"
+    }
+  ],
+  "security_groups": [
+    {
+      "GroupID": "sg-01",
+      "GroupName": "web",
+      "VpcID": "vpc-1",
+      "Region": "us-east-1",
+      "Attached": [
+        "i-0a1"
+      ],
+      "Rules": [
+        {
+          "Direction": "ingress",
+          "Action": "allow",
+          "Protocol": "tcp",
+          "PortRange": "22",
+          "CIDR": "0.0.0.0/0",
+          "Priority": "",
+          "Description": "",
+          "Exposed": true
+        },
+        {
+          "Direction": "ingress",
+          "Action": "allow",
+          "Protocol": "tcp",
+          "PortRange": "443",
+          "CIDR": "0.0.0.0/0",
+          "Priority": "",
+          "Description": "",
+          "Exposed": false
+        }
+      ]
+    }
+  ],
+  "sms": {
+    "Signs": null,
+    "Templates": null,
+    "DailySize": 0
+  },
+  "errors": [
+    {
+      "Scope": "database",
+      "Message": "AccessDenied: rds:DescribeDBInstances"
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "partial ndjson",
+			result: partialInventory(),
+			format: NDJSON,
+			want: `{"provider":"aws","type":"host","region":"us-east-1","asset":{"HostName":"web","ID":"i-0a1","State":"running","PublicIPv4":"203.0.113.10","PrivateIpv4":"10.0.0.10","OSType":"linux","DNSName":"","Public":true,"Region":"us-east-1"}}
+{"provider":"aws","type":"host","region":"us-east-1","asset":{"HostName":"db","ID":"i-0b2","State":"stopped","PublicIPv4":"","PrivateIpv4":"10.0.0.20","OSType":"linux","DNSName":"","Public":false,"Region":"us-east-1"}}
+{"provider":"aws","type":"storage","region":"us-east-1","asset":{"BucketName":"ctk-logs","AccountName":"","Region":"us-east-1"}}
+{"provider":"aws","type":"securitygroup","region":"us-east-1","asset":{"GroupID":"sg-01","GroupName":"web","VpcID":"vpc-1","Region":"us-east-1","Attached":["i-0a1"],"Rules":[{"Direction":"ingress","Action":"allow","Protocol":"tcp","PortRange":"22","CIDR":"0.0.0.0/0","Priority":"","Description":"","Exposed":true},{"Direction":"ingress","Action":"allow","Protocol":"tcp","PortRange":"443","CIDR":"0.0.0.0/0","Priority":"","Description":"","Exposed":false}]}}
+{"provider":"aws","type":"error","asset":{"Scope":"database","Message":"AccessDenied: rds:DescribeDBInstances"}}
+`,
+		},
+		{
+			name:   "partial csv",
+			result: partialInventory(),
+			format: CSV,
+			want: `AssetType,Provider,HostName,ID,State,PublicIPv4,PrivateIpv4,OSType,DNSName,Public,Region
+host,aws,web,i-0a1,running,203.0.113.10,10.0.0.10,linux,,true,us-east-1
+host,aws,db,i-0b2,stopped,,10.0.0.20,linux,,false,us-east-1
+
+AssetType,Provider,BucketName,AccountName,Region
+storage,aws,ctk-logs,,us-east-1
+
+AssetType,Provider,GroupID,GroupName,VpcID,Region,Attached,Direction,Action,Protocol,PortRange,CIDR,Priority,Description,Exposed
+securitygroup,aws,sg-01,web,vpc-1,us-east-1,i-0a1,ingress,allow,tcp,22,0.0.0.0/0,,,true
+securitygroup,aws,sg-01,web,vpc-1,us-east-1,i-0a1,ingress,allow,tcp,443,0.0.0.0/0,,,false
+
+AssetType,Provider,Scope,Message
+error,aws,database,AccessDenied: rds:DescribeDBInstances
+`,
+		},
+		{
+			name:   "partial findings",
+			result: partialInventory(),
+			format: Findings,
+			want: `{
+  "tool": "cloudtoolkit",
+  "provider": "aws",
+  "rules": [
+    {
+      "id": "internet-exposed-ingress",
+      "level": "error",
+      "description": "Firewall rule allows a remote-administration or data-store port from the whole internet."
+    },
+    {
+      "id": "public-host",
+      "level": "note",
+      "description": "Instance has a public IP address."
+    }
+  ],
+  "findings": [
+    {
+      "rule_id": "internet-exposed-ingress",
+      "level": "error",
+      "message": "sg-01 allows tcp/22 from 0.0.0.0/0",
+      "provider": "aws",
+      "resource": {
+        "type": "securitygroup",
+        "id": "sg-01",
+        "name": "web",
+        "region": "us-east-1"
+      },
+      "properties": {
+        "attached": "i-0a1",
+        "cidr": "0.0.0.0/0",
+        "port_range": "22",
+        "protocol": "tcp"
+      }
+    },
+    {
+      "rule_id": "public-host",
+      "level": "note",
+      "message": "i-0a1 is reachable at 203.0.113.10",
+      "provider": "aws",
+      "resource": {
+        "type": "host",
+        "id": "i-0a1",
+        "name": "web",
+        "region": "us-east-1"
+      },
+      "properties": {
+        "dns_name": "",
+        "public_ip": "203.0.113.10"
+      }
+    }
+  ]
+}
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, tt.format, tt.result); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+// Finding levels follow SARIF: error, warning, note.
+const (
+	levelError   = "error"
+	levelWarning = "warning"
+	levelNote    = "note"
+)
+
+// Rule describes one finding type so consumers can map rule IDs to their own
+// severities without parsing messages.
+type Rule struct {
+	ID          string `json:"id"`
+	Level       string `json:"level"`
+	Description string `json:"description"`
+}
+
+var (
+	ruleExposedIngress = Rule{
+		ID:          "internet-exposed-ingress",
+		Level:       levelError,
+		Description: "Firewall rule allows a remote-administration or data-store port from the whole internet.",
+	}
+	rulePublicBucket = Rule{
+		ID:          "public-bucket",
+		Level:       levelWarning,
+		Description: "Storage container is readable beyond its account: anonymously, by any authenticated principal, or under a policy condition.",
+	}
+	rulePublicHost = Rule{
+		ID:          "public-host",
+		Level:       levelNote,
+		Description: "Instance has a public IP address.",
+	}
+)
+
+// Resource locates the asset a finding is about.
+type Resource struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Account string `json:"account,omitempty"`
+}
+
+type Finding struct {
+	RuleID     string            `json:"rule_id"`
+	Level      string            `json:"level"`
+	Message    string            `json:"message"`
+	Provider   string            `json:"provider"`
+	Resource   Resource          `json:"resource"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Report is the findings document: a SARIF-like run with the rules that
+// fired and one entry per exposure issue. Findings is never null so an empty
+// run is distinguishable from a failed one.
+type Report struct {
+	Tool     string    `json:"tool"`
+	Provider string    `json:"provider"`
+	Rules    []Rule    `json:"rules"`
+	Findings []Finding `json:"findings"`
+}
+
+// BuildReport extracts exposure issues from a payload result: internet-exposed
+// firewall rules and public hosts from cloudlist, and anonymously readable
+// containers from bucket-acl-check. Containers are judged on their effective
+// exposure, so one that is public only through its bucket policy is reported
+// even though its ACL is private. Other results produce an empty report.
+func BuildReport(result any) Report {
+	report := Report{
+		Tool:     "cloudtoolkit",
+		Provider: providerOf(result),
+		Rules:    []Rule{},
+		Findings: []Finding{},
+	}
+	fired := make(map[string]bool)
+	add := func(rule Rule, f Finding) {
+		if !fired[rule.ID] {
+			fired[rule.ID] = true
+			report.Rules = append(report.Rules, rule)
+		}
+		f.RuleID = rule.ID
+		f.Level = rule.Level
+		f.Provider = report.Provider
+		report.Findings = append(report.Findings, f)
+	}
+
+	if cloud, ok := cloudListOf(result); ok {
+		for _, group := range cloud.Security {
+			for _, rule := range group.Rules {
+				if !rule.Exposed {
+					continue
+				}
+				add(ruleExposedIngress, Finding{
+					Message: fmt.Sprintf("%s allows %s/%s from %s", group.GroupID, rule.Protocol, rule.PortRange, rule.CIDR),
+					Resource: Resource{
+						Type:    schema.AssetSecurity,
+						ID:      group.GroupID,
+						Name:    group.GroupName,
+						Region:  group.Region,
+						Account: group.Account,
+					},
+					Properties: map[string]string{
+						"protocol":   rule.Protocol,
+						"port_range": rule.PortRange,
+						"cidr":       rule.CIDR,
+						"attached":   strings.Join(group.Attached, ","),
+					},
+				})
+			}
+		}
+		for _, host := range cloud.Hosts {
+			if !host.Public {
+				continue
+			}
+			add(rulePublicHost, Finding{
+				Message: fmt.Sprintf("%s is reachable at %s", host.ID, host.PublicIPv4),
+				Resource: Resource{
+					Type:    schema.AssetHost,
+					ID:      host.ID,
+					Name:    host.HostName,
+					Region:  host.Region,
+					Account: host.Account,
+				},
+				Properties: map[string]string{
+					"public_ip": host.PublicIPv4,
+					"dns_name":  host.DNSName,
+				},
+			})
+		}
+	}
+
+	if acl, ok := result.(payloads.BucketACLCheckResult); ok {
+		for _, entry := range acl.Containers {
+			if !publicBucket(entry.Exposure, entry.Level) {
+				continue
+			}
+			exposure := entry.Exposure
+			if exposure == "" {
+				exposure = entry.Level
+			}
+			add(rulePublicBucket, Finding{
+				Message: fmt.Sprintf("%s grants %s access", entry.Container, exposure),
+				Resource: Resource{
+					Type:    schema.AssetStorage,
+					ID:      entry.Container,
+					Account: entry.Account,
+				},
+				Properties: map[string]string{
+					"level":    entry.Level,
+					"exposure": entry.Exposure,
+					"reason":   entry.Reason,
+				},
+			})
+		}
+	}
+	return report
+}
+
+// publicBucket reports whether a container is exposed beyond its account. The
+// effective exposure decides when the provider reported one; the ACL level is
+// only consulted for entries without it.
+func publicBucket(exposure, level string) bool {
+	switch exposure {
+	case schema.ExposurePublicReadWrite, schema.ExposurePublicRead,
+		schema.ExposureAuthenticated, schema.ExposureConditional:
+		return true
+	case "":
+		return publicACL(level)
+	}
+	return false
+}
+
+// publicACL reports whether a provider ACL level grants anonymous access:
+// the canned "public-read*" ACLs, GCS "Public" and Azure "Blob"/"Container".
+func publicACL(level string) bool {
+	level = strings.ToLower(strings.TrimSpace(level))
+	switch level {
+	case "blob", "container":
+		return true
+	}
+	return strings.Contains(level, "public")
+}
@@ -0,0 +1,87 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+func TestBucketFindingsUseEffectiveExposure(t *testing.T) {
+	audit := func(level, exposure, reason string) payloads.BucketACLCheckResult {
+		var result payloads.BucketACLCheckResult
+		data := `{"provider":"aws","action":"audit","status":"success","containers":[{"container":"ctk-demo","level":"` + level + `","exposure":"` + exposure + `","reason":"` + reason + `"}]}`
+		if err := json.Unmarshal([]byte(data), &result); err != nil {
+			t.Fatalf("decode result: %v", err)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		result   payloads.BucketACLCheckResult
+		want     bool
+		exposure string
+	}{
+		{name: "public by policy only", result: audit("private", schema.ExposurePublicRead, "bucket policy allows Principal *"), want: true, exposure: schema.ExposurePublicRead},
+		{name: "authenticated users", result: audit("private", schema.ExposureAuthenticated, "ACL grants AuthenticatedUsers"), want: true, exposure: schema.ExposureAuthenticated},
+		{name: "conditional policy", result: audit("private", schema.ExposureConditional, "policy allows * with aws:SourceIp"), want: true, exposure: schema.ExposureConditional},
+		{name: "public ACL blocked", result: audit("public-read", schema.ExposurePrivate, "public access block ignores public ACLs"), want: false},
+		{name: "unknown layer", result: audit("private", schema.ExposureUnknown, "policy: access denied"), want: false},
+		{name: "ACL only provider", result: audit("Container", "", ""), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := BuildReport(tt.result)
+			if got := len(report.Findings) == 1; got != tt.want {
+				t.Fatalf("findings = %+v, want finding %v", report.Findings, tt.want)
+			}
+			if !tt.want {
+				return
+			}
+			f := report.Findings[0]
+			if f.RuleID != rulePublicBucket.ID || f.Resource.ID != "ctk-demo" {
+				t.Errorf("finding = %+v", f)
+			}
+			if f.Properties["exposure"] != tt.exposure {
+				t.Errorf("exposure property = %q, want %q", f.Properties["exposure"], tt.exposure)
+			}
+			if f.Properties["reason"] != tt.result.Containers[0].Reason {
+				t.Errorf("reason property = %q, want %q", f.Properties["reason"], tt.result.Containers[0].Reason)
+			}
+		})
+	}
+}
+
+func TestBucketRecordsCarryExposure(t *testing.T) {
+	var result payloads.BucketACLCheckResult
+	data := `{"provider":"aws","action":"audit","status":"success","containers":[{"container":"ctk-demo","level":"private","exposure":"public-read","reason":"bucket policy allows Principal *"}]}`
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		t.Fatalf("decode result: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, NDJSON, result); err != nil {
+		t.Fatalf("Write ndjson: %v", err)
+	}
+	want := `{"provider":"aws","type":"bucket-acl","asset":{"container":"ctk-demo","level":"private","exposure":"public-read","reason":"bucket policy allows Principal *"}}` + "\n"
+	if buf.String() != want {
+		t.Errorf("ndjson =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, CSV, result); err != nil {
+		t.Fatalf("Write csv: %v", err)
+	}
+	want = strings.Join([]string{
+		"AssetType,Provider,Container,Level,Exposure,Reason",
+		"bucket-acl,aws,ctk-demo,private,public-read,bucket policy allows Principal *",
+		"",
+	}, "\n")
+	if buf.String() != want {
+		t.Errorf("csv =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
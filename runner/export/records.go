@@ -0,0 +1,199 @@
+package export
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+// Record is one NDJSON line: a single asset (or result row) tagged with the
+// provider, asset type, region and account so consumers can route it without
+// knowing the payload that produced it.
+type Record struct {
+	Provider string `json:"provider"`
+	Type     string `json:"type"`
+	Region   string `json:"region,omitempty"`
+	Account  string `json:"account,omitempty"`
+	Asset    any    `json:"asset"`
+}
+
+// Records flattens result into per-asset records. Cloudlist results yield one
+// record per asset; other payload results yield one record per element of
+// each list field, or a single "result" record when they carry no list.
+func Records(result any) []Record {
+	if cloud, ok := cloudListOf(result); ok {
+		return cloudListRecords(cloud)
+	}
+	provider := providerOf(result)
+	var out []Record
+	if acl, ok := result.(payloads.BucketACLCheckResult); ok && len(acl.Containers) > 0 {
+		for _, v := range bucketExposures(acl) {
+			out = append(out, Record{Provider: provider, Type: bucketACLType, Account: v.Account, Asset: v})
+		}
+		return out
+	}
+	for _, field := range listFields(result) {
+		for i := 0; i < field.value.Len(); i++ {
+			item := field.value.Index(i).Interface()
+			out = append(out, Record{
+				Provider: provider,
+				Type:     field.name,
+				Account:  stringField(item, "Account"),
+				Region:   stringField(item, "Region"),
+				Asset:    item,
+			})
+		}
+	}
+	if len(out) == 0 && result != nil {
+		out = append(out, Record{Provider: provider, Type: "result", Asset: result})
+	}
+	return out
+}
+
+func cloudListRecords(result *payloads.CloudListResult) []Record {
+	var out []Record
+	add := func(kind, region, account string, asset any) {
+		out = append(out, Record{
+			Provider: result.Provider,
+			Type:     kind,
+			Region:   region,
+			Account:  account,
+			Asset:    asset,
+		})
+	}
+	for _, v := range result.Balances {
+		add(schema.AssetBalance, "", v.Account, v)
+	}
+	for _, v := range result.Hosts {
+		add(schema.AssetHost, v.Region, v.Account, v)
+	}
+	for _, v := range result.Storages {
+		add(schema.AssetStorage, v.Region, v.Account, v)
+	}
+	for _, v := range result.Users {
+		add(schema.AssetUser, "", v.Account, v)
+	}
+	for _, v := range result.Databases {
+		add(schema.AssetDatabase, v.Region, v.Account, v)
+	}
+	for _, v := range result.Domains {
+		add(schema.AssetDomain, "", v.Account, v)
+	}
+	for _, v := range result.Logs {
+		add(schema.AssetLog, v.Region, v.Account, v)
+	}
+	for _, v := range result.Security {
+		add(schema.AssetSecurity, v.Region, v.Account, v)
+	}
+	for _, v := range result.SMS.Signs {
+		add("sms-sign", "", "", v)
+	}
+	for _, v := range result.SMS.Templates {
+		add("sms-template", "", "", v)
+	}
+	for _, v := range result.Errors {
+		add("error", "", "", v)
+	}
+	return out
+}
+
+// bucketACLType is the record and CSV section type of a bucket-acl-check
+// container.
+const bucketACLType = "bucket-acl"
+
+// bucketExposure is one bucket-acl-check container as exported. Exposure and
+// Reason are always present, even when empty, so consumers can filter on the
+// effective verdict without probing for the key.
+type bucketExposure struct {
+	Container string `json:"container"`
+	Level     string `json:"level"`
+	Exposure  string `json:"exposure"`
+	Reason    string `json:"reason"`
+	Account   string `json:"account,omitempty"`
+}
+
+func bucketExposures(result payloads.BucketACLCheckResult) []bucketExposure {
+	out := make([]bucketExposure, 0, len(result.Containers))
+	for _, entry := range result.Containers {
+		out = append(out, bucketExposure{
+			Container: entry.Container,
+			Level:     entry.Level,
+			Exposure:  entry.Exposure,
+			Reason:    entry.Reason,
+			Account:   entry.Account,
+		})
+	}
+	return out
+}
+
+// cloudListOf accepts both the pointer returned by CloudList.Result and a
+// plain value.
+func cloudListOf(result any) (*payloads.CloudListResult, bool) {
+	switch v := result.(type) {
+	case *payloads.CloudListResult:
+		return v, v != nil
+	case payloads.CloudListResult:
+		return &v, true
+	}
+	return nil, false
+}
+
+type listField struct {
+	name  string
+	value reflect.Value
+}
+
+// listFields returns the non-empty struct-slice fields of a payload result,
+// named by their JSON key.
+func listFields(result any) []listField {
+	v := reflect.Indirect(reflect.ValueOf(result))
+	if !v.IsValid() || v.Kind() != reflect.Struct {
+		return nil
+	}
+	var out []listField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type.Kind() != reflect.Slice || v.Field(i).Len() == 0 {
+			continue
+		}
+		elem := f.Type.Elem()
+		if elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			continue
+		}
+		out = append(out, listField{name: jsonName(f), value: v.Field(i)})
+	}
+	return out
+}
+
+func providerOf(result any) string {
+	if cloud, ok := cloudListOf(result); ok {
+		return cloud.Provider
+	}
+	return stringField(result, "Provider")
+}
+
+func stringField(v any, name string) string {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return ""
+	}
+	f := rv.FieldByName(name)
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
@@ -46,14 +46,14 @@ var actionSpecs = map[string]actionSpec{
 	"bls": {
 		payload: "bucket-check",
 		minArgs: 0,
-		maxArgs: 1,
-		usage:   "bls [bucket]",
+		maxArgs: -1,
+		usage:   "bls [bucket] [<filter>=<value>...]",
 		summary: "list objects in bucket(s)",
 		build: func(args []string) string {
-			if len(args) == 0 {
-				return "list all"
+			if len(args) == 0 || strings.Contains(args[0], "=") {
+				args = append([]string{"all"}, args...)
 			}
-			return "list " + args[0]
+			return "list " + strings.Join(args, " ")
 		},
 	},
 	"bcnt": {
@@ -72,14 +72,15 @@ var actionSpecs = map[string]actionSpec{
 	"evt": {
 		payload: "event-check",
 		minArgs: 0,
-		maxArgs: 1,
-		usage:   "evt [scope]",
+		maxArgs: -1,
+		usage:   "evt [all|<filter>=<value>...]",
 		summary: "review recent cloud events",
 		build: func(args []string) string {
-			if len(args) == 0 || strings.TrimSpace(args[0]) == "" {
+			scope := strings.TrimSpace(strings.Join(args, " "))
+			if scope == "" {
 				return "dump all"
 			}
-			return "dump " + strings.TrimSpace(args[0])
+			return "dump " + scope
 		},
 	},
 	"shell": {
@@ -89,6 +90,16 @@ var actionSpecs = map[string]actionSpec{
 		usage:   "shell <instance-id> <cmd...> -r <region> (-sh | -cmd)",
 		summary: "run validation on a single instance",
 	},
+	"batch": {
+		payload: "instance-cmd-batch",
+		minArgs: 2,
+		maxArgs: -1,
+		usage:   "batch <region=|os=|name=|tag=|concurrency=>... <cmd...>",
+		summary: "run validation on every instance matching a filter",
+		build: func(args []string) string {
+			return strings.Join(args, " ")
+		},
+	},
 	"rolels": {
 		payload: "role-binding-check",
 		minArgs: 0,
@@ -159,6 +170,26 @@ var actionSpecs = map[string]actionSpec{
 			return "unexpose " + args[0]
 		},
 	},
+	"canary": {
+		payload: "object-access-check",
+		minArgs: 1,
+		maxArgs: 2,
+		usage:   "canary <bucket> [presign]",
+		summary: "write, read and delete a canary object",
+		build: func(args []string) string {
+			return "run " + strings.Join(args, " ")
+		},
+	},
+	"posture": {
+		payload: "iam-posture",
+		minArgs: 0,
+		maxArgs: -1,
+		usage:   "posture [<threshold>=<value>...]",
+		summary: "report IAM credential hygiene by severity",
+		build: func(args []string) string {
+			return strings.Join(args, " ")
+		},
+	},
 	"keyls": {
 		payload: "iam-credential-check",
 		minArgs: 1,
@@ -256,24 +287,28 @@ func resolveCloudlistSelection(defaults []string, selection string) ([]string, e
 	}
 
 	aliases := map[string]string{
-		"all":      "all",
-		"balance":  "balance",
-		"amt":      "balance",
-		"host":     "host",
-		"vm":       "host",
-		"user":     "account",
-		"account":  "account",
-		"iam":      "account",
-		"bucket":   "bucket",
-		"s3":       "bucket",
-		"database": "database",
-		"db":       "database",
-		"rds":      "database",
-		"domain":   "domain",
-		"dns":      "domain",
-		"sms":      "sms",
-		"log":      "log",
-		"sls":      "log",
+		"all":           "all",
+		"balance":       "balance",
+		"amt":           "balance",
+		"host":          "host",
+		"vm":            "host",
+		"user":          "account",
+		"account":       "account",
+		"iam":           "account",
+		"bucket":        "bucket",
+		"s3":            "bucket",
+		"database":      "database",
+		"db":            "database",
+		"rds":           "database",
+		"domain":        "domain",
+		"dns":           "domain",
+		"sms":           "sms",
+		"log":           "log",
+		"sls":           "log",
+		"securitygroup": "securitygroup",
+		"sg":            "securitygroup",
+		"firewall":      "securitygroup",
+		"nsg":           "securitygroup",
 	}
 
 	items := make([]string, 0)
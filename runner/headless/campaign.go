@@ -0,0 +1,123 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
+	"github.com/404tk/cloudtoolkit/runner/campaign"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/table"
+)
+
+// runCampaign executes a playbook. Credentials come from the usual sources
+// (--profile, --creds, --stdin or provider flags) unless the playbook runs
+// against replay. Every sensitive step is approved before the first one
+// runs, and an interrupt stops the steps but still runs the cleanup.
+func runCampaign(args []string, flags commandFlags) int {
+	if len(args) != 1 {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("usage: ctk campaign <playbook.yaml|playbook.json>"))
+	}
+	c, err := campaign.Load(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+
+	var source map[string]string
+	if !c.Replay {
+		if source, err = credentialDataFromFlags(flags); err != nil {
+			return fail(flags.JSON, exitConfigError, err)
+		}
+		for _, step := range c.AllSteps() {
+			config := map[string]string{
+				utils.Provider: step.Provider,
+				utils.Payload:  step.Payload,
+				utils.Metadata: step.Metadata,
+			}
+			if err := requireApproval(config, flags); err != nil {
+				return fail(flags.JSON, exitApprovalRequired, fmt.Errorf("%s: %w", step.Name, err))
+			}
+		}
+	}
+
+	baseEnv := flags.runEnv()
+	prev := env.Active().Clone()
+	env.SetActive(baseEnv)
+	defer env.SetActive(prev)
+	ctx, collector := telemetry.Scope(env.With(context.Background(), baseEnv))
+	if flags.Metrics != "" {
+		defer writeMetrics(collector, flags.Metrics)
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	r := campaign.Runner{
+		Options: func(provider string) (map[string]string, error) {
+			if sourceProvider := strings.TrimSpace(source[utils.Provider]); sourceProvider != "" && sourceProvider != provider {
+				return nil, fmt.Errorf("provider mismatch: step selected %s but credential source is for %s", provider, sourceProvider)
+			}
+			options := make(map[string]string)
+			mergeConfig(options, source)
+			mergeConfig(options, flags.providerOptions())
+			if detect := strings.TrimSpace(flags.Detect); detect != "" {
+				options[utils.Detect] = detect
+			}
+			return options, nil
+		},
+	}
+	report := r.Run(ctx, c)
+
+	code := exitSuccess
+	if !report.Passed() {
+		code = exitCampaignFailed
+	}
+	if flags.structured() {
+		if writeCode := writeResult(flags, report); writeCode != exitSuccess {
+			return writeCode
+		}
+		return code
+	}
+	writeCampaignReport(report)
+	return code
+}
+
+type campaignRow struct {
+	Step     string `table:"Step"`
+	Provider string `table:"Provider"`
+	Payload  string `table:"Payload"`
+	Expect   string `table:"Expect"`
+	Status   string `table:"Status"`
+	Duration string `table:"Duration"`
+	Waited   string `table:"Waited"`
+	Error    string `table:"Error"`
+}
+
+func writeCampaignReport(report campaign.Report) {
+	rows := func(results []campaign.StepResult) []campaignRow {
+		out := make([]campaignRow, 0, len(results))
+		for _, res := range results {
+			out = append(out, campaignRow{
+				Step:     res.Name,
+				Provider: res.Provider,
+				Payload:  res.Payload,
+				Expect:   res.Expect,
+				Status:   res.Status,
+				Duration: res.Duration,
+				Waited:   res.Waited,
+				Error:    res.Error,
+			})
+		}
+		return out
+	}
+	table.Output(rows(report.Steps))
+	if len(report.Cleanup) > 0 {
+		fmt.Fprintln(os.Stdout, "Cleanup:")
+		table.Output(rows(report.Cleanup))
+	}
+	fmt.Fprintf(os.Stdout, "Campaign %s %s (%d steps, %d cleanup)\n",
+		report.Campaign, report.Status, len(report.Steps), len(report.Cleanup))
+}
@@ -0,0 +1,259 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/runner/ledger"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/table"
+)
+
+// cleanupOutcome is one reversed (or failed) ledger entry.
+type cleanupOutcome struct {
+	ID       string `json:"id"`
+	Provider string `json:"provider"`
+	Payload  string `json:"payload"`
+	Resource string `json:"resource"`
+	Undo     string `json:"undo"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Result   any    `json:"result,omitempty"`
+}
+
+// cleanupReport is the structured output of the cleanup subcommands.
+type cleanupReport struct {
+	Entries  []ledger.Entry   `json:"entries,omitempty"`
+	Outcomes []cleanupOutcome `json:"outcomes,omitempty"`
+}
+
+// runCleanup manages the ledger of artifacts left by mutating payloads:
+//
+//	ctk cleanup [list]           outstanding entries
+//	ctk cleanup run [id...]      reverse the given (default: all) entries
+//	ctk cleanup resolve <id...>  close entries without touching the cloud
+//
+// run uses the credential source from the flags when one is given and the
+// cached session the entry was recorded with otherwise.
+func runCleanup(args []string, flags commandFlags) int {
+	action := "list"
+	if len(args) > 0 {
+		action, args = args[0], args[1:]
+	}
+	switch action {
+	case "list":
+		return listCleanup(flags)
+	case "run":
+		return runCleanupEntries(args, flags)
+	case "resolve":
+		return resolveCleanup(args, flags)
+	}
+	return fail(flags.JSON, exitConfigError, fmt.Errorf("usage: ctk cleanup [list | run [id...] | resolve <id...>]"))
+}
+
+func listCleanup(flags commandFlags) int {
+	entries, err := ledger.Default().Outstanding()
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	if flags.structured() {
+		return writeResult(flags, cleanupReport{Entries: entries})
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stdout, "No outstanding artifacts.")
+		return exitSuccess
+	}
+	type entryRow struct {
+		ID         string `table:"ID"`
+		Provider   string `table:"Provider"`
+		Credential string `table:"Credential"`
+		Payload    string `table:"Payload"`
+		Resource   string `table:"Resource"`
+		Undo       string `table:"Undo"`
+		CreatedAt  string `table:"Created"`
+	}
+	rows := make([]entryRow, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, entryRow{
+			ID:         e.ID,
+			Provider:   e.Provider,
+			Credential: e.Credential,
+			Payload:    e.Payload,
+			Resource:   e.Resource,
+			Undo:       e.Undo,
+			CreatedAt:  e.CreatedAt.Local().Format(time.DateTime),
+		})
+	}
+	table.Output(rows)
+	return exitSuccess
+}
+
+func runCleanupEntries(ids []string, flags commandFlags) int {
+	entries, err := selectCleanupEntries(ids)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	if len(entries) == 0 {
+		if flags.structured() {
+			return writeResult(flags, cleanupReport{})
+		}
+		fmt.Fprintln(os.Stdout, "No outstanding artifacts.")
+		return exitSuccess
+	}
+
+	source, err := credentialDataFromFlags(flags)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	for _, e := range entries {
+		config := map[string]string{
+			utils.Provider: e.Provider,
+			utils.Payload:  e.Payload,
+			utils.Metadata: e.Undo,
+		}
+		if err := requireApproval(config, flags); err != nil {
+			return fail(flags.JSON, exitApprovalRequired, fmt.Errorf("%s: %w", e.ID, err))
+		}
+	}
+
+	baseEnv := flags.runEnv()
+	prev := env.Active().Clone()
+	env.SetActive(baseEnv)
+	defer env.SetActive(prev)
+	ctx, stop := signal.NotifyContext(env.With(context.Background(), baseEnv), os.Interrupt)
+	defer stop()
+
+	outcomes := make([]cleanupOutcome, 0, len(entries))
+	failed := 0
+	for _, e := range entries {
+		outcome := cleanupOutcome{
+			ID:       e.ID,
+			Provider: e.Provider,
+			Payload:  e.Payload,
+			Resource: e.Resource,
+			Undo:     e.Undo,
+			Status:   "resolved",
+		}
+		err := ctx.Err()
+		if err == nil {
+			var config map[string]string
+			if config, err = cleanupConfig(e, source, flags); err == nil {
+				outcome.Result, err = payloads.UndoEntry(ctx, e, config)
+			}
+		}
+		if err != nil {
+			outcome.Status = "error"
+			outcome.Error = err.Error()
+			failed++
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	code := exitSuccess
+	if failed > 0 {
+		code = exitPartial
+	}
+	if flags.structured() {
+		if writeCode := writeResult(flags, cleanupReport{Outcomes: outcomes}); writeCode != exitSuccess {
+			return writeCode
+		}
+		return code
+	}
+	type outcomeRow struct {
+		ID       string `table:"ID"`
+		Provider string `table:"Provider"`
+		Resource string `table:"Resource"`
+		Undo     string `table:"Undo"`
+		Status   string `table:"Status"`
+		Error    string `table:"Error"`
+	}
+	rows := make([]outcomeRow, 0, len(outcomes))
+	for _, o := range outcomes {
+		rows = append(rows, outcomeRow{
+			ID:       o.ID,
+			Provider: o.Provider,
+			Resource: o.Resource,
+			Undo:     o.Undo,
+			Status:   o.Status,
+			Error:    o.Error,
+		})
+	}
+	table.Output(rows)
+	return code
+}
+
+// cleanupConfig builds the provider config for reversing e: defaults, then
+// either the flag credential source (when it is for the same provider) or
+// the cached session e was recorded with, then provider flags.
+func cleanupConfig(e ledger.Entry, source map[string]string, flags commandFlags) (map[string]string, error) {
+	config, ok := registry.DefaultConfig(e.Provider)
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider: %s", e.Provider)
+	}
+	sourceProvider := strings.TrimSpace(source[utils.Provider])
+	if sourceProvider == "" || sourceProvider != e.Provider {
+		cached, err := loadProfile(e.Credential)
+		if err != nil {
+			if sourceProvider != "" {
+				return nil, fmt.Errorf("credential source is for %s, not %s, and no cached session matches: %w", sourceProvider, e.Provider, err)
+			}
+			return nil, fmt.Errorf("no cached session for credential %s: %w; pass the original credential with --profile, --creds or --stdin", e.Credential, err)
+		}
+		source = cached
+	}
+	mergeConfig(config, source)
+	mergeConfig(config, flags.providerOptions())
+	config[utils.Provider] = e.Provider
+	return config, nil
+}
+
+func selectCleanupEntries(ids []string) ([]ledger.Entry, error) {
+	outstanding, err := ledger.Default().Outstanding()
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 || (len(ids) == 1 && ids[0] == "all") {
+		return ledger.UndoOrder(outstanding), nil
+	}
+	byID := make(map[string]ledger.Entry, len(outstanding))
+	for _, e := range outstanding {
+		byID[e.ID] = e
+	}
+	out := make([]ledger.Entry, 0, len(ids))
+	for _, id := range ids {
+		e, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("no outstanding ledger entry %s", id)
+		}
+		out = append(out, e)
+	}
+	return ledger.UndoOrder(out), nil
+}
+
+func resolveCleanup(ids []string, flags commandFlags) int {
+	if len(ids) == 0 {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("usage: ctk cleanup resolve <id...>"))
+	}
+	resolved := make([]ledger.Entry, 0, len(ids))
+	for _, id := range ids {
+		e, err := ledger.Default().Resolve(id, ledger.ResolvedManually)
+		if err != nil {
+			return fail(flags.JSON, exitConfigError, err)
+		}
+		resolved = append(resolved, e)
+	}
+	if flags.structured() {
+		return writeResult(flags, cleanupReport{Entries: resolved})
+	}
+	for _, e := range resolved {
+		fmt.Fprintf(os.Stdout, "Resolved %s (%s %s)\n", e.ID, e.Provider, e.Resource)
+	}
+	return exitSuccess
+}
@@ -44,6 +44,9 @@ func buildRunConfig(provider, payload, metadataOverride string, flags commandFla
 
 	config[utils.Provider] = resolvedProvider
 	config[utils.Payload] = payload
+	if detect := strings.TrimSpace(flags.Detect); detect != "" {
+		config[utils.Detect] = detect
+	}
 	if metadata != "" {
 		config[utils.Metadata] = metadata
 	} else if flagMetadata != "" {
@@ -111,6 +114,9 @@ func loadProfile(profile string) (map[string]string, error) {
 	if profile == "" {
 		return nil, errors.New("empty profile name")
 	}
+	if cache.Cfg.Locked() {
+		return nil, fmt.Errorf("credential cache is encrypted: set %s to unlock it", cache.PassphraseEnv)
+	}
 
 	if id, err := findProfileID(profile); err == nil {
 		return decodeSessionJSON(cache.Cfg.CredSelect(id))
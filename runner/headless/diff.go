@@ -0,0 +1,94 @@
+package headless
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/runner/snapshot"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/table"
+)
+
+// runSnapshot enumerates once, persists the result as a snapshot and then
+// renders it the same way a plain `ls` (or `ls --format ...`) would.
+func runSnapshot(ctx context.Context, config map[string]string, flags commandFlags) int {
+	list := payloads.CloudList{}
+	value, err := list.Result(ctx, config)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	result, ok := value.(*payloads.CloudListResult)
+	if !ok || result == nil {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("cloudlist returned no result"))
+	}
+	if err := snapshot.Save(flags.Snapshot, snapshot.New(*result, time.Now())); err != nil {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("write snapshot: %w", err))
+	}
+
+	code := exitSuccess
+	if len(result.Errors) > 0 {
+		code = exitPartial
+	}
+	if flags.structured() {
+		if writeCode := writeResult(flags, result); writeCode != exitSuccess {
+			return writeCode
+		}
+		return code
+	}
+	list.Print(ctx, result)
+	fmt.Fprintf(os.Stderr, "Snapshot written to %s\n", flags.Snapshot)
+	return code
+}
+
+// runDiff compares two snapshots. It exits 0 when they match and exitDrift
+// when any asset was added, removed or changed, so CI can gate on it.
+func runDiff(args []string, flags commandFlags) int {
+	if len(args) != 2 {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("usage: ctk diff <old-snapshot> <new-snapshot>"))
+	}
+	older, err := snapshot.Load(strings.TrimSpace(args[0]))
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	newer, err := snapshot.Load(strings.TrimSpace(args[1]))
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	diff, err := snapshot.Compare(older, newer)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+
+	code := exitSuccess
+	if !diff.Empty() {
+		code = exitDrift
+	}
+	if flags.structured() {
+		if writeCode := writeResult(flags, diff); writeCode != exitSuccess {
+			return writeCode
+		}
+		return code
+	}
+	for _, dup := range diff.Duplicates {
+		logger.Warning(fmt.Sprintf("The %s snapshot lists %s %s %d times; the copies are compared as %s#2, ...",
+			dup.Snapshot, dup.Kind, dup.Key, dup.Count, dup.Key))
+	}
+	if !diff.Empty() {
+		table.Output(diff.Changes)
+	}
+	fmt.Fprintf(os.Stdout, "%s: %d added, %d removed, %d changed (%s -> %s)\n",
+		diff.Provider, diff.Added, diff.Removed, diff.Changed,
+		formatSnapshotTime(diff.From), formatSnapshotTime(diff.To))
+	return code
+}
+
+func formatSnapshotTime(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -8,6 +8,8 @@ import (
 	"strings"
 
 	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
 	"github.com/404tk/cloudtoolkit/utils"
 )
 
@@ -101,6 +103,36 @@ var providerFlagBindings = map[string]providerFlagBinding{
 		valueName: "value",
 		help:      "Base64-encoded GCP service account JSON",
 	},
+	utils.AWSRoleArn: {
+		long:      utils.AWSRoleArn,
+		valueName: "arn",
+		help:      "AWS role ARN to assume (comma-separated for chains)",
+	},
+	utils.AWSExternalId: {
+		long:      utils.AWSExternalId,
+		valueName: "id",
+		help:      "AWS AssumeRole external ID",
+	},
+	utils.AWSSessionName: {
+		long:      utils.AWSSessionName,
+		valueName: "name",
+		help:      "AWS AssumeRole session name",
+	},
+	utils.AWSMFASerial: {
+		long:      utils.AWSMFASerial,
+		valueName: "arn",
+		help:      "AWS MFA device serial",
+	},
+	utils.AWSMFAToken: {
+		long:      utils.AWSMFAToken,
+		valueName: "code",
+		help:      "AWS MFA token code",
+	},
+	utils.AWSOrgRole: {
+		long:      utils.AWSOrgRole,
+		valueName: "name",
+		help:      "AWS role name assumed in each organization member account",
+	},
 }
 
 var providerFlagBindingOrder = []string{
@@ -115,6 +147,12 @@ var providerFlagBindingOrder = []string{
 	utils.AzureTenantId,
 	utils.AzureSubscriptionId,
 	utils.GCPserviceAccountJSON,
+	utils.AWSRoleArn,
+	utils.AWSExternalId,
+	utils.AWSSessionName,
+	utils.AWSMFASerial,
+	utils.AWSMFAToken,
+	utils.AWSOrgRole,
 }
 
 var commonHeadlessFlagSpecs = []headlessFlagSpec{
@@ -185,7 +223,7 @@ var commonHeadlessFlagSpecs = []headlessFlagSpec{
 		short:     "P",
 		kind:      flagValue,
 		valueName: "name",
-		help:      "use cached credential profile",
+		help:      "use cached credential profile (CTK_CACHE_PASSPHRASE unlocks an encrypted cache)",
 		section:   helpCommon,
 		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
 			fs.StringVar(&cfg.Profile, "profile", cfg.Profile, "credential profile name")
@@ -202,6 +240,56 @@ var commonHeadlessFlagSpecs = []headlessFlagSpec{
 			fs.StringVar(&cfg.CredsPath, "creds", cfg.CredsPath, "credentials JSON file")
 		},
 	},
+	{
+		long:      "format",
+		kind:      flagValue,
+		valueName: "name",
+		help:      "output format: text, json, ndjson, csv or findings",
+		section:   helpCommon,
+		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
+			fs.StringVar(&cfg.Format, "format", cfg.Format, "output format")
+		},
+	},
+	{
+		long:      "metrics",
+		kind:      flagValue,
+		valueName: "file",
+		help:      "write the run's API call metrics as OpenMetrics text (- for stderr)",
+		section:   helpCommon,
+		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
+			fs.StringVar(&cfg.Metrics, "metrics", cfg.Metrics, "OpenMetrics output file")
+		},
+	},
+	{
+		long:      "rate-limit",
+		kind:      flagValue,
+		valueName: "rules",
+		help:      "pace API calls: provider[/service[/action]]=rate[:burst],... (requests per second, or off)",
+		section:   helpCommon,
+		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
+			fs.StringVar(&cfg.RateLimit, "rate-limit", cfg.RateLimit, "client-side rate limits")
+		},
+	},
+	{
+		long:      "detect",
+		kind:      flagValue,
+		valueName: "window",
+		help:      "after a mutation, poll the audit trail this long (e.g. 10m) for the matching event",
+		section:   helpCommon,
+		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
+			fs.StringVar(&cfg.Detect, "detect", cfg.Detect, "detection confirmation window")
+		},
+	},
+	{
+		long:      "snapshot",
+		kind:      flagValue,
+		valueName: "file",
+		help:      "write the ls result as a versioned snapshot for ctk diff",
+		section:   helpCommon,
+		bind: func(fs *flag.FlagSet, cfg *commandFlags) {
+			fs.StringVar(&cfg.Snapshot, "snapshot", cfg.Snapshot, "cloudlist snapshot file")
+		},
+	},
 	{
 		long:      "metadata",
 		kind:      flagValue,
@@ -229,6 +317,15 @@ func parseFlags(args []string) (commandFlags, []string, error) {
 	if err := fs.Parse(normalized); err != nil {
 		return commandFlags{}, nil, err
 	}
+	if err := cfg.resolveFormat(); err != nil {
+		return cfg, nil, err
+	}
+	if _, err := payloads.ParseDetectionWindow(cfg.Detect); err != nil {
+		return cfg, nil, err
+	}
+	if _, err := ratelimit.Parse(cfg.RateLimit); err != nil {
+		return cfg, nil, err
+	}
 	return cfg, fs.Args(), nil
 }
 
@@ -254,6 +351,13 @@ func wantsHelp(args []string) bool {
 				shellTargetSeen = false
 				continue
 			}
+			// batch has no target argument; its command starts right after
+			// the filters.
+			if arg == "batch" && !sawShell {
+				sawShell = true
+				shellTargetSeen = true
+				continue
+			}
 			if sawShell && !shellTargetSeen {
 				shellTargetSeen = true
 			}
@@ -287,6 +391,11 @@ func normalizeArgs(args []string) ([]string, error) {
 				shellTargetSeen = false
 				continue
 			}
+			if arg == "batch" && !sawShell {
+				sawShell = true
+				shellTargetSeen = true
+				continue
+			}
 			if sawShell && !shellTargetSeen {
 				shellTargetSeen = true
 			}
@@ -382,7 +491,7 @@ func providerOptionFlagSpecs() []headlessFlagSpec {
 func orderedProviderOptionNames() []string {
 	available := make(map[string]struct{})
 	for _, name := range registry.OptionNames() {
-		if name == utils.Payload || name == utils.Metadata {
+		if name == utils.Payload || name == utils.Metadata || name == utils.Format || name == utils.Detect {
 			continue
 		}
 		available[name] = struct{}{}
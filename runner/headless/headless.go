@@ -11,7 +11,7 @@ import (
 	"github.com/404tk/cloudtoolkit/pkg/providers"
 	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
-	"github.com/404tk/cloudtoolkit/runner"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 	"github.com/404tk/cloudtoolkit/runner/payloads"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/confirm"
@@ -48,6 +48,18 @@ func Run(args []string) int {
 	if flags.Describe {
 		return fail(flags.JSON, exitConfigError, fmt.Errorf("`-v` cannot be combined with other commands"))
 	}
+	if command == "diff" {
+		return runDiff(remaining[1:], flags)
+	}
+	if command == "campaign" {
+		return runCampaign(remaining[1:], flags)
+	}
+	if command == "cleanup" {
+		return runCleanup(remaining[1:], flags)
+	}
+	if command == "serve" {
+		return runServe(remaining[1:], flags)
+	}
 	if providers.Supports(command) {
 		return runShort(command, remaining[1:], flags)
 	}
@@ -97,11 +109,14 @@ func executeRun(providerName, payloadName, metadataOverride string, flags comman
 	if capability != "" && !registry.SupportsCapability(provider, capability) {
 		return fail(flags.JSON, exitUnsupported, fmt.Errorf("%s does not support %s", provider, payloadName))
 	}
+	if flags.Snapshot != "" && payloadName != "cloudlist" {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("--snapshot only applies to ls (cloudlist)"))
+	}
 	if err := requireApproval(config, flags); err != nil {
 		return fail(flags.JSON, exitApprovalRequired, err)
 	}
 
-	baseEnv := runner.DefaultEnv()
+	baseEnv := flags.runEnv()
 	if payloadName == "cloudlist" {
 		items, err := resolveCloudlistSelection(baseEnv.Cloudlist, metadataOverride)
 		if err != nil {
@@ -113,20 +128,26 @@ func executeRun(providerName, payloadName, metadataOverride string, flags comman
 	env.SetActive(baseEnv)
 	defer env.SetActive(prev)
 
-	ctx := env.With(context.Background(), baseEnv)
-	if !flags.JSON {
+	ctx, collector := telemetry.Scope(env.With(context.Background(), baseEnv))
+	if flags.Metrics != "" {
+		defer writeMetrics(collector, flags.Metrics)
+	}
+	if flags.Snapshot != "" {
+		return runSnapshot(ctx, config, flags)
+	}
+	if !flags.structured() {
 		payload.Run(ctx, config)
 		return exitSuccess
 	}
 	producer, ok := payload.(payloads.ResultProducer)
 	if !ok {
-		return fail(flags.JSON, exitUnsupported, fmt.Errorf("payload %s does not support structured headless output yet; retry without --json", payloadName))
+		return fail(flags.JSON, exitUnsupported, fmt.Errorf("payload %s does not support structured headless output yet; retry without --json or --format", payloadName))
 	}
 
 	result, err := producer.Result(ctx, config)
 	if err != nil {
 		if resultErr, ok := err.(payloads.ResultError); ok {
-			if writeCode := writeJSON(resultErr.ResultPayload()); writeCode != exitSuccess {
+			if writeCode := writeResult(flags, resultErr.ResultPayload()); writeCode != exitSuccess {
 				return writeCode
 			}
 			return resultErr.ExitCode()
@@ -141,7 +162,7 @@ func executeRun(providerName, payloadName, metadataOverride string, flags comman
 	if cloud, ok := result.(payloads.CloudListResult); ok && len(cloud.Errors) > 0 {
 		code = exitPartial
 	}
-	if writeCode := writeJSON(result); writeCode != exitSuccess {
+	if writeCode := writeResult(flags, result); writeCode != exitSuccess {
 		return writeCode
 	}
 	return code
@@ -175,7 +196,7 @@ func canInferProvider(flags commandFlags) bool {
 }
 
 func canPromptForApproval(flags commandFlags) bool {
-	if flags.JSON || flags.Stdin {
+	if flags.structured() || flags.Stdin {
 		return false
 	}
 	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
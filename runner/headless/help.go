@@ -15,6 +15,12 @@ func writeHelp() int {
 	b.WriteString("  ctk -h | --help          show this help\n")
 	b.WriteString("  ctk <provider> <action> [args] [flags]\n")
 	b.WriteString("  ctk <action> [args] (-P <profile> | --creds <file> | --stdin) [flags]\n")
+	b.WriteString("  ctk diff <old> <new>     compare two ls snapshots (exit 1 on drift)\n")
+	b.WriteString("  ctk campaign <playbook>  run a YAML/JSON validation playbook (exit 1 on failure)\n")
+	b.WriteString("  ctk cleanup [list]       show artifacts left by mutating payloads\n")
+	b.WriteString("  ctk cleanup run [id...]  reverse outstanding artifacts (-y; exit 2 if any fail)\n")
+	b.WriteString("  ctk cleanup resolve <id> mark an artifact removed without touching the cloud\n")
+	b.WriteString("  ctk serve [addr]         HTTP/JSON job API on 127.0.0.1:8421 (bearer token: CTK_SERVE_TOKEN)\n")
 
 	writeHelpActions(&b)
 	writeHelpFlags(&b, "Common flags:", helpCommon)
@@ -5,9 +5,48 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 	"github.com/404tk/cloudtoolkit/runner"
+	"github.com/404tk/cloudtoolkit/runner/export"
+	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
+// resolveFormat validates --format and reconciles it with --json, which is
+// shorthand for --format json. JSON-based formats also switch errors to JSON
+// so a consumer reading stdout never has to handle plain text.
+func (f *commandFlags) resolveFormat() error {
+	format, err := export.Parse(f.Format)
+	if err != nil {
+		return err
+	}
+	if f.JSON {
+		if f.Format != "" && format != export.JSON {
+			return fmt.Errorf("--json cannot be combined with --format %s", format)
+		}
+		format = export.JSON
+	}
+	f.Format = format
+	switch format {
+	case export.JSON, export.NDJSON, export.Findings:
+		f.JSON = true
+	}
+	return nil
+}
+
+// structured reports whether results go through the export writers instead
+// of the payload's table output.
+func (f commandFlags) structured() bool {
+	return export.Structured(f.Format)
+}
+
+func writeResult(flags commandFlags, v any) int {
+	if err := export.Write(os.Stdout, flags.Format, v); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return exitConfigError
+	}
+	return exitSuccess
+}
+
 func writeJSON(v any) int {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -52,3 +91,22 @@ func fail(jsonOutput bool, code int, err error) int {
 	fmt.Fprintln(os.Stderr, err.Error())
 	return code
 }
+
+// writeMetrics dumps the API calls recorded by collector as OpenMetrics text
+// to path, or to stderr for "-". A failed write is only logged: the run
+// itself already finished.
+func writeMetrics(collector *telemetry.Collector, path string) {
+	if path == "-" {
+		_ = collector.WriteOpenMetrics(os.Stderr)
+		return
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		logger.Warning("Metrics not written:", err.Error())
+		return
+	}
+	defer file.Close()
+	if err := collector.WriteOpenMetrics(file); err != nil {
+		logger.Warning("Metrics not written:", err.Error())
+	}
+}
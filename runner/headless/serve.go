@@ -0,0 +1,96 @@
+package headless
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/runner/server"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// ServeTokenEnv supplies the bearer token for `ctk serve`; a random token is
+// generated and printed when it is unset.
+const ServeTokenEnv = "CTK_SERVE_TOKEN"
+
+const defaultServeAddr = "127.0.0.1:8421"
+
+// serveShutdownGrace is how long running jobs get to return after cancellation.
+const serveShutdownGrace = 10 * time.Second
+
+// runServe starts the HTTP job API and blocks until interrupted.
+//
+//	ctk serve [addr]
+func runServe(args []string, flags commandFlags) int {
+	if len(args) > 1 {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("usage: ctk serve [addr]"))
+	}
+	addr := defaultServeAddr
+	if len(args) == 1 {
+		addr = strings.TrimSpace(args[0])
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, fmt.Errorf("invalid listen address %q: %w", addr, err))
+	}
+
+	token := strings.TrimSpace(os.Getenv(ServeTokenEnv))
+	if token == "" {
+		var b [24]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return fail(flags.JSON, exitConfigError, err)
+		}
+		token = hex.EncodeToString(b[:])
+		fmt.Fprintf(os.Stderr, "Bearer token (set %s to choose one): %s\n", ServeTokenEnv, token)
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		logger.Warning(fmt.Sprintf("Listening on %s exposes the job API beyond this host.", addr))
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fail(flags.JSON, exitConfigError, err)
+	}
+	api := &server.Server{
+		Token:   token,
+		Profile: loadProfile,
+		Env:     flags.runEnv,
+	}
+	httpServer := &http.Server{
+		Handler:           api.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+	logger.Info(fmt.Sprintf("Serving the job API on http://%s/v1/jobs", listener.Addr()))
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fail(flags.JSON, exitConfigError, err)
+		}
+	case <-ctx.Done():
+	}
+	logger.Info("Shutting down, cancelling outstanding jobs ...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownGrace)
+	defer cancel()
+	_ = httpServer.Shutdown(shutdownCtx)
+	if err := api.Shutdown(shutdownCtx); err != nil {
+		logger.Warning("Some jobs did not stop in time:", err.Error())
+	}
+	return exitSuccess
+}
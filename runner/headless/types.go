@@ -3,10 +3,15 @@ package headless
 import (
 	"flag"
 	"strings"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/runner"
 )
 
 const (
 	exitSuccess          = 0
+	exitDrift            = 1
+	exitCampaignFailed   = 1
 	exitPartial          = 2
 	exitApprovalRequired = 3
 	exitConfigError      = 4
@@ -24,6 +29,11 @@ type commandFlags struct {
 	Profile   string
 	CredsPath string
 	Metadata  string
+	Snapshot  string
+	Format    string
+	Detect    string
+	Metrics   string
+	RateLimit string
 
 	providerValues map[string]string
 }
@@ -57,6 +67,18 @@ func (f commandFlags) providerOptions() map[string]string {
 	return items
 }
 
+// runEnv returns the default env with --rate-limit layered over its limits.
+func (f commandFlags) runEnv() *env.Env {
+	e := runner.DefaultEnv()
+	if limit := strings.TrimSpace(f.RateLimit); limit != "" {
+		if e.RateLimit != "" {
+			limit = e.RateLimit + "," + limit
+		}
+		e.RateLimit = limit
+	}
+	return e
+}
+
 type codedError interface {
 	error
 	ErrorCode() string
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/ratelimit"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 	"gopkg.in/yaml.v3"
 )
@@ -34,6 +36,7 @@ type Config struct {
 		LogFormat      string `yaml:"log_format"`
 	} `yaml:"common"`
 	Cloudlist       []string              `yaml:"cloudlist"`
+	RateLimits      map[string]string     `yaml:"rate_limits"`
 	IAMUserCheck    userValidationConfig  `yaml:"iam-user-check"`
 	RDSAccountCheck databaseAccountConfig `yaml:"rds-account-check"`
 }
@@ -63,8 +66,8 @@ func resolveConfigPath() string {
 }
 
 // InitConfig parses config.yaml (CWD or XDG) and returns the resulting *env.Env.
-// As a side effect it pins the same env via env.SetActive so capability methods
-// without ctx (EventDump, parseRDSAccount) can fall back to env.Active().
+// As a side effect it pins the same env via env.SetActive so env.From(ctx)
+// falls back to it when no per-run env is attached.
 //
 // cmd/main.go calls this once when entering the interactive console.
 func InitConfig() *env.Env {
@@ -105,6 +108,7 @@ func configToEnv(cfg Config) *env.Env {
 	} else {
 		e.RunTimeout = 10 * time.Minute
 	}
+	e.RateLimit = rateLimitSpec(cfg.RateLimits)
 	logFormat := strings.ToLower(strings.TrimSpace(cfg.Common.LogFormat))
 	logger.SetFormat(logger.Format(logFormat))
 
@@ -120,6 +124,40 @@ func configToEnv(cfg Config) *env.Env {
 	return e
 }
 
+// rateLimitScopeAll is the rate_limits key standing for the bare "off" of a
+// ratelimit spec, which a YAML map cannot express on its own.
+const rateLimitScopeAll = "all"
+
+// rateLimitSpec flattens the rate_limits section into a ratelimit.Parse
+// spec, sorted so equal sections share one limiter. `all: off` drops the
+// built-in rules before the other entries apply. An invalid section is
+// reported and ignored.
+func rateLimitSpec(limits map[string]string) string {
+	scopes := make([]string, 0, len(limits))
+	items := []string{}
+	for scope, value := range limits {
+		if strings.EqualFold(strings.TrimSpace(scope), rateLimitScopeAll) {
+			if !strings.EqualFold(strings.TrimSpace(value), ratelimit.Off) {
+				logger.Error(fmt.Sprintf("Ignoring rate_limits: %s accepts only %q, got %q", rateLimitScopeAll, ratelimit.Off, value))
+				return ""
+			}
+			items = append(items, ratelimit.Off)
+			continue
+		}
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	for _, scope := range scopes {
+		items = append(items, scope+"="+limits[scope])
+	}
+	spec := strings.Join(items, ",")
+	if _, err := ratelimit.Parse(spec); err != nil {
+		logger.Error(fmt.Sprintf("Ignoring rate_limits: %v", err))
+		return ""
+	}
+	return spec
+}
+
 const defaultConfigFile = `common:
   log_enable: false
   list_policies: false
@@ -136,6 +174,7 @@ cloudlist:
   - bucket
   - sms
   - log
+  - securitygroup
 
 iam-user-check:
   action: add
@@ -145,4 +184,13 @@ iam-user-check:
 rds-account-check:
   username: ctkguest
   password: 1QAZ2wsx@Asdlkj
+
+# Client-side request pacing, layered over built-in per-cloud quotas.
+# provider[/service[/action]]: requests per second[:burst], or off.
+# all: off drops the built-in quotas; the entries below still apply.
+rate_limits:
+  # all: "off"
+  # aws/ec2: "10:40"
+  # tencent/cvm/DescribeInstances: "5"
+  # gcp: "off"
 `
@@ -0,0 +1,31 @@
+package runner
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestRateLimitSpec(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{name: "scopes", yaml: "rate_limits:\n  gcp: off\n  aws/ec2: \"10:40\"\n", want: "aws/ec2=10:40,gcp=off"},
+		{name: "all off", yaml: "rate_limits:\n  all: off\n  aws/ec2: \"5\"\n", want: "off,aws/ec2=5"},
+		{name: "all with a rate", yaml: "rate_limits:\n  all: \"5\"\n", want: ""},
+		{name: "invalid", yaml: "rate_limits:\n  aws: fast\n", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg Config
+			if err := yaml.Unmarshal([]byte(tt.yaml), &cfg); err != nil {
+				t.Fatalf("unmarshal: %v", err)
+			}
+			if got := rateLimitSpec(cfg.RateLimits); got != tt.want {
+				t.Fatalf("rateLimitSpec() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
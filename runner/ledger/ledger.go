@@ -0,0 +1,305 @@
+// Package ledger records the artifacts that mutating validation payloads
+// leave in a cloud account (users, access keys, role bindings, public
+// containers, database accounts) so they can be reversed later even if the
+// operator forgets the matching delete.
+package ledger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Resolutions recorded on a closed entry.
+const (
+	// ResolvedByPayload marks an artifact removed by running the reverse
+	// action directly (e.g. `iam-user-check del`).
+	ResolvedByPayload = "payload"
+	// ResolvedByCleanup marks an artifact reversed by the cleanup command.
+	ResolvedByCleanup = "cleanup"
+	// ResolvedManually marks an entry closed without running anything,
+	// typically because the artifact was already removed out of band.
+	ResolvedManually = "manual"
+)
+
+// ErrNotFound is returned for an unknown entry ID.
+var ErrNotFound = errors.New("ledger entry not found")
+
+// Entry is one artifact. Resource identifies it within the provider and
+// credential (e.g. "user:ctk-demo"); Undo is the payload metadata that
+// removes it.
+type Entry struct {
+	ID         string     `json:"id"`
+	Provider   string     `json:"provider"`
+	Credential string     `json:"credential_uuid"`
+	Payload    string     `json:"payload"`
+	Action     string     `json:"action"`
+	Resource   string     `json:"resource"`
+	Undo       string     `json:"undo"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Resolution string     `json:"resolution,omitempty"`
+}
+
+// Open reports whether the artifact is still outstanding.
+func (e Entry) Open() bool {
+	return e.ResolvedAt == nil
+}
+
+// Ledger is the on-disk entry list. Every call reloads the file so the
+// console, headless runs and campaigns share one view. Writers hold a lock
+// file next to the ledger for the whole read-modify-write, so separate
+// processes recording at the same time do not drop each other's entries;
+// readers rely on save replacing the file by rename.
+type Ledger struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Lock file tuning. A lock older than lockStale is left over from a process
+// that died mid-write and is taken over; no write holds it for that long.
+const (
+	lockPoll    = 10 * time.Millisecond
+	lockTimeout = 5 * time.Second
+	lockStale   = 30 * time.Second
+)
+
+var (
+	defaultOnce   sync.Once
+	defaultLedger *Ledger
+)
+
+// Default returns the ledger kept next to the credential cache under
+// ~/.config/cloudtoolkit.
+func Default() *Ledger {
+	defaultOnce.Do(func() {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		defaultLedger = &Ledger{Path: filepath.Join(home, ".config", "cloudtoolkit", "ledger.json")}
+	})
+	return defaultLedger
+}
+
+// Entries returns every entry, open and resolved, oldest first.
+func (l *Ledger) Entries() ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load()
+}
+
+// Outstanding returns the open entries, oldest first.
+func (l *Ledger) Outstanding() ([]Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return nil, err
+	}
+	out := entries[:0]
+	for _, e := range entries {
+		if e.Open() {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// UndoOrder returns entries in the order they must be reversed: newest
+// first, so an access key or role binding is removed before the user it
+// hangs off. Entries with the same CreatedAt keep their reverse file order.
+func UndoOrder(entries []Entry) []Entry {
+	out := make([]Entry, len(entries))
+	for i, e := range entries {
+		out[len(entries)-1-i] = e
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].CreatedAt.After(out[j].CreatedAt)
+	})
+	return out
+}
+
+// Record adds e as an open entry. An open entry for the same artifact is
+// refreshed instead of duplicated, so repeating an `add` leaves one entry.
+func (l *Ledger) Record(e Entry) (Entry, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return Entry{}, err
+	}
+	defer unlock()
+	entries, err := l.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now().UTC()
+	}
+	for i, existing := range entries {
+		if existing.Open() && sameArtifact(existing, e) {
+			entries[i].Action = e.Action
+			entries[i].Undo = e.Undo
+			entries[i].CreatedAt = e.CreatedAt
+			return entries[i], l.save(entries)
+		}
+	}
+	if e.ID == "" {
+		e.ID = newID()
+	}
+	entries = append(entries, e)
+	return e, l.save(entries)
+}
+
+// ResolveArtifact closes every open entry for the artifact described by e
+// (provider, credential, payload and resource) and returns how many closed.
+func (l *Ledger) ResolveArtifact(e Entry, resolution string) (int, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+	entries, err := l.load()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	n := 0
+	for i, existing := range entries {
+		if existing.Open() && sameArtifact(existing, e) {
+			entries[i].ResolvedAt = &now
+			entries[i].Resolution = resolution
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, nil
+	}
+	return n, l.save(entries)
+}
+
+// Resolve closes the entry with id. Resolving a closed entry is a no-op.
+func (l *Ledger) Resolve(id, resolution string) (Entry, error) {
+	unlock, err := l.lock()
+	if err != nil {
+		return Entry{}, err
+	}
+	defer unlock()
+	entries, err := l.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	for i, existing := range entries {
+		if existing.ID != id {
+			continue
+		}
+		if !existing.Open() {
+			return existing, nil
+		}
+		now := time.Now().UTC()
+		entries[i].ResolvedAt = &now
+		entries[i].Resolution = resolution
+		return entries[i], l.save(entries)
+	}
+	return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// Lookup returns the entry with id.
+func (l *Ledger) Lookup(id string) (Entry, error) {
+	entries, err := l.Entries()
+	if err != nil {
+		return Entry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// lock takes the in-process mutex and then the cross-process lock file,
+// returning the function that releases both.
+func (l *Ledger) lock() (func(), error) {
+	l.mu.Lock()
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0700); err != nil {
+		l.mu.Unlock()
+		return nil, err
+	}
+	path := l.Path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() {
+				os.Remove(path)
+				l.mu.Unlock()
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			l.mu.Unlock()
+			return nil, err
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			l.mu.Unlock()
+			return nil, fmt.Errorf("ledger is locked by another process; remove %s if none is running", path)
+		}
+		time.Sleep(lockPoll)
+	}
+}
+
+func sameArtifact(a, b Entry) bool {
+	return a.Provider == b.Provider &&
+		a.Credential == b.Credential &&
+		a.Payload == b.Payload &&
+		a.Resource == b.Resource
+}
+
+func (l *Ledger) load() ([]Entry, error) {
+	data, err := os.ReadFile(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", l.Path, err)
+	}
+	return entries, nil
+}
+
+// save writes through a temp file so an interrupted write never truncates
+// the ledger.
+func (l *Ledger) save(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0700); err != nil {
+		return err
+	}
+	tmp := l.Path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.Path)
+}
+
+func newID() string {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("%08x", time.Now().UnixNano()&0xffffffff)
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,189 @@
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestUndoOrderRemovesKeyBeforeUser(t *testing.T) {
+	l := &Ledger{Path: filepath.Join(t.TempDir(), "ledger.json")}
+	created := time.Date(2026, 4, 20, 8, 0, 0, 0, time.UTC)
+	user, err := l.Record(Entry{
+		Provider: "aws", Credential: "cred-1", Payload: "iam-user-check",
+		Action: "add", Resource: "user:ctk-demo", Undo: "del ctk-demo", CreatedAt: created,
+	})
+	if err != nil {
+		t.Fatalf("Record user: %v", err)
+	}
+	key, err := l.Record(Entry{
+		Provider: "aws", Credential: "cred-1", Payload: "iam-credential-check",
+		Action: "create", Resource: "access-key:AKIA1", Undo: "delete ctk-demo AKIA1", CreatedAt: created.Add(time.Second),
+	})
+	if err != nil {
+		t.Fatalf("Record key: %v", err)
+	}
+
+	outstanding, err := l.Outstanding()
+	if err != nil {
+		t.Fatalf("Outstanding: %v", err)
+	}
+	got := UndoOrder(outstanding)
+	if len(got) != 2 || got[0].ID != key.ID || got[1].ID != user.ID {
+		t.Fatalf("expected key before user, got %+v", got)
+	}
+}
+
+func TestUndoOrderReversesFileOrderOnEqualTimes(t *testing.T) {
+	at := time.Date(2026, 4, 20, 8, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ID: "user", CreatedAt: at},
+		{ID: "binding", CreatedAt: at},
+		{ID: "key", CreatedAt: at},
+	}
+	got := UndoOrder(entries)
+	if got[0].ID != "key" || got[1].ID != "binding" || got[2].ID != "user" {
+		t.Fatalf("unexpected order: %+v", got)
+	}
+	if entries[0].ID != "user" {
+		t.Fatal("UndoOrder must not reorder its input")
+	}
+}
+
+func TestRecordRefreshesOpenArtifact(t *testing.T) {
+	l := &Ledger{Path: filepath.Join(t.TempDir(), "ledger.json")}
+	user := Entry{Provider: "aws", Credential: "cred-1", Payload: "iam-user-check", Action: "add", Resource: "user:ctk-demo", Undo: "del ctk-demo"}
+	first, err := l.Record(user)
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	user.Undo = "del ctk-demo --force"
+	second, err := l.Record(user)
+	if err != nil {
+		t.Fatalf("Record again: %v", err)
+	}
+	if second.ID != first.ID || second.Undo != user.Undo {
+		t.Fatalf("repeated Record = %+v, want ID %s with the new undo", second, first.ID)
+	}
+	if entries, _ := l.Entries(); len(entries) != 1 {
+		t.Fatalf("expected one entry, got %+v", entries)
+	}
+
+	if _, err := l.Resolve(first.ID, ResolvedByPayload); err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	third, err := l.Record(user)
+	if err != nil {
+		t.Fatalf("Record after resolve: %v", err)
+	}
+	if third.ID == first.ID {
+		t.Fatal("recording a resolved artifact must open a new entry")
+	}
+	if entries, _ := l.Entries(); len(entries) != 2 {
+		t.Fatalf("expected two entries, got %+v", entries)
+	}
+}
+
+func TestResolveArtifact(t *testing.T) {
+	l := &Ledger{Path: filepath.Join(t.TempDir(), "ledger.json")}
+	user := Entry{Provider: "aws", Credential: "cred-1", Payload: "iam-user-check", Action: "add", Resource: "user:ctk-demo"}
+	other := user
+	other.Credential = "cred-2"
+	for _, e := range []Entry{user, other} {
+		if _, err := l.Record(e); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	if n, err := l.ResolveArtifact(user, ResolvedByPayload); err != nil || n != 1 {
+		t.Fatalf("ResolveArtifact = %d, %v; want 1", n, err)
+	}
+	if n, err := l.ResolveArtifact(user, ResolvedByPayload); err != nil || n != 0 {
+		t.Fatalf("second ResolveArtifact = %d, %v; want 0", n, err)
+	}
+	outstanding, err := l.Outstanding()
+	if err != nil {
+		t.Fatalf("Outstanding: %v", err)
+	}
+	if len(outstanding) != 1 || outstanding[0].Credential != "cred-2" {
+		t.Fatalf("entry for another credential must stay open, got %+v", outstanding)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	l := &Ledger{Path: filepath.Join(t.TempDir(), "ledger.json")}
+	if _, err := l.Resolve("missing", ResolvedManually); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Resolve on an empty ledger = %v, want ErrNotFound", err)
+	}
+	e, err := l.Record(Entry{Provider: "aws", Credential: "cred-1", Payload: "iam-user-check", Resource: "user:ctk-demo"})
+	if err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if _, err := l.Resolve("missing", ResolvedManually); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Resolve(unknown) = %v, want ErrNotFound", err)
+	}
+
+	closed, err := l.Resolve(e.ID, ResolvedByCleanup)
+	if err != nil || closed.Open() || closed.Resolution != ResolvedByCleanup {
+		t.Fatalf("Resolve = %+v, %v", closed, err)
+	}
+	again, err := l.Resolve(e.ID, ResolvedManually)
+	if err != nil {
+		t.Fatalf("Resolve closed entry: %v", err)
+	}
+	if again.Resolution != ResolvedByCleanup || !again.ResolvedAt.Equal(*closed.ResolvedAt) {
+		t.Fatalf("resolving a closed entry changed it: %+v", again)
+	}
+}
+
+func TestConcurrentWritersKeepEveryEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.json")
+	// Separate Ledger values share only the file, like separate processes.
+	writers := []*Ledger{{Path: path}, {Path: path}, {Path: path}}
+	const perWriter = 20
+
+	var wg sync.WaitGroup
+	for w, l := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				resource := fmt.Sprintf("user:ctk-%d-%d", w, i)
+				if _, err := l.Record(Entry{Provider: "aws", Credential: "cred-1", Payload: "iam-user-check", Resource: resource}); err != nil {
+					t.Errorf("Record %s: %v", resource, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	entries, err := (&Ledger{Path: path}).Entries()
+	if err != nil {
+		t.Fatalf("Entries: %v", err)
+	}
+	if len(entries) != len(writers)*perWriter {
+		t.Fatalf("expected %d entries, got %d", len(writers)*perWriter, len(entries))
+	}
+	if _, err := os.Stat(path + ".lock"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("lock file left behind: %v", err)
+	}
+}
+
+func TestStaleLockIsTakenOver(t *testing.T) {
+	l := &Ledger{Path: filepath.Join(t.TempDir(), "ledger.json")}
+	lock := l.Path + ".lock"
+	if err := os.WriteFile(lock, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Record(Entry{Provider: "aws", Credential: "cred-1", Payload: "iam-user-check", Resource: "user:ctk-demo"}); err != nil {
+		t.Fatalf("Record with a stale lock: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -23,12 +24,16 @@ type BucketACLCheckResult struct {
 	Message    string                  `json:"message,omitempty"`
 	Status     string                  `json:"status"`
 	Error      string                  `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
 }
 
 type bucketACLEntryJSON struct {
 	Account   string `json:"account,omitempty"`
 	Container string `json:"container"`
 	Level     string `json:"level"`
+	Exposure  string `json:"exposure,omitempty"`
+	Reason    string `json:"reason,omitempty"`
 }
 
 type bucketACLAction struct {
@@ -58,6 +63,8 @@ func (p BucketACLCheck) Run(ctx context.Context, config map[string]string) {
 			Account   string `table:"Account"`
 			Container string `table:"Container"`
 			Level     string `table:"Public Access"`
+			Exposure  string `table:"Effective"`
+			Reason    string `table:"Reason"`
 		}
 		rows := make([]aclRow, 0, len(result.Containers))
 		for _, entry := range result.Containers {
@@ -65,6 +72,8 @@ func (p BucketACLCheck) Run(ctx context.Context, config map[string]string) {
 				Account:   entry.Account,
 				Container: entry.Container,
 				Level:     entry.Level,
+				Exposure:  entry.Exposure,
+				Reason:    entry.Reason,
 			})
 		}
 		table.Output(rows)
@@ -72,6 +81,7 @@ func (p BucketACLCheck) Run(ctx context.Context, config map[string]string) {
 	if result.Message != "" {
 		logger.Warning(result.Message)
 	}
+	logDetection(result.Detection)
 }
 
 func (p BucketACLCheck) Result(ctx context.Context, config map[string]string) (any, error) {
@@ -79,6 +89,9 @@ func (p BucketACLCheck) Result(ctx context.Context, config map[string]string) (a
 	if err != nil {
 		return nil, err
 	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
 
 	i, err := inventoryFromConfig(config)
 	if err != nil {
@@ -90,6 +103,7 @@ func (p BucketACLCheck) Result(ctx context.Context, config map[string]string) (a
 		return nil, fmt.Errorf("%s does not support bucket-acl-check", i.Providers.Name())
 	}
 
+	started := time.Now()
 	aclResult, err := mgr.BucketACL(ctx, parsed.Action, parsed.Container, parsed.Level)
 
 	result := BucketACLCheckResult{
@@ -116,9 +130,21 @@ func (p BucketACLCheck) Result(ctx context.Context, config map[string]string) (a
 			Account:   entry.Account,
 			Container: entry.Container,
 			Level:     entry.Level,
+			Exposure:  entry.Exposure,
+			Reason:    entry.Reason,
 		})
 	}
 	result.Status = "success"
+	containerArtifact := artifact{payload: "bucket-acl-check", action: parsed.Action, resource: "container:" + result.Container}
+	switch parsed.Action {
+	case "expose":
+		containerArtifact.undo = []string{"unexpose", result.Container}
+		recordArtifact(i.Providers, config, containerArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{shared: bucketACLAPIs, resources: []string{result.Container}})
+	case "unexpose":
+		resolveArtifact(ctx, i.Providers, config, containerArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{shared: bucketACLAPIs, resources: []string{result.Container}})
+	}
 	return result, nil
 }
 
@@ -136,6 +162,7 @@ func (p BucketACLCheck) Help() HelpDoc {
 			"set metadata <action> [container] [level]",
 			"`action` is typically `audit`, `expose`, or `unexpose`.",
 			"`level` is only meaningful for `expose`: provider-specific (e.g. Azure `Blob` / `Container`).",
+			"`audit` reports the ACL level plus the effective exposure after bucket policies, IAM bindings and public access blocks: public-read-write, public-read, authenticated, conditional, private, or unknown when a layer could not be read.",
 		},
 		MetadataExamples: []string{
 			"set metadata audit",
@@ -144,7 +171,7 @@ func (p BucketACLCheck) Help() HelpDoc {
 			"set metadata unexpose ctk-demo-container",
 		},
 		MetadataSuggestions: []Suggestion{
-			{Text: "audit", Description: "list public-access state for every container in scope"},
+			{Text: "audit", Description: "list ACL level and effective exposure for every container in scope"},
 			{Text: "audit <container>", Description: "show public-access state for a single container"},
 			{Text: "expose <container> <level>", Description: "set public-access on a container to validate exposure detection"},
 			{Text: "unexpose <container>", Description: "revert public-access back to private"},
@@ -1,10 +1,19 @@
 package payloads
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -21,15 +30,25 @@ type BucketCheckResult struct {
 	ObjectCount int64                 `json:"object_count,omitempty"`
 	Objects     []schema.BucketObject `json:"objects,omitempty"`
 	Message     string                `json:"message,omitempty"`
-	Status      string                `json:"status"`
-	Error       string                `json:"error,omitempty"`
+	// Truncated is set when the listing matched more objects than are kept
+	// for display; Output then holds all of them.
+	Truncated bool   `json:"truncated,omitempty"`
+	Output    string `json:"output,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
 }
 
 type bucketAction struct {
 	Action     string
 	BucketName string
+	Query      schema.ObjectQuery
+	Output     string
 }
 
+// bucketDisplayLimit caps the objects a list result keeps for the table and
+// JSON output. Every match still streams to the NDJSON output.
+const bucketDisplayLimit = 1000
+
 func (p BucketCheck) Run(ctx context.Context, config map[string]string) {
 	resultAny, err := p.Result(ctx, config)
 	if err != nil && resultAny == nil {
@@ -75,6 +94,12 @@ func (p BucketCheck) Run(ctx context.Context, config map[string]string) {
 				}
 				table.Output(rows)
 			}
+			if result.Truncated {
+				logger.Warning(fmt.Sprintf("Showing the first %d objects of %s.", len(result.Objects), result.BucketName))
+			}
+			if result.Output != "" {
+				logger.Info(fmt.Sprintf("Output written to [%s]", result.Output))
+			}
 		case "total":
 			if result.BucketName != "" {
 				logger.Warning(fmt.Sprintf("%s has %d objects.", result.BucketName, result.ObjectCount))
@@ -90,7 +115,7 @@ func (p BucketCheck) Run(ctx context.Context, config map[string]string) {
 }
 
 func (p BucketCheck) Result(ctx context.Context, config map[string]string) (any, error) {
-	parsed, err := parseBucketAction(config["metadata"])
+	parsed, err := parseBucketAction(config["metadata"], time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -103,10 +128,26 @@ func (p BucketCheck) Result(ctx context.Context, config map[string]string) (any,
 		return nil, fmt.Errorf("%s does not support bucket-check", i.Providers.Name())
 	}
 
-	bucketResults, opErr := mgr.BucketDump(ctx, parsed.Action, parsed.BucketName)
+	var (
+		bucketResults []schema.BucketResult
+		listing       *objectListing
+		opErr         error
+	)
+	if parsed.Action == "list" {
+		listing, err = newObjectListing(ctx, i.Providers.Name(), parsed.Output)
+		if err != nil {
+			return nil, err
+		}
+		bucketResults, opErr = mgr.WalkObjects(ctx, parsed.BucketName, parsed.Query, listing.visit)
+		if err := listing.Close(); err != nil && opErr == nil {
+			opErr = err
+		}
+	} else {
+		bucketResults, opErr = mgr.BucketDump(ctx, parsed.Action, parsed.BucketName)
+	}
 	results := make([]BucketCheckResult, 0, len(bucketResults))
 	for _, bucketResult := range bucketResults {
-		results = append(results, BucketCheckResult{
+		result := BucketCheckResult{
 			Provider:    i.Providers.Name(),
 			Action:      bucketResult.Action,
 			BucketName:  bucketResult.BucketName,
@@ -114,26 +155,24 @@ func (p BucketCheck) Result(ctx context.Context, config map[string]string) (any,
 			Objects:     bucketResult.Objects,
 			Message:     bucketResult.Message,
 			Status:      "success",
-		})
+		}
+		if listing != nil {
+			result.Objects = listing.objects[bucketResult.BucketName]
+			result.Truncated = int64(len(result.Objects)) < bucketResult.ObjectCount
+			result.Output = listing.path
+		}
+		results = append(results, result)
 	}
 	if opErr != nil {
-		if len(results) == 0 {
-			results = append(results, BucketCheckResult{
-				Provider:   i.Providers.Name(),
-				Action:     parsed.Action,
-				BucketName: parsed.BucketName,
-				Status:     "error",
-				Error:      opErr.Error(),
-			})
-		} else {
-			results = append(results, BucketCheckResult{
-				Provider:   i.Providers.Name(),
-				Action:     parsed.Action,
-				BucketName: parsed.BucketName,
-				Status:     "error",
-				Error:      opErr.Error(),
-			})
-		}
+		// The buckets read before the failure stay in the result ahead of
+		// the error entry.
+		results = append(results, BucketCheckResult{
+			Provider:   i.Providers.Name(),
+			Action:     parsed.Action,
+			BucketName: parsed.BucketName,
+			Status:     "error",
+			Error:      opErr.Error(),
+		})
 		return results, NewResultError(results, 4, opErr)
 	}
 	return results, nil
@@ -150,15 +189,23 @@ func (p BucketCheck) Capability() string {
 func (p BucketCheck) Help() HelpDoc {
 	return HelpDoc{
 		MetadataSyntax: []string{
-			"set metadata <action> <bucket-name>",
-			"`action` is typically `list` or `total`.",
+			"set metadata <action> <bucket-name> [<filter>=<value>...]",
+			"`action` is typically `list` or `total`; `all` selects every bucket.",
+			"Azure takes a container name, `account/container` when the name is ambiguous, or `all`.",
+			"`list` pages through the whole bucket. Filters: prefix=<key-prefix>, match=<glob>, since=<24h|7d|YYYY-MM-DD|RFC3339>, min-size=<bytes, or 10KB/5MB/1GB>, max=<N>.",
+			"Matches stream to out=<file.ndjson>, or to the log directory when logging is enabled; the first 1000 per bucket are shown. Server jobs only accept a relative out= path, written under the log directory.",
 		},
 		MetadataExamples: []string{
 			"set metadata list ctk-validation-bucket",
+			"set metadata list ctk-validation-bucket prefix=logs/ since=7d",
+			"set metadata list all match=*.sql min-size=1MB out=/tmp/objects.ndjson",
 			"set metadata total ctk-validation-bucket",
 		},
 		MetadataSuggestions: []Suggestion{
 			{Text: "list <bucket-name>", Description: "review object listings inside one authorized bucket"},
+			{Text: "list <bucket-name> prefix=", Description: "list the objects under one key prefix"},
+			{Text: "list <bucket-name> match=", Description: "list objects whose name matches a glob such as *.bak"},
+			{Text: "list <bucket-name> since=", Description: "list objects modified within a lookback like 7d"},
 			{Text: "total <bucket-name>", Description: "count objects in one bucket"},
 		},
 		SafetyNotes: []string{
@@ -168,15 +215,177 @@ func (p BucketCheck) Help() HelpDoc {
 	}
 }
 
-func parseBucketAction(metadata string) (bucketAction, error) {
+func parseBucketAction(metadata string, now time.Time) (bucketAction, error) {
 	data := argparse.Split(metadata)
 	if len(data) < 2 {
 		return bucketAction{}, errors.New("invalid metadata format: expected 'list <bucket>' or 'total <bucket>'")
 	}
-	return bucketAction{
+	parsed := bucketAction{
 		Action:     data[0],
 		BucketName: data[1],
-	}, nil
+	}
+	if len(data) > 2 && parsed.Action != "list" {
+		return bucketAction{}, fmt.Errorf("filters only apply to list, not %s", parsed.Action)
+	}
+	for _, token := range data[2:] {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			return bucketAction{}, fmt.Errorf("invalid filter %q: expected <filter>=<value>", token)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "prefix":
+			parsed.Query.Prefix = value
+		case "match", "glob":
+			parsed.Query.Pattern = value
+		case "since", "modified-since":
+			t, err := parseQueryTime(value, now)
+			if err != nil {
+				return bucketAction{}, err
+			}
+			parsed.Query.ModifiedSince = t
+		case "min-size", "minsize":
+			size, err := parseByteSize(value)
+			if err != nil {
+				return bucketAction{}, err
+			}
+			parsed.Query.MinSize = size
+		case "max", "limit":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n <= 0 {
+				return bucketAction{}, fmt.Errorf("invalid max %q: expected a positive number", value)
+			}
+			parsed.Query.Max = n
+		case "out", "output":
+			if value == "" {
+				return bucketAction{}, errors.New("out needs a file path")
+			}
+			parsed.Output = value
+		default:
+			return bucketAction{}, fmt.Errorf("unknown filter %q: expected prefix, match, since, min-size, max or out", key)
+		}
+	}
+	if parsed.Query.Pattern != "" {
+		if _, err := path.Match(parsed.Query.Pattern, ""); err != nil {
+			return bucketAction{}, fmt.Errorf("invalid match %q: %w", parsed.Query.Pattern, err)
+		}
+	}
+	return parsed, nil
+}
+
+// byteUnits are binary multiples, in line with utils.ParseBytes.
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"tb", 1 << 40}, {"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10},
+	{"t", 1 << 40}, {"g", 1 << 30}, {"m", 1 << 20}, {"k", 1 << 10}, {"b", 1},
+}
+
+func parseByteSize(value string) (int64, error) {
+	number := strings.ToLower(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if trimmed, ok := strings.CutSuffix(number, unit.suffix); ok {
+			number, multiplier = strings.TrimSpace(trimmed), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid min-size %q: expected bytes or a size like 10KB, 5MB, 1GB", value)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// objectListing collects a list action's matches: the first
+// bucketDisplayLimit per bucket for display, and every one as an NDJSON
+// record when an output file is open.
+type objectListing struct {
+	provider string
+	path     string
+	file     *os.File
+	w        *bufio.Writer
+	enc      *json.Encoder
+	objects  map[string][]schema.BucketObject
+}
+
+// objectRecord mirrors the export package's record so the stream reads like
+// a `-format ndjson` export of the objects.
+type objectRecord struct {
+	Provider string              `json:"provider"`
+	Type     string              `json:"type"`
+	Asset    schema.BucketObject `json:"asset"`
+}
+
+func newObjectListing(ctx context.Context, provider, path string) (*objectListing, error) {
+	l := &objectListing{provider: provider, objects: map[string][]schema.BucketObject{}}
+	e := env.From(ctx)
+	if path != "" && e.ConfineOutput {
+		confined, err := confineOutput(e, path)
+		if err != nil {
+			return nil, err
+		}
+		path = confined
+	}
+	if path == "" {
+		if e.LogEnable {
+			filename := time.Now().Format("20060102150405.ndjson")
+			path = fmt.Sprintf("%s/%s_objects_%s", e.LogDir, provider, filename)
+		}
+	}
+	if path == "" {
+		return l, nil
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("create output directory: %w", err)
+		}
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("create object output: %w", err)
+	}
+	l.path, l.file = path, file
+	l.w = bufio.NewWriter(file)
+	l.enc = json.NewEncoder(l.w)
+	return l, nil
+}
+
+// confineOutput resolves out= for a job whose metadata comes from a remote
+// caller: only a relative path inside the log directory is accepted.
+func confineOutput(e *env.Env, path string) (string, error) {
+	if !e.LogEnable || e.LogDir == "" {
+		return "", errors.New("out= requires logging to be enabled for server jobs")
+	}
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("out=%s must be a relative path inside the log directory for server jobs", path)
+	}
+	return filepath.Join(e.LogDir, path), nil
+}
+
+func (l *objectListing) visit(obj schema.BucketObject) error {
+	if kept := l.objects[obj.BucketName]; len(kept) < bucketDisplayLimit {
+		l.objects[obj.BucketName] = append(kept, obj)
+	}
+	if l.enc == nil {
+		return nil
+	}
+	if err := l.enc.Encode(objectRecord{Provider: l.provider, Type: "objects", Asset: obj}); err != nil {
+		return fmt.Errorf("write object output: %w", err)
+	}
+	return nil
+}
+
+func (l *objectListing) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	err := l.w.Flush()
+	if closeErr := l.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func init() {
@@ -0,0 +1,29 @@
+package payloads
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+)
+
+func TestConfineOutputKeepsServerJobsInLogDir(t *testing.T) {
+	logDir := t.TempDir()
+	e := &env.Env{LogEnable: true, LogDir: logDir, ConfineOutput: true}
+
+	got, err := confineOutput(e, "objects/run.ndjson")
+	if err != nil {
+		t.Fatalf("confineOutput: %v", err)
+	}
+	if want := filepath.Join(logDir, "objects/run.ndjson"); got != want {
+		t.Errorf("path = %q, want %q", got, want)
+	}
+	for _, path := range []string{"/etc/cron.d/x", "../outside.ndjson", "a/../../b"} {
+		if _, err := confineOutput(e, path); err == nil {
+			t.Errorf("confineOutput(%q) accepted a path outside the log directory", path)
+		}
+	}
+	if _, err := confineOutput(&env.Env{ConfineOutput: true}, "run.ndjson"); err == nil {
+		t.Error("confineOutput accepted out= with logging disabled")
+	}
+}
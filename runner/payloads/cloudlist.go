@@ -3,9 +3,11 @@ package payloads
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils"
 	"github.com/404tk/cloudtoolkit/utils/logger"
@@ -16,25 +18,30 @@ type CloudList struct{}
 
 type CloudListResult struct {
 	Provider    string                 `json:"provider"`
+	Balances    []schema.Balance       `json:"balances,omitempty"`
 	Hosts       []schema.Host          `json:"hosts,omitempty"`
 	Storages    []schema.Storage       `json:"storages,omitempty"`
 	Users       []schema.User          `json:"users,omitempty"`
 	Databases   []schema.Database      `json:"databases,omitempty"`
 	Domains     []schema.Domain        `json:"domains,omitempty"`
 	Logs        []schema.Log           `json:"logs,omitempty"`
+	Security    []schema.SecurityGroup `json:"security_groups,omitempty"`
 	SMS         schema.Sms             `json:"sms,omitempty"`
 	Errors      []schema.ResourceError `json:"errors,omitempty"`
 	OutputFiles []string               `json:"output_files,omitempty"`
+	// Telemetry counts the provider API calls the inventory made.
+	Telemetry *telemetry.Summary `json:"telemetry,omitempty"`
 }
 
 type cloudListExecution struct {
 	provider  string
 	path      string
 	resources schema.Resources
+	telemetry telemetry.Summary
 }
 
 func (p CloudList) Run(ctx context.Context, config map[string]string) {
-	result, exec, err := p.result(ctx, config)
+	result, _, err := p.result(ctx, config)
 	if err != nil {
 		logger.Error(err)
 		return
@@ -42,6 +49,16 @@ func (p CloudList) Run(ctx context.Context, config map[string]string) {
 	if result == nil {
 		return
 	}
+	p.Print(ctx, result)
+}
+
+// Print renders a cloudlist result as tables, mirroring them into the log
+// file recorded in OutputFiles when logging is enabled.
+func (p CloudList) Print(ctx context.Context, result *CloudListResult) {
+	path := ""
+	if len(result.OutputFiles) > 0 {
+		path = result.OutputFiles[0]
+	}
 	select {
 	case <-ctx.Done():
 		return
@@ -51,11 +68,13 @@ func (p CloudList) Run(ctx context.Context, config map[string]string) {
 			logger.Warning(tag + " results:")
 			table.Output(items)
 			if e.LogEnable {
-				utils.WriteLog(exec.path, tag+" results:")
-				table.FileOutput(exec.path, items)
+				utils.WriteLog(path, tag+" results:")
+				table.FileOutput(path, items)
 			}
 		}
-
+		if len(result.Balances) > 0 {
+			printGroup("Balance", result.Balances)
+		}
 		if len(result.Hosts) > 0 {
 			printGroup("Hosts", result.Hosts)
 		}
@@ -72,11 +91,25 @@ func (p CloudList) Run(ctx context.Context, config map[string]string) {
 			if len(domain.Records) == 0 {
 				continue
 			}
-			printGroup("Domain "+domain.DomainName, domain.Records)
+			tag := "Domain " + domain.DomainName
+			if domain.Account != "" {
+				tag += " (" + domain.Account + ")"
+			}
+			printGroup(tag, domain.Records)
 		}
 		if len(result.Logs) > 0 {
 			printGroup("Log Service", result.Logs)
 		}
+		if len(result.Security) > 0 {
+			printGroup("Security Groups", securityGroupRows(result.Security))
+			for _, group := range result.Security {
+				for _, rule := range group.Rules {
+					if rule.Exposed {
+						logger.Warning(fmt.Sprintf("Internet-exposed ingress: %s %s/%s from %s", securityGroupLabel(group), rule.Protocol, rule.PortRange, rule.CIDR))
+					}
+				}
+			}
+		}
 
 		if len(result.SMS.Signs) > 0 {
 			printGroup("SMS Signs", result.SMS.Signs)
@@ -92,8 +125,11 @@ func (p CloudList) Run(ctx context.Context, config map[string]string) {
 		for _, item := range result.Errors {
 			logger.Error(fmt.Sprintf("%s failed: %s", item.Scope, item.Message))
 		}
+		if t := result.Telemetry; t != nil {
+			logger.Info(fmt.Sprintf("%d API calls (%d retried, %d failed) in %.2fs of request time.", t.Calls, t.Retries, t.Failures, t.Seconds))
+		}
 		if e.LogEnable {
-			logger.Info(fmt.Sprintf("Output written to [%s]", exec.path))
+			logger.Info(fmt.Sprintf("Output written to [%s]", path))
 		}
 	}
 }
@@ -116,13 +152,16 @@ func (p CloudList) result(ctx context.Context, config map[string]string) (*Cloud
 		return nil, cloudListExecution{}, fmt.Errorf("%s does not support cloud asset inventory", i.Providers.Name())
 	}
 
+	ctx, collector := telemetry.Scope(ctx)
 	resources, err := enum.Resources(ctx)
 	if err != nil && len(resources.Errors) == 0 {
 		return nil, cloudListExecution{}, err
 	}
+	saveInventory(ctx, i.Providers, config, resources)
 	exec := cloudListExecution{
 		provider:  i.Providers.Name(),
 		resources: resources,
+		telemetry: collector.Summary(),
 	}
 	if e := env.From(ctx); e.LogEnable {
 		filename := time.Now().Format("20060102150405.log")
@@ -141,8 +180,14 @@ func buildCloudListResult(exec cloudListExecution) CloudListResult {
 	if exec.path != "" {
 		result.OutputFiles = []string{exec.path}
 	}
+	if exec.telemetry.Calls > 0 {
+		summary := exec.telemetry
+		result.Telemetry = &summary
+	}
 	for _, asset := range exec.resources.Assets {
 		switch v := asset.(type) {
+		case schema.Balance:
+			result.Balances = append(result.Balances, v)
 		case schema.Host:
 			result.Hosts = append(result.Hosts, v)
 		case schema.Storage:
@@ -155,11 +200,70 @@ func buildCloudListResult(exec cloudListExecution) CloudListResult {
 			result.Domains = append(result.Domains, v)
 		case schema.Log:
 			result.Logs = append(result.Logs, v)
+		case schema.SecurityGroup:
+			result.Security = append(result.Security, v)
 		}
 	}
 	return result
 }
 
+// securityGroupRow flattens one rule of a security group for table output.
+// A group without rules still gets a row so it shows up in the inventory.
+type securityGroupRow struct {
+	GroupID   string `table:"Group ID"`
+	GroupName string `table:"Name"`
+	Direction string `table:"Direction"`
+	Action    string `table:"Action"`
+	Protocol  string `table:"Protocol"`
+	PortRange string `table:"Port Range"`
+	CIDR      string `table:"CIDR"`
+	Exposed   string `table:"Exposed"`
+	Attached  string `table:"Attached"`
+	Region    string `table:"Region"`
+	Account   string `table:"Account"`
+}
+
+func securityGroupRows(groups []schema.SecurityGroup) []securityGroupRow {
+	var rows []securityGroupRow
+	for _, group := range groups {
+		base := securityGroupRow{
+			GroupID:   group.GroupID,
+			GroupName: group.GroupName,
+			Attached:  strings.Join(group.Attached, ","),
+			Region:    group.Region,
+			Account:   group.Account,
+		}
+		if len(group.Rules) == 0 {
+			rows = append(rows, base)
+			continue
+		}
+		for _, rule := range group.Rules {
+			row := base
+			row.Direction = rule.Direction
+			row.Action = rule.Action
+			row.Protocol = rule.Protocol
+			row.PortRange = rule.PortRange
+			row.CIDR = rule.CIDR
+			if rule.Exposed {
+				row.Exposed = "YES"
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func securityGroupLabel(group schema.SecurityGroup) string {
+	label := group.GroupID
+	if group.GroupName != "" && group.GroupName != group.GroupID {
+		label += " (" + group.GroupName + ")"
+	}
+	if group.Account != "" {
+		label = group.Account + "/" + label
+	}
+	return label
+}
+
 func (p CloudList) Desc() string {
 	return "Enumerate cloud assets in authorized environments to verify CSPM and CNAPP inventory coverage, telemetry quality, and investigation readiness."
 }
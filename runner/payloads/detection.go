@@ -0,0 +1,229 @@
+package payloads
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// Detection statuses.
+const (
+	DetectionObserved    = "observed"
+	DetectionNotObserved = "not_observed"
+	DetectionUnsupported = "unsupported"
+)
+
+// detectionPollInterval is how often the audit trail is re-read while
+// waiting for a mutation to show up.
+const detectionPollInterval = 30 * time.Second
+
+// detectionSkew tolerates clock drift between this host and the provider
+// when discarding events older than the mutation.
+const detectionSkew = time.Minute
+
+// DetectionResult reports whether a mutation reached the provider's audit
+// trail. Latency is measured from just before the mutating call to the poll
+// that first returned the matching event, so it includes up to one poll
+// interval of slack.
+type DetectionResult struct {
+	Status  string        `json:"status"`
+	Window  string        `json:"window"`
+	Latency string        `json:"latency,omitempty"`
+	Polls   int           `json:"polls"`
+	Event   *schema.Event `json:"event,omitempty"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// detectionProbe describes the audit event a mutation should produce. apis
+// are event/API names, matched exactly or as the trailing segment of the
+// provider's name (CloudTrail "CreateUser", ActionTrail "ram:CreateUser",
+// Cloud Logging "google.iam.admin.v1.CreateServiceAccountKey", Activity Log
+// "Microsoft.Authorization/roleAssignments/write", ...); resources are
+// identifiers of which at least one must appear in the event.
+//
+// shared lists APIs that the opposite mutation records too, such as GCP
+// SetIamPolicy for both adding and removing a binding. They only match
+// events stamped at or after the mutation, without the clock-skew allowance,
+// so the earlier opposite call is not mistaken for this one.
+type detectionProbe struct {
+	apis      []string
+	shared    []string
+	resources []string
+}
+
+// The create and delete lists are disjoint; APIs that record both
+// directions are in the shared lists. bucket-acl-check's expose and unexpose
+// go through the same calls, so bucketACLAPIs is only used as a shared list.
+var (
+	userCreateAPIs       = []string{"CreateUser", "AddUser", "CreateServiceAccount", "Add user"}
+	userDeleteAPIs       = []string{"DeleteUser", "DelUser", "DeleteServiceAccount", "Delete user"}
+	credentialCreateAPIs = []string{"CreateAccessKey", "CreateServiceAccountKey", "CreateCredential", "addPassword", "addKey"}
+	credentialDeleteAPIs = []string{"DeleteAccessKey", "DeleteServiceAccountKey", "DeleteCredential", "removePassword", "removeKey"}
+	bindingCreateAPIs    = []string{"AttachUserPolicy", "AttachPolicy", "AttachRole", "roleAssignments/write", "AssociateRole", "GrantRole"}
+	bindingDeleteAPIs    = []string{"DetachUserPolicy", "DetachPolicy", "DetachRole", "roleAssignments/delete", "DisassociateRole", "RevokeRole"}
+	bindingSharedAPIs    = []string{"SetIamPolicy"}
+	bucketACLAPIs        = []string{"PutBucketAcl", "PutBucketPolicy", "DeleteBucketPolicy", "PutPublicAccessBlock", "DeletePublicAccessBlock", "SetBucketAcl", "SetIamPolicy", "setIamPermissions", "containers/write"}
+	dbAccountCreateAPIs  = []string{"CreateAccount", "CreateDBAccount", "CreateDatabaseUser", "users.insert", "ResetAccountPassword"}
+	dbAccountDeleteAPIs  = []string{"DeleteAccount", "DeleteDBAccount", "DeleteDatabaseUser", "users.delete"}
+	dbAccountSharedAPIs  = []string{"ModifyDBInstance"}
+	objectAccessAPIs     = []string{"PutObject", "GetObject", "DeleteObject", "storage.objects.create", "storage.objects.get", "storage.objects.delete"}
+)
+
+// detectionWindow returns the `detect` option as a duration; zero disables
+// confirmation.
+func detectionWindow(config map[string]string) (time.Duration, error) {
+	value := strings.TrimSpace(config[utils.Detect])
+	if value == "" || value == "0" || value == "off" {
+		return 0, nil
+	}
+	window, err := time.ParseDuration(value)
+	if err != nil || window < 0 {
+		return 0, fmt.Errorf("invalid detect window %q: expected a duration such as 5m", value)
+	}
+	return window, nil
+}
+
+// ParseDetectionWindow validates a `detect` option value.
+func ParseDetectionWindow(value string) (time.Duration, error) {
+	return detectionWindow(map[string]string{utils.Detect: value})
+}
+
+// confirmDetection polls the provider's audit trail for the event probe
+// describes until it appears or the window closes. It returns nil when
+// confirmation is off. Failing to read the trail is reported in the result,
+// never as a payload error: the mutation itself already succeeded.
+func confirmDetection(ctx context.Context, provider schema.Provider, config map[string]string, started time.Time, probe detectionProbe) *DetectionResult {
+	window, err := detectionWindow(config)
+	if err != nil || window == 0 {
+		return nil
+	}
+	result := &DetectionResult{Status: DetectionNotObserved, Window: window.String()}
+	reader, ok := provider.(schema.EventReader)
+	if !ok {
+		result.Status = DetectionUnsupported
+		result.Error = fmt.Sprintf("%s has no audit event reader", provider.Name())
+		return result
+	}
+
+	logger.Info(fmt.Sprintf("Waiting up to %s for the audit trail to record %s ...", window, strings.Join(probe.resources, ", ")))
+	deadline := started.Add(window)
+	interval := min(detectionPollInterval, window)
+	for {
+		result.Polls++
+		events, err := reader.QueryEvents(ctx, schema.EventQuery{Since: started.Add(-detectionSkew)})
+		if err != nil {
+			result.Error = err.Error()
+		} else if event, ok := probe.match(events, started); ok {
+			result.Status = DetectionObserved
+			result.Latency = time.Since(started).Round(time.Second).String()
+			result.Event = &event
+			result.Error = ""
+			return result
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return result
+		}
+		timer := time.NewTimer(min(interval, remaining))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			result.Error = fmt.Sprintf("detection wait interrupted: %s", ctx.Err())
+			return result
+		case <-timer.C:
+		}
+	}
+}
+
+// match returns the newest event that names one of the probe's APIs and
+// mentions one of its resources. Events whose timestamp parses and predates
+// started by more than detectionSkew are ignored so a previous run of the
+// same payload does not count.
+func (p detectionProbe) match(events []schema.Event, started time.Time) (schema.Event, bool) {
+	var (
+		best     schema.Event
+		bestTime time.Time
+		found    bool
+	)
+	since := started.Add(-detectionSkew)
+	for _, event := range events {
+		at, ok := schema.ParseEventTime(event.Time)
+		switch {
+		case apiMatches(event, p.apis):
+			if ok && at.Before(since) {
+				continue
+			}
+		case apiMatches(event, p.shared):
+			if !ok || at.Before(started) {
+				continue
+			}
+		default:
+			continue
+		}
+		if len(p.resources) > 0 && !containsFold([]string{event.Affected, event.Request, event.Name}, p.resources) {
+			continue
+		}
+		if !found || (ok && at.After(bestTime)) {
+			best, bestTime, found = event, at, true
+		}
+	}
+	return best, found
+}
+
+// apiMatches reports whether the event's API or name is one of apis, either
+// whole or as its tail following a '.', '/' or ':' separator, ignoring case:
+// "CreateUser" matches "ram:CreateUser" but not "CreateUserProfile".
+func apiMatches(event schema.Event, apis []string) bool {
+	for _, name := range []string{event.API, event.Name} {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for _, api := range apis {
+			api = strings.ToLower(api)
+			if name == api {
+				return true
+			}
+			if i := len(name) - len(api) - 1; i >= 0 && strings.HasSuffix(name, api) && strings.ContainsRune("./:", rune(name[i])) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFold(haystacks, needles []string) bool {
+	for _, needle := range needles {
+		needle = strings.ToLower(strings.TrimSpace(needle))
+		if needle == "" {
+			continue
+		}
+		for _, haystack := range haystacks {
+			if strings.Contains(strings.ToLower(haystack), needle) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// logDetection prints the detection outcome for the table output path.
+func logDetection(d *DetectionResult) {
+	if d == nil {
+		return
+	}
+	switch d.Status {
+	case DetectionObserved:
+		logger.Info(fmt.Sprintf("Audit event %s observed after %s (%d polls).", d.Event.API, d.Latency, d.Polls))
+	case DetectionUnsupported:
+		logger.Warning(fmt.Sprintf("Detection confirmation skipped: %s.", d.Error))
+	default:
+		msg := fmt.Sprintf("Audit event not observed within %s (%d polls).", d.Window, d.Polls)
+		if d.Error != "" {
+			msg += " Last error: " + d.Error
+		}
+		logger.Warning(msg)
+	}
+}
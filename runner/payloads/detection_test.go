@@ -0,0 +1,293 @@
+package payloads
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+func TestDetectionProbeMatch(t *testing.T) {
+	started := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) string { return started.Add(d).Format(time.RFC3339) }
+	create := detectionProbe{apis: userCreateAPIs, resources: []string{"ctk-probe"}}
+	unbind := detectionProbe{apis: bindingDeleteAPIs, shared: bindingSharedAPIs, resources: []string{"ctk-probe"}}
+
+	tests := []struct {
+		name   string
+		probe  detectionProbe
+		events []schema.Event
+		want   string // Id of the matched event, "" for none
+	}{
+		{
+			name:   "exact api",
+			probe:  create,
+			events: []schema.Event{{Id: "1", API: "CreateUser", Request: `{"UserName":"ctk-probe"}`, Time: at(time.Second)}},
+			want:   "1",
+		},
+		{
+			name:  "trailing segment",
+			probe: create,
+			events: []schema.Event{
+				{Id: "ram", API: "ram:CreateUser", Affected: "ctk-probe", Time: at(time.Second)},
+			},
+			want: "ram",
+		},
+		{
+			name:  "substring of another api",
+			probe: create,
+			events: []schema.Event{
+				{Id: "1", API: "CreateUserProfile", Affected: "ctk-probe", Time: at(time.Second)},
+				{Id: "2", API: "BatchCreateUser", Affected: "ctk-probe", Time: at(time.Second)},
+			},
+		},
+		{
+			name:   "other resource",
+			probe:  create,
+			events: []schema.Event{{Id: "1", API: "CreateUser", Affected: "someone-else", Time: at(time.Second)}},
+		},
+		{
+			name:  "stale event",
+			probe: create,
+			events: []schema.Event{
+				{Id: "old", API: "CreateUser", Affected: "ctk-probe", Time: at(-2 * detectionSkew)},
+			},
+		},
+		{
+			name:   "within clock skew",
+			probe:  create,
+			events: []schema.Event{{Id: "skewed", API: "CreateUser", Affected: "ctk-probe", Time: at(-detectionSkew / 2)}},
+			want:   "skewed",
+		},
+		{
+			name:  "newest wins",
+			probe: create,
+			events: []schema.Event{
+				{Id: "first", API: "CreateUser", Affected: "ctk-probe", Time: at(time.Second)},
+				{Id: "newest", API: "CreateUser", Affected: "ctk-probe", Time: at(3 * time.Second)},
+				{Id: "middle", API: "CreateUser", Affected: "ctk-probe", Time: at(2 * time.Second)},
+			},
+			want: "newest",
+		},
+		{
+			name:  "zone-less time is never stale",
+			probe: create,
+			events: []schema.Event{
+				{Id: "local", API: "CreateUser", Affected: "ctk-probe", Time: "2020-01-01 08:00:00"},
+			},
+			want: "local",
+		},
+		{
+			name:  "zoned event beats zone-less one",
+			probe: create,
+			events: []schema.Event{
+				{Id: "local", API: "CreateUser", Affected: "ctk-probe", Time: "2026-05-01 20:00:05"},
+				{Id: "zoned", API: "CreateUser", Affected: "ctk-probe", Time: at(time.Second)},
+			},
+			want: "zoned",
+		},
+		{
+			name:  "create is not a delete",
+			probe: unbind,
+			events: []schema.Event{
+				{Id: "attach", API: "AttachUserPolicy", Affected: "ctk-probe", Time: at(time.Second)},
+			},
+		},
+		{
+			name:  "shared api from before the mutation",
+			probe: unbind,
+			events: []schema.Event{
+				{Id: "bind", API: "google.iam.admin.v1.SetIamPolicy", Affected: "projects/p/serviceAccounts/ctk-probe", Time: at(-10 * time.Second)},
+			},
+		},
+		{
+			name:  "shared api after the mutation",
+			probe: unbind,
+			events: []schema.Event{
+				{Id: "bind", API: "SetIamPolicy", Affected: "ctk-probe", Time: at(-10 * time.Second)},
+				{Id: "unbind", API: "SetIamPolicy", Affected: "ctk-probe", Time: at(2 * time.Second)},
+			},
+			want: "unbind",
+		},
+		{
+			name:   "shared api without a parsable time",
+			probe:  unbind,
+			events: []schema.Event{{Id: "local", API: "SetIamPolicy", Affected: "ctk-probe", Time: "2026-05-01 20:00:05"}},
+		},
+		{
+			name:  "azure operation path",
+			probe: detectionProbe{apis: bindingCreateAPIs, resources: []string{"ctk-probe"}},
+			events: []schema.Event{
+				{Id: "ra", API: "Microsoft.Authorization/roleAssignments/write", Request: "ctk-probe", Time: at(time.Second)},
+			},
+			want: "ra",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.probe.match(tt.events, started)
+			if ok != (tt.want != "") || got.Id != tt.want {
+				t.Fatalf("match() = %q, %v; want %q", got.Id, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectionAPIListsAreDisjoint(t *testing.T) {
+	pairs := []struct {
+		name           string
+		create, delete []string
+	}{
+		{name: "user", create: userCreateAPIs, delete: userDeleteAPIs},
+		{name: "credential", create: credentialCreateAPIs, delete: credentialDeleteAPIs},
+		{name: "binding", create: bindingCreateAPIs, delete: bindingDeleteAPIs},
+		{name: "db account", create: dbAccountCreateAPIs, delete: dbAccountDeleteAPIs},
+	}
+	for _, pair := range pairs {
+		for _, api := range pair.create {
+			if apiMatches(schema.Event{API: api}, pair.delete) {
+				t.Errorf("%s: create API %q also matches the delete list", pair.name, api)
+			}
+		}
+		for _, api := range pair.delete {
+			if apiMatches(schema.Event{API: api}, pair.create) {
+				t.Errorf("%s: delete API %q also matches the create list", pair.name, api)
+			}
+		}
+	}
+}
+
+func TestParseEventTime(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+		ok    bool
+	}{
+		{value: "2026-05-01T12:00:00Z", want: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ok: true},
+		{value: " 2026-05-01T12:00:00.123456789Z ", want: time.Date(2026, 5, 1, 12, 0, 0, 123456789, time.UTC), ok: true},
+		{value: "2026-05-01T20:00:00+08:00", want: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ok: true},
+		{value: "2026-05-01T20:00:00+0800", want: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ok: true},
+		{value: "2026-05-01 20:00:00"},
+		{value: "2026-05-01T20:00:00"},
+		{value: "1777636800"},
+		{value: ""},
+	}
+	for _, tt := range tests {
+		got, ok := schema.ParseEventTime(tt.value)
+		if ok != tt.ok || !got.Equal(tt.want) {
+			t.Errorf("ParseEventTime(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDetectionWindow(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "0", want: 0},
+		{value: "off", want: 0},
+		{value: " 5m ", want: 5 * time.Minute},
+		{value: "90s", want: 90 * time.Second},
+		{value: "5", wantErr: true},
+		{value: "-1m", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := detectionWindow(map[string]string{utils.Detect: tt.value})
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("detectionWindow(%q) = %v, %v; want %v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// detectionTestReader answers each QueryEvents call with the next entry of
+// polls, repeating the last one.
+type detectionTestReader struct {
+	mu      sync.Mutex
+	polls   []detectionPoll
+	queries []schema.EventQuery
+}
+
+type detectionPoll struct {
+	events []schema.Event
+	err    error
+}
+
+func (r *detectionTestReader) Name() string { return "test" }
+
+func (r *detectionTestReader) EventDump(context.Context, string, string) (schema.EventActionResult, error) {
+	return schema.EventActionResult{}, errors.New("not implemented")
+}
+
+func (r *detectionTestReader) QueryEvents(_ context.Context, q schema.EventQuery) ([]schema.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, q)
+	poll := r.polls[min(len(r.queries), len(r.polls))-1]
+	return poll.events, poll.err
+}
+
+type detectionTestProvider struct{}
+
+func (detectionTestProvider) Name() string { return "test" }
+
+func TestConfirmDetectionPolls(t *testing.T) {
+	probe := detectionProbe{apis: userCreateAPIs, resources: []string{"ctk-probe"}}
+	hit := func() schema.Event {
+		return schema.Event{Id: "hit", API: "CreateUser", Affected: "ctk-probe", Time: time.Now().UTC().Format(time.RFC3339)}
+	}
+
+	t.Run("observed on a later poll", func(t *testing.T) {
+		reader := &detectionTestReader{polls: []detectionPoll{
+			{err: errors.New("trail lagging")},
+			{events: []schema.Event{hit()}},
+		}}
+		started := time.Now()
+		got := confirmDetection(context.Background(), reader, map[string]string{utils.Detect: "200ms"}, started, probe)
+		if got == nil || got.Status != DetectionObserved || got.Polls != 2 || got.Event.Id != "hit" || got.Error != "" {
+			t.Fatalf("confirmDetection() = %+v", got)
+		}
+		if since := reader.queries[0].Since; !since.Equal(started.Add(-detectionSkew)) {
+			t.Errorf("query since = %v, want %v", since, started.Add(-detectionSkew))
+		}
+	})
+
+	t.Run("window closes", func(t *testing.T) {
+		reader := &detectionTestReader{polls: []detectionPoll{{err: errors.New("access denied")}}}
+		got := confirmDetection(context.Background(), reader, map[string]string{utils.Detect: "50ms"}, time.Now(), probe)
+		if got == nil || got.Status != DetectionNotObserved || got.Polls < 1 || got.Error != "access denied" {
+			t.Fatalf("confirmDetection() = %+v", got)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		reader := &detectionTestReader{polls: []detectionPoll{{}}}
+		got := confirmDetection(ctx, reader, map[string]string{utils.Detect: "1h"}, time.Now(), probe)
+		if got == nil || got.Status != DetectionNotObserved || got.Polls != 1 || got.Error == "" {
+			t.Fatalf("confirmDetection() = %+v", got)
+		}
+	})
+
+	t.Run("no reader", func(t *testing.T) {
+		got := confirmDetection(context.Background(), detectionTestProvider{}, map[string]string{utils.Detect: "1m"}, time.Now(), probe)
+		if got == nil || got.Status != DetectionUnsupported {
+			t.Fatalf("confirmDetection() = %+v", got)
+		}
+	})
+
+	t.Run("off", func(t *testing.T) {
+		reader := &detectionTestReader{polls: []detectionPoll{{events: []schema.Event{hit()}}}}
+		if got := confirmDetection(context.Background(), reader, map[string]string{}, time.Now(), probe); got != nil || len(reader.queries) != 0 {
+			t.Fatalf("confirmDetection() = %+v after %d queries, want nil", got, len(reader.queries))
+		}
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
@@ -29,6 +32,7 @@ type EventCheckResult struct {
 type eventAction struct {
 	Action string
 	Scope  string
+	Query  schema.EventQuery
 }
 
 func (p EventCheck) Run(ctx context.Context, config map[string]string) {
@@ -63,7 +67,7 @@ func (p EventCheck) Run(ctx context.Context, config map[string]string) {
 }
 
 func (p EventCheck) Result(ctx context.Context, config map[string]string) (any, error) {
-	parsed, err := parseEventAction(config["metadata"])
+	parsed, err := parseEventAction(config["metadata"], time.Now().UTC())
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +81,12 @@ func (p EventCheck) Result(ctx context.Context, config map[string]string) (any,
 		return nil, fmt.Errorf("%s does not support event-check", i.Providers.Name())
 	}
 
-	eventResult, err := reader.EventDump(ctx, parsed.Action, parsed.Scope)
+	var eventResult schema.EventActionResult
+	if parsed.Action == "dump" {
+		eventResult.Events, err = reader.QueryEvents(ctx, parsed.Query)
+	} else {
+		eventResult, err = reader.EventDump(ctx, parsed.Action, parsed.Scope)
+	}
 	result := EventCheckResult{
 		Provider: i.Providers.Name(),
 		Action:   parsed.Action,
@@ -103,15 +112,148 @@ func (p EventCheck) Result(ctx context.Context, config map[string]string) (any,
 	return result, nil
 }
 
-func parseEventAction(metadata string) (eventAction, error) {
+func parseEventAction(metadata string, now time.Time) (eventAction, error) {
 	data := argparse.Split(metadata)
-	if len(data) < 2 {
-		return eventAction{}, errors.New("invalid metadata format: expected 'dump <source-ip|all>' or 'whitelist <security-event-id>'")
+	if len(data) == 0 {
+		return eventAction{}, errors.New("invalid metadata format: expected 'dump [all|<filter>=<value>...]' or 'whitelist <security-event-id>'")
+	}
+	switch data[0] {
+	case "dump":
+		q, err := parseEventQuery(data[1:], now)
+		if err != nil {
+			return eventAction{}, err
+		}
+		return eventAction{Action: "dump", Scope: q.String(), Query: q}, nil
+	case "whitelist":
+		if len(data) < 2 {
+			return eventAction{}, errors.New("invalid metadata format: expected 'whitelist <security-event-id>'")
+		}
+		return eventAction{Action: "whitelist", Scope: data[1]}, nil
+	}
+	return eventAction{}, fmt.Errorf("invalid action: %s (expected: dump, whitelist)", data[0])
+}
+
+// parseEventQuery reads the dump filters into the provider-neutral query each
+// driver translates. Besides key=value filters it keeps the older scopes:
+// "all", a bare source IP and a <startUnix>:<endUnix> window.
+func parseEventQuery(tokens []string, now time.Time) (schema.EventQuery, error) {
+	var q schema.EventQuery
+	for _, token := range tokens {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			if err := addLegacyEventScope(&q, token); err != nil {
+				return schema.EventQuery{}, err
+			}
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			return schema.EventQuery{}, fmt.Errorf("empty value for %s filter", key)
+		}
+		var err error
+		switch strings.ToLower(key) {
+		case "since", "from", "start":
+			q.Since, err = parseQueryTime(value, now)
+		case "until", "to", "end":
+			q.Until, err = parseQueryTime(value, now)
+		case "name", "event", "api":
+			q.Name = value
+		case "principal", "user", "caller":
+			q.Principal = value
+		case "ip", "source-ip", "sourceip":
+			if net.ParseIP(value) == nil {
+				err = fmt.Errorf("invalid ip filter %q", value)
+			}
+			q.SourceIP = value
+		case "resource":
+			q.Resource = value
+		case "result", "status":
+			q.Result, err = parseEventResult(value)
+		default:
+			err = fmt.Errorf("unknown event filter %q: expected since, until, name, principal, ip, resource or result", key)
+		}
+		if err != nil {
+			return schema.EventQuery{}, err
+		}
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && q.Until.Before(q.Since) {
+		return schema.EventQuery{}, fmt.Errorf("until %s is before since %s", q.Until.Format(time.RFC3339), q.Since.Format(time.RFC3339))
+	}
+	return q, nil
+}
+
+// Bare numbers in this range are Unix seconds (2001-09-09 to 2286-11-20).
+// Anything shorter is more likely a year or a lookback missing its unit.
+const (
+	minQueryUnix = 1_000_000_000
+	maxQueryUnix = 10_000_000_000
+)
+
+// parseQueryTime accepts a lookback such as 30m, 24h or 7d, "now", a zoned
+// timestamp as read from events (RFC3339), a UTC date or date and time, a
+// four-digit year or Unix seconds. Other bare numbers are rejected instead
+// of being read as seconds into 1970.
+func parseQueryTime(value string, now time.Time) (time.Time, error) {
+	if strings.EqualFold(value, "now") {
+		return now, nil
+	}
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch {
+		case len(value) == 4 && n > 0:
+			return time.Date(int(n), time.January, 1, 0, 0, 0, 0, time.UTC), nil
+		case n >= minQueryUnix && n < maxQueryUnix:
+			return time.Unix(n, 0).UTC(), nil
+		}
+		return time.Time{}, fmt.Errorf("invalid time %q: a bare number must be a year (YYYY) or Unix seconds; give lookbacks a unit, e.g. %sh or %sd", value, value, value)
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, ok := schema.ParseEventTime(value); ok {
+		return t.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: expected a lookback like 24h or 7d, RFC3339, YYYY-MM-DD, YYYY or unix seconds", value)
+}
+
+// addLegacyEventScope applies a scope from before the key=value filters.
+func addLegacyEventScope(q *schema.EventQuery, token string) error {
+	if token == "all" {
+		return nil
+	}
+	if net.ParseIP(token) != nil {
+		q.SourceIP = token
+		return nil
+	}
+	if start, end, ok := strings.Cut(token, ":"); ok {
+		startUnix, startErr := strconv.ParseInt(start, 10, 64)
+		endUnix, endErr := strconv.ParseInt(end, 10, 64)
+		if startErr == nil && endErr == nil {
+			window := schema.EventWindow(startUnix, endUnix)
+			q.Since, q.Until = window.Since, window.Until
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid dump scope %q: expected all, a source IP, <startUnix>:<endUnix> or <filter>=<value>", token)
+}
+
+func parseEventResult(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "success", "succeeded", "ok":
+		return schema.EventResultSuccess, nil
+	case "failure", "failed", "error":
+		return schema.EventResultFailure, nil
 	}
-	return eventAction{
-		Action: data[0],
-		Scope:  data[1],
-	}, nil
+	return "", fmt.Errorf("invalid result filter %q: expected success or failure", value)
 }
 
 func (p EventCheck) Desc() string {
@@ -125,17 +267,23 @@ func (p EventCheck) Capability() string {
 func (p EventCheck) Help() HelpDoc {
 	return HelpDoc{
 		MetadataSyntax: []string{
-			"set metadata dump <source-ip|all>",
+			"set metadata dump [all|<filter>=<value>...]",
+			"  filters: since= until= (24h, 7d, RFC3339, YYYY-MM-DD, YYYY, unix seconds) name= principal= ip= resource= result=<success|failure>",
+			"  Alibaba Cloud filters Security Center alerts, not ActionTrail records: name= matches the alert or the API in its details.",
 			"set metadata whitelist <security-event-id>",
 		},
 		MetadataExamples: []string{
 			"set metadata dump all",
-			"set metadata dump 198.51.100.24",
+			"set metadata dump since=24h name=RunInstances result=failure",
+			"set metadata dump since=2026-01-01 until=2026-01-02 principal=alice ip=198.51.100.24",
 			"set metadata whitelist 1234567890",
 		},
 		MetadataSuggestions: []Suggestion{
 			{Text: "dump all", Description: "review all relevant events"},
-			{Text: "dump <source-ip>", Description: "review events for one source IP"},
+			{Text: "dump since=24h", Description: "review events from the last day"},
+			{Text: "dump principal=<user>", Description: "review events by one user or access key"},
+			{Text: "dump ip=<source-ip>", Description: "review events for one source IP"},
+			{Text: "dump result=failure", Description: "review failed calls"},
 			{Text: "whitelist <security-event-id>", Description: "adjust one provider event handling rule where explicitly approved"},
 		},
 		SafetyNotes: []string{
@@ -0,0 +1,100 @@
+package payloads
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+func TestParseEventQuery(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	utc := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name    string
+		tokens  string
+		want    schema.EventQuery
+		wantErr string
+	}{
+		{name: "all", tokens: "all"},
+		{name: "minutes lookback", tokens: "since=30m", want: schema.EventQuery{Since: now.Add(-30 * time.Minute)}},
+		{name: "hours lookback to now", tokens: "since=24h until=now", want: schema.EventQuery{Since: now.Add(-24 * time.Hour), Until: now}},
+		{name: "days lookback", tokens: "from=7d to=1d", want: schema.EventQuery{Since: utc(2026, 5, 3, 12, 0), Until: utc(2026, 5, 9, 12, 0)}},
+		{name: "dates", tokens: "since=2026-01-01 until=2026-01-02", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 0), Until: utc(2026, 1, 2, 0, 0)}},
+		{name: "zone-less date and time is UTC", tokens: "start=2026-01-01T08:30:00", want: schema.EventQuery{Since: utc(2026, 1, 1, 8, 30)}},
+		{name: "RFC3339 offset", tokens: "since=2026-01-01T08:30:00+08:00", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 30)}},
+		{name: "offset without colon", tokens: "since=2026-01-01T08:30:00+0800", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 30)}},
+		{name: "year", tokens: "since=2026", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 0)}},
+		{name: "unix seconds", tokens: "since=1767225600 until=1767312000", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 0), Until: utc(2026, 1, 2, 0, 0)}},
+		{name: "number without unit", tokens: "since=7", wantErr: "bare number"},
+		{name: "short epoch", tokens: "since=123456", wantErr: "bare number"},
+		{name: "negative number", tokens: "since=-5", wantErr: "bare number"},
+		{name: "unparsable time", tokens: "since=yesterday", wantErr: "invalid time"},
+		{name: "legacy window", tokens: "1767225600:1767312000", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 0), Until: utc(2026, 1, 2, 0, 0)}},
+		{name: "legacy open end", tokens: "1767225600:0", want: schema.EventQuery{Since: utc(2026, 1, 1, 0, 0)}},
+		{name: "legacy source ip", tokens: "198.51.100.24", want: schema.EventQuery{SourceIP: "198.51.100.24"}},
+		{name: "legacy ipv6", tokens: "2001:db8::1", want: schema.EventQuery{SourceIP: "2001:db8::1"}},
+		{name: "legacy garbage", tokens: "yesterday", wantErr: "invalid dump scope"},
+		{name: "legacy half window", tokens: "1767225600:later", wantErr: "invalid dump scope"},
+		{
+			name:   "filters",
+			tokens: "name=RunInstances principal=alice ip=198.51.100.24 resource=i-123 result=failed",
+			want:   schema.EventQuery{Name: "RunInstances", Principal: "alice", SourceIP: "198.51.100.24", Resource: "i-123", Result: schema.EventResultFailure},
+		},
+		{name: "filter aliases", tokens: "API=CreateUser USER=bob status=ok", want: schema.EventQuery{Name: "CreateUser", Principal: "bob", Result: schema.EventResultSuccess}},
+		{name: "bad ip", tokens: "ip=not-an-ip", wantErr: "invalid ip filter"},
+		{name: "bad result", tokens: "result=maybe", wantErr: "invalid result filter"},
+		{name: "empty value", tokens: "name=", wantErr: "empty value"},
+		{name: "unknown filter", tokens: "region=us-east-1", wantErr: "unknown event filter"},
+		{name: "until before since", tokens: "since=2026-01-02 until=2026-01-01", wantErr: "is before since"},
+		{name: "until before since lookback", tokens: "since=1h until=2h", wantErr: "is before since"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseEventQuery(strings.Fields(tt.tokens), now)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseEventQuery(%q) error = %v, want %q", tt.tokens, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseEventQuery(%q) error = %v", tt.tokens, err)
+			}
+			if !got.Since.Equal(tt.want.Since) || !got.Until.Equal(tt.want.Until) {
+				t.Errorf("window = %v .. %v, want %v .. %v", got.Since, got.Until, tt.want.Since, tt.want.Until)
+			}
+			got.Since, got.Until = tt.want.Since, tt.want.Until
+			if got != tt.want {
+				t.Errorf("query = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventQueryWindowMatchesParsedTimes(t *testing.T) {
+	q, err := parseEventQuery([]string{"since=2026-05-01T12:00:00Z", "until=2026-05-01T13:00:00Z"}, time.Now())
+	if err != nil {
+		t.Fatalf("parseEventQuery: %v", err)
+	}
+	tests := []struct {
+		time string
+		want bool
+	}{
+		{time: "2026-05-01T12:30:00Z", want: true},
+		{time: "2026-05-01T20:30:00+08:00", want: true},
+		{time: "2026-05-01T19:30:00+0800", want: false},
+		{time: "2026-05-01T13:00:00.500Z", want: false},
+		{time: "2026-05-01 08:00:00", want: true}, // zone-less: kept, not judged
+		{time: "", want: true},
+	}
+	for _, tt := range tests {
+		if got := q.Match(schema.Event{Time: tt.time}); got != tt.want {
+			t.Errorf("Match(time %q) = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+}
@@ -1,7 +1,16 @@
 package payloads
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/404tk/cloudtoolkit/pkg/inventory"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay"
+	"github.com/404tk/cloudtoolkit/pkg/providers/replay/cassette"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/cache"
 	"github.com/404tk/cloudtoolkit/utils/logger"
 )
 
@@ -17,3 +26,35 @@ func loadInventory(config map[string]string) (*inventory.Inventory, bool) {
 	}
 	return i, true
 }
+
+// replaySession reports whether providers answer from demo fixtures or a
+// recorded cassette rather than a real account.
+func replaySession() bool {
+	return replay.IsActive() || cassette.Playing()
+}
+
+// withCachedInventory lets the provider resolve instance and database
+// metadata from the persisted inventory of the credential in config when its
+// in-process cache is empty. Replay sessions use only their fixtures.
+func withCachedInventory(ctx context.Context, provider schema.Provider, config map[string]string) context.Context {
+	if replaySession() {
+		return ctx
+	}
+	return invcache.With(ctx, invcache.Default(), cache.CredentialUUID(provider, config))
+}
+
+// saveInventory persists the hosts and databases of an inventory run for the
+// credential in config, into the inventory attached to ctx when there is
+// one. Failing to write the cache is only logged.
+func saveInventory(ctx context.Context, provider schema.Provider, config map[string]string, resources schema.Resources) {
+	if replaySession() {
+		return
+	}
+	store, credential := invcache.Default(), cache.CredentialUUID(provider, config)
+	if scope, ok := invcache.From(ctx); ok {
+		store, credential = scope.Store, scope.Credential
+	}
+	if err := store.Save(credential, env.From(ctx).Cloudlist, resources); err != nil {
+		logger.Warning(fmt.Sprintf("Inventory cache not updated: %v", err))
+	}
+}
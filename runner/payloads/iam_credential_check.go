@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -23,6 +24,8 @@ type IAMCredentialCheckResult struct {
 	Message        string                 `json:"message,omitempty"`
 	Status         string                 `json:"status"`
 	Error          string                 `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
 }
 
 type iamCredentialRowJSON struct {
@@ -86,6 +89,7 @@ func (p IAMCredentialCheck) Run(ctx context.Context, config map[string]string) {
 	if result.Message != "" {
 		logger.Warning(result.Message)
 	}
+	logDetection(result.Detection)
 }
 
 func (p IAMCredentialCheck) Result(ctx context.Context, config map[string]string) (any, error) {
@@ -93,6 +97,9 @@ func (p IAMCredentialCheck) Result(ctx context.Context, config map[string]string
 	if err != nil {
 		return nil, err
 	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
 
 	i, err := inventoryFromConfig(config)
 	if err != nil {
@@ -104,6 +111,7 @@ func (p IAMCredentialCheck) Result(ctx context.Context, config map[string]string
 		return nil, fmt.Errorf("%s does not support iam-credential-check", i.Providers.Name())
 	}
 
+	started := time.Now()
 	credResult, err := mgr.IAMCredential(ctx, parsed.Action, parsed.Principal, parsed.CredentialID)
 
 	result := IAMCredentialCheckResult{
@@ -135,6 +143,20 @@ func (p IAMCredentialCheck) Result(ctx context.Context, config map[string]string
 		})
 	}
 	result.Status = "success"
+	credArtifact := artifact{
+		payload:  "iam-credential-check",
+		action:   parsed.Action,
+		resource: "credential:" + result.Principal + "/" + result.CredentialID,
+	}
+	switch parsed.Action {
+	case "create":
+		credArtifact.undo = []string{"delete", result.Principal, result.CredentialID}
+		recordArtifact(i.Providers, config, credArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: credentialCreateAPIs, resources: []string{result.Principal, result.CredentialID}})
+	case "delete":
+		resolveArtifact(ctx, i.Providers, config, credArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: credentialDeleteAPIs, resources: []string{result.Principal, result.CredentialID}})
+	}
 	return result, nil
 }
 
@@ -0,0 +1,434 @@
+package payloads
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/argparse"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/table"
+)
+
+type IAMPosture struct{}
+
+type IAMPostureResult struct {
+	Provider   string               `json:"provider"`
+	Thresholds iamPostureThresholds `json:"thresholds"`
+	Users      []iamPostureUserJSON `json:"users,omitempty"`
+	Findings   []iamPostureFinding  `json:"findings,omitempty"`
+	Message    string               `json:"message,omitempty"`
+	Status     string               `json:"status"`
+	Error      string               `json:"error,omitempty"`
+}
+
+type iamPostureThresholds struct {
+	KeyAgeDays      int    `json:"key_age_days"`
+	PasswordAgeDays int    `json:"password_age_days"`
+	InactiveDays    int    `json:"inactive_days"`
+	MinSeverity     string `json:"min_severity"`
+}
+
+type iamPostureUserJSON struct {
+	Account         string              `json:"account,omitempty"`
+	UserName        string              `json:"user_name"`
+	UserID          string              `json:"user_id,omitempty"`
+	CreateTime      string              `json:"create_time,omitempty"`
+	LastLogin       string              `json:"last_login,omitempty"`
+	Password        bool                `json:"password"`
+	PasswordChanged string              `json:"password_changed,omitempty"`
+	MFA             bool                `json:"mfa"`
+	AccessKeys      []iamPostureKeyJSON `json:"access_keys,omitempty"`
+	Gaps            []string            `json:"gaps,omitempty"`
+}
+
+type iamPostureKeyJSON struct {
+	ID              string `json:"id"`
+	Active          bool   `json:"active"`
+	CreateTime      string `json:"create_time,omitempty"`
+	AgeDays         int    `json:"age_days,omitempty"`
+	LastUsed        string `json:"last_used,omitempty"`
+	LastUsedService string `json:"last_used_service,omitempty"`
+}
+
+type iamPostureFinding struct {
+	Severity string `json:"severity"`
+	Account  string `json:"account,omitempty"`
+	UserName string `json:"user_name"`
+	Check    string `json:"check"`
+	Detail   string `json:"detail"`
+}
+
+// Finding severities, most severe first.
+const (
+	severityHigh   = "high"
+	severityMedium = "medium"
+	severityLow    = "low"
+)
+
+var severityRank = map[string]int{severityHigh: 0, severityMedium: 1, severityLow: 2}
+
+func (p IAMPosture) Run(ctx context.Context, config map[string]string) {
+	resultAny, err := p.Result(ctx, config)
+	if err != nil && resultAny == nil {
+		logger.Error(err.Error())
+		return
+	}
+	result, ok := resultAny.(IAMPostureResult)
+	if !ok {
+		logger.Error("Invalid result type")
+		return
+	}
+	if result.Status == "error" {
+		logger.Error(result.Error)
+		return
+	}
+
+	if len(result.Users) > 0 {
+		type userRow struct {
+			Account   string `table:"Account"`
+			User      string `table:"User"`
+			Password  string `table:"Password"`
+			MFA       string `table:"MFA"`
+			Keys      string `table:"Active Keys"`
+			OldestKey string `table:"Oldest Key"`
+			LastLogin string `table:"Last Login"`
+		}
+		rows := make([]userRow, 0, len(result.Users))
+		for _, user := range result.Users {
+			row := userRow{
+				Account:   user.Account,
+				User:      user.UserName,
+				Password:  postureFlag(user.Password, false),
+				MFA:       postureFlag(user.MFA, slices.Contains(user.Gaps, schema.PostureGapMFA)),
+				LastLogin: user.LastLogin,
+			}
+			active, oldest := 0, -1
+			for _, key := range user.AccessKeys {
+				if !key.Active {
+					continue
+				}
+				active++
+				if key.CreateTime != "" && key.AgeDays > oldest {
+					oldest = key.AgeDays
+				}
+			}
+			row.Keys = strconv.Itoa(active)
+			if slices.Contains(user.Gaps, schema.PostureGapAccessKeys) {
+				row.Keys = "?"
+			}
+			if oldest >= 0 {
+				row.OldestKey = fmt.Sprintf("%dd", oldest)
+			}
+			rows = append(rows, row)
+		}
+		table.Output(rows)
+	}
+	if len(result.Findings) > 0 {
+		type findingRow struct {
+			Severity string `table:"Severity"`
+			Account  string `table:"Account"`
+			User     string `table:"User"`
+			Check    string `table:"Check"`
+			Detail   string `table:"Detail"`
+		}
+		rows := make([]findingRow, 0, len(result.Findings))
+		for _, finding := range result.Findings {
+			rows = append(rows, findingRow{
+				Severity: finding.Severity,
+				Account:  finding.Account,
+				User:     finding.UserName,
+				Check:    finding.Check,
+				Detail:   finding.Detail,
+			})
+		}
+		table.Output(rows)
+	}
+	if result.Message != "" {
+		logger.Warning(result.Message)
+	}
+}
+
+func (p IAMPosture) Result(ctx context.Context, config map[string]string) (any, error) {
+	thresholds, err := parseIAMPostureThresholds(config["metadata"])
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := inventoryFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, ok := i.Providers.(schema.IAMPostureReader)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support iam-posture", i.Providers.Name())
+	}
+
+	users, err := reader.IAMPosture(ctx)
+	result := IAMPostureResult{
+		Provider:   i.Providers.Name(),
+		Thresholds: thresholds,
+	}
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return result, NewResultError(result, 4, err)
+	}
+
+	now := time.Now().UTC()
+	for _, user := range users {
+		result.Users = append(result.Users, iamPostureUser(user, now))
+	}
+	result.Findings = postureFindings(users, thresholds, now)
+
+	var gaps []string
+	for _, user := range users {
+		if len(user.Gaps) > 0 {
+			gaps = append(gaps, user.UserName)
+		}
+	}
+	switch {
+	case len(users) == 0:
+		result.Message = "No IAM users found."
+	case len(result.Findings) == 0:
+		result.Message = fmt.Sprintf("%d users reviewed, no findings at %s severity or above.", len(users), thresholds.MinSeverity)
+	default:
+		result.Message = fmt.Sprintf("%d users reviewed, %d findings.", len(users), len(result.Findings))
+	}
+	if len(gaps) > 0 {
+		result.Message += fmt.Sprintf(" Some credential facts could not be read for %s; those checks were skipped.", strings.Join(gaps, ", "))
+	}
+	result.Status = "success"
+	return result, nil
+}
+
+func (p IAMPosture) Desc() string {
+	return "Report IAM credential hygiene per user, covering access key age and use, MFA, console passwords and inactivity, ranked by severity."
+}
+
+func (p IAMPosture) Capability() string {
+	return "iam-posture"
+}
+
+func (p IAMPosture) Help() HelpDoc {
+	return HelpDoc{
+		MetadataSyntax: []string{
+			"set metadata [<threshold>=<value>...]",
+			"Thresholds: key-age=<days>, password-age=<days> and inactive=<days>, each 90 by default; min=<high|medium|low> hides less severe findings.",
+			"high: a console password without MFA, or an active access key older than key-age.",
+			"medium: an active key unused for inactive days, more than one active key, a password older than password-age, or a user with credentials idle for inactive days.",
+			"low: an inactive access key that was never deleted.",
+		},
+		MetadataExamples: []string{
+			"set metadata",
+			"set metadata key-age=180 inactive=60",
+			"set metadata min=high",
+		},
+		MetadataSuggestions: []Suggestion{
+			{Text: "key-age=", Description: "flag active access keys older than this many days"},
+			{Text: "password-age=", Description: "flag console passwords not changed for this many days"},
+			{Text: "inactive=", Description: "flag keys and users idle for this many days"},
+			{Text: "min=high", Description: "only report high severity findings"},
+		},
+		SafetyNotes: []string{
+			"Read-only: the payload lists users, keys, MFA devices and login profiles and changes nothing.",
+			"Facts a provider does not report are listed as gaps and their checks are skipped rather than reported.",
+		},
+	}
+}
+
+func parseIAMPostureThresholds(metadata string) (iamPostureThresholds, error) {
+	thresholds := iamPostureThresholds{
+		KeyAgeDays:      90,
+		PasswordAgeDays: 90,
+		InactiveDays:    90,
+		MinSeverity:     severityLow,
+	}
+	for _, token := range argparse.Split(metadata) {
+		key, value, ok := strings.Cut(token, "=")
+		if !ok {
+			return iamPostureThresholds{}, fmt.Errorf("invalid threshold %q: expected <threshold>=<value>", token)
+		}
+		value = strings.TrimSpace(value)
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "min" || key == "severity" {
+			value = strings.ToLower(value)
+			if _, ok := severityRank[value]; !ok {
+				return iamPostureThresholds{}, fmt.Errorf("invalid min %q: expected high, medium or low", value)
+			}
+			thresholds.MinSeverity = value
+			continue
+		}
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days <= 0 {
+			return iamPostureThresholds{}, fmt.Errorf("invalid %s %q: expected a positive number of days", key, value)
+		}
+		switch key {
+		case "key-age":
+			thresholds.KeyAgeDays = days
+		case "password-age":
+			thresholds.PasswordAgeDays = days
+		case "inactive":
+			thresholds.InactiveDays = days
+		default:
+			return iamPostureThresholds{}, fmt.Errorf("unknown threshold %q: expected key-age, password-age, inactive or min", key)
+		}
+	}
+	return thresholds, nil
+}
+
+// scoreUserPosture applies the iam-posture checks to one user. A check whose
+// input is listed in the user's gaps is skipped.
+func scoreUserPosture(user schema.UserPosture, thresholds iamPostureThresholds, now time.Time) []iamPostureFinding {
+	var findings []iamPostureFinding
+	add := func(severity, check, detail string) {
+		findings = append(findings, iamPostureFinding{
+			Severity: severity,
+			Account:  user.Account,
+			UserName: user.UserName,
+			Check:    check,
+			Detail:   detail,
+		})
+	}
+
+	if user.Password && !user.MFA && !user.HasGap(schema.PostureGapMFA) {
+		add(severityHigh, "console-without-mfa", "console password set with no MFA device")
+	}
+	if user.Password && !user.PasswordChanged.IsZero() && !user.HasGap(schema.PostureGapPasswordAge) {
+		if age := daysSince(user.PasswordChanged, now); age > thresholds.PasswordAgeDays {
+			add(severityMedium, "password-age", fmt.Sprintf("console password unchanged for %d days", age))
+		}
+	}
+
+	active := 0
+	lastActivity := user.LastLogin
+	keyUseKnown := !user.HasGap(schema.PostureGapKeyLastUsed)
+	for _, key := range user.AccessKeys {
+		if !key.Active {
+			add(severityLow, "inactive-key", fmt.Sprintf("access key %s is disabled but not deleted", key.ID))
+			continue
+		}
+		active++
+		if key.LastUsed.After(lastActivity) {
+			lastActivity = key.LastUsed
+		}
+		if !key.CreateTime.IsZero() {
+			if age := daysSince(key.CreateTime, now); age > thresholds.KeyAgeDays {
+				add(severityHigh, "key-age", fmt.Sprintf("access key %s is %d days old", key.ID, age))
+			}
+		}
+		if !keyUseKnown {
+			continue
+		}
+		switch {
+		case !key.LastUsed.IsZero():
+			if idle := daysSince(key.LastUsed, now); idle > thresholds.InactiveDays {
+				add(severityMedium, "key-unused", fmt.Sprintf("access key %s last used %d days ago", key.ID, idle))
+			}
+		case !key.CreateTime.IsZero() && daysSince(key.CreateTime, now) > thresholds.InactiveDays:
+			add(severityMedium, "key-unused", fmt.Sprintf("access key %s has never been used", key.ID))
+		}
+	}
+	if active > 1 {
+		add(severityMedium, "multiple-keys", fmt.Sprintf("%d active access keys", active))
+	}
+
+	// Inactivity needs every sign of life the user could show: console
+	// sign-ins when a password is set and key use when keys are active.
+	credentialed := user.Password || active > 0
+	known := !(user.Password && user.HasGap(schema.PostureGapLastLogin)) && !(active > 0 && !keyUseKnown)
+	if credentialed && known && !user.CreateTime.IsZero() && daysSince(user.CreateTime, now) > thresholds.InactiveDays {
+		if lastActivity.IsZero() {
+			add(severityMedium, "inactive-user", "credentials never used")
+		} else if idle := daysSince(lastActivity, now); idle > thresholds.InactiveDays {
+			add(severityMedium, "inactive-user", fmt.Sprintf("no sign-in or key use for %d days", idle))
+		}
+	}
+	return findings
+}
+
+// postureFindings scores every user and keeps the findings at or above the
+// minimum severity, most severe first.
+func postureFindings(users []schema.UserPosture, thresholds iamPostureThresholds, now time.Time) []iamPostureFinding {
+	var findings []iamPostureFinding
+	for _, user := range users {
+		for _, finding := range scoreUserPosture(user, thresholds, now) {
+			if severityRank[finding.Severity] <= severityRank[thresholds.MinSeverity] {
+				findings = append(findings, finding)
+			}
+		}
+	}
+	sortPostureFindings(findings)
+	return findings
+}
+
+func sortPostureFindings(findings []iamPostureFinding) {
+	sort.SliceStable(findings, func(a, b int) bool {
+		if ra, rb := severityRank[findings[a].Severity], severityRank[findings[b].Severity]; ra != rb {
+			return ra < rb
+		}
+		if findings[a].Account != findings[b].Account {
+			return findings[a].Account < findings[b].Account
+		}
+		return findings[a].UserName < findings[b].UserName
+	})
+}
+
+func iamPostureUser(user schema.UserPosture, now time.Time) iamPostureUserJSON {
+	out := iamPostureUserJSON{
+		Account:         user.Account,
+		UserName:        user.UserName,
+		UserID:          user.UserID,
+		CreateTime:      postureTime(user.CreateTime),
+		LastLogin:       postureTime(user.LastLogin),
+		Password:        user.Password,
+		PasswordChanged: postureTime(user.PasswordChanged),
+		MFA:             user.MFA,
+		Gaps:            user.Gaps,
+	}
+	for _, key := range user.AccessKeys {
+		row := iamPostureKeyJSON{
+			ID:              key.ID,
+			Active:          key.Active,
+			CreateTime:      postureTime(key.CreateTime),
+			LastUsed:        postureTime(key.LastUsed),
+			LastUsedService: key.LastUsedService,
+		}
+		if !key.CreateTime.IsZero() {
+			row.AgeDays = daysSince(key.CreateTime, now)
+		}
+		out.AccessKeys = append(out.AccessKeys, row)
+	}
+	return out
+}
+
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+func postureTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func postureFlag(value, unknown bool) string {
+	switch {
+	case unknown:
+		return "?"
+	case value:
+		return "yes"
+	}
+	return "no"
+}
+
+func init() {
+	registerPayload("iam-posture", IAMPosture{})
+}
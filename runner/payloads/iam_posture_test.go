@@ -0,0 +1,213 @@
+package payloads
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+var postureNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func daysAgo(n int) time.Time {
+	return postureNow.AddDate(0, 0, -n)
+}
+
+func postureChecks(findings []iamPostureFinding) []string {
+	out := make([]string, 0, len(findings))
+	for _, f := range findings {
+		out = append(out, f.Severity+" "+f.Check)
+	}
+	return out
+}
+
+func TestScoreUserPosture(t *testing.T) {
+	usedKey := func(id string, created, used int) schema.AccessKeyPosture {
+		return schema.AccessKeyPosture{ID: id, Active: true, CreateTime: daysAgo(created), LastUsed: daysAgo(used)}
+	}
+	neverUsedKey := func(id string, created int) schema.AccessKeyPosture {
+		return schema.AccessKeyPosture{ID: id, Active: true, CreateTime: daysAgo(created)}
+	}
+
+	tests := []struct {
+		name     string
+		user     schema.UserPosture
+		metadata string
+		want     []string
+	}{
+		{
+			name: "clean",
+			user: schema.UserPosture{
+				CreateTime: daysAgo(400), LastLogin: daysAgo(5),
+				Password: true, PasswordChanged: daysAgo(10), MFA: true,
+				AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 30, 1)},
+			},
+		},
+		{
+			name: "console without mfa",
+			user: schema.UserPosture{CreateTime: daysAgo(10), Password: true},
+			want: []string{"high console-without-mfa"},
+		},
+		{
+			name: "mfa unknown",
+			user: schema.UserPosture{CreateTime: daysAgo(10), Password: true, Gaps: []string{schema.PostureGapMFA}},
+		},
+		{
+			name: "password age",
+			user: schema.UserPosture{CreateTime: daysAgo(10), Password: true, MFA: true, PasswordChanged: daysAgo(120)},
+			want: []string{"medium password-age"},
+		},
+		{
+			name: "password age unknown",
+			user: schema.UserPosture{CreateTime: daysAgo(10), Password: true, MFA: true, PasswordChanged: daysAgo(120), Gaps: []string{schema.PostureGapPasswordAge}},
+		},
+		{
+			name:     "password age override",
+			user:     schema.UserPosture{CreateTime: daysAgo(10), Password: true, MFA: true, PasswordChanged: daysAgo(120)},
+			metadata: "password-age=180",
+		},
+		{
+			name: "old key",
+			user: schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 200, 1)}},
+			want: []string{"high key-age"},
+		},
+		{
+			name:     "old key within override",
+			user:     schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 200, 1)}},
+			metadata: "key-age=365d",
+		},
+		{
+			name: "key never used",
+			user: schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{neverUsedKey("AK1", 100)}},
+			want: []string{"high key-age", "medium key-unused", "medium inactive-user"},
+		},
+		{
+			name: "key use unknown",
+			user: schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{neverUsedKey("AK1", 100)}, Gaps: []string{schema.PostureGapKeyLastUsed}},
+			want: []string{"high key-age"},
+		},
+		{
+			name: "key idle",
+			user: schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 60, 50)}},
+		},
+		{
+			name:     "key idle past inactive override",
+			user:     schema.UserPosture{CreateTime: daysAgo(300), AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 60, 50)}},
+			metadata: "inactive=30",
+			want:     []string{"medium key-unused", "medium inactive-user"},
+		},
+		{
+			name: "disabled key",
+			user: schema.UserPosture{CreateTime: daysAgo(10), AccessKeys: []schema.AccessKeyPosture{{ID: "AK1", CreateTime: daysAgo(400)}}},
+			want: []string{"low inactive-key"},
+		},
+		{
+			name: "multiple keys",
+			user: schema.UserPosture{CreateTime: daysAgo(10), AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 5, 1), usedKey("AK2", 5, 1)}},
+			want: []string{"medium multiple-keys"},
+		},
+		{
+			name: "inactive console user",
+			user: schema.UserPosture{CreateTime: daysAgo(400), LastLogin: daysAgo(200), Password: true, MFA: true, PasswordChanged: daysAgo(10)},
+			want: []string{"medium inactive-user"},
+		},
+		{
+			name: "key use keeps a console user active",
+			user: schema.UserPosture{
+				CreateTime: daysAgo(400), LastLogin: daysAgo(200), Password: true, MFA: true, PasswordChanged: daysAgo(10),
+				AccessKeys: []schema.AccessKeyPosture{usedKey("AK1", 30, 2)},
+			},
+		},
+		{
+			name: "last login unknown",
+			user: schema.UserPosture{CreateTime: daysAgo(400), Password: true, MFA: true, PasswordChanged: daysAgo(10), Gaps: []string{schema.PostureGapLastLogin}},
+		},
+		{
+			name: "no credentials",
+			user: schema.UserPosture{CreateTime: daysAgo(400)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds, err := parseIAMPostureThresholds(tt.metadata)
+			if err != nil {
+				t.Fatalf("parseIAMPostureThresholds(%q): %v", tt.metadata, err)
+			}
+			tt.user.UserName = "alice"
+			got := postureChecks(scoreUserPosture(tt.user, thresholds, postureNow))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPostureFindingsMinSeverity(t *testing.T) {
+	users := []schema.UserPosture{
+		{UserName: "carol", CreateTime: daysAgo(10), AccessKeys: []schema.AccessKeyPosture{{ID: "AK9", CreateTime: daysAgo(5)}}},
+		{UserName: "bob", CreateTime: daysAgo(10), Password: true, MFA: true, PasswordChanged: daysAgo(120)},
+		{UserName: "alice", CreateTime: daysAgo(10), Password: true},
+	}
+
+	tests := []struct {
+		metadata string
+		want     []string
+	}{
+		{metadata: "", want: []string{"high alice console-without-mfa", "medium bob password-age", "low carol inactive-key"}},
+		{metadata: "min=medium", want: []string{"high alice console-without-mfa", "medium bob password-age"}},
+		{metadata: "min=high", want: []string{"high alice console-without-mfa"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.metadata, func(t *testing.T) {
+			thresholds, err := parseIAMPostureThresholds(tt.metadata)
+			if err != nil {
+				t.Fatalf("parseIAMPostureThresholds(%q): %v", tt.metadata, err)
+			}
+			var got []string
+			for _, f := range postureFindings(users, thresholds, postureNow) {
+				got = append(got, f.Severity+" "+f.UserName+" "+f.Check)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseIAMPostureThresholds(t *testing.T) {
+	tests := []struct {
+		metadata string
+		want     iamPostureThresholds
+		wantErr  string
+	}{
+		{metadata: "", want: iamPostureThresholds{KeyAgeDays: 90, PasswordAgeDays: 90, InactiveDays: 90, MinSeverity: severityLow}},
+		{
+			metadata: "key-age=180 password-age=60d inactive=30",
+			want:     iamPostureThresholds{KeyAgeDays: 180, PasswordAgeDays: 60, InactiveDays: 30, MinSeverity: severityLow},
+		},
+		{metadata: "MIN=High", want: iamPostureThresholds{KeyAgeDays: 90, PasswordAgeDays: 90, InactiveDays: 90, MinSeverity: severityHigh}},
+		{metadata: "severity=medium", want: iamPostureThresholds{KeyAgeDays: 90, PasswordAgeDays: 90, InactiveDays: 90, MinSeverity: severityMedium}},
+		{metadata: "min=critical", wantErr: "invalid min"},
+		{metadata: "key-age=0", wantErr: "positive number of days"},
+		{metadata: "inactive=soon", wantErr: "positive number of days"},
+		{metadata: "mfa=1", wantErr: "unknown threshold"},
+		{metadata: "key-age", wantErr: "expected <threshold>=<value>"},
+	}
+	for _, tt := range tests {
+		got, err := parseIAMPostureThresholds(tt.metadata)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseIAMPostureThresholds(%q) error = %v, want %q", tt.metadata, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseIAMPostureThresholds(%q) = %+v, %v; want %+v", tt.metadata, got, err, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -23,6 +24,8 @@ type IAMUserCheckResult struct {
 	AccountID string `json:"account_id,omitempty"`
 	Message   string `json:"message,omitempty"`
 	Error     string `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
 }
 
 type iamUserAction struct {
@@ -63,6 +66,7 @@ func (p IAMUserCheck) Run(ctx context.Context, config map[string]string) {
 	} else {
 		logger.Warning(iamResult.Message)
 	}
+	logDetection(iamResult.Detection)
 }
 
 func (p IAMUserCheck) Result(ctx context.Context, config map[string]string) (any, error) {
@@ -70,6 +74,9 @@ func (p IAMUserCheck) Result(ctx context.Context, config map[string]string) (any
 	if err != nil {
 		return nil, err
 	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
 
 	i, err := inventoryFromConfig(config)
 	if err != nil {
@@ -81,6 +88,7 @@ func (p IAMUserCheck) Result(ctx context.Context, config map[string]string) (any
 		return nil, fmt.Errorf("%s does not support user management", i.Providers.Name())
 	}
 
+	started := time.Now()
 	iamResult, err := mgr.UserManagement(parsed.Action, parsed.Username, parsed.Password)
 
 	result := IAMUserCheckResult{
@@ -101,6 +109,16 @@ func (p IAMUserCheck) Result(ctx context.Context, config map[string]string) (any
 	result.Message = iamResult.Message
 
 	result.Status = "success"
+	userArtifact := artifact{payload: "iam-user-check", action: parsed.Action, resource: "user:" + parsed.Username}
+	switch parsed.Action {
+	case "add":
+		userArtifact.undo = []string{"del", parsed.Username}
+		recordArtifact(i.Providers, config, userArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: userCreateAPIs, resources: []string{parsed.Username}})
+	case "del":
+		resolveArtifact(ctx, i.Providers, config, userArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: userDeleteAPIs, resources: []string{parsed.Username}})
+	}
 	return result, nil
 }
 
@@ -0,0 +1,498 @@
+package payloads
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/vmexecspec"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/argparse"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/table"
+)
+
+// defaultBatchConcurrency caps in-flight instance commands when the metadata
+// does not set concurrency=N.
+const defaultBatchConcurrency = 5
+
+type InstanceCmdBatch struct{}
+
+type InstanceCmdBatchResult struct {
+	Provider    string               `json:"provider"`
+	Filter      string               `json:"filter"`
+	Command     string               `json:"command"`
+	Concurrency int                  `json:"concurrency"`
+	Matched     int                  `json:"matched"`
+	Succeeded   int                  `json:"succeeded"`
+	Failed      int                  `json:"failed"`
+	Instances   []InstanceCmdOutcome `json:"instances"`
+	Status      string               `json:"status"`
+	Error       string               `json:"error,omitempty"`
+}
+
+// InstanceCmdOutcome is the per-instance result of a batch run. Status is
+// success, failed (the command ran but exited non-zero) or error (the exec
+// service did not run it to completion).
+type InstanceCmdOutcome struct {
+	InstanceID string `json:"instance_id"`
+	HostName   string `json:"host_name,omitempty"`
+	Region     string `json:"region,omitempty"`
+	OSType     string `json:"os_type"`
+	Status     string `json:"status"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
+	Stdout     string `json:"stdout,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	Duration   string `json:"duration"`
+	Error      string `json:"error,omitempty"`
+}
+
+// hostFilter selects hosts from the inventory. Values of the same key are
+// OR-ed, different keys are AND-ed.
+type hostFilter struct {
+	Regions     []string
+	OSTypes     []string
+	Names       []string
+	Tags        []string
+	Concurrency int
+	// Refresh enumerates hosts live instead of using the persisted
+	// inventory.
+	Refresh bool
+}
+
+type instanceBatch struct {
+	Filter  hostFilter
+	Command string
+}
+
+func (p InstanceCmdBatch) Run(ctx context.Context, config map[string]string) {
+	resultAny, err := p.Result(ctx, config)
+	if err != nil && resultAny == nil {
+		logger.Error(err.Error())
+		return
+	}
+	result, ok := resultAny.(InstanceCmdBatchResult)
+	if !ok {
+		logger.Error("Invalid result type")
+		return
+	}
+	if result.Status == "error" && len(result.Instances) == 0 {
+		logger.Error(result.Error)
+		return
+	}
+
+	type batchRow struct {
+		InstanceID string `table:"Instance ID"`
+		HostName   string `table:"HostName"`
+		Region     string `table:"Region"`
+		Status     string `table:"Status"`
+		ExitCode   string `table:"Exit Code"`
+		Duration   string `table:"Duration"`
+	}
+	rows := make([]batchRow, 0, len(result.Instances))
+	for _, outcome := range result.Instances {
+		row := batchRow{
+			InstanceID: outcome.InstanceID,
+			HostName:   outcome.HostName,
+			Region:     outcome.Region,
+			Status:     outcome.Status,
+			Duration:   outcome.Duration,
+		}
+		if outcome.ExitCode != nil {
+			row.ExitCode = strconv.Itoa(*outcome.ExitCode)
+		}
+		rows = append(rows, row)
+	}
+	table.Output(rows)
+
+	for _, outcome := range result.Instances {
+		if outcome.Stdout == "" && outcome.Stderr == "" && outcome.Error == "" {
+			continue
+		}
+		logger.Warning(fmt.Sprintf("%s (%s)", outcome.InstanceID, outcome.Status))
+		if outcome.Stdout != "" {
+			if _, err := os.Stdout.WriteString(outcome.Stdout); err != nil {
+				logger.Error(err.Error())
+			}
+		}
+		if outcome.Stderr != "" {
+			if _, err := os.Stderr.WriteString(outcome.Stderr); err != nil {
+				logger.Error(err.Error())
+			}
+		}
+		if outcome.Error != "" {
+			logger.Error(outcome.Error)
+		}
+	}
+	logger.Info(fmt.Sprintf("%d matched, %d succeeded, %d failed", result.Matched, result.Succeeded, result.Failed))
+}
+
+func (p InstanceCmdBatch) Result(ctx context.Context, config map[string]string) (any, error) {
+	parsed, err := parseInstanceBatch(config["metadata"])
+	if err != nil {
+		return nil, err
+	}
+
+	i, err := inventoryFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	execer, ok := i.Providers.(schema.VMExecutor)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support instance-cmd-batch", i.Providers.Name())
+	}
+
+	result := InstanceCmdBatchResult{
+		Provider:    i.Providers.Name(),
+		Filter:      parsed.Filter.String(),
+		Command:     parsed.Command,
+		Concurrency: parsed.Filter.concurrency(),
+		Instances:   []InstanceCmdOutcome{},
+	}
+
+	ctx = withCachedInventory(ctx, i.Providers, config)
+	inventoried, err := batchHosts(ctx, i.Providers, config, parsed.Filter.Refresh)
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+		return result, NewResultError(result, 4, err)
+	}
+	hosts := parsed.Filter.Select(inventoried)
+	result.Matched = len(hosts)
+	if len(hosts) == 0 {
+		err := fmt.Errorf("no hosts match %s", result.Filter)
+		result.Status = "error"
+		result.Error = err.Error()
+		return result, NewResultError(result, 4, err)
+	}
+
+	result.Instances = runInstanceBatch(ctx, execer, hosts, parsed.Command, result.Concurrency)
+	for _, outcome := range result.Instances {
+		if outcome.Status == "success" {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+	switch {
+	case result.Failed == 0:
+		result.Status = "success"
+		return result, nil
+	case result.Succeeded == 0:
+		result.Status = "error"
+	default:
+		result.Status = "partial"
+	}
+	err = fmt.Errorf("%d of %d instances did not succeed", result.Failed, result.Matched)
+	result.Error = err.Error()
+	return result, NewResultError(result, 4, err)
+}
+
+// batchHosts returns the hosts to filter: the persisted inventory of the
+// credential unless refresh is set, otherwise (or when nothing is cached) a
+// live host enumeration, which is saved for the next run.
+func batchHosts(ctx context.Context, provider schema.Provider, config map[string]string, refresh bool) ([]schema.Host, error) {
+	if !refresh {
+		if scope, ok := invcache.From(ctx); ok {
+			if entry, ok := scope.Store.Load(scope.Credential); ok && len(entry.Hosts) > 0 {
+				logger.Info(fmt.Sprintf("Using %d hosts from the inventory of %s; add refresh=true to enumerate again.", len(entry.Hosts), entry.HostsAt.Local().Format(time.DateTime)))
+				return entry.Hosts, nil
+			}
+		}
+	}
+	enum, ok := provider.(schema.Enumerator)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support cloud asset inventory", provider.Name())
+	}
+	hostEnv := env.From(ctx).Clone()
+	hostEnv.Cloudlist = []string{schema.AssetHost}
+	hostCtx := env.With(ctx, hostEnv)
+	resources, err := enum.Resources(hostCtx)
+	if err != nil && len(resources.Assets) == 0 {
+		return nil, err
+	}
+	saveInventory(hostCtx, provider, config, resources)
+	var hosts []schema.Host
+	for _, asset := range resources.Assets {
+		if host, ok := asset.(schema.Host); ok {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts, nil
+}
+
+// runInstanceBatch executes command on every host with at most concurrency
+// commands in flight. Outcomes keep the order of hosts.
+func runInstanceBatch(ctx context.Context, execer schema.VMExecutor, hosts []schema.Host, command string, concurrency int) []InstanceCmdOutcome {
+	outcomes := make([]InstanceCmdOutcome, len(hosts))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for idx, host := range hosts {
+		osType := normalizeOSType(host.OSType)
+		outcomes[idx] = InstanceCmdOutcome{
+			InstanceID: host.ID,
+			HostName:   host.HostName,
+			Region:     host.Region,
+			OSType:     osType,
+		}
+		select {
+		case <-ctx.Done():
+			outcomes[idx].Status = "error"
+			outcomes[idx].Error = ctx.Err().Error()
+			continue
+		case sem <- struct{}{}:
+		}
+		wg.Add(1)
+		go func(outcome *InstanceCmdOutcome) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			spec := vmexecspec.BuildLinux(command)
+			if osType == "windows" {
+				spec = vmexecspec.BuildWindows(command)
+			}
+			started := time.Now()
+			commandResult, err := execer.ExecuteCloudVMCommand(ctx, outcome.InstanceID, spec)
+			outcome.Duration = time.Since(started).Round(time.Millisecond).String()
+			outcome.Stdout = commandResult.Output
+			outcome.Stderr = commandResult.Stderr
+			outcome.ExitCode = commandResult.ExitCode
+			switch {
+			case err != nil && commandResult.ExitCode != nil && *commandResult.ExitCode != 0:
+				outcome.Status = "failed"
+				outcome.Error = err.Error()
+			case err != nil:
+				outcome.Status = "error"
+				outcome.Error = err.Error()
+			case commandResult.ExitCode != nil && *commandResult.ExitCode != 0:
+				outcome.Status = "failed"
+			default:
+				outcome.Status = "success"
+			}
+		}(&outcomes[idx])
+	}
+	wg.Wait()
+	return outcomes
+}
+
+// normalizeOSType folds the provider-specific OS labels (LINUX_UNIX, Windows,
+// windows-server, ...) into the linux/windows pair the exec drivers accept.
+// Hosts without an OS label are treated as linux.
+func normalizeOSType(osType string) string {
+	if strings.Contains(strings.ToLower(osType), "win") {
+		return "windows"
+	}
+	return "linux"
+}
+
+func parseInstanceBatch(metadata string) (instanceBatch, error) {
+	var batch instanceBatch
+	rest := strings.TrimSpace(metadata)
+	for rest != "" {
+		data := argparse.SplitN(rest, 2)
+		if len(data) == 0 {
+			break
+		}
+		key, value, ok := strings.Cut(data[0], "=")
+		if !ok || !isBatchFilterKey(key) {
+			break
+		}
+		if value == "" {
+			return instanceBatch{}, fmt.Errorf("empty value for %s filter", key)
+		}
+		if err := batch.Filter.add(key, value); err != nil {
+			return instanceBatch{}, err
+		}
+		rest = ""
+		if len(data) > 1 {
+			rest = strings.TrimSpace(data[1])
+		}
+	}
+	if rest == "" {
+		return instanceBatch{}, errors.New("invalid metadata format: expected '<filter>... <cmd>'")
+	}
+	if !batch.Filter.selective() {
+		return instanceBatch{}, errors.New("at least one region=, os=, name= or tag= filter is required; use name=* to target every host")
+	}
+	batch.Command = rest
+	return batch, nil
+}
+
+func isBatchFilterKey(key string) bool {
+	switch key {
+	case "region", "os", "name", "tag", "concurrency", "refresh":
+		return true
+	}
+	return false
+}
+
+func (f *hostFilter) add(key, value string) error {
+	switch key {
+	case "region":
+		f.Regions = append(f.Regions, value)
+	case "os":
+		osType := strings.ToLower(value)
+		if osType != "linux" && osType != "windows" {
+			return fmt.Errorf("invalid os filter %q: expected linux or windows", value)
+		}
+		f.OSTypes = append(f.OSTypes, osType)
+	case "name":
+		if _, err := path.Match(value, ""); err != nil {
+			return fmt.Errorf("invalid name pattern %q: %w", value, err)
+		}
+		f.Names = append(f.Names, value)
+	case "tag":
+		f.Tags = append(f.Tags, value)
+	case "concurrency":
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid concurrency %q: expected a positive integer", value)
+		}
+		f.Concurrency = n
+	case "refresh":
+		refresh, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid refresh %q: expected true or false", value)
+		}
+		f.Refresh = refresh
+	}
+	return nil
+}
+
+func (f hostFilter) selective() bool {
+	return len(f.Regions)+len(f.OSTypes)+len(f.Names)+len(f.Tags) > 0
+}
+
+func (f hostFilter) concurrency() int {
+	if f.Concurrency > 0 {
+		return f.Concurrency
+	}
+	return defaultBatchConcurrency
+}
+
+// Select returns the hosts matching every filter key, in inventory order.
+func (f hostFilter) Select(hosts []schema.Host) []schema.Host {
+	out := make([]schema.Host, 0, len(hosts))
+	for _, host := range hosts {
+		if f.matches(host) {
+			out = append(out, host)
+		}
+	}
+	return out
+}
+
+func (f hostFilter) matches(host schema.Host) bool {
+	if len(f.Regions) > 0 && !anyOf(f.Regions, func(region string) bool {
+		return strings.EqualFold(region, host.Region)
+	}) {
+		return false
+	}
+	if len(f.OSTypes) > 0 && !anyOf(f.OSTypes, func(osType string) bool {
+		return osType == normalizeOSType(host.OSType)
+	}) {
+		return false
+	}
+	if len(f.Names) > 0 && !anyOf(f.Names, func(pattern string) bool {
+		return globMatch(pattern, host.HostName) || globMatch(pattern, host.ID)
+	}) {
+		return false
+	}
+	if len(f.Tags) > 0 && !anyOf(f.Tags, func(tag string) bool {
+		key, value, hasValue := strings.Cut(tag, "=")
+		got, ok := host.Tags[key]
+		if !ok {
+			return false
+		}
+		return !hasValue || globMatch(value, got)
+	}) {
+		return false
+	}
+	return true
+}
+
+func (f hostFilter) String() string {
+	parts := make([]string, 0, len(f.Regions)+len(f.OSTypes)+len(f.Names)+len(f.Tags))
+	for _, v := range f.Regions {
+		parts = append(parts, "region="+v)
+	}
+	for _, v := range f.OSTypes {
+		parts = append(parts, "os="+v)
+	}
+	for _, v := range f.Names {
+		parts = append(parts, "name="+v)
+	}
+	for _, v := range f.Tags {
+		parts = append(parts, "tag="+v)
+	}
+	return strings.Join(parts, " ")
+}
+
+func anyOf(values []string, match func(string) bool) bool {
+	for _, v := range values {
+		if match(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func globMatch(pattern, value string) bool {
+	ok, err := path.Match(pattern, value)
+	return err == nil && ok
+}
+
+func (p InstanceCmdBatch) Help() HelpDoc {
+	return HelpDoc{
+		MetadataSyntax: []string{
+			"set metadata <filter>... <cmd>",
+			"Filters: region=<region>, os=linux|windows, name=<glob>, tag=<key>[=<glob>], concurrency=<n> (default 5), refresh=true.",
+			"Repeated keys are OR-ed, different keys are AND-ed. Hosts come from the inventory saved by the last `cloudlist` (or batch) run; refresh=true, or an empty or expired inventory, enumerates hosts live first.",
+		},
+		MetadataExamples: []string{
+			"set metadata region=cn-hangzhou os=linux whoami",
+			"set metadata name=web-* concurrency=10 'id && hostname'",
+			"set metadata tag=env=lab os=windows whoami",
+			"set metadata refresh=true region=us-east-1 uptime",
+		},
+		MetadataSuggestions: []Suggestion{
+			{Text: "name=* <cmd>", Description: "run one validation command on every inventoried host"},
+			{Text: "region=<region> <cmd>", Description: "run one validation command on hosts in a region"},
+			{Text: "tag=<key>=<value> <cmd>", Description: "run one validation command on hosts carrying a tag"},
+		},
+		SafetyNotes: []string{
+			"Use only on instances that are owned, lab-managed, or explicitly authorized for command validation.",
+			"Check the filter against `cloudlist -host` output first; every matching host receives the command.",
+		},
+	}
+}
+
+func (p InstanceCmdBatch) Desc() string {
+	return "Run an authorized validation command across a filtered set of cloud instances to verify detection coverage fleet-wide."
+}
+
+func (p InstanceCmdBatch) Capability() string {
+	return "vm"
+}
+
+func (p InstanceCmdBatch) Sensitivity(metadata string) Sensitivity {
+	parsed, err := parseInstanceBatch(metadata)
+	if err != nil {
+		return Sensitivity{}
+	}
+	return Sensitivity{
+		Level:      "destructive",
+		ConfirmKey: "instance-cmd-batch.exec",
+		Resource:   parsed.Filter.String(),
+	}
+}
+
+func init() {
+	registerPayload("instance-cmd-batch", InstanceCmdBatch{})
+}
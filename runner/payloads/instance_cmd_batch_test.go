@@ -0,0 +1,78 @@
+package payloads
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/invcache"
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+)
+
+const batchTestCredential = "0123456789abcdef0123456789abcdef"
+
+type batchTestProvider struct {
+	calls int
+	hosts []schema.Host
+}
+
+func (p *batchTestProvider) Name() string { return "test" }
+
+func (p *batchTestProvider) Resources(context.Context) (schema.Resources, error) {
+	p.calls++
+	resources := schema.Resources{Provider: "test"}
+	for _, host := range p.hosts {
+		resources.Assets = append(resources.Assets, host)
+	}
+	return resources, nil
+}
+
+func TestBatchHostsPrefersPersistedInventory(t *testing.T) {
+	store := &invcache.Store{Dir: t.TempDir(), TTL: time.Hour}
+	ctx := invcache.With(context.Background(), store, batchTestCredential)
+	provider := &batchTestProvider{hosts: []schema.Host{{ID: "i-live", Region: "us-east-1"}}}
+
+	// Nothing cached yet: enumerate live and persist the result.
+	hosts, err := batchHosts(ctx, provider, nil, false)
+	if err != nil {
+		t.Fatalf("batchHosts: %v", err)
+	}
+	if provider.calls != 1 || len(hosts) != 1 || hosts[0].ID != "i-live" {
+		t.Fatalf("calls=%d hosts=%+v", provider.calls, hosts)
+	}
+	if _, ok := store.Host(batchTestCredential, "i-live"); !ok {
+		t.Fatal("live enumeration was not saved to the inventory")
+	}
+
+	// The next run resolves from the inventory without enumerating.
+	provider.hosts = []schema.Host{{ID: "i-new", Region: "us-east-1"}}
+	hosts, err = batchHosts(ctx, provider, nil, false)
+	if err != nil {
+		t.Fatalf("batchHosts: %v", err)
+	}
+	if provider.calls != 1 || len(hosts) != 1 || hosts[0].ID != "i-live" {
+		t.Fatalf("expected cached hosts, got calls=%d hosts=%+v", provider.calls, hosts)
+	}
+
+	// refresh=true enumerates again.
+	hosts, err = batchHosts(ctx, provider, nil, true)
+	if err != nil {
+		t.Fatalf("batchHosts: %v", err)
+	}
+	if provider.calls != 2 || len(hosts) != 1 || hosts[0].ID != "i-new" {
+		t.Fatalf("expected refreshed hosts, got calls=%d hosts=%+v", provider.calls, hosts)
+	}
+}
+
+func TestParseInstanceBatchRefresh(t *testing.T) {
+	parsed, err := parseInstanceBatch("refresh=true name=web-* uptime")
+	if err != nil {
+		t.Fatalf("parseInstanceBatch: %v", err)
+	}
+	if !parsed.Filter.Refresh || parsed.Command != "uptime" {
+		t.Fatalf("unexpected batch: %+v", parsed)
+	}
+	if _, err := parseInstanceBatch("refresh=maybe name=* uptime"); err == nil {
+		t.Fatal("expected an error for refresh=maybe")
+	}
+}
@@ -18,6 +18,8 @@ type InstanceCmdCheckResult struct {
 	InstanceID string `json:"instance_id"`
 	Command    string `json:"command"`
 	Output     string `json:"output,omitempty"`
+	Stderr     string `json:"stderr,omitempty"`
+	ExitCode   *int   `json:"exit_code,omitempty"`
 	Status     string `json:"status"`
 	Error      string `json:"error,omitempty"`
 }
@@ -39,15 +41,18 @@ func (p InstanceCmdCheck) Run(ctx context.Context, config map[string]string) {
 		logger.Error("Invalid result type")
 		return
 	}
-	if result.Status == "error" {
-		logger.Error(result.Error)
-		return
+	if result.Output != "" {
+		if _, err := os.Stdout.WriteString(result.Output); err != nil {
+			logger.Error(err.Error())
+		}
 	}
-	if result.Output == "" {
-		return
+	if result.Stderr != "" {
+		if _, err := os.Stderr.WriteString(result.Stderr); err != nil {
+			logger.Error(err.Error())
+		}
 	}
-	if _, err := os.Stdout.WriteString(result.Output); err != nil {
-		logger.Error(err.Error())
+	if result.Status == "error" {
+		logger.Error(result.Error)
 	}
 }
 
@@ -66,12 +71,15 @@ func (p InstanceCmdCheck) Result(ctx context.Context, config map[string]string)
 		return nil, fmt.Errorf("%s does not support instance-cmd-check", i.Providers.Name())
 	}
 
+	ctx = withCachedInventory(ctx, i.Providers, config)
 	commandResult, err := execer.ExecuteCloudVMCommand(ctx, parsed.InstanceID, parsed.Command)
 	result := InstanceCmdCheckResult{
 		Provider:   i.Providers.Name(),
 		InstanceID: parsed.InstanceID,
 		Command:    parsed.Command,
 		Output:     commandResult.Output,
+		Stderr:     commandResult.Stderr,
+		ExitCode:   commandResult.ExitCode,
 	}
 	if err != nil {
 		result.Status = "error"
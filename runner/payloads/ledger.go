@@ -0,0 +1,107 @@
+package payloads
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/ledger"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/cloudtoolkit/utils/argparse"
+	"github.com/404tk/cloudtoolkit/utils/cache"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// artifact identifies something a mutating payload created. undo is the
+// metadata argv that removes it through the same payload.
+type artifact struct {
+	payload  string
+	action   string
+	resource string
+	undo     []string
+}
+
+// recordArtifact adds a to the cleanup ledger. Replay and cassette playback
+// sessions touch no real account and are not recorded. A ledger failure is only logged: the cloud
+// change already happened and the result must still reach the caller.
+func recordArtifact(provider schema.Provider, config map[string]string, a artifact) {
+	if replaySession() {
+		return
+	}
+	_, err := ledger.Default().Record(ledger.Entry{
+		Provider:   config[utils.Provider],
+		Credential: cache.CredentialUUID(provider, config),
+		Payload:    a.payload,
+		Action:     a.action,
+		Resource:   a.resource,
+		Undo:       argparse.Join(a.undo),
+	})
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Cleanup ledger not updated for %s: %v", a.resource, err))
+	}
+}
+
+type resolutionKey struct{}
+
+// resolveArtifact closes the ledger entry for an artifact the reverse action
+// just removed. The resolution is "payload" unless ctx comes from UndoEntry.
+func resolveArtifact(ctx context.Context, provider schema.Provider, config map[string]string, a artifact) {
+	if replaySession() {
+		return
+	}
+	resolution, ok := ctx.Value(resolutionKey{}).(string)
+	if !ok {
+		resolution = ledger.ResolvedByPayload
+	}
+	_, err := ledger.Default().ResolveArtifact(ledger.Entry{
+		Provider:   config[utils.Provider],
+		Credential: cache.CredentialUUID(provider, config),
+		Payload:    a.payload,
+		Resource:   a.resource,
+	}, resolution)
+	if err != nil {
+		logger.Warning(fmt.Sprintf("Cleanup ledger not updated for %s: %v", a.resource, err))
+	}
+}
+
+// UndoEntry reverses a ledger entry by running its payload with the recorded
+// undo metadata on config. config must carry the credential the artifact was
+// created with; any other credential is refused so a cleanup never touches a
+// different account. The entry is marked resolved only when the payload
+// succeeds.
+func UndoEntry(ctx context.Context, entry ledger.Entry, config map[string]string) (any, error) {
+	payload, _, ok := Lookup(entry.Payload)
+	if !ok {
+		return nil, fmt.Errorf("unsupported payload: %s", entry.Payload)
+	}
+	producer, ok := payload.(ResultProducer)
+	if !ok {
+		return nil, fmt.Errorf("payload %s has no structured result", entry.Payload)
+	}
+	run := make(map[string]string, len(config)+2)
+	for key, value := range config {
+		run[key] = value
+	}
+	run[utils.Provider] = entry.Provider
+	run[utils.Payload] = entry.Payload
+	run[utils.Metadata] = entry.Undo
+
+	i, err := inventoryFromConfig(run)
+	if err != nil {
+		return nil, err
+	}
+	if cache.CredentialUUID(i.Providers, run) != entry.Credential {
+		return nil, fmt.Errorf("entry %s was recorded with credential %s; supply that credential to clean it up", entry.ID, entry.Credential)
+	}
+
+	result, err := producer.Result(context.WithValue(ctx, resolutionKey{}, ledger.ResolvedByCleanup), run)
+	if err != nil {
+		return result, err
+	}
+	// The payload resolves its own artifact; resolving by ID as well covers
+	// entries whose resource key drifted (e.g. a provider-normalized scope).
+	if _, err := ledger.Default().Resolve(entry.ID, ledger.ResolvedByCleanup); err != nil {
+		return result, err
+	}
+	return result, nil
+}
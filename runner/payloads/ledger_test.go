@@ -0,0 +1,43 @@
+package payloads
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/runner/ledger"
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+// undoTestPayload is a ResultProducer that counts its calls.
+type undoTestPayload struct {
+	calls *int
+}
+
+func (undoTestPayload) Run(context.Context, map[string]string) {}
+func (undoTestPayload) Desc() string                           { return "undo test payload" }
+
+func (p undoTestPayload) Result(context.Context, map[string]string) (any, error) {
+	*p.calls++
+	return nil, nil
+}
+
+func TestUndoEntryRefusesOtherCredential(t *testing.T) {
+	const name = "ledger-undo-test"
+	calls := 0
+	Payloads[name] = undoTestPayload{calls: &calls}
+	t.Cleanup(func() { delete(Payloads, name) })
+
+	entry := ledger.Entry{
+		ID: "0badc0de", Provider: "aws", Credential: "recorded-credential",
+		Payload: name, Action: "add", Resource: "user:ctk-demo", Undo: "del ctk-demo",
+	}
+	config := map[string]string{utils.AccessKey: "AKIAEXAMPLE", utils.SecretKey: "secret"}
+	_, err := UndoEntry(context.Background(), entry, config)
+	if err == nil || !strings.Contains(err.Error(), "was recorded with credential recorded-credential") {
+		t.Fatalf("UndoEntry error = %v, want a credential mismatch", err)
+	}
+	if calls != 0 {
+		t.Fatalf("payload ran %d times for a mismatched credential", calls)
+	}
+}
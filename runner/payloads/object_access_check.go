@@ -0,0 +1,320 @@
+package payloads
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/utils/argparse"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+	"github.com/404tk/table"
+)
+
+type ObjectAccessCheck struct{}
+
+type ObjectAccessCheckResult struct {
+	Provider string                 `json:"provider"`
+	Action   string                 `json:"action"`
+	Bucket   string                 `json:"bucket"`
+	Key      string                 `json:"key,omitempty"`
+	Steps    []objectAccessStepJSON `json:"steps,omitempty"`
+	Message  string                 `json:"message,omitempty"`
+	Status   string                 `json:"status"`
+	Error    string                 `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
+}
+
+// objectAccessStepJSON is one data-plane request. Time is taken just before
+// the request was sent; URL is the pre-signed URL without its signature.
+type objectAccessStepJSON struct {
+	Step       string `json:"step"`
+	Time       string `json:"time"`
+	RequestID  string `json:"request_id,omitempty"`
+	StatusCode int    `json:"http_status,omitempty"`
+	URL        string `json:"url,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type objectAccessAction struct {
+	Action  string
+	Bucket  string
+	Key     string
+	Presign bool
+}
+
+// canaryPrefix keeps canary objects together so a leftover is easy to spot
+// and log queries can filter on it.
+const canaryPrefix = "ctk-canary/"
+
+const objectStepTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+func (p ObjectAccessCheck) Run(ctx context.Context, config map[string]string) {
+	resultAny, err := p.Result(ctx, config)
+	if err != nil && resultAny == nil {
+		logger.Error(err.Error())
+		return
+	}
+	result, ok := resultAny.(ObjectAccessCheckResult)
+	if !ok {
+		logger.Error("Invalid result type")
+		return
+	}
+
+	if len(result.Steps) > 0 {
+		type stepRow struct {
+			Step      string `table:"Step"`
+			Time      string `table:"Time (UTC)"`
+			RequestID string `table:"Request ID"`
+			Status    string `table:"HTTP"`
+			Result    string `table:"Result"`
+		}
+		rows := make([]stepRow, 0, len(result.Steps))
+		for _, step := range result.Steps {
+			row := stepRow{
+				Step:      step.Step,
+				Time:      step.Time,
+				RequestID: step.RequestID,
+				Result:    "ok",
+			}
+			if step.StatusCode > 0 {
+				row.Status = fmt.Sprint(step.StatusCode)
+			}
+			if step.Error != "" {
+				row.Result = step.Error
+			}
+			rows = append(rows, row)
+		}
+		table.Output(rows)
+	}
+	if result.Status == "error" {
+		logger.Error(result.Error)
+		return
+	}
+	if result.Message != "" {
+		logger.Warning(result.Message)
+	}
+	logDetection(result.Detection)
+}
+
+func (p ObjectAccessCheck) Result(ctx context.Context, config map[string]string) (any, error) {
+	parsed, err := parseObjectAccessAction(config["metadata"])
+	if err != nil {
+		return nil, err
+	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
+
+	i, err := inventoryFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr, ok := i.Providers.(schema.ObjectAccessManager)
+	if !ok {
+		return nil, fmt.Errorf("%s does not support object-access-check", i.Providers.Name())
+	}
+
+	result := ObjectAccessCheckResult{
+		Provider: i.Providers.Name(),
+		Action:   parsed.Action,
+		Bucket:   parsed.Bucket,
+		Key:      parsed.Key,
+	}
+	if parsed.Action == "run" {
+		if result.Key, err = canaryKey(time.Now()); err != nil {
+			return nil, err
+		}
+	}
+	objectArtifact := artifact{
+		payload:  "object-access-check",
+		action:   parsed.Action,
+		resource: "object:" + result.Bucket + "/" + result.Key,
+		undo:     []string{"delete", result.Bucket, result.Key},
+	}
+
+	started := time.Now()
+	var failed error
+	step := func(action string, body []byte) (schema.ObjectAccessResult, error) {
+		record := objectAccessStepJSON{Step: action, Time: time.Now().UTC().Format(objectStepTimeLayout)}
+		out, err := mgr.ObjectAccess(ctx, action, result.Bucket, result.Key, body)
+		record.RequestID = out.RequestID
+		record.StatusCode = out.StatusCode
+		record.URL = unsignedURL(out.URL)
+		if err == nil && body == nil && action != "delete" && !bytes.Equal(out.Body, canaryBody(result.Key)) {
+			err = fmt.Errorf("read back %d bytes that differ from the canary", len(out.Body))
+		}
+		if err != nil {
+			record.Error = err.Error()
+			if failed == nil {
+				failed = fmt.Errorf("%s %s/%s: %w", action, result.Bucket, result.Key, err)
+			}
+		}
+		result.Steps = append(result.Steps, record)
+		return out, err
+	}
+
+	switch parsed.Action {
+	case "run":
+		// The canary is on the ledger from the moment it exists, so a run
+		// interrupted before the delete still leaves it for `cleanup`.
+		if _, err := step("put", canaryBody(result.Key)); err == nil {
+			recordArtifact(i.Providers, config, objectArtifact)
+			step("get", nil)
+			if parsed.Presign {
+				step("presign", nil)
+			}
+			if _, err := step("delete", nil); err == nil {
+				resolveArtifact(ctx, i.Providers, config, objectArtifact)
+			}
+		}
+	case "delete":
+		if _, err := step("delete", nil); err == nil {
+			resolveArtifact(ctx, i.Providers, config, objectArtifact)
+		}
+	}
+	if failed != nil {
+		result.Status = "error"
+		result.Error = failed.Error()
+		return result, NewResultError(result, 4, failed)
+	}
+
+	result.Status = "success"
+	result.Message = fmt.Sprintf("%d requests on %s/%s; look them up by request ID in the data-event or access logs", len(result.Steps), result.Bucket, result.Key)
+	result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: objectAccessAPIs, resources: []string{result.Key}})
+	return result, nil
+}
+
+func (p ObjectAccessCheck) Desc() string {
+	return "Write, read back and delete a canary object in an authorized bucket to validate data-plane audit logging coverage."
+}
+
+func (p ObjectAccessCheck) Capability() string {
+	return "object-access"
+}
+
+func (p ObjectAccessCheck) Help() HelpDoc {
+	return HelpDoc{
+		MetadataSyntax: []string{
+			"set metadata run <bucket> [presign]",
+			"set metadata delete <bucket> <key>",
+			"`run` writes a uniquely named object under `" + canaryPrefix + "`, reads it back, optionally reads it through a pre-signed URL, and deletes it.",
+			"Each step reports its request ID and start time so the matching data-event or access-log records can be found.",
+			"`delete` only accepts canary keys as `run` names them: `" + canaryPrefix + "<time>-<suffix>.txt`.",
+		},
+		MetadataExamples: []string{
+			"set metadata run ctk-validation-logs",
+			"set metadata run ctk-validation-logs presign",
+			"set metadata delete ctk-validation-logs ctk-canary/20260420T101500Z-3f9a1c2e.txt",
+		},
+		MetadataSuggestions: []Suggestion{
+			{Text: "run <bucket>", Description: "write, read back and delete a canary object"},
+			{Text: "run <bucket> presign", Description: "also read the canary anonymously through a pre-signed URL"},
+			{Text: "delete <bucket> <key>", Description: "remove a canary object left by an interrupted run"},
+		},
+		SafetyNotes: []string{
+			"Data-plane requests are only logged where data events, access logging or Data Access audit logs are enabled on the bucket.",
+			"Every canary is recorded for `cleanup` once written and resolved when its delete succeeds; the pre-signed URL expires after five minutes.",
+		},
+	}
+}
+
+func (p ObjectAccessCheck) Sensitivity(metadata string) Sensitivity {
+	data := argparse.Split(metadata)
+	if len(data) == 0 {
+		return Sensitivity{}
+	}
+	switch data[0] {
+	case "run", "delete":
+	default:
+		return Sensitivity{}
+	}
+	resource := ""
+	if len(data) >= 2 {
+		resource = data[1]
+	}
+	return Sensitivity{
+		Level:      "destructive",
+		ConfirmKey: "object-access-check." + data[0],
+		Resource:   resource,
+	}
+}
+
+func parseObjectAccessAction(metadata string) (objectAccessAction, error) {
+	data := argparse.Split(metadata)
+	if len(data) == 0 {
+		return objectAccessAction{}, errors.New("invalid metadata format: expected 'run <bucket> [presign]' or 'delete <bucket> <key>'")
+	}
+	action := objectAccessAction{Action: strings.ToLower(data[0])}
+	switch action.Action {
+	case "run":
+		if len(data) < 2 || len(data) > 3 {
+			return objectAccessAction{}, errors.New("invalid metadata format: expected 'run <bucket> [presign]'")
+		}
+		action.Bucket = data[1]
+		if len(data) == 3 {
+			if !strings.EqualFold(data[2], "presign") {
+				return objectAccessAction{}, fmt.Errorf("unsupported run option %q: expected presign", data[2])
+			}
+			action.Presign = true
+		}
+	case "delete":
+		if len(data) != 3 {
+			return objectAccessAction{}, errors.New("invalid metadata format: expected 'delete <bucket> <key>'")
+		}
+		action.Bucket = data[1]
+		action.Key = data[2]
+		if !canaryKeyPattern.MatchString(action.Key) {
+			return objectAccessAction{}, fmt.Errorf("refusing to delete %q: only canary objects named like %s<time>-<suffix>.txt can be deleted", action.Key, canaryPrefix)
+		}
+	default:
+		return objectAccessAction{}, fmt.Errorf("unsupported action %q: expected run / delete", data[0])
+	}
+	return action, nil
+}
+
+// canaryKey names a canary object after the run time plus a random suffix,
+// so concurrent runs never collide and log records sort by run.
+func canaryKey(now time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generate canary key: %w", err)
+	}
+	return canaryPrefix + now.UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix) + ".txt", nil
+}
+
+// canaryKeyPattern matches exactly the keys canaryKey generates, so delete
+// cannot be pointed at another object through "..", extra path segments or
+// a lookalike name under the canary prefix.
+var canaryKeyPattern = regexp.MustCompile(`^` + regexp.QuoteMeta(canaryPrefix) + `[0-9]{8}T[0-9]{6}Z-[0-9a-f]{8}\.txt$`)
+
+func canaryBody(key string) []byte {
+	return []byte("cloudtoolkit object-access-check canary " + key + "\n")
+}
+
+// unsignedURL drops the query of a pre-signed URL, which carries the
+// signature, so results can be shared.
+func unsignedURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = ""
+	return u.String()
+}
+
+func init() {
+	registerPayload("object-access-check", ObjectAccessCheck{})
+}
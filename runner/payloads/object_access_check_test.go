@@ -0,0 +1,69 @@
+package payloads
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseObjectAccessAction(t *testing.T) {
+	tests := []struct {
+		metadata string
+		want     objectAccessAction
+		wantErr  string
+	}{
+		{metadata: "run logs", want: objectAccessAction{Action: "run", Bucket: "logs"}},
+		{metadata: "RUN logs PreSign", want: objectAccessAction{Action: "run", Bucket: "logs", Presign: true}},
+		{metadata: "delete logs ctk-canary/20260420T101500Z-3f9a1c2e.txt", want: objectAccessAction{Action: "delete", Bucket: "logs", Key: "ctk-canary/20260420T101500Z-3f9a1c2e.txt"}},
+		{metadata: "delete logs backups/db.sql", wantErr: "only canary objects"},
+		{metadata: "delete logs ctk-canary", wantErr: "only canary objects"},
+		{metadata: "delete logs", wantErr: "invalid metadata format"},
+		{metadata: "run logs upload", wantErr: "unsupported run option"},
+		{metadata: "run", wantErr: "invalid metadata format"},
+		{metadata: "get logs key", wantErr: "unsupported action"},
+		{metadata: "", wantErr: "invalid metadata format"},
+	}
+	for _, tt := range tests {
+		got, err := parseObjectAccessAction(tt.metadata)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseObjectAccessAction(%q) error = %v, want %q", tt.metadata, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseObjectAccessAction(%q): %v", tt.metadata, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseObjectAccessAction(%q) = %+v, want %+v", tt.metadata, got, tt.want)
+		}
+	}
+}
+
+func TestCanaryKeyIsDeletable(t *testing.T) {
+	key, err := canaryKey(time.Date(2026, 4, 20, 10, 15, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("canaryKey: %v", err)
+	}
+	if !strings.HasPrefix(key, canaryPrefix+"20260420T101500Z-") {
+		t.Fatalf("unexpected key %q", key)
+	}
+	if _, err := parseObjectAccessAction("delete logs " + key); err != nil {
+		t.Fatalf("generated key rejected by delete: %v", err)
+	}
+	for _, bad := range []string{
+		"ctk-canary/../anything",
+		"ctk-canary/../20260420T101500Z-3f9a1c2e.txt",
+		"ctk-canary//20260420T101500Z-3f9a1c2e.txt",
+		"ctk-canary/sub/20260420T101500Z-3f9a1c2e.txt",
+		"ctk-canary/20260420T101500Z-3f9a1c2e.txt/..",
+		"ctk-canary/20260420T101500Z-3F9A1C2E.txt",
+		"ctk-canary/",
+		"logs/20260420T101500Z-3f9a1c2e.txt",
+	} {
+		if _, err := parseObjectAccessAction("delete logs " + bad); err == nil {
+			t.Errorf("delete accepted non-canary key %q", bad)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -23,6 +24,8 @@ type RDSAccountCheckResult struct {
 	Message    string `json:"message,omitempty"`
 	Status     string `json:"status"`
 	Error      string `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
 }
 
 type rdsAction struct {
@@ -62,6 +65,7 @@ func (p RDSAccountCheck) Run(ctx context.Context, config map[string]string) {
 	if result.Message != "" {
 		logger.Warning(result.Message)
 	}
+	logDetection(result.Detection)
 }
 
 func (p RDSAccountCheck) Result(ctx context.Context, config map[string]string) (any, error) {
@@ -69,6 +73,9 @@ func (p RDSAccountCheck) Result(ctx context.Context, config map[string]string) (
 	if err != nil {
 		return nil, err
 	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
 
 	i, err := inventoryFromConfig(config)
 	if err != nil {
@@ -79,6 +86,8 @@ func (p RDSAccountCheck) Result(ctx context.Context, config map[string]string) (
 		return nil, fmt.Errorf("%s does not support rds-account-check", i.Providers.Name())
 	}
 
+	ctx = withCachedInventory(ctx, i.Providers, config)
+	started := time.Now()
 	dbResult, err := mgr.DBManagement(ctx, parsed.Action, parsed.InstanceID)
 	result := RDSAccountCheckResult{
 		Provider:   i.Providers.Name(),
@@ -95,6 +104,16 @@ func (p RDSAccountCheck) Result(ctx context.Context, config map[string]string) (
 		return result, NewResultError(result, 4, err)
 	}
 	result.Status = "success"
+	accountArtifact := artifact{payload: "rds-account-check", action: parsed.Action, resource: "db-account:" + parsed.InstanceID}
+	switch parsed.Action {
+	case "useradd":
+		accountArtifact.undo = []string{"userdel", parsed.InstanceID}
+		recordArtifact(i.Providers, config, accountArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: dbAccountCreateAPIs, shared: dbAccountSharedAPIs, resources: []string{parsed.InstanceID, result.Username}})
+	case "userdel":
+		resolveArtifact(ctx, i.Providers, config, accountArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: dbAccountDeleteAPIs, shared: dbAccountSharedAPIs, resources: []string{parsed.InstanceID, result.Username}})
+	}
 	return result, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/404tk/cloudtoolkit/pkg/schema"
 	"github.com/404tk/cloudtoolkit/utils/argparse"
@@ -24,6 +25,8 @@ type RoleBindingCheckResult struct {
 	Message      string                `json:"message,omitempty"`
 	Status       string                `json:"status"`
 	Error        string                `json:"error,omitempty"`
+
+	Detection *DetectionResult `json:"detection,omitempty"`
 }
 
 type roleBindingRowJSON struct {
@@ -78,6 +81,7 @@ func (p RoleBindingCheck) Run(ctx context.Context, config map[string]string) {
 	if result.Message != "" {
 		logger.Warning(result.Message)
 	}
+	logDetection(result.Detection)
 }
 
 func (p RoleBindingCheck) Result(ctx context.Context, config map[string]string) (any, error) {
@@ -85,6 +89,9 @@ func (p RoleBindingCheck) Result(ctx context.Context, config map[string]string)
 	if err != nil {
 		return nil, err
 	}
+	if _, err := detectionWindow(config); err != nil {
+		return nil, err
+	}
 
 	i, err := inventoryFromConfig(config)
 	if err != nil {
@@ -96,6 +103,7 @@ func (p RoleBindingCheck) Result(ctx context.Context, config map[string]string)
 		return nil, fmt.Errorf("%s does not support role-binding-check", i.Providers.Name())
 	}
 
+	started := time.Now()
 	bindingResult, err := mgr.RoleBinding(ctx, parsed.Action, parsed.Principal, parsed.Role, parsed.Scope)
 
 	result := RoleBindingCheckResult{
@@ -126,6 +134,23 @@ func (p RoleBindingCheck) Result(ctx context.Context, config map[string]string)
 		})
 	}
 	result.Status = "success"
+	bindingArtifact := artifact{
+		payload:  "role-binding-check",
+		action:   parsed.Action,
+		resource: "binding:" + result.Principal + "|" + result.Role + "|" + result.Scope,
+	}
+	switch parsed.Action {
+	case "add":
+		bindingArtifact.undo = []string{"del", result.Principal, result.Role}
+		if result.Scope != "" {
+			bindingArtifact.undo = append(bindingArtifact.undo, result.Scope)
+		}
+		recordArtifact(i.Providers, config, bindingArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: bindingCreateAPIs, shared: bindingSharedAPIs, resources: bindingResources(result)})
+	case "del":
+		resolveArtifact(ctx, i.Providers, config, bindingArtifact)
+		result.Detection = confirmDetection(ctx, i.Providers, config, started, detectionProbe{apis: bindingDeleteAPIs, shared: bindingSharedAPIs, resources: bindingResources(result)})
+	}
 	return result, nil
 }
 
@@ -189,6 +214,13 @@ func (p RoleBindingCheck) Sensitivity(metadata string) Sensitivity {
 	}
 }
 
+// bindingResources lists the identifiers a role-binding audit event may
+// carry: CloudTrail and Cloud Logging name the principal or policy, while the
+// Azure Activity Log only names the assignment under its scope.
+func bindingResources(result RoleBindingCheckResult) []string {
+	return []string{result.Principal, result.AssignmentID, result.Scope}
+}
+
 func parseRoleBindingAction(metadata string) (roleBindingAction, error) {
 	data := argparse.Split(metadata)
 	if len(data) == 0 {
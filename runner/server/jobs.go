@@ -0,0 +1,171 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Job states.
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// maxFinishedJobs bounds memory: once exceeded, the oldest finished jobs are
+// forgotten. Queued and running jobs are never dropped.
+const maxFinishedJobs = 500
+
+// JobRequest is the body of POST /v1/jobs. Credentials come from the cached
+// Profile (session note or UUID) and/or Options, which also carry region and
+// other provider options; Options win. Approval must equal the payload's
+// confirm key (e.g. "iam-user-check.add") for sensitive actions.
+type JobRequest struct {
+	Provider string            `json:"provider"`
+	Profile  string            `json:"profile,omitempty"`
+	Options  map[string]string `json:"options,omitempty"`
+	Payload  string            `json:"payload"`
+	Metadata string            `json:"metadata,omitempty"`
+	Approval string            `json:"approval,omitempty"`
+	Timeout  string            `json:"timeout,omitempty"`
+}
+
+// Job is one submitted payload run. Result is the payload's structured
+// result and is only included when a single job is fetched.
+type Job struct {
+	ID         string     `json:"id"`
+	Provider   string     `json:"provider"`
+	Payload    string     `json:"payload"`
+	Metadata   string     `json:"metadata,omitempty"`
+	Status     string     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Result     any        `json:"result,omitempty"`
+
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+// Finished reports whether the job reached a terminal state.
+func (j *Job) Finished() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// jobStore keeps jobs in memory in submission order. Every accessor returns
+// copies so handlers never race with the running job.
+type jobStore struct {
+	mu    sync.Mutex
+	jobs  map[string]*Job
+	order []string
+}
+
+func newJobStore() *jobStore {
+	return &jobStore{jobs: make(map[string]*Job)}
+}
+
+func (s *jobStore) add(j *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[j.ID] = j
+	s.order = append(s.order, j.ID)
+	s.prune()
+}
+
+// prune drops the oldest finished jobs beyond maxFinishedJobs. Callers hold mu.
+func (s *jobStore) prune() {
+	finished := 0
+	for _, id := range s.order {
+		if s.jobs[id].Finished() {
+			finished++
+		}
+	}
+	if finished <= maxFinishedJobs {
+		return
+	}
+	kept := s.order[:0]
+	for _, id := range s.order {
+		if finished > maxFinishedJobs && s.jobs[id].Finished() {
+			delete(s.jobs, id)
+			finished--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	s.order = kept
+}
+
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *j, true
+}
+
+// list returns the jobs without results, newest first, optionally filtered
+// by status.
+func (s *jobStore) list(status string) []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]Job, 0, len(s.order))
+	for _, id := range s.order {
+		j := *s.jobs[id]
+		if status != "" && j.Status != status {
+			continue
+		}
+		j.Result = nil
+		out = append(out, j)
+	}
+	sort.SliceStable(out, func(a, b int) bool {
+		return out[a].CreatedAt.After(out[b].CreatedAt)
+	})
+	return out
+}
+
+func (s *jobStore) update(id string, fn func(*Job)) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	fn(j)
+	if j.Finished() {
+		s.prune()
+	}
+	return *j, true
+}
+
+// cancelAll cancels every job that has not finished; used on shutdown.
+func (s *jobStore) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, j := range s.jobs {
+		if !j.Finished() && j.cancel != nil {
+			j.cancelled = true
+			j.cancel()
+		}
+	}
+}
+
+func newJobID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return hex.EncodeToString([]byte(time.Now().Format("150405.000000")))
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,342 @@
+// Package server exposes validation payloads over a local HTTP/JSON API.
+// Jobs run asynchronously through the same payloads.ResultProducer path as
+// the headless runner; each job gets its own env.Env on its context, so
+// concurrent jobs never share or mutate the process-wide env.
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/providers/registry"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/pkg/runtime/telemetry"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+	"github.com/404tk/cloudtoolkit/utils"
+	"github.com/404tk/cloudtoolkit/utils/logger"
+)
+
+// maxRequestBody caps a job submission.
+const maxRequestBody = 1 << 20
+
+// Server handles the job API. Token is required on every request as
+// `Authorization: Bearer <token>`.
+type Server struct {
+	Token string
+	// Profile resolves a cached credential profile to provider options.
+	Profile func(name string) (map[string]string, error)
+	// Env returns a fresh env for one job. It must not return a shared
+	// pointer that other jobs may mutate.
+	Env func() *env.Env
+
+	jobs *jobStore
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+type apiError struct {
+	Error      string `json:"error"`
+	Code       string `json:"code,omitempty"`
+	ConfirmKey string `json:"confirm_key,omitempty"`
+}
+
+// Handler returns the routed, authenticated API:
+//
+//	POST /v1/jobs               submit a job (202)
+//	GET  /v1/jobs[?status=...]  list jobs, newest first, without results
+//	GET  /v1/jobs/{id}          job status and result
+//	POST /v1/jobs/{id}/cancel   cancel a queued or running job
+//	GET  /v1/payloads           payload names, capabilities and descriptions
+//	GET  /metrics               provider API call metrics, OpenMetrics text
+func (s *Server) Handler() http.Handler {
+	s.init()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/jobs", s.submit)
+	mux.HandleFunc("GET /v1/jobs", s.list)
+	mux.HandleFunc("GET /v1/jobs/{id}", s.get)
+	mux.HandleFunc("POST /v1/jobs/{id}/cancel", s.cancel)
+	mux.HandleFunc("GET /v1/payloads", s.payloads)
+	mux.Handle("GET /metrics", telemetry.Handler())
+	return s.authenticate(mux)
+}
+
+// Shutdown cancels outstanding jobs and waits for them to return or ctx to
+// expire.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.init()
+	s.jobs.cancelAll()
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Server) init() {
+	s.once.Do(func() {
+		s.jobs = newJobStore()
+	})
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ctk"`)
+			writeError(w, http.StatusUnauthorized, apiError{Error: "missing or invalid bearer token", Code: "unauthorized"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) submit(w http.ResponseWriter, r *http.Request) {
+	var req JobRequest
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, apiError{Error: "invalid job request: " + err.Error(), Code: "bad_request"})
+		return
+	}
+	producer, config, timeout, apiErr, status := s.prepare(req)
+	if apiErr != nil {
+		writeError(w, status, *apiErr)
+		return
+	}
+
+	jobEnv := s.newEnv()
+	if timeout <= 0 {
+		timeout = jobEnv.RunTimeout
+	}
+	ctx, cancel := context.WithTimeout(env.With(context.Background(), jobEnv), timeout)
+	job := &Job{
+		ID:        newJobID(),
+		Provider:  config[utils.Provider],
+		Payload:   config[utils.Payload],
+		Metadata:  config[utils.Metadata],
+		Status:    StatusQueued,
+		CreatedAt: time.Now().UTC(),
+		cancel:    cancel,
+	}
+	s.jobs.add(job)
+	snapshot, _ := s.jobs.get(job.ID)
+
+	s.wg.Add(1)
+	go s.run(ctx, cancel, job.ID, producer, config)
+
+	logger.Info(fmt.Sprintf("Job %s queued: %s %s", job.ID, job.Provider, job.Payload))
+	w.Header().Set("Location", "/v1/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, snapshot)
+}
+
+// prepare validates a request the way the headless runner does and builds
+// the payload config: provider defaults, then the profile, then Options.
+func (s *Server) prepare(req JobRequest) (payloads.ResultProducer, map[string]string, time.Duration, *apiError, int) {
+	bad := func(code, format string, args ...any) (payloads.ResultProducer, map[string]string, time.Duration, *apiError, int) {
+		return nil, nil, 0, &apiError{Error: fmt.Sprintf(format, args...), Code: code}, http.StatusBadRequest
+	}
+
+	provider := strings.TrimSpace(req.Provider)
+	var profile map[string]string
+	if name := strings.TrimSpace(req.Profile); name != "" {
+		if s.Profile == nil {
+			return bad("bad_request", "credential profiles are not available")
+		}
+		var err error
+		if profile, err = s.Profile(name); err != nil {
+			return bad("bad_request", "profile %s: %v", name, err)
+		}
+		if provider == "" {
+			provider = strings.TrimSpace(profile[utils.Provider])
+		}
+		if p := strings.TrimSpace(profile[utils.Provider]); p != "" && p != provider {
+			return bad("bad_request", "provider mismatch: job selected %s but profile is for %s", provider, p)
+		}
+	}
+	if provider == "" {
+		return bad("bad_request", "provider is required unless supplied by the profile")
+	}
+	config, ok := registry.DefaultConfig(provider)
+	if !ok {
+		return bad("unsupported", "unsupported provider: %s", provider)
+	}
+
+	payload, name, ok := payloads.Lookup(req.Payload)
+	if !ok {
+		return bad("unsupported", "unsupported payload: %s", req.Payload)
+	}
+	producer, ok := payload.(payloads.ResultProducer)
+	if !ok {
+		return bad("unsupported", "payload %s has no structured result", name)
+	}
+	if capability := payloads.PayloadCapability(name); capability != "" && !registry.SupportsCapability(provider, capability) {
+		return bad("unsupported", "%s does not support %s", provider, name)
+	}
+
+	var timeout time.Duration
+	if v := strings.TrimSpace(req.Timeout); v != "" {
+		var err error
+		if timeout, err = time.ParseDuration(v); err != nil || timeout <= 0 {
+			return bad("bad_request", "invalid timeout %q", v)
+		}
+	}
+	if _, err := payloads.ParseDetectionWindow(req.Options[utils.Detect]); err != nil {
+		return bad("bad_request", "%v", err)
+	}
+
+	for _, layer := range []map[string]string{profile, req.Options} {
+		for key, value := range layer {
+			if strings.TrimSpace(value) != "" {
+				config[key] = value
+			}
+		}
+	}
+	config[utils.Provider] = provider
+	config[utils.Payload] = name
+	config[utils.Metadata] = strings.TrimSpace(req.Metadata)
+	delete(config, utils.Format)
+
+	sensitivity := payloads.DescribeSensitivity(name, config[utils.Metadata])
+	if sensitivity.RequiresConfirmation() && strings.TrimSpace(req.Approval) != sensitivity.ConfirmKey {
+		return nil, nil, 0, &apiError{
+			Error:      fmt.Sprintf("sensitive action requires approval %q", sensitivity.ConfirmKey),
+			Code:       "approval_required",
+			ConfirmKey: sensitivity.ConfirmKey,
+		}, http.StatusForbidden
+	}
+	return producer, config, timeout, nil, 0
+}
+
+func (s *Server) run(ctx context.Context, cancel context.CancelFunc, id string, producer payloads.ResultProducer, config map[string]string) {
+	defer s.wg.Done()
+	defer cancel()
+
+	if ctx.Err() != nil {
+		s.finish(id, ctx, nil, ctx.Err())
+		return
+	}
+	started := time.Now().UTC()
+	s.jobs.update(id, func(j *Job) {
+		j.Status = StatusRunning
+		j.StartedAt = &started
+	})
+	result, err := producer.Result(ctx, config)
+	s.finish(id, ctx, result, err)
+}
+
+func (s *Server) finish(id string, ctx context.Context, result any, err error) {
+	finished := time.Now().UTC()
+	job, _ := s.jobs.update(id, func(j *Job) {
+		j.FinishedAt = &finished
+		j.Result = result
+		switch {
+		case j.cancelled:
+			j.Status = StatusCancelled
+			j.Error = context.Canceled.Error()
+		case err != nil:
+			j.Status = StatusFailed
+			j.Error = err.Error()
+			if resultErr, ok := err.(payloads.ResultError); ok {
+				j.Result = resultErr.ResultPayload()
+			}
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				j.Error = "job timed out: " + j.Error
+			}
+		default:
+			j.Status = StatusSucceeded
+		}
+	})
+	logger.Info(fmt.Sprintf("Job %s %s", id, job.Status))
+}
+
+func (s *Server) list(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"jobs": s.jobs.list(r.URL.Query().Get("status"))})
+}
+
+func (s *Server) get(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, apiError{Error: "job not found", Code: "not_found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (s *Server) cancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	var cancel context.CancelFunc
+	job, ok := s.jobs.update(id, func(j *Job) {
+		if j.Finished() {
+			return
+		}
+		j.cancelled = true
+		cancel = j.cancel
+	})
+	if !ok {
+		writeError(w, http.StatusNotFound, apiError{Error: "job not found", Code: "not_found"})
+		return
+	}
+	if job.Finished() {
+		writeError(w, http.StatusConflict, apiError{Error: "job already " + job.Status, Code: "finished"})
+		return
+	}
+	if cancel != nil {
+		cancel()
+	}
+	job.Result = nil
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (s *Server) payloads(w http.ResponseWriter, _ *http.Request) {
+	type payloadInfo struct {
+		Name        string `json:"name"`
+		Capability  string `json:"capability,omitempty"`
+		Description string `json:"description"`
+	}
+	entries := payloads.Visible()
+	out := make([]payloadInfo, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, payloadInfo{
+			Name:        entry.Name,
+			Capability:  payloads.PayloadCapability(entry.Name),
+			Description: entry.Payload.Desc(),
+		})
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"payloads": out})
+}
+
+func (s *Server) newEnv() *env.Env {
+	e := env.Default()
+	if s.Env != nil {
+		if fresh := s.Env(); fresh != nil {
+			e = fresh.Clone()
+		}
+	}
+	e.ConfineOutput = true
+	return e
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, e apiError) {
+	writeJSON(w, status, e)
+}
@@ -0,0 +1,204 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/runtime/env"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+const testToken = "s3cret"
+
+// testPayload is a ResultProducer whose Result is supplied by the test.
+type testPayload struct {
+	result func(ctx context.Context, config map[string]string) (any, error)
+}
+
+func (testPayload) Run(context.Context, map[string]string) {}
+func (testPayload) Desc() string                           { return "test payload" }
+
+func (p testPayload) Result(ctx context.Context, config map[string]string) (any, error) {
+	return p.result(ctx, config)
+}
+
+func registerTestPayload(t *testing.T, name string, fn func(context.Context, map[string]string) (any, error)) {
+	t.Helper()
+	payloads.Payloads[name] = testPayload{result: fn}
+	t.Cleanup(func() { delete(payloads.Payloads, name) })
+}
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	s := &Server{Token: testToken, Env: func() *env.Env { return &env.Env{RunTimeout: time.Minute} }}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown: %v", err)
+		}
+		ts.Close()
+	})
+	return ts
+}
+
+func call(t *testing.T, ts *httptest.Server, method, path, token string, body any, out any) *http.Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		reader = strings.NewReader(string(data))
+	}
+	req, err := http.NewRequest(method, ts.URL+path, reader)
+	if err != nil {
+		t.Fatalf("new request: %v", err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("decode %s %s: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+// waitFor polls the job until cond holds or the test times out.
+func waitFor(t *testing.T, ts *httptest.Server, id string, cond func(Job) bool) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var job Job
+		call(t, ts, http.MethodGet, "/v1/jobs/"+id, testToken, nil, &job)
+		if cond(job) {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("job %s stuck in %s", id, job.Status)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRejectsMissingOrInvalidToken(t *testing.T) {
+	ts := newTestServer(t)
+	for _, token := range []string{"", "wrong"} {
+		var apiErr apiError
+		resp := call(t, ts, http.MethodGet, "/v1/jobs", token, nil, &apiErr)
+		if resp.StatusCode != http.StatusUnauthorized || apiErr.Code != "unauthorized" {
+			t.Fatalf("token %q: status %d, %+v", token, resp.StatusCode, apiErr)
+		}
+		if resp.Header.Get("WWW-Authenticate") == "" {
+			t.Fatalf("token %q: missing WWW-Authenticate", token)
+		}
+	}
+}
+
+func TestSensitiveJobRequiresApproval(t *testing.T) {
+	ts := newTestServer(t)
+	req := JobRequest{Provider: "aws", Payload: "iam-user-check", Metadata: "add ctkguest Passw0rd!"}
+
+	var apiErr apiError
+	resp := call(t, ts, http.MethodPost, "/v1/jobs", testToken, req, &apiErr)
+	if resp.StatusCode != http.StatusForbidden || apiErr.Code != "approval_required" || apiErr.ConfirmKey != "iam-user-check.add" {
+		t.Fatalf("status %d, %+v", resp.StatusCode, apiErr)
+	}
+	req.Approval = "iam-user-check.del"
+	if resp := call(t, ts, http.MethodPost, "/v1/jobs", testToken, req, &apiErr); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("wrong approval accepted: status %d", resp.StatusCode)
+	}
+
+	var list struct{ Jobs []Job }
+	call(t, ts, http.MethodGet, "/v1/jobs", testToken, nil, &list)
+	if len(list.Jobs) != 0 {
+		t.Fatalf("rejected submissions created jobs: %+v", list.Jobs)
+	}
+}
+
+func TestSubmitAndPollUntilSucceeded(t *testing.T) {
+	registerTestPayload(t, "server-test-ok", func(ctx context.Context, config map[string]string) (any, error) {
+		if !env.From(ctx).ConfineOutput {
+			t.Error("job env does not confine output")
+		}
+		return map[string]string{"region": config["region"]}, nil
+	})
+	ts := newTestServer(t)
+
+	var job Job
+	resp := call(t, ts, http.MethodPost, "/v1/jobs", testToken, JobRequest{
+		Provider: "aws",
+		Payload:  "server-test-ok",
+		Options:  map[string]string{"region": "us-west-2"},
+	}, &job)
+	if resp.StatusCode != http.StatusAccepted || job.ID == "" {
+		t.Fatalf("submit: status %d, %+v", resp.StatusCode, job)
+	}
+	if got := resp.Header.Get("Location"); got != "/v1/jobs/"+job.ID {
+		t.Fatalf("Location = %q", got)
+	}
+
+	done := waitFor(t, ts, job.ID, func(j Job) bool { return j.Finished() })
+	if done.Status != StatusSucceeded || done.StartedAt == nil || done.FinishedAt == nil {
+		t.Fatalf("finished job = %+v", done)
+	}
+	if result, _ := done.Result.(map[string]any); result["region"] != "us-west-2" {
+		t.Fatalf("result = %#v", done.Result)
+	}
+
+	var list struct{ Jobs []Job }
+	call(t, ts, http.MethodGet, "/v1/jobs?status=succeeded", testToken, nil, &list)
+	if len(list.Jobs) != 1 || list.Jobs[0].ID != job.ID || list.Jobs[0].Result != nil {
+		t.Fatalf("list = %+v", list.Jobs)
+	}
+}
+
+func TestCancelRunningJob(t *testing.T) {
+	started := make(chan struct{})
+	registerTestPayload(t, "server-test-block", func(ctx context.Context, _ map[string]string) (any, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ts := newTestServer(t)
+
+	var job Job
+	call(t, ts, http.MethodPost, "/v1/jobs", testToken, JobRequest{Provider: "aws", Payload: "server-test-block"}, &job)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job never started")
+	}
+
+	var cancelled Job
+	if resp := call(t, ts, http.MethodPost, "/v1/jobs/"+job.ID+"/cancel", testToken, nil, &cancelled); resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("cancel: status %d", resp.StatusCode)
+	}
+	done := waitFor(t, ts, job.ID, func(j Job) bool { return j.Finished() })
+	if done.Status != StatusCancelled {
+		t.Fatalf("job = %+v, want cancelled", done)
+	}
+
+	var apiErr apiError
+	if resp := call(t, ts, http.MethodPost, "/v1/jobs/"+job.ID+"/cancel", testToken, nil, &apiErr); resp.StatusCode != http.StatusConflict || apiErr.Code != "finished" {
+		t.Fatalf("second cancel: status %d, %+v", resp.StatusCode, apiErr)
+	}
+	if resp := call(t, ts, http.MethodPost, "/v1/jobs/missing/cancel", testToken, nil, &apiErr); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("cancel unknown job: status %d", resp.StatusCode)
+	}
+}
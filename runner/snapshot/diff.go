@@ -0,0 +1,242 @@
+package snapshot
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	ActionAdded   = "added"
+	ActionRemoved = "removed"
+	ActionChanged = "changed"
+)
+
+// Asset kinds reported by Compare, in output order.
+const (
+	KindHost         = "host"
+	KindBucket       = "bucket"
+	KindUser         = "user"
+	KindDatabase     = "database"
+	KindDomainRecord = "domain-record"
+	KindLog          = "log"
+	KindSecGroup     = "security-group"
+	KindFirewallRule = "firewall-rule"
+)
+
+var kindOrder = map[string]int{
+	KindHost:         0,
+	KindBucket:       1,
+	KindUser:         2,
+	KindDatabase:     3,
+	KindDomainRecord: 4,
+	KindLog:          5,
+	KindSecGroup:     6,
+	KindFirewallRule: 7,
+}
+
+type Change struct {
+	Kind   string `json:"kind" table:"Type"`
+	Key    string `json:"key" table:"Key"`
+	Action string `json:"action" table:"Change"`
+	Detail string `json:"detail,omitempty" table:"Detail"`
+}
+
+// Duplicate is a key shared by several assets of one snapshot, usually a
+// provider listing the same resource twice or an ID missing from the key.
+// Each copy is still diffed, under Key suffixed with "#2", "#3", ...
+type Duplicate struct {
+	Kind     string `json:"kind"`
+	Key      string `json:"key"`
+	Snapshot string `json:"snapshot"` // "older" or "newer"
+	Count    int    `json:"count"`
+}
+
+type Diff struct {
+	Provider   string      `json:"provider"`
+	From       time.Time   `json:"from"`
+	To         time.Time   `json:"to"`
+	Added      int         `json:"added"`
+	Removed    int         `json:"removed"`
+	Changed    int         `json:"changed"`
+	Changes    []Change    `json:"changes"`
+	Duplicates []Duplicate `json:"duplicates,omitempty"`
+}
+
+// Empty reports whether the two snapshots hold the same assets.
+func (d Diff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// Compare reports assets added, removed or changed between older and newer.
+// Assets are matched by a stable per-kind key (account, region and resource
+// ID where the provider exposes them), so a renamed field shows up as a
+// change while a recreated resource with a new ID shows up as removed+added.
+func Compare(older, newer Snapshot) (Diff, error) {
+	if older.Provider != newer.Provider {
+		return Diff{}, fmt.Errorf("provider mismatch: %s snapshot compared with %s snapshot", older.Provider, newer.Provider)
+	}
+	diff := Diff{
+		Provider: newer.Provider,
+		From:     older.Timestamp,
+		To:       newer.Timestamp,
+		Changes:  []Change{},
+	}
+	before, olderDups := index(older)
+	after, newerDups := index(newer)
+	diff.Duplicates = append(duplicates("older", olderDups), duplicates("newer", newerDups)...)
+	for key, prev := range before {
+		next, ok := after[key]
+		if !ok {
+			diff.Changes = append(diff.Changes, Change{Kind: key.kind, Key: key.id, Action: ActionRemoved})
+			diff.Removed++
+			continue
+		}
+		if fields := changedFields(prev, next); len(fields) > 0 {
+			diff.Changes = append(diff.Changes, Change{Kind: key.kind, Key: key.id, Action: ActionChanged, Detail: strings.Join(fields, "; ")})
+			diff.Changed++
+		}
+	}
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			diff.Changes = append(diff.Changes, Change{Kind: key.kind, Key: key.id, Action: ActionAdded})
+			diff.Added++
+		}
+	}
+	sort.Slice(diff.Changes, func(i, j int) bool {
+		a, b := diff.Changes[i], diff.Changes[j]
+		if a.Kind != b.Kind {
+			return kindOrder[a.Kind] < kindOrder[b.Kind]
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.Action < b.Action
+	})
+	return diff, nil
+}
+
+type assetKey struct {
+	kind string
+	id   string
+}
+
+// index keys every diffable asset. Balances are left out: spend moves on
+// every run and is not inventory drift. A key seen more than once keeps each
+// copy under an ordinal suffix, in snapshot order, and is counted in the
+// returned map of duplicates.
+func index(s Snapshot) (map[assetKey]any, map[assetKey]int) {
+	out := make(map[assetKey]any)
+	seen := make(map[assetKey]int)
+	put := func(kind string, value any, parts ...string) {
+		key := assetKey{kind: kind, id: joinKey(parts...)}
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key.id = fmt.Sprintf("%s#%d", key.id, n)
+		}
+		out[key] = value
+	}
+	for _, v := range s.Hosts {
+		id := v.ID
+		if id == "" {
+			id = v.HostName
+		}
+		put(KindHost, v, v.Account, v.Region, id)
+	}
+	for _, v := range s.Storages {
+		put(KindBucket, v, v.Account, v.AccountName, v.BucketName)
+	}
+	for _, v := range s.Users {
+		put(KindUser, v, v.Account, v.UserName)
+	}
+	for _, v := range s.Databases {
+		put(KindDatabase, v, v.Account, v.Region, v.InstanceId)
+	}
+	for _, domain := range s.Domains {
+		for _, record := range domain.Records {
+			put(KindDomainRecord, record, domain.Account, domain.DomainName, record.RR, record.Type, record.Value)
+		}
+	}
+	for _, v := range s.Logs {
+		put(KindLog, v, v.Account, v.Region, v.ProjectName)
+	}
+	for _, group := range s.Security {
+		for _, rule := range group.Rules {
+			put(KindFirewallRule, rule, group.Account, group.Region, group.GroupID, rule.Direction, rule.Action, rule.Protocol, rule.PortRange, rule.CIDR)
+		}
+		// Rules are diffed on their own; the group row only tracks renames
+		// and attachment drift.
+		group.Rules = nil
+		put(KindSecGroup, group, group.Account, group.Region, group.GroupID)
+	}
+	dups := make(map[assetKey]int)
+	for key, n := range seen {
+		if n > 1 {
+			dups[key] = n
+		}
+	}
+	return out, dups
+}
+
+// duplicates flattens the duplicate counts of one snapshot in output order.
+func duplicates(snapshot string, counts map[assetKey]int) []Duplicate {
+	out := make([]Duplicate, 0, len(counts))
+	for key, n := range counts {
+		out = append(out, Duplicate{Kind: key.kind, Key: key.id, Snapshot: snapshot, Count: n})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Kind != out[j].Kind {
+			return kindOrder[out[i].Kind] < kindOrder[out[j].Kind]
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// joinKey drops empty parts so single-account snapshots get short keys and
+// providers without regions (e.g. global IAM) don't grow a leading slash.
+func joinKey(parts ...string) string {
+	kept := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			kept = append(kept, part)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// changedFields lists `Field: old -> new` for every differing exported field,
+// named after the column header the asset is rendered with.
+func changedFields(prev, next any) []string {
+	a, b := reflect.ValueOf(prev), reflect.ValueOf(next)
+	if a.Type() != b.Type() || a.Kind() != reflect.Struct {
+		return nil
+	}
+	var out []string
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		x, y := a.Field(i).Interface(), b.Field(i).Interface()
+		if reflect.DeepEqual(x, y) {
+			continue
+		}
+		name := field.Tag.Get("table")
+		if name == "" {
+			name = field.Name
+		}
+		out = append(out, fmt.Sprintf("%s: %v -> %v", name, formatValue(x), formatValue(y)))
+	}
+	return out
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok && s == "" {
+		return `""`
+	}
+	return fmt.Sprint(v)
+}
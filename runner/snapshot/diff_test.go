@@ -0,0 +1,142 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+func snap(result payloads.CloudListResult) Snapshot {
+	result.Provider = "aws"
+	return New(result, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+}
+
+func TestCompare(t *testing.T) {
+	web := schema.Host{HostName: "web", ID: "i-1", State: "running", Region: "us-east-1"}
+	stopped := web
+	stopped.State = "stopped"
+	db := schema.Host{HostName: "db", ID: "i-2", Region: "us-east-1"}
+	group := schema.SecurityGroup{GroupID: "sg-1", Region: "us-east-1", Rules: []schema.FirewallRule{{Direction: "ingress", Protocol: "tcp", PortRange: "22", CIDR: "10.0.0.0/8"}}}
+	opened := group
+	opened.Rules = []schema.FirewallRule{{Direction: "ingress", Protocol: "tcp", PortRange: "22", CIDR: "0.0.0.0/0"}}
+
+	tests := []struct {
+		name   string
+		older  payloads.CloudListResult
+		newer  payloads.CloudListResult
+		want   []Change
+		counts [3]int // added, removed, changed
+	}{
+		{
+			name:  "unchanged",
+			older: payloads.CloudListResult{Hosts: []schema.Host{web, db}},
+			newer: payloads.CloudListResult{Hosts: []schema.Host{db, web}},
+			want:  []Change{},
+		},
+		{
+			name:   "added",
+			older:  payloads.CloudListResult{Hosts: []schema.Host{web}},
+			newer:  payloads.CloudListResult{Hosts: []schema.Host{web, db}},
+			want:   []Change{{Kind: KindHost, Key: "us-east-1/i-2", Action: ActionAdded}},
+			counts: [3]int{1, 0, 0},
+		},
+		{
+			name:   "removed",
+			older:  payloads.CloudListResult{Hosts: []schema.Host{web, db}, Users: []schema.User{{UserName: "alice"}}},
+			newer:  payloads.CloudListResult{Hosts: []schema.Host{web}},
+			want:   []Change{{Kind: KindHost, Key: "us-east-1/i-2", Action: ActionRemoved}, {Kind: KindUser, Key: "alice", Action: ActionRemoved}},
+			counts: [3]int{0, 2, 0},
+		},
+		{
+			name:   "changed",
+			older:  payloads.CloudListResult{Hosts: []schema.Host{web}},
+			newer:  payloads.CloudListResult{Hosts: []schema.Host{stopped}},
+			want:   []Change{{Kind: KindHost, Key: "us-east-1/i-1", Action: ActionChanged, Detail: "State: running -> stopped"}},
+			counts: [3]int{0, 0, 1},
+		},
+		{
+			name:  "firewall rules are keyed on their own",
+			older: payloads.CloudListResult{Security: []schema.SecurityGroup{group}},
+			newer: payloads.CloudListResult{Security: []schema.SecurityGroup{opened}},
+			want: []Change{
+				{Kind: KindFirewallRule, Key: "us-east-1/sg-1/ingress/tcp/22/0.0.0.0/0", Action: ActionAdded},
+				{Kind: KindFirewallRule, Key: "us-east-1/sg-1/ingress/tcp/22/10.0.0.0/8", Action: ActionRemoved},
+			},
+			counts: [3]int{1, 1, 0},
+		},
+		{
+			name:   "accounts split otherwise equal keys",
+			older:  payloads.CloudListResult{Hosts: []schema.Host{withAccount(web, "111")}},
+			newer:  payloads.CloudListResult{Hosts: []schema.Host{withAccount(web, "111"), withAccount(web, "222")}},
+			want:   []Change{{Kind: KindHost, Key: "222/us-east-1/i-1", Action: ActionAdded}},
+			counts: [3]int{1, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, err := Compare(snap(tt.older), snap(tt.newer))
+			if err != nil {
+				t.Fatalf("Compare() error = %v", err)
+			}
+			if !reflect.DeepEqual(diff.Changes, tt.want) {
+				t.Fatalf("Changes = %+v, want %+v", diff.Changes, tt.want)
+			}
+			if got := [3]int{diff.Added, diff.Removed, diff.Changed}; got != tt.counts {
+				t.Fatalf("added/removed/changed = %v, want %v", got, tt.counts)
+			}
+			if diff.Empty() != (len(tt.want) == 0) {
+				t.Fatalf("Empty() = %v with %d changes", diff.Empty(), len(tt.want))
+			}
+			if len(diff.Duplicates) != 0 {
+				t.Fatalf("unexpected duplicates: %+v", diff.Duplicates)
+			}
+		})
+	}
+}
+
+func withAccount(h schema.Host, account string) schema.Host {
+	h.Account = account
+	return h
+}
+
+func TestCompareReportsDuplicateKeys(t *testing.T) {
+	a := schema.Host{HostName: "web", ID: "i-1", State: "running", Region: "us-east-1"}
+	b := a
+	b.PrivateIpv4 = "10.0.0.2"
+	c := b
+	c.State = "stopped"
+
+	older := snap(payloads.CloudListResult{Hosts: []schema.Host{a, b}})
+	newer := snap(payloads.CloudListResult{Hosts: []schema.Host{a, c, a}})
+	diff, err := Compare(older, newer)
+	if err != nil {
+		t.Fatalf("Compare() error = %v", err)
+	}
+	wantDups := []Duplicate{
+		{Kind: KindHost, Key: "us-east-1/i-1", Snapshot: "older", Count: 2},
+		{Kind: KindHost, Key: "us-east-1/i-1", Snapshot: "newer", Count: 3},
+	}
+	if !reflect.DeepEqual(diff.Duplicates, wantDups) {
+		t.Fatalf("Duplicates = %+v, want %+v", diff.Duplicates, wantDups)
+	}
+	// No copy is dropped: the second copy changed and the third is new.
+	wantChanges := []Change{
+		{Kind: KindHost, Key: "us-east-1/i-1#2", Action: ActionChanged, Detail: "State: running -> stopped"},
+		{Kind: KindHost, Key: "us-east-1/i-1#3", Action: ActionAdded},
+	}
+	if !reflect.DeepEqual(diff.Changes, wantChanges) {
+		t.Fatalf("Changes = %+v, want %+v", diff.Changes, wantChanges)
+	}
+}
+
+func TestCompareRejectsProviderMismatch(t *testing.T) {
+	older := snap(payloads.CloudListResult{})
+	newer := snap(payloads.CloudListResult{})
+	newer.Provider = "gcp"
+	if _, err := Compare(older, newer); err == nil {
+		t.Fatal("expected a provider mismatch error")
+	}
+}
@@ -0,0 +1,120 @@
+// Package snapshot persists cloudlist results in a versioned JSON format and
+// compares two of them, so drift introduced between two validation windows
+// can be reported asset by asset.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+// Version is the snapshot format written by this build. Files without a
+// version field are plain `ls --json` output and are read as version 0.
+const Version = 1
+
+// Snapshot is a cloudlist result stamped with the provider, the accounts it
+// covers and the time it was taken. The embedded result keeps the same JSON
+// field names as `ls --json`.
+type Snapshot struct {
+	Version   int       `json:"version"`
+	Accounts  []string  `json:"accounts,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	payloads.CloudListResult
+}
+
+// New builds a snapshot of result taken at now.
+func New(result payloads.CloudListResult, now time.Time) Snapshot {
+	result.OutputFiles = nil
+	return Snapshot{
+		Version:         Version,
+		Accounts:        accountsOf(result),
+		Timestamp:       now.UTC(),
+		CloudListResult: result,
+	}
+}
+
+// Save writes s to path as indented JSON, creating parent directories.
+func Save(path string, s Snapshot) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Load reads a snapshot or a plain cloudlist JSON result from path.
+func Load(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return Snapshot{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Version > Version {
+		return Snapshot{}, fmt.Errorf("%s: snapshot version %d is newer than supported version %d", path, s.Version, Version)
+	}
+	if s.Provider == "" {
+		return Snapshot{}, fmt.Errorf("%s: not a cloudlist snapshot (missing provider)", path)
+	}
+	if len(s.Accounts) == 0 {
+		s.Accounts = accountsOf(s.CloudListResult)
+	}
+	return s, nil
+}
+
+// accountsOf lists the distinct account IDs stamped on assets, which are only
+// set by multi-account (organization) enumeration.
+func accountsOf(result payloads.CloudListResult) []string {
+	seen := make(map[string]struct{})
+	add := func(id string) {
+		if id != "" {
+			seen[id] = struct{}{}
+		}
+	}
+	for _, v := range result.Hosts {
+		add(v.Account)
+	}
+	for _, v := range result.Storages {
+		add(v.Account)
+	}
+	for _, v := range result.Users {
+		add(v.Account)
+	}
+	for _, v := range result.Databases {
+		add(v.Account)
+	}
+	for _, v := range result.Domains {
+		add(v.Account)
+	}
+	for _, v := range result.Logs {
+		add(v.Account)
+	}
+	for _, v := range result.Security {
+		add(v.Account)
+	}
+	for _, v := range result.Balances {
+		add(v.Account)
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+	out := make([]string, 0, len(seen))
+	for id := range seen {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
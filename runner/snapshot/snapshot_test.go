@@ -0,0 +1,77 @@
+package snapshot
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/404tk/cloudtoolkit/pkg/schema"
+	"github.com/404tk/cloudtoolkit/runner/payloads"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "snap.json")
+	result := payloads.CloudListResult{
+		Provider:    "aws",
+		Hosts:       []schema.Host{{ID: "i-1", Region: "us-east-1", Account: "222"}},
+		Users:       []schema.User{{UserName: "alice", Account: "111"}},
+		OutputFiles: []string{"logs/out.log"},
+	}
+	s := New(result, time.Date(2026, 1, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600)))
+	if err := Save(path, s); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.Version != Version || !got.Timestamp.Equal(s.Timestamp) || got.Timestamp.Location() != time.UTC {
+		t.Fatalf("header = %d %v", got.Version, got.Timestamp)
+	}
+	if !reflect.DeepEqual(got.Accounts, []string{"111", "222"}) {
+		t.Fatalf("Accounts = %v", got.Accounts)
+	}
+	if got.OutputFiles != nil {
+		t.Fatalf("OutputFiles persisted: %v", got.OutputFiles)
+	}
+	if !reflect.DeepEqual(got.Hosts, result.Hosts) || !reflect.DeepEqual(got.Users, result.Users) {
+		t.Fatalf("assets = %+v / %+v", got.Hosts, got.Users)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{name: "plain ls --json", data: `{"provider":"aws","hosts":[{"ID":"i-1"}]}`},
+		{name: "newer version", data: `{"version":99,"provider":"aws"}`, wantErr: "newer than supported"},
+		{name: "missing provider", data: `{"version":1}`, wantErr: "missing provider"},
+		{name: "not json", data: `hosts: []`, wantErr: "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "snap.json")
+			if err := os.WriteFile(path, []byte(tt.data), 0600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			s, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if s.Version != 0 || len(s.Hosts) != 1 {
+				t.Fatalf("Load() = %+v", s)
+			}
+		})
+	}
+}
@@ -128,3 +128,18 @@ func SplitN(s string, n int) []string {
 	}
 	return out
 }
+
+// Join is the inverse of Split: it renders argv as a metadata string,
+// single-quoting tokens that are empty or contain whitespace, quotes or
+// backslashes.
+func Join(args []string) string {
+	out := make([]string, 0, len(args))
+	for _, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t'\"\\") {
+			out = append(out, arg)
+			continue
+		}
+		out = append(out, "'"+strings.ReplaceAll(arg, "'", `'\''`)+"'")
+	}
+	return strings.Join(out, " ")
+}
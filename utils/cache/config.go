@@ -2,6 +2,7 @@ package cache
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
@@ -12,11 +13,19 @@ import (
 
 var Cfg *InitCfg
 
+// InitCfg is the on-disk credential cache. The file is either a legacy
+// plaintext JSON array or an encrypted envelope (see crypto.go). While an
+// encrypted file is locked, Creds only holds entries captured during this
+// run; they are merged into the decrypted set on Unlock.
 type InitCfg struct {
 	Path  string
 	Creds []Credential
 	mu    sync.RWMutex
 	once  sync.Once
+	key   *storeKey
+	// sealed reports that Path holds an envelope whose contents have not
+	// been decrypted into Creds.
+	sealed bool
 }
 
 // Snapshot returns a shallow copy of Creds safe for iteration outside the package.
@@ -41,54 +50,206 @@ func NewConfig() *InitCfg {
 
 func (cfg *InitCfg) ensureLoaded() {
 	cfg.once.Do(func() {
-		path := filepath.Join(userHomeDir(), ".config/cloudtoolkit/config.json")
-		cfg.Path = path
-		cfg.Creds = getCreds(path)
-		if _, err := os.Stat(path); err == nil {
-			_ = os.Chmod(path, 0600)
+		if cfg.Path == "" {
+			cfg.Path = filepath.Join(userHomeDir(), ".config/cloudtoolkit/config.json")
 		}
+		if _, err := os.Stat(cfg.Path); err == nil {
+			_ = os.Chmod(cfg.Path, 0600)
+		}
+		cfg.load(os.Getenv(PassphraseEnv))
 	})
 }
 
-func getCreds(path string) (creds []Credential) {
-	file, err := os.Open(path)
+// load reads Path once at startup. An encrypted file is opened with
+// passphrase when one is supplied and stays sealed otherwise. A plaintext
+// file is migrated to the encrypted format as soon as a passphrase is known.
+func (cfg *InitCfg) load(passphrase string) {
+	data, err := os.ReadFile(cfg.Path)
 	if err != nil {
 		return
 	}
-	defer file.Close()
+	if isEnvelope(data) {
+		cfg.sealed = true
+		if passphrase == "" {
+			return
+		}
+		if err := cfg.unlockData(data, passphrase); err != nil {
+			logger.Error(fmt.Sprintf("Unlock credential cache via %s failed: %v", PassphraseEnv, err))
+		}
+		return
+	}
 
-	err = json.NewDecoder(file).Decode(&creds)
+	creds, err := decodeCreds(data)
 	if err != nil {
 		logger.Error("Get credential info failed:", err.Error())
 		return
 	}
+	cfg.Creds = creds
+	if passphrase == "" {
+		return
+	}
+	if err := cfg.setPassphrase(passphrase); err != nil {
+		logger.Error("Encrypt credential cache failed:", err.Error())
+		return
+	}
+	logger.Info(fmt.Sprintf("Credential cache migrated to the encrypted format (%s).", cfg.Path))
+}
 
-	return
+// Locked reports whether the cache is encrypted and has not been unlocked.
+func (cfg *InitCfg) Locked() bool {
+	cfg.ensureLoaded()
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.sealed
 }
 
-func SaveFile() {
-	Cfg.ensureLoaded()
-	Cfg.mu.RLock()
-	snapshot := make([]Credential, len(Cfg.Creds))
-	copy(snapshot, Cfg.Creds)
-	path := Cfg.Path
-	Cfg.mu.RUnlock()
+// Encrypted reports whether the cache is, or will be on the next save,
+// stored as an encrypted envelope.
+func (cfg *InitCfg) Encrypted() bool {
+	cfg.ensureLoaded()
+	cfg.mu.RLock()
+	defer cfg.mu.RUnlock()
+	return cfg.sealed || cfg.key != nil
+}
 
-	data, err := json.MarshalIndent(snapshot, "", "\t")
+// Unlock decrypts the cache file with passphrase. Entries captured while
+// the cache was locked are kept and take precedence over stored ones with
+// the same UUID.
+func (cfg *InitCfg) Unlock(passphrase string) error {
+	cfg.ensureLoaded()
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	if !cfg.sealed {
+		if cfg.key == nil {
+			return ErrNotEncrypted
+		}
+		return nil
+	}
+	data, err := os.ReadFile(cfg.Path)
 	if err != nil {
-		logger.Error("Failed to marshal credentials:", err.Error())
-		return
+		return err
 	}
-	err = os.MkdirAll(filepath.Dir(path), 0700)
+	return cfg.unlockData(data, passphrase)
+}
+
+func (cfg *InitCfg) unlockData(data []byte, passphrase string) error {
+	key, plaintext, err := open(data, passphrase)
 	if err != nil {
-		logger.Error("Could not mkdir:", err.Error())
-		return
+		return err
 	}
-	err = os.WriteFile(path, data, 0600)
+	stored, err := decodeCreds(plaintext)
 	if err != nil {
+		return err
+	}
+	cfg.Creds = mergeCreds(stored, cfg.Creds)
+	cfg.key = key
+	cfg.sealed = false
+	return nil
+}
+
+// Lock writes the cache encrypted and drops the key and the decrypted
+// entries from memory. It fails on a plaintext cache; use SetPassphrase
+// first.
+func (cfg *InitCfg) Lock() error {
+	cfg.ensureLoaded()
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	if cfg.sealed {
+		return nil
+	}
+	if cfg.key == nil {
+		return ErrNotEncrypted
+	}
+	if err := cfg.write(); err != nil {
+		return err
+	}
+	cfg.Creds = nil
+	cfg.key = nil
+	cfg.sealed = true
+	return nil
+}
+
+// SetPassphrase derives a fresh key from passphrase and rewrites the cache
+// encrypted with it. It serves both for the first encryption of a
+// plaintext cache and for changing the passphrase of an unlocked one.
+func (cfg *InitCfg) SetPassphrase(passphrase string) error {
+	cfg.ensureLoaded()
+	cfg.mu.Lock()
+	defer cfg.mu.Unlock()
+	return cfg.setPassphrase(passphrase)
+}
+
+func (cfg *InitCfg) setPassphrase(passphrase string) error {
+	if cfg.sealed {
+		return ErrLocked
+	}
+	key, err := newStoreKey(passphrase)
+	if err != nil {
+		return err
+	}
+	cfg.key = key
+	return cfg.write()
+}
+
+func SaveFile() {
+	Cfg.ensureLoaded()
+	Cfg.mu.RLock()
+	defer Cfg.mu.RUnlock()
+	if Cfg.sealed {
+		if n := len(Cfg.Creds); n > 0 {
+			logger.Warning(fmt.Sprintf("Credential cache is locked, %d new session(s) were not saved. Run `sessions unlock` before exiting to keep them.", n))
+		}
+		return
+	}
+	if err := Cfg.write(); err != nil {
 		logger.Error("Failed to write the config file:", err.Error())
 	}
+}
+
+// write persists Creds to Path, sealed when a key is set. Callers hold mu.
+func (cfg *InitCfg) write() error {
+	data, err := json.MarshalIndent(cfg.Creds, "", "\t")
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+	if cfg.key != nil {
+		if data, err = seal(cfg.key, data); err != nil {
+			return fmt.Errorf("encrypt credentials: %w", err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0700); err != nil {
+		return fmt.Errorf("mkdir: %w", err)
+	}
+	// Write through a temp file so an interrupted save never leaves a
+	// truncated envelope that no passphrase can open.
+	tmp := cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, cfg.Path)
+}
+
+func decodeCreds(data []byte) ([]Credential, error) {
+	var creds []Credential
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
 
+func mergeCreds(stored, pending []Credential) []Credential {
+	index := make(map[string]int, len(stored))
+	for i, cred := range stored {
+		index[cred.UUID] = i
+	}
+	for _, cred := range pending {
+		if i, ok := index[cred.UUID]; ok {
+			stored[i] = cred
+			continue
+		}
+		stored = append(stored, cred)
+	}
+	return stored
 }
 
 func userHomeDir() string {
@@ -24,9 +24,9 @@ func (cfg *InitCfg) CredInsert(user string, provider any, data map[string]string
 	cfg.ensureLoaded()
 	providerName := data[utils.Provider]
 	accessKey := credentialKey(provider, data)
-	uuid := utils.Md5Encode(accessKey + providerName)
+	uuid := CredentialUUID(provider, data)
 
-	b, err := json.Marshal(data)
+	b, err := json.Marshal(persistable(data))
 	if err != nil {
 		logger.Error("Map to json failed:", err.Error())
 		return
@@ -52,6 +52,26 @@ func (cfg *InitCfg) CredInsert(user string, provider any, data map[string]string
 	})
 }
 
+// CredentialUUID returns the cache UUID for a provider config, the same ID
+// CredInsert stores it under, so callers can tie records to a credential
+// without keeping the secret.
+func CredentialUUID(provider any, data map[string]string) string {
+	return utils.Md5Encode(credentialKey(provider, data) + data[utils.Provider])
+}
+
+// persistable drops options that are only valid for the login that used
+// them, such as a one-time MFA code, from what is written to the cache.
+func persistable(data map[string]string) map[string]string {
+	out := make(map[string]string, len(data))
+	for k, v := range data {
+		if k == utils.AWSMFAToken {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
 func credentialKey(provider any, data map[string]string) string {
 	if keyer, ok := provider.(CredentialKeyer); ok {
 		return keyer.CredentialKey(data)
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/404tk/cloudtoolkit/utils"
+)
+
+func TestCredInsertDropsMFAToken(t *testing.T) {
+	cfg := &InitCfg{}
+	cfg.once.Do(func() {})
+	options := map[string]string{
+		utils.Provider:    "aws",
+		utils.AccessKey:   "AKID",
+		utils.AWSMFAToken: "123456",
+	}
+	cfg.CredInsert("alice", nil, options)
+
+	if len(cfg.Creds) != 1 {
+		t.Fatalf("expected 1 credential, got %d", len(cfg.Creds))
+	}
+	var stored map[string]string
+	if err := json.Unmarshal([]byte(cfg.Creds[0].JsonData), &stored); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := stored[utils.AWSMFAToken]; ok {
+		t.Errorf("mfa token persisted: %v", stored)
+	}
+	if options[utils.AWSMFAToken] != "123456" {
+		t.Error("caller's options were modified")
+	}
+}
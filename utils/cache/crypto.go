@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseEnv unlocks an encrypted credential cache without a prompt, for
+// headless runs in CI. It is read once, when the cache is first loaded.
+const PassphraseEnv = "CTK_CACHE_PASSPHRASE"
+
+const (
+	envelopeVersion = 1
+	envelopeKDF     = "scrypt"
+	envelopeCipher  = "aes-256-gcm"
+
+	// scrypt cost parameters recommended for interactive logins (2^15, 8, 1).
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+
+	saltSize = 16
+	keySize  = 32
+)
+
+var (
+	ErrLocked         = errors.New("credential cache is locked")
+	ErrNotEncrypted   = errors.New("credential cache is not encrypted")
+	ErrBadPassphrase  = errors.New("wrong passphrase or corrupted credential cache")
+	ErrEmptyPassword  = errors.New("empty passphrase")
+	errUnknownVersion = errors.New("unsupported credential cache format")
+	errKDFParams      = errors.New("unexpected credential cache KDF parameters")
+)
+
+// envelope is the on-disk form of an encrypted cache. The plaintext is the
+// same JSON array a legacy config.json holds, so decrypting a file yields
+// exactly what an unencrypted store would have contained.
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	N          int    `json:"n"`
+	R          int    `json:"r"`
+	P          int    `json:"p"`
+	Cipher     string `json:"cipher"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// storeKey is a derived key together with the KDF inputs needed to rewrite
+// the envelope without asking for the passphrase again.
+type storeKey struct {
+	salt    []byte
+	n, r, p int
+	key     []byte
+}
+
+func newStoreKey(passphrase string) (*storeKey, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return deriveKey(passphrase, salt, scryptN, scryptR, scryptP)
+}
+
+func deriveKey(passphrase string, salt []byte, n, r, p int) (*storeKey, error) {
+	if passphrase == "" {
+		return nil, ErrEmptyPassword
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, n, r, p, keySize)
+	if err != nil {
+		return nil, err
+	}
+	return &storeKey{salt: salt, n: n, r: r, p: p, key: key}, nil
+}
+
+// isEnvelope tells an encrypted file (a JSON object) apart from a legacy
+// plaintext one (a JSON array).
+func isEnvelope(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+func parseEnvelope(data []byte) (envelope, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return env, err
+	}
+	if env.Version != envelopeVersion || env.KDF != envelopeKDF || env.Cipher != envelopeCipher {
+		return env, fmt.Errorf("%w: version=%d kdf=%s cipher=%s", errUnknownVersion, env.Version, env.KDF, env.Cipher)
+	}
+	// The parameters are read before anything is authenticated, so only the
+	// ones this build writes are accepted; a tampered file with a huge N
+	// would otherwise stall or exhaust memory in scrypt on every start-up.
+	if env.N != scryptN || env.R != scryptR || env.P != scryptP || len(env.Salt) != saltSize {
+		return env, fmt.Errorf("%w: n=%d r=%d p=%d salt=%d bytes", errKDFParams, env.N, env.R, env.P, len(env.Salt))
+	}
+	return env, nil
+}
+
+func seal(k *storeKey, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(k.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	env := envelope{
+		Version: envelopeVersion,
+		KDF:     envelopeKDF,
+		N:       k.n,
+		R:       k.r,
+		P:       k.p,
+		Cipher:  envelopeCipher,
+		Salt:    k.salt,
+		Nonce:   nonce,
+	}
+	env.Ciphertext = gcm.Seal(nil, nonce, plaintext, env.additionalData())
+	return json.MarshalIndent(env, "", "\t")
+}
+
+// open derives the key from passphrase and the envelope's KDF parameters and
+// returns it together with the decrypted payload.
+func open(data []byte, passphrase string) (*storeKey, []byte, error) {
+	env, err := parseEnvelope(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := deriveKey(passphrase, env.Salt, env.N, env.R, env.P)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := newGCM(k.key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, nil, ErrBadPassphrase
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, env.additionalData())
+	if err != nil {
+		return nil, nil, ErrBadPassphrase
+	}
+	return k, plaintext, nil
+}
+
+// additionalData binds the KDF parameters to the ciphertext so they cannot
+// be downgraded in the file without failing authentication.
+func (env envelope) additionalData() []byte {
+	return []byte(fmt.Sprintf("ctk-cache/v%d/%s/%d/%d/%d/%s", env.Version, env.KDF, env.N, env.R, env.P, env.Cipher))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
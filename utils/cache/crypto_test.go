@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	key, err := newStoreKey("correct horse")
+	if err != nil {
+		t.Fatalf("newStoreKey: %v", err)
+	}
+	data, err := seal(key, []byte(`[{"UUID":"u-1"}]`))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !isEnvelope(data) {
+		t.Fatalf("sealed data is not an envelope: %s", data)
+	}
+	_, plaintext, err := open(data, "correct horse")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if string(plaintext) != `[{"UUID":"u-1"}]` {
+		t.Errorf("plaintext = %s", plaintext)
+	}
+}
+
+func TestOpenWrongPassphrase(t *testing.T) {
+	key, err := newStoreKey("correct horse")
+	if err != nil {
+		t.Fatalf("newStoreKey: %v", err)
+	}
+	data, err := seal(key, []byte(`[]`))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if _, _, err := open(data, "battery staple"); !errors.Is(err, ErrBadPassphrase) {
+		t.Fatalf("open with wrong passphrase: err = %v, want %v", err, ErrBadPassphrase)
+	}
+}
+
+func TestOpenRejectsTamperedEnvelope(t *testing.T) {
+	key, err := newStoreKey("correct horse")
+	if err != nil {
+		t.Fatalf("newStoreKey: %v", err)
+	}
+	data, err := seal(key, []byte(`[]`))
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	// The KDF parameters are authenticated as additional data.
+	gcm, err := newGCM(key.key)
+	if err != nil {
+		t.Fatalf("newGCM: %v", err)
+	}
+	downgraded := env
+	downgraded.N = 1 << 10
+	if _, err := gcm.Open(nil, env.Nonce, env.Ciphertext, downgraded.additionalData()); err == nil {
+		t.Error("ciphertext opened under downgraded additional data")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*envelope)
+		want   error
+	}{
+		{name: "huge n", mutate: func(e *envelope) { e.N = 1 << 40 }, want: errKDFParams},
+		{name: "low n", mutate: func(e *envelope) { e.N = 1 << 10 }, want: errKDFParams},
+		{name: "r", mutate: func(e *envelope) { e.R = 1024 }, want: errKDFParams},
+		{name: "p", mutate: func(e *envelope) { e.P = 64 }, want: errKDFParams},
+		{name: "salt", mutate: func(e *envelope) { e.Salt = e.Salt[:4] }, want: errKDFParams},
+		{name: "version", mutate: func(e *envelope) { e.Version = 2 }, want: errUnknownVersion},
+		{name: "ciphertext", mutate: func(e *envelope) { e.Ciphertext[0] ^= 0xff }, want: ErrBadPassphrase},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := env
+			tampered.Salt = append([]byte(nil), env.Salt...)
+			tampered.Ciphertext = append([]byte(nil), env.Ciphertext...)
+			tt.mutate(&tampered)
+			raw, err := json.Marshal(tampered)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			if _, _, err := open(raw, "correct horse"); !errors.Is(err, tt.want) {
+				t.Fatalf("open: err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigratesPlaintextCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`[{"UUID":"u-1","Provider":"aws","AccessKey":"AKID"}]`), 0600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	cfg := &InitCfg{Path: path}
+	cfg.load("correct horse")
+	if cfg.sealed || cfg.key == nil || len(cfg.Creds) != 1 {
+		t.Fatalf("unexpected state after migration: sealed=%v key=%v creds=%+v", cfg.sealed, cfg.key != nil, cfg.Creds)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !isEnvelope(data) {
+		t.Fatalf("cache was not rewritten as an envelope: %s", data)
+	}
+
+	reloaded := &InitCfg{Path: path}
+	reloaded.load("")
+	if !reloaded.sealed || len(reloaded.Creds) != 0 {
+		t.Fatalf("encrypted cache loaded without a passphrase: %+v", reloaded.Creds)
+	}
+	if err := reloaded.unlockData(data, "correct horse"); err != nil {
+		t.Fatalf("unlock: %v", err)
+	}
+	if len(reloaded.Creds) != 1 || reloaded.Creds[0].AccessKey != "AKID" {
+		t.Errorf("unexpected creds after unlock: %+v", reloaded.Creds)
+	}
+}
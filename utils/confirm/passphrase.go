@@ -0,0 +1,31 @@
+package confirm
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Passphrase prompts on stderr and reads one line from stdin without echo
+// when stdin is a terminal. Piped input is read as a plain line so scripted
+// REPL sessions still work.
+func Passphrase(label string) (string, error) {
+	fmt.Fprintf(os.Stderr, "%s: ", label)
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -22,8 +22,19 @@ const (
 	GCPserviceAccountJSON = "base64Json"
 )
 
+const (
+	AWSRoleArn     = "roleArn"
+	AWSExternalId  = "externalId"
+	AWSSessionName = "sessionName"
+	AWSMFASerial   = "mfaSerial"
+	AWSMFAToken    = "mfaToken"
+	AWSOrgRole     = "orgRole"
+)
+
 const (
 	Metadata    = "metadata"
+	Format      = "format"
+	Detect      = "detect"
 	BucketCheck = "list all"
 	EventCheck  = "dump all"
 )